@@ -163,6 +163,16 @@ type Routes struct {
 
 	AccessControlPolicies *mux.Router // 'api/v4/access_control_policies'
 	AccessControlPolicy   *mux.Router // 'api/v4/access_control_policies/{policy_id:[A-Za-z0-9]+}'
+
+	Whitelist         *mux.Router // 'api/v4/whitelist'
+	WhitelistPolicies *mux.Router // 'api/v4/whitelist/policies'
+	WhitelistPolicy   *mux.Router // 'api/v4/whitelist/policies/{policy_id:[A-Za-z0-9]+}'
+	WhitelistDenials  *mux.Router // 'api/v4/whitelist/denials'
+
+	SavedSearches *mux.Router // 'api/v4/saved_searches'
+	SavedSearch   *mux.Router // 'api/v4/saved_searches/{saved_search_id:[A-Za-z0-9]+}'
+
+	LoginBans *mux.Router // 'api/v4/login_bans'
 }
 
 type API struct {
@@ -312,6 +322,16 @@ func Init(srv *app.Server) (*API, error) {
 	api.BaseRoutes.AccessControlPolicies = api.BaseRoutes.APIRoot.PathPrefix("/access_control_policies").Subrouter()
 	api.BaseRoutes.AccessControlPolicy = api.BaseRoutes.APIRoot.PathPrefix("/access_control_policies/{policy_id:[A-Za-z0-9]+}").Subrouter()
 
+	api.BaseRoutes.Whitelist = api.BaseRoutes.APIRoot.PathPrefix("/whitelist").Subrouter()
+	api.BaseRoutes.WhitelistPolicies = api.BaseRoutes.Whitelist.PathPrefix("/policies").Subrouter()
+	api.BaseRoutes.WhitelistPolicy = api.BaseRoutes.WhitelistPolicies.PathPrefix("/{policy_id:[A-Za-z0-9]+}").Subrouter()
+	api.BaseRoutes.WhitelistDenials = api.BaseRoutes.Whitelist.PathPrefix("/denials").Subrouter()
+
+	api.BaseRoutes.SavedSearches = api.BaseRoutes.APIRoot.PathPrefix("/saved_searches").Subrouter()
+	api.BaseRoutes.SavedSearch = api.BaseRoutes.SavedSearches.PathPrefix("/{saved_search_id:[A-Za-z0-9]+}").Subrouter()
+
+	api.BaseRoutes.LoginBans = api.BaseRoutes.APIRoot.PathPrefix("/login_bans").Subrouter()
+
 	api.InitUser()
 	api.InitBot()
 	api.InitTeam()
@@ -365,6 +385,10 @@ func Init(srv *app.Server) (*API, error) {
 	api.InitCustomProfileAttributes()
 	api.InitAuditLogging()
 	api.InitAccessControlPolicy()
+	api.InitWhitelistPolicy()
+	api.InitWhitelist()
+	api.InitSavedSearch()
+	api.InitLoginBan()
 
 	// If we allow testing then listen for manual testing URL hits
 	if *srv.Config().ServiceSettings.EnableTesting {
@@ -4,14 +4,21 @@
 package api4
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/audit"
 )
 
 func (api *API) InitBleve() {
 	api.BaseRoutes.Bleve.Handle("/purge_indexes", api.APISessionRequired(purgeBleveIndexes)).Methods(http.MethodPost)
+	api.BaseRoutes.Bleve.Handle("/snapshots", api.APISessionRequired(createBleveSnapshot)).Methods(http.MethodPost)
+	api.BaseRoutes.Bleve.Handle("/snapshots", api.APISessionRequired(getBleveSnapshots)).Methods(http.MethodGet)
+	api.BaseRoutes.Bleve.Handle("/snapshots/{snapshot_name:[A-Za-z0-9_-]+}/restore", api.APISessionRequired(restoreBleveSnapshot)).Methods(http.MethodPost)
 }
 
 func purgeBleveIndexes(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -23,7 +30,8 @@ func purgeBleveIndexes(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := c.App.PurgeBleveIndexes(c.AppContext); err != nil {
+	specifiedIndexesQuery := r.URL.Query()["index"]
+	if err := c.App.PurgeBleveIndexes(c.AppContext, specifiedIndexesQuery); err != nil {
 		c.Err = err
 		return
 	}
@@ -32,3 +40,69 @@ func purgeBleveIndexes(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	ReturnStatusOK(w)
 }
+
+func createBleveSnapshot(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("createBleveSnapshot", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionToAndNotRestrictedAdmin(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	job, appErr := c.App.CreateBleveSnapshotJob(c.AppContext)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("job_id", job.Id)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getBleveSnapshots(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionToAndNotRestrictedAdmin(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	snapshots, appErr := c.App.GetBleveSnapshots(c.AppContext)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(snapshots); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func restoreBleveSnapshot(c *Context, w http.ResponseWriter, r *http.Request) {
+	snapshotName := mux.Vars(r)["snapshot_name"]
+
+	auditRec := c.MakeAuditRecord("restoreBleveSnapshot", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "snapshot_name", snapshotName)
+
+	if !c.App.SessionHasPermissionToAndNotRestrictedAdmin(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	snapshot, appErr := c.App.RestoreBleveSnapshot(c.AppContext, snapshotName)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
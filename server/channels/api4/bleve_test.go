@@ -45,3 +45,58 @@ func TestBlevePurgeIndexes(t *testing.T) {
 		CheckForbiddenStatus(t, resp)
 	})
 }
+
+func TestBleveSnapshots(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("as system user", func(t *testing.T) {
+		_, resp, err := th.Client.CreateBleveSnapshot(context.Background())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.GetBleveSnapshots(context.Background())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.RestoreBleveSnapshot(context.Background(), "snapshot")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("unknown snapshot", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.RestoreBleveSnapshot(context.Background(), "unknown")
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("snapshot and restore the indexes", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.BleveSettings.IndexDir = t.TempDir()
+			*cfg.BleveSettings.EnableIndexing = true
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.BleveSettings.EnableIndexing = false
+		})
+
+		job, resp, err := th.SystemAdminClient.CreateBleveSnapshot(context.Background())
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		require.Equal(t, model.JobTypeBleveSnapshot, job.Type)
+
+		// The job takes the snapshot the same way
+		snapshot, appErr := th.App.CreateBleveSnapshot(th.Context)
+		require.Nil(t, appErr)
+		require.NotEmpty(t, snapshot.Name)
+
+		snapshots, _, err := th.SystemAdminClient.GetBleveSnapshots(context.Background())
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, snapshot.Name, snapshots[0].Name)
+
+		restored, resp, err := th.SystemAdminClient.RestoreBleveSnapshot(context.Background(), snapshot.Name)
+		require.NoError(t, err)
+		CheckOKStatus(t, resp)
+		require.Equal(t, snapshot.DocCounts, restored.DocCounts)
+	})
+}
@@ -222,7 +222,7 @@ func (api *API) APILocal(h handlerFunc, opts ...APIHandlerOption) http.Handler {
 func (api *API) RateLimitedHandler(apiHandler http.Handler, settings model.RateLimitSettings) http.Handler {
 	settings.SetDefaults()
 
-	rateLimiter, err := app.NewRateLimiter(&settings, []string{})
+	rateLimiter, err := app.NewRateLimiter(&settings, []string{}, nil)
 	if err != nil {
 		api.srv.Log().Error("getRateLimitedHandler", mlog.Err(err))
 		return nil
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitLoginBan() {
+	api.BaseRoutes.LoginBans.Handle("", api.APISessionRequired(getLoginBans)).Methods(http.MethodGet)
+	api.BaseRoutes.LoginBans.Handle("", api.APISessionRequired(removeLoginBan)).Methods(http.MethodDelete)
+	api.BaseRoutes.LoginBans.Handle("/all", api.APISessionRequired(clearLoginBans)).Methods(http.MethodDelete)
+}
+
+func getLoginBans(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	bans, appErr := c.App.GetLoginBans()
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(bans)
+	if err != nil {
+		c.Err = model.NewAppError("getLoginBans", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+// removeLoginBan lifts the ban of the address or CIDR range in the ip field of the body.
+func removeLoginBan(c *Context, w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		IP string `json:"ip"`
+	}
+
+	if jsonErr := json.NewDecoder(r.Body).Decode(&requestBody); jsonErr != nil {
+		c.SetInvalidParamWithErr("request body", jsonErr)
+		return
+	}
+
+	if requestBody.IP == "" {
+		c.SetInvalidParam("ip")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("removeLoginBan", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "ip", requestBody.IP)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if appErr := c.App.RemoveLoginBan(c.AppContext, requestBody.IP); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("login_ban")
+
+	ReturnStatusOK(w)
+}
+
+func clearLoginBans(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("clearLoginBans", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if appErr := c.App.ClearLoginBans(c.AppContext); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("login_ban")
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestLoginBans(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.LoginThrottleSettings.Enable = true
+		*cfg.LoginThrottleSettings.BackoffAfterAttempts = 10
+		*cfg.LoginThrottleSettings.MaxAttemptsPerAddress = 3
+		*cfg.LoginThrottleSettings.MaxAttemptsPerSubnet = 0
+		// The subnet backs off too, until the ban of its address is removed
+		*cfg.LoginThrottleSettings.SubnetBackoffAfterAttempts = 2
+	})
+
+	client := th.CreateClient()
+	for range 3 {
+		_, resp, err := client.Login(context.Background(), th.BasicUser.Email, "wrongpassword")
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, resp)
+	}
+
+	_, resp, err := client.Login(context.Background(), th.BasicUser.Email, th.BasicUser.Password)
+	require.Error(t, err)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	CheckErrorID(t, err, "app.login_throttle.banned.app_error")
+
+	t.Run("get", func(t *testing.T) {
+		bans, _, err := th.SystemAdminClient.GetLoginBans(context.Background())
+		require.NoError(t, err)
+		require.Len(t, bans, 1)
+		assert.Equal(t, "127.0.0.1/32", bans[0].IP)
+		assert.Equal(t, model.LoginBanReasonAddress, bans[0].Reason)
+		assert.Equal(t, 3, bans[0].FailedAttempts)
+
+		_, resp, err := th.Client.GetLoginBans(context.Background())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		resp, err := th.Client.RemoveLoginBan(context.Background(), "127.0.0.1")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = th.SystemAdminClient.RemoveLoginBan(context.Background(), "not an ip")
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		resp, err = th.SystemAdminClient.RemoveLoginBan(context.Background(), "198.51.100.7")
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		_, err = th.SystemAdminClient.RemoveLoginBan(context.Background(), "127.0.0.1")
+		require.NoError(t, err)
+
+		_, _, err = client.Login(context.Background(), th.BasicUser.Email, th.BasicUser.Password)
+		require.NoError(t, err)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		resp, err := th.Client.ClearLoginBans(context.Background())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, err = th.App.Srv().Store().LoginBan().Save(&model.LoginBan{
+			IP:       "198.51.100.0/24",
+			Reason:   model.LoginBanReasonSubnet,
+			ExpireAt: model.GetMillis() + 60000,
+		})
+		require.NoError(t, err)
+
+		_, err = th.SystemAdminClient.ClearLoginBans(context.Background())
+		require.NoError(t, err)
+
+		bans, _, err := th.SystemAdminClient.GetLoginBans(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, bans)
+	})
+}
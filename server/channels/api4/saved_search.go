@@ -0,0 +1,261 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+// savedSearchPerPageMaximum caps the results of a saved search returned at
+// once, as a search can't be paginated by the database.
+const savedSearchPerPageMaximum = 200
+
+func (api *API) InitSavedSearch() {
+	api.BaseRoutes.SavedSearches.Handle("", api.APISessionRequired(createSavedSearch)).Methods(http.MethodPost)
+	api.BaseRoutes.User.Handle("/saved_searches", api.APISessionRequired(getSavedSearchesForUser)).Methods(http.MethodGet)
+
+	api.BaseRoutes.SavedSearch.Handle("", api.APISessionRequired(getSavedSearch)).Methods(http.MethodGet)
+	api.BaseRoutes.SavedSearch.Handle("/patch", api.APISessionRequired(patchSavedSearch)).Methods(http.MethodPut)
+	api.BaseRoutes.SavedSearch.Handle("", api.APISessionRequired(deleteSavedSearch)).Methods(http.MethodDelete)
+	api.BaseRoutes.SavedSearch.Handle("/search", api.APISessionRequired(searchPostsWithSavedSearch)).Methods(http.MethodPost)
+}
+
+// requireSavedSearchOwner gets the saved search of the request, which only its
+// owner may use or change.
+func requireSavedSearchOwner(c *Context) *model.SavedSearch {
+	c.RequireSavedSearchId()
+	if c.Err != nil {
+		return nil
+	}
+
+	savedSearch, appErr := c.App.GetSavedSearch(c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return nil
+	}
+
+	if savedSearch.UserId != c.AppContext.Session().UserId {
+		c.Err = model.NewAppError("requireSavedSearchOwner", "app.saved_search.not_found.app_error", nil, "", http.StatusNotFound)
+		return nil
+	}
+
+	return savedSearch
+}
+
+func createSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	var savedSearch model.SavedSearch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&savedSearch); jsonErr != nil {
+		c.SetInvalidParamWithErr("saved_search", jsonErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "saved_search", &savedSearch)
+
+	savedSearch.UserId = c.AppContext.Session().UserId
+
+	if savedSearch.TeamId != "" && !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), savedSearch.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	created, appErr := c.App.CreateSavedSearch(c.AppContext, &savedSearch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("saved_search")
+	auditRec.AddEventResultState(created)
+
+	js, err := json.Marshal(created)
+	if err != nil {
+		c.Err = model.NewAppError("createSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getSavedSearchesForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	savedSearches, appErr := c.App.GetSavedSearchesForUser(c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(savedSearches)
+	if err != nil {
+		c.Err = model.NewAppError("getSavedSearchesForUser", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	savedSearch := requireSavedSearchOwner(c)
+	if c.Err != nil {
+		return
+	}
+
+	js, err := json.Marshal(savedSearch)
+	if err != nil {
+		c.Err = model.NewAppError("getSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	var patch model.SavedSearchPatch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&patch); jsonErr != nil {
+		c.SetInvalidParamWithErr("saved_search", jsonErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "saved_search_id", c.Params.SavedSearchId)
+
+	savedSearch := requireSavedSearchOwner(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddEventPriorState(savedSearch)
+
+	patched, appErr := c.App.PatchSavedSearch(c.AppContext, savedSearch.Id, &patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("saved_search")
+	auditRec.AddEventResultState(patched)
+
+	js, err := json.Marshal(patched)
+	if err != nil {
+		c.Err = model.NewAppError("patchSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("deleteSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "saved_search_id", c.Params.SavedSearchId)
+
+	savedSearch := requireSavedSearchOwner(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddEventPriorState(savedSearch)
+
+	if appErr := c.App.DeleteSavedSearch(c.AppContext, savedSearch.Id); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func searchPostsWithSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	var params model.SearchParameter
+	if r.ContentLength != 0 {
+		if jsonErr := json.NewDecoder(r.Body).Decode(&params); jsonErr != nil {
+			c.Err = model.NewAppError("searchPostsWithSavedSearch", "api.post.search_posts.invalid_body.app_error", nil, "", http.StatusBadRequest).Wrap(jsonErr)
+			return
+		}
+	}
+
+	savedSearch := requireSavedSearchOwner(c)
+	if c.Err != nil {
+		return
+	}
+
+	if savedSearch.TeamId != "" && !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), savedSearch.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	timeZoneOffset := 0
+	if params.TimeZoneOffset != nil {
+		timeZoneOffset = *params.TimeZoneOffset
+	}
+
+	page := 0
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if page < 0 {
+		c.SetInvalidParam("page")
+		return
+	}
+
+	perPage := 60
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+	if perPage <= 0 || perPage > savedSearchPerPageMaximum {
+		c.SetInvalidParam("per_page")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("searchPostsWithSavedSearch", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelAPI)
+	audit.AddEventParameterAuditable(auditRec, "saved_search", savedSearch)
+
+	results, appErr := c.App.SearchPostsWithSavedSearch(c.AppContext, savedSearch, timeZoneOffset, page, perPage)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	clientPostList := c.App.PreparePostListForClient(c.AppContext, results.PostList)
+	clientPostList, appErr = c.App.SanitizePostListMetadataForUser(c.AppContext, clientPostList, c.AppContext.Session().UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	results = model.MakePostSearchResults(clientPostList, results.Matches)
+	auditRec.Success()
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if err := results.EncodeJSON(w); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSavedSearches(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	client := th.Client
+
+	savedSearch, resp, err := client.CreateSavedSearch(context.Background(), &model.SavedSearch{
+		TeamId: th.BasicTeam.Id,
+		Name:   "basic",
+		Terms:  th.BasicPost.Message,
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, savedSearch.UserId)
+
+	t.Run("get", func(t *testing.T) {
+		savedSearches, _, err := client.GetSavedSearchesForUser(context.Background(), th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, savedSearch.Id, savedSearches[0].Id)
+
+		got, _, err := client.GetSavedSearch(context.Background(), savedSearch.Id)
+		require.NoError(t, err)
+		assert.Equal(t, savedSearch.Name, got.Name)
+
+		_, resp, err := client.GetSavedSearchesForUser(context.Background(), th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		results, _, err := client.SearchPostsWithSavedSearch(context.Background(), savedSearch.Id, &model.SearchParameter{})
+		require.NoError(t, err)
+		assert.Contains(t, results.Order, th.BasicPost.Id)
+	})
+
+	t.Run("search with too many results per page", func(t *testing.T) {
+		_, resp, err := client.SearchPostsWithSavedSearch(context.Background(), savedSearch.Id, &model.SearchParameter{PerPage: model.NewPointer(savedSearchPerPageMaximum + 1)})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patched, _, err := client.PatchSavedSearch(context.Background(), savedSearch.Id, &model.SavedSearchPatch{Alert: model.NewPointer(true)})
+		require.NoError(t, err)
+		assert.True(t, patched.Alert)
+		assert.Equal(t, savedSearch.Terms, patched.Terms)
+
+		_, resp, err := client.PatchSavedSearch(context.Background(), savedSearch.Id, &model.SavedSearchPatch{Terms: model.NewPointer("")})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("other users can't use the saved search", func(t *testing.T) {
+		client2 := th.CreateClient()
+		_, _, err := client2.Login(context.Background(), th.BasicUser2.Email, th.BasicUser2.Password)
+		require.NoError(t, err)
+
+		_, resp, err := client2.GetSavedSearch(context.Background(), savedSearch.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		_, resp, err = client2.SearchPostsWithSavedSearch(context.Background(), savedSearch.Id, &model.SearchParameter{})
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		resp, err = client2.DeleteSavedSearch(context.Background(), savedSearch.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteSavedSearch(context.Background(), savedSearch.Id)
+		require.NoError(t, err)
+
+		_, resp, err := client.GetSavedSearch(context.Background(), savedSearch.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
 )
 
 func (api *API) InitScheduledPost() {
@@ -21,6 +22,13 @@ func (api *API) InitScheduledPost() {
 	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(updateScheduledPost)).Methods(http.MethodPut)
 	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteScheduledPost)).Methods(http.MethodDelete)
 	api.BaseRoutes.Posts.Handle("/scheduled/team/{team_id:[A-Za-z0-9]+}", api.APISessionRequired(getTeamScheduledPosts)).Methods(http.MethodGet)
+	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}/pause", api.APISessionRequired(pauseScheduledPost)).Methods(http.MethodPost)
+	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}/resume", api.APISessionRequired(resumeScheduledPost)).Methods(http.MethodPost)
+	api.BaseRoutes.Posts.Handle("/schedule/{scheduled_post_id:[A-Za-z0-9]+}/skip", api.APISessionRequired(skipScheduledPostOccurrence)).Methods(http.MethodPost)
+
+	api.BaseRoutes.Channel.Handle("/scheduled_posts", api.APISessionRequired(getChannelScheduledPosts)).Methods(http.MethodGet)
+	api.BaseRoutes.Channel.Handle("/scheduled_posts/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(updateChannelScheduledPost)).Methods(http.MethodPut)
+	api.BaseRoutes.Channel.Handle("/scheduled_posts/{scheduled_post_id:[A-Za-z0-9]+}", api.APISessionRequired(deleteChannelScheduledPost)).Methods(http.MethodDelete)
 }
 
 func scheduledPostChecks(where string, c *Context, scheduledPost *model.ScheduledPost) {
@@ -43,6 +51,14 @@ func scheduledPostChecks(where string, c *Context, scheduledPost *model.Schedule
 	postPriorityCheckWithContext(where, c, scheduledPost.GetPriority(), scheduledPost.RootId)
 }
 
+// requireManageChannelScheduledPosts checks that the session user can manage
+// the scheduled posts of every user in the channel.
+func requireManageChannelScheduledPosts(c *Context, channelId string) {
+	if !c.App.SessionHasPermissionToChannel(c.AppContext, *c.AppContext.Session(), channelId, model.PermissionManageChannelScheduledPosts) {
+		c.SetPermissionError(model.PermissionManageChannelScheduledPosts)
+	}
+}
+
 func requireScheduledPostsEnabled(c *Context) {
 	if !*c.App.Srv().Config().ServiceSettings.ScheduledPosts {
 		c.Err = model.NewAppError("", "api.scheduled_posts.feature_disabled", nil, "", http.StatusBadRequest)
@@ -80,6 +96,13 @@ func createSchedulePost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if scheduledPost.PublishAsBotId != "" {
+		requireManageChannelScheduledPosts(c, scheduledPost.ChannelId)
+		if c.Err != nil {
+			return
+		}
+	}
+
 	createdScheduledPost, appErr := c.App.SaveScheduledPost(c.AppContext, &scheduledPost, connectionID)
 	if appErr != nil {
 		c.Err = appErr
@@ -227,3 +250,190 @@ func deleteScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func pauseScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	recurringScheduledPostAction(c, w, r, "pauseScheduledPost", c.App.PauseScheduledPost)
+}
+
+func resumeScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	recurringScheduledPostAction(c, w, r, "resumeScheduledPost", c.App.ResumeScheduledPost)
+}
+
+func skipScheduledPostOccurrence(c *Context, w http.ResponseWriter, r *http.Request) {
+	recurringScheduledPostAction(c, w, r, "skipScheduledPostOccurrence", c.App.SkipScheduledPostOccurrence)
+}
+
+// recurringScheduledPostAction runs an action on a recurring scheduled post of
+// the session user and returns the scheduled post with its next occurrence.
+func recurringScheduledPostAction(
+	c *Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	event string,
+	action func(rctx request.CTX, userId, scheduledPostId, connectionId string) (*model.ScheduledPost, *model.AppError),
+) {
+	requireScheduledPostsEnabled(c)
+	if c.Err != nil {
+		return
+	}
+
+	scheduledPostId := mux.Vars(r)["scheduled_post_id"]
+	if scheduledPostId == "" {
+		c.SetInvalidURLParam("scheduled_post_id")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord(event, audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "scheduledPostId", scheduledPostId)
+
+	userId := c.AppContext.Session().UserId
+	connectionID := r.Header.Get(model.ConnectionId)
+	scheduledPost, appErr := action(c.AppContext, userId, scheduledPostId, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(scheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	if err := json.NewEncoder(w).Encode(scheduledPost); err != nil {
+		mlog.Error("failed to encode scheduled post to return API response", mlog.Err(err))
+		return
+	}
+}
+
+func getChannelScheduledPosts(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireScheduledPostsEnabled(c)
+	if c.Err != nil {
+		return
+	}
+
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	requireManageChannelScheduledPosts(c, c.Params.ChannelId)
+	if c.Err != nil {
+		return
+	}
+
+	scheduledPosts, appErr := c.App.GetChannelScheduledPosts(c.AppContext, c.Params.ChannelId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(scheduledPosts); err != nil {
+		mlog.Error("failed to encode scheduled posts to return API response", mlog.Err(err))
+		return
+	}
+}
+
+func updateChannelScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireScheduledPostsEnabled(c)
+	if c.Err != nil {
+		return
+	}
+
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	scheduledPostId := mux.Vars(r)["scheduled_post_id"]
+	if scheduledPostId == "" {
+		c.SetInvalidURLParam("scheduled_post_id")
+		return
+	}
+
+	var scheduledPost model.ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&scheduledPost); err != nil {
+		c.SetInvalidParamWithErr("schedule_post", err)
+		return
+	}
+
+	if scheduledPost.Id != scheduledPostId {
+		c.SetInvalidURLParam("scheduled_post_id")
+		return
+	}
+	scheduledPost.ChannelId = c.Params.ChannelId
+
+	auditRec := c.MakeAuditRecord("updateChannelScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameter(auditRec, "channelId", c.Params.ChannelId)
+	audit.AddEventParameterAuditable(auditRec, "scheduledPost", &scheduledPost)
+
+	requireManageChannelScheduledPosts(c, c.Params.ChannelId)
+	if c.Err != nil {
+		return
+	}
+
+	scheduledPostChecks("Api4.updateChannelScheduledPost", c, &scheduledPost)
+	if c.Err != nil {
+		return
+	}
+
+	connectionID := r.Header.Get(model.ConnectionId)
+	updatedScheduledPost, appErr := c.App.UpdateChannelScheduledPost(c.AppContext, c.AppContext.Session().UserId, c.Params.ChannelId, &scheduledPost, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(updatedScheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	if err := json.NewEncoder(w).Encode(updatedScheduledPost); err != nil {
+		mlog.Error("failed to encode scheduled post to return API response", mlog.Err(err))
+		return
+	}
+}
+
+func deleteChannelScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	requireScheduledPostsEnabled(c)
+	if c.Err != nil {
+		return
+	}
+
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	scheduledPostId := mux.Vars(r)["scheduled_post_id"]
+	if scheduledPostId == "" {
+		c.SetInvalidURLParam("scheduled_post_id")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteChannelScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	audit.AddEventParameter(auditRec, "channelId", c.Params.ChannelId)
+	audit.AddEventParameter(auditRec, "scheduledPostId", scheduledPostId)
+
+	requireManageChannelScheduledPosts(c, c.Params.ChannelId)
+	if c.Err != nil {
+		return
+	}
+
+	connectionID := r.Header.Get(model.ConnectionId)
+	deletedScheduledPost, appErr := c.App.DeleteChannelScheduledPost(c.AppContext, c.Params.ChannelId, scheduledPostId, connectionID)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(deletedScheduledPost)
+	auditRec.AddEventObjectType("scheduledPost")
+
+	if err := json.NewEncoder(w).Encode(deletedScheduledPost); err != nil {
+		mlog.Error("failed to encode scheduled post to return API response", mlog.Err(err))
+		return
+	}
+}
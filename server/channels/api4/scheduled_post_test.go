@@ -6,6 +6,7 @@ package api4
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/stretchr/testify/require"
@@ -72,3 +73,157 @@ func TestCreateScheduledPost(t *testing.T) {
 		require.Nil(t, createdScheduledPost)
 	})
 }
+
+func TestRecurringScheduledPost(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.Srv().SetLicense(model.NewTestLicenseSKU(model.LicenseShortSkuProfessional))
+
+	client := th.Client
+
+	scheduledAt := model.GetMillis() + 100000 // 100 seconds in the future
+	scheduledPost, _, err := client.CreateScheduledPost(context.Background(), &model.ScheduledPost{
+		Draft: model.Draft{
+			CreateAt:  model.GetMillis(),
+			ChannelId: th.BasicChannel.Id,
+			Message:   "this is a recurring scheduled post",
+		},
+		ScheduledAt:    scheduledAt,
+		RecurrenceRule: "FREQ=WEEKLY",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=WEEKLY", scheduledPost.RecurrenceRule)
+
+	t.Run("pause and resume", func(t *testing.T) {
+		pausedPost, _, err := client.PauseScheduledPost(context.Background(), scheduledPost.Id)
+		require.NoError(t, err)
+		require.NotZero(t, pausedPost.PausedAt)
+
+		resumedPost, _, err := client.ResumeScheduledPost(context.Background(), scheduledPost.Id)
+		require.NoError(t, err)
+		require.Zero(t, resumedPost.PausedAt)
+	})
+
+	t.Run("skip the next occurrence", func(t *testing.T) {
+		skippedPost, _, err := client.SkipScheduledPostOccurrence(context.Background(), scheduledPost.Id)
+		require.NoError(t, err)
+		require.Equal(t, scheduledAt+(7*24*time.Hour).Milliseconds(), skippedPost.ScheduledAt)
+	})
+
+	t.Run("someone else's scheduled post", func(t *testing.T) {
+		_, resp, err := th.SystemAdminClient.PauseScheduledPost(context.Background(), scheduledPost.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
+func TestChannelScheduledPosts(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.Srv().SetLicense(model.NewTestLicenseSKU(model.LicenseShortSkuProfessional))
+
+	th.MakeUserChannelAdmin(th.BasicUser, th.BasicChannel)
+	th.MakeUserChannelAdmin(th.BasicUser, th.BasicChannel2)
+	th.App.Srv().Store().Channel().ClearCaches()
+
+	client2 := th.CreateClient()
+	th.LoginBasic2WithClient(client2)
+
+	scheduledPost, _, err := client2.CreateScheduledPost(context.Background(), &model.ScheduledPost{
+		Draft: model.Draft{
+			CreateAt:  model.GetMillis(),
+			ChannelId: th.BasicChannel.Id,
+			Message:   "this is an announcement",
+		},
+		ScheduledAt: model.GetMillis() + 100000, // 100 seconds in the future
+	})
+	require.NoError(t, err)
+
+	bot := th.CreateBotWithSystemAdminClient()
+	th.App.AddUserToChannel(th.Context, &model.User{Id: bot.UserId}, th.BasicChannel, false)
+
+	t.Run("get", func(t *testing.T) {
+		scheduledPosts, _, err := th.Client.GetChannelScheduledPosts(context.Background(), th.BasicChannel.Id)
+		require.NoError(t, err)
+		require.Len(t, scheduledPosts, 1)
+		require.Equal(t, scheduledPost.Id, scheduledPosts[0].Id)
+		require.Equal(t, th.BasicUser2.Id, scheduledPosts[0].UserId)
+
+		_, resp, err := client2.GetChannelScheduledPosts(context.Background(), th.BasicChannel.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("update and publish as a bot", func(t *testing.T) {
+		scheduledPost.Message = "this is an edited announcement"
+
+		// The content published as another user can't be edited
+		_, resp, err := th.Client.UpdateChannelScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		scheduledPost.PublishAsBotId = bot.UserId
+
+		_, resp, err = client2.UpdateChannelScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		// Publishing as the bot of another user takes the permission to manage it
+		_, resp, err = th.Client.UpdateChannelScheduledPost(context.Background(), scheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		th.AddPermissionToRole(model.PermissionManageOthersBots.Id, model.SystemUserRoleId)
+		defer th.RemovePermissionFromRole(model.PermissionManageOthersBots.Id, model.SystemUserRoleId)
+
+		updatedPost, _, err := th.Client.UpdateChannelScheduledPost(context.Background(), scheduledPost)
+		require.NoError(t, err)
+		require.Equal(t, "this is an edited announcement", updatedPost.Message)
+		require.Equal(t, bot.UserId, updatedPost.PublishAsBotId)
+		require.Equal(t, th.BasicUser2.Id, updatedPost.UserId)
+		require.Equal(t, th.BasicUser.Id, updatedPost.LastEditorId)
+	})
+
+	t.Run("create as a bot without the permission", func(t *testing.T) {
+		botScheduledPost := &model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is an announcement",
+			},
+			ScheduledAt:    model.GetMillis() + 100000,
+			PublishAsBotId: bot.UserId,
+		}
+
+		_, resp, err := client2.CreateScheduledPost(context.Background(), botScheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		// Managing the scheduled posts of the channel doesn't allow publishing as any bot
+		_, resp, err = th.Client.CreateScheduledPost(context.Background(), botScheduledPost)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, resp, err := client2.DeleteChannelScheduledPost(context.Background(), th.BasicChannel.Id, scheduledPost.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		// A scheduled post of another channel the user manages
+		_, resp, err = th.Client.DeleteChannelScheduledPost(context.Background(), th.BasicChannel2.Id, scheduledPost.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		_, _, err = th.Client.DeleteChannelScheduledPost(context.Background(), th.BasicChannel.Id, scheduledPost.Id)
+		require.NoError(t, err)
+
+		scheduledPosts, _, err := th.Client.GetChannelScheduledPosts(context.Background(), th.BasicChannel.Id)
+		require.NoError(t, err)
+		require.Empty(t, scheduledPosts)
+	})
+}
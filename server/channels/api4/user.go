@@ -1868,6 +1868,11 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 			"app.team.join_user_to_team.max_accounts.app_error",
 			"store.sql_user.save.max_accounts.app_error",
 			"api.user.check_user_login_attempts.too_many_ldap.app_error",
+			"api.user.login.whitelist_enrollment_email_sent.app_error",
+			"api.context.ip_whitelist_denied.app_error",
+			"app.whitelist.enrollment.rate_limited.app_error",
+			"app.login_throttle.banned.app_error",
+			"app.login_throttle.backoff.app_error",
 		}
 
 		maskError := true
@@ -1969,6 +1974,11 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	c.LogAuditWithUserId(user.Id, "authenticated")
 
+	if err := c.App.CheckWhitelistEnrollmentOnLogin(c.AppContext, user, c.AppContext.IPAddress()); err != nil {
+		c.Err = err
+		return
+	}
+
 	isMobileDevice := utils.IsMobileRequest(r)
 	session, err := c.App.DoLogin(c.AppContext, w, r, user, deviceId, isMobileDevice, false, false)
 	if err != nil {
@@ -3637,15 +3647,21 @@ func getUserWhitelist(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ips, err := c.App.GetUserWhitelistIPs(c.Params.UserId)
+	entries, err := c.App.GetUserWhitelist(c.Params.UserId)
 	if err != nil {
 		c.Err = err
 		return
 	}
 
+	ips := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ips = append(ips, entry.IP)
+	}
+
 	response := map[string]interface{}{
 		"user_id": c.Params.UserId,
 		"ips":     ips,
+		"entries": entries,
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -3667,7 +3683,9 @@ func addUserToWhitelist(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	var requestBody struct {
-		IP string `json:"ip"`
+		IP          string `json:"ip"`
+		Description string `json:"description"`
+		ExpiresAt   int64  `json:"expires_at"`
 	}
 
 	if jsonErr := json.NewDecoder(r.Body).Decode(&requestBody); jsonErr != nil {
@@ -3684,18 +3702,29 @@ func addUserToWhitelist(c *Context, w http.ResponseWriter, r *http.Request) {
 	defer c.LogAuditRec(auditRec)
 	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
 	audit.AddEventParameter(auditRec, "ip", requestBody.IP)
+	audit.AddEventParameter(auditRec, "expires_at", requestBody.ExpiresAt)
 
-	if err := c.App.AddUserToWhitelist(c.AppContext, c.Params.UserId, requestBody.IP); err != nil {
+	entry, err := c.App.AddUserToWhitelist(c.AppContext, &model.WhitelistItem{
+		UserId:      c.Params.UserId,
+		IP:          requestBody.IP,
+		Description: requestBody.Description,
+		CreatorId:   c.AppContext.Session().UserId,
+		ExpiresAt:   requestBody.ExpiresAt,
+	})
+	if err != nil {
 		c.Err = err
 		return
 	}
 
 	auditRec.Success()
+	auditRec.AddEventObjectType("whitelist_item")
+	auditRec.AddEventResultState(entry)
 
 	response := map[string]interface{}{
 		"user_id": c.Params.UserId,
-		"ip":      requestBody.IP,
+		"ip":      entry.IP,
 		"status":  "added",
+		"entry":   entry,
 	}
 
 	w.WriteHeader(http.StatusCreated)
@@ -3718,7 +3747,8 @@ func removeUserFromWhitelist(c *Context, w http.ResponseWriter, r *http.Request)
 	}
 
 	var requestBody struct {
-		IP string `json:"ip"`
+		IP             string `json:"ip"`
+		RevokeSessions bool   `json:"revoke_sessions"`
 	}
 
 	if jsonErr := json.NewDecoder(r.Body).Decode(&requestBody); jsonErr != nil {
@@ -3735,13 +3765,27 @@ func removeUserFromWhitelist(c *Context, w http.ResponseWriter, r *http.Request)
 	defer c.LogAuditRec(auditRec)
 	audit.AddEventParameter(auditRec, "user_id", c.Params.UserId)
 	audit.AddEventParameter(auditRec, "ip", requestBody.IP)
+	audit.AddEventParameter(auditRec, "revoke_sessions", requestBody.RevokeSessions)
+
+	// Record the entry being removed so the audit trail shows what access was lost
+	if normalized, err := model.NormalizeWhitelistIP(requestBody.IP); err == nil {
+		if entries, appErr := c.App.GetUserWhitelist(c.Params.UserId); appErr == nil {
+			for _, entry := range entries {
+				if entry.IP == normalized {
+					auditRec.AddEventPriorState(entry)
+					break
+				}
+			}
+		}
+	}
 
-	if err := c.App.RemoveUserFromWhitelist(c.AppContext, c.Params.UserId, requestBody.IP); err != nil {
+	if err := c.App.RemoveUserFromWhitelist(c.AppContext, c.Params.UserId, requestBody.IP, requestBody.RevokeSessions); err != nil {
 		c.Err = err
 		return
 	}
 
 	auditRec.Success()
+	auditRec.AddEventObjectType("whitelist_item")
 
 	response := map[string]interface{}{
 		"user_id": c.Params.UserId,
@@ -55,6 +55,31 @@ func (api *API) InitWebSocket() {
 }
 
 func connectWebSocket(c *Context, w http.ResponseWriter, r *http.Request) {
+	// Connections are checked before the upgrade so that reconnecting from an
+	// address which is no longer whitelisted fails instead of resuming the old queues.
+	if userID := c.AppContext.Session().UserId; userID != "" {
+		decision, appErr := c.App.EvaluateUserIPWhitelist(c.AppContext, userID, c.AppContext.IPAddress())
+		if appErr != nil {
+			c.Err = model.NewAppError("connect", "api.context.whitelist.check_error.app_error", nil, "", http.StatusInternalServerError).Wrap(appErr)
+			return
+		}
+		if !decision.Allowed {
+			c.Logger.Warn("Websocket connection denied: IP not in whitelist",
+				mlog.String("user_id", userID),
+				mlog.String("ip", c.AppContext.IPAddress()),
+				mlog.String("rule", decision.Rule))
+			c.App.RecordWhitelistDenial(c.AppContext, &model.WhitelistDenial{
+				UserId:    userID,
+				IP:        c.AppContext.IPAddress(),
+				Path:      r.URL.Path,
+				Rule:      decision.Rule,
+				SessionId: c.AppContext.Session().Id,
+			})
+			c.Err = model.NewAppError("connect", "api.context.ip_whitelist_denied.app_error", nil, "", http.StatusUnauthorized)
+			return
+		}
+	}
+
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  model.SocketMaxMessageSizeKb,
 		WriteBufferSize: model.SocketMaxMessageSizeKb,
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+)
+
+func (api *API) InitWhitelist() {
+	api.BaseRoutes.Whitelist.Handle("/entries", api.APISessionRequired(getAllWhitelistItems)).Methods(http.MethodGet)
+
+	api.BaseRoutes.WhitelistDenials.Handle("", api.APISessionRequired(getWhitelistDenials)).Methods(http.MethodGet)
+	api.BaseRoutes.WhitelistDenials.Handle("/export", api.APISessionRequired(exportWhitelistDenials)).Methods(http.MethodGet)
+
+	api.BaseRoutes.Whitelist.Handle("/enrollment/confirm", api.APIHandler(confirmWhitelistEnrollment)).Methods(http.MethodGet)
+}
+
+// getAllWhitelistItems pages through the entries of every user. The after_user_id
+// and after_ip parameters are those of the last entry of the previous page.
+func getAllWhitelistItems(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	query := r.URL.Query()
+	afterUserId := query.Get("after_user_id")
+	if afterUserId != "" && !model.IsValidId(afterUserId) {
+		c.SetInvalidURLParam("after_user_id")
+		return
+	}
+
+	items, appErr := c.App.GetAllWhitelistItems(afterUserId, query.Get("after_ip"), c.Params.PerPage)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(items)
+	if err != nil {
+		c.Err = model.NewAppError("getAllWhitelistItems", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+// whitelistDenialFilterFromRequest reads the user_id, ip, since and until query
+// parameters of the denied-access history endpoints.
+func whitelistDenialFilterFromRequest(c *Context, r *http.Request) *model.WhitelistDenialFilter {
+	query := r.URL.Query()
+	filter := &model.WhitelistDenialFilter{
+		UserId:  query.Get("user_id"),
+		IP:      query.Get("ip"),
+		Page:    c.Params.Page,
+		PerPage: c.Params.PerPage,
+	}
+
+	if filter.UserId != "" && !model.IsValidId(filter.UserId) {
+		c.SetInvalidURLParam("user_id")
+		return nil
+	}
+
+	for name, value := range map[string]*int64{"since": &filter.Since, "until": &filter.Until} {
+		if query.Get(name) == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(query.Get(name), 10, 64)
+		if err != nil || parsed < 0 {
+			c.SetInvalidURLParam(name)
+			return nil
+		}
+		*value = parsed
+	}
+
+	if filter.Until != 0 && filter.Since > filter.Until {
+		c.SetInvalidURLParam("until")
+		return nil
+	}
+
+	return filter
+}
+
+func getWhitelistDenials(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	filter := whitelistDenialFilterFromRequest(c, r)
+	if c.Err != nil {
+		return
+	}
+
+	denials, appErr := c.App.GetWhitelistDenials(filter)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(denials)
+	if err != nil {
+		c.Err = model.NewAppError("getWhitelistDenials", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func exportWhitelistDenials(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("exportWhitelistDenials", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	filter := whitelistDenialFilterFromRequest(c, r)
+	if c.Err != nil {
+		return
+	}
+	audit.AddEventParameter(auditRec, "user_id", filter.UserId)
+	audit.AddEventParameter(auditRec, "ip", filter.IP)
+	audit.AddEventParameter(auditRec, "since", filter.Since)
+	audit.AddEventParameter(auditRec, "until", filter.Until)
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment;filename=\"whitelist_denials.csv\"")
+
+	// Rows are streamed, so a failure after the first page truncates the file
+	if appErr := c.App.ExportWhitelistDenials(filter, w); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+}
+
+// confirmWhitelistEnrollment is the target of the emailed enrollment link. It is
+// opened in a browser, so the outcome is a redirect to the login page or an error page.
+func confirmWhitelistEnrollment(c *Context, w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if len(token) != model.TokenSize {
+		c.Err = model.NewAppError("confirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest)
+		utils.RenderWebAppError(c.App.Config(), w, r, c.Err, c.App.AsymmetricSigningKey())
+		return
+	}
+
+	item, appErr := c.App.ConfirmWhitelistEnrollment(c.AppContext, token)
+	if appErr != nil {
+		c.Err = appErr
+		utils.RenderWebAppError(c.App.Config(), w, r, c.Err, c.App.AsymmetricSigningKey())
+		return
+	}
+
+	c.LogAuditWithUserId(item.UserId, "whitelist enrollment confirmed ip="+item.IP)
+	http.Redirect(w, r, c.GetSiteURLHeader()+"/login?extra=whitelist_enrolled", http.StatusFound)
+}
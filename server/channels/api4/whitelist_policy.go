@@ -0,0 +1,321 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitWhitelistPolicy() {
+	api.BaseRoutes.WhitelistPolicies.Handle("", api.APISessionRequired(getWhitelistPolicies)).Methods(http.MethodGet)
+	api.BaseRoutes.WhitelistPolicies.Handle("", api.APISessionRequired(createWhitelistPolicy)).Methods(http.MethodPost)
+	api.BaseRoutes.WhitelistPolicies.Handle("/name/{policy_name:[A-Za-z0-9_-]+}", api.APISessionRequired(getWhitelistPolicyByName)).Methods(http.MethodGet)
+
+	api.BaseRoutes.WhitelistPolicy.Handle("", api.APISessionRequired(getWhitelistPolicy)).Methods(http.MethodGet)
+	api.BaseRoutes.WhitelistPolicy.Handle("/patch", api.APISessionRequired(patchWhitelistPolicy)).Methods(http.MethodPut)
+	api.BaseRoutes.WhitelistPolicy.Handle("", api.APISessionRequired(deleteWhitelistPolicy)).Methods(http.MethodDelete)
+	api.BaseRoutes.WhitelistPolicy.Handle("/targets", api.APISessionRequired(getWhitelistPolicyTargets)).Methods(http.MethodGet)
+	api.BaseRoutes.WhitelistPolicy.Handle("/targets", api.APISessionRequired(addWhitelistPolicyTarget)).Methods(http.MethodPost)
+	api.BaseRoutes.WhitelistPolicy.Handle("/targets", api.APISessionRequired(removeWhitelistPolicyTarget)).Methods(http.MethodDelete)
+}
+
+func getWhitelistPolicies(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	policies, appErr := c.App.GetWhitelistPolicies(c.Params.Page, c.Params.PerPage)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(policies)
+	if err != nil {
+		c.Err = model.NewAppError("getWhitelistPolicies", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createWhitelistPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	var policy model.WhitelistPolicy
+	if jsonErr := json.NewDecoder(r.Body).Decode(&policy); jsonErr != nil {
+		c.SetInvalidParamWithErr("policy", jsonErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createWhitelistPolicy", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "policy", &policy)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	policy.CreatorId = c.AppContext.Session().UserId
+
+	created, appErr := c.App.CreateWhitelistPolicy(c.AppContext, &policy)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("whitelist_policy")
+	auditRec.AddEventResultState(created)
+
+	js, err := json.Marshal(created)
+	if err != nil {
+		c.Err = model.NewAppError("createWhitelistPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getWhitelistPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	policy, appErr := c.App.GetWhitelistPolicy(c.Params.PolicyId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(policy)
+	if err != nil {
+		c.Err = model.NewAppError("getWhitelistPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getWhitelistPolicyByName(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	c.RequirePolicyName()
+	if c.Err != nil {
+		return
+	}
+
+	policy, appErr := c.App.GetWhitelistPolicyByName(c.Params.PolicyName)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(policy)
+	if err != nil {
+		c.Err = model.NewAppError("getWhitelistPolicyByName", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchWhitelistPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch model.WhitelistPolicyPatch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&patch); jsonErr != nil {
+		c.SetInvalidParamWithErr("policy", jsonErr)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchWhitelistPolicy", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "policy_id", c.Params.PolicyId)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	originalPolicy, appErr := c.App.GetWhitelistPolicy(c.Params.PolicyId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+	auditRec.AddEventPriorState(originalPolicy)
+
+	patched, appErr := c.App.PatchWhitelistPolicy(c.AppContext, c.Params.PolicyId, &patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("whitelist_policy")
+	auditRec.AddEventResultState(patched)
+
+	js, err := json.Marshal(patched)
+	if err != nil {
+		c.Err = model.NewAppError("patchWhitelistPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteWhitelistPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteWhitelistPolicy", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "policy_id", c.Params.PolicyId)
+
+	if appErr := c.App.DeleteWhitelistPolicy(c.AppContext, c.Params.PolicyId); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
+func getWhitelistPolicyTargets(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	targets, appErr := c.App.GetWhitelistPolicyTargets(c.Params.PolicyId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(targets)
+	if err != nil {
+		c.Err = model.NewAppError("getWhitelistPolicyTargets", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func addWhitelistPolicyTarget(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	var target model.WhitelistPolicyTarget
+	if jsonErr := json.NewDecoder(r.Body).Decode(&target); jsonErr != nil {
+		c.SetInvalidParamWithErr("target", jsonErr)
+		return
+	}
+	target.PolicyId = c.Params.PolicyId
+
+	auditRec := c.MakeAuditRecord("addWhitelistPolicyTarget", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "target", &target)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	added, appErr := c.App.AddWhitelistPolicyTarget(c.AppContext, &target)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("whitelist_policy_target")
+	auditRec.AddEventResultState(added)
+
+	js, err := json.Marshal(added)
+	if err != nil {
+		c.Err = model.NewAppError("addWhitelistPolicyTarget", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func removeWhitelistPolicyTarget(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePolicyId()
+	if c.Err != nil {
+		return
+	}
+
+	var target model.WhitelistPolicyTarget
+	if jsonErr := json.NewDecoder(r.Body).Decode(&target); jsonErr != nil {
+		c.SetInvalidParamWithErr("target", jsonErr)
+		return
+	}
+	target.PolicyId = c.Params.PolicyId
+
+	auditRec := c.MakeAuditRecord("removeWhitelistPolicyTarget", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "target", &target)
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if appErr := c.App.RemoveWhitelistPolicyTarget(c.AppContext, &target); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
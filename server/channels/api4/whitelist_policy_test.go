@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestWhitelistPolicies(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	policy := &model.WhitelistPolicy{
+		Name:        "office",
+		Description: "Office network",
+		IPRanges:    model.StringArray{"10.0.0.0/8", "10.0.0.0/8", "192.0.2.1"},
+	}
+
+	t.Run("create", func(t *testing.T) {
+		_, resp, err := th.Client.CreateWhitelistPolicy(context.Background(), policy)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.CreateWhitelistPolicy(context.Background(), &model.WhitelistPolicy{Name: "bad_range", IPRanges: model.StringArray{"10.0.0.0/33"}})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		created, resp, err := th.SystemAdminClient.CreateWhitelistPolicy(context.Background(), policy)
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		assert.NotEmpty(t, created.Id)
+		assert.Equal(t, th.SystemAdminUser.Id, created.CreatorId)
+		assert.Equal(t, model.StringArray{"10.0.0.0/8", "192.0.2.1/32"}, created.IPRanges)
+		policy = created
+
+		_, resp, err = th.SystemAdminClient.CreateWhitelistPolicy(context.Background(), &model.WhitelistPolicy{Name: "office"})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+		CheckErrorID(t, err, "app.whitelist_policy.name_exists.app_error")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		_, resp, err := th.Client.GetWhitelistPolicies(context.Background(), 0, 60)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		policies, _, err := th.SystemAdminClient.GetWhitelistPolicies(context.Background(), 0, 60)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		assert.Equal(t, policy.Id, policies[0].Id)
+
+		fetched, _, err := th.SystemAdminClient.GetWhitelistPolicy(context.Background(), policy.Id)
+		require.NoError(t, err)
+		assert.Equal(t, policy.Name, fetched.Name)
+
+		fetched, _, err = th.SystemAdminClient.GetWhitelistPolicyByName(context.Background(), "office")
+		require.NoError(t, err)
+		assert.Equal(t, policy.Id, fetched.Id)
+
+		_, resp, err = th.Client.GetWhitelistPolicy(context.Background(), policy.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.GetWhitelistPolicy(context.Background(), model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patch := &model.WhitelistPolicyPatch{
+			Description: model.NewPointer("VPN"),
+			IPRanges:    &[]string{"172.16.0.0/12"},
+		}
+
+		_, resp, err := th.Client.PatchWhitelistPolicy(context.Background(), policy.Id, patch)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		patched, _, err := th.SystemAdminClient.PatchWhitelistPolicy(context.Background(), policy.Id, patch)
+		require.NoError(t, err)
+		assert.Equal(t, "office", patched.Name)
+		assert.Equal(t, "VPN", patched.Description)
+		assert.Equal(t, model.StringArray{"172.16.0.0/12"}, patched.IPRanges)
+
+		_, resp, err = th.SystemAdminClient.PatchWhitelistPolicy(context.Background(), policy.Id, &model.WhitelistPolicyPatch{IPRanges: &[]string{"not a range"}})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.PatchWhitelistPolicy(context.Background(), model.NewId(), patch)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("targets", func(t *testing.T) {
+		_, resp, err := th.Client.AddWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeTeam, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.AddWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeTeam, model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.AddWhitelistPolicyTarget(context.Background(), policy.Id, "channel", th.BasicChannel.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.AddWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeTeam, th.BasicTeam.Id)
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.AddWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeRole, model.SystemUserRoleId)
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.AddWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeTeam, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+		CheckErrorID(t, err, "app.whitelist_policy.target_exists.app_error")
+
+		targets, _, err := th.SystemAdminClient.GetWhitelistPolicyTargets(context.Background(), policy.Id)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+
+		_, resp, err = th.Client.GetWhitelistPolicyTargets(context.Background(), policy.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = th.Client.RemoveWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeRole, model.SystemUserRoleId)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, err = th.SystemAdminClient.RemoveWhitelistPolicyTarget(context.Background(), policy.Id, model.WhitelistPolicyTargetTypeRole, model.SystemUserRoleId)
+		require.NoError(t, err)
+
+		targets, _, err = th.SystemAdminClient.GetWhitelistPolicyTargets(context.Background(), policy.Id)
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		assert.Equal(t, th.BasicTeam.Id, targets[0].TargetId)
+	})
+
+	t.Run("policy applies to members of its targets", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.WhitelistSettings.Enable = true
+			*cfg.WhitelistSettings.EmptyWhitelistAction = model.WhitelistEmptyActionDeny
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.Enable = false })
+
+		allowed, appErr := th.App.CheckUserIPWhitelisted(th.Context, th.BasicUser.Id, "172.16.4.2")
+		require.Nil(t, appErr)
+		assert.True(t, allowed)
+
+		allowed, appErr = th.App.CheckUserIPWhitelisted(th.Context, th.BasicUser.Id, "10.1.2.3")
+		require.Nil(t, appErr)
+		assert.False(t, allowed)
+
+		// The cached policies are dropped when the policy changes
+		_, _, err := th.SystemAdminClient.PatchWhitelistPolicy(context.Background(), policy.Id, &model.WhitelistPolicyPatch{IPRanges: &[]string{"10.0.0.0/8"}})
+		require.NoError(t, err)
+
+		allowed, appErr = th.App.CheckUserIPWhitelisted(th.Context, th.BasicUser.Id, "10.1.2.3")
+		require.Nil(t, appErr)
+		assert.True(t, allowed)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp, err := th.Client.DeleteWhitelistPolicy(context.Background(), policy.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, err = th.SystemAdminClient.DeleteWhitelistPolicy(context.Background(), policy.Id)
+		require.NoError(t, err)
+
+		_, resp, err = th.SystemAdminClient.GetWhitelistPolicy(context.Background(), policy.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		resp, err = th.SystemAdminClient.DeleteWhitelistPolicy(context.Background(), policy.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+)
+
+func bleveSnapshotPath(name string) string {
+	return path.Join(model.BleveSnapshotsDirectory, name+".zip")
+}
+
+// bleveSnapshotManifestPath is the path of the description of a snapshot,
+// stored next to it to list the snapshots without opening them.
+func bleveSnapshotManifestPath(name string) string {
+	return path.Join(model.BleveSnapshotsDirectory, name+".json")
+}
+
+func (s *Server) bleveEngine(where string) (*bleveengine.BleveEngine, *model.AppError) {
+	engine, ok := s.platform.SearchEngine.BleveEngine.(*bleveengine.BleveEngine)
+	if !ok || engine == nil {
+		return nil, model.NewAppError(where, "searchengine.bleve.disabled.error", nil, "", http.StatusNotImplemented)
+	}
+	return engine, nil
+}
+
+// CreateBleveSnapshotJob creates the job taking a snapshot of the Bleve
+// indexes, which copying the indexes makes too long to take during a request.
+func (a *App) CreateBleveSnapshotJob(rctx request.CTX) (*model.Job, *model.AppError) {
+	if _, appErr := a.Srv().bleveEngine("CreateBleveSnapshotJob"); appErr != nil {
+		return nil, appErr
+	}
+
+	return a.Srv().Jobs.CreateJob(rctx, model.JobTypeBleveSnapshot, nil)
+}
+
+// CreateBleveSnapshot stores a snapshot of the Bleve indexes in the file store.
+func (a *App) CreateBleveSnapshot(rctx request.CTX) (*model.BleveSnapshot, *model.AppError) {
+	engine, appErr := a.Srv().bleveEngine("CreateBleveSnapshot")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	name := time.Now().UTC().Format("20060102-150405") + "-" + model.NewId()[:8]
+
+	// The archive is written to the file store while it is made
+	reader, writer := io.Pipe()
+	type snapshotResult struct {
+		snapshot *model.BleveSnapshot
+		appErr   *model.AppError
+	}
+	resultCh := make(chan snapshotResult, 1)
+	go func() {
+		snapshot, appErr := engine.Snapshot(rctx, writer)
+		if appErr != nil {
+			writer.CloseWithError(appErr)
+		} else {
+			writer.Close()
+		}
+		resultCh <- snapshotResult{snapshot, appErr}
+	}()
+
+	size, writeErr := a.WriteFile(reader, bleveSnapshotPath(name))
+	// Stops the snapshot if the file store failed
+	reader.Close()
+	result := <-resultCh
+
+	if result.appErr != nil || writeErr != nil {
+		if appErr := a.RemoveFile(bleveSnapshotPath(name)); appErr != nil {
+			rctx.Logger().Warn("Failed to remove the incomplete Bleve snapshot", mlog.String("name", name), mlog.Err(appErr))
+		}
+		// Failing to write the archive is the consequence of the file store
+		// failing
+		if result.appErr != nil && (writeErr == nil || result.appErr.Id != "bleveengine.snapshot.write.error") {
+			return nil, result.appErr
+		}
+		return nil, writeErr
+	}
+
+	snapshot := result.snapshot
+	snapshot.Name = name
+	snapshot.Size = size
+
+	manifest, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, model.NewAppError("CreateBleveSnapshot", "app.bleve_snapshot.marshal.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if _, appErr := a.WriteFile(bytes.NewReader(manifest), bleveSnapshotManifestPath(name)); appErr != nil {
+		return nil, appErr
+	}
+
+	return snapshot, nil
+}
+
+// GetBleveSnapshots returns the snapshots of the Bleve indexes of the file
+// store, the most recent first.
+func (a *App) GetBleveSnapshots(rctx request.CTX) ([]*model.BleveSnapshot, *model.AppError) {
+	paths, appErr := a.ListDirectory(model.BleveSnapshotsDirectory)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	snapshots := []*model.BleveSnapshot{}
+	for _, filePath := range paths {
+		if !strings.HasSuffix(filePath, ".json") {
+			continue
+		}
+
+		data, appErr := a.ReadFile(filePath)
+		if appErr != nil {
+			return nil, appErr
+		}
+		var snapshot model.BleveSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			rctx.Logger().Warn("Failed to decode the description of a Bleve snapshot", mlog.String("path", filePath), mlog.Err(err))
+			continue
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreateAt > snapshots[j].CreateAt
+	})
+
+	return snapshots, nil
+}
+
+// RestoreBleveSnapshot replaces the Bleve indexes by the ones of a snapshot of
+// the file store. When the indexes are shared, every node of the cluster
+// restores it.
+func (a *App) RestoreBleveSnapshot(rctx request.CTX, name string) (*model.BleveSnapshot, *model.AppError) {
+	engine, appErr := a.Srv().bleveEngine("RestoreBleveSnapshot")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	snapshot, appErr := a.Srv().restoreBleveSnapshot(rctx, engine, name)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if engine.IsShared() && a.Cluster() != nil {
+		a.Cluster().SendClusterMessage(&model.ClusterMessage{
+			Event:    model.ClusterEventBleveRestoreSnapshot,
+			SendType: model.ClusterSendReliable,
+			Data:     []byte(name),
+		})
+	}
+
+	return snapshot, nil
+}
+
+func (s *Server) restoreBleveSnapshot(rctx request.CTX, engine *bleveengine.BleveEngine, name string) (*model.BleveSnapshot, *model.AppError) {
+	if !model.IsValidBleveSnapshotName(name) {
+		return nil, model.NewAppError("RestoreBleveSnapshot", "app.bleve_snapshot.invalid_name.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	snapshotPath := bleveSnapshotPath(name)
+	exists, appErr := s.fileExists(snapshotPath)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if !exists {
+		return nil, model.NewAppError("RestoreBleveSnapshot", "app.bleve_snapshot.not_found.app_error", map[string]any{"Name": name}, "", http.StatusNotFound)
+	}
+
+	reader, appErr := s.fileReader(snapshotPath)
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer reader.Close()
+
+	// The archive is read at random, which not every file store supports
+	file, err := os.CreateTemp("", "bleve_snapshot")
+	if err != nil {
+		return nil, model.NewAppError("RestoreBleveSnapshot", "app.bleve_snapshot.download.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	size, err := io.Copy(file, reader)
+	if err != nil {
+		return nil, model.NewAppError("RestoreBleveSnapshot", "app.bleve_snapshot.download.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	snapshot, appErr := engine.RestoreSnapshot(rctx, file, size)
+	if appErr != nil {
+		return nil, appErr
+	}
+	snapshot.Name = name
+	snapshot.Size = size
+
+	return snapshot, nil
+}
+
+func (s *Server) clusterBleveRestoreSnapshotHandler(msg *model.ClusterMessage) {
+	engine, appErr := s.bleveEngine("RestoreBleveSnapshot")
+	if appErr != nil {
+		return
+	}
+
+	name := string(msg.Data)
+	s.Go(func() {
+		if _, appErr := s.restoreBleveSnapshot(request.EmptyContext(s.Log()), engine, name); appErr != nil {
+			s.Log().Error("Failed to restore the Bleve snapshot restored by another node", mlog.String("name", name), mlog.Err(appErr))
+		}
+	})
+}
@@ -62,6 +62,8 @@ func (s *Server) registerClusterHandlers() {
 	s.platform.RegisterClusterMessageHandler(model.ClusterEventInstallPlugin, s.clusterInstallPluginHandler)
 	s.platform.RegisterClusterMessageHandler(model.ClusterEventRemovePlugin, s.clusterRemovePluginHandler)
 	s.platform.RegisterClusterMessageHandler(model.ClusterEventPluginEvent, s.clusterPluginEventHandler)
+	s.platform.RegisterClusterMessageHandler(model.ClusterEventBleveRestoreSnapshot, s.clusterBleveRestoreSnapshotHandler)
+	s.platform.RegisterClusterMessageHandler(model.ClusterEventInvalidateSavedSearchAlerts, s.clusterInvalidateSavedSearchAlertsHandler)
 
 	s.platform.RegisterClusterHandlers()
 }
@@ -156,6 +156,43 @@ func (es *Service) SendVerifyEmail(userEmail, locale, siteURL, token, redirect s
 	return nil
 }
 
+// SendWhitelistEnrollmentEmail sends the link that adds ipAddress to the user's
+// IP whitelist once it is opened.
+func (es *Service) SendWhitelistEnrollmentEmail(email, locale, siteURL, ipAddress, token string) error {
+	T := i18n.GetUserTranslations(locale)
+
+	link := fmt.Sprintf("%s/api/v4/whitelist/enrollment/confirm?token=%s", siteURL, url.QueryEscape(token))
+
+	serverURL := condenseSiteURL(siteURL)
+
+	subject := T("api.templates.whitelist_enrollment_subject",
+		map[string]any{"SiteName": es.config().TeamSettings.SiteName})
+
+	data := es.NewEmailTemplateData(locale)
+	data.Props["SiteURL"] = siteURL
+	data.Props["Title"] = T("api.templates.whitelist_enrollment_body.title")
+	data.Props["SubTitle1"] = T("api.templates.whitelist_enrollment_body.subTitle1", map[string]any{"IPAddress": ipAddress})
+	data.Props["ServerURL"] = T("api.templates.verify_body.serverURL", map[string]any{"ServerURL": serverURL})
+	data.Props["SubTitle2"] = T("api.templates.whitelist_enrollment_body.subTitle2")
+	data.Props["ButtonURL"] = link
+	data.Props["Button"] = T("api.templates.whitelist_enrollment_body.button")
+	data.Props["Info"] = T("api.templates.whitelist_enrollment_body.info")
+	data.Props["Info1"] = T("api.templates.whitelist_enrollment_body.info1")
+	data.Props["QuestionTitle"] = T("api.templates.questions_footer.title")
+	data.Props["QuestionInfo"] = T("api.templates.questions_footer.info")
+
+	body, err := es.templatesContainer.RenderToString("whitelist_enrollment_body", data)
+	if err != nil {
+		return err
+	}
+
+	if err := es.sendMail(email, subject, body, "WhitelistEnrollmentEmail"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (es *Service) SendSignInChangeEmail(email, method, locale, siteURL string) error {
 	T := i18n.GetUserTranslations(locale)
 
@@ -546,6 +546,24 @@ func (_m *ServiceInterface) SendWelcomeEmail(userID string, _a1 string, verified
 	return r0
 }
 
+// SendWhitelistEnrollmentEmail provides a mock function with given fields: _a0, locale, siteURL, ipAddress, token
+func (_m *ServiceInterface) SendWhitelistEnrollmentEmail(_a0 string, locale string, siteURL string, ipAddress string, token string) error {
+	ret := _m.Called(_a0, locale, siteURL, ipAddress, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendWhitelistEnrollmentEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
+		r0 = rf(_a0, locale, siteURL, ipAddress, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetStore provides a mock function with given fields: st
 func (_m *ServiceInterface) SetStore(st store.Store) {
 	_m.Called(st)
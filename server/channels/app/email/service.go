@@ -133,6 +133,7 @@ type ServiceInterface interface {
 	SendEmailChangeEmail(oldEmail, newEmail, locale, siteURL string) error
 	SendVerifyEmail(userEmail, locale, siteURL, token, redirect string) error
 	SendSignInChangeEmail(email, method, locale, siteURL string) error
+	SendWhitelistEnrollmentEmail(email, locale, siteURL, ipAddress, token string) error
 	SendWelcomeEmail(userID string, email string, verified bool, disableWelcomeEmail bool, locale, siteURL, redirect string) error
 	SendCloudWelcomeEmail(userEmail, locale, teamInviteID, workSpaceName, dns, siteURL string) error
 	SendPasswordChangeEmail(email, method, locale, siteURL string) error
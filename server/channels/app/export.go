@@ -170,6 +170,11 @@ func (a *App) BulkExport(ctx request.CTX, writer io.Writer, outPath string, job
 	}
 	profilePictures = append(profilePictures, botPPs...)
 
+	ctx.Logger().Info("Bulk export: exporting whitelist entries")
+	if appErr = a.exportAllWhitelistItems(ctx, job, writer); appErr != nil {
+		return appErr
+	}
+
 	ctx.Logger().Info("Bulk export: exporting posts")
 	attachments, appErr := a.exportAllPosts(ctx, job, writer, opts.IncludeAttachments, opts.IncludeArchivedChannels)
 	if appErr != nil {
@@ -629,6 +634,39 @@ func (a *App) exportAllBots(ctx request.CTX, job *model.Job, writer io.Writer, i
 	return profilePictures, nil
 }
 
+func (a *App) exportAllWhitelistItems(ctx request.CTX, job *model.Job, writer io.Writer) *model.AppError {
+	afterUserId := ""
+	afterIP := ""
+	cnt := 0
+
+	const pageSize = 1000
+
+	for {
+		items, err := a.Srv().Store().Whitelist().GetAllAfter(pageSize, afterUserId, afterIP)
+		if err != nil {
+			return model.NewAppError("exportAllWhitelistItems", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		cnt += len(items)
+		updateJobProgress(ctx.Logger(), a.Srv().Store(), job, "whitelist_entries_exported", cnt)
+
+		for _, item := range items {
+			afterUserId = item.UserId
+			afterIP = item.IP
+
+			if err := a.exportWriteLine(writer, importLineFromWhitelistItem(item)); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < pageSize {
+			break
+		}
+	}
+
+	return nil
+}
+
 func (a *App) buildUserTeamAndChannelMemberships(c request.CTX, userID string, includeArchivedChannels bool) (*[]imports.UserTeamImportData, *model.AppError) {
 	var memberships []imports.UserTeamImportData
 
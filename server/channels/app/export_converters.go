@@ -330,6 +330,19 @@ func importLineFromEmoji(emoji *model.Emoji, filePath string) *imports.LineImpor
 	}
 }
 
+func importLineFromWhitelistItem(item *model.WhitelistItemForExport) *imports.LineImportData {
+	return &imports.LineImportData{
+		Type: "whitelist",
+		Whitelist: &imports.WhitelistImportData{
+			Username:    &item.Username,
+			IP:          &item.IP,
+			Description: &item.Description,
+			CreateAt:    &item.CreateAt,
+			ExpiresAt:   &item.ExpiresAt,
+		},
+	}
+}
+
 func importRoleDataFromRole(role *model.Role) *imports.RoleImportData {
 	return &imports.RoleImportData{
 		Name:          &role.Name,
@@ -1501,7 +1501,18 @@ func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId strin
 		}
 	}
 
-	return fileInfoSearchResults, a.filterInaccessibleFiles(fileInfoSearchResults, filterFileOptions{assumeSortedCreatedAt: true})
+	if appErr := a.filterInaccessibleFiles(fileInfoSearchResults, filterFileOptions{assumeSortedCreatedAt: true}); appErr != nil {
+		return fileInfoSearchResults, appErr
+	}
+
+	// The snippets of the filtered out files would reveal their contents
+	for fileId := range fileInfoSearchResults.Snippets {
+		if _, ok := fileInfoSearchResults.FileInfos[fileId]; !ok {
+			delete(fileInfoSearchResults.Snippets, fileId)
+		}
+	}
+
+	return fileInfoSearchResults, nil
 }
 
 func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileInfo) error {
@@ -1510,15 +1521,27 @@ func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileI
 		return nil
 	}
 
+	// Nor the files that failed to be extracted too many times, as they would likely fail again.
+	if fileInfo.ContentExtractionFailures >= model.MaxContentExtractionFailures {
+		rctx.Logger().Debug("Skipping the content extraction of a file that failed too many times", mlog.String("file_info_id", fileInfo.Id), mlog.Int("failures", fileInfo.ContentExtractionFailures))
+		return nil
+	}
+
+	settings, err := a.contentExtractionSettings()
+	if err != nil {
+		return err
+	}
+
 	file, aerr := a.FileReader(fileInfo.Path)
 	if aerr != nil {
 		return errors.Wrap(aerr, "failed to open file for extract file content")
 	}
 	defer file.Close()
-	text, err := docextractor.Extract(rctx.Logger(), fileInfo.Name, file, docextractor.ExtractSettings{
-		ArchiveRecursion: *a.Config().FileSettings.ArchiveRecursion,
-	})
+	text, err := docextractor.Extract(rctx.Logger(), fileInfo.Name, file, settings)
 	if err != nil {
+		if storeErr := a.Srv().Store().FileInfo().SetContentExtractionFailure(rctx, fileInfo.Id, err.Error()); storeErr != nil {
+			rctx.Logger().Warn("Failed to record the content extraction failure.", mlog.Err(storeErr), mlog.String("file_info_id", fileInfo.Id))
+		}
 		return errors.Wrap(err, "failed to extract file content")
 	}
 	if text != "" {
@@ -1538,6 +1561,36 @@ func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileI
 	return nil
 }
 
+// contentExtractionSettings gets the settings of the content extraction from the config, with
+// the extractors reported to the metrics.
+func (a *App) contentExtractionSettings() (docextractor.ExtractSettings, error) {
+	fileSettings := a.Config().FileSettings
+	settings := docextractor.ExtractSettings{
+		ArchiveRecursion: *fileSettings.ArchiveRecursion,
+		Timeout:          time.Duration(*fileSettings.ExtractContentTimeoutSeconds) * time.Second,
+		MaxMemory:        int64(*fileSettings.ExtractContentMaxMemoryMB) * 1024 * 1024,
+	}
+
+	if metrics := a.Metrics(); metrics != nil {
+		settings.Observer = func(extractor string, elapsed time.Duration, err error) {
+			metrics.ObserveFileExtractionDuration(extractor, err == nil, elapsed.Seconds())
+			if err != nil {
+				metrics.IncrementFileExtractionFailureCounter(extractor, docextractor.FailureReason(err))
+			}
+		}
+	}
+
+	if *fileSettings.ExtractContentInWorker {
+		executable, err := os.Executable()
+		if err != nil {
+			return settings, errors.Wrap(err, "failed to find the executable of the content extraction worker")
+		}
+		settings.WorkerCommand = []string{executable, docextractor.WorkerCommandName}
+	}
+
+	return settings, nil
+}
+
 // GetLastAccessibleFileTime returns CreateAt time(from cache) of the last accessible post as per the cloud limit
 func (a *App) GetLastAccessibleFileTime() (int64, *model.AppError) {
 	license := a.Srv().License()
@@ -509,7 +509,7 @@ func TestSearchFilesInTeamForUser(t *testing.T) {
 		}
 
 		es := &mocks.SearchEngineInterface{}
-		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(resultsPage, nil)
+		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(resultsPage, nil, nil)
 		es.On("Start").Return(nil).Maybe()
 		es.On("IsActive").Return(true)
 		es.On("IsSearchEnabled").Return(true)
@@ -526,6 +526,38 @@ func TestSearchFilesInTeamForUser(t *testing.T) {
 		es.AssertExpectations(t)
 	})
 
+	t.Run("should return the snippets of the search engine", func(t *testing.T) {
+		th, fileInfos := setup(t, true)
+		defer th.TearDown()
+
+		page := 0
+		resultsPage := []string{
+			fileInfos[6].Id,
+			fileInfos[5].Id,
+		}
+		snippets := model.FileInfoSearchSnippets{
+			fileInfos[6].Id: {"the <mark>searched</mark> term"},
+		}
+
+		es := &mocks.SearchEngineInterface{}
+		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(resultsPage, snippets, nil)
+		es.On("Start").Return(nil).Maybe()
+		es.On("IsActive").Return(true)
+		es.On("IsSearchEnabled").Return(true)
+		th.App.Srv().Platform().SearchEngine.ElasticsearchEngine = es
+		defer func() {
+			th.App.Srv().Platform().SearchEngine.ElasticsearchEngine = nil
+		}()
+
+		results, err := th.App.SearchFilesInTeamForUser(th.Context, searchTerm, th.BasicUser.Id, th.BasicTeam.Id, false, false, 0, page, perPage)
+
+		require.Nil(t, err)
+		require.NotNil(t, results)
+		assert.Equal(t, resultsPage, results.Order)
+		assert.Equal(t, snippets, results.Snippets)
+		es.AssertExpectations(t)
+	})
+
 	t.Run("should return later pages of fileInfos from ElasticSearch", func(t *testing.T) {
 		th, fileInfos := setup(t, true)
 		defer th.TearDown()
@@ -537,7 +569,7 @@ func TestSearchFilesInTeamForUser(t *testing.T) {
 		}
 
 		es := &mocks.SearchEngineInterface{}
-		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(resultsPage, nil)
+		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(resultsPage, nil, nil)
 		es.On("Start").Return(nil).Maybe()
 		es.On("IsActive").Return(true)
 		es.On("IsSearchEnabled").Return(true)
@@ -561,7 +593,7 @@ func TestSearchFilesInTeamForUser(t *testing.T) {
 		page := 0
 
 		es := &mocks.SearchEngineInterface{}
-		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(nil, &model.AppError{})
+		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(nil, nil, &model.AppError{})
 		es.On("GetName").Return("mock")
 		es.On("Start").Return(nil).Maybe()
 		es.On("IsActive").Return(true)
@@ -594,7 +626,7 @@ func TestSearchFilesInTeamForUser(t *testing.T) {
 		page := 1
 
 		es := &mocks.SearchEngineInterface{}
-		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(nil, &model.AppError{})
+		es.On("SearchFiles", mock.Anything, mock.Anything, page, perPage).Return(nil, nil, &model.AppError{})
 		es.On("GetName").Return("mock")
 		es.On("Start").Return(nil).Maybe()
 		es.On("IsActive").Return(true)
@@ -621,6 +653,13 @@ func TestExtractContentFromFileInfo(t *testing.T) {
 
 	// Test that we don't process images.
 	require.NoError(t, app.ExtractContentFromFileInfo(request.TestContext(t), fi))
+
+	// Test that we don't process the files that failed too many times.
+	fi = &model.FileInfo{
+		MimeType:                  "application/pdf",
+		ContentExtractionFailures: model.MaxContentExtractionFailures,
+	}
+	require.NoError(t, app.ExtractContentFromFileInfo(request.TestContext(t), fi))
 }
 
 func TestGetLastAccessibleFileTime(t *testing.T) {
@@ -389,6 +389,11 @@ func (a *App) importLine(c request.CTX, line imports.LineImportData, dryRun bool
 			return model.NewAppError("BulkImport", "app.import.import_line.null_emoji.error", nil, "", http.StatusBadRequest)
 		}
 		return a.importEmoji(c, line.Emoji, dryRun)
+	case line.Type == "whitelist":
+		if line.Whitelist == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_whitelist.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importWhitelist(c, line.Whitelist, dryRun)
 	default:
 		return model.NewAppError("BulkImport", "app.import.import_line.unknown_line_type.error", map[string]any{"Type": line.Type}, "", http.StatusBadRequest)
 	}
@@ -2654,3 +2654,62 @@ func (a *App) extractThreadMembers(line *imports.LineImportWorkerData, users map
 
 	return threadMemberships, 0, nil
 }
+
+func (a *App) importWhitelist(rctx request.CTX, data *imports.WhitelistImportData, dryRun bool) *model.AppError {
+	var fields []mlog.Field
+	if data != nil && data.Username != nil && data.IP != nil {
+		fields = append(fields, mlog.String("username", *data.Username), mlog.String("ip", *data.IP))
+	}
+	rctx.Logger().Info("Validating whitelist entry", fields...)
+
+	if err := imports.ValidateWhitelistImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	rctx.Logger().Info("Importing whitelist entry", fields...)
+
+	user, nErr := a.Srv().Store().User().GetByUsername(*data.Username)
+	if nErr != nil {
+		return model.NewAppError("BulkImport", "app.import.import_whitelist.user_not_found.error", map[string]any{"Username": *data.Username}, "", http.StatusBadRequest).Wrap(nErr)
+	}
+
+	item := &model.WhitelistItem{
+		UserId: user.Id,
+		IP:     *data.IP,
+	}
+	if data.Description != nil {
+		item.Description = *data.Description
+	}
+	if data.CreateAt != nil {
+		item.CreateAt = *data.CreateAt
+	}
+	if data.ExpiresAt != nil {
+		item.ExpiresAt = *data.ExpiresAt
+	}
+	item.PreSave()
+	if appErr := item.IsValid(); appErr != nil {
+		return appErr
+	}
+
+	existing, nErr := a.Srv().Store().Whitelist().GetByUserId(user.Id)
+	if nErr != nil {
+		return model.NewAppError("importWhitelist", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+	}
+	for _, existingItem := range existing {
+		if existingItem.IP == item.IP {
+			rctx.Logger().Info("Skipping whitelist entry that already exists", fields...)
+			return nil
+		}
+	}
+
+	if nErr := a.Srv().Store().Whitelist().Add(item); nErr != nil {
+		return model.NewAppError("importWhitelist", "app.whitelist.add.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+	}
+
+	return nil
+}
@@ -5184,6 +5184,43 @@ func TestImportImportEmoji(t *testing.T) {
 	require.ErrorIs(t, appErr.Unwrap(), utils.ErrSizeLimitExceeded)
 }
 
+func TestImportImportWhitelist(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	data := imports.WhitelistImportData{
+		Username:    model.NewPointer(th.BasicUser.Username),
+		IP:          model.NewPointer("10.20.30.40/16"),
+		Description: model.NewPointer("VPN"),
+	}
+
+	appErr := th.App.importWhitelist(th.Context, &data, true)
+	require.Nil(t, appErr, "Valid entry should have passed dry run")
+
+	items, appErr := th.App.GetUserWhitelist(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	assert.Empty(t, items, "Dry run should not have imported the entry")
+
+	appErr = th.App.importWhitelist(th.Context, &data, false)
+	require.Nil(t, appErr)
+
+	// Importing the same entry again is a no-op
+	appErr = th.App.importWhitelist(th.Context, &data, false)
+	require.Nil(t, appErr)
+
+	items, appErr = th.App.GetUserWhitelist(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	require.Len(t, items, 1)
+	assert.Equal(t, "10.20.0.0/16", items[0].IP)
+	assert.Equal(t, "VPN", items[0].Description)
+
+	data.Username = model.NewPointer(model.NewUsername())
+	appErr = th.App.importWhitelist(th.Context, &data, false)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "app.import.import_whitelist.user_not_found.error", appErr.Id)
+}
+
 func TestImportAttachment(t *testing.T) {
 	mainHelper.Parallel(t)
 	th := Setup(t)
@@ -24,6 +24,7 @@ type LineImportData struct {
 	DirectChannel *DirectChannelImportData `json:"direct_channel,omitempty"`
 	DirectPost    *DirectPostImportData    `json:"direct_post,omitempty"`
 	Emoji         *EmojiImportData         `json:"emoji,omitempty"`
+	Whitelist     *WhitelistImportData     `json:"whitelist,omitempty"`
 	Version       *int                     `json:"version,omitempty"`
 	Info          *VersionInfoImportData   `json:"info,omitempty"`
 }
@@ -174,6 +175,14 @@ type EmojiImportData struct {
 	Data  *zip.File `json:"-"`
 }
 
+type WhitelistImportData struct {
+	Username    *string `json:"username"`
+	IP          *string `json:"ip"`
+	Description *string `json:"description,omitempty"`
+	CreateAt    *int64  `json:"create_at,omitempty"`
+	ExpiresAt   *int64  `json:"expires_at,omitempty"`
+}
+
 type ReactionImportData struct {
 	User      *string `json:"user"`
 	CreateAt  *int64  `json:"create_at"`
@@ -717,6 +717,38 @@ func ValidateEmojiImportData(data *EmojiImportData) *model.AppError {
 	return nil
 }
 
+func ValidateWhitelistImportData(data *WhitelistImportData) *model.AppError {
+	if data == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.empty.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Username == nil || *data.Username == "" {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.username_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.IP == nil || *data.IP == "" {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.ip_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, err := model.ParseWhitelistPrefix(*data.IP); err != nil {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.ip_invalid.error", map[string]any{"IP": *data.IP}, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	if data.Description != nil && utf8.RuneCountInString(*data.Description) > model.WhitelistItemDescriptionMaxLength {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.description_length.error", map[string]any{"MaxLength": model.WhitelistItemDescriptionMaxLength}, "", http.StatusBadRequest)
+	}
+
+	if data.CreateAt != nil && *data.CreateAt < 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.create_at.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.ExpiresAt != nil && (*data.ExpiresAt < 0 || (*data.ExpiresAt != 0 && data.CreateAt != nil && *data.CreateAt != 0 && *data.ExpiresAt <= *data.CreateAt)) {
+		return model.NewAppError("BulkImport", "app.import.validate_whitelist_import_data.expires_at.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
 func isValidTrueOrFalseString(value string) bool {
 	return value == "true" || value == "false"
 }
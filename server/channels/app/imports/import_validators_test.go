@@ -1541,6 +1541,36 @@ func TestImportValidateEmojiImportData(t *testing.T) {
 	}
 }
 
+func TestImportValidateWhitelistImportData(t *testing.T) {
+	testCases := []struct {
+		testName    string
+		data        *WhitelistImportData
+		expectError string
+	}{
+		{"success", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("10.0.0.0/8"), Description: model.NewPointer("VPN"), CreateAt: model.NewPointer(int64(1000)), ExpiresAt: model.NewPointer(int64(2000))}, ""},
+		{"single address", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("2001:db8::1")}, ""},
+		{"nil data", nil, "app.import.validate_whitelist_import_data.empty.error"},
+		{"nil username", &WhitelistImportData{IP: model.NewPointer("10.0.0.1")}, "app.import.validate_whitelist_import_data.username_missing.error"},
+		{"empty ip", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("")}, "app.import.validate_whitelist_import_data.ip_missing.error"},
+		{"invalid ip", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("10.0.0.300")}, "app.import.validate_whitelist_import_data.ip_invalid.error"},
+		{"description too long", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("10.0.0.1"), Description: model.NewPointer(strings.Repeat("a", model.WhitelistItemDescriptionMaxLength+1))}, "app.import.validate_whitelist_import_data.description_length.error"},
+		{"negative create_at", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("10.0.0.1"), CreateAt: model.NewPointer(int64(-1))}, "app.import.validate_whitelist_import_data.create_at.error"},
+		{"expires before creation", &WhitelistImportData{Username: model.NewPointer("john"), IP: model.NewPointer("10.0.0.1"), CreateAt: model.NewPointer(int64(2000)), ExpiresAt: model.NewPointer(int64(1000))}, "app.import.validate_whitelist_import_data.expires_at.error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			err := ValidateWhitelistImportData(tc.data)
+			if tc.expectError == "" {
+				assert.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+				assert.Equal(t, tc.expectError, err.Id)
+			}
+		})
+	}
+}
+
 func checkError(t *testing.T, err *model.AppError) {
 	require.NotNil(t, err, "Should have returned an error.")
 }
@@ -76,7 +76,7 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 	switch job.Type {
 	case model.JobTypeBlevePostIndexing:
 		return a.SessionHasPermissionTo(session, model.PermissionCreatePostBleveIndexesJob), model.PermissionCreatePostBleveIndexesJob
-	case model.JobTypeDataRetention:
+	case model.JobTypeDataRetention, model.JobTypeBleveDataRetention:
 		return a.SessionHasPermissionTo(session, model.PermissionCreateDataRetentionJob), model.PermissionCreateDataRetentionJob
 	case model.JobTypeMessageExport:
 		return a.SessionHasPermissionTo(session, model.PermissionCreateComplianceExportJob), model.PermissionCreateComplianceExportJob
@@ -99,7 +99,7 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 		model.JobTypeCloud,
 		model.JobTypeExtractContent:
 		return a.SessionHasPermissionTo(session, model.PermissionManageJobs), model.PermissionManageJobs
-	case model.JobTypeAccessControlSync:
+	case model.JobTypeAccessControlSync, model.JobTypeBleveSnapshot:
 		return a.SessionHasPermissionTo(session, model.PermissionManageSystem), model.PermissionManageSystem
 	}
 
@@ -112,7 +112,7 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 	switch job.Type {
 	case model.JobTypeBlevePostIndexing:
 		permission = model.PermissionManagePostBleveIndexesJob
-	case model.JobTypeDataRetention:
+	case model.JobTypeDataRetention, model.JobTypeBleveDataRetention:
 		permission = model.PermissionManageDataRetentionJob
 	case model.JobTypeMessageExport:
 		permission = model.PermissionManageComplianceExportJob
@@ -135,7 +135,7 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 		model.JobTypeCloud,
 		model.JobTypeExtractContent:
 		permission = model.PermissionManageJobs
-	case model.JobTypeAccessControlSync:
+	case model.JobTypeAccessControlSync, model.JobTypeBleveSnapshot:
 		permission = model.PermissionManageSystem
 	}
 
@@ -148,7 +148,7 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 
 func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType string) (bool, *model.Permission) {
 	switch jobType {
-	case model.JobTypeDataRetention:
+	case model.JobTypeDataRetention, model.JobTypeBleveDataRetention:
 		return a.SessionHasPermissionTo(session, model.PermissionReadDataRetentionJob), model.PermissionReadDataRetentionJob
 	case model.JobTypeMessageExport:
 		return a.SessionHasPermissionTo(session, model.PermissionReadComplianceExportJob), model.PermissionReadComplianceExportJob
@@ -171,9 +171,11 @@ func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType strin
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
 		model.JobTypeMobileSessionMetadata,
+		model.JobTypeCleanupExpiredWhitelist,
+		model.JobTypeCleanupExpiredLoginBans,
 		model.JobTypeExtractContent:
 		return a.SessionHasPermissionTo(session, model.PermissionReadJobs), model.PermissionReadJobs
-	case model.JobTypeAccessControlSync:
+	case model.JobTypeAccessControlSync, model.JobTypeBleveSnapshot:
 		return a.SessionHasPermissionTo(session, model.PermissionManageSystem), model.PermissionManageSystem
 	}
 
@@ -55,6 +55,16 @@ func (a *App) AuthenticateUserForLogin(c request.CTX, id, loginId, password, mfa
 		}
 	}()
 
+	if err = a.checkLoginThrottle(c); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil && isFailedLogin(err, mfaToken) {
+			a.recordFailedLogin(c)
+		}
+	}()
+
 	if password == "" && !isCWSLogin(a, cwsToken) {
 		return nil, model.NewAppError("AuthenticateUserForLogin", "api.user.login.blank_pwd.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -0,0 +1,246 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const loginThrottleCacheSize = 50000
+
+// loginThrottleAddress parses the client address the login throttle applies to,
+// returning false when it can't be parsed or is exempt.
+func loginThrottleAddress(settings *model.LoginThrottleSettings, ipAddress string) (netip.Addr, bool) {
+	address, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	address = address.Unmap().WithZone("")
+
+	for _, exempt := range settings.ExemptAddresses {
+		if prefix, err := model.ParseWhitelistPrefix(exempt); err == nil && prefix.Contains(address) {
+			return netip.Addr{}, false
+		}
+	}
+
+	return address, true
+}
+
+func loginThrottleSubnet(settings *model.LoginThrottleSettings, address netip.Addr) netip.Prefix {
+	bits := *settings.IPv6SubnetPrefixLength
+	if address.Is4() {
+		bits = *settings.IPv4SubnetPrefixLength
+	}
+
+	subnet, _ := address.Prefix(bits)
+	return subnet
+}
+
+func loginFailuresKey(reason string, prefix netip.Prefix) string {
+	return reason + ":" + prefix.String()
+}
+
+// loginBackoff is how long to wait after the last failed login before trying
+// again, doubling with every failure past backoffAfterAttempts.
+func loginBackoff(settings *model.LoginThrottleSettings, failures int64, backoffAfterAttempts int) time.Duration {
+	if failures < int64(backoffAfterAttempts) {
+		return 0
+	}
+
+	maxBackoff := time.Duration(*settings.MaxBackoffSeconds) * time.Second
+	exponent := failures - int64(backoffAfterAttempts)
+	if exponent >= 32 {
+		return maxBackoff
+	}
+
+	return min(time.Second<<exponent, maxBackoff)
+}
+
+// checkLoginThrottle refuses a login from an address that is banned, or whose
+// address or subnet has to wait before trying again after failed logins.
+func (a *App) checkLoginThrottle(c request.CTX) *model.AppError {
+	settings := a.Config().LoginThrottleSettings
+	if !*settings.Enable {
+		return nil
+	}
+
+	address, ok := loginThrottleAddress(&settings, c.IPAddress())
+	if !ok {
+		return nil
+	}
+
+	now := model.GetMillis()
+	bans, err := a.Srv().Store().LoginBan().GetActive(now)
+	if err != nil {
+		// Logins stay possible when the bans can't be read
+		c.Logger().Warn("Failed to get the login bans", mlog.Err(err))
+	}
+
+	for _, ban := range bans {
+		if prefix, err := ban.Prefix(); err == nil && prefix.Contains(address) {
+			minutes := math.Ceil(float64(ban.ExpireAt-now) / float64(time.Minute.Milliseconds()))
+			return model.NewAppError("checkLoginThrottle", "app.login_throttle.banned.app_error", map[string]any{"Minutes": int(minutes)}, "ip="+ban.IP, http.StatusTooManyRequests)
+		}
+	}
+
+	var retryAt int64
+	for _, limit := range []struct {
+		reason               string
+		prefix               netip.Prefix
+		backoffAfterAttempts int
+	}{
+		{model.LoginBanReasonAddress, netip.PrefixFrom(address, address.BitLen()), *settings.BackoffAfterAttempts},
+		{model.LoginBanReasonSubnet, loginThrottleSubnet(&settings, address), *settings.SubnetBackoffAfterAttempts},
+	} {
+		key := loginFailuresKey(limit.reason, limit.prefix)
+		failures, err := a.Srv().loginFailures.Get(key)
+		if err != nil {
+			c.Logger().Warn("Failed to get the failed logins", mlog.String("key", key), mlog.Err(err))
+			continue
+		}
+		retryAt = max(retryAt, failures.UpdateAt+loginBackoff(&settings, failures.Count, limit.backoffAfterAttempts).Milliseconds())
+	}
+
+	if retryAt > now {
+		seconds := math.Ceil(float64(retryAt-now) / float64(time.Second.Milliseconds()))
+		return model.NewAppError("checkLoginThrottle", "app.login_throttle.backoff.app_error", map[string]any{"Seconds": int(seconds)}, "", http.StatusTooManyRequests)
+	}
+
+	return nil
+}
+
+// isFailedLogin reports whether a login error counts against the client address.
+func isFailedLogin(appErr *model.AppError, mfaToken string) bool {
+	// A login without MFA token is how the clients learn that the user needs one
+	if mfaToken == "" && appErr.Id == "api.user.check_user_mfa.bad_code.app_error" {
+		return false
+	}
+
+	return appErr.StatusCode == http.StatusBadRequest || appErr.StatusCode == http.StatusUnauthorized
+}
+
+// recordFailedLogin counts a failed login against the client address and its
+// subnet, banning either of them once they reach their maximum. The counts are
+// incremented atomically, so concurrent failures from any node all count.
+func (a *App) recordFailedLogin(c request.CTX) {
+	settings := a.Config().LoginThrottleSettings
+	if !*settings.Enable {
+		return
+	}
+
+	address, ok := loginThrottleAddress(&settings, c.IPAddress())
+	if !ok {
+		return
+	}
+
+	window := time.Duration(*settings.AttemptWindowMinutes) * time.Minute
+	for _, limit := range []struct {
+		reason      string
+		prefix      netip.Prefix
+		maxAttempts int
+	}{
+		{model.LoginBanReasonAddress, netip.PrefixFrom(address, address.BitLen()), *settings.MaxAttemptsPerAddress},
+		{model.LoginBanReasonSubnet, loginThrottleSubnet(&settings, address), *settings.MaxAttemptsPerSubnet},
+	} {
+		key := loginFailuresKey(limit.reason, limit.prefix)
+		failures, err := a.Srv().loginFailures.Increment(key, model.GetMillis(), window)
+		if err != nil {
+			c.Logger().Warn("Failed to count a failed login", mlog.String("key", key), mlog.Err(err))
+			continue
+		}
+
+		if limit.maxAttempts > 0 && failures.Count >= int64(limit.maxAttempts) {
+			a.banLoginPrefix(c, limit.prefix, limit.reason, int(failures.Count))
+		}
+	}
+}
+
+// banLoginPrefix bans an address or subnet and forgets its failed logins, so
+// that they are counted anew once the ban expires.
+func (a *App) banLoginPrefix(c request.CTX, prefix netip.Prefix, reason string, failedAttempts int) {
+	now := model.GetMillis()
+	ban, err := a.Srv().Store().LoginBan().Save(&model.LoginBan{
+		IP:             prefix.String(),
+		Reason:         reason,
+		FailedAttempts: failedAttempts,
+		CreateAt:       now,
+		ExpireAt:       now + int64(*a.Config().LoginThrottleSettings.BanDurationMinutes)*time.Minute.Milliseconds(),
+	})
+	if err != nil {
+		c.Logger().Error("Failed to ban an address after failed logins", mlog.String("ip", prefix.String()), mlog.Err(err))
+		return
+	}
+
+	c.Logger().Warn("Banned an address after failed logins",
+		mlog.String("ip", ban.IP),
+		mlog.String("reason", ban.Reason),
+		mlog.Int("failed_attempts", ban.FailedAttempts),
+		mlog.Int("expire_at", ban.ExpireAt))
+
+	if err := a.Srv().loginFailures.Remove(loginFailuresKey(reason, prefix)); err != nil {
+		c.Logger().Warn("Failed to reset the failed logins of a banned address", mlog.String("ip", ban.IP), mlog.Err(err))
+	}
+}
+
+// GetLoginBans returns the bans of the login throttle that have not expired.
+func (a *App) GetLoginBans() ([]*model.LoginBan, *model.AppError) {
+	bans, err := a.Srv().Store().LoginBan().GetActive(model.GetMillis())
+	if err != nil {
+		return nil, model.NewAppError("GetLoginBans", "app.login_throttle.get_bans.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return bans, nil
+}
+
+// RemoveLoginBan lifts the ban of an address or CIDR range and forgets its failed logins.
+func (a *App) RemoveLoginBan(c request.CTX, ip string) *model.AppError {
+	prefix, err := model.ParseWhitelistPrefix(ip)
+	if err != nil {
+		return model.NewAppError("RemoveLoginBan", "app.login_throttle.invalid_ip.app_error", nil, "ip="+ip, http.StatusBadRequest).Wrap(err)
+	}
+
+	normalized, _ := model.NormalizeWhitelistIP(ip)
+	if err := a.Srv().Store().LoginBan().Delete(normalized); err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return model.NewAppError("RemoveLoginBan", "app.login_throttle.ban_not_found.app_error", nil, "ip="+normalized, http.StatusNotFound).Wrap(err)
+		}
+		return model.NewAppError("RemoveLoginBan", "app.login_throttle.remove_ban.app_error", nil, "ip="+normalized, http.StatusInternalServerError).Wrap(err)
+	}
+
+	keys := []string{loginFailuresKey(model.LoginBanReasonAddress, prefix), loginFailuresKey(model.LoginBanReasonSubnet, prefix)}
+	if prefix.IsSingleIP() {
+		// The subnet of the address may be backing off too
+		settings := a.Config().LoginThrottleSettings
+		keys = append(keys, loginFailuresKey(model.LoginBanReasonSubnet, loginThrottleSubnet(&settings, prefix.Addr())))
+	}
+
+	if err := a.Srv().loginFailures.Remove(keys...); err != nil {
+		c.Logger().Warn("Failed to reset the failed logins of an address", mlog.String("ip", normalized), mlog.Err(err))
+	}
+
+	return nil
+}
+
+// ClearLoginBans lifts every ban and forgets every failed login.
+func (a *App) ClearLoginBans(c request.CTX) *model.AppError {
+	if err := a.Srv().Store().LoginBan().DeleteAll(); err != nil {
+		return model.NewAppError("ClearLoginBans", "app.login_throttle.remove_ban.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().loginFailures.Purge(); err != nil {
+		c.Logger().Warn("Failed to reset the failed logins", mlog.Err(err))
+	}
+
+	return nil
+}
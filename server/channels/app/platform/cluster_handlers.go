@@ -20,6 +20,7 @@ func (ps *PlatformService) RegisterClusterHandlers() {
 	ps.clusterIFace.RegisterClusterMessageHandler(model.ClusterEventBusyStateChanged, ps.clusterBusyStateChgHandler)
 	ps.clusterIFace.RegisterClusterMessageHandler(model.ClusterEventClearSessionCacheForUser, ps.clusterClearSessionCacheForUserHandler)
 	ps.clusterIFace.RegisterClusterMessageHandler(model.ClusterEventClearSessionCacheForAllUsers, ps.clusterClearSessionCacheForAllUsersHandler)
+	ps.clusterIFace.RegisterClusterMessageHandler(model.ClusterEventWhitelistRevokeUser, ps.clusterWhitelistRevokeUserHandler)
 
 	for e, h := range ps.additionalClusterHandlers {
 		ps.clusterIFace.RegisterClusterMessageHandler(e, h)
@@ -116,6 +117,7 @@ func (ps *PlatformService) InvalidateAllCachesSkipSend() *model.AppError {
 	ps.Store.Post().ClearCaches()
 	ps.Store.FileInfo().ClearCaches()
 	ps.Store.Webhook().ClearCaches()
+	ps.Store.Whitelist().ClearCaches()
 
 	if err := linkCache.Purge(); err != nil {
 		ps.logger.Warn("Failed to clear the link cache", mlog.Err(err))
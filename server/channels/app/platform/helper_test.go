@@ -65,6 +65,12 @@ func (ms *mockSuite) HasPermissionToReadChannel(c request.CTX, userID string, ch
 	return true
 }
 
+func (ms *mockSuite) EvaluateUserIPWhitelist(c request.CTX, userID string, ipAddress string) (*model.WhitelistDecision, *model.AppError) {
+	return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleDisabled}, nil
+}
+
+func (ms *mockSuite) RecordWhitelistDenial(c request.CTX, denial *model.WhitelistDenial) {}
+
 func setupDBStore(tb testing.TB) (store.Store, *model.SqlSettings) {
 	var dbStore store.Store
 	var dbSettings *model.SqlSettings
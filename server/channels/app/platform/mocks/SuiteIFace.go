@@ -16,6 +16,38 @@ type SuiteIFace struct {
 	mock.Mock
 }
 
+// EvaluateUserIPWhitelist provides a mock function with given fields: c, userID, ipAddress
+func (_m *SuiteIFace) EvaluateUserIPWhitelist(c request.CTX, userID string, ipAddress string) (*model.WhitelistDecision, *model.AppError) {
+	ret := _m.Called(c, userID, ipAddress)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EvaluateUserIPWhitelist")
+	}
+
+	var r0 *model.WhitelistDecision
+	var r1 *model.AppError
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string) (*model.WhitelistDecision, *model.AppError)); ok {
+		return rf(c, userID, ipAddress)
+	}
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string) *model.WhitelistDecision); ok {
+		r0 = rf(c, userID, ipAddress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistDecision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(request.CTX, string, string) *model.AppError); ok {
+		r1 = rf(c, userID, ipAddress)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetSession provides a mock function with given fields: token
 func (_m *SuiteIFace) GetSession(token string) (*model.Session, *model.AppError) {
 	ret := _m.Called(token)
@@ -66,6 +98,11 @@ func (_m *SuiteIFace) HasPermissionToReadChannel(c request.CTX, userID string, c
 	return r0
 }
 
+// RecordWhitelistDenial provides a mock function with given fields: c, denial
+func (_m *SuiteIFace) RecordWhitelistDenial(c request.CTX, denial *model.WhitelistDenial) {
+	_m.Called(c, denial)
+}
+
 // RolesGrantPermission provides a mock function with given fields: roleNames, permissionId
 func (_m *SuiteIFace) RolesGrantPermission(roleNames []string, permissionId string) bool {
 	ret := _m.Called(roleNames, permissionId)
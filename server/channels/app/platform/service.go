@@ -220,6 +220,14 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 	// Depends on step 3 (s.SearchEngine must be non-nil)
 	ps.initEnterprise()
 
+	// The Bleve indexes can be shared through the cluster
+	if ps.clusterIFace != nil {
+		bleveEngine.SetCluster(ps.clusterIFace, ps.Log())
+		for _, event := range bleveengine.ClusterEvents {
+			ps.RegisterClusterMessageHandler(event, bleveEngine.HandleClusterMessage)
+		}
+	}
+
 	// Step 5: Init Metrics
 	if metricsInterfaceFn != nil && ps.metricsIFace == nil { // if the metrics interface is set by options, do not override it
 		ps.metricsIFace = metricsInterfaceFn(ps, *ps.configStore.Get().SqlSettings.DriverName, *ps.configStore.Get().SqlSettings.DataSource)
@@ -30,6 +30,8 @@ type SuiteIFace interface {
 	RolesGrantPermission(roleNames []string, permissionId string) bool
 	HasPermissionToReadChannel(c request.CTX, userID string, channel *model.Channel) bool
 	UserCanSeeOtherUser(c request.CTX, userID string, otherUserId string) (bool, *model.AppError)
+	EvaluateUserIPWhitelist(c request.CTX, userID string, ipAddress string) (*model.WhitelistDecision, *model.AppError)
+	RecordWhitelistDenial(c request.CTX, denial *model.WhitelistDenial)
 }
 
 type webConnActivityMessage struct {
@@ -65,6 +67,13 @@ type webConnCountMessage struct {
 	result chan int
 }
 
+// webConnListMessage lists the active connections of a user, or of every user
+// if userID is empty.
+type webConnListMessage struct {
+	userID string
+	result chan []*WebConn
+}
+
 var hubSemaphoreCount = runtime.NumCPU() * 4
 
 // Hub is the central place to manage all websocket connections in the server.
@@ -88,6 +97,7 @@ type Hub struct {
 	checkRegistered chan *webConnSessionMessage
 	checkConn       chan *webConnCheckMessage
 	connCount       chan *webConnCountMessage
+	listConns       chan *webConnListMessage
 	broadcastHooks  map[string]BroadcastHook
 
 	// Hub-specific semaphore for limiting concurrent goroutines
@@ -109,6 +119,7 @@ func newWebHub(ps *PlatformService) *Hub {
 		checkRegistered: make(chan *webConnSessionMessage),
 		checkConn:       make(chan *webConnCheckMessage),
 		connCount:       make(chan *webConnCountMessage),
+		listConns:       make(chan *webConnListMessage),
 		hubSemaphore:    make(chan struct{}, hubSemaphoreCount),
 	}
 }
@@ -423,6 +434,25 @@ func (h *Hub) WebConnCountForUser(userID string) int {
 	return 0
 }
 
+// ActiveWebConnsForUser returns the active connections of the user in the hub.
+func (h *Hub) ActiveWebConnsForUser(userID string) []*WebConn {
+	req := &webConnListMessage{
+		userID: userID,
+		result: make(chan []*WebConn),
+	}
+	select {
+	case h.listConns <- req:
+		return <-req.result
+	case <-h.stop:
+	}
+	return nil
+}
+
+// ActiveWebConns returns the active connections of every user in the hub.
+func (h *Hub) ActiveWebConns() []*WebConn {
+	return h.ActiveWebConnsForUser("")
+}
+
 // Broadcast broadcasts the message to all connections in the hub.
 func (h *Hub) Broadcast(message *model.WebSocketEvent) {
 	// XXX: The hub nil check is because of the way we setup our tests. We call
@@ -557,6 +587,22 @@ func (h *Hub) Start() {
 				req.result <- res
 			case req := <-h.connCount:
 				req.result <- connIndex.ForUserActiveCount(req.userID)
+			case req := <-h.listConns:
+				var conns []*WebConn
+				if req.userID == "" {
+					for conn := range connIndex.All() {
+						if conn.Active.Load() {
+							conns = append(conns, conn)
+						}
+					}
+				} else {
+					for conn := range connIndex.ForUser(req.userID) {
+						if conn.Active.Load() {
+							conns = append(conns, conn)
+						}
+					}
+				}
+				req.result <- conns
 			case <-ticker.C:
 				connIndex.RemoveInactiveConnections()
 			case webConnReg := <-h.register:
@@ -57,6 +57,14 @@ func (wr *WebSocketRouter) ServeWebSocket(conn *WebConn, r *model.WebSocketReque
 		conn.SetSessionToken(session.Token)
 		conn.UserId = session.UserId
 
+		if !conn.isIPWhitelisted() {
+			conn.Platform.Log().Warn("Websocket authentication denied: IP not in whitelist",
+				mlog.String("user_id", conn.UserId),
+				mlog.String("ip", conn.remoteAddress))
+			conn.WebSocket.Close()
+			return
+		}
+
 		nErr := conn.Platform.HubRegister(conn)
 		if nErr != nil {
 			conn.Platform.Log().Error("Error while registering to hub", mlog.String("user_id", conn.UserId), mlog.Err(nErr))
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+type whitelistRevokeMessage struct {
+	UserID         string `json:"user_id"`
+	RevokeSessions bool   `json:"revoke_sessions"`
+}
+
+// isIPWhitelisted checks the address the connection was opened from against
+// the whitelist of its user, recording the denial if it is refused. A failed
+// check is treated as a denial.
+func (wc *WebConn) isIPWhitelisted() bool {
+	c := request.EmptyContext(wc.Platform.logger)
+	decision, err := wc.Suite.EvaluateUserIPWhitelist(c, wc.UserId, wc.remoteAddress)
+	if err != nil {
+		wc.Platform.logger.Error("Error checking IP whitelist for websocket connection",
+			mlog.String("user_id", wc.UserId),
+			mlog.String("conn_id", wc.GetConnectionID()),
+			mlog.Err(err))
+		return false
+	}
+
+	if !decision.Allowed {
+		var sessionID string
+		if session := wc.GetSession(); session != nil {
+			sessionID = session.Id
+		}
+		wc.Suite.RecordWhitelistDenial(c, &model.WhitelistDenial{
+			UserId:    wc.UserId,
+			IP:        wc.remoteAddress,
+			Path:      model.APIURLSuffix + "/websocket",
+			Rule:      decision.Rule,
+			SessionId: sessionID,
+		})
+	}
+
+	return decision.Allowed
+}
+
+// CloseWebConnsNotWhitelisted closes the connections of the user, on every node
+// of the cluster, that were opened from an address which is no longer whitelisted.
+// If revokeSessions is true the sessions behind those connections are revoked too.
+func (ps *PlatformService) CloseWebConnsNotWhitelisted(userID string, revokeSessions bool) {
+	ps.CloseWebConnsNotWhitelistedSkipClusterSend(userID, revokeSessions)
+	ps.sendWhitelistRevokeMessage(whitelistRevokeMessage{UserID: userID, RevokeSessions: revokeSessions})
+}
+
+// CloseAllWebConnsNotWhitelisted is CloseWebConnsNotWhitelisted for the connections
+// of every user, for changes such as policy edits that may affect any of them.
+func (ps *PlatformService) CloseAllWebConnsNotWhitelisted(revokeSessions bool) {
+	ps.CloseAllWebConnsNotWhitelistedSkipClusterSend(revokeSessions)
+	ps.sendWhitelistRevokeMessage(whitelistRevokeMessage{RevokeSessions: revokeSessions})
+}
+
+func (ps *PlatformService) sendWhitelistRevokeMessage(revoke whitelistRevokeMessage) {
+	if ps.clusterIFace == nil {
+		return
+	}
+
+	data, err := json.Marshal(revoke)
+	if err != nil {
+		ps.logger.Warn("Failed to encode whitelist revoke message", mlog.String("user_id", revoke.UserID), mlog.Err(err))
+		return
+	}
+	ps.clusterIFace.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.ClusterEventWhitelistRevokeUser,
+		SendType: model.ClusterSendReliable,
+		Data:     data,
+	})
+}
+
+// CloseWebConnsNotWhitelistedSkipClusterSend is CloseWebConnsNotWhitelisted for
+// the connections of this node only.
+func (ps *PlatformService) CloseWebConnsNotWhitelistedSkipClusterSend(userID string, revokeSessions bool) {
+	hub := ps.GetHubForUserId(userID)
+	if hub == nil {
+		return
+	}
+
+	ps.closeWebConnsNotWhitelisted(hub.ActiveWebConnsForUser(userID), revokeSessions)
+}
+
+// CloseAllWebConnsNotWhitelistedSkipClusterSend is CloseAllWebConnsNotWhitelisted
+// for the connections of this node only.
+func (ps *PlatformService) CloseAllWebConnsNotWhitelistedSkipClusterSend(revokeSessions bool) {
+	for _, hub := range ps.hubs {
+		ps.closeWebConnsNotWhitelisted(hub.ActiveWebConns(), revokeSessions)
+	}
+}
+
+func (ps *PlatformService) closeWebConnsNotWhitelisted(conns []*WebConn, revokeSessions bool) {
+	c := request.EmptyContext(ps.logger)
+	revoked := map[string]bool{}
+	for _, conn := range conns {
+		if conn.isIPWhitelisted() {
+			continue
+		}
+
+		ps.logger.Info("Closing websocket connection from an address that is no longer whitelisted",
+			mlog.String("user_id", conn.UserId),
+			mlog.String("conn_id", conn.GetConnectionID()),
+			mlog.String("ip", conn.remoteAddress))
+
+		if session := conn.GetSession(); revokeSessions && session != nil && session.Id != "" && !revoked[session.Id] {
+			revoked[session.Id] = true
+			if err := ps.RevokeSession(c, session); err != nil {
+				ps.logger.Warn("Failed to revoke session of a connection that is no longer whitelisted",
+					mlog.String("user_id", conn.UserId),
+					mlog.String("session_id", session.Id),
+					mlog.Err(err))
+			}
+		}
+
+		// The read pump unregisters the connection once the socket is closed.
+		conn.WebSocket.Close()
+	}
+}
+
+func (ps *PlatformService) clusterWhitelistRevokeUserHandler(msg *model.ClusterMessage) {
+	var revoke whitelistRevokeMessage
+	if err := json.Unmarshal(msg.Data, &revoke); err != nil {
+		ps.logger.Warn("Failed to decode whitelist revoke message from JSON", mlog.Err(err))
+		return
+	}
+
+	// An empty user id is sent by CloseAllWebConnsNotWhitelisted
+	if revoke.UserID == "" {
+		ps.CloseAllWebConnsNotWhitelistedSkipClusterSend(revoke.RevokeSessions)
+		return
+	}
+
+	ps.CloseWebConnsNotWhitelistedSkipClusterSend(revoke.UserID, revoke.RevokeSessions)
+}
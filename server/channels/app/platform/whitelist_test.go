@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	platform_mocks "github.com/mattermost/mattermost/server/v8/channels/app/platform/mocks"
+)
+
+func TestCloseWebConnsNotWhitelisted(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	session, err := th.Service.CreateSession(th.Context, &model.Session{
+		UserId: th.BasicUser.Id,
+	})
+	require.NoError(t, err)
+
+	mockSuite := &platform_mocks.SuiteIFace{}
+	mockSuite.On("GetSession", session.Token).Return(session, nil)
+	mockSuite.On("EvaluateUserIPWhitelist", mock.Anything, th.BasicUser.Id, "10.0.0.1").Return(&model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleUserEntry}, nil)
+	mockSuite.On("EvaluateUserIPWhitelist", mock.Anything, th.BasicUser.Id, "192.168.0.1").Return(&model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleNotWhitelisted}, nil)
+	mockSuite.On("RecordWhitelistDenial", mock.Anything, mock.MatchedBy(func(denial *model.WhitelistDenial) bool {
+		return denial.IP == "192.168.0.1" && denial.Rule == model.WhitelistRuleNotWhitelisted
+	})).Once()
+	th.Suite = mockSuite
+
+	s := httptest.NewServer(dummyWebsocketHandler(t))
+	defer s.Close()
+
+	register := func(remoteAddress string) *WebConn {
+		d := websocket.Dialer{}
+		c, _, err := d.Dial("ws://"+s.Listener.Addr().String()+"/ws", nil)
+		require.NoError(t, err)
+
+		wc := th.Service.NewWebConn(&WebConnConfig{
+			WebSocket:     c,
+			Session:       *session,
+			TFunc:         i18n.IdentityTfunc(),
+			Locale:        "en",
+			RemoteAddress: remoteAddress,
+		}, th.Suite, &hookRunner{})
+		require.NoError(t, th.Service.HubRegister(wc))
+		go wc.Pump()
+		return wc
+	}
+
+	allowed := register("10.0.0.1")
+	defer allowed.Close()
+	register("192.168.0.1")
+
+	require.Equal(t, 2, th.Service.WebConnCountForUser(th.BasicUser.Id))
+
+	th.Service.CloseWebConnsNotWhitelisted(th.BasicUser.Id, true)
+
+	require.Eventually(t, func() bool {
+		return th.Service.WebConnCountForUser(th.BasicUser.Id) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	require.True(t, allowed.Active.Load())
+
+	_, err = th.Service.GetSessionByID(th.Context, session.Id)
+	require.Error(t, err)
+	mockSuite.AssertExpectations(t)
+}
+
+func TestCloseAllWebConnsNotWhitelisted(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	session, err := th.Service.CreateSession(th.Context, &model.Session{
+		UserId: th.BasicUser.Id,
+	})
+	require.NoError(t, err)
+	session2, err := th.Service.CreateSession(th.Context, &model.Session{
+		UserId: th.BasicUser2.Id,
+	})
+	require.NoError(t, err)
+
+	mockSuite := &platform_mocks.SuiteIFace{}
+	mockSuite.On("GetSession", session.Token).Return(session, nil)
+	mockSuite.On("GetSession", session2.Token).Return(session2, nil)
+	mockSuite.On("EvaluateUserIPWhitelist", mock.Anything, th.BasicUser.Id, "10.0.0.1").Return(&model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRulePolicy}, nil)
+	mockSuite.On("EvaluateUserIPWhitelist", mock.Anything, th.BasicUser2.Id, "10.0.0.1").Return(&model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleNotWhitelisted}, nil)
+	mockSuite.On("RecordWhitelistDenial", mock.Anything, mock.MatchedBy(func(denial *model.WhitelistDenial) bool {
+		return denial.UserId == th.BasicUser2.Id
+	})).Once()
+	th.Suite = mockSuite
+
+	s := httptest.NewServer(dummyWebsocketHandler(t))
+	defer s.Close()
+
+	register := func(session *model.Session) *WebConn {
+		d := websocket.Dialer{}
+		c, _, err := d.Dial("ws://"+s.Listener.Addr().String()+"/ws", nil)
+		require.NoError(t, err)
+
+		wc := th.Service.NewWebConn(&WebConnConfig{
+			WebSocket:     c,
+			Session:       *session,
+			TFunc:         i18n.IdentityTfunc(),
+			Locale:        "en",
+			RemoteAddress: "10.0.0.1",
+		}, th.Suite, &hookRunner{})
+		require.NoError(t, th.Service.HubRegister(wc))
+		go wc.Pump()
+		return wc
+	}
+
+	allowed := register(session)
+	defer allowed.Close()
+	register(session2)
+
+	th.Service.CloseAllWebConnsNotWhitelisted(false)
+
+	require.Eventually(t, func() bool {
+		return th.Service.WebConnCountForUser(th.BasicUser2.Id) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	require.True(t, allowed.Active.Load())
+	require.Equal(t, 1, th.Service.WebConnCountForUser(th.BasicUser.Id))
+
+	// The session is kept when not revoking
+	_, err = th.Service.GetSessionByID(th.Context, session2.Id)
+	require.NoError(t, err)
+	mockSuite.AssertExpectations(t)
+}
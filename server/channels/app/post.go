@@ -580,6 +580,10 @@ func (a *App) handlePostEvents(c request.CTX, post *model.Post, user *model.User
 		})
 	}
 
+	a.Srv().Go(func() {
+		a.sendSavedSearchAlerts(c, post, user, channel)
+	})
+
 	if triggerWebhooks {
 		a.Srv().Go(func() {
 			if err := a.handleWebhookEvents(c, post, team, channel, user); err != nil {
@@ -1713,9 +1717,26 @@ func (a *App) SearchPostsInTeam(teamID string, paramsList []*model.SearchParams)
 	})
 }
 
+// checkSearchQuerySupported rejects the regular expressions when no search
+// engine matches them, as the database would have to scan every message.
+func (a *App) checkSearchQuerySupported(paramsList []*model.SearchParams) *model.AppError {
+	for _, params := range paramsList {
+		if params.Query != nil && params.Query.ContainsType(model.SearchQueryTypeRegexp) && !a.SearchEngine().RegexpSearchEnabled() {
+			return model.NewAppError("SearchPostsForUser", "app.post.search.regexp_unsupported.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
 func (a *App) SearchPostsForUser(c request.CTX, terms string, userID string, teamID string, isOrSearch bool, includeDeletedChannels bool, timeZoneOffset int, page, perPage int) (*model.PostSearchResults, *model.AppError) {
 	var postSearchResults *model.PostSearchResults
-	paramsList := model.ParseSearchParams(strings.TrimSpace(terms), timeZoneOffset)
+	paramsList, appErr := model.ParseSearchParamsWithQuery(strings.TrimSpace(terms), timeZoneOffset)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr = a.checkSearchQuerySupported(paramsList); appErr != nil {
+		return nil, appErr
+	}
 	includeDeleted := includeDeletedChannels && *a.Config().TeamSettings.ExperimentalViewArchivedChannels
 
 	if !*a.Config().ServiceSettings.EnablePostSearch {
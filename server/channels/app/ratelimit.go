@@ -6,8 +6,11 @@ package app
 import (
 	"math"
 	"net/http"
+	"net/netip"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/throttled/throttled"
@@ -17,22 +20,78 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
 )
 
+// rateLimitErrorLogInterval is the least time between two logs of the errors of the rate
+// limiter, which fail every request while its store is unreachable.
+const rateLimitErrorLogInterval = time.Minute
+
+// rateLimitNamedRoutes are the endpoints of the named routes of a RateLimitRouteQuota.
+var rateLimitNamedRoutes = map[string][]string{
+	model.RateLimitRouteLogin: {
+		"POST /api/v4/users/login",
+		"POST /api/v4/users/login/*",
+	},
+	model.RateLimitRouteSearch: {
+		"POST /api/v4/posts/search",
+		"POST /api/v4/teams/*/posts/search",
+		"POST /api/v4/files/search",
+		"POST /api/v4/teams/*/files/search",
+	},
+	model.RateLimitRouteFileUpload: {
+		"POST /api/v4/files",
+		"POST /api/v4/uploads",
+		"POST /api/v4/uploads/*",
+	},
+	model.RateLimitRouteCreatePost: {
+		"POST /api/v4/posts",
+	},
+}
+
 type RateLimiter struct {
 	throttledRateLimiter *throttled.GCRARateLimiter
+	routeRateLimiters    []*routeRateLimiter
 	useAuth              bool
 	useIP                bool
 	header               string
 	trustedProxyIPHeader []string
+	trustedProxies       []netip.Prefix
+
+	// metrics counts the requests let through because of an error, when set.
+	metrics einterfaces.MetricsInterface
+
+	errorLogMutex    sync.Mutex
+	lastErrorLog     time.Time
+	suppressedErrors int
 }
 
-func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string) (*RateLimiter, error) {
+// routeRateLimiter applies the quota of a route, keeping its state apart from the other routes.
+type routeRateLimiter struct {
+	keyPrefix            string
+	endpoints            []rateLimitEndpoint
+	throttledRateLimiter *throttled.GCRARateLimiter
+}
+
+type rateLimitEndpoint struct {
+	method   string
+	segments []string
+}
+
+// NewRateLimiter creates a rate limiter keeping its state in memory. Client addresses are resolved
+// like for the IP whitelist, honoring the proxy headers only when set by one of the trusted proxies.
+func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string, trustedProxies []netip.Prefix) (*RateLimiter, error) {
 	store, err := memstore.New(*settings.MemoryStoreSize)
 	if err != nil {
 		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_memory_store"))
 	}
 
+	return NewRateLimiterWithStore(settings, trustedProxyIPHeader, trustedProxies, store)
+}
+
+// NewRateLimiterWithStore creates a rate limiter keeping its state in the given store, which
+// can be shared by the nodes of a cluster.
+func NewRateLimiterWithStore(settings *model.RateLimitSettings, trustedProxyIPHeader []string, trustedProxies []netip.Prefix, store throttled.GCRAStore) (*RateLimiter, error) {
 	quota := throttled.RateQuota{
 		MaxRate:  throttled.PerSec(*settings.PerSec),
 		MaxBurst: *settings.MaxBurst,
@@ -43,15 +102,88 @@ func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []st
 		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
 	}
 
+	routeRateLimiters := make([]*routeRateLimiter, 0, len(settings.RouteQuotas))
+	for i, routeQuota := range settings.RouteQuotas {
+		routeRateLimiter, err := newRouteRateLimiter(i, routeQuota, store)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
+		}
+		routeRateLimiters = append(routeRateLimiters, routeRateLimiter)
+	}
+
 	return &RateLimiter{
 		throttledRateLimiter: throttledRateLimiter,
+		routeRateLimiters:    routeRateLimiters,
 		useAuth:              *settings.VaryByUser,
 		useIP:                *settings.VaryByRemoteAddr,
 		header:               settings.VaryByHeader,
 		trustedProxyIPHeader: trustedProxyIPHeader,
+		trustedProxies:       trustedProxies,
+	}, nil
+}
+
+func newRouteRateLimiter(index int, routeQuota *model.RateLimitRouteQuota, store throttled.GCRAStore) (*routeRateLimiter, error) {
+	quota := throttled.RateQuota{
+		MaxRate:  throttled.PerSec(*routeQuota.PerSec),
+		MaxBurst: *routeQuota.MaxBurst,
+	}
+
+	throttledRateLimiter, err := throttled.NewGCRARateLimiter(store, quota)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, ok := rateLimitNamedRoutes[*routeQuota.Route]
+	if !ok {
+		patterns = []string{" " + *routeQuota.Route}
+	}
+
+	endpoints := make([]rateLimitEndpoint, 0, len(patterns))
+	for _, pattern := range patterns {
+		method, path, _ := strings.Cut(pattern, " ")
+		if *routeQuota.Method != "" {
+			if method != "" && method != *routeQuota.Method {
+				continue
+			}
+			method = *routeQuota.Method
+		}
+		endpoints = append(endpoints, rateLimitEndpoint{
+			method:   method,
+			segments: strings.Split(strings.Trim(path, "/"), "/"),
+		})
+	}
+
+	return &routeRateLimiter{
+		keyPrefix:            "route" + strconv.Itoa(index) + ":",
+		endpoints:            endpoints,
+		throttledRateLimiter: throttledRateLimiter,
 	}, nil
 }
 
+func (rrl *routeRateLimiter) matches(r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, endpoint := range rrl.endpoints {
+		if endpoint.method != "" && endpoint.method != r.Method {
+			continue
+		}
+		if len(endpoint.segments) != len(segments) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range endpoint.segments {
+			if segment != segments[i] && (segment != "*" || segments[i] == "") {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 	key := ""
 
@@ -60,13 +192,13 @@ func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 		if tokenLocation != TokenLocationNotFound {
 			key += token
 		} else if rl.useIP { // If we don't find an authentication token and IP based is enabled, fall back to IP
-			key += utils.GetIPAddress(r, rl.trustedProxyIPHeader)
+			key += utils.GetClientIPAddress(r, rl.trustedProxyIPHeader, rl.trustedProxies)
 		}
 	} else if rl.useIP { // Only if Auth based is not enabed do we use a plain IP based
-		key += utils.GetIPAddress(r, rl.trustedProxyIPHeader)
+		key += utils.GetClientIPAddress(r, rl.trustedProxyIPHeader, rl.trustedProxies)
 	}
 
-	// Note that most of the time the user won't have to set this because the utils.GetIPAddress above tries the
+	// Note that most of the time the user won't have to set this because the utils.GetClientIPAddress above tries the
 	// most common headers anyway.
 	if rl.header != "" {
 		key += strings.ToLower(r.Header.Get(rl.header))
@@ -76,9 +208,24 @@ func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 }
 
 func (rl *RateLimiter) RateLimitWriter(key string, w http.ResponseWriter) bool {
-	limited, context, err := rl.throttledRateLimiter.RateLimit(key, 1)
+	return rl.rateLimitWriter(rl.throttledRateLimiter, key, w)
+}
+
+// RateLimitRequestWriter rate limits a request with the quota of the first route matching it,
+// or with the default quota.
+func (rl *RateLimiter) RateLimitRequestWriter(key string, w http.ResponseWriter, r *http.Request) bool {
+	for _, routeRateLimiter := range rl.routeRateLimiters {
+		if routeRateLimiter.matches(r) {
+			return rl.rateLimitWriter(routeRateLimiter.throttledRateLimiter, routeRateLimiter.keyPrefix+key, w)
+		}
+	}
+	return rl.RateLimitWriter(key, w)
+}
+
+func (rl *RateLimiter) rateLimitWriter(throttledRateLimiter *throttled.GCRARateLimiter, key string, w http.ResponseWriter) bool {
+	limited, context, err := throttledRateLimiter.RateLimit(key, 1)
 	if err != nil {
-		mlog.Error("Internal server error when rate limiting. Rate Limiting broken.", mlog.Err(err))
+		rl.handleError(err)
 		return false
 	}
 
@@ -92,9 +239,32 @@ func (rl *RateLimiter) RateLimitWriter(key string, w http.ResponseWriter) bool {
 	return limited
 }
 
-func (rl *RateLimiter) UserIdRateLimit(userID string, w http.ResponseWriter) bool {
+// handleError counts a request let through because of an error, and logs the error unless
+// another one was logged less than rateLimitErrorLogInterval ago, with the number of errors
+// left out of the logs since.
+func (rl *RateLimiter) handleError(err error) {
+	if rl.metrics != nil {
+		rl.metrics.IncrementHTTPRateLimitError()
+	}
+
+	rl.errorLogMutex.Lock()
+	now := time.Now()
+	if now.Sub(rl.lastErrorLog) < rateLimitErrorLogInterval {
+		rl.suppressedErrors++
+		rl.errorLogMutex.Unlock()
+		return
+	}
+	suppressedErrors := rl.suppressedErrors
+	rl.lastErrorLog = now
+	rl.suppressedErrors = 0
+	rl.errorLogMutex.Unlock()
+
+	mlog.Error("Internal server error when rate limiting. Rate Limiting broken.", mlog.Int("suppressed_errors", suppressedErrors), mlog.Err(err))
+}
+
+func (rl *RateLimiter) UserIdRateLimit(userID string, w http.ResponseWriter, r *http.Request) bool {
 	if rl.useAuth {
-		return rl.RateLimitWriter(userID, w)
+		return rl.RateLimitRequestWriter(userID, w, r)
 	}
 	return false
 }
@@ -103,29 +273,33 @@ func (rl *RateLimiter) RateLimitHandler(wrappedHandler http.Handler) http.Handle
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		key := rl.GenerateKey(r)
 
-		if !rl.RateLimitWriter(key, w) {
+		if !rl.RateLimitRequestWriter(key, w, r) {
 			wrappedHandler.ServeHTTP(w, r)
 		}
 	})
 }
 
-// Copied from https://github.com/throttled/throttled http.go
+// Copied from https://github.com/throttled/throttled http.go, with the RateLimit-* headers
+// of the IETF draft besides the X-RateLimit-* ones.
 func setRateLimitHeaders(w http.ResponseWriter, context throttled.RateLimitResult) {
 	if v := context.Limit; v >= 0 {
-		w.Header().Add("X-RateLimit-Limit", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(v))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(v))
 	}
 
 	if v := context.Remaining; v >= 0 {
-		w.Header().Add("X-RateLimit-Remaining", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(v))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(v))
 	}
 
 	if v := context.ResetAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("X-RateLimit-Reset", strconv.Itoa(vi))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(vi))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(vi))
 	}
 
 	if v := context.RetryAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("Retry-After", strconv.Itoa(vi))
+		w.Header().Set("Retry-After", strconv.Itoa(vi))
 	}
 }
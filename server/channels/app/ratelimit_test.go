@@ -4,14 +4,20 @@
 package app
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/throttled/throttled/store/memstore"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
 )
 
 func genRateLimitSettings(useAuth, useIP bool, header string) *model.RateLimitSettings {
@@ -29,11 +35,11 @@ func genRateLimitSettings(useAuth, useIP bool, header string) *model.RateLimitSe
 func TestNewRateLimiterSuccess(t *testing.T) {
 	mainHelper.Parallel(t)
 	settings := genRateLimitSettings(false, false, "")
-	rateLimiter, err := NewRateLimiter(settings, nil)
+	rateLimiter, err := NewRateLimiter(settings, nil, nil)
 	require.NotNil(t, rateLimiter)
 	require.NoError(t, err)
 
-	rateLimiter, err = NewRateLimiter(settings, []string{"X-Forwarded-For"})
+	rateLimiter, err = NewRateLimiter(settings, []string{"X-Forwarded-For"}, nil)
 	require.NotNil(t, rateLimiter)
 	require.NoError(t, err)
 }
@@ -42,11 +48,11 @@ func TestNewRateLimiterFailure(t *testing.T) {
 	mainHelper.Parallel(t)
 	invalidSettings := genRateLimitSettings(false, false, "")
 	invalidSettings.MaxBurst = model.NewPointer(-100)
-	rateLimiter, err := NewRateLimiter(invalidSettings, nil)
+	rateLimiter, err := NewRateLimiter(invalidSettings, nil, nil)
 	require.Nil(t, rateLimiter)
 	require.Error(t, err)
 
-	rateLimiter, err = NewRateLimiter(invalidSettings, []string{"X-Forwarded-For", "X-Real-Ip"})
+	rateLimiter, err = NewRateLimiter(invalidSettings, []string{"X-Forwarded-For", "X-Real-Ip"}, nil)
 	require.Nil(t, rateLimiter)
 	require.Error(t, err)
 }
@@ -85,7 +91,7 @@ func TestGenerateKey(t *testing.T) {
 			req.Header.Set(tc.header, tc.headerResult)
 		}
 
-		rateLimiter, _ := NewRateLimiter(genRateLimitSettings(tc.useAuth, tc.useIP, tc.header), nil)
+		rateLimiter, _ := NewRateLimiter(genRateLimitSettings(tc.useAuth, tc.useIP, tc.header), nil, nil)
 
 		key := rateLimiter.GenerateKey(req)
 
@@ -99,11 +105,151 @@ func TestGenerateKey_TrustedHeader(t *testing.T) {
 	req.RemoteAddr = "10.10.10.5:80"
 	req.Header.Set("X-Forwarded-For", "10.6.3.1, 10.5.1.2")
 
-	rateLimiter, _ := NewRateLimiter(genRateLimitSettings(true, true, ""), []string{"X-Forwarded-For"})
+	rateLimiter, _ := NewRateLimiter(genRateLimitSettings(true, true, ""), []string{"X-Forwarded-For"}, nil)
 	key := rateLimiter.GenerateKey(req)
 	require.Equal(t, "10.6.3.1", key, "Wrong key on test with allowed trusted proxy header")
 
-	rateLimiter, _ = NewRateLimiter(genRateLimitSettings(true, true, ""), nil)
+	rateLimiter, _ = NewRateLimiter(genRateLimitSettings(true, true, ""), nil, nil)
 	key = rateLimiter.GenerateKey(req)
 	require.Equal(t, "10.10.10.5", key, "Wrong key on test without allowed trusted proxy header")
 }
+
+func TestGenerateKey_TrustedProxies(t *testing.T) {
+	mainHelper.Parallel(t)
+	trustedProxies := utils.ParseTrustedProxies([]string{"10.10.10.0/24"})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Forwarded-For", "10.6.3.1, 10.5.1.2")
+
+	req.RemoteAddr = "10.10.10.5:80"
+	rateLimiter, _ := NewRateLimiter(genRateLimitSettings(false, true, ""), []string{"X-Forwarded-For"}, trustedProxies)
+	require.Equal(t, "10.5.1.2", rateLimiter.GenerateKey(req), "Wrong key on test from a trusted proxy")
+
+	req.RemoteAddr = "10.20.20.5:80"
+	require.Equal(t, "10.20.20.5", rateLimiter.GenerateKey(req), "Wrong key on test from an untrusted proxy")
+}
+
+func TestRateLimitRouteQuotas(t *testing.T) {
+	mainHelper.Parallel(t)
+	settings := genRateLimitSettings(false, true, "")
+	settings.RouteQuotas = []*model.RateLimitRouteQuota{
+		{Route: model.NewPointer(model.RateLimitRouteLogin), Method: model.NewPointer(""), PerSec: model.NewPointer(1), MaxBurst: model.NewPointer(1)},
+		{Route: model.NewPointer("/api/v4/channels/*/posts"), Method: model.NewPointer(http.MethodGet), PerSec: model.NewPointer(1), MaxBurst: model.NewPointer(2)},
+	}
+	rateLimiter, err := NewRateLimiter(settings, nil, nil)
+	require.NoError(t, err)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.10.10.5:80"
+		w := httptest.NewRecorder()
+		rateLimiter.RateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("applies the quota of a named route", func(t *testing.T) {
+		w := request(http.MethodPost, "/api/v4/users/login")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+
+		require.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v4/users/login/switch").Code)
+		w = request(http.MethodPost, "/api/v4/users/login")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		require.NotEmpty(t, w.Header().Get("Retry-After"))
+	})
+
+	t.Run("applies the quota of a path route", func(t *testing.T) {
+		for range 3 {
+			require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v4/channels/"+model.NewId()+"/posts").Code)
+		}
+		require.Equal(t, http.StatusTooManyRequests, request(http.MethodGet, "/api/v4/channels/"+model.NewId()+"/posts").Code)
+	})
+
+	t.Run("applies the default quota to the other requests", func(t *testing.T) {
+		w := request(http.MethodPost, "/api/v4/channels/"+model.NewId()+"/posts")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "101", w.Header().Get("RateLimit-Limit"))
+
+		w = request(http.MethodGet, "/api/v4/users/login")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "101", w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+// failingGCRAStore fails like a store that can't be reached.
+type failingGCRAStore struct{}
+
+func (failingGCRAStore) GetWithTime(key string) (int64, time.Time, error) {
+	return 0, time.Time{}, errors.New("connection refused")
+}
+
+func (failingGCRAStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (failingGCRAStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	mainHelper.Parallel(t)
+	rateLimiter, err := NewRateLimiterWithStore(genRateLimitSettings(false, true, ""), nil, nil, failingGCRAStore{})
+	require.NoError(t, err)
+
+	metrics := &mocks.MetricsInterface{}
+	metrics.On("IncrementHTTPRateLimitError").Times(3)
+	rateLimiter.metrics = metrics
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/api/v4/users/me", nil)
+		req.RemoteAddr = "10.10.10.5:80"
+		w := httptest.NewRecorder()
+		rateLimiter.RateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	metrics.AssertExpectations(t)
+	require.False(t, rateLimiter.lastErrorLog.IsZero())
+	require.Equal(t, 2, rateLimiter.suppressedErrors)
+}
+
+func TestRateLimitRouteMatches(t *testing.T) {
+	mainHelper.Parallel(t)
+	store, err := memstore.New(100)
+	require.NoError(t, err)
+
+	cases := []struct {
+		route   string
+		method  string
+		request string
+		matches bool
+	}{
+		{model.RateLimitRouteSearch, "", "POST /api/v4/teams/abc/posts/search", true},
+		{model.RateLimitRouteSearch, "", "GET /api/v4/teams/abc/posts/search", false},
+		{model.RateLimitRouteSearch, "GET", "POST /api/v4/posts/search", false},
+		{model.RateLimitRouteFileUpload, "", "POST /api/v4/uploads/abc", true},
+		{model.RateLimitRouteCreatePost, "", "POST /api/v4/posts/", true},
+		{model.RateLimitRouteCreatePost, "", "POST /api/v4/posts/abc", false},
+		{"/api/v4/users/*", "", "DELETE /api/v4/users/abc", true},
+		{"/api/v4/users/*", "", "GET /api/v4/users/abc/teams", false},
+		{"/api/v4/users/*", "PUT", "GET /api/v4/users/abc", false},
+	}
+
+	for _, tc := range cases {
+		routeRateLimiter, err := newRouteRateLimiter(0, &model.RateLimitRouteQuota{
+			Route:    model.NewPointer(tc.route),
+			Method:   model.NewPointer(tc.method),
+			PerSec:   model.NewPointer(1),
+			MaxBurst: model.NewPointer(1),
+		}, store)
+		require.NoError(t, err)
+
+		method, path, _ := strings.Cut(tc.request, " ")
+		require.Equal(t, tc.matches, routeRateLimiter.matches(httptest.NewRequest(method, path, nil)), tc.route+" "+tc.method+" for "+tc.request)
+	}
+}
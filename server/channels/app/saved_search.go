@@ -0,0 +1,394 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+)
+
+// savedSearchAppError converts a saved search store error into an AppError
+func savedSearchAppError(where string, err error) *model.AppError {
+	var appErr *model.AppError
+	var nfErr *store.ErrNotFound
+	var uniqueErr *store.ErrUniqueConstraint
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &nfErr):
+		return model.NewAppError(where, "app.saved_search.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+	case errors.As(err, &uniqueErr):
+		return model.NewAppError(where, "app.saved_search.name_exists.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	default:
+		return model.NewAppError(where, "app.saved_search.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+}
+
+// validateSavedSearchTerms checks the terms of a saved search parse into a
+// valid search, so that running it or its alert can't fail later on.
+func validateSavedSearchTerms(savedSearch *model.SavedSearch) *model.AppError {
+	paramsList, appErr := model.ParseSearchParamsWithQuery(savedSearch.Terms, 0)
+	if appErr != nil {
+		return appErr
+	}
+
+	return model.IsSearchParamsListValid(paramsList)
+}
+
+// CreateSavedSearch saves a new named search for a user
+func (a *App) CreateSavedSearch(c request.CTX, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError) {
+	savedSearch.Id = ""
+
+	if appErr := validateSavedSearchTerms(savedSearch); appErr != nil {
+		return nil, appErr
+	}
+
+	savedSearches, err := a.Srv().Store().SavedSearch().GetForUser(savedSearch.UserId)
+	if err != nil {
+		return nil, model.NewAppError("CreateSavedSearch", "app.saved_search.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(savedSearches) >= model.SavedSearchMaxPerUser {
+		return nil, model.NewAppError("CreateSavedSearch", "app.saved_search.max_per_user.app_error", map[string]any{"Max": model.SavedSearchMaxPerUser}, "", http.StatusBadRequest)
+	}
+
+	saved, err := a.Srv().Store().SavedSearch().Save(savedSearch)
+	if err != nil {
+		return nil, savedSearchAppError("CreateSavedSearch", err)
+	}
+	if saved.Alert {
+		a.invalidateSavedSearchAlerts(saved.TeamId)
+	}
+
+	return saved, nil
+}
+
+// GetSavedSearch gets a saved search by id
+func (a *App) GetSavedSearch(savedSearchId string) (*model.SavedSearch, *model.AppError) {
+	savedSearch, err := a.Srv().Store().SavedSearch().Get(savedSearchId)
+	if err != nil {
+		return nil, savedSearchAppError("GetSavedSearch", err)
+	}
+
+	return savedSearch, nil
+}
+
+// GetSavedSearchesForUser gets the saved searches of a user ordered by name
+func (a *App) GetSavedSearchesForUser(userID string) ([]*model.SavedSearch, *model.AppError) {
+	savedSearches, err := a.Srv().Store().SavedSearch().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetSavedSearchesForUser", "app.saved_search.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return savedSearches, nil
+}
+
+// PatchSavedSearch updates the name, terms or alert of a saved search
+func (a *App) PatchSavedSearch(c request.CTX, savedSearchId string, patch *model.SavedSearchPatch) (*model.SavedSearch, *model.AppError) {
+	savedSearch, appErr := a.GetSavedSearch(savedSearchId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	hadAlert := savedSearch.Alert
+	savedSearch.Patch(patch)
+	if appErr := validateSavedSearchTerms(savedSearch); appErr != nil {
+		return nil, appErr
+	}
+
+	updated, err := a.Srv().Store().SavedSearch().Update(savedSearch)
+	if err != nil {
+		return nil, savedSearchAppError("PatchSavedSearch", err)
+	}
+	if hadAlert || updated.Alert {
+		a.invalidateSavedSearchAlerts(updated.TeamId)
+	}
+
+	return updated, nil
+}
+
+// DeleteSavedSearch removes a saved search and its alert
+func (a *App) DeleteSavedSearch(c request.CTX, savedSearchId string) *model.AppError {
+	savedSearch, appErr := a.GetSavedSearch(savedSearchId)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := a.Srv().Store().SavedSearch().Delete(savedSearchId); err != nil {
+		return savedSearchAppError("DeleteSavedSearch", err)
+	}
+	if savedSearch.Alert {
+		a.invalidateSavedSearchAlerts(savedSearch.TeamId)
+	}
+
+	return nil
+}
+
+// SearchPostsWithSavedSearch runs a saved search as its owner, so the results
+// only include the posts of the channels the owner can read.
+func (a *App) SearchPostsWithSavedSearch(c request.CTX, savedSearch *model.SavedSearch, timeZoneOffset, page, perPage int) (*model.PostSearchResults, *model.AppError) {
+	return a.SearchPostsForUser(c, savedSearch.Terms, savedSearch.UserId, savedSearch.TeamId, savedSearch.IsOrSearch, false, timeZoneOffset, page, perPage)
+}
+
+// savedSearchAlertsCacheDuration is how long the alerts of a team are kept
+// parsed, after which the channels and users of their filters, which may have
+// been renamed, are resolved again.
+const savedSearchAlertsCacheDuration = 5 * time.Minute
+
+// savedSearchAlert is a saved search with an alert, parsed and with the
+// channels and users of its filters resolved, ready to match the new posts.
+type savedSearchAlert struct {
+	savedSearch *model.SavedSearch
+	paramsList  []*model.SearchParams
+	// regexpsList holds the compiled regular expressions of each parameters,
+	// not to compile them again for every post.
+	regexpsList []model.SearchRegexps
+}
+
+// matchesPost reports whether the post matches one of the parsed parameters,
+// like a search.
+func (alert *savedSearchAlert) matchesPost(post *model.Post, analyzer model.SearchTextAnalyzer) bool {
+	for i, params := range alert.paramsList {
+		if params.MatchesPostWithRegexps(post, analyzer, alert.regexpsList[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+type savedSearchAlertCacheEntry struct {
+	alerts   []*savedSearchAlert
+	expireAt time.Time
+}
+
+// savedSearchAlertCache keeps the alerts of the teams in memory, as they are
+// matched against every new post. The alerts hold the parsed search queries,
+// so they aren't kept in a serialized cache.
+type savedSearchAlertCache struct {
+	mut   sync.Mutex
+	teams map[string]*savedSearchAlertCacheEntry
+}
+
+func (c *savedSearchAlertCache) get(teamID string) ([]*savedSearchAlert, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	entry, ok := c.teams[teamID]
+	if !ok || time.Now().After(entry.expireAt) {
+		return nil, false
+	}
+	return entry.alerts, true
+}
+
+func (c *savedSearchAlertCache) set(teamID string, alerts []*savedSearchAlert) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.teams == nil {
+		c.teams = map[string]*savedSearchAlertCacheEntry{}
+	}
+	c.teams[teamID] = &savedSearchAlertCacheEntry{alerts: alerts, expireAt: time.Now().Add(savedSearchAlertsCacheDuration)}
+}
+
+// invalidate removes the alerts of a team, or of every team for the saved
+// searches of all the teams.
+func (c *savedSearchAlertCache) invalidate(teamID string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if teamID == "" {
+		c.teams = nil
+		return
+	}
+	delete(c.teams, teamID)
+}
+
+// invalidateSavedSearchAlerts drops the cached alerts of the team of a saved
+// search that changed, on every node of the cluster.
+func (a *App) invalidateSavedSearchAlerts(teamID string) {
+	a.Srv().savedSearchAlerts.invalidate(teamID)
+
+	if a.Cluster() != nil {
+		a.Cluster().SendClusterMessage(&model.ClusterMessage{
+			Event:    model.ClusterEventInvalidateSavedSearchAlerts,
+			SendType: model.ClusterSendBestEffort,
+			Data:     []byte(teamID),
+		})
+	}
+}
+
+func (s *Server) clusterInvalidateSavedSearchAlertsHandler(msg *model.ClusterMessage) {
+	s.savedSearchAlerts.invalidate(string(msg.Data))
+}
+
+// getSavedSearchAlerts returns the alerts matching the posts of a team, parsed
+// and resolved once for all the posts until they change.
+func (a *App) getSavedSearchAlerts(c request.CTX, teamID string) ([]*savedSearchAlert, error) {
+	if alerts, ok := a.Srv().savedSearchAlerts.get(teamID); ok {
+		return alerts, nil
+	}
+
+	savedSearches, err := a.Srv().Store().SavedSearch().GetAlertsForTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*savedSearchAlert, 0, len(savedSearches))
+	for _, savedSearch := range savedSearches {
+		paramsList, appErr := model.ParseSearchParamsWithQuery(savedSearch.Terms, 0)
+		if appErr != nil {
+			c.Logger().Debug("Failed to parse the terms of a saved search", mlog.String("saved_search_id", savedSearch.Id), mlog.Err(appErr))
+			continue
+		}
+
+		alert := &savedSearchAlert{savedSearch: savedSearch}
+		for _, params := range paramsList {
+			if params.Terms == "*" {
+				continue
+			}
+			params.OrTerms = savedSearch.IsOrSearch
+			params.InChannels = a.convertChannelNamesToChannelIds(c, params.InChannels, savedSearch.UserId, savedSearch.TeamId, false)
+			params.ExcludedChannels = a.convertChannelNamesToChannelIds(c, params.ExcludedChannels, savedSearch.UserId, savedSearch.TeamId, false)
+			params.FromUsers = a.convertUserNameToUserIds(c, params.FromUsers)
+			params.ExcludedUsers = a.convertUserNameToUserIds(c, params.ExcludedUsers)
+			alert.paramsList = append(alert.paramsList, params)
+			alert.regexpsList = append(alert.regexpsList, params.CompileRegexps())
+		}
+		alerts = append(alerts, alert)
+	}
+
+	a.Srv().savedSearchAlerts.set(teamID, alerts)
+	return alerts, nil
+}
+
+// searchTextAnalyzer returns the analysis of the messages by the search engine
+// the posts are searched with, or nil when they are searched in the database.
+func (a *App) searchTextAnalyzer() model.SearchTextAnalyzer {
+	engine, ok := a.SearchEngine().BleveEngine.(*bleveengine.BleveEngine)
+	if !ok || engine == nil || !engine.IsSearchEnabled() {
+		return nil
+	}
+	return engine.PostTextAnalyzer()
+}
+
+// sendSavedSearchAlerts sends a direct message from the system bot to the
+// owners of the saved searches with an alert that match a new post. The posts
+// are matched as they are created, alongside their indexing, since they aren't
+// searchable right away and the search engines may not be indexing at all.
+func (a *App) sendSavedSearchAlerts(c request.CTX, post *model.Post, user *model.User, channel *model.Channel) {
+	// Bot posts are skipped, starting with the alerts themselves
+	if post.Type != model.PostTypeDefault || user.IsBot || post.IsFromOAuthBot() {
+		return
+	}
+
+	alerts, err := a.getSavedSearchAlerts(c, channel.TeamId)
+	if err != nil {
+		c.Logger().Warn("Failed to get the saved search alerts", mlog.String("team_id", channel.TeamId), mlog.Err(err))
+		return
+	}
+
+	analyzer := a.searchTextAnalyzer()
+	var matched []*model.SavedSearch
+	var ownerIDs []string
+	for _, alert := range alerts {
+		ownerID := alert.savedSearch.UserId
+		if ownerID == user.Id || slices.Contains(ownerIDs, ownerID) || !alert.matchesPost(post, analyzer) {
+			continue
+		}
+		matched = append(matched, alert.savedSearch)
+		ownerIDs = append(ownerIDs, ownerID)
+	}
+
+	recipients := a.savedSearchAlertRecipients(c, ownerIDs, channel)
+	for _, savedSearch := range matched {
+		if !recipients[savedSearch.UserId] {
+			continue
+		}
+		if appErr := a.sendSavedSearchAlert(c, savedSearch, post); appErr != nil {
+			c.Logger().Warn("Failed to send a saved search alert", mlog.String("saved_search_id", savedSearch.Id), mlog.String("post_id", post.Id), mlog.Err(appErr))
+		}
+	}
+}
+
+// savedSearchAlertRecipients returns the owners of saved searches who could
+// find a post of the channel by searching, which only covers the active users
+// who are members of the channel and still allowed to read it.
+func (a *App) savedSearchAlertRecipients(c request.CTX, userIDs []string, channel *model.Channel) map[string]bool {
+	recipients := map[string]bool{}
+	if len(userIDs) == 0 || a.isChannelArchivedAndHidden(channel) {
+		return recipients
+	}
+
+	users, err := a.Srv().Store().User().GetProfileByIds(c.Context(), userIDs, &store.UserGetByIdsOpts{}, true)
+	if err != nil {
+		c.Logger().Warn("Failed to get the owners of the saved search alerts", mlog.Err(err))
+		return recipients
+	}
+	activeIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		if user.DeleteAt == 0 && !user.IsBot {
+			activeIDs = append(activeIDs, user.Id)
+		}
+	}
+	if len(activeIDs) == 0 {
+		return recipients
+	}
+
+	members, err := a.Srv().Store().Channel().GetMembersByIds(channel.Id, activeIDs)
+	if err != nil {
+		c.Logger().Warn("Failed to get the channel members of the saved search alerts", mlog.String("channel_id", channel.Id), mlog.Err(err))
+		return recipients
+	}
+	for _, member := range members {
+		// The roles of the members usually grant the permission, the other
+		// checks are only made for the rest
+		if a.RolesGrantPermission(member.GetRoles(), model.PermissionReadChannelContent.Id) || a.HasPermissionToReadChannel(c, member.UserId, channel) {
+			recipients[member.UserId] = true
+		}
+	}
+
+	return recipients
+}
+
+func (a *App) sendSavedSearchAlert(c request.CTX, savedSearch *model.SavedSearch, post *model.Post) *model.AppError {
+	bot, appErr := a.GetSystemBot(c)
+	if appErr != nil {
+		return appErr
+	}
+
+	channel, appErr := a.GetOrCreateDirectChannel(c, savedSearch.UserId, bot.UserId)
+	if appErr != nil {
+		return appErr
+	}
+
+	user, appErr := a.GetUser(savedSearch.UserId)
+	if appErr != nil {
+		return appErr
+	}
+	T := i18n.GetUserTranslations(user.Locale)
+
+	alert := &model.Post{
+		ChannelId: channel.Id,
+		Message: T("app.saved_search.alert.message", map[string]any{
+			"Name": savedSearch.Name,
+			"Link": a.GetSiteURL() + "/_redirect/pl/" + post.Id,
+		}),
+		Type:   model.PostTypeDefault,
+		UserId: bot.UserId,
+	}
+
+	// The direct message is pushed and emailed like any other, following the
+	// notification preferences of the user
+	_, appErr = a.CreatePost(c, alert, channel, model.CreatePostFlags{ForceNotification: true})
+	return appErr
+}
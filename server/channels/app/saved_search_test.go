@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreateSavedSearch(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("base case", func(t *testing.T) {
+		savedSearch, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{
+			UserId: th.BasicUser.Id,
+			TeamId: th.BasicTeam.Id,
+			Name:   "outages",
+			Terms:  "(error OR failure) in:" + th.BasicChannel.Name,
+		})
+		require.Nil(t, appErr)
+		require.NotEmpty(t, savedSearch.Id)
+
+		savedSearches, appErr := th.App.GetSavedSearchesForUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, savedSearch.Id, savedSearches[0].Id)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{UserId: th.BasicUser.Id, Name: "outages", Terms: "error"})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.saved_search.name_exists.app_error", appErr.Id)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		_, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{UserId: th.BasicUser.Id, Name: "invalid", Terms: "(error OR failure"})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+}
+
+func TestSendSavedSearchAlerts(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	savedSearch, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{
+		UserId: th.BasicUser2.Id,
+		TeamId: th.BasicTeam.Id,
+		Name:   "deployments",
+		Terms:  "deploy* -rollback",
+		Alert:  true,
+	})
+	require.Nil(t, appErr)
+
+	bot, appErr := th.App.GetSystemBot(th.Context)
+	require.Nil(t, appErr)
+	botChannel, appErr := th.App.GetOrCreateDirectChannel(th.Context, th.BasicUser2.Id, bot.UserId)
+	require.Nil(t, appErr)
+
+	sendAlerts := func(message string) []*model.Post {
+		post := &model.Post{
+			Id:        model.NewId(),
+			ChannelId: th.BasicChannel.Id,
+			UserId:    th.BasicUser.Id,
+			Message:   message,
+			CreateAt:  model.GetMillis(),
+		}
+		th.App.sendSavedSearchAlerts(th.Context, post, th.BasicUser, th.BasicChannel)
+
+		alerts, appErr := th.App.GetPosts(botChannel.Id, 0, 10)
+		require.Nil(t, appErr)
+		return alerts.ToSlice()
+	}
+
+	t.Run("no alert for the channels the user isn't a member of", func(t *testing.T) {
+		assert.Empty(t, sendAlerts("deploying the release"))
+	})
+
+	th.AddUserToChannel(th.BasicUser2, th.BasicChannel)
+
+	t.Run("no alert for the posts the search doesn't match", func(t *testing.T) {
+		assert.Empty(t, sendAlerts("deploying the rollback"))
+		assert.Empty(t, sendAlerts("releasing"))
+	})
+
+	t.Run("alert for the posts the search matches", func(t *testing.T) {
+		alerts := sendAlerts("deploying the release")
+		require.Len(t, alerts, 1)
+		assert.Equal(t, bot.UserId, alerts[0].UserId)
+		assert.Contains(t, alerts[0].Message, "deployments")
+	})
+
+	t.Run("alert for the new terms of a patched search", func(t *testing.T) {
+		_, appErr := th.App.PatchSavedSearch(th.Context, savedSearch.Id, &model.SavedSearchPatch{Terms: model.NewPointer("releas*")})
+		require.Nil(t, appErr)
+
+		assert.Len(t, sendAlerts("releasing"), 2)
+	})
+}
@@ -6,6 +6,7 @@ package app
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -29,6 +30,16 @@ func (a *App) SaveScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 		return nil, model.NewAppError("App.scheduledPostPreSaveChecks", "app.save_scheduled_post.channel_deleted.app_error", map[string]any{"user_id": scheduledPost.UserId, "channel_id": scheduledPost.ChannelId}, "", http.StatusBadRequest)
 	}
 
+	if scheduledPost.PublishAsBotId != "" {
+		if appErr := a.checkScheduledPostBot(rctx, scheduledPost.ChannelId, scheduledPost.PublishAsBotId); appErr != nil {
+			return nil, appErr
+		}
+
+		if appErr := a.checkScheduledPostBotPermission(rctx, scheduledPost.UserId, scheduledPost.PublishAsBotId); appErr != nil {
+			return nil, appErr
+		}
+	}
+
 	savedScheduledPost, err := a.Srv().Store().ScheduledPost().CreateScheduledPost(scheduledPost)
 	if err != nil {
 		return nil, model.NewAppError("App.ScheduledPost", "app.save_scheduled_post.save.app_error", map[string]any{"user_id": scheduledPost.UserId, "channel_id": scheduledPost.ChannelId}, "", http.StatusBadRequest).Wrap(err)
@@ -81,6 +92,19 @@ func (a *App) UpdateScheduledPost(rctx request.CTX, userId string, scheduledPost
 	// updated scheduled post. It's better to do this before calling update than after.
 	scheduledPost.RestoreNonUpdatableFields(existingScheduledPost)
 
+	// The bot a post is published as can only be changed through the
+	// scheduled posts of the channel.
+	scheduledPost.PublishAsBotId = existingScheduledPost.PublishAsBotId
+	scheduledPost.LastEditorId = userId
+
+	// The author can still change the time of a post published as a bot, but
+	// its content only with the permission to manage the bot.
+	if scheduledPost.PublishAsBotId != "" && !scheduledPost.HasSameContent(existingScheduledPost) {
+		if appErr := a.checkScheduledPostBotPermission(rctx, userId, scheduledPost.PublishAsBotId); appErr != nil {
+			return nil, appErr
+		}
+	}
+
 	if err := a.Srv().Store().ScheduledPost().UpdatedScheduledPost(scheduledPost); err != nil {
 		return nil, model.NewAppError("app.UpdateScheduledPost", "app.update_scheduled_post.update.error", map[string]any{"user_id": userId, "scheduled_post_id": scheduledPost.Id}, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -113,6 +137,255 @@ func (a *App) DeleteScheduledPost(rctx request.CTX, userId, scheduledPostId, con
 	return scheduledPost, nil
 }
 
+// GetChannelScheduledPosts returns the scheduled posts of every user in a
+// channel, the next to be sent first.
+func (a *App) GetChannelScheduledPosts(rctx request.CTX, channelId string) ([]*model.ScheduledPost, *model.AppError) {
+	scheduledPosts, err := a.Srv().Store().ScheduledPost().GetScheduledPostsForChannel(channelId)
+	if err != nil {
+		return nil, model.NewAppError("App.GetChannelScheduledPosts", "app.get_channel_scheduled_posts.error", map[string]any{"channel_id": channelId}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if scheduledPosts == nil {
+		scheduledPosts = []*model.ScheduledPost{}
+	}
+
+	for _, scheduledPost := range scheduledPosts {
+		a.prepareDraftWithFileInfos(rctx, scheduledPost.UserId, &scheduledPost.Draft)
+	}
+
+	return scheduledPosts, nil
+}
+
+// UpdateChannelScheduledPost updates a scheduled post of any user in the
+// channel, including the bot it is published as. It stays the scheduled post
+// of its author, and only its author can change what is published as them:
+// the editor can only change the content of the post of another user when it
+// is published as a bot the editor manages.
+func (a *App) UpdateChannelScheduledPost(rctx request.CTX, editorId, channelId string, scheduledPost *model.ScheduledPost, connectionId string) (*model.ScheduledPost, *model.AppError) {
+	maxMessageLength := a.Srv().Store().ScheduledPost().GetMaxMessageSize()
+	scheduledPost.PreUpdate()
+	if validationErr := scheduledPost.IsValid(maxMessageLength); validationErr != nil {
+		return nil, validationErr
+	}
+
+	existingScheduledPost, appErr := a.getChannelScheduledPost("app.UpdateChannelScheduledPost", channelId, scheduledPost.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	scheduledPost.RestoreNonUpdatableFields(existingScheduledPost)
+
+	if scheduledPost.PublishAsBotId != "" {
+		if scheduledPost.PublishAsBotId != existingScheduledPost.PublishAsBotId {
+			if appErr := a.checkScheduledPostBot(rctx, channelId, scheduledPost.PublishAsBotId); appErr != nil {
+				return nil, appErr
+			}
+		}
+
+		if appErr := a.checkScheduledPostBotPermission(rctx, editorId, scheduledPost.PublishAsBotId); appErr != nil {
+			return nil, appErr
+		}
+	} else if editorId != scheduledPost.UserId && (existingScheduledPost.PublishAsBotId != "" || !scheduledPost.HasSameContent(existingScheduledPost)) {
+		// The content may have been written by someone else while the post
+		// was published as a bot, so it can't go back to its author either.
+		return nil, model.NewAppError("app.UpdateChannelScheduledPost", "app.scheduled_post.channel_scheduled_post.edit_content.app_error", map[string]any{"channel_id": channelId, "scheduled_post_id": scheduledPost.Id}, "", http.StatusForbidden)
+	}
+
+	scheduledPost.LastEditorId = editorId
+
+	if err := a.Srv().Store().ScheduledPost().UpdatedScheduledPost(scheduledPost); err != nil {
+		return nil, model.NewAppError("app.UpdateChannelScheduledPost", "app.update_scheduled_post.update.error", map[string]any{"channel_id": channelId, "scheduled_post_id": scheduledPost.Id}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, connectionId)
+
+	return scheduledPost, nil
+}
+
+// DeleteChannelScheduledPost cancels a scheduled post of any user in the channel.
+func (a *App) DeleteChannelScheduledPost(rctx request.CTX, channelId, scheduledPostId, connectionId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, appErr := a.getChannelScheduledPost("app.DeleteChannelScheduledPost", channelId, scheduledPostId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store().ScheduledPost().PermanentlyDeleteScheduledPosts([]string{scheduledPostId}); err != nil {
+		return nil, model.NewAppError("app.DeleteChannelScheduledPost", "app.delete_scheduled_post.delete_error", map[string]any{"channel_id": channelId, "scheduled_post_id": scheduledPostId}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, connectionId)
+
+	return scheduledPost, nil
+}
+
+func (a *App) getChannelScheduledPost(where, channelId, scheduledPostId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, err := a.Srv().Store().ScheduledPost().Get(scheduledPostId)
+	if err != nil {
+		return nil, model.NewAppError(where, "app.update_scheduled_post.get_scheduled_post.error", map[string]any{"channel_id": channelId, "scheduled_post_id": scheduledPostId}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// A scheduled post of another channel is reported as missing so that
+	// the permission on one channel doesn't reveal the posts of another.
+	if scheduledPost.ChannelId != channelId {
+		return nil, model.NewAppError(where, "app.scheduled_post.channel_scheduled_post.not_found", map[string]any{"channel_id": channelId, "scheduled_post_id": scheduledPostId}, "", http.StatusNotFound)
+	}
+
+	return scheduledPost, nil
+}
+
+// checkScheduledPostBot checks that a scheduled post can be published as a
+// bot in a channel: the bot must be enabled and a member of the channel.
+func (a *App) checkScheduledPostBot(rctx request.CTX, channelId, botUserId string) *model.AppError {
+	bot, appErr := a.GetBot(rctx, botUserId, false)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return model.NewAppError("App.checkScheduledPostBot", "app.scheduled_post.publish_as_bot.not_found.app_error", map[string]any{"channel_id": channelId, "bot_user_id": botUserId}, "", http.StatusBadRequest).Wrap(appErr)
+		}
+		return appErr
+	}
+
+	if _, appErr := a.GetChannelMember(rctx, channelId, bot.UserId); appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return model.NewAppError("App.checkScheduledPostBot", "app.scheduled_post.publish_as_bot.not_member.app_error", map[string]any{"channel_id": channelId, "bot_user_id": botUserId}, "", http.StatusBadRequest).Wrap(appErr)
+		}
+		return appErr
+	}
+
+	return nil
+}
+
+// checkScheduledPostBotPermission checks that a user can publish as a bot,
+// which takes the permission to manage the bot.
+func (a *App) checkScheduledPostBotPermission(rctx request.CTX, userId, botUserId string) *model.AppError {
+	bot, appErr := a.GetBot(rctx, botUserId, true)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return model.NewAppError("App.checkScheduledPostBotPermission", "app.scheduled_post.publish_as_bot.not_found.app_error", map[string]any{"user_id": userId, "bot_user_id": botUserId}, "", http.StatusBadRequest).Wrap(appErr)
+		}
+		return appErr
+	}
+
+	permission := model.PermissionManageOthersBots
+	if bot.OwnerId == userId {
+		permission = model.PermissionManageBots
+	}
+
+	if !a.HasPermissionTo(userId, permission) {
+		return model.NewAppError("App.checkScheduledPostBotPermission", "app.scheduled_post.publish_as_bot.permission.app_error", map[string]any{"user_id": userId, "bot_user_id": botUserId}, "", http.StatusForbidden)
+	}
+
+	return nil
+}
+
+// scheduledPostLocation is the timezone the occurrences of the recurring
+// scheduled posts of a user are in.
+func (a *App) scheduledPostLocation(userId string) *time.Location {
+	user, appErr := a.GetUser(userId)
+	if appErr != nil {
+		return time.UTC
+	}
+
+	return user.GetTimezoneLocation()
+}
+
+func (a *App) getRecurringScheduledPostForUpdate(where, userId, scheduledPostId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, err := a.Srv().Store().ScheduledPost().Get(scheduledPostId)
+	if err != nil {
+		return nil, model.NewAppError(where, "app.update_scheduled_post.get_scheduled_post.error", map[string]any{"user_id": userId, "scheduled_post_id": scheduledPostId}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if scheduledPost.UserId != userId {
+		return nil, model.NewAppError(where, "app.update_scheduled_post.update_permission.error", map[string]any{"user_id": userId, "scheduled_post_id": scheduledPostId}, "", http.StatusForbidden)
+	}
+
+	if !scheduledPost.IsRecurring() {
+		return nil, model.NewAppError(where, "app.scheduled_post.not_recurring.app_error", map[string]any{"user_id": userId, "scheduled_post_id": scheduledPostId}, "", http.StatusBadRequest)
+	}
+
+	return scheduledPost, nil
+}
+
+// saveRescheduledPost saves a recurring scheduled post after moving it to
+// another occurrence, or deletes it when the series has ended.
+func (a *App) saveRescheduledPost(rctx request.CTX, where string, scheduledPost *model.ScheduledPost, ended bool, connectionId string) *model.AppError {
+	if ended {
+		if err := a.Srv().Store().ScheduledPost().PermanentlyDeleteScheduledPosts([]string{scheduledPost.Id}); err != nil {
+			return model.NewAppError(where, "app.delete_scheduled_post.delete_error", map[string]any{"user_id": scheduledPost.UserId, "scheduled_post_id": scheduledPost.Id}, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, connectionId)
+		return nil
+	}
+
+	if err := a.Srv().Store().ScheduledPost().UpdatedScheduledPost(scheduledPost); err != nil {
+		return model.NewAppError(where, "app.update_scheduled_post.update.error", map[string]any{"user_id": scheduledPost.UserId, "scheduled_post_id": scheduledPost.Id}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, connectionId)
+	return nil
+}
+
+// PauseScheduledPost stops sending the occurrences of a recurring scheduled
+// post until it is resumed.
+func (a *App) PauseScheduledPost(rctx request.CTX, userId, scheduledPostId, connectionId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, appErr := a.getRecurringScheduledPostForUpdate("app.PauseScheduledPost", userId, scheduledPostId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if scheduledPost.IsPaused() {
+		return scheduledPost, nil
+	}
+
+	scheduledPost.PausedAt = model.GetMillis()
+	if appErr := a.saveRescheduledPost(rctx, "app.PauseScheduledPost", scheduledPost, false, connectionId); appErr != nil {
+		return nil, appErr
+	}
+
+	return scheduledPost, nil
+}
+
+// ResumeScheduledPost resumes a paused recurring scheduled post from its first
+// occurrence in the future, skipping those that passed while it was paused.
+func (a *App) ResumeScheduledPost(rctx request.CTX, userId, scheduledPostId, connectionId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, appErr := a.getRecurringScheduledPostForUpdate("app.ResumeScheduledPost", userId, scheduledPostId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !scheduledPost.IsPaused() {
+		return scheduledPost, nil
+	}
+
+	scheduledPost.PausedAt = 0
+	ended := false
+	if now := model.GetMillis(); scheduledPost.ScheduledAt <= now {
+		ended = !scheduledPost.Reschedule(now, a.scheduledPostLocation(userId))
+	}
+
+	if appErr := a.saveRescheduledPost(rctx, "app.ResumeScheduledPost", scheduledPost, ended, connectionId); appErr != nil {
+		return nil, appErr
+	}
+
+	return scheduledPost, nil
+}
+
+// SkipScheduledPostOccurrence moves a recurring scheduled post to the
+// occurrence after its next one, deleting it if that was its last.
+func (a *App) SkipScheduledPostOccurrence(rctx request.CTX, userId, scheduledPostId, connectionId string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, appErr := a.getRecurringScheduledPostForUpdate("app.SkipScheduledPostOccurrence", userId, scheduledPostId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	ended := !scheduledPost.Reschedule(max(scheduledPost.ScheduledAt, model.GetMillis()), a.scheduledPostLocation(userId))
+	if appErr := a.saveRescheduledPost(rctx, "app.SkipScheduledPostOccurrence", scheduledPost, ended, connectionId); appErr != nil {
+		return nil, appErr
+	}
+
+	return scheduledPost, nil
+}
+
 func (a *App) PublishScheduledPostEvent(rctx request.CTX, eventType model.WebsocketEventType, scheduledPost *model.ScheduledPost, connectionId string) {
 	if scheduledPost == nil {
 		rctx.Logger().Warn("publishScheduledPostEvent called with nil scheduledPost")
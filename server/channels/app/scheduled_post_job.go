@@ -21,8 +21,14 @@ import (
 const (
 	getPendingScheduledPostsPageSize = 100
 	scheduledPostBatchWaitTime       = 1 * time.Second
+	scheduledPostMaxDelay            = 24 * time.Hour
 )
 
+// errRecurringScheduledPostNotMoved is returned when a recurring scheduled post
+// can't be moved to its next occurrence. The current occurrence isn't sent then,
+// and is tried again by the next run of the job.
+var errRecurringScheduledPostNotMoved = errors.New("failed to move the recurring scheduled post to its next occurrence")
+
 func (a *App) ProcessScheduledPosts(rctx request.CTX) {
 	rctx = rctx.WithLogger(rctx.Logger().With(mlog.String("component", "scheduled_post_job")))
 
@@ -35,7 +41,7 @@ func (a *App) ProcessScheduledPosts(rctx request.CTX) {
 	}
 
 	beforeTime := model.GetMillis()
-	afterTime := beforeTime - (24 * 60 * 60 * 1000) // subtracting 24 hours from beforeTime
+	afterTime := beforeTime - scheduledPostMaxDelay.Milliseconds()
 	lastScheduledPostId := ""
 
 	for {
@@ -105,16 +111,45 @@ func (a *App) ProcessScheduledPosts(rctx request.CTX) {
 // processScheduledPostBatch processes one batch
 func (a *App) processScheduledPostBatch(rctx request.CTX, scheduledPosts []*model.ScheduledPost) error {
 	var failedScheduledPosts []*model.ScheduledPost
+	var failedOccurrences []*model.ScheduledPost
 	var successfulScheduledPostIDs []string
 
 	for i := range scheduledPosts {
-		scheduledPost, err := a.postScheduledPost(rctx, scheduledPosts[i])
-		if err != nil {
+		// An occurrence of a recurring post that was missed by more than the
+		// delay scheduled posts are sent within is skipped, and the series goes on.
+		if scheduledPosts[i].IsRecurring() && model.GetMillis()-scheduledPosts[i].ScheduledAt > scheduledPostMaxDelay.Milliseconds() {
+			rctx.Logger().Debug("processScheduledPostBatch skipping missed occurrences of a recurring scheduled post", mlog.String("scheduled_post_id", scheduledPosts[i].Id), mlog.Int("scheduled_at", scheduledPosts[i].ScheduledAt))
+			rescheduled, err := a.rescheduleRecurringScheduledPost(rctx, scheduledPosts[i])
+			if err != nil {
+				continue
+			}
+
+			if rescheduled {
+				a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPosts[i], "")
+			} else {
+				a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPosts[i], "")
+				successfulScheduledPostIDs = append(successfulScheduledPostIDs, scheduledPosts[i].Id)
+			}
+			continue
+		}
+
+		scheduledPost, rescheduled, err := a.postScheduledPost(rctx, scheduledPosts[i])
+		if errors.Is(err, errRecurringScheduledPostNotMoved) {
+			continue
+		} else if err != nil {
 			rctx.Logger().Error("processScheduledPostBatch scheduled post processing failed", mlog.String("scheduled_post_id", scheduledPosts[i].Id), mlog.Err(err))
+			if failedOccurrence := a.skipFailedScheduledPostOccurrence(rctx, scheduledPost, rescheduled); failedOccurrence != nil {
+				failedOccurrences = append(failedOccurrences, failedOccurrence)
+				continue
+			}
 			failedScheduledPosts = append(failedScheduledPosts, scheduledPost)
 			continue
 		}
 
+		if rescheduled {
+			continue
+		}
+
 		successfulScheduledPostIDs = append(successfulScheduledPostIDs, scheduledPost.Id)
 	}
 
@@ -123,11 +158,67 @@ func (a *App) processScheduledPostBatch(rctx request.CTX, scheduledPosts []*mode
 	}
 
 	a.handleFailedScheduledPosts(rctx, failedScheduledPosts)
+	if len(failedOccurrences) > 0 {
+		a.notifyUserAboutFailedScheduledMessages(rctx, failedOccurrences)
+	}
 	return nil
 }
 
-// postScheduledPost processes an individual scheduled post
-func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
+// skipFailedScheduledPostOccurrence moves a recurring scheduled post whose
+// current occurrence failed to its next occurrence, without recording the
+// error on it, so that one failed occurrence doesn't end the series. It returns
+// the failed occurrence to notify the user about, or nil when the failure ends
+// the scheduled post and is to be recorded on it.
+func (a *App) skipFailedScheduledPostOccurrence(rctx request.CTX, scheduledPost *model.ScheduledPost, rescheduled bool) *model.ScheduledPost {
+	if !scheduledPost.IsRecurring() || scheduledPostErrorEndsSeries(scheduledPost.ErrorCode) {
+		return nil
+	}
+
+	errorCode := scheduledPost.ErrorCode
+	scheduledPost.ErrorCode = ""
+	if !rescheduled {
+		var err error
+		rescheduled, err = a.rescheduleRecurringScheduledPost(rctx, scheduledPost)
+		if errors.Is(err, errRecurringScheduledPostNotMoved) {
+			// The occurrence is tried again by the next run of the job
+			return nil
+		} else if !rescheduled {
+			scheduledPost.ErrorCode = errorCode
+			return nil
+		}
+	}
+
+	a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, "")
+
+	return &model.ScheduledPost{
+		Draft: model.Draft{
+			UserId:    scheduledPost.UserId,
+			ChannelId: scheduledPost.ChannelId,
+		},
+		Id:        scheduledPost.Id,
+		ErrorCode: errorCode,
+	}
+}
+
+// scheduledPostErrorEndsSeries reports whether an error can't go away before
+// the next occurrence of a recurring scheduled post, ending the series.
+func scheduledPostErrorEndsSeries(errorCode string) bool {
+	switch errorCode {
+	case model.ScheduledPostErrorCodeUserDoesNotExist,
+		model.ScheduledPostErrorCodeUserDeleted,
+		model.ScheduledPostErrorCodeChannelNotFound,
+		model.ScheduledPostErrorCodeChannelArchived,
+		model.ScheduledPostErrorThreadDeleted:
+		return true
+	}
+
+	return false
+}
+
+// postScheduledPost processes an individual scheduled post. It returns true when
+// the post recurs and was moved to its next occurrence, rather than to be deleted,
+// including when sending the current occurrence failed afterwards.
+func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost) (*model.ScheduledPost, bool, error) {
 	// we'll process scheduled posts one by one.
 	// If an error occurs, we'll log it and move onto the next scheduled post
 
@@ -137,7 +228,7 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 			rctx.Logger().Warn("channel for scheduled post not found, setting error code", mlog.String("scheduled_post_id", scheduledPost.Id), mlog.String("channel_id", scheduledPost.ChannelId), mlog.String("error_code", model.ScheduledPostErrorCodeChannelNotFound), mlog.Err(appErr))
 
 			scheduledPost.ErrorCode = model.ScheduledPostErrorCodeChannelNotFound
-			return scheduledPost, nil
+			return scheduledPost, false, nil
 		}
 
 		rctx.Logger().Error(
@@ -149,7 +240,7 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 		)
 
 		scheduledPost.ErrorCode = model.ScheduledPostErrorUnknownError
-		return scheduledPost, appErr
+		return scheduledPost, false, appErr
 	}
 
 	errorCode, err := a.canPostScheduledPost(rctx, scheduledPost, channel)
@@ -163,7 +254,7 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 			mlog.Err(err),
 		)
 
-		return scheduledPost, err
+		return scheduledPost, false, err
 	}
 
 	if scheduledPost.ErrorCode != "" {
@@ -175,7 +266,7 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 			mlog.String("error_code", scheduledPost.ErrorCode),
 		)
 
-		return scheduledPost, fmt.Errorf("App.processScheduledPostBatch: skipping posting a scheduled post as `can post` check failed, error_code: %s", scheduledPost.ErrorCode)
+		return scheduledPost, false, fmt.Errorf("App.processScheduledPostBatch: skipping posting a scheduled post as `can post` check failed, error_code: %s", scheduledPost.ErrorCode)
 	}
 
 	post, err := scheduledPost.ToPost()
@@ -188,7 +279,33 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 		)
 
 		scheduledPost.ErrorCode = model.ScheduledPostErrorUnknownError
-		return scheduledPost, err
+		return scheduledPost, false, err
+	}
+
+	// Every occurrence of a recurring post gets its own copies of the files,
+	// as a file can only be attached to one post. A post published as a bot
+	// gets copies too, as only the creator of a file can attach it.
+	if (scheduledPost.IsRecurring() || scheduledPost.PublishAsBotId != "") && len(post.FileIds) > 0 {
+		if post.FileIds, appErr = a.CopyFileInfos(rctx, post.UserId, post.FileIds); appErr != nil {
+			rctx.Logger().Error(
+				"App.processScheduledPostBatch: failed to copy the files of a scheduled post",
+				mlog.String("scheduled_post_id", scheduledPost.Id),
+				mlog.String("error_code", model.ScheduledPostErrorUnknownError),
+				mlog.Err(appErr),
+			)
+
+			scheduledPost.ErrorCode = model.ScheduledPostErrorUnknownError
+			return scheduledPost, false, appErr
+		}
+	}
+
+	// A recurring post is moved to its next occurrence before the current one
+	// is sent, so that failing to move it can't send the current one twice.
+	rescheduled := false
+	if scheduledPost.IsRecurring() {
+		if rescheduled, err = a.rescheduleRecurringScheduledPost(rctx, scheduledPost); err != nil {
+			return scheduledPost, false, err
+		}
 	}
 
 	createPostFlags := model.CreatePostFlags{
@@ -206,13 +323,41 @@ func (a *App) postScheduledPost(rctx request.CTX, scheduledPost *model.Scheduled
 		)
 
 		scheduledPost.ErrorCode = model.ScheduledPostErrorUnknownError
-		return scheduledPost, appErr
+		return scheduledPost, rescheduled, appErr
+	}
+
+	// send the WS event to delete the just posted scheduledPost from list,
+	// or to update it when it recurs and was moved to its next occurrence.
+	if rescheduled {
+		a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostUpdated, scheduledPost, "")
+	} else {
+		a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, "")
+	}
+
+	return scheduledPost, rescheduled, nil
+}
+
+// rescheduleRecurringScheduledPost moves a recurring scheduled post to its next
+// occurrence in the timezone of its user. It returns false when the series has
+// ended, in which case the scheduled post is left to be deleted, and
+// errRecurringScheduledPostNotMoved when it can't be moved.
+func (a *App) rescheduleRecurringScheduledPost(rctx request.CTX, scheduledPost *model.ScheduledPost) (bool, error) {
+	if !scheduledPost.Reschedule(model.GetMillis(), a.scheduledPostLocation(scheduledPost.UserId)) {
+		rctx.Logger().Debug("rescheduleRecurringScheduledPost recurring scheduled post has ended", mlog.String("scheduled_post_id", scheduledPost.Id))
+		return false, nil
 	}
 
-	// send the WS event to delete the just posted scheduledPost from list
-	a.PublishScheduledPostEvent(rctx, model.WebsocketScheduledPostDeleted, scheduledPost, "")
+	if err := a.Srv().Store().ScheduledPost().UpdatedScheduledPost(scheduledPost); err != nil {
+		rctx.Logger().Error(
+			"App.rescheduleRecurringScheduledPost: failed to move recurring scheduled post to its next occurrence",
+			mlog.String("scheduled_post_id", scheduledPost.Id),
+			mlog.Int("scheduled_at", scheduledPost.ScheduledAt),
+			mlog.Err(err),
+		)
+		return false, errRecurringScheduledPostNotMoved
+	}
 
-	return scheduledPost, nil
+	return true, nil
 }
 
 // canPostScheduledPost checks whether the scheduled post be created based on permissions and other checks.
@@ -305,6 +450,31 @@ func (a *App) canPostScheduledPost(rctx request.CTX, scheduledPost *model.Schedu
 		return model.ScheduledPostErrorInvalidPost, nil
 	}
 
+	if scheduledPost.PublishAsBotId != "" {
+		if appErr := a.checkScheduledPostBot(rctx, scheduledPost.ChannelId, scheduledPost.PublishAsBotId); appErr != nil {
+			if appErr.StatusCode == http.StatusInternalServerError {
+				rctx.Logger().Error(
+					"App.canPostScheduledPost: failed to check the bot the scheduled post is published as",
+					mlog.String("scheduled_post_id", scheduledPost.Id),
+					mlog.String("bot_user_id", scheduledPost.PublishAsBotId),
+					mlog.String("error_code", model.ScheduledPostErrorUnknownError),
+					mlog.Err(appErr),
+				)
+				return model.ScheduledPostErrorUnknownError, errors.Wrapf(appErr, "App.canPostScheduledPost: failed to check the bot, scheduled_post_id: %s, bot_user_id: %s", scheduledPost.Id, scheduledPost.PublishAsBotId)
+			}
+
+			rctx.Logger().Debug(
+				"canPostScheduledPost bot the scheduled post is published as is unavailable",
+				mlog.String("scheduled_post_id", scheduledPost.Id),
+				mlog.String("bot_user_id", scheduledPost.PublishAsBotId),
+				mlog.String("channel_id", scheduledPost.ChannelId),
+				mlog.String("error_code", model.ScheduledPostErrorCodeBotUnavailable),
+				mlog.Err(appErr),
+			)
+			return model.ScheduledPostErrorCodeBotUnavailable, nil
+		}
+	}
+
 	return "", nil
 }
 
@@ -482,6 +652,8 @@ func getErrorReason(T i18n.TranslateFunc, errorCode string) string {
 		reason = T("app.scheduled_post.error_reason.unable_to_send")
 	case "invalid_post":
 		reason = T("app.scheduled_post.error_reason.invalid_post")
+	case "bot_unavailable":
+		reason = T("app.scheduled_post.error_reason.bot_unavailable")
 	default:
 		reason = errorCode
 	}
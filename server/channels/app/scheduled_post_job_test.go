@@ -11,6 +11,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProcessScheduledPosts(t *testing.T) {
@@ -264,6 +265,256 @@ func TestProcessScheduledPosts(t *testing.T) {
 		assert.Equal(t, model.ScheduledPostErrorCodeNoChannelPermission, scheduledPosts[1].ErrorCode)
 		assert.Greater(t, scheduledPosts[1].ProcessedAt, int64(0))
 	})
+
+	t.Run("moves recurring scheduled posts to their next occurrence", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		th.App.Srv().SetLicense(getLicWithSkuShortName(model.LicenseShortSkuProfessional))
+
+		scheduledAt := model.GetMillis() + 1000
+		scheduledPost, err := th.Server.Store().ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is a recurring scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			RecurrenceRule: "FREQ=DAILY;COUNT=2",
+		})
+		assert.NoError(t, err)
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		posts, appErr := th.App.GetPosts(th.BasicChannel.Id, 0, 1)
+		assert.Nil(t, appErr)
+		assert.Equal(t, scheduledPost.Message, posts.Posts[posts.Order[0]].Message)
+
+		rescheduledPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, scheduledAt+(24*time.Hour).Milliseconds(), rescheduledPost.ScheduledAt)
+		assert.Equal(t, 1, rescheduledPost.OccurrenceCount)
+		assert.Empty(t, rescheduledPost.ErrorCode)
+
+		// The second and last occurrence ends the series
+		rescheduledPost.ScheduledAt = model.GetMillis() + 1000
+		assert.NoError(t, th.Server.Store().ScheduledPost().UpdatedScheduledPost(rescheduledPost))
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		scheduledPosts, err := th.App.Srv().Store().ScheduledPost().GetScheduledPostsForUser(th.BasicUser.Id, th.BasicChannel.TeamId)
+		assert.NoError(t, err)
+		assert.Len(t, scheduledPosts, 0)
+	})
+
+	t.Run("goes on with a recurring scheduled post whose occurrence fails", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		th.App.Srv().SetLicense(getLicWithSkuShortName(model.LicenseShortSkuProfessional))
+
+		// A reply to a post of another channel passes the checks but fails to be created
+		rootPost := th.CreatePost(th.CreateChannel(th.Context, th.BasicTeam))
+
+		scheduledAt := model.GetMillis() + 1000
+		scheduledPost, err := th.Server.Store().ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				RootId:    rootPost.Id,
+				Message:   "this is a recurring scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			RecurrenceRule: "FREQ=DAILY",
+		})
+		require.NoError(t, err)
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		rescheduledPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Empty(t, rescheduledPost.ErrorCode)
+		assert.Equal(t, scheduledAt+(24*time.Hour).Milliseconds(), rescheduledPost.ScheduledAt)
+		assert.Equal(t, 1, rescheduledPost.OccurrenceCount)
+
+		// The next occurrence is sent once the post can be created
+		rootPost.ChannelId = th.BasicChannel.Id
+		_, err = th.Server.Store().Post().Overwrite(th.Context, rootPost)
+		require.NoError(t, err)
+
+		rescheduledPost.ScheduledAt = model.GetMillis() + 1000
+		require.NoError(t, th.Server.Store().ScheduledPost().UpdatedScheduledPost(rescheduledPost))
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		posts, appErr := th.App.GetPosts(th.BasicChannel.Id, 0, 1)
+		require.Nil(t, appErr)
+		post := posts.Posts[posts.Order[0]]
+		assert.Equal(t, scheduledPost.Message, post.Message)
+		assert.Equal(t, rootPost.Id, post.RootId)
+
+		rescheduledPost, err = th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Empty(t, rescheduledPost.ErrorCode)
+		assert.Equal(t, 2, rescheduledPost.OccurrenceCount)
+	})
+
+	t.Run("attaches copies of the files to every occurrence of recurring scheduled posts", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		th.App.Srv().SetLicense(getLicWithSkuShortName(model.LicenseShortSkuProfessional))
+
+		fileInfo := th.CreateFileInfo(th.BasicUser.Id, "", "")
+		scheduledPost, err := th.Server.Store().ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is a recurring scheduled post with a file",
+				FileIds:   model.StringArray{fileInfo.Id},
+			},
+			ScheduledAt:    model.GetMillis() + 1000,
+			RecurrenceRule: "FREQ=DAILY",
+		})
+		assert.NoError(t, err)
+
+		var postIDs []string
+		for range 2 {
+			time.Sleep(1 * time.Second)
+
+			th.App.ProcessScheduledPosts(th.Context)
+
+			posts, appErr := th.App.GetPosts(th.BasicChannel.Id, 0, 1)
+			assert.Nil(t, appErr)
+			post := posts.Posts[posts.Order[0]]
+			assert.Equal(t, scheduledPost.Message, post.Message)
+			require.Len(t, post.FileIds, 1)
+			assert.NotEqual(t, fileInfo.Id, post.FileIds[0])
+			postIDs = append(postIDs, post.Id)
+
+			copiedFileInfo, err := th.App.Srv().Store().FileInfo().Get(post.FileIds[0])
+			assert.NoError(t, err)
+			assert.Equal(t, post.Id, copiedFileInfo.PostId)
+			assert.Equal(t, fileInfo.Path, copiedFileInfo.Path)
+
+			rescheduledPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+			assert.NoError(t, err)
+			rescheduledPost.ScheduledAt = model.GetMillis() + 1000
+			assert.NoError(t, th.Server.Store().ScheduledPost().UpdatedScheduledPost(rescheduledPost))
+		}
+		assert.NotEqual(t, postIDs[0], postIDs[1])
+
+		// The original stays unattached for the next occurrences
+		originalFileInfo, err := th.App.Srv().Store().FileInfo().Get(fileInfo.Id)
+		assert.NoError(t, err)
+		assert.Empty(t, originalFileInfo.PostId)
+	})
+
+	t.Run("skips missed occurrences of recurring scheduled posts", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		th.App.Srv().SetLicense(getLicWithSkuShortName(model.LicenseShortSkuProfessional))
+
+		day := (24 * time.Hour).Milliseconds()
+		scheduledAt := model.GetMillis() - 2*day - 1000
+		scheduledPost, err := th.Server.Store().ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is a missed recurring scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			RecurrenceRule: "FREQ=DAILY",
+		})
+		assert.NoError(t, err)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		posts, appErr := th.App.GetPosts(th.BasicChannel.Id, 0, 1)
+		assert.Nil(t, appErr)
+		assert.NotEqual(t, scheduledPost.Message, posts.Posts[posts.Order[0]].Message)
+
+		rescheduledPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, scheduledAt+3*day, rescheduledPost.ScheduledAt)
+		assert.Equal(t, 3, rescheduledPost.OccurrenceCount)
+	})
+
+	t.Run("publishes as the bot", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		th.App.Srv().SetLicense(getLicWithSkuShortName(model.LicenseShortSkuProfessional))
+
+		bot := th.CreateBot()
+		botUser, appErr := th.App.GetUser(bot.UserId)
+		assert.Nil(t, appErr)
+		th.AddUserToChannel(botUser, th.BasicChannel)
+
+		newScheduledPost := func(message string, fileIds ...string) *model.ScheduledPost {
+			scheduledPost, err := th.Server.Store().ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+				Draft: model.Draft{
+					CreateAt:  model.GetMillis(),
+					UserId:    th.BasicUser.Id,
+					ChannelId: th.BasicChannel.Id,
+					Message:   message,
+					FileIds:   fileIds,
+				},
+				ScheduledAt:    model.GetMillis() + 1000,
+				PublishAsBotId: bot.UserId,
+			})
+			assert.NoError(t, err)
+			return scheduledPost
+		}
+
+		// The files of the author are attached as copies the bot creates
+		fileInfo := th.CreateFileInfo(th.BasicUser.Id, "", "")
+		newScheduledPost("this is an announcement", fileInfo.Id)
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		posts, appErr := th.App.GetPosts(th.BasicChannel.Id, 0, 1)
+		assert.Nil(t, appErr)
+		post := posts.Posts[posts.Order[0]]
+		assert.Equal(t, "this is an announcement", post.Message)
+		assert.Equal(t, bot.UserId, post.UserId)
+		assert.Equal(t, "true", post.GetProp(model.PostPropsFromBot))
+		require.Len(t, post.FileIds, 1)
+
+		copiedFileInfo, err := th.App.Srv().Store().FileInfo().Get(post.FileIds[0])
+		assert.NoError(t, err)
+		assert.Equal(t, post.Id, copiedFileInfo.PostId)
+		assert.Equal(t, bot.UserId, copiedFileInfo.CreatorId)
+
+		// A bot that is no longer a member of the channel fails the post
+		appErr = th.App.RemoveUserFromChannel(th.Context, bot.UserId, th.BasicUser.Id, th.BasicChannel)
+		assert.Nil(t, appErr)
+
+		scheduledPost := newScheduledPost("this is another announcement")
+
+		time.Sleep(1 * time.Second)
+
+		th.App.ProcessScheduledPosts(th.Context)
+
+		failedPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorCodeBotUnavailable, failedPost.ErrorCode)
+	})
 }
 
 func TestHandleFailedScheduledPosts(t *testing.T) {
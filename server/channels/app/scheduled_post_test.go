@@ -759,6 +759,238 @@ func TestDeleteScheduledPost(t *testing.T) {
 	})
 }
 
+func TestRecurringScheduledPostActions(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	day := (24 * time.Hour).Milliseconds()
+	scheduledAt := model.GetMillis() + 100000 // 100 seconds in the future
+	scheduledPost, appErr := th.App.SaveScheduledPost(th.Context, &model.ScheduledPost{
+		Draft: model.Draft{
+			CreateAt:  model.GetMillis(),
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   "this is a recurring scheduled post",
+		},
+		ScheduledAt:    scheduledAt,
+		RecurrenceRule: "FREQ=DAILY;COUNT=3",
+	}, "connection_id")
+	require.Nil(t, appErr)
+
+	t.Run("should skip the next occurrence", func(t *testing.T) {
+		skippedPost, appErr := th.App.SkipScheduledPostOccurrence(th.Context, th.BasicUser.Id, scheduledPost.Id, "connection_id")
+		require.Nil(t, appErr)
+		require.Equal(t, scheduledAt+day, skippedPost.ScheduledAt)
+		require.Equal(t, 1, skippedPost.OccurrenceCount)
+	})
+
+	t.Run("should pause and resume", func(t *testing.T) {
+		pausedPost, appErr := th.App.PauseScheduledPost(th.Context, th.BasicUser.Id, scheduledPost.Id, "connection_id")
+		require.Nil(t, appErr)
+		require.NotZero(t, pausedPost.PausedAt)
+
+		fetchedScheduledPost, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		require.Equal(t, pausedPost.PausedAt, fetchedScheduledPost.PausedAt)
+
+		resumedPost, appErr := th.App.ResumeScheduledPost(th.Context, th.BasicUser.Id, scheduledPost.Id, "connection_id")
+		require.Nil(t, appErr)
+		require.Zero(t, resumedPost.PausedAt)
+		require.Equal(t, scheduledAt+day, resumedPost.ScheduledAt)
+	})
+
+	t.Run("should not allow changing someone else's scheduled post", func(t *testing.T) {
+		_, appErr := th.App.PauseScheduledPost(th.Context, th.BasicUser2.Id, scheduledPost.Id, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("should delete the scheduled post when skipping its last occurrence", func(t *testing.T) {
+		skippedPost, appErr := th.App.SkipScheduledPostOccurrence(th.Context, th.BasicUser.Id, scheduledPost.Id, "connection_id")
+		require.Nil(t, appErr)
+		require.Equal(t, scheduledAt+2*day, skippedPost.ScheduledAt)
+
+		_, appErr = th.App.SkipScheduledPostOccurrence(th.Context, th.BasicUser.Id, scheduledPost.Id, "connection_id")
+		require.Nil(t, appErr)
+
+		_, err := th.Server.Store().ScheduledPost().Get(scheduledPost.Id)
+		require.Error(t, err)
+	})
+
+	t.Run("should not pause a scheduled post that does not recur", func(t *testing.T) {
+		oneOffPost, appErr := th.App.SaveScheduledPost(th.Context, &model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is a scheduled post",
+			},
+			ScheduledAt: scheduledAt,
+		}, "connection_id")
+		require.Nil(t, appErr)
+
+		_, appErr = th.App.PauseScheduledPost(th.Context, th.BasicUser.Id, oneOffPost.Id, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.not_recurring.app_error", appErr.Id)
+	})
+
+	t.Run("should reject an invalid recurrence rule", func(t *testing.T) {
+		_, appErr := th.App.SaveScheduledPost(th.Context, &model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "this is a scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			RecurrenceRule: "FREQ=HOURLY",
+		}, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.scheduled_post.is_valid.recurrence_rule.app_error", appErr.Id)
+	})
+}
+
+func TestChannelScheduledPosts(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.AddUserToChannel(th.BasicUser2, th.BasicChannel)
+
+	newScheduledPost := func(userId, channelId string) *model.ScheduledPost {
+		scheduledPost, appErr := th.App.SaveScheduledPost(th.Context, &model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    userId,
+				ChannelId: channelId,
+				Message:   "this is an announcement",
+			},
+			ScheduledAt: model.GetMillis() + 100000, // 100 seconds in the future
+		}, "connection_id")
+		require.Nil(t, appErr)
+		return scheduledPost
+	}
+
+	userPost := newScheduledPost(th.BasicUser.Id, th.BasicChannel.Id)
+	user2Post := newScheduledPost(th.BasicUser2.Id, th.BasicChannel.Id)
+	otherChannel := th.CreateChannel(th.Context, th.BasicTeam)
+	otherChannelPost := newScheduledPost(th.BasicUser.Id, otherChannel.Id)
+
+	bot := th.CreateBot()
+	botUser, appErr := th.App.GetUser(bot.UserId)
+	require.Nil(t, appErr)
+
+	t.Run("should get the scheduled posts of every user in the channel", func(t *testing.T) {
+		scheduledPosts, appErr := th.App.GetChannelScheduledPosts(th.Context, th.BasicChannel.Id)
+		require.Nil(t, appErr)
+		require.Len(t, scheduledPosts, 2)
+		require.ElementsMatch(t, []string{userPost.Id, user2Post.Id}, []string{scheduledPosts[0].Id, scheduledPosts[1].Id})
+	})
+
+	t.Run("should not edit the content of another user's scheduled post published as them", func(t *testing.T) {
+		user2Post.Message = "this is an edited announcement"
+		_, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.channel_scheduled_post.edit_content.app_error", appErr.Id)
+		user2Post.Message = "this is an announcement"
+	})
+
+	t.Run("should update the time of the scheduled post of another user", func(t *testing.T) {
+		user2Post.ScheduledAt = model.GetMillis() + 200000
+		updatedPost, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.Nil(t, appErr)
+		require.Equal(t, th.BasicUser2.Id, updatedPost.UserId)
+
+		fetchedScheduledPost, err := th.Server.Store().ScheduledPost().Get(user2Post.Id)
+		require.NoError(t, err)
+		require.Equal(t, user2Post.ScheduledAt, fetchedScheduledPost.ScheduledAt)
+		require.Equal(t, th.BasicUser.Id, fetchedScheduledPost.LastEditorId)
+	})
+
+	t.Run("should only publish as a bot that is a member of the channel", func(t *testing.T) {
+		user2Post.PublishAsBotId = bot.UserId
+		_, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.publish_as_bot.not_member.app_error", appErr.Id)
+
+		th.AddUserToChannel(botUser, th.BasicChannel)
+
+		user2Post.PublishAsBotId = th.BasicUser.Id
+		_, appErr = th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.publish_as_bot.not_found.app_error", appErr.Id)
+		user2Post.PublishAsBotId = bot.UserId
+	})
+
+	t.Run("should only publish as a bot the editor manages", func(t *testing.T) {
+		_, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.publish_as_bot.permission.app_error", appErr.Id)
+
+		th.AddPermissionToRole(model.PermissionManageBots.Id, model.SystemUserRoleId)
+		defer th.RemovePermissionFromRole(model.PermissionManageBots.Id, model.SystemUserRoleId)
+
+		// The bot of another user takes the permission to manage the bots of others
+		_, appErr = th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser2.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.publish_as_bot.permission.app_error", appErr.Id)
+
+		user2Post.Message = "this is an edited announcement"
+		updatedPost, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.Nil(t, appErr)
+		require.Equal(t, bot.UserId, updatedPost.PublishAsBotId)
+		require.Equal(t, "this is an edited announcement", updatedPost.Message)
+		require.Equal(t, th.BasicUser.Id, updatedPost.LastEditorId)
+	})
+
+	t.Run("should not publish the content of the editor as the author", func(t *testing.T) {
+		user2Post.PublishAsBotId = ""
+		_, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.channel_scheduled_post.edit_content.app_error", appErr.Id)
+	})
+
+	t.Run("should keep the bot when the author updates the scheduled post", func(t *testing.T) {
+		user2Post.PublishAsBotId = ""
+		updatedPost, appErr := th.App.UpdateScheduledPost(th.Context, th.BasicUser2.Id, user2Post, "connection_id")
+		require.Nil(t, appErr)
+		require.Equal(t, bot.UserId, updatedPost.PublishAsBotId)
+	})
+
+	t.Run("should not let the author edit the content published as a bot they don't manage", func(t *testing.T) {
+		user2Post.Message = "this is the announcement of the author"
+		_, appErr := th.App.UpdateScheduledPost(th.Context, th.BasicUser2.Id, user2Post, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.scheduled_post.publish_as_bot.permission.app_error", appErr.Id)
+
+		fetchedScheduledPost, err := th.Server.Store().ScheduledPost().Get(user2Post.Id)
+		require.NoError(t, err)
+		require.Equal(t, "this is an edited announcement", fetchedScheduledPost.Message)
+		user2Post.Message = fetchedScheduledPost.Message
+	})
+
+	t.Run("should not manage the scheduled posts of another channel", func(t *testing.T) {
+		_, appErr := th.App.UpdateChannelScheduledPost(th.Context, th.BasicUser.Id, th.BasicChannel.Id, otherChannelPost, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		_, appErr = th.App.DeleteChannelScheduledPost(th.Context, th.BasicChannel.Id, otherChannelPost.Id, "connection_id")
+		require.NotNil(t, appErr)
+		require.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("should cancel the scheduled post of another user", func(t *testing.T) {
+		_, appErr := th.App.DeleteChannelScheduledPost(th.Context, th.BasicChannel.Id, userPost.Id, "connection_id")
+		require.Nil(t, appErr)
+
+		scheduledPosts, appErr := th.App.GetChannelScheduledPosts(th.Context, th.BasicChannel.Id)
+		require.Nil(t, appErr)
+		require.Len(t, scheduledPosts, 1)
+		require.Equal(t, user2Post.Id, scheduledPosts[0].Id)
+	})
+}
+
 func TestPublishScheduledPostEvent(t *testing.T) {
 	mainHelper.Parallel(t)
 	th := Setup(t).InitBasic()
@@ -9,6 +9,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
 )
 
 func (a *App) TestElasticsearch(rctx request.CTX, cfg *model.Config) *model.AppError {
@@ -53,16 +54,29 @@ func (a *App) PurgeElasticsearchIndexes(c request.CTX, indexes []string) *model.
 	return appErr
 }
 
-func (a *App) PurgeBleveIndexes(c request.CTX) *model.AppError {
+func (a *App) PurgeBleveIndexes(c request.CTX, indexes []string) *model.AppError {
 	engine := a.SearchEngine().BleveEngine
 	if engine == nil {
 		err := model.NewAppError("PurgeBleveIndexes", "searchengine.bleve.disabled.error", nil, "", http.StatusNotImplemented)
 		return err
 	}
-	if err := engine.PurgeIndexes(c); err != nil {
-		return err
+
+	var appErr *model.AppError
+	if len(indexes) > 0 {
+		appErr = engine.PurgeIndexList(c, indexes)
+	} else {
+		appErr = engine.PurgeIndexes(c)
+	}
+
+	return appErr
+}
+
+// bleveClusterLeaderChanged moves the ownership of the shared Bleve indexes to
+// the new leader.
+func (s *Server) bleveClusterLeaderChanged() {
+	if engine, ok := s.platform.SearchEngine.BleveEngine.(*bleveengine.BleveEngine); ok {
+		engine.ClusterLeaderChanged()
 	}
-	return nil
 }
 
 func (a *App) ActiveSearchBackend() string {
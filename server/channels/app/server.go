@@ -10,12 +10,15 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -41,6 +44,8 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/active_users"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/cleanup_desktop_tokens"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/cleanup_expired_login_bans"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/cleanup_expired_whitelist"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_dms_preferences_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_empty_drafts_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_orphan_drafts_migration"
@@ -72,6 +77,8 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/services/remotecluster"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine/indexer"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine/retention"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine/snapshot"
 	"github.com/mattermost/mattermost/server/v8/platform/services/sharedchannel"
 	"github.com/mattermost/mattermost/server/v8/platform/services/telemetry"
 	"github.com/mattermost/mattermost/server/v8/platform/services/upgrader"
@@ -127,9 +134,19 @@ type Server struct {
 	htmlTemplateWatcher     *templates.Container
 	seenPendingPostIdsCache cache.Cache
 	openGraphDataCache      cache.Cache
+	// loginFailures counts the recent failed logins of the addresses and subnets,
+	// across the cluster with Redis and by each node otherwise.
+	loginFailures           cache.CounterStore
+	whitelistDenialCache    cache.Cache
+	savedSearchAlerts       savedSearchAlertCache
 	clusterLeaderListenerId string
 	loggerLicenseListenerId string
 
+	// whitelistExcludedPaths holds the compiled WhitelistSettings.ExcludedPaths.
+	whitelistExcludedPaths atomic.Pointer[[]*regexp.Regexp]
+	// trustedProxies holds the parsed ServiceSettings.TrustedProxyCIDRs.
+	trustedProxies atomic.Pointer[[]netip.Prefix]
+
 	platform         *platform.PlatformService
 	platformOptions  []platform.Option
 	telemetryService *telemetry.TelemetryService
@@ -323,6 +340,13 @@ func NewServer(options ...Option) (*Server, error) {
 	}); err != nil {
 		return nil, errors.Wrap(err, "Unable to create opengraphdata cache")
 	}
+	s.loginFailures = cache.NewCounterStore(s.platform.CacheProvider(), "login_throttle", loginThrottleCacheSize)
+	if s.whitelistDenialCache, err = s.platform.CacheProvider().NewCache(&cache.CacheOptions{
+		Name: "whitelist_denials",
+		Size: whitelistDenialCacheSize,
+	}); err != nil {
+		return nil, errors.Wrap(err, "Unable to create whitelist denial cache")
+	}
 
 	s.createPushNotificationsHub(request.EmptyContext(s.Log()))
 
@@ -381,6 +405,7 @@ func NewServer(options ...Option) (*Server, error) {
 		if s.Jobs != nil {
 			s.Jobs.HandleClusterLeaderChange(s.IsLeader())
 		}
+		s.bleveClusterLeaderChanged()
 		s.platform.SetupFeatureFlags()
 	})
 
@@ -459,6 +484,9 @@ func NewServer(options ...Option) (*Server, error) {
 		})
 	}
 
+	s.initWhitelistSettings()
+	s.initTrustedProxies()
+
 	if s.skipPostInit {
 		return s, nil
 	}
@@ -927,11 +955,21 @@ func (s *Server) Start() error {
 	if *s.platform.Config().RateLimitSettings.Enable {
 		mlog.Info("RateLimiter is enabled")
 
-		rateLimiter, err2 := NewRateLimiter(&s.platform.Config().RateLimitSettings, s.platform.Config().ServiceSettings.TrustedProxyIPHeader)
+		serviceSettings := s.platform.Config().ServiceSettings
+		trustedProxies := utils.ParseTrustedProxies(serviceSettings.TrustedProxyCIDRs)
+		var rateLimiter *RateLimiter
+		var err2 error
+		// With Redis, the limits are shared by all the nodes of the cluster.
+		if store := cache.NewGCRAStore(s.platform.CacheProvider(), "api"); store != nil {
+			rateLimiter, err2 = NewRateLimiterWithStore(&s.platform.Config().RateLimitSettings, serviceSettings.TrustedProxyIPHeader, trustedProxies, store)
+		} else {
+			rateLimiter, err2 = NewRateLimiter(&s.platform.Config().RateLimitSettings, serviceSettings.TrustedProxyIPHeader, trustedProxies)
+		}
 		if err2 != nil {
 			return err2
 		}
 
+		rateLimiter.metrics = s.GetMetrics()
 		s.RateLimiter = rateLimiter
 		handler = rateLimiter.RateLimitHandler(handler)
 	}
@@ -1510,6 +1548,18 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeBleveDataRetention,
+		retention.MakeWorker(s.Jobs, s.platform.SearchEngine.BleveEngine.(*bleveengine.BleveEngine)),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeBleveSnapshot,
+		snapshot.MakeWorker(s.Jobs, s.platform.SearchEngine.BleveEngine.(*bleveengine.BleveEngine), New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeMigrations,
 		migrations.MakeWorker(s.Jobs, s.Store()),
@@ -1645,6 +1695,18 @@ func (s *Server) initJobs() {
 		cleanup_desktop_tokens.MakeScheduler(s.Jobs),
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeCleanupExpiredWhitelist,
+		cleanup_expired_whitelist.MakeWorker(s.Jobs),
+		cleanup_expired_whitelist.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeCleanupExpiredLoginBans,
+		cleanup_expired_login_bans.MakeWorker(s.Jobs),
+		cleanup_expired_login_bans.MakeScheduler(s.Jobs),
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeRefreshMaterializedViews,
 		refresh_materialized_views.MakeWorker(s.Jobs, *s.platform.Config().SqlSettings.DriverName),
@@ -4,190 +4,489 @@
 package app
 
 import (
-	"net"
+	"encoding/csv"
+	"errors"
+	"io"
 	"net/http"
-	"strings"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
 )
 
-// AddUserToWhitelist adds an IP address to a user's whitelist
-func (a *App) AddUserToWhitelist(c request.CTX, userId, ipAddress string) *model.AppError {
+const (
+	whitelistDenialExportPageSize = 1000
+	whitelistDenialCacheSize      = 50000
+
+	// whitelistDenialCoalesceWindow is how long after a denial is recorded that
+	// repeated denials of the same user, address and rule are counted on it.
+	whitelistDenialCoalesceWindow = 5 * time.Minute
+)
+
+// AddUserToWhitelist adds an IP address or CIDR range to a user's whitelist
+func (a *App) AddUserToWhitelist(c request.CTX, whitelistItem *model.WhitelistItem) (*model.WhitelistItem, *model.AppError) {
 	// Validate user exists
-	if _, err := a.GetUser(userId); err != nil {
-		return err
+	if _, err := a.GetUser(whitelistItem.UserId); err != nil {
+		return nil, err
 	}
 
-	// Validate IP address format
-	if !isValidIPAddress(ipAddress) {
-		return model.NewAppError("AddUserToWhitelist", "app.whitelist.invalid_ip.app_error", nil, "ip="+ipAddress, http.StatusBadRequest)
+	// Validate IP address or range format
+	if _, err := model.ParseWhitelistPrefix(whitelistItem.IP); err != nil {
+		return nil, model.NewAppError("AddUserToWhitelist", "app.whitelist.invalid_ip.app_error", nil, "ip="+whitelistItem.IP, http.StatusBadRequest).Wrap(err)
 	}
 
-	whitelistItem := &model.WhitelistItem{
-		UserId: userId,
-		IP:     ipAddress,
+	whitelistItem.PreSave()
+	if appErr := whitelistItem.IsValid(); appErr != nil {
+		return nil, appErr
 	}
 
 	if err := a.Srv().Store().Whitelist().Add(whitelistItem); err != nil {
-		return model.NewAppError("AddUserToWhitelist", "app.whitelist.add.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return nil, model.NewAppError("AddUserToWhitelist", "app.whitelist.add.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	c.Logger().Info("Added IP to user whitelist", mlog.String("user_id", userId), mlog.String("ip", ipAddress))
-	return nil
+	c.Logger().Info("Added IP to user whitelist",
+		mlog.String("user_id", whitelistItem.UserId),
+		mlog.String("ip", whitelistItem.IP),
+		mlog.String("creator_id", whitelistItem.CreatorId),
+		mlog.Int("expires_at", whitelistItem.ExpiresAt))
+	return whitelistItem, nil
 }
 
-// RemoveUserFromWhitelist removes an IP address from a user's whitelist
-func (a *App) RemoveUserFromWhitelist(c request.CTX, userId, ipAddress string) *model.AppError {
-	whitelistItem := &model.WhitelistItem{
-		UserId: userId,
-		IP:     ipAddress,
+// RemoveUserFromWhitelist removes an IP address or CIDR range from a user's whitelist.
+// Websocket connections of the user that are no longer whitelisted are closed across
+// the cluster, and their sessions are revoked as well if revokeSessions is true.
+func (a *App) RemoveUserFromWhitelist(c request.CTX, userId, ipAddress string, revokeSessions bool) *model.AppError {
+	ips := []string{ipAddress}
+	// Entries are stored in canonical form, so also remove the normalized spelling of the input
+	if normalized, err := model.NormalizeWhitelistIP(ipAddress); err == nil && normalized != ipAddress {
+		ips = append(ips, normalized)
 	}
 
-	if err := a.Srv().Store().Whitelist().Delete(whitelistItem); err != nil {
-		return model.NewAppError("RemoveUserFromWhitelist", "app.whitelist.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	for _, ip := range ips {
+		whitelistItem := &model.WhitelistItem{
+			UserId: userId,
+			IP:     ip,
+		}
+
+		if err := a.Srv().Store().Whitelist().Delete(whitelistItem); err != nil {
+			return model.NewAppError("RemoveUserFromWhitelist", "app.whitelist.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
 	}
 
-	c.Logger().Info("Removed IP from user whitelist", mlog.String("user_id", userId), mlog.String("ip", ipAddress))
+	c.Logger().Info("Removed IP from user whitelist", mlog.String("user_id", userId), mlog.String("ip", ipAddress), mlog.Bool("revoke_sessions", revokeSessions))
+
+	a.Srv().Platform().CloseWebConnsNotWhitelisted(userId, revokeSessions)
 	return nil
 }
 
-// GetUserWhitelistIPs gets all whitelisted IP addresses for a user
+// GetUserWhitelist gets all whitelist entries for a user that have not expired yet
+func (a *App) GetUserWhitelist(userId string) ([]*model.WhitelistItem, *model.AppError) {
+	items, err := a.Srv().Store().Whitelist().GetByUserId(userId)
+	if err != nil {
+		return nil, model.NewAppError("GetUserWhitelist", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	now := model.GetMillis()
+	active := make([]*model.WhitelistItem, 0, len(items))
+	for _, item := range items {
+		// Expired entries are purged by a job, but may linger until it runs
+		if item.IsExpired(now) {
+			continue
+		}
+		active = append(active, item)
+	}
+
+	return active, nil
+}
+
+// GetUserWhitelistIPs gets all whitelisted IP addresses and ranges for a user
 func (a *App) GetUserWhitelistIPs(userId string) ([]string, *model.AppError) {
-	ips, err := a.Srv().Store().Whitelist().GetByUserId(userId)
+	items, err := a.GetUserWhitelist(userId)
 	if err != nil {
-		return nil, model.NewAppError("GetUserWhitelistIPs", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(items))
+	for _, item := range items {
+		ips = append(ips, item.IP)
 	}
 
 	return ips, nil
 }
 
-// CheckUserIPWhitelisted checks if a user's IP is whitelisted
-func (a *App) CheckUserIPWhitelisted(c request.CTX, userId string, ipAddresses []string) (bool, *model.AppError) {
-	if userId == "" {
-		return true, nil
+// GetAllWhitelistItems gets a page of the whitelist entries of every user, ordered by
+// user and IP and starting after the given user and IP.
+func (a *App) GetAllWhitelistItems(afterUserId, afterIP string, perPage int) ([]*model.WhitelistItemForExport, *model.AppError) {
+	items, err := a.Srv().Store().Whitelist().GetAllAfter(perPage, afterUserId, afterIP)
+	if err != nil {
+		return nil, model.NewAppError("GetAllWhitelistItems", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return items, nil
+}
+
+// getUserWhitelistPrefixes gets the prefixes of the user's active whitelist entries. The
+// compiled set is cached by the store, so checking it does not hit the database on every request.
+// Entries that cannot be parsed are skipped and logged rather than failing the check.
+func (a *App) getUserWhitelistPrefixes(c request.CTX, userId string) ([]netip.Prefix, *model.AppError) {
+	prefixSet, err := a.Srv().Store().Whitelist().GetPrefixSetByUserId(userId)
+	if err != nil {
+		return nil, model.NewAppError("getUserWhitelistPrefixes", "app.whitelist.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for _, ip := range prefixSet.Invalid {
+		c.Logger().Warn("Skipping invalid IP whitelist entry", mlog.String("user_id", userId), mlog.String("ip", ip))
+	}
+
+	return prefixSet.Active(model.GetMillis()), nil
+}
+
+// CheckUserIPWhitelisted checks if a user's IP is whitelisted. The address
+// should be resolved with GetClientIPAddress so that spoofed proxy headers are ignored.
+func (a *App) CheckUserIPWhitelisted(c request.CTX, userId string, ipAddress string) (bool, *model.AppError) {
+	decision, appErr := a.EvaluateUserIPWhitelist(c, userId, ipAddress)
+	if appErr != nil {
+		return false, appErr
+	}
+	return decision.Allowed, nil
+}
+
+// EvaluateUserIPWhitelist checks the user's IP against the whitelist and reports
+// which rule decided the outcome. Which users are exempt is controlled by WhitelistSettings.
+func (a *App) EvaluateUserIPWhitelist(c request.CTX, userId string, ipAddress string) (*model.WhitelistDecision, *model.AppError) {
+	settings := a.Config().WhitelistSettings
+	if userId == "" || !*settings.Enable {
+		return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleDisabled}, nil
 	}
 
 	user, err := a.GetUser(userId)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// System admins bypass IP whitelist
-	if a.RolesGrantPermission(user.GetRoles(), model.PermissionManageSystem.Id) {
-		c.Logger().Debug("System admin bypassing IP whitelist", mlog.String("user_id", userId))
-		return true, nil
+	if user.IsBot {
+		switch *settings.BotAccess {
+		case model.WhitelistAccessBypass:
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleBotBypass}, nil
+		case model.WhitelistAccessDeny:
+			return &model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleBotDenied}, nil
+		}
+	}
+
+	if session := c.Session(); session != nil && session.UserId == userId {
+		if session.IsOAuth {
+			switch *settings.OAuthAppAccess {
+			case model.WhitelistAccessBypass:
+				return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleOAuthAppBypass}, nil
+			case model.WhitelistAccessDeny:
+				return &model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleOAuthAppDenied}, nil
+			}
+		}
+
+		if session.IsUserAccessToken() {
+			switch *settings.PersonalAccessTokenAccess {
+			case model.WhitelistAccessBypass:
+				return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleAccessTokenBypass}, nil
+			case model.WhitelistAccessDeny:
+				return &model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleAccessTokenDenied}, nil
+			}
+		}
 	}
 
-	// Team admins bypass IP whitelist
 	teamMembers, teamErr := a.GetTeamMembersForUser(c, userId, "", false)
 	if teamErr != nil {
 		c.Logger().Warn("Failed to get team members for IP whitelist check", mlog.String("user_id", userId), mlog.Err(teamErr))
-		// Continue with normal whitelist check even if we can't get team members
-	} else {
-		// Check if user is admin of any team
-		for _, teamMember := range teamMembers {
-			if teamMember.SchemeAdmin {
-				c.Logger().Debug("Team admin bypassing IP whitelist", mlog.String("user_id", userId), mlog.String("team_id", teamMember.TeamId))
-				return true, nil
-			}
-		}
+		// Continue with the user's system roles and personal entries only
+		teamMembers = nil
 	}
 
-	// Get user's whitelisted IPs
-	whitelistedIPs, appErr := a.GetUserWhitelistIPs(userId)
+	if decision := a.getWhitelistBypass(c, settings, user, teamMembers); decision != nil {
+		return decision, nil
+	}
+
+	// Get user's whitelisted networks
+	prefixes, appErr := a.getUserWhitelistPrefixes(c, userId)
 	if appErr != nil {
-		return false, appErr
+		return nil, appErr
 	}
 
-	// If no IPs are whitelisted, deny access (user needs to set up whitelist to use the system)
-	if len(whitelistedIPs) == 0 {
-		return false, nil
+	// Get the policies attached to the user's teams, groups and roles
+	policies, appErr := a.getWhitelistPoliciesForUser(c, user, teamMembers)
+	if appErr != nil {
+		return nil, appErr
 	}
 
-	// Check if current IP is in the whitelist
-	for _, whitelistedIP := range whitelistedIPs {
-		for _, ipAddress := range ipAddresses {
-			if ipAddress == whitelistedIP {
-				c.Logger().Debug("IP found in whitelist", mlog.String("user_id", userId), mlog.String("ip", ipAddress))
-				return true, nil
-			}
+	for _, prefix := range prefixes {
+		if model.WhitelistPrefixesContain([]netip.Prefix{prefix}, []string{ipAddress}) {
+			c.Logger().Debug("IP found in whitelist", mlog.String("user_id", userId), mlog.String("ip", ipAddress))
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleUserEntry, Match: prefix.String()}, nil
 		}
 	}
 
+	empty := len(prefixes) == 0
+	for _, policy := range policies {
+		policyPrefixes := policy.Prefixes()
+		if model.WhitelistPrefixesContain(policyPrefixes, []string{ipAddress}) {
+			c.Logger().Debug("IP found in whitelist policy", mlog.String("user_id", userId), mlog.String("ip", ipAddress), mlog.String("policy", policy.Name))
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRulePolicy, Match: policy.Name}, nil
+		}
+		empty = empty && len(policyPrefixes) == 0
+	}
+
+	if empty {
+		if *settings.EmptyWhitelistAction == model.WhitelistEmptyActionAllow {
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleEmptyAllowed}, nil
+		}
+		return &model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleEmptyDenied}, nil
+	}
+
 	// IP not in whitelist - deny access
-	return false, nil
+	return &model.WhitelistDecision{Allowed: false, Rule: model.WhitelistRuleNotWhitelisted}, nil
 }
 
-// GetClientIPAddress extracts the real client IP from the request
-func (a *App) GetClientIPAddress(r *http.Request) string {
-	// Check X-Forwarded-For header first (for load balancers/proxies)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For can contain multiple IPs, take the first one
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			ip := strings.TrimSpace(ips[0])
-			if isValidIPAddress(ip) {
-				return ip
-			}
+// getWhitelistBypass returns an allowing decision if one of the user's system or
+// team roles is listed in, or grants one of the permissions of, the configured bypass rules.
+func (a *App) getWhitelistBypass(c request.CTX, settings model.WhitelistSettings, user *model.User, teamMembers []*model.TeamMember) *model.WhitelistDecision {
+	if len(settings.BypassRoles) == 0 && len(settings.BypassPermissions) == 0 {
+		return nil
+	}
+
+	roles := user.GetRoles()
+	for _, teamMember := range teamMembers {
+		roles = append(roles, teamMember.GetRoles()...)
+		if teamMember.SchemeAdmin {
+			roles = append(roles, model.TeamAdminRoleId)
 		}
 	}
 
-	// Check X-Real-IP header (common with nginx)
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		if isValidIPAddress(xri) {
-			return xri
+	for _, role := range roles {
+		if slices.Contains(settings.BypassRoles, role) {
+			c.Logger().Debug("Role bypassing IP whitelist", mlog.String("user_id", user.Id), mlog.String("role", role))
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleBypassRole, Match: role}
 		}
 	}
 
-	// Fall back to RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	for _, permission := range settings.BypassPermissions {
+		if a.RolesGrantPermission(roles, permission) {
+			c.Logger().Debug("Permission bypassing IP whitelist", mlog.String("user_id", user.Id), mlog.String("permission", permission))
+			return &model.WhitelistDecision{Allowed: true, Rule: model.WhitelistRuleBypassPermission, Match: permission}
+		}
+	}
+
+	return nil
+}
+
+// RecordWhitelistDenial stores a denied request for the denied-access history
+// and emits a matching audit record. A client that keeps retrying would otherwise
+// write a row and an audit record per request, so repeated denials of the same
+// user, address and rule within whitelistDenialCoalesceWindow only count another
+// occurrence on the first one's record. Failures are logged, not returned, so
+// that recording never changes the outcome of the request.
+func (a *App) RecordWhitelistDenial(c request.CTX, denial *model.WhitelistDenial) {
+	denial.PreSave()
+	key := whitelistDenialKey(denial)
+
+	var id string
+	if err := a.Srv().whitelistDenialCache.Get(key, &id); err == nil {
+		err = a.Srv().Store().Whitelist().IncrementDenial(id, denial.CreateAt)
+		if err == nil {
+			return
+		}
+
+		var nfErr *store.ErrNotFound
+		if !errors.As(err, &nfErr) {
+			c.Logger().Warn("Failed to count an IP whitelist denial", mlog.String("user_id", denial.UserId), mlog.String("ip", denial.IP), mlog.Err(err))
+			return
+		}
+		// The record was purged in the meantime, so start a new one
+	} else if !errors.Is(err, cache.ErrKeyNotFound) {
+		c.Logger().Warn("Failed to get the recent IP whitelist denials", mlog.String("user_id", denial.UserId), mlog.Err(err))
+	}
+
+	rec := a.MakeAuditRecord(c, "whitelistAccessDenied", audit.Fail)
+	rec.Actor.UserId = denial.UserId
+	rec.Actor.SessionId = denial.SessionId
+	rec.Actor.IpAddress = denial.IP
+	rec.AddMeta(audit.KeyAPIPath, denial.Path)
+	audit.AddEventParameter(rec, "user_id", denial.UserId)
+	audit.AddEventParameter(rec, "ip", denial.IP)
+	audit.AddEventParameter(rec, "path", denial.Path)
+	audit.AddEventParameter(rec, "rule", denial.Rule)
+	rec.AddEventObjectType("whitelist_denial")
+
+	saved, err := a.Srv().Store().Whitelist().SaveDenial(denial)
 	if err != nil {
-		// If SplitHostPort fails, RemoteAddr might be just an IP
-		if isValidIPAddress(r.RemoteAddr) {
-			return r.RemoteAddr
+		c.Logger().Warn("Failed to record IP whitelist denial", mlog.String("user_id", denial.UserId), mlog.String("ip", denial.IP), mlog.Err(err))
+	} else {
+		rec.AddEventResultState(saved)
+		if err := a.Srv().whitelistDenialCache.SetWithExpiry(key, saved.Id, whitelistDenialCoalesceWindow); err != nil {
+			c.Logger().Warn("Failed to remember an IP whitelist denial", mlog.String("user_id", denial.UserId), mlog.Err(err))
 		}
-		return ""
 	}
 
-	return ip
+	a.LogAuditRecWithLevel(c, rec, LevelAPI, nil)
 }
 
-// isValidIPAddress validates if a string is a valid IPv4 or IPv6 address
-func isValidIPAddress(ip string) bool {
-	return net.ParseIP(ip) != nil
+func whitelistDenialKey(denial *model.WhitelistDenial) string {
+	return denial.UserId + ":" + denial.Rule + ":" + denial.IP
 }
 
-// WhitelistMiddleware is the middleware function that checks IP whitelist for authenticated users
-// This is a standalone middleware that can be used independently if needed
-func (a *App) WhitelistMiddleware(handler http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// For now, this middleware is not used since we integrated the check directly into the Handler
-		// This is kept for potential future use or alternative integration approaches
-		handler.ServeHTTP(w, r)
+// GetWhitelistDenials returns a page of recorded denials matching the filter, newest first.
+func (a *App) GetWhitelistDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, *model.AppError) {
+	if filter.IP != "" {
+		ip, err := model.NormalizeWhitelistIP(filter.IP)
+		if err != nil {
+			return nil, model.NewAppError("GetWhitelistDenials", "app.whitelist.invalid_ip.app_error", nil, "ip="+filter.IP, http.StatusBadRequest).Wrap(err)
+		}
+		filter.IP = ip
+	}
+
+	denials, err := a.Srv().Store().Whitelist().GetDenials(filter)
+	if err != nil {
+		return nil, model.NewAppError("GetWhitelistDenials", "app.whitelist.get_denials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return denials, nil
+}
+
+// ExportWhitelistDenials writes every denial matching the filter as CSV, fetching
+// them in pages so that large histories are not held in memory. Nothing is written
+// until the first page has been fetched, so invalid filters fail cleanly.
+func (a *App) ExportWhitelistDenials(filter *model.WhitelistDenialFilter, w io.Writer) *model.AppError {
+	csvWriter := csv.NewWriter(w)
+
+	pageFilter := *filter
+	pageFilter.PerPage = whitelistDenialExportPageSize
+	for pageFilter.Page = 0; ; pageFilter.Page++ {
+		denials, appErr := a.GetWhitelistDenials(&pageFilter)
+		if appErr != nil {
+			return appErr
+		}
+
+		if pageFilter.Page == 0 {
+			if err := csvWriter.Write(model.WhitelistDenialCSVHeader); err != nil {
+				return model.NewAppError("ExportWhitelistDenials", "app.whitelist.export_denials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+		}
+
+		for _, denial := range denials {
+			if err := csvWriter.Write(denial.CSVRecord()); err != nil {
+				return model.NewAppError("ExportWhitelistDenials", "app.whitelist.export_denials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+		}
+
+		if len(denials) < pageFilter.PerPage {
+			break
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return model.NewAppError("ExportWhitelistDenials", "app.whitelist.export_denials.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
+
+// initWhitelistSettings compiles the excluded path patterns and recompiles them
+// whenever the configuration changes. A change that can deny users who were
+// allowed before, such as enabling the whitelist or narrowing the bypass rules,
+// re-checks the websocket connections of this node; every node receives the
+// config change, so nothing is sent to the rest of the cluster.
+func (s *Server) initWhitelistSettings() {
+	s.whitelistExcludedPaths.Store(compileWhitelistExcludedPaths(s.platform.Log(), s.platform.Config().WhitelistSettings.ExcludedPaths))
+
+	s.platform.AddConfigListener(func(oldCfg, newCfg *model.Config) {
+		if !slices.Equal(oldCfg.WhitelistSettings.ExcludedPaths, newCfg.WhitelistSettings.ExcludedPaths) {
+			s.whitelistExcludedPaths.Store(compileWhitelistExcludedPaths(s.platform.Log(), newCfg.WhitelistSettings.ExcludedPaths))
+		}
+
+		if *newCfg.WhitelistSettings.Enable && !reflect.DeepEqual(oldCfg.WhitelistSettings, newCfg.WhitelistSettings) {
+			s.Go(func() {
+				s.platform.CloseAllWebConnsNotWhitelistedSkipClusterSend(false)
+			})
+		}
 	})
 }
 
-// shouldSkipWhitelistCheck determines if the whitelist check should be skipped for certain paths
-func (a *App) shouldSkipWhitelistCheck(path string) bool {
-	skipPaths := []string{
-		"/api/v4/users/login",
-		"/api/v4/users/logout",
-		"/api/v4/system/ping",
-		"/api/v4/config/client",
-		"/login",
-		"/signup",
-		"/static/",
-		"/fonts/",
-		"/images/",
+func compileWhitelistExcludedPaths(logger mlog.LoggerIFace, patterns []string) *[]*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := model.CompileWhitelistPathPattern(pattern)
+		if err != nil {
+			logger.Warn("Skipping invalid whitelist excluded path", mlog.String("pattern", pattern), mlog.Err(err))
+			continue
+		}
+		compiled = append(compiled, re)
 	}
+	return &compiled
+}
 
-	for _, skipPath := range skipPaths {
-		if strings.HasPrefix(path, skipPath) {
+// IsWhitelistExcludedPath reports whether requests to the given path skip the
+// IP whitelist check, according to WhitelistSettings.ExcludedPaths.
+func (a *App) IsWhitelistExcludedPath(path string) bool {
+	patterns := a.Srv().whitelistExcludedPaths.Load()
+	if patterns == nil {
+		patterns = compileWhitelistExcludedPaths(a.Log(), a.Config().WhitelistSettings.ExcludedPaths)
+	}
+
+	for _, re := range *patterns {
+		if re.MatchString(path) {
 			return true
 		}
 	}
 
 	return false
-} 
\ No newline at end of file
+}
+
+// initTrustedProxies parses the trusted proxy ranges and parses them again
+// whenever the configuration changes, so requests do not parse them each time.
+func (s *Server) initTrustedProxies() {
+	proxies := utils.ParseTrustedProxies(s.platform.Config().ServiceSettings.TrustedProxyCIDRs)
+	s.trustedProxies.Store(&proxies)
+
+	s.platform.AddConfigListener(func(oldCfg, newCfg *model.Config) {
+		if slices.Equal(oldCfg.ServiceSettings.TrustedProxyCIDRs, newCfg.ServiceSettings.TrustedProxyCIDRs) {
+			return
+		}
+		proxies := utils.ParseTrustedProxies(newCfg.ServiceSettings.TrustedProxyCIDRs)
+		s.trustedProxies.Store(&proxies)
+	})
+}
+
+func (s *Server) getClientIPAddress(r *http.Request) string {
+	serviceSettings := s.platform.Config().ServiceSettings
+
+	proxies := s.trustedProxies.Load()
+	if proxies == nil {
+		parsed := utils.ParseTrustedProxies(serviceSettings.TrustedProxyCIDRs)
+		proxies = &parsed
+	}
+
+	return utils.GetClientIPAddress(r, serviceSettings.TrustedProxyIPHeader, *proxies)
+}
+
+// GetClientIPAddress resolves the real client IP of the request, honoring the
+// configured proxy headers only when they were set by a trusted proxy.
+func (a *App) GetClientIPAddress(r *http.Request) string {
+	return a.Srv().getClientIPAddress(r)
+}
+
+// WhitelistMiddleware is the middleware function that checks IP whitelist for authenticated users
+// This is a standalone middleware that can be used independently if needed
+func (a *App) WhitelistMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// For now, this middleware is not used since we integrated the check directly into the Handler
+		// This is kept for potential future use or alternative integration approaches
+		handler.ServeHTTP(w, r)
+	})
+}
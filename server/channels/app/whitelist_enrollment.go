@@ -0,0 +1,265 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const (
+	TokenTypeWhitelistEnrollment = "whitelist_enrollment"
+
+	whitelistEnrollmentRateLimitWindow = 60 * 60 * 1000 // 1 hour
+)
+
+// whitelistEnrollmentTokenType is the type of the enrollment links of a user,
+// to look them up without loading the links of every user.
+func whitelistEnrollmentTokenType(userId string) string {
+	return TokenTypeWhitelistEnrollment + "_" + userId
+}
+
+type whitelistEnrollmentTokenExtra struct {
+	UserId string
+	Email  string
+	IP     string
+}
+
+type whitelistEnrollmentToken struct {
+	*model.Token
+	extra whitelistEnrollmentTokenExtra
+}
+
+// CheckWhitelistEnrollmentOnLogin checks the address a user is logging in from when
+// self-service enrollment is enabled. A user with MFA active, whose TOTP code was
+// already validated by the login, has the address enrolled right away. Otherwise a
+// confirmation link is emailed and the login is refused until it is opened.
+// Without enrollment the whitelist is enforced on the requests following the login.
+func (a *App) CheckWhitelistEnrollmentOnLogin(c request.CTX, user *model.User, ipAddress string) *model.AppError {
+	settings := a.Config().WhitelistSettings
+	if !*settings.Enable || !*settings.EnableSelfServiceEnrollment {
+		return nil
+	}
+
+	decision, appErr := a.EvaluateUserIPWhitelist(c, user.Id, ipAddress)
+	if appErr != nil {
+		return appErr
+	}
+
+	// Only addresses missing from the whitelist can be enrolled, bot and OAuth
+	// app denials are left to the checks of the following requests
+	if decision.Allowed || (decision.Rule != model.WhitelistRuleNotWhitelisted && decision.Rule != model.WhitelistRuleEmptyDenied) {
+		return nil
+	}
+
+	a.RecordWhitelistDenial(c, &model.WhitelistDenial{
+		UserId: user.Id,
+		IP:     ipAddress,
+		Path:   model.APIURLSuffix + "/users/login",
+		Rule:   decision.Rule,
+	})
+
+	if slices.Contains(settings.EnrollmentMethods, model.WhitelistEnrollmentMethodMfa) && user.MfaActive && *a.Config().ServiceSettings.EnableMultifactorAuthentication {
+		tokens, appErr := a.getWhitelistEnrollmentTokens(user.Id)
+		if appErr != nil {
+			return appErr
+		}
+		if appErr := a.checkWhitelistEnrollmentRateLimit(user.Id, tokens); appErr != nil {
+			return appErr
+		}
+
+		_, appErr = a.enrollWhitelistIP(c, user.Id, ipAddress, model.WhitelistEnrollmentMethodMfa)
+		return appErr
+	}
+
+	if slices.Contains(settings.EnrollmentMethods, model.WhitelistEnrollmentMethodEmail) && *a.Config().EmailSettings.SendEmailNotifications {
+		if appErr := a.sendWhitelistEnrollmentEmail(c, user, ipAddress); appErr != nil {
+			return appErr
+		}
+		return model.NewAppError("CheckWhitelistEnrollmentOnLogin", "api.user.login.whitelist_enrollment_email_sent.app_error", nil, "", http.StatusForbidden)
+	}
+
+	return model.NewAppError("CheckWhitelistEnrollmentOnLogin", "api.context.ip_whitelist_denied.app_error", nil, "", http.StatusForbidden)
+}
+
+// ConfirmWhitelistEnrollment adds the address of an emailed enrollment link to
+// the whitelist of its user. Each link can only be used once.
+func (a *App) ConfirmWhitelistEnrollment(c request.CTX, tokenString string) (*model.WhitelistItem, *model.AppError) {
+	settings := a.Config().WhitelistSettings
+	if !*settings.EnableSelfServiceEnrollment || !slices.Contains(settings.EnrollmentMethods, model.WhitelistEnrollmentMethodEmail) {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	token, err := a.Srv().Store().Token().GetByToken(tokenString)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.confirm.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if !strings.HasPrefix(token.Type, TokenTypeWhitelistEnrollment+"_") {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	// The link is single use, so it is removed whatever the outcome
+	if appErr := a.DeleteToken(token); appErr != nil {
+		return nil, appErr
+	}
+
+	if model.GetMillis()-token.CreateAt >= int64(*settings.EnrollmentLinkExpiryMinutes)*60*1000 {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.link_expired.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	var extra whitelistEnrollmentTokenExtra
+	if err := json.Unmarshal([]byte(token.Extra), &extra); err != nil {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if token.Type != whitelistEnrollmentTokenType(extra.UserId) {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	user, appErr := a.GetUser(extra.UserId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// A link sent before the email address changed no longer proves ownership
+	if user.Email != extra.Email {
+		return nil, model.NewAppError("ConfirmWhitelistEnrollment", "app.whitelist.enrollment.invalid_link.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return a.enrollWhitelistIP(c, user.Id, extra.IP, model.WhitelistEnrollmentMethodEmail)
+}
+
+// sendWhitelistEnrollmentEmail emails a link adding ipAddress to the user's
+// whitelist, unless one sent earlier for the same address is still valid.
+func (a *App) sendWhitelistEnrollmentEmail(c request.CTX, user *model.User, ipAddress string) *model.AppError {
+	tokens, appErr := a.getWhitelistEnrollmentTokens(user.Id)
+	if appErr != nil {
+		return appErr
+	}
+
+	expiry := int64(*a.Config().WhitelistSettings.EnrollmentLinkExpiryMinutes) * 60 * 1000
+	now := model.GetMillis()
+	for _, token := range tokens {
+		if token.extra.IP == ipAddress && now-token.CreateAt < expiry {
+			return nil
+		}
+	}
+
+	if appErr := a.checkWhitelistEnrollmentRateLimit(user.Id, tokens); appErr != nil {
+		return appErr
+	}
+
+	jsonData, err := json.Marshal(whitelistEnrollmentTokenExtra{
+		UserId: user.Id,
+		Email:  user.Email,
+		IP:     ipAddress,
+	})
+	if err != nil {
+		return model.NewAppError("sendWhitelistEnrollmentEmail", "app.whitelist.enrollment.send_email.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	token := model.NewToken(whitelistEnrollmentTokenType(user.Id), string(jsonData))
+	if err := a.Srv().Store().Token().Save(token); err != nil {
+		return model.NewAppError("sendWhitelistEnrollmentEmail", "app.whitelist.enrollment.send_email.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	if err := a.Srv().EmailService.SendWhitelistEnrollmentEmail(user.Email, user.Locale, a.GetSiteURL(), ipAddress, token.Token); err != nil {
+		return model.NewAppError("sendWhitelistEnrollmentEmail", "app.whitelist.enrollment.send_email.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	c.Logger().Info("Sent IP whitelist enrollment email", mlog.String("user_id", user.Id), mlog.String("ip", ipAddress))
+	return nil
+}
+
+// getWhitelistEnrollmentTokens gets the pending enrollment links of the user.
+func (a *App) getWhitelistEnrollmentTokens(userId string) ([]*whitelistEnrollmentToken, *model.AppError) {
+	tokens, err := a.Srv().Store().Token().GetAllTokensByType(whitelistEnrollmentTokenType(userId))
+	if err != nil {
+		return nil, model.NewAppError("getWhitelistEnrollmentTokens", "app.whitelist.enrollment.get_tokens.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	var userTokens []*whitelistEnrollmentToken
+	for _, token := range tokens {
+		var extra whitelistEnrollmentTokenExtra
+		if err := json.Unmarshal([]byte(token.Extra), &extra); err != nil || extra.UserId != userId {
+			continue
+		}
+		userTokens = append(userTokens, &whitelistEnrollmentToken{Token: token, extra: extra})
+	}
+
+	return userTokens, nil
+}
+
+// checkWhitelistEnrollmentRateLimit counts the links sent and the entries the user
+// added themselves during the last hour against EnrollmentRateLimitPerHour.
+func (a *App) checkWhitelistEnrollmentRateLimit(userId string, tokens []*whitelistEnrollmentToken) *model.AppError {
+	since := model.GetMillis() - whitelistEnrollmentRateLimitWindow
+
+	count := 0
+	for _, token := range tokens {
+		if token.CreateAt >= since {
+			count++
+		}
+	}
+
+	items, appErr := a.GetUserWhitelist(userId)
+	if appErr != nil {
+		return appErr
+	}
+	for _, item := range items {
+		if item.CreatorId == userId && item.CreateAt >= since {
+			count++
+		}
+	}
+
+	if count >= *a.Config().WhitelistSettings.EnrollmentRateLimitPerHour {
+		return model.NewAppError("checkWhitelistEnrollmentRateLimit", "app.whitelist.enrollment.rate_limited.app_error", nil, "user_id="+userId, http.StatusTooManyRequests)
+	}
+
+	return nil
+}
+
+// enrollWhitelistIP adds a single address to the user's whitelist on their own
+// behalf, expiring after EnrollmentEntryExpiryHours when set.
+func (a *App) enrollWhitelistIP(c request.CTX, userId, ipAddress, method string) (*model.WhitelistItem, *model.AppError) {
+	rec := a.MakeAuditRecord(c, "whitelistSelfServiceEnroll", audit.Fail)
+	defer a.LogAuditRecWithLevel(c, rec, LevelAPI, nil)
+	rec.Actor.UserId = userId
+	audit.AddEventParameter(rec, "user_id", userId)
+	audit.AddEventParameter(rec, "ip", ipAddress)
+	audit.AddEventParameter(rec, "method", method)
+
+	item := &model.WhitelistItem{
+		UserId:      userId,
+		IP:          ipAddress,
+		Description: "Self-service enrollment (" + method + ")",
+		CreatorId:   userId,
+	}
+	if hours := *a.Config().WhitelistSettings.EnrollmentEntryExpiryHours; hours > 0 {
+		item.ExpiresAt = model.GetMillis() + int64(hours)*60*60*1000
+	}
+
+	saved, appErr := a.AddUserToWhitelist(c, item)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	rec.Success()
+	rec.AddEventObjectType("whitelist_item")
+	rec.AddEventResultState(saved)
+	return saved, nil
+}
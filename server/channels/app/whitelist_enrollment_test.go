@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	emailmocks "github.com/mattermost/mattermost/server/v8/channels/app/email/mocks"
+)
+
+func TestWhitelistEnrollmentOnLogin(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	const ip = "198.51.100.7"
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		cfg.WhitelistSettings.EnableSelfServiceEnrollment = model.NewPointer(true)
+		cfg.WhitelistSettings.EnrollmentMethods = []string{model.WhitelistEnrollmentMethodEmail}
+		cfg.WhitelistSettings.EnrollmentRateLimitPerHour = model.NewPointer(2)
+		cfg.EmailSettings.SendEmailNotifications = model.NewPointer(true)
+	})
+
+	t.Run("disabled enrollment leaves the login alone", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.WhitelistSettings.EnableSelfServiceEnrollment = model.NewPointer(false)
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.WhitelistSettings.EnableSelfServiceEnrollment = model.NewPointer(true)
+		})
+
+		require.Nil(t, th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, ip))
+	})
+
+	t.Run("email link enrolls the address once", func(t *testing.T) {
+		var sentToken string
+		emailServiceMock := emailmocks.ServiceInterface{}
+		emailServiceMock.On("SendWhitelistEnrollmentEmail", th.BasicUser.Email, mock.Anything, mock.Anything, ip, mock.AnythingOfType("string")).
+			Once().
+			Run(func(args mock.Arguments) { sentToken = args.String(4) }).
+			Return(nil)
+		emailServiceMock.On("Stop").Return()
+		th.App.Srv().EmailService = &emailServiceMock
+
+		appErr := th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, ip)
+		require.NotNil(t, appErr)
+		require.Equal(t, "api.user.login.whitelist_enrollment_email_sent.app_error", appErr.Id)
+
+		// A second login while the link is valid does not send another email
+		appErr = th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, ip)
+		require.NotNil(t, appErr)
+		emailServiceMock.AssertExpectations(t)
+
+		item, appErr := th.App.ConfirmWhitelistEnrollment(th.Context, sentToken)
+		require.Nil(t, appErr)
+		require.Equal(t, th.BasicUser.Id, item.UserId)
+		require.Equal(t, th.BasicUser.Id, item.CreatorId)
+
+		allowed, appErr := th.App.CheckUserIPWhitelisted(th.Context, th.BasicUser.Id, ip)
+		require.Nil(t, appErr)
+		require.True(t, allowed)
+		require.Nil(t, th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, ip))
+
+		_, appErr = th.App.ConfirmWhitelistEnrollment(th.Context, sentToken)
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.whitelist.enrollment.invalid_link.app_error", appErr.Id)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		emailServiceMock := emailmocks.ServiceInterface{}
+		emailServiceMock.On("SendWhitelistEnrollmentEmail", th.BasicUser.Email, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		emailServiceMock.On("Stop").Return()
+		th.App.Srv().EmailService = &emailServiceMock
+
+		// The entry enrolled above counts towards the limit of two per hour
+		appErr := th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, "198.51.100.8")
+		require.NotNil(t, appErr)
+		require.Equal(t, "api.user.login.whitelist_enrollment_email_sent.app_error", appErr.Id)
+
+		appErr = th.App.CheckWhitelistEnrollmentOnLogin(th.Context, th.BasicUser, "198.51.100.9")
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.whitelist.enrollment.rate_limited.app_error", appErr.Id)
+		require.Equal(t, http.StatusTooManyRequests, appErr.StatusCode)
+	})
+
+	t.Run("links of other users are left out", func(t *testing.T) {
+		emailServiceMock := emailmocks.ServiceInterface{}
+		emailServiceMock.On("SendWhitelistEnrollmentEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		emailServiceMock.On("Stop").Return()
+		th.App.Srv().EmailService = &emailServiceMock
+
+		// The links of the user rate limited above don't count for another one
+		user := th.CreateUser()
+		appErr := th.App.CheckWhitelistEnrollmentOnLogin(th.Context, user, ip)
+		require.NotNil(t, appErr)
+		require.Equal(t, "api.user.login.whitelist_enrollment_email_sent.app_error", appErr.Id)
+
+		tokens, appErr := th.App.getWhitelistEnrollmentTokens(user.Id)
+		require.Nil(t, appErr)
+		require.Len(t, tokens, 1)
+		require.Equal(t, whitelistEnrollmentTokenType(user.Id), tokens[0].Type)
+		require.Equal(t, ip, tokens[0].extra.IP)
+
+		tokens, appErr = th.App.getWhitelistEnrollmentTokens(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		for _, token := range tokens {
+			require.Equal(t, th.BasicUser.Id, token.extra.UserId)
+		}
+	})
+
+	t.Run("mfa enrolls the address right away", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.ServiceSettings.EnableMultifactorAuthentication = model.NewPointer(true)
+			cfg.WhitelistSettings.EnrollmentMethods = []string{model.WhitelistEnrollmentMethodMfa}
+		})
+
+		user := th.CreateUser()
+		user.MfaActive = true
+
+		require.Nil(t, th.App.CheckWhitelistEnrollmentOnLogin(th.Context, user, ip))
+
+		items, appErr := th.App.GetUserWhitelist(user.Id)
+		require.Nil(t, appErr)
+		require.Len(t, items, 1)
+		require.Equal(t, ip, items[0].IP)
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// whitelistPolicyAppError converts a whitelist policy store error into an AppError
+func whitelistPolicyAppError(where string, err error) *model.AppError {
+	var appErr *model.AppError
+	var nfErr *store.ErrNotFound
+	var uniqueErr *store.ErrUniqueConstraint
+	var conflictErr *store.ErrConflict
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &nfErr):
+		return model.NewAppError(where, "app.whitelist_policy.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+	case errors.As(err, &uniqueErr):
+		return model.NewAppError(where, "app.whitelist_policy.name_exists.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	case errors.As(err, &conflictErr):
+		return model.NewAppError(where, "app.whitelist_policy.target_exists.app_error", nil, "", http.StatusBadRequest).Wrap(err)
+	default:
+		return model.NewAppError(where, "app.whitelist_policy.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+}
+
+// CreateWhitelistPolicy saves a new named whitelist policy
+func (a *App) CreateWhitelistPolicy(c request.CTX, policy *model.WhitelistPolicy) (*model.WhitelistPolicy, *model.AppError) {
+	policy.Id = ""
+
+	saved, err := a.Srv().Store().WhitelistPolicy().Save(policy)
+	if err != nil {
+		return nil, whitelistPolicyAppError("CreateWhitelistPolicy", err)
+	}
+
+	c.Logger().Info("Created IP whitelist policy", mlog.String("policy_id", saved.Id), mlog.String("name", saved.Name))
+	return saved, nil
+}
+
+// GetWhitelistPolicy gets a whitelist policy by id
+func (a *App) GetWhitelistPolicy(policyId string) (*model.WhitelistPolicy, *model.AppError) {
+	policy, err := a.Srv().Store().WhitelistPolicy().Get(policyId)
+	if err != nil {
+		return nil, whitelistPolicyAppError("GetWhitelistPolicy", err)
+	}
+
+	return policy, nil
+}
+
+// GetWhitelistPolicyByName gets a whitelist policy by its unique name
+func (a *App) GetWhitelistPolicyByName(name string) (*model.WhitelistPolicy, *model.AppError) {
+	policy, err := a.Srv().Store().WhitelistPolicy().GetByName(name)
+	if err != nil {
+		return nil, whitelistPolicyAppError("GetWhitelistPolicyByName", err)
+	}
+
+	return policy, nil
+}
+
+// GetWhitelistPolicies gets a page of whitelist policies ordered by name
+func (a *App) GetWhitelistPolicies(page, perPage int) ([]*model.WhitelistPolicy, *model.AppError) {
+	policies, err := a.Srv().Store().WhitelistPolicy().GetAll(page*perPage, perPage)
+	if err != nil {
+		return nil, model.NewAppError("GetWhitelistPolicies", "app.whitelist_policy.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return policies, nil
+}
+
+// PatchWhitelistPolicy updates the name, description or ranges of a whitelist policy
+func (a *App) PatchWhitelistPolicy(c request.CTX, policyId string, patch *model.WhitelistPolicyPatch) (*model.WhitelistPolicy, *model.AppError) {
+	policy, appErr := a.GetWhitelistPolicy(policyId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	policy.Patch(patch)
+
+	updated, err := a.Srv().Store().WhitelistPolicy().Update(policy)
+	if err != nil {
+		return nil, whitelistPolicyAppError("PatchWhitelistPolicy", err)
+	}
+
+	c.Logger().Info("Updated IP whitelist policy", mlog.String("policy_id", updated.Id), mlog.String("name", updated.Name))
+	a.closeWebConnsAfterWhitelistPolicyChange()
+	return updated, nil
+}
+
+// DeleteWhitelistPolicy removes a whitelist policy and all of its target assignments
+func (a *App) DeleteWhitelistPolicy(c request.CTX, policyId string) *model.AppError {
+	if err := a.Srv().Store().WhitelistPolicy().Delete(policyId); err != nil {
+		return whitelistPolicyAppError("DeleteWhitelistPolicy", err)
+	}
+
+	c.Logger().Info("Deleted IP whitelist policy", mlog.String("policy_id", policyId))
+	a.closeWebConnsAfterWhitelistPolicyChange()
+	return nil
+}
+
+// GetWhitelistPolicyTargets gets the teams, groups and roles a policy is attached to
+func (a *App) GetWhitelistPolicyTargets(policyId string) ([]*model.WhitelistPolicyTarget, *model.AppError) {
+	if _, appErr := a.GetWhitelistPolicy(policyId); appErr != nil {
+		return nil, appErr
+	}
+
+	targets, err := a.Srv().Store().WhitelistPolicy().GetTargets(policyId)
+	if err != nil {
+		return nil, model.NewAppError("GetWhitelistPolicyTargets", "app.whitelist_policy.get_targets.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return targets, nil
+}
+
+// AddWhitelistPolicyTarget attaches a policy to a team, group or role after checking the target exists
+func (a *App) AddWhitelistPolicyTarget(c request.CTX, target *model.WhitelistPolicyTarget) (*model.WhitelistPolicyTarget, *model.AppError) {
+	if appErr := target.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	if _, appErr := a.GetWhitelistPolicy(target.PolicyId); appErr != nil {
+		return nil, appErr
+	}
+
+	var appErr *model.AppError
+	switch target.TargetType {
+	case model.WhitelistPolicyTargetTypeTeam:
+		_, appErr = a.GetTeam(target.TargetId)
+	case model.WhitelistPolicyTargetTypeGroup:
+		_, appErr = a.GetGroup(target.TargetId, nil, nil)
+	case model.WhitelistPolicyTargetTypeRole:
+		_, appErr = a.GetRoleByName(c.Context(), target.TargetId)
+	}
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store().WhitelistPolicy().AddTarget(target); err != nil {
+		return nil, whitelistPolicyAppError("AddWhitelistPolicyTarget", err)
+	}
+
+	c.Logger().Info("Attached IP whitelist policy",
+		mlog.String("policy_id", target.PolicyId),
+		mlog.String("target_type", target.TargetType),
+		mlog.String("target_id", target.TargetId))
+	// A policy can deny users whose whitelist was empty and therefore allowed
+	a.closeWebConnsAfterWhitelistPolicyChange()
+	return target, nil
+}
+
+// RemoveWhitelistPolicyTarget detaches a policy from a team, group or role
+func (a *App) RemoveWhitelistPolicyTarget(c request.CTX, target *model.WhitelistPolicyTarget) *model.AppError {
+	if err := a.Srv().Store().WhitelistPolicy().RemoveTarget(target); err != nil {
+		return model.NewAppError("RemoveWhitelistPolicyTarget", "app.whitelist_policy.remove_target.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	c.Logger().Info("Detached IP whitelist policy",
+		mlog.String("policy_id", target.PolicyId),
+		mlog.String("target_type", target.TargetType),
+		mlog.String("target_id", target.TargetId))
+	a.closeWebConnsAfterWhitelistPolicyChange()
+	return nil
+}
+
+// closeWebConnsAfterWhitelistPolicyChange closes, in the background and on every
+// node, the websocket connections that a policy change has stopped whitelisting.
+// Policies can apply to any user, so every connection is checked again.
+func (a *App) closeWebConnsAfterWhitelistPolicyChange() {
+	a.Srv().Go(func() {
+		a.Srv().Platform().CloseAllWebConnsNotWhitelisted(false)
+	})
+}
+
+// getWhitelistPoliciesForUser collects every policy attached to one of the
+// user's teams, groups, system roles or team roles.
+func (a *App) getWhitelistPoliciesForUser(c request.CTX, user *model.User, teamMembers []*model.TeamMember) ([]*model.WhitelistPolicy, *model.AppError) {
+	roleNames := user.GetRoles()
+	teamIds := make([]string, 0, len(teamMembers))
+	for _, teamMember := range teamMembers {
+		if teamMember.DeleteAt != 0 {
+			continue
+		}
+		teamIds = append(teamIds, teamMember.TeamId)
+		roleNames = append(roleNames, teamMember.GetRoles()...)
+	}
+
+	groups, appErr := a.GetGroupsByUserId(user.Id, model.GroupSearchOpts{})
+	if appErr != nil {
+		return nil, appErr
+	}
+	groupIds := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupIds = append(groupIds, group.Id)
+	}
+
+	policies, err := a.Srv().Store().WhitelistPolicy().GetForTargets(teamIds, groupIds, roleNames)
+	if err != nil {
+		return nil, model.NewAppError("getWhitelistPoliciesForUser", "app.whitelist_policy.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestEvaluateUserIPWhitelist(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	const (
+		allowedIP = "10.1.2.3"
+		otherIP   = "192.0.2.1"
+	)
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.WhitelistSettings.Enable = true
+		*cfg.WhitelistSettings.EmptyWhitelistAction = model.WhitelistEmptyActionDeny
+	})
+
+	evaluate := func(t *testing.T, userId, ip string) *model.WhitelistDecision {
+		t.Helper()
+		decision, appErr := th.App.EvaluateUserIPWhitelist(th.Context, userId, ip)
+		require.Nil(t, appErr)
+		return decision
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.Enable = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.Enable = true })
+
+		decision := evaluate(t, th.BasicUser.Id, otherIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleDisabled, decision.Rule)
+	})
+
+	t.Run("empty whitelist", func(t *testing.T) {
+		decision := evaluate(t, th.BasicUser.Id, otherIP)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleEmptyDenied, decision.Rule)
+
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.WhitelistSettings.EmptyWhitelistAction = model.WhitelistEmptyActionAllow
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.WhitelistSettings.EmptyWhitelistAction = model.WhitelistEmptyActionDeny
+		})
+
+		decision = evaluate(t, th.BasicUser.Id, otherIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleEmptyAllowed, decision.Rule)
+	})
+
+	t.Run("bypass permission", func(t *testing.T) {
+		decision := evaluate(t, th.SystemAdminUser.Id, otherIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleBypassPermission, decision.Rule)
+		assert.Equal(t, model.PermissionManageSystem.Id, decision.Match)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.WhitelistSettings.BypassPermissions = []string{} })
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			cfg.WhitelistSettings.BypassPermissions = []string{model.PermissionManageSystem.Id}
+		})
+
+		decision = evaluate(t, th.SystemAdminUser.Id, otherIP)
+		assert.False(t, decision.Allowed)
+	})
+
+	t.Run("bypass role", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { cfg.WhitelistSettings.BypassRoles = []string{model.SystemUserRoleId} })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { cfg.WhitelistSettings.BypassRoles = []string{model.TeamAdminRoleId} })
+
+		decision := evaluate(t, th.BasicUser.Id, otherIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleBypassRole, decision.Rule)
+		assert.Equal(t, model.SystemUserRoleId, decision.Match)
+	})
+
+	t.Run("user entry", func(t *testing.T) {
+		item, appErr := th.App.AddUserToWhitelist(th.Context, &model.WhitelistItem{UserId: th.BasicUser2.Id, IP: "10.0.0.0/8"})
+		require.Nil(t, appErr)
+		defer func() {
+			require.Nil(t, th.App.RemoveUserFromWhitelist(th.Context, th.BasicUser2.Id, item.IP, false))
+		}()
+
+		decision := evaluate(t, th.BasicUser2.Id, allowedIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleUserEntry, decision.Rule)
+		assert.Equal(t, "10.0.0.0/8", decision.Match)
+
+		decision = evaluate(t, th.BasicUser2.Id, otherIP)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleNotWhitelisted, decision.Rule)
+	})
+
+	t.Run("bot", func(t *testing.T) {
+		bot := th.CreateBot()
+
+		decision := evaluate(t, bot.UserId, otherIP)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleEmptyDenied, decision.Rule)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.BotAccess = model.WhitelistAccessBypass })
+		decision = evaluate(t, bot.UserId, otherIP)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleBotBypass, decision.Rule)
+
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.BotAccess = model.WhitelistAccessDeny })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.WhitelistSettings.BotAccess = model.WhitelistAccessEnforce })
+
+		_, appErr := th.App.AddUserToWhitelist(th.Context, &model.WhitelistItem{UserId: bot.UserId, IP: allowedIP})
+		require.Nil(t, appErr)
+		decision = evaluate(t, bot.UserId, allowedIP)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, model.WhitelistRuleBotDenied, decision.Rule)
+	})
+
+	sessionTests := map[string]struct {
+		session    *model.Session
+		setAccess  func(cfg *model.Config, access string)
+		bypassRule string
+		deniedRule string
+	}{
+		"oauth app": {
+			session: &model.Session{UserId: th.BasicUser.Id, IsOAuth: true},
+			setAccess: func(cfg *model.Config, access string) {
+				*cfg.WhitelistSettings.OAuthAppAccess = access
+			},
+			bypassRule: model.WhitelistRuleOAuthAppBypass,
+			deniedRule: model.WhitelistRuleOAuthAppDenied,
+		},
+		"personal access token": {
+			session: &model.Session{UserId: th.BasicUser.Id, Props: model.StringMap{model.SessionPropType: model.SessionTypeUserAccessToken}},
+			setAccess: func(cfg *model.Config, access string) {
+				*cfg.WhitelistSettings.PersonalAccessTokenAccess = access
+			},
+			bypassRule: model.WhitelistRuleAccessTokenBypass,
+			deniedRule: model.WhitelistRuleAccessTokenDenied,
+		},
+	}
+	for name, test := range sessionTests {
+		t.Run(name, func(t *testing.T) {
+			rctx := th.Context.WithSession(test.session)
+			defer th.App.UpdateConfig(func(cfg *model.Config) { test.setAccess(cfg, model.WhitelistAccessEnforce) })
+
+			decision, appErr := th.App.EvaluateUserIPWhitelist(rctx, th.BasicUser.Id, otherIP)
+			require.Nil(t, appErr)
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, model.WhitelistRuleEmptyDenied, decision.Rule)
+
+			th.App.UpdateConfig(func(cfg *model.Config) { test.setAccess(cfg, model.WhitelistAccessBypass) })
+			decision, appErr = th.App.EvaluateUserIPWhitelist(rctx, th.BasicUser.Id, otherIP)
+			require.Nil(t, appErr)
+			assert.True(t, decision.Allowed)
+			assert.Equal(t, test.bypassRule, decision.Rule)
+
+			th.App.UpdateConfig(func(cfg *model.Config) { test.setAccess(cfg, model.WhitelistAccessDeny) })
+			decision, appErr = th.App.EvaluateUserIPWhitelist(rctx, th.BasicUser.Id, otherIP)
+			require.Nil(t, appErr)
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, test.deniedRule, decision.Rule)
+
+			// The setting only applies to the session's own user
+			decision, appErr = th.App.EvaluateUserIPWhitelist(rctx, th.BasicUser2.Id, otherIP)
+			require.Nil(t, appErr)
+			assert.Equal(t, model.WhitelistRuleEmptyDenied, decision.Rule)
+		})
+	}
+}
+
+func TestRecordWhitelistDenial(t *testing.T) {
+	mainHelper.Parallel(t)
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	record := func(rule string) {
+		th.App.RecordWhitelistDenial(th.Context, &model.WhitelistDenial{
+			UserId: th.BasicUser.Id,
+			IP:     "::ffff:192.0.2.1",
+			Path:   "/api/v4/users/me",
+			Rule:   rule,
+		})
+	}
+
+	record(model.WhitelistRuleNotWhitelisted)
+	record(model.WhitelistRuleNotWhitelisted)
+	record(model.WhitelistRuleNotWhitelisted)
+	record(model.WhitelistRuleEmptyDenied)
+
+	denials, appErr := th.App.GetWhitelistDenials(&model.WhitelistDenialFilter{UserId: th.BasicUser.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	require.Len(t, denials, 2)
+
+	occurrences := map[string]int{}
+	for _, denial := range denials {
+		assert.Equal(t, "192.0.2.1", denial.IP)
+		assert.GreaterOrEqual(t, denial.LastAt, denial.CreateAt)
+		occurrences[denial.Rule] = denial.Occurrences
+	}
+	assert.Equal(t, map[string]int{
+		model.WhitelistRuleNotWhitelisted: 3,
+		model.WhitelistRuleEmptyDenied:    1,
+	}, occurrences)
+}
@@ -0,0 +1,45 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND index_name = 'idx_whitelist_expiresat'
+    ) > 0,
+    'DROP INDEX idx_whitelist_expiresat ON Whitelist;',
+    'SELECT 1'
+));
+
+PREPARE removeIndexIfExists FROM @preparedStatement;
+EXECUTE removeIndexIfExists;
+DEALLOCATE PREPARE removeIndexIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND column_name = 'ExpiresAt'
+    ) > 0,
+    'ALTER TABLE Whitelist DROP COLUMN Description, DROP COLUMN CreatorId, DROP COLUMN CreateAt, DROP COLUMN ExpiresAt;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND column_name = 'IP'
+        AND column_type != 'varchar(39)'
+    ) > 0,
+    'ALTER TABLE Whitelist MODIFY COLUMN IP varchar(39) NOT NULL;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
@@ -0,0 +1,45 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND column_name = 'IP'
+        AND column_type != 'varchar(49)'
+    ) > 0,
+    'ALTER TABLE Whitelist MODIFY COLUMN IP varchar(49) NOT NULL;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND column_name = 'ExpiresAt'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE Whitelist ADD COLUMN Description varchar(255) NOT NULL DEFAULT \'\', ADD COLUMN CreatorId varchar(26) NOT NULL DEFAULT \'\', ADD COLUMN CreateAt bigint NOT NULL DEFAULT 0, ADD COLUMN ExpiresAt bigint NOT NULL DEFAULT 0;'
+));
+
+PREPARE alterIfNotExists FROM @preparedStatement;
+EXECUTE alterIfNotExists;
+DEALLOCATE PREPARE alterIfNotExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE table_name = 'Whitelist'
+        AND table_schema = DATABASE()
+        AND index_name = 'idx_whitelist_expiresat'
+    ) > 0,
+    'SELECT 1',
+    'CREATE INDEX idx_whitelist_expiresat ON Whitelist(ExpiresAt);'
+));
+
+PREPARE createIndexIfNotExists FROM @preparedStatement;
+EXECUTE createIndexIfNotExists;
+DEALLOCATE PREPARE createIndexIfNotExists;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS WhitelistPolicyTargets;
+DROP TABLE IF EXISTS WhitelistPolicies;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS WhitelistPolicies (
+    Id varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    Description varchar(1024) NOT NULL DEFAULT '',
+    IPRanges text,
+    CreatorId varchar(26) NOT NULL DEFAULT '',
+    CreateAt bigint NOT NULL DEFAULT 0,
+    UpdateAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_whitelistpolicies_name (Name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS WhitelistPolicyTargets (
+    PolicyId varchar(26) NOT NULL,
+    TargetType varchar(16) NOT NULL,
+    TargetId varchar(64) NOT NULL,
+    CreateAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (PolicyId, TargetType, TargetId),
+    KEY idx_whitelistpolicytargets_target (TargetType, TargetId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
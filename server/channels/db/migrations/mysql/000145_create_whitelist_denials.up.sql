@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS WhitelistDenials (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    IP varchar(49) NOT NULL DEFAULT '',
+    Path varchar(512) NOT NULL DEFAULT '',
+    Rule varchar(32) NOT NULL DEFAULT '',
+    SessionId varchar(26) NOT NULL DEFAULT '',
+    CreateAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    KEY idx_whitelistdenials_create_at (CreateAt),
+    KEY idx_whitelistdenials_user_id_create_at (UserId, CreateAt),
+    KEY idx_whitelistdenials_ip_create_at (IP, CreateAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS SavedSearches (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    TeamId varchar(26) NOT NULL DEFAULT '',
+    Name varchar(64) NOT NULL,
+    Terms varchar(1024) NOT NULL,
+    IsOrSearch boolean NOT NULL DEFAULT false,
+    Alert boolean NOT NULL DEFAULT false,
+    CreateAt bigint NOT NULL DEFAULT 0,
+    UpdateAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_savedsearches_user_id_name (UserId, Name),
+    KEY idx_savedsearches_alert_team_id (Alert, TeamId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
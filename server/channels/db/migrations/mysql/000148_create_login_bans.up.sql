@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS LoginBans (
+    IP varchar(49) NOT NULL,
+    Reason varchar(16) NOT NULL DEFAULT '',
+    FailedAttempts integer NOT NULL DEFAULT 0,
+    CreateAt bigint NOT NULL DEFAULT 0,
+    ExpireAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (IP),
+    KEY idx_loginbans_expire_at (ExpireAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,44 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'RecurrenceRule'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE ScheduledPosts ADD COLUMN RecurrenceRule varchar(256) NOT NULL DEFAULT '''';'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'OccurrenceCount'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE ScheduledPosts ADD COLUMN OccurrenceCount int NOT NULL DEFAULT 0;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'PausedAt'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE ScheduledPosts ADD COLUMN PausedAt bigint NOT NULL DEFAULT 0;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
@@ -0,0 +1,29 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND index_name = 'idx_scheduledposts_channelid_scheduled_at'
+    ) > 0,
+    'DROP INDEX idx_scheduledposts_channelid_scheduled_at ON ScheduledPosts;',
+    'SELECT 1'
+));
+
+PREPARE removeIndexIfExists FROM @preparedStatement;
+EXECUTE removeIndexIfExists;
+DEALLOCATE PREPARE removeIndexIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'PublishAsBotId'
+    ) > 0,
+    'ALTER TABLE ScheduledPosts DROP COLUMN PublishAsBotId;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
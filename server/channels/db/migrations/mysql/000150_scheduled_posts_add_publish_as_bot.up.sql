@@ -0,0 +1,29 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'PublishAsBotId'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE ScheduledPosts ADD COLUMN PublishAsBotId varchar(26) NOT NULL DEFAULT '''';'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND index_name = 'idx_scheduledposts_channelid_scheduled_at'
+    ) > 0,
+    'SELECT 1',
+    'CREATE INDEX idx_scheduledposts_channelid_scheduled_at ON ScheduledPosts (ChannelId, ScheduledAt);'
+));
+
+PREPARE createIndexIfNotExists FROM @preparedStatement;
+EXECUTE createIndexIfNotExists;
+DEALLOCATE PREPARE createIndexIfNotExists;
@@ -0,0 +1,29 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'WhitelistDenials'
+        AND table_schema = DATABASE()
+        AND column_name = 'LastAt'
+    ) > 0,
+    'ALTER TABLE WhitelistDenials DROP COLUMN LastAt;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'WhitelistDenials'
+        AND table_schema = DATABASE()
+        AND column_name = 'Occurrences'
+    ) > 0,
+    'ALTER TABLE WhitelistDenials DROP COLUMN Occurrences;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
@@ -0,0 +1,31 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'WhitelistDenials'
+        AND table_schema = DATABASE()
+        AND column_name = 'Occurrences'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE WhitelistDenials ADD COLUMN Occurrences int NOT NULL DEFAULT 1;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'WhitelistDenials'
+        AND table_schema = DATABASE()
+        AND column_name = 'LastAt'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE WhitelistDenials ADD COLUMN LastAt bigint NOT NULL DEFAULT 0;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+UPDATE WhitelistDenials SET LastAt = CreateAt WHERE LastAt = 0;
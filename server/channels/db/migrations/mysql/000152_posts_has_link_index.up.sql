@@ -0,0 +1,2 @@
+-- MySQL has no partial indexes, has:link searches use the full-text index on Message instead.
+SELECT 1;
@@ -0,0 +1,16 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'ScheduledPosts'
+        AND table_schema = DATABASE()
+        AND column_name = 'RecurrenceStartAt'
+    ) > 0,
+    'SELECT 1',
+    'ALTER TABLE ScheduledPosts ADD COLUMN RecurrenceStartAt bigint NOT NULL DEFAULT 0;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+UPDATE ScheduledPosts SET RecurrenceStartAt = ScheduledAt WHERE RecurrenceRule <> '' AND RecurrenceStartAt = 0;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_whitelist_expiresat;
+
+ALTER TABLE whitelist DROP COLUMN IF EXISTS expiresat;
+ALTER TABLE whitelist DROP COLUMN IF EXISTS createat;
+ALTER TABLE whitelist DROP COLUMN IF EXISTS creatorid;
+ALTER TABLE whitelist DROP COLUMN IF EXISTS description;
+ALTER TABLE whitelist ALTER COLUMN ip TYPE varchar(39);
@@ -0,0 +1,7 @@
+ALTER TABLE whitelist ALTER COLUMN ip TYPE varchar(49);
+ALTER TABLE whitelist ADD COLUMN IF NOT EXISTS description varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE whitelist ADD COLUMN IF NOT EXISTS creatorid varchar(26) NOT NULL DEFAULT '';
+ALTER TABLE whitelist ADD COLUMN IF NOT EXISTS createat bigint NOT NULL DEFAULT 0;
+ALTER TABLE whitelist ADD COLUMN IF NOT EXISTS expiresat bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_whitelist_expiresat ON whitelist (expiresat);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_whitelistpolicytargets_target;
+DROP TABLE IF EXISTS whitelistpolicytargets;
+DROP TABLE IF EXISTS whitelistpolicies;
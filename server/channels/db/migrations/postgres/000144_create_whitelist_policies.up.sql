@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS whitelistpolicies (
+    id varchar(26) PRIMARY KEY,
+    name varchar(64) NOT NULL,
+    description varchar(1024) NOT NULL DEFAULT '',
+    ipranges text,
+    creatorid varchar(26) NOT NULL DEFAULT '',
+    createat bigint NOT NULL DEFAULT 0,
+    updateat bigint NOT NULL DEFAULT 0,
+    UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS whitelistpolicytargets (
+    policyid varchar(26) NOT NULL,
+    targettype varchar(16) NOT NULL,
+    targetid varchar(64) NOT NULL,
+    createat bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (policyid, targettype, targetid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_whitelistpolicytargets_target ON whitelistpolicytargets (targettype, targetid);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS whitelistdenials (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    ip varchar(49) NOT NULL DEFAULT '',
+    path varchar(512) NOT NULL DEFAULT '',
+    rule varchar(32) NOT NULL DEFAULT '',
+    sessionid varchar(26) NOT NULL DEFAULT '',
+    createat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_whitelistdenials_create_at ON whitelistdenials (createat);
+CREATE INDEX IF NOT EXISTS idx_whitelistdenials_user_id_create_at ON whitelistdenials (userid, createat);
+CREATE INDEX IF NOT EXISTS idx_whitelistdenials_ip_create_at ON whitelistdenials (ip, createat);
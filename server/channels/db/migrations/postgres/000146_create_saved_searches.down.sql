@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_savedsearches_alert_team_id;
+DROP TABLE IF EXISTS savedsearches;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS savedsearches (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    teamid varchar(26) NOT NULL DEFAULT '',
+    name varchar(64) NOT NULL,
+    terms varchar(1024) NOT NULL,
+    isorsearch boolean NOT NULL DEFAULT false,
+    alert boolean NOT NULL DEFAULT false,
+    createat bigint NOT NULL DEFAULT 0,
+    updateat bigint NOT NULL DEFAULT 0,
+    UNIQUE (userid, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_savedsearches_alert_team_id ON savedsearches (teamid) WHERE alert;
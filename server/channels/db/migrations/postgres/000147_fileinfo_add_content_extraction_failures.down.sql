@@ -0,0 +1,2 @@
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS contentextractionerror;
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS contentextractionfailures;
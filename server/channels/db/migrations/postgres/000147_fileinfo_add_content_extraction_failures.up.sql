@@ -0,0 +1,2 @@
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS contentextractionfailures integer NOT NULL DEFAULT 0;
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS contentextractionerror varchar(256) NOT NULL DEFAULT '';
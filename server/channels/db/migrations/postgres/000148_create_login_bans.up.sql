@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS loginbans (
+    ip varchar(49) PRIMARY KEY,
+    reason varchar(16) NOT NULL DEFAULT '',
+    failedattempts integer NOT NULL DEFAULT 0,
+    createat bigint NOT NULL DEFAULT 0,
+    expireat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_loginbans_expire_at ON loginbans (expireat);
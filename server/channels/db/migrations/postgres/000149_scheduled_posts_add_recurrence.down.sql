@@ -0,0 +1,3 @@
+ALTER TABLE scheduledposts DROP COLUMN IF EXISTS pausedat;
+ALTER TABLE scheduledposts DROP COLUMN IF EXISTS occurrencecount;
+ALTER TABLE scheduledposts DROP COLUMN IF EXISTS recurrencerule;
@@ -0,0 +1,3 @@
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS recurrencerule varchar(256) NOT NULL DEFAULT '';
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS occurrencecount integer NOT NULL DEFAULT 0;
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS pausedat bigint NOT NULL DEFAULT 0;
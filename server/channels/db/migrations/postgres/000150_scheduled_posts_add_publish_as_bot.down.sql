@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scheduledposts_channelid_scheduled_at;
+ALTER TABLE scheduledposts DROP COLUMN IF EXISTS publishasbotid;
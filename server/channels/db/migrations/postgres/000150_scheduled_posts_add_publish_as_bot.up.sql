@@ -0,0 +1,2 @@
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS publishasbotid varchar(26) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_scheduledposts_channelid_scheduled_at ON scheduledposts (channelid, scheduledat);
@@ -0,0 +1,2 @@
+ALTER TABLE whitelistdenials DROP COLUMN IF EXISTS lastat;
+ALTER TABLE whitelistdenials DROP COLUMN IF EXISTS occurrences;
@@ -0,0 +1,3 @@
+ALTER TABLE whitelistdenials ADD COLUMN IF NOT EXISTS occurrences integer NOT NULL DEFAULT 1;
+ALTER TABLE whitelistdenials ADD COLUMN IF NOT EXISTS lastat bigint NOT NULL DEFAULT 0;
+UPDATE whitelistdenials SET lastat = createat WHERE lastat = 0;
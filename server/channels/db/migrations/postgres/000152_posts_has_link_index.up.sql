@@ -0,0 +1,2 @@
+-- morph:nontransactional
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_has_link ON posts(channelid, createat) WHERE deleteat = 0 AND (lower(message) LIKE '%http://%' OR lower(message) LIKE '%https://%');
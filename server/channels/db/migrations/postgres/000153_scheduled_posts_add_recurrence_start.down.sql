@@ -0,0 +1 @@
+ALTER TABLE scheduledposts DROP COLUMN IF EXISTS recurrencestartat;
@@ -0,0 +1,2 @@
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS recurrencestartat bigint NOT NULL DEFAULT 0;
+UPDATE scheduledposts SET recurrencestartat = scheduledat WHERE recurrencerule <> '' AND recurrencestartat = 0;
@@ -0,0 +1 @@
+ALTER TABLE scheduledposts ADD COLUMN IF NOT EXISTS lasteditorid varchar(26) NOT NULL DEFAULT '';
@@ -0,0 +1,36 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cleanup_expired_login_bans
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const jobName = "CleanupExpiredLoginBans"
+
+// MakeWorker removes the login bans that expired. It stays enabled when the
+// login throttle is disabled, so that the bans left behind are removed too.
+func MakeWorker(jobServer *jobs.JobServer) *jobs.SimpleWorker {
+	isEnabled := func(cfg *model.Config) bool {
+		return true
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		deleted, err := jobServer.Store.LoginBan().DeleteExpired(model.GetMillis())
+		if err != nil {
+			return err
+		}
+
+		if deleted > 0 {
+			logger.Info("Removed expired login bans", mlog.Int("count", deleted))
+		}
+
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+	return worker
+}
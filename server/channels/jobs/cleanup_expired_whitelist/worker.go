@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cleanup_expired_whitelist
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const (
+	jobName = "CleanupExpiredWhitelist"
+
+	// Denials are removed in batches so a large backlog does not hold long locks
+	denialsDeleteBatchSize = 1000
+)
+
+func MakeWorker(jobServer *jobs.JobServer) *jobs.SimpleWorker {
+	isEnabled := func(cfg *model.Config) bool {
+		return true
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		deleted, err := jobServer.Store.Whitelist().DeleteExpired(model.GetMillis())
+		if err != nil {
+			return err
+		}
+
+		if deleted > 0 {
+			logger.Info("Removed expired whitelist entries", mlog.Int("count", deleted))
+		}
+
+		retentionDays := *jobServer.Config().WhitelistSettings.DeniedAccessRetentionDays
+		if retentionDays <= 0 {
+			return nil
+		}
+
+		endTime := model.GetMillis() - int64(retentionDays)*model.DayInMilliseconds
+		var deletedDenials int64
+		for {
+			count, err := jobServer.Store.Whitelist().DeleteDenialsBefore(endTime, denialsDeleteBatchSize)
+			if err != nil {
+				return err
+			}
+			deletedDenials += count
+			if count < denialsDeleteBatchSize {
+				break
+			}
+		}
+
+		if deletedDenials > 0 {
+			logger.Info("Removed old whitelist denials", mlog.Int("count", deletedDenials))
+		}
+
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+	return worker
+}
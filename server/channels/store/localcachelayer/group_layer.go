@@ -0,0 +1,161 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"bytes"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// LocalCacheGroupStore caches the unfiltered groups of each user, which are
+// looked up on every request to resolve the user's IP whitelist policies.
+type LocalCacheGroupStore struct {
+	store.GroupStore
+	rootStore *LocalCacheStore
+}
+
+func (s *LocalCacheGroupStore) handleClusterInvalidateGroupsByUser(msg *model.ClusterMessage) {
+	if bytes.Equal(msg.Data, clearCacheMessageData) {
+		s.rootStore.groupsByUserCache.Purge()
+	} else {
+		s.rootStore.groupsByUserCache.Remove(string(msg.Data))
+	}
+}
+
+func (s LocalCacheGroupStore) ClearCaches() {
+	s.rootStore.doClearCacheCluster(s.rootStore.groupsByUserCache)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.groupsByUserCache.Name())
+	}
+}
+
+func (s LocalCacheGroupStore) InvalidateGroupsForUser(userID string) {
+	s.rootStore.doInvalidateCacheCluster(s.rootStore.groupsByUserCache, userID, nil)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.groupsByUserCache.Name())
+	}
+}
+
+// GetByUser only caches lookups without search options, since filtered
+// lookups are not repeated often enough to be worth it.
+func (s LocalCacheGroupStore) GetByUser(userID string, opts model.GroupSearchOpts) ([]*model.Group, error) {
+	if opts != (model.GroupSearchOpts{}) {
+		return s.GroupStore.GetByUser(userID, opts)
+	}
+
+	var groups []*model.Group
+	if err := s.rootStore.doStandardReadCache(s.rootStore.groupsByUserCache, userID, &groups); err == nil {
+		return groups, nil
+	}
+
+	groups, err := s.GroupStore.GetByUser(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rootStore.doStandardAddToCache(s.rootStore.groupsByUserCache, userID, groups)
+
+	return groups, nil
+}
+
+func (s LocalCacheGroupStore) CreateWithUserIds(group *model.GroupWithUserIds) (*model.Group, error) {
+	created, err := s.GroupStore.CreateWithUserIds(group)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range group.UserIds {
+		s.InvalidateGroupsForUser(userID)
+	}
+	return created, nil
+}
+
+// Update, Delete and Restore change groups that may be cached for any of their
+// members, so they drop the whole cache.
+func (s LocalCacheGroupStore) Update(group *model.Group) (*model.Group, error) {
+	updated, err := s.GroupStore.Update(group)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClearCaches()
+	return updated, nil
+}
+
+func (s LocalCacheGroupStore) Delete(groupID string) (*model.Group, error) {
+	deleted, err := s.GroupStore.Delete(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClearCaches()
+	return deleted, nil
+}
+
+func (s LocalCacheGroupStore) Restore(groupID string) (*model.Group, error) {
+	restored, err := s.GroupStore.Restore(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClearCaches()
+	return restored, nil
+}
+
+func (s LocalCacheGroupStore) UpsertMember(groupID string, userID string) (*model.GroupMember, error) {
+	member, err := s.GroupStore.UpsertMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.InvalidateGroupsForUser(userID)
+	return member, nil
+}
+
+func (s LocalCacheGroupStore) DeleteMember(groupID string, userID string) (*model.GroupMember, error) {
+	member, err := s.GroupStore.DeleteMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.InvalidateGroupsForUser(userID)
+	return member, nil
+}
+
+func (s LocalCacheGroupStore) UpsertMembers(groupID string, userIDs []string) ([]*model.GroupMember, error) {
+	members, err := s.GroupStore.UpsertMembers(groupID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		s.InvalidateGroupsForUser(userID)
+	}
+	return members, nil
+}
+
+func (s LocalCacheGroupStore) DeleteMembers(groupID string, userIDs []string) ([]*model.GroupMember, error) {
+	members, err := s.GroupStore.DeleteMembers(groupID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range userIDs {
+		s.InvalidateGroupsForUser(userID)
+	}
+	return members, nil
+}
+
+func (s LocalCacheGroupStore) PermanentDeleteMembersByUser(userID string) error {
+	if err := s.GroupStore.PermanentDeleteMembersByUser(userID); err != nil {
+		return err
+	}
+
+	s.InvalidateGroupsForUser(userID)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func TestGroupStoreCache(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		groups, err := cachedStore.Group().GetByUser("123", model.GroupSearchOpts{})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		mockStore.Group().(*mocks.GroupStore).AssertNumberOfCalls(t, "GetByUser", 1)
+
+		cachedGroups, err := cachedStore.Group().GetByUser("123", model.GroupSearchOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, groups, cachedGroups)
+		mockStore.Group().(*mocks.GroupStore).AssertNumberOfCalls(t, "GetByUser", 1)
+	})
+
+	t.Run("filtered lookups are not cached", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.Group().GetByUser("123", model.GroupSearchOpts{FilterAllowReference: true})
+		cachedStore.Group().GetByUser("123", model.GroupSearchOpts{FilterAllowReference: true})
+		mockStore.Group().(*mocks.GroupStore).AssertNumberOfCalls(t, "GetByUser", 2)
+	})
+
+	for name, change := range map[string]func(s LocalCacheStore) error{
+		"add member": func(s LocalCacheStore) error {
+			_, err := s.Group().UpsertMember("group1", "123")
+			return err
+		},
+		"remove member": func(s LocalCacheStore) error {
+			_, err := s.Group().DeleteMember("group1", "123")
+			return err
+		},
+		"delete group": func(s LocalCacheStore) error {
+			_, err := s.Group().Delete("group1")
+			return err
+		},
+	} {
+		t.Run("first call not cached, "+name+", and then not cached again", func(t *testing.T) {
+			mockStore := getMockStore(t)
+			mockCacheProvider := getMockCacheProvider()
+			cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+			require.NoError(t, err)
+
+			cachedStore.Group().GetByUser("123", model.GroupSearchOpts{})
+			mockStore.Group().(*mocks.GroupStore).AssertNumberOfCalls(t, "GetByUser", 1)
+			require.NoError(t, change(cachedStore))
+			cachedStore.Group().GetByUser("123", model.GroupSearchOpts{})
+			mockStore.Group().(*mocks.GroupStore).AssertNumberOfCalls(t, "GetByUser", 2)
+		})
+	}
+}
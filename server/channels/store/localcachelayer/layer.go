@@ -68,6 +68,18 @@ const (
 	TeamCacheSize = 20000
 	TeamCacheSec  = 30 * 60
 
+	WhitelistCacheSize = model.SessionCacheSize
+	WhitelistCacheSec  = 30 * 60
+
+	WhitelistPolicyCacheSize = 20000
+	WhitelistPolicyCacheSec  = 30 * 60
+
+	LoginBanCacheSize = 1
+	LoginBanCacheSec  = 15 * 60
+
+	GroupsByUserCacheSize = model.SessionCacheSize
+	GroupsByUserCacheSec  = 30 * 60
+
 	ChannelCacheSec = 15 * 60 // 15 mins
 )
 
@@ -126,6 +138,18 @@ type LocalCacheStore struct {
 	termsOfServiceCache cache.Cache
 
 	invite LocalCacheInviteStore
+
+	whitelist      LocalCacheWhitelistStore
+	whitelistCache cache.Cache
+
+	whitelistPolicy      LocalCacheWhitelistPolicyStore
+	whitelistPolicyCache cache.Cache
+
+	loginBan      LocalCacheLoginBanStore
+	loginBanCache cache.Cache
+
+	group             LocalCacheGroupStore
+	groupsByUserCache cache.Cache
 }
 
 func NewLocalCacheLayer(baseStore store.Store, metrics einterfaces.MetricsInterface, cluster einterfaces.ClusterInterface, cacheProvider cache.Provider, logger mlog.LoggerIFace) (localCacheStore LocalCacheStore, err error) {
@@ -371,6 +395,50 @@ func NewLocalCacheLayer(baseStore store.Store, metrics einterfaces.MetricsInterf
 	// Invite
 	localCacheStore.invite = LocalCacheInviteStore{InviteStore: baseStore.Invite(), rootStore: &localCacheStore}
 
+	// Whitelist
+	if localCacheStore.whitelistCache, err = cacheProvider.NewCache(&cache.CacheOptions{
+		Size:                   WhitelistCacheSize,
+		Name:                   "Whitelist",
+		DefaultExpiry:          WhitelistCacheSec * time.Second,
+		InvalidateClusterEvent: model.ClusterEventInvalidateCacheForWhitelist,
+	}); err != nil {
+		return
+	}
+	localCacheStore.whitelist = LocalCacheWhitelistStore{WhitelistStore: baseStore.Whitelist(), rootStore: &localCacheStore}
+
+	// Whitelist policies
+	if localCacheStore.whitelistPolicyCache, err = cacheProvider.NewCache(&cache.CacheOptions{
+		Size:                   WhitelistPolicyCacheSize,
+		Name:                   "WhitelistPolicy",
+		DefaultExpiry:          WhitelistPolicyCacheSec * time.Second,
+		InvalidateClusterEvent: model.ClusterEventInvalidateCacheForWhitelistPolicies,
+	}); err != nil {
+		return
+	}
+	localCacheStore.whitelistPolicy = LocalCacheWhitelistPolicyStore{WhitelistPolicyStore: baseStore.WhitelistPolicy(), rootStore: &localCacheStore}
+
+	// Login bans
+	if localCacheStore.loginBanCache, err = cacheProvider.NewCache(&cache.CacheOptions{
+		Size:                   LoginBanCacheSize,
+		Name:                   "LoginBan",
+		DefaultExpiry:          LoginBanCacheSec * time.Second,
+		InvalidateClusterEvent: model.ClusterEventInvalidateCacheForLoginBans,
+	}); err != nil {
+		return
+	}
+	localCacheStore.loginBan = LocalCacheLoginBanStore{LoginBanStore: baseStore.LoginBan(), rootStore: &localCacheStore}
+
+	// Groups
+	if localCacheStore.groupsByUserCache, err = cacheProvider.NewCache(&cache.CacheOptions{
+		Size:                   GroupsByUserCacheSize,
+		Name:                   "GroupsByUser",
+		DefaultExpiry:          GroupsByUserCacheSec * time.Second,
+		InvalidateClusterEvent: model.ClusterEventInvalidateCacheForGroupsByUser,
+	}); err != nil {
+		return
+	}
+	localCacheStore.group = LocalCacheGroupStore{GroupStore: baseStore.Group(), rootStore: &localCacheStore}
+
 	if cluster != nil {
 		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForReactions, localCacheStore.reaction.handleClusterInvalidateReaction)
 		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForRoles, localCacheStore.role.handleClusterInvalidateRole)
@@ -395,6 +463,10 @@ func NewLocalCacheLayer(baseStore store.Store, metrics einterfaces.MetricsInterf
 		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForProfileInChannel, localCacheStore.user.handleClusterInvalidateProfilesInChannel)
 		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForAllProfiles, localCacheStore.user.handleClusterInvalidateAllProfiles)
 		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForTeams, localCacheStore.team.handleClusterInvalidateTeam)
+		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForWhitelist, localCacheStore.whitelist.handleClusterInvalidateWhitelist)
+		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForWhitelistPolicies, localCacheStore.whitelistPolicy.handleClusterInvalidateWhitelistPolicies)
+		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForLoginBans, localCacheStore.loginBan.handleClusterInvalidateLoginBans)
+		cluster.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForGroupsByUser, localCacheStore.group.handleClusterInvalidateGroupsByUser)
 	}
 	return
 }
@@ -447,6 +519,22 @@ func (s LocalCacheStore) Invite() store.InviteStore {
 	return s.invite
 }
 
+func (s LocalCacheStore) Whitelist() store.WhitelistStore {
+	return s.whitelist
+}
+
+func (s LocalCacheStore) WhitelistPolicy() store.WhitelistPolicyStore {
+	return s.whitelistPolicy
+}
+
+func (s LocalCacheStore) LoginBan() store.LoginBanStore {
+	return s.loginBan
+}
+
+func (s LocalCacheStore) Group() store.GroupStore {
+	return s.group
+}
+
 func (s LocalCacheStore) DropAllTables() {
 	s.Invalidate()
 	s.Store.DropAllTables()
@@ -580,6 +668,10 @@ func (s *LocalCacheStore) Invalidate() {
 	s.doClearCacheCluster(s.profilesInChannelCache)
 	s.doClearCacheCluster(s.teamAllTeamIdsForUserCache)
 	s.doClearCacheCluster(s.rolePermissionsCache)
+	s.doClearCacheCluster(s.whitelistCache)
+	s.doClearCacheCluster(s.whitelistPolicyCache)
+	s.doClearCacheCluster(s.loginBanCache)
+	s.doClearCacheCluster(s.groupsByUserCache)
 }
 
 // allocateCacheTargets is used to fill target value types
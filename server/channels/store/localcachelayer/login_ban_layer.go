@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const loginBansCacheKey = "active"
+
+type LocalCacheLoginBanStore struct {
+	store.LoginBanStore
+	rootStore *LocalCacheStore
+}
+
+func (s *LocalCacheLoginBanStore) handleClusterInvalidateLoginBans(msg *model.ClusterMessage) {
+	s.rootStore.loginBanCache.Purge()
+}
+
+func (s LocalCacheLoginBanStore) ClearCaches() {
+	s.rootStore.doClearCacheCluster(s.rootStore.loginBanCache)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.loginBanCache.Name())
+	}
+}
+
+// GetActive caches the bans that were active when they were read, as they are
+// checked on every login, and filters out the ones that expired since, so
+// expiry needs no invalidation.
+func (s LocalCacheLoginBanStore) GetActive(now int64) ([]*model.LoginBan, error) {
+	var bans []*model.LoginBan
+	if err := s.rootStore.doStandardReadCache(s.rootStore.loginBanCache, loginBansCacheKey, &bans); err != nil {
+		if bans, err = s.LoginBanStore.GetActive(now); err != nil {
+			return nil, err
+		}
+
+		s.rootStore.doStandardAddToCache(s.rootStore.loginBanCache, loginBansCacheKey, bans)
+	}
+
+	active := make([]*model.LoginBan, 0, len(bans))
+	for _, ban := range bans {
+		if ban.ExpireAt > now {
+			active = append(active, ban)
+		}
+	}
+	return active, nil
+}
+
+func (s LocalCacheLoginBanStore) Save(ban *model.LoginBan) (*model.LoginBan, error) {
+	saved, err := s.LoginBanStore.Save(ban)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClearCaches()
+	return saved, nil
+}
+
+func (s LocalCacheLoginBanStore) Delete(ip string) error {
+	if err := s.LoginBanStore.Delete(ip); err != nil {
+		return err
+	}
+
+	s.ClearCaches()
+	return nil
+}
+
+func (s LocalCacheLoginBanStore) DeleteAll() error {
+	if err := s.LoginBanStore.DeleteAll(); err != nil {
+		return err
+	}
+
+	s.ClearCaches()
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func TestLoginBanStore(t *testing.T) {
+	StoreTestWithSqlStore(t, storetest.TestLoginBanStore)
+}
+
+func TestLoginBanStoreCache(t *testing.T) {
+	fakeLoginBan := model.LoginBan{IP: "203.0.113.0/24", Reason: model.LoginBanReasonSubnet, FailedAttempts: 100, CreateAt: 1000, ExpireAt: 5000}
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("first call not cached, second cached", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		bans, err := cachedStore.LoginBan().GetActive(2000)
+		require.NoError(t, err)
+		require.Len(t, bans, 1)
+		mockStore.LoginBan().(*mocks.LoginBanStore).AssertNumberOfCalls(t, "GetActive", 1)
+
+		cachedBans, err := cachedStore.LoginBan().GetActive(3000)
+		require.NoError(t, err)
+		assert.Equal(t, bans, cachedBans)
+		mockStore.LoginBan().(*mocks.LoginBanStore).AssertNumberOfCalls(t, "GetActive", 1)
+	})
+
+	t.Run("cached bans that expired are filtered out", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.LoginBan().GetActive(2000)
+		bans, err := cachedStore.LoginBan().GetActive(5000)
+		require.NoError(t, err)
+		assert.Empty(t, bans)
+		mockStore.LoginBan().(*mocks.LoginBanStore).AssertNumberOfCalls(t, "GetActive", 1)
+	})
+
+	for name, change := range map[string]func(s LocalCacheStore) error{
+		"save ban": func(s LocalCacheStore) error {
+			_, err := s.LoginBan().Save(&fakeLoginBan)
+			return err
+		},
+		"delete ban": func(s LocalCacheStore) error {
+			return s.LoginBan().Delete("203.0.113.0/24")
+		},
+		"delete all bans": func(s LocalCacheStore) error {
+			return s.LoginBan().DeleteAll()
+		},
+	} {
+		t.Run("first call not cached, "+name+", and then not cached again", func(t *testing.T) {
+			mockStore := getMockStore(t)
+			mockCacheProvider := getMockCacheProvider()
+			cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+			require.NoError(t, err)
+
+			cachedStore.LoginBan().GetActive(2000)
+			mockStore.LoginBan().(*mocks.LoginBanStore).AssertNumberOfCalls(t, "GetActive", 1)
+			require.NoError(t, change(cachedStore))
+			cachedStore.LoginBan().GetActive(2000)
+			mockStore.LoginBan().(*mocks.LoginBanStore).AssertNumberOfCalls(t, "GetActive", 2)
+		})
+	}
+}
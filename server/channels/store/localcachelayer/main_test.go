@@ -188,6 +188,41 @@ func getMockStore(t *testing.T) *mocks.Store {
 	mockTeamStore.On("GetUserTeamIds", "123", false).Return(fakeUserTeamIds, nil)
 	mockStore.On("Team").Return(&mockTeamStore)
 
+	mockStore.On("Invite").Return(&mocks.InviteStore{})
+
+	fakeWhitelistItem := model.WhitelistItem{UserId: "123", IP: "192.168.0.0/16"}
+	mockWhitelistStore := mocks.WhitelistStore{}
+	mockWhitelistStore.On("GetPrefixSetByUserId", "123").Return(model.NewWhitelistPrefixSet([]*model.WhitelistItem{&fakeWhitelistItem}), nil)
+	mockWhitelistStore.On("Add", &fakeWhitelistItem).Return(nil)
+	mockWhitelistStore.On("Delete", &fakeWhitelistItem).Return(nil)
+	mockStore.On("Whitelist").Return(&mockWhitelistStore)
+
+	fakeWhitelistPolicy := model.WhitelistPolicy{Id: "policy", Name: "office", IPRanges: model.StringArray{"10.0.0.0/8"}}
+	fakeWhitelistPolicyTarget := model.WhitelistPolicyTarget{PolicyId: "policy", TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: "team1"}
+	mockWhitelistPolicyStore := mocks.WhitelistPolicyStore{}
+	mockWhitelistPolicyStore.On("GetForTargets", mock.Anything, mock.Anything, mock.Anything).Return([]*model.WhitelistPolicy{&fakeWhitelistPolicy}, nil)
+	mockWhitelistPolicyStore.On("Update", &fakeWhitelistPolicy).Return(&fakeWhitelistPolicy, nil)
+	mockWhitelistPolicyStore.On("Delete", "policy").Return(nil)
+	mockWhitelistPolicyStore.On("AddTarget", &fakeWhitelistPolicyTarget).Return(nil)
+	mockWhitelistPolicyStore.On("RemoveTarget", &fakeWhitelistPolicyTarget).Return(nil)
+	mockStore.On("WhitelistPolicy").Return(&mockWhitelistPolicyStore)
+
+	fakeLoginBan := model.LoginBan{IP: "203.0.113.0/24", Reason: model.LoginBanReasonSubnet, FailedAttempts: 100, CreateAt: 1000, ExpireAt: 5000}
+	mockLoginBanStore := mocks.LoginBanStore{}
+	mockLoginBanStore.On("GetActive", mock.Anything).Return([]*model.LoginBan{&fakeLoginBan}, nil)
+	mockLoginBanStore.On("Save", &fakeLoginBan).Return(&fakeLoginBan, nil)
+	mockLoginBanStore.On("Delete", "203.0.113.0/24").Return(nil)
+	mockLoginBanStore.On("DeleteAll").Return(nil)
+	mockStore.On("LoginBan").Return(&mockLoginBanStore)
+
+	fakeGroup := model.Group{Id: "group1", Name: model.NewPointer("group1")}
+	mockGroupStore := mocks.GroupStore{}
+	mockGroupStore.On("GetByUser", "123", mock.Anything).Return([]*model.Group{&fakeGroup}, nil)
+	mockGroupStore.On("UpsertMember", "group1", "123").Return(&model.GroupMember{GroupId: "group1", UserId: "123"}, nil)
+	mockGroupStore.On("DeleteMember", "group1", "123").Return(&model.GroupMember{GroupId: "group1", UserId: "123"}, nil)
+	mockGroupStore.On("Delete", "group1").Return(&fakeGroup, nil)
+	mockStore.On("Group").Return(&mockGroupStore)
+
 	return &mockStore
 }
 
@@ -53,7 +53,7 @@ func (s LocalCacheReactionStore) GetForPost(postId string, allowFromCache bool)
 	return reaction, nil
 }
 
-func (s LocalCacheReactionStore) DeleteAllWithEmojiName(emojiName string) error {
+func (s LocalCacheReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
 	// This could be improved. Right now we just clear the whole
 	// cache because we don't have a way find what post Ids have this emoji name.
 	defer s.rootStore.doClearCacheCluster(s.rootStore.reactionCache)
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"bytes"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type LocalCacheWhitelistStore struct {
+	store.WhitelistStore
+	rootStore *LocalCacheStore
+}
+
+func (s *LocalCacheWhitelistStore) handleClusterInvalidateWhitelist(msg *model.ClusterMessage) {
+	if bytes.Equal(msg.Data, clearCacheMessageData) {
+		s.rootStore.whitelistCache.Purge()
+	} else {
+		s.rootStore.whitelistCache.Remove(string(msg.Data))
+	}
+}
+
+func (s LocalCacheWhitelistStore) ClearCaches() {
+	s.rootStore.doClearCacheCluster(s.rootStore.whitelistCache)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.whitelistCache.Name())
+	}
+}
+
+func (s LocalCacheWhitelistStore) InvalidateWhitelistCacheForUser(userId string) {
+	s.rootStore.doInvalidateCacheCluster(s.rootStore.whitelistCache, userId, nil)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.whitelistCache.Name())
+	}
+}
+
+// GetPrefixSetByUserId caches the compiled set including expired entries, which
+// are filtered out when the set is checked, so expiry needs no invalidation.
+func (s LocalCacheWhitelistStore) GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error) {
+	var prefixSet *model.WhitelistPrefixSet
+	if err := s.rootStore.doStandardReadCache(s.rootStore.whitelistCache, userId, &prefixSet); err == nil {
+		return prefixSet, nil
+	}
+
+	prefixSet, err := s.WhitelistStore.GetPrefixSetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rootStore.doStandardAddToCache(s.rootStore.whitelistCache, userId, prefixSet)
+
+	return prefixSet, nil
+}
+
+func (s LocalCacheWhitelistStore) Add(whitelistItem *model.WhitelistItem) error {
+	if err := s.WhitelistStore.Add(whitelistItem); err != nil {
+		return err
+	}
+
+	s.InvalidateWhitelistCacheForUser(whitelistItem.UserId)
+	return nil
+}
+
+func (s LocalCacheWhitelistStore) Delete(whitelistItem *model.WhitelistItem) error {
+	if err := s.WhitelistStore.Delete(whitelistItem); err != nil {
+		return err
+	}
+
+	s.InvalidateWhitelistCacheForUser(whitelistItem.UserId)
+	return nil
+}
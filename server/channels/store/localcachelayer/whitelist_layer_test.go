@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func TestWhitelistStore(t *testing.T) {
+	StoreTestWithSqlStore(t, storetest.TestWhitelistStore)
+}
+
+func TestWhitelistStoreCache(t *testing.T) {
+	fakeWhitelistItem := model.WhitelistItem{UserId: "123", IP: "192.168.0.0/16"}
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		prefixSet, err := cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		require.NoError(t, err)
+		require.Len(t, prefixSet.Entries, 1)
+		assert.Equal(t, "192.168.0.0/16", prefixSet.Entries[0].Prefix.String())
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 1)
+
+		cachedPrefixSet, err := cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		require.NoError(t, err)
+		assert.Equal(t, prefixSet, cachedPrefixSet)
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 1)
+	})
+
+	t.Run("first call not cached, add entry, and then not cached again", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 1)
+		require.NoError(t, cachedStore.Whitelist().Add(&fakeWhitelistItem))
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 2)
+	})
+
+	t.Run("first call not cached, delete entry, and then not cached again", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 1)
+		require.NoError(t, cachedStore.Whitelist().Delete(&fakeWhitelistItem))
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 2)
+	})
+
+	t.Run("first call not cached, clear caches, and then not cached again", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 1)
+		cachedStore.Whitelist().ClearCaches()
+		cachedStore.Whitelist().GetPrefixSetByUserId("123")
+		mockStore.Whitelist().(*mocks.WhitelistStore).AssertNumberOfCalls(t, "GetPrefixSetByUserId", 2)
+	})
+}
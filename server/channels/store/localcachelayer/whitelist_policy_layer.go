@@ -0,0 +1,105 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type LocalCacheWhitelistPolicyStore struct {
+	store.WhitelistPolicyStore
+	rootStore *LocalCacheStore
+}
+
+func (s *LocalCacheWhitelistPolicyStore) handleClusterInvalidateWhitelistPolicies(msg *model.ClusterMessage) {
+	if bytes.Equal(msg.Data, clearCacheMessageData) {
+		s.rootStore.whitelistPolicyCache.Purge()
+	} else {
+		s.rootStore.whitelistPolicyCache.Remove(string(msg.Data))
+	}
+}
+
+// ClearCaches drops every cached lookup, since a change to one policy or target
+// can affect the result for any combination of teams, groups and roles.
+func (s LocalCacheWhitelistPolicyStore) ClearCaches() {
+	s.rootStore.doClearCacheCluster(s.rootStore.whitelistPolicyCache)
+
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.whitelistPolicyCache.Name())
+	}
+}
+
+// whitelistPolicyTargetsKey builds a cache key that does not depend on the order of the ids.
+func whitelistPolicyTargetsKey(teamIds, groupIds, roleNames []string) string {
+	hash := sha256.New()
+	for _, ids := range [][]string{teamIds, groupIds, roleNames} {
+		sorted := slices.Clone(ids)
+		slices.Sort(sorted)
+		hash.Write([]byte(strings.Join(sorted, ",")))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (s LocalCacheWhitelistPolicyStore) GetForTargets(teamIds, groupIds, roleNames []string) ([]*model.WhitelistPolicy, error) {
+	key := whitelistPolicyTargetsKey(teamIds, groupIds, roleNames)
+
+	var policies []*model.WhitelistPolicy
+	if err := s.rootStore.doStandardReadCache(s.rootStore.whitelistPolicyCache, key, &policies); err == nil {
+		return policies, nil
+	}
+
+	policies, err := s.WhitelistPolicyStore.GetForTargets(teamIds, groupIds, roleNames)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rootStore.doStandardAddToCache(s.rootStore.whitelistPolicyCache, key, policies)
+
+	return policies, nil
+}
+
+func (s LocalCacheWhitelistPolicyStore) Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	updated, err := s.WhitelistPolicyStore.Update(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ClearCaches()
+	return updated, nil
+}
+
+func (s LocalCacheWhitelistPolicyStore) Delete(id string) error {
+	if err := s.WhitelistPolicyStore.Delete(id); err != nil {
+		return err
+	}
+
+	s.ClearCaches()
+	return nil
+}
+
+func (s LocalCacheWhitelistPolicyStore) AddTarget(target *model.WhitelistPolicyTarget) error {
+	if err := s.WhitelistPolicyStore.AddTarget(target); err != nil {
+		return err
+	}
+
+	s.ClearCaches()
+	return nil
+}
+
+func (s LocalCacheWhitelistPolicyStore) RemoveTarget(target *model.WhitelistPolicyTarget) error {
+	if err := s.WhitelistPolicyStore.RemoveTarget(target); err != nil {
+		return err
+	}
+
+	s.ClearCaches()
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package localcachelayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+)
+
+func TestWhitelistPolicyStore(t *testing.T) {
+	StoreTestWithSqlStore(t, storetest.TestWhitelistPolicyStore)
+}
+
+func TestWhitelistPolicyStoreCache(t *testing.T) {
+	fakeWhitelistPolicy := model.WhitelistPolicy{Id: "policy", Name: "office", IPRanges: model.StringArray{"10.0.0.0/8"}}
+	fakeWhitelistPolicyTarget := model.WhitelistPolicyTarget{PolicyId: "policy", TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: "team1"}
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("first call not cached, second cached regardless of the order of the targets", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		policies, err := cachedStore.WhitelistPolicy().GetForTargets([]string{"team1", "team2"}, nil, []string{"system_user"})
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		mockStore.WhitelistPolicy().(*mocks.WhitelistPolicyStore).AssertNumberOfCalls(t, "GetForTargets", 1)
+
+		cachedPolicies, err := cachedStore.WhitelistPolicy().GetForTargets([]string{"team2", "team1"}, nil, []string{"system_user"})
+		require.NoError(t, err)
+		assert.Equal(t, policies, cachedPolicies)
+		mockStore.WhitelistPolicy().(*mocks.WhitelistPolicyStore).AssertNumberOfCalls(t, "GetForTargets", 1)
+
+		cachedStore.WhitelistPolicy().GetForTargets([]string{"team1"}, nil, []string{"system_user"})
+		mockStore.WhitelistPolicy().(*mocks.WhitelistPolicyStore).AssertNumberOfCalls(t, "GetForTargets", 2)
+	})
+
+	for name, change := range map[string]func(s LocalCacheStore) error{
+		"update policy": func(s LocalCacheStore) error {
+			_, err := s.WhitelistPolicy().Update(&fakeWhitelistPolicy)
+			return err
+		},
+		"delete policy": func(s LocalCacheStore) error {
+			return s.WhitelistPolicy().Delete("policy")
+		},
+		"add target": func(s LocalCacheStore) error {
+			return s.WhitelistPolicy().AddTarget(&fakeWhitelistPolicyTarget)
+		},
+		"remove target": func(s LocalCacheStore) error {
+			return s.WhitelistPolicy().RemoveTarget(&fakeWhitelistPolicyTarget)
+		},
+	} {
+		t.Run("first call not cached, "+name+", and then not cached again", func(t *testing.T) {
+			mockStore := getMockStore(t)
+			mockCacheProvider := getMockCacheProvider()
+			cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+			require.NoError(t, err)
+
+			cachedStore.WhitelistPolicy().GetForTargets([]string{"team1"}, nil, nil)
+			mockStore.WhitelistPolicy().(*mocks.WhitelistPolicyStore).AssertNumberOfCalls(t, "GetForTargets", 1)
+			require.NoError(t, change(cachedStore))
+			cachedStore.WhitelistPolicy().GetForTargets([]string{"team1"}, nil, nil)
+			mockStore.WhitelistPolicy().(*mocks.WhitelistPolicyStore).AssertNumberOfCalls(t, "GetForTargets", 2)
+		})
+	}
+}
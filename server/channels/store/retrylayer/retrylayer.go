@@ -43,6 +43,7 @@ type RetryLayer struct {
 	JobStore                        store.JobStore
 	LicenseStore                    store.LicenseStore
 	LinkMetadataStore               store.LinkMetadataStore
+	LoginBanStore                   store.LoginBanStore
 	NotifyAdminStore                store.NotifyAdminStore
 	OAuthStore                      store.OAuthStore
 	OutgoingOAuthConnectionStore    store.OutgoingOAuthConnectionStore
@@ -60,6 +61,7 @@ type RetryLayer struct {
 	RemoteClusterStore              store.RemoteClusterStore
 	RetentionPolicyStore            store.RetentionPolicyStore
 	RoleStore                       store.RoleStore
+	SavedSearchStore                store.SavedSearchStore
 	ScheduledPostStore              store.ScheduledPostStore
 	SchemeStore                     store.SchemeStore
 	SessionStore                    store.SessionStore
@@ -76,6 +78,7 @@ type RetryLayer struct {
 	UserTermsOfServiceStore         store.UserTermsOfServiceStore
 	WebhookStore                    store.WebhookStore
 	WhitelistStore                  store.WhitelistStore
+	WhitelistPolicyStore            store.WhitelistPolicyStore
 }
 
 func (s *RetryLayer) AccessControlPolicy() store.AccessControlPolicyStore {
@@ -158,6 +161,10 @@ func (s *RetryLayer) LinkMetadata() store.LinkMetadataStore {
 	return s.LinkMetadataStore
 }
 
+func (s *RetryLayer) LoginBan() store.LoginBanStore {
+	return s.LoginBanStore
+}
+
 func (s *RetryLayer) NotifyAdmin() store.NotifyAdminStore {
 	return s.NotifyAdminStore
 }
@@ -226,6 +233,10 @@ func (s *RetryLayer) Role() store.RoleStore {
 	return s.RoleStore
 }
 
+func (s *RetryLayer) SavedSearch() store.SavedSearchStore {
+	return s.SavedSearchStore
+}
+
 func (s *RetryLayer) ScheduledPost() store.ScheduledPostStore {
 	return s.ScheduledPostStore
 }
@@ -290,6 +301,10 @@ func (s *RetryLayer) Whitelist() store.WhitelistStore {
 	return s.WhitelistStore
 }
 
+func (s *RetryLayer) WhitelistPolicy() store.WhitelistPolicyStore {
+	return s.WhitelistPolicyStore
+}
+
 type RetryLayerAccessControlPolicyStore struct {
 	store.AccessControlPolicyStore
 	Root *RetryLayer
@@ -390,6 +405,11 @@ type RetryLayerLinkMetadataStore struct {
 	Root *RetryLayer
 }
 
+type RetryLayerLoginBanStore struct {
+	store.LoginBanStore
+	Root *RetryLayer
+}
+
 type RetryLayerNotifyAdminStore struct {
 	store.NotifyAdminStore
 	Root *RetryLayer
@@ -475,6 +495,11 @@ type RetryLayerRoleStore struct {
 	Root *RetryLayer
 }
 
+type RetryLayerSavedSearchStore struct {
+	store.SavedSearchStore
+	Root *RetryLayer
+}
+
 type RetryLayerScheduledPostStore struct {
 	store.ScheduledPostStore
 	Root *RetryLayer
@@ -555,6 +580,11 @@ type RetryLayerWhitelistStore struct {
 	Root *RetryLayer
 }
 
+type RetryLayerWhitelistPolicyStore struct {
+	store.WhitelistPolicyStore
+	Root *RetryLayer
+}
+
 func isRepeatableError(err error) bool {
 	var pqErr *pq.Error
 	var mysqlErr *mysql.MySQLError
@@ -4822,6 +4852,27 @@ func (s *RetryLayerFileInfoStore) GetFilesBatchForIndexing(startTime int64, star
 
 }
 
+func (s *RetryLayerFileInfoStore) GetFilteredFilesBatchForIndexing(startTime int64, startFileID string, filter store.IndexingBatchFilter, limit int) ([]*model.FileForIndexing, error) {
+
+	tries := 0
+	for {
+		result, err := s.FileInfoStore.GetFilteredFilesBatchForIndexing(startTime, startFileID, filter, limit)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerFileInfoStore) GetForPost(postID string, readFromMaster bool, includeDeleted bool, allowFromCache bool) ([]*model.FileInfo, error) {
 
 	tries := 0
@@ -5143,6 +5194,27 @@ func (s *RetryLayerFileInfoStore) SetContent(ctx request.CTX, fileID string, con
 
 }
 
+func (s *RetryLayerFileInfoStore) SetContentExtractionFailure(ctx request.CTX, fileID string, extractionErr string) error {
+
+	tries := 0
+	for {
+		err := s.FileInfoStore.SetContentExtractionFailure(ctx, fileID, extractionErr)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 
 	tries := 0
@@ -6823,6 +6895,111 @@ func (s *RetryLayerLinkMetadataStore) Save(linkMetadata *model.LinkMetadata) (*m
 
 }
 
+func (s *RetryLayerLoginBanStore) Delete(ip string) error {
+
+	tries := 0
+	for {
+		err := s.LoginBanStore.Delete(ip)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerLoginBanStore) DeleteAll() error {
+
+	tries := 0
+	for {
+		err := s.LoginBanStore.DeleteAll()
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerLoginBanStore) DeleteExpired(now int64) (int64, error) {
+
+	tries := 0
+	for {
+		result, err := s.LoginBanStore.DeleteExpired(now)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerLoginBanStore) GetActive(now int64) ([]*model.LoginBan, error) {
+
+	tries := 0
+	for {
+		result, err := s.LoginBanStore.GetActive(now)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerLoginBanStore) Save(ban *model.LoginBan) (*model.LoginBan, error) {
+
+	tries := 0
+	for {
+		result, err := s.LoginBanStore.Save(ban)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) error {
 
 	tries := 0
@@ -7795,6 +7972,27 @@ func (s *RetryLayerPostStore) Get(ctx context.Context, id string, opts model.Get
 
 }
 
+func (s *RetryLayerPostStore) GetAllPosts(options *model.GetAllPostsOptions) (*model.PostList, int, error) {
+
+	tries := 0
+	for {
+		result, resultVar1, err := s.PostStore.GetAllPosts(options)
+		if err == nil {
+			return result, resultVar1, nil
+		}
+		if !isRepeatableError(err) {
+			return result, resultVar1, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, resultVar1, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerPostStore) GetDirectPostParentsForExportAfter(limit int, afterID string, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
 
 	tries := 0
@@ -7843,6 +8041,27 @@ func (s *RetryLayerPostStore) GetEtag(channelID string, allowFromCache bool, col
 
 }
 
+func (s *RetryLayerPostStore) GetFilteredPostsBatchForIndexing(startTime int64, startPostID string, filter store.IndexingBatchFilter, limit int) ([]*model.PostForIndexing, error) {
+
+	tries := 0
+	for {
+		result, err := s.PostStore.GetFilteredPostsBatchForIndexing(startTime, startPostID, filter, limit)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerPostStore) GetFlaggedPosts(userID string, offset int, limit int) (*model.PostList, error) {
 
 	tries := 0
@@ -8527,6 +8746,27 @@ func (s *RetryLayerPostStore) RefreshPostStats() error {
 
 }
 
+func (s *RetryLayerPostStore) RemovePostsBetween(options *model.RemovePostsBetweenOptions) ([]*model.Post, error) {
+
+	tries := 0
+	for {
+		result, err := s.PostStore.RemovePostsBetween(options)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayerPostStore) Save(rctx request.CTX, post *model.Post) (*model.Post, error) {
 
 	tries := 0
@@ -9850,21 +10090,21 @@ func (s *RetryLayerReactionStore) Delete(reaction *model.Reaction) (*model.React
 
 }
 
-func (s *RetryLayerReactionStore) DeleteAllWithEmojiName(emojiName string) error {
+func (s *RetryLayerReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
 
 	tries := 0
 	for {
-		err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
+		result, err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
 		if err == nil {
-			return nil
+			return result, nil
 		}
 		if !isRepeatableError(err) {
-			return err
+			return result, err
 		}
 		tries++
 		if tries >= 3 {
 			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
-			return err
+			return result, err
 		}
 		timepkg.Sleep(100 * timepkg.Millisecond)
 	}
@@ -10018,21 +10258,21 @@ func (s *RetryLayerReactionStore) PermanentDeleteBatch(endTime int64, limit int6
 
 }
 
-func (s *RetryLayerReactionStore) PermanentDeleteByUser(userID string) error {
+func (s *RetryLayerReactionStore) PermanentDeleteByUser(userID string) ([]string, error) {
 
 	tries := 0
 	for {
-		err := s.ReactionStore.PermanentDeleteByUser(userID)
+		result, err := s.ReactionStore.PermanentDeleteByUser(userID)
 		if err == nil {
-			return nil
+			return result, nil
 		}
 		if !isRepeatableError(err) {
-			return err
+			return result, err
 		}
 		tries++
 		if tries >= 3 {
 			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
-			return err
+			return result, err
 		}
 		timepkg.Sleep(100 * timepkg.Millisecond)
 	}
@@ -10879,32 +11119,32 @@ func (s *RetryLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
 
 }
 
-func (s *RetryLayerScheduledPostStore) CreateScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
+func (s *RetryLayerSavedSearchStore) Delete(id string) error {
 
 	tries := 0
 	for {
-		result, err := s.ScheduledPostStore.CreateScheduledPost(scheduledPost)
+		err := s.SavedSearchStore.Delete(id)
 		if err == nil {
-			return result, nil
+			return nil
 		}
 		if !isRepeatableError(err) {
-			return result, err
+			return err
 		}
 		tries++
 		if tries >= 3 {
 			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
-			return result, err
+			return err
 		}
 		timepkg.Sleep(100 * timepkg.Millisecond)
 	}
 
 }
 
-func (s *RetryLayerScheduledPostStore) Get(scheduledPostId string) (*model.ScheduledPost, error) {
+func (s *RetryLayerSavedSearchStore) Get(id string) (*model.SavedSearch, error) {
 
 	tries := 0
 	for {
-		result, err := s.ScheduledPostStore.Get(scheduledPostId)
+		result, err := s.SavedSearchStore.Get(id)
 		if err == nil {
 			return result, nil
 		}
@@ -10921,17 +11161,11 @@ func (s *RetryLayerScheduledPostStore) Get(scheduledPostId string) (*model.Sched
 
 }
 
-func (s *RetryLayerScheduledPostStore) GetMaxMessageSize() int {
-
-	return s.ScheduledPostStore.GetMaxMessageSize()
-
-}
-
-func (s *RetryLayerScheduledPostStore) GetPendingScheduledPosts(beforeTime int64, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error) {
+func (s *RetryLayerSavedSearchStore) GetAlertsForTeam(teamID string) ([]*model.SavedSearch, error) {
 
 	tries := 0
 	for {
-		result, err := s.ScheduledPostStore.GetPendingScheduledPosts(beforeTime, afterTime, lastScheduledPostId, perPage)
+		result, err := s.SavedSearchStore.GetAlertsForTeam(teamID)
 		if err == nil {
 			return result, nil
 		}
@@ -10948,11 +11182,11 @@ func (s *RetryLayerScheduledPostStore) GetPendingScheduledPosts(beforeTime int64
 
 }
 
-func (s *RetryLayerScheduledPostStore) GetScheduledPostsForUser(userId string, teamId string) ([]*model.ScheduledPost, error) {
+func (s *RetryLayerSavedSearchStore) GetForUser(userID string) ([]*model.SavedSearch, error) {
 
 	tries := 0
 	for {
-		result, err := s.ScheduledPostStore.GetScheduledPostsForUser(userId, teamId)
+		result, err := s.SavedSearchStore.GetForUser(userID)
 		if err == nil {
 			return result, nil
 		}
@@ -10969,11 +11203,11 @@ func (s *RetryLayerScheduledPostStore) GetScheduledPostsForUser(userId string, t
 
 }
 
-func (s *RetryLayerScheduledPostStore) PermanentDeleteByUser(userId string) error {
+func (s *RetryLayerSavedSearchStore) PermanentDeleteByUser(userID string) error {
 
 	tries := 0
 	for {
-		err := s.ScheduledPostStore.PermanentDeleteByUser(userId)
+		err := s.SavedSearchStore.PermanentDeleteByUser(userID)
 		if err == nil {
 			return nil
 		}
@@ -10990,49 +11224,223 @@ func (s *RetryLayerScheduledPostStore) PermanentDeleteByUser(userId string) erro
 
 }
 
-func (s *RetryLayerScheduledPostStore) PermanentlyDeleteScheduledPosts(scheduledPostIDs []string) error {
+func (s *RetryLayerSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
 
 	tries := 0
 	for {
-		err := s.ScheduledPostStore.PermanentlyDeleteScheduledPosts(scheduledPostIDs)
+		result, err := s.SavedSearchStore.Save(savedSearch)
 		if err == nil {
-			return nil
+			return result, nil
 		}
 		if !isRepeatableError(err) {
-			return err
+			return result, err
 		}
 		tries++
 		if tries >= 3 {
 			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
-			return err
+			return result, err
 		}
 		timepkg.Sleep(100 * timepkg.Millisecond)
 	}
 
 }
 
-func (s *RetryLayerScheduledPostStore) UpdateOldScheduledPosts(beforeTime int64) error {
+func (s *RetryLayerSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
 
 	tries := 0
 	for {
-		err := s.ScheduledPostStore.UpdateOldScheduledPosts(beforeTime)
+		result, err := s.SavedSearchStore.Update(savedSearch)
 		if err == nil {
-			return nil
+			return result, nil
 		}
 		if !isRepeatableError(err) {
-			return err
+			return result, err
 		}
 		tries++
 		if tries >= 3 {
 			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
-			return err
+			return result, err
 		}
 		timepkg.Sleep(100 * timepkg.Millisecond)
 	}
 
 }
 
-func (s *RetryLayerScheduledPostStore) UpdatedScheduledPost(scheduledPost *model.ScheduledPost) error {
+func (s *RetryLayerScheduledPostStore) CreateScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
+
+	tries := 0
+	for {
+		result, err := s.ScheduledPostStore.CreateScheduledPost(scheduledPost)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) Get(scheduledPostId string) (*model.ScheduledPost, error) {
+
+	tries := 0
+	for {
+		result, err := s.ScheduledPostStore.Get(scheduledPostId)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) GetMaxMessageSize() int {
+
+	return s.ScheduledPostStore.GetMaxMessageSize()
+
+}
+
+func (s *RetryLayerScheduledPostStore) GetPendingScheduledPosts(beforeTime int64, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error) {
+
+	tries := 0
+	for {
+		result, err := s.ScheduledPostStore.GetPendingScheduledPosts(beforeTime, afterTime, lastScheduledPostId, perPage)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) GetScheduledPostsForChannel(channelId string) ([]*model.ScheduledPost, error) {
+
+	tries := 0
+	for {
+		result, err := s.ScheduledPostStore.GetScheduledPostsForChannel(channelId)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) GetScheduledPostsForUser(userId string, teamId string) ([]*model.ScheduledPost, error) {
+
+	tries := 0
+	for {
+		result, err := s.ScheduledPostStore.GetScheduledPostsForUser(userId, teamId)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) PermanentDeleteByUser(userId string) error {
+
+	tries := 0
+	for {
+		err := s.ScheduledPostStore.PermanentDeleteByUser(userId)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) PermanentlyDeleteScheduledPosts(scheduledPostIDs []string) error {
+
+	tries := 0
+	for {
+		err := s.ScheduledPostStore.PermanentlyDeleteScheduledPosts(scheduledPostIDs)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) UpdateOldScheduledPosts(beforeTime int64) error {
+
+	tries := 0
+	for {
+		err := s.ScheduledPostStore.UpdateOldScheduledPosts(beforeTime)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerScheduledPostStore) UpdatedScheduledPost(scheduledPost *model.ScheduledPost) error {
 
 	tries := 0
 	for {
@@ -16900,6 +17308,12 @@ func (s *RetryLayerWhitelistStore) Add(whitelistItem *model.WhitelistItem) error
 
 }
 
+func (s *RetryLayerWhitelistStore) ClearCaches() {
+
+	s.WhitelistStore.ClearCaches()
+
+}
+
 func (s *RetryLayerWhitelistStore) Delete(whitelistItem *model.WhitelistItem) error {
 
 	tries := 0
@@ -16921,7 +17335,70 @@ func (s *RetryLayerWhitelistStore) Delete(whitelistItem *model.WhitelistItem) er
 
 }
 
-func (s *RetryLayerWhitelistStore) GetByUserId(userId string) ([]string, error) {
+func (s *RetryLayerWhitelistStore) DeleteDenialsBefore(endTime int64, limit int64) (int64, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.DeleteDenialsBefore(endTime, limit)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) DeleteExpired(now int64) (int64, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.DeleteExpired(now)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) GetAllAfter(limit int, afterUserId string, afterIP string) ([]*model.WhitelistItemForExport, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.GetAllAfter(limit, afterUserId, afterIP)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) GetByUserId(userId string) ([]*model.WhitelistItem, error) {
 
 	tries := 0
 	for {
@@ -16942,6 +17419,306 @@ func (s *RetryLayerWhitelistStore) GetByUserId(userId string) ([]string, error)
 
 }
 
+func (s *RetryLayerWhitelistStore) GetDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.GetDenials(filter)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.GetPrefixSetByUserId(userId)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) IncrementDenial(id string, lastAt int64) error {
+
+	tries := 0
+	for {
+		err := s.WhitelistStore.IncrementDenial(id, lastAt)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistStore) InvalidateWhitelistCacheForUser(userId string) {
+
+	s.WhitelistStore.InvalidateWhitelistCacheForUser(userId)
+
+}
+
+func (s *RetryLayerWhitelistStore) SaveDenial(denial *model.WhitelistDenial) (*model.WhitelistDenial, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistStore.SaveDenial(denial)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) AddTarget(target *model.WhitelistPolicyTarget) error {
+
+	tries := 0
+	for {
+		err := s.WhitelistPolicyStore.AddTarget(target)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) Delete(id string) error {
+
+	tries := 0
+	for {
+		err := s.WhitelistPolicyStore.Delete(id)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) Get(id string) (*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.Get(id)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) GetAll(offset int, limit int) ([]*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.GetAll(offset, limit)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) GetByName(name string) (*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.GetByName(name)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) GetForTargets(teamIds []string, groupIds []string, roleNames []string) ([]*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.GetForTargets(teamIds, groupIds, roleNames)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) GetTargets(policyId string) ([]*model.WhitelistPolicyTarget, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.GetTargets(policyId)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) RemoveTarget(target *model.WhitelistPolicyTarget) error {
+
+	tries := 0
+	for {
+		err := s.WhitelistPolicyStore.RemoveTarget(target)
+		if err == nil {
+			return nil
+		}
+		if !isRepeatableError(err) {
+			return err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) Save(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.Save(policy)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
+func (s *RetryLayerWhitelistPolicyStore) Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+
+	tries := 0
+	for {
+		result, err := s.WhitelistPolicyStore.Update(policy)
+		if err == nil {
+			return result, nil
+		}
+		if !isRepeatableError(err) {
+			return result, err
+		}
+		tries++
+		if tries >= 3 {
+			err = errors.Wrap(err, "giving up after 3 consecutive repeatable transaction failures")
+			return result, err
+		}
+		timepkg.Sleep(100 * timepkg.Millisecond)
+	}
+
+}
+
 func (s *RetryLayer) Close() {
 	s.Store.Close()
 }
@@ -17003,6 +17780,7 @@ func New(childStore store.Store) *RetryLayer {
 	newStore.JobStore = &RetryLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
 	newStore.LicenseStore = &RetryLayerLicenseStore{LicenseStore: childStore.License(), Root: &newStore}
 	newStore.LinkMetadataStore = &RetryLayerLinkMetadataStore{LinkMetadataStore: childStore.LinkMetadata(), Root: &newStore}
+	newStore.LoginBanStore = &RetryLayerLoginBanStore{LoginBanStore: childStore.LoginBan(), Root: &newStore}
 	newStore.NotifyAdminStore = &RetryLayerNotifyAdminStore{NotifyAdminStore: childStore.NotifyAdmin(), Root: &newStore}
 	newStore.OAuthStore = &RetryLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
 	newStore.OutgoingOAuthConnectionStore = &RetryLayerOutgoingOAuthConnectionStore{OutgoingOAuthConnectionStore: childStore.OutgoingOAuthConnection(), Root: &newStore}
@@ -17020,6 +17798,7 @@ func New(childStore store.Store) *RetryLayer {
 	newStore.RemoteClusterStore = &RetryLayerRemoteClusterStore{RemoteClusterStore: childStore.RemoteCluster(), Root: &newStore}
 	newStore.RetentionPolicyStore = &RetryLayerRetentionPolicyStore{RetentionPolicyStore: childStore.RetentionPolicy(), Root: &newStore}
 	newStore.RoleStore = &RetryLayerRoleStore{RoleStore: childStore.Role(), Root: &newStore}
+	newStore.SavedSearchStore = &RetryLayerSavedSearchStore{SavedSearchStore: childStore.SavedSearch(), Root: &newStore}
 	newStore.ScheduledPostStore = &RetryLayerScheduledPostStore{ScheduledPostStore: childStore.ScheduledPost(), Root: &newStore}
 	newStore.SchemeStore = &RetryLayerSchemeStore{SchemeStore: childStore.Scheme(), Root: &newStore}
 	newStore.SessionStore = &RetryLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
@@ -17036,5 +17815,6 @@ func New(childStore store.Store) *RetryLayer {
 	newStore.UserTermsOfServiceStore = &RetryLayerUserTermsOfServiceStore{UserTermsOfServiceStore: childStore.UserTermsOfService(), Root: &newStore}
 	newStore.WebhookStore = &RetryLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
 	newStore.WhitelistStore = &RetryLayerWhitelistStore{WhitelistStore: childStore.Whitelist(), Root: &newStore}
+	newStore.WhitelistPolicyStore = &RetryLayerWhitelistPolicyStore{WhitelistPolicyStore: childStore.WhitelistPolicy(), Root: &newStore}
 	return &newStore
 }
@@ -191,7 +191,7 @@ func (s SearchFileInfoStore) Search(rctx request.CTX, paramsList []*model.Search
 			if nErr != nil {
 				return nil, nErr
 			}
-			fileIds, appErr := engine.SearchFiles(userChannels, paramsList, page, perPage)
+			fileIds, snippets, appErr := engine.SearchFiles(userChannels, paramsList, page, perPage)
 			if appErr != nil {
 				rctx.Logger().Error("Encountered error on Search.", mlog.String("search_engine", engine.GetName()), mlog.Err(appErr))
 				continue
@@ -207,6 +207,12 @@ func (s SearchFileInfoStore) Search(rctx request.CTX, paramsList []*model.Search
 				for _, f := range files {
 					filesList.AddFileInfo(f)
 					filesList.AddOrder(f.Id)
+					if fileSnippets, ok := snippets[f.Id]; ok {
+						if filesList.Snippets == nil {
+							filesList.Snippets = model.FileInfoSearchSnippets{}
+						}
+						filesList.Snippets[f.Id] = fileSnippets
+					}
 				}
 			}
 			return filesList, nil
@@ -21,6 +21,7 @@ type SearchStore struct {
 	channel      *SearchChannelStore
 	post         *SearchPostStore
 	fileInfo     *SearchFileInfoStore
+	reaction     *SearchReactionStore
 	configValue  atomic.Pointer[model.Config]
 }
 
@@ -35,6 +36,7 @@ func NewSearchLayer(baseStore store.Store, searchEngine *searchengine.Broker, cf
 	searchStore.team = &SearchTeamStore{TeamStore: baseStore.Team(), rootStore: searchStore}
 	searchStore.user = &SearchUserStore{UserStore: baseStore.User(), rootStore: searchStore}
 	searchStore.fileInfo = &SearchFileInfoStore{FileInfoStore: baseStore.FileInfo(), rootStore: searchStore}
+	searchStore.reaction = &SearchReactionStore{ReactionStore: baseStore.Reaction(), rootStore: searchStore}
 
 	return searchStore
 }
@@ -59,6 +61,10 @@ func (s *SearchStore) FileInfo() store.FileInfoStore {
 	return s.fileInfo
 }
 
+func (s *SearchStore) Reaction() store.ReactionStore {
+	return s.reaction
+}
+
 func (s *SearchStore) Team() store.TeamStore {
 	return s.team
 }
@@ -111,6 +117,17 @@ func (s *SearchStore) indexUser(rctx request.CTX, user *model.User) {
 	}
 }
 
+// isIndexingEnabled reports whether any of the active engines indexes, to
+// skip loading what only the engines need.
+func (s *SearchStore) isIndexingEnabled() bool {
+	for _, engine := range s.searchEngine.GetActiveEngines() {
+		if engine.IsIndexingEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
 // Runs an indexing function synchronously or asynchronously depending on the engine
 func runIndexFn(rctx request.CTX, engine searchengine.SearchEngineInterface, indexFn func(searchengine.SearchEngineInterface)) {
 	if engine.IsIndexingSync() {
@@ -4,6 +4,9 @@
 package searchlayer
 
 import (
+	"slices"
+	"sync"
+
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -18,16 +21,38 @@ type SearchPostStore struct {
 	rootStore *SearchStore
 }
 
-func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post) {
+// indexPost indexes the post in the engines with indexing enabled. The
+// search properties of the post are loaded once for all of them, and only
+// if saved is false: a post that was just saved has no reactions nor
+// replies yet, and carries its priority in its metadata.
+func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post, saved bool) {
+	if !s.rootStore.isIndexingEnabled() {
+		return
+	}
+
+	indexed := sync.OnceValue(func() *model.Post {
+		if saved {
+			return withSavedSearchProperties(post)
+		}
+		return s.withSearchProperties(rctx, post)
+	})
+	teamID := sync.OnceValues(func() (string, error) {
+		channel, err := s.rootStore.Channel().Get(post.ChannelId, true)
+		if err != nil {
+			return "", err
+		}
+		return channel.TeamId, nil
+	})
+
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsIndexingEnabled() {
 			runIndexFn(rctx, engine, func(engineCopy searchengine.SearchEngineInterface) {
-				channel, chanErr := s.rootStore.Channel().Get(post.ChannelId, true)
+				teamID, chanErr := teamID()
 				if chanErr != nil {
 					rctx.Logger().Error("Couldn't get channel for post for SearchEngine indexing.", mlog.String("channel_id", post.ChannelId), mlog.String("search_engine", engineCopy.GetName()), mlog.String("post_id", post.Id), mlog.Err(chanErr))
 					return
 				}
-				if err := engineCopy.IndexPost(post, channel.TeamId); err != nil {
+				if err := engineCopy.IndexPost(indexed(), teamID); err != nil {
 					rctx.Logger().Warn("Encountered error indexing post", mlog.String("post_id", post.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
 					return
 				}
@@ -36,6 +61,79 @@ func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post) {
 	}
 }
 
+// withSavedSearchProperties returns a copy of a post that was just saved with
+// the search properties it can have.
+func withSavedSearchProperties(post *model.Post) *model.Post {
+	post = post.Clone()
+	if post.Metadata == nil {
+		post.Metadata = &model.PostMetadata{}
+	}
+	post.Metadata.Reactions = nil
+	post.ReplyCount = 0
+	return post
+}
+
+// withSearchProperties returns a copy of the post with the reactions, the
+// priority and the reply count searched for by the has: is: reacted: and
+// priority: filters, which the saved posts don't always carry. Only the
+// properties the post can have are loaded: the reactions if it has any, and
+// the priority and the reply count if it is the root of a thread.
+func (s SearchPostStore) withSearchProperties(rctx request.CTX, post *model.Post) *model.Post {
+	post = post.Clone()
+	if post.Metadata == nil {
+		post.Metadata = &model.PostMetadata{}
+	}
+
+	post.Metadata.Reactions = nil
+	if post.HasReactions {
+		reactions, err := s.rootStore.Reaction().GetForPost(post.Id, false)
+		if err != nil {
+			rctx.Logger().Warn("Couldn't get the reactions of the post for SearchEngine indexing.", mlog.String("post_id", post.Id), mlog.Err(err))
+		}
+		post.Metadata.Reactions = reactions
+	}
+
+	post.Metadata.Priority = nil
+	if post.RootId != "" {
+		return post
+	}
+
+	priorities, err := s.rootStore.PostPriority().GetForPosts([]string{post.Id})
+	if err != nil {
+		rctx.Logger().Warn("Couldn't get the priority of the post for SearchEngine indexing.", mlog.String("post_id", post.Id), mlog.Err(err))
+	}
+	if len(priorities) > 0 {
+		post.Metadata.Priority = priorities[0]
+	}
+
+	thread, err := s.rootStore.Thread().Get(post.Id)
+	if err != nil {
+		rctx.Logger().Warn("Couldn't get the thread of the post for SearchEngine indexing.", mlog.String("post_id", post.Id), mlog.Err(err))
+	} else if thread != nil {
+		post.ReplyCount = thread.ReplyCount
+	}
+
+	return post
+}
+
+// indexThreadRoot reindexes the root of a thread whose first reply was just
+// created or whose last reply was just deleted, as it starts or stops being
+// the root of a thread.
+func (s SearchPostStore) indexThreadRoot(rctx request.CTX, rootID string) {
+	if !s.rootStore.isIndexingEnabled() {
+		return
+	}
+	thread, err := s.rootStore.Thread().Get(rootID)
+	if err != nil || (thread != nil && thread.ReplyCount > 1) {
+		return
+	}
+	root, err := s.PostStore.GetSingle(rctx, rootID, false)
+	if err != nil {
+		return
+	}
+	s.indexPost(rctx, root, false)
+}
+
 func (s SearchPostStore) deletePostIndex(rctx request.CTX, post *model.Post) {
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsIndexingEnabled() {
@@ -81,7 +179,7 @@ func (s SearchPostStore) Update(rctx request.CTX, newPost, oldPost *model.Post)
 	post, err := s.PostStore.Update(rctx, newPost, oldPost)
 
 	if err == nil {
-		s.indexPost(rctx, post)
+		s.indexPost(rctx, post, false)
 	}
 	return post, err
 }
@@ -89,7 +187,7 @@ func (s SearchPostStore) Update(rctx request.CTX, newPost, oldPost *model.Post)
 func (s *SearchPostStore) Overwrite(rctx request.CTX, post *model.Post) (*model.Post, error) {
 	post, err := s.PostStore.Overwrite(rctx, post)
 	if err == nil {
-		s.indexPost(rctx, post)
+		s.indexPost(rctx, post, false)
 	}
 	return post, err
 }
@@ -98,7 +196,10 @@ func (s SearchPostStore) Save(rctx request.CTX, post *model.Post) (*model.Post,
 	npost, err := s.PostStore.Save(rctx, post)
 
 	if err == nil {
-		s.indexPost(rctx, npost)
+		s.indexPost(rctx, npost, true)
+		if npost.RootId != "" {
+			s.indexThreadRoot(rctx, npost.RootId)
+		}
 	}
 	return npost, err
 }
@@ -113,6 +214,9 @@ func (s SearchPostStore) Delete(rctx request.CTX, postId string, date int64, del
 		return err
 	}
 	s.deletePostIndex(rctx, post)
+	if post.RootId != "" {
+		s.indexThreadRoot(rctx, post.RootId)
+	}
 	return nil
 }
 
@@ -185,7 +289,14 @@ func (s SearchPostStore) searchPostsForUserByEngine(engine searchengine.SearchEn
 }
 
 func (s SearchPostStore) SearchPostsForUser(rctx request.CTX, paramsList []*model.SearchParams, userId, teamId string, page, perPage int) (*model.PostSearchResults, error) {
+	hasQuery := slices.ContainsFunc(paramsList, func(params *model.SearchParams) bool {
+		return params.Query != nil
+	})
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
+		// The advanced searches are left to the engines running them
+		if hasQuery && !searchengine.SupportsSearchQuery(engine) {
+			continue
+		}
 		if engine.IsSearchEnabled() {
 			results, err := s.searchPostsForUserByEngine(engine, paramsList, userId, teamId, page, perPage)
 			if err != nil {
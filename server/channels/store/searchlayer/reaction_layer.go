@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package searchlayer
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// SearchReactionStore reindexes the posts whose reactions change, for the
+// reacted: search filter.
+type SearchReactionStore struct {
+	store.ReactionStore
+	rootStore *SearchStore
+}
+
+func (s SearchReactionStore) indexPostFromID(postID string) {
+	if !s.rootStore.isIndexingEnabled() {
+		return
+	}
+	rctx := request.EmptyContext(s.rootStore.Logger())
+	post, err := s.rootStore.Post().GetSingle(rctx, postID, false)
+	if err != nil {
+		return
+	}
+	s.rootStore.post.indexPost(rctx, post, false)
+}
+
+func (s SearchReactionStore) Save(reaction *model.Reaction) (*model.Reaction, error) {
+	reaction, err := s.ReactionStore.Save(reaction)
+	if err == nil {
+		s.indexPostFromID(reaction.PostId)
+	}
+	return reaction, err
+}
+
+func (s SearchReactionStore) Delete(reaction *model.Reaction) (*model.Reaction, error) {
+	reaction, err := s.ReactionStore.Delete(reaction)
+	if err == nil {
+		s.indexPostFromID(reaction.PostId)
+	}
+	return reaction, err
+}
+
+func (s SearchReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
+	postIDs, err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
+	if err == nil {
+		for _, postID := range postIDs {
+			s.indexPostFromID(postID)
+		}
+	}
+	return postIDs, err
+}
+
+func (s SearchReactionStore) PermanentDeleteByUser(userID string) ([]string, error) {
+	postIDs, err := s.ReactionStore.PermanentDeleteByUser(userID)
+	if err == nil {
+		for _, postID := range postIDs {
+			s.indexPostFromID(postID)
+		}
+	}
+	return postIDs, err
+}
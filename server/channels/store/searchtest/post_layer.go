@@ -290,6 +290,26 @@ var searchPostStoreTests = []searchTest{
 		Fn:   testSearchPostDeleted,
 		Tags: []string{EngineAll},
 	},
+	{
+		Name: "Should be able to search with OR, grouping and exclusions",
+		Fn:   testSearchWithQuery,
+		Tags: []string{EnginePostgres, EngineMySQL, EngineBleve},
+	},
+	{
+		Name: "Should be able to search with regular expressions",
+		Fn:   testSearchWithRegexp,
+		Tags: []string{EngineBleve},
+	},
+	{
+		Name: "Should fail to search with regular expressions in the database",
+		Fn:   testSearchWithRegexpInDatabase,
+		Tags: []string{EnginePostgres, EngineMySQL},
+	},
+	{
+		Name: "Should be able to filter posts by their properties",
+		Fn:   testSearchWithPostPropertyFilters,
+		Tags: []string{EngineAll},
+	},
 }
 
 func TestSearchPostStore(t *testing.T, s store.Store, testEngine *SearchTestEngine) {
@@ -2111,3 +2131,124 @@ func testSearchPostDeleted(t *testing.T, th *SearchTestHelper) {
 		require.Len(t, results.Posts, 0)
 	})
 }
+
+func testSearchWithQuery(t *testing.T, th *SearchTestHelper) {
+	errorPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "disk error on the database server", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	failurePost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "backup failure, see the known issue", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	errnoPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "errno returned when writing the logs", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	defer th.deleteUserPosts(th.User.Id)
+
+	testCases := []struct {
+		terms       string
+		expectedIDs []string
+	}{
+		{`error OR failure -"known issue"`, []string{errorPost.Id}},
+		{`error OR failure`, []string{errorPost.Id, failurePost.Id}},
+		{`(disk OR backup) -(failure OR logs)`, []string{errorPost.Id}},
+		{`errno OR "known issue"`, []string{failurePost.Id, errnoPost.Id}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.terms, func(t *testing.T) {
+			paramsList, appErr := model.ParseSearchParamsWithQuery(tc.terms, 0)
+			require.Nil(t, appErr)
+
+			results, err := th.Store.Post().SearchPostsForUser(th.Context, paramsList, th.User.Id, th.Team.Id, 0, 20)
+			require.NoError(t, err)
+			require.Len(t, results.Posts, len(tc.expectedIDs))
+			for _, id := range tc.expectedIDs {
+				th.checkPostInSearchResults(t, id, results.Posts)
+			}
+		})
+	}
+}
+
+func testSearchWithPostPropertyFilters(t *testing.T, th *SearchTestHelper) {
+	filePostModel := th.createPostModel(th.User.Id, th.ChannelBasic.Id, "release notes attached", "", model.PostTypeDefault, 1000000, false)
+	filePostModel.FileIds = model.StringArray{model.NewId()}
+	filePost, err := th.Store.Post().Save(th.Context, filePostModel)
+	require.NoError(t, err)
+	linkPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "release notes at https://example.com/notes", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	pinnedPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "release checklist", "", model.PostTypeDefault, 0, true)
+	require.NoError(t, err)
+	rootPostModel := th.createPostModel(th.User.Id, th.ChannelBasic.Id, "release discussion", "", model.PostTypeDefault, 1000000, false)
+	rootPostModel.Metadata = &model.PostMetadata{Priority: &model.PostPriority{Priority: model.NewPointer(model.PostPriorityUrgent)}}
+	rootPost, err := th.Store.Post().Save(th.Context, rootPostModel)
+	require.NoError(t, err)
+	_, err = th.createReply(th.User.Id, "follow up", "", rootPost, 2000000, false)
+	require.NoError(t, err)
+	defer th.deleteUserPosts(th.User.Id)
+
+	_, err = th.Store.Reaction().Save(&model.Reaction{UserId: th.User.Id, PostId: pinnedPost.Id, EmojiName: "tada", ChannelId: th.ChannelBasic.Id})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		terms       string
+		expectedIDs []string
+	}{
+		{"release has:file", []string{filePost.Id}},
+		{"release has:link", []string{linkPost.Id}},
+		{"release -has:file -has:link", []string{pinnedPost.Id, rootPost.Id}},
+		{"release is:pinned", []string{pinnedPost.Id}},
+		{"release is:thread-root", []string{rootPost.Id}},
+		{"release reacted:tada", []string{pinnedPost.Id}},
+		{"release -reacted:tada", []string{filePost.Id, linkPost.Id, rootPost.Id}},
+		{"release priority:urgent", []string{rootPost.Id}},
+		{"release -priority:standard", []string{rootPost.Id}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.terms, func(t *testing.T) {
+			paramsList := model.ParseSearchParams(tc.terms, 0)
+
+			results, err := th.Store.Post().SearchPostsForUser(th.Context, paramsList, th.User.Id, th.Team.Id, 0, 20)
+			require.NoError(t, err)
+			require.Len(t, results.Posts, len(tc.expectedIDs))
+			for _, id := range tc.expectedIDs {
+				th.checkPostInSearchResults(t, id, results.Posts)
+			}
+		})
+	}
+
+	t.Run("reactions of a deleted emoji", func(t *testing.T) {
+		_, err := th.Store.Reaction().DeleteAllWithEmojiName("tada")
+		require.NoError(t, err)
+
+		results, err := th.Store.Post().SearchPostsForUser(th.Context, model.ParseSearchParams("release reacted:tada", 0), th.User.Id, th.Team.Id, 0, 20)
+		require.NoError(t, err)
+		require.Empty(t, results.Posts)
+	})
+}
+
+func testSearchWithRegexp(t *testing.T, th *SearchTestHelper) {
+	errorPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "disk error on the database server", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	_, err = th.createPost(th.User.Id, th.ChannelBasic.Id, "backup failure, see the known issue", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	errnoPost, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "errno returned when writing the logs", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	defer th.deleteUserPosts(th.User.Id)
+
+	paramsList, appErr := model.ParseSearchParamsWithQuery(`/err(or|no)/`, 0)
+	require.Nil(t, appErr)
+
+	results, err := th.Store.Post().SearchPostsForUser(th.Context, paramsList, th.User.Id, th.Team.Id, 0, 20)
+	require.NoError(t, err)
+	require.Len(t, results.Posts, 2)
+	th.checkPostInSearchResults(t, errorPost.Id, results.Posts)
+	th.checkPostInSearchResults(t, errnoPost.Id, results.Posts)
+}
+
+func testSearchWithRegexpInDatabase(t *testing.T, th *SearchTestHelper) {
+	_, err := th.createPost(th.User.Id, th.ChannelBasic.Id, "disk error on the database server", "", model.PostTypeDefault, 0, false)
+	require.NoError(t, err)
+	defer th.deleteUserPosts(th.User.Id)
+
+	paramsList, appErr := model.ParseSearchParamsWithQuery(`/err(or|no)/`, 0)
+	require.Nil(t, appErr)
+
+	_, err = th.Store.Post().SearchPostsForUser(th.Context, paramsList, th.User.Id, th.Team.Id, 0, 20)
+	require.Error(t, err)
+}
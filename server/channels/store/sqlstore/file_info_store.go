@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	sq "github.com/mattermost/squirrel"
 	"github.com/pkg/errors"
@@ -42,6 +43,9 @@ type fileInfoWithChannelID struct {
 	Content         string
 	RemoteId        *string
 	Archived        bool
+
+	ContentExtractionFailures int
+	ContentExtractionError    string
 }
 
 func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
@@ -66,6 +70,9 @@ func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
 		MiniPreview:     fi.MiniPreview,
 		Content:         fi.Content,
 		RemoteId:        fi.RemoteId,
+
+		ContentExtractionFailures: fi.ContentExtractionFailures,
+		ContentExtractionError:    fi.ContentExtractionError,
 	}
 }
 
@@ -106,6 +113,8 @@ func newSqlFileInfoStore(sqlStore *SqlStore, metrics einterfaces.MetricsInterfac
 		"Coalesce(FileInfo.Content, '') AS Content",
 		"Coalesce(FileInfo.RemoteId, '') AS RemoteId",
 		"FileInfo.Archived",
+		"FileInfo.ContentExtractionFailures",
+		"FileInfo.ContentExtractionError",
 	}
 
 	return s
@@ -430,6 +439,8 @@ func (fs SqlFileInfoStore) SetContent(rctx request.CTX, fileId, content string)
 	query := fs.getQueryBuilder().
 		Update("FileInfo").
 		Set("Content", content).
+		Set("ContentExtractionFailures", 0).
+		Set("ContentExtractionError", "").
 		Where(sq.Eq{"Id": fileId})
 
 	queryString, args, err := query.ToSql()
@@ -445,6 +456,36 @@ func (fs SqlFileInfoStore) SetContent(rctx request.CTX, fileId, content string)
 	return nil
 }
 
+func (fs SqlFileInfoStore) SetContentExtractionFailure(rctx request.CTX, fileId, extractionErr string) error {
+	if len(extractionErr) > model.ContentExtractionErrorMaxLength {
+		// Cut on a rune boundary, as the error often holds the name of the file
+		// and the database rejects invalid UTF-8.
+		end := model.ContentExtractionErrorMaxLength
+		for end > 0 && !utf8.RuneStart(extractionErr[end]) {
+			end--
+		}
+		extractionErr = extractionErr[:end]
+	}
+
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("ContentExtractionFailures", sq.Expr("ContentExtractionFailures + 1")).
+		Set("ContentExtractionError", extractionErr).
+		Where(sq.Eq{"Id": fileId})
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "file_info_tosql")
+	}
+
+	_, err = fs.GetMaster().Exec(queryString, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update FileInfo content extraction failures with id=%s", fileId)
+	}
+
+	return nil
+}
+
 func (fs SqlFileInfoStore) DeleteForPost(rctx request.CTX, postId string) (string, error) {
 	if _, err := fs.GetMaster().Exec(
 		`UPDATE
@@ -760,6 +801,35 @@ func (fs SqlFileInfoStore) GetFilesBatchForIndexing(startTime int64, startFileID
 	return files, nil
 }
 
+// GetFilteredFilesBatchForIndexing is GetFilesBatchForIndexing restricted to
+// the files of a team or a channel, deleted files included.
+func (fs SqlFileInfoStore) GetFilteredFilesBatchForIndexing(startTime int64, startFileID string, filter store.IndexingBatchFilter, limit int) ([]*model.FileForIndexing, error) {
+	query := fs.getQueryBuilder().
+		Select(fs.queryFields...).
+		From("FileInfo").
+		Where(sq.Or{
+			sq.Gt{"FileInfo.CreateAt": startTime},
+			sq.And{
+				sq.Eq{"FileInfo.CreateAt": startTime},
+				sq.Gt{"FileInfo.Id": startFileID},
+			},
+		}).
+		OrderBy("FileInfo.CreateAt ASC, FileInfo.Id ASC").
+		Limit(uint64(limit))
+
+	if filter.ChannelID != "" {
+		query = query.Where(sq.Eq{"FileInfo.ChannelId": filter.ChannelID})
+	} else if filter.TeamID != "" {
+		query = query.Where(sq.Expr("FileInfo.ChannelId IN (SELECT Id FROM Channels WHERE TeamId = ?)", filter.TeamID))
+	}
+
+	files := []*model.FileForIndexing{}
+	if err := fs.GetSearchReplicaX().SelectBuilder(&files, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find Files")
+	}
+	return files, nil
+}
+
 func (fs SqlFileInfoStore) GetStorageUsage(_, includeDeleted bool) (int64, error) {
 	var query sq.SelectBuilder
 	if fs.DriverName() == model.DatabaseDriverPostgres && !includeDeleted {
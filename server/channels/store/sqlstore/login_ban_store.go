@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	sq "github.com/mattermost/squirrel"
+)
+
+type SqlLoginBanStore struct {
+	*SqlStore
+
+	loginBanSelectQuery sq.SelectBuilder
+}
+
+func newSqlLoginBanStore(sqlStore *SqlStore) store.LoginBanStore {
+	s := &SqlLoginBanStore{
+		SqlStore: sqlStore,
+	}
+
+	s.loginBanSelectQuery = s.getQueryBuilder().
+		Select(
+			"LoginBans.IP",
+			"LoginBans.Reason",
+			"LoginBans.FailedAttempts",
+			"LoginBans.CreateAt",
+			"LoginBans.ExpireAt",
+		).
+		From("LoginBans")
+
+	return s
+}
+
+func (s *SqlLoginBanStore) Save(ban *model.LoginBan) (*model.LoginBan, error) {
+	ban.PreSave()
+	if err := ban.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("LoginBans").
+		Columns("IP", "Reason", "FailedAttempts", "CreateAt", "ExpireAt").
+		Values(ban.IP, ban.Reason, ban.FailedAttempts, ban.CreateAt, ban.ExpireAt)
+
+	if s.DriverName() == model.DatabaseDriverMysql {
+		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE Reason = ?, FailedAttempts = ?, CreateAt = ?, ExpireAt = ?", ban.Reason, ban.FailedAttempts, ban.CreateAt, ban.ExpireAt))
+	} else {
+		query = query.SuffixExpr(sq.Expr("ON CONFLICT (IP) DO UPDATE SET Reason = ?, FailedAttempts = ?, CreateAt = ?, ExpireAt = ?", ban.Reason, ban.FailedAttempts, ban.CreateAt, ban.ExpireAt))
+	}
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save LoginBan with ip=%s", ban.IP)
+	}
+
+	return ban, nil
+}
+
+// GetActive returns the bans that have not expired at the given time, the
+// latest to expire first. They are read from the master so that a new ban
+// applies at once.
+func (s *SqlLoginBanStore) GetActive(now int64) ([]*model.LoginBan, error) {
+	query := s.loginBanSelectQuery.
+		Where(sq.Gt{"ExpireAt": now}).
+		OrderBy("ExpireAt DESC", "IP")
+
+	bans := []*model.LoginBan{}
+	if err := s.GetMaster().SelectBuilder(&bans, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find LoginBans")
+	}
+
+	return bans, nil
+}
+
+func (s *SqlLoginBanStore) Delete(ip string) error {
+	result, err := s.GetMaster().ExecBuilder(s.getQueryBuilder().Delete("LoginBans").Where(sq.Eq{"IP": ip}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete LoginBan with ip=%s", ip)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("LoginBan", ip)
+	}
+
+	return nil
+}
+
+func (s *SqlLoginBanStore) DeleteAll() error {
+	if _, err := s.GetMaster().ExecBuilder(s.getQueryBuilder().Delete("LoginBans")); err != nil {
+		return errors.Wrap(err, "failed to delete LoginBans")
+	}
+
+	return nil
+}
+
+func (s *SqlLoginBanStore) DeleteExpired(now int64) (int64, error) {
+	result, err := s.GetMaster().ExecBuilder(s.getQueryBuilder().Delete("LoginBans").Where(sq.LtOrEq{"ExpireAt": now}))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete expired LoginBans")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
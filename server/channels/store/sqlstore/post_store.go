@@ -2028,6 +2028,115 @@ func (s *SqlPostStore) buildCreateDateFilterClause(params *model.SearchParams, b
 	return builder
 }
 
+// buildSearchPostPropertyFilterClause handles the has: is: reacted: and
+// priority: filters. All of them have to match, except for the priorities
+// since a post only has one.
+func (s *SqlPostStore) buildSearchPostPropertyFilterClause(params *model.SearchParams, builder sq.SelectBuilder) (sq.SelectBuilder, error) {
+	exclude := func(clause sq.Sqlizer) (sq.Sqlizer, error) {
+		sql, args, err := clause.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr("NOT ("+sql+")", args...), nil
+	}
+
+	var clauses, excludedClauses []sq.Sqlizer
+	for _, value := range params.Has {
+		clauses = append(clauses, s.searchHasClause(value))
+	}
+	for _, value := range params.ExcludedHas {
+		excludedClauses = append(excludedClauses, s.searchHasClause(value))
+	}
+	for _, value := range params.Is {
+		clauses = append(clauses, searchIsClause(value))
+	}
+	for _, value := range params.ExcludedIs {
+		excludedClauses = append(excludedClauses, searchIsClause(value))
+	}
+	for _, emojiName := range params.Reactions {
+		clauses = append(clauses, searchReactedClause(emojiName))
+	}
+	for _, emojiName := range params.ExcludedReactions {
+		excludedClauses = append(excludedClauses, searchReactedClause(emojiName))
+	}
+	if len(params.Priorities) > 0 {
+		clauses = append(clauses, searchPriorityClause(params.Priorities))
+	}
+	if len(params.ExcludedPriorities) > 0 {
+		excludedClauses = append(excludedClauses, searchPriorityClause(params.ExcludedPriorities))
+	}
+
+	for _, clause := range excludedClauses {
+		excludedClause, err := exclude(clause)
+		if err != nil {
+			return builder, err
+		}
+		clauses = append(clauses, excludedClause)
+	}
+	for _, clause := range clauses {
+		builder = builder.Where(clause)
+	}
+
+	return builder, nil
+}
+
+// searchHasLinkCondition is the predicate of the idx_posts_has_link partial
+// index on PostgreSQL. It is inlined rather than bound so that the planner
+// can prove the searches imply it and use the index.
+const searchHasLinkCondition = "(LOWER(q2.Message) LIKE '%http://%' OR LOWER(q2.Message) LIKE '%https://%')"
+
+func (s *SqlPostStore) searchHasClause(value string) sq.Sqlizer {
+	switch value {
+	case model.SearchHasFile:
+		return sq.Expr("q2.FileIds NOT IN ('', '[]')")
+	case model.SearchHasLink:
+		if s.DriverName() == model.DatabaseDriverMysql {
+			// MySQL has no partial indexes, so the full-text index narrows
+			// the posts down to the ones with the words of the protocols
+			// before they are matched.
+			return sq.Expr("MATCH (q2.Message) AGAINST ('http https' IN BOOLEAN MODE) AND " + searchHasLinkCondition)
+		}
+		return sq.Expr(searchHasLinkCondition)
+	}
+	return sq.Expr("1 = 0")
+}
+
+func searchIsClause(value string) sq.Sqlizer {
+	switch value {
+	case model.SearchIsPinned:
+		return sq.Eq{"q2.IsPinned": true}
+	case model.SearchIsThreadRoot:
+		return sq.Expr("q2.RootId = '' AND EXISTS (SELECT 1 FROM Posts Replies WHERE Replies.RootId = q2.Id AND Replies.DeleteAt = 0)")
+	}
+	return sq.Expr("1 = 0")
+}
+
+func searchReactedClause(emojiName string) sq.Sqlizer {
+	return sq.Expr("EXISTS (SELECT 1 FROM Reactions WHERE Reactions.PostId = q2.Id AND Reactions.EmojiName = ? AND COALESCE(Reactions.DeleteAt, 0) = 0)", emojiName)
+}
+
+// searchPriorityClause matches the posts with any of the priorities, the
+// standard priority being the absence of one.
+func searchPriorityClause(priorities []string) sq.Sqlizer {
+	var clauses sq.Or
+	var set []string
+	for _, priority := range priorities {
+		if priority == model.SearchPriorityStandard {
+			clauses = append(clauses, sq.Expr("NOT EXISTS (SELECT 1 FROM PostsPriority WHERE PostsPriority.PostId = q2.Id AND PostsPriority.Priority IN (?, ?))", model.PostPriorityUrgent, model.PostPriorityImportant))
+		} else {
+			set = append(set, priority)
+		}
+	}
+	if len(set) > 0 {
+		args := make([]any, len(set))
+		for i, priority := range set {
+			args[i] = priority
+		}
+		clauses = append(clauses, sq.Expr("EXISTS (SELECT 1 FROM PostsPriority WHERE PostsPriority.PostId = q2.Id AND PostsPriority.Priority IN ("+sq.Placeholders(len(set))+"))", args...))
+	}
+	return clauses
+}
+
 func (s *SqlPostStore) buildSearchTeamFilterClause(teamId string, builder sq.SelectBuilder) sq.SelectBuilder {
 	if teamId == "" {
 		return builder
@@ -2113,10 +2222,11 @@ func (s *SqlPostStore) Search(teamId string, userId string, params *model.Search
 
 func (s *SqlPostStore) search(teamId string, userId string, params *model.SearchParams, channelsByName bool, userByUsername bool) (*model.PostList, error) {
 	list := model.NewPostList()
-	if params.Terms == "" && params.ExcludedTerms == "" &&
+	if params.Terms == "" && params.ExcludedTerms == "" && params.Query == nil &&
 		len(params.InChannels) == 0 && len(params.ExcludedChannels) == 0 &&
 		len(params.FromUsers) == 0 && len(params.ExcludedUsers) == 0 &&
-		params.OnDate == "" && params.AfterDate == "" && params.BeforeDate == "" {
+		params.OnDate == "" && params.AfterDate == "" && params.BeforeDate == "" &&
+		!params.HasPostPropertyFilters() {
 		return list, nil
 	}
 
@@ -2135,6 +2245,10 @@ func (s *SqlPostStore) search(teamId string, userId string, params *model.Search
 		return nil, errors.Wrap(err, "failed to build search post filter clause")
 	}
 	baseQuery = s.buildCreateDateFilterClause(params, baseQuery)
+	baseQuery, err = s.buildSearchPostPropertyFilterClause(params, baseQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build search post property filter clause")
+	}
 
 	termMap := map[string]bool{}
 	terms := params.Terms
@@ -2155,7 +2269,15 @@ func (s *SqlPostStore) search(teamId string, userId string, params *model.Search
 		excludedTerms = strings.Replace(excludedTerms, c, " ", -1)
 	}
 
-	if terms == "" && excludedTerms == "" {
+	if params.Query != nil {
+		queryClause, err := s.buildSearchQueryClause(params.Query)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build search query clause")
+		}
+		if queryClause != nil {
+			baseQuery = baseQuery.Where(queryClause)
+		}
+	} else if terms == "" && excludedTerms == "" {
 		// we've already confirmed that we have a channel or user to search for
 	} else if s.DriverName() == model.DatabaseDriverPostgres {
 		// Parse text for wildcards
@@ -2309,6 +2431,80 @@ func (s *SqlPostStore) search(teamId string, userId string, params *model.Search
 	return list, nil
 }
 
+// buildSearchQueryClause compiles the syntax tree of an advanced search into a
+// condition on the messages. It returns nil when nothing is left to search
+// for once the characters the database can't search for are removed.
+func (s *SqlPostStore) buildSearchQueryClause(query *model.SearchQuery) (sq.Sqlizer, error) {
+	switch query.Type {
+	case model.SearchQueryTypeAnd, model.SearchQueryTypeOr:
+		var clauses []sq.Sqlizer
+		for _, child := range query.Children {
+			clause, err := s.buildSearchQueryClause(child)
+			if err != nil {
+				return nil, err
+			}
+			if clause != nil {
+				clauses = append(clauses, clause)
+			}
+		}
+		switch {
+		case len(clauses) == 0:
+			return nil, nil
+		case len(clauses) == 1:
+			return clauses[0], nil
+		case query.Type == model.SearchQueryTypeAnd:
+			return sq.And(clauses), nil
+		}
+		return sq.Or(clauses), nil
+
+	case model.SearchQueryTypeNot:
+		clause, err := s.buildSearchQueryClause(query.Children[0])
+		if err != nil || clause == nil {
+			return nil, err
+		}
+		sql, args, err := clause.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr("NOT ("+sql+")", args...), nil
+
+	case model.SearchQueryTypeRegexp:
+		// Matching a regular expression can't use the full text indexes
+		return nil, errors.New("regular expressions can't be searched for in the database")
+	}
+
+	words := query.Value
+	// The operators of the full text queries are searched for as words
+	for _, c := range append(s.specialSearchChars(), "&", "|", "!", "'", `"`) {
+		words = strings.Replace(words, c, " ", -1)
+	}
+	if s.DriverName() == model.DatabaseDriverMysql {
+		words, err := removeMysqlStopWordsFromTerms(words)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to remove Mysql stop-words from terms")
+		}
+		if words == "" {
+			return nil, nil
+		}
+		if query.Type == model.SearchQueryTypePhrase {
+			words = `"` + words + `"`
+		} else {
+			words = "+" + strings.Join(strings.Fields(words), " +")
+		}
+		return sq.Expr("MATCH (Message) AGAINST (? IN BOOLEAN MODE)", words), nil
+	}
+
+	fields := strings.Fields(wildCardRegex.ReplaceAllLiteralString(words, ":* "))
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	operator := "&"
+	if query.Type == model.SearchQueryTypePhrase {
+		operator = "<->"
+	}
+	return sq.Expr(fmt.Sprintf("to_tsvector('%[1]s', Message) @@ to_tsquery('%[1]s', ?)", s.pgDefaultTextSearchConfig), strings.Join(fields, operator)), nil
+}
+
 func removeMysqlStopWordsFromTerms(terms string) (string, error) {
 	stopWords := make([]string, len(searchlayer.MySQLStopWords))
 	copy(stopWords, searchlayer.MySQLStopWords)
@@ -2655,12 +2851,16 @@ func (s *SqlPostStore) GetPostsBatchForIndexing(startTime int64, startPostID str
 	// and https://community.mattermost.com/core/pl/ui5dz96shinetb8nq83myggbma
 	if s.DriverName() == model.DatabaseDriverMysql {
 		query := `SELECT
-				Posts.*, Channels.TeamId
+				Posts.*, Channels.TeamId, COALESCE(Threads.ReplyCount, 0) AS ReplyCount
 			FROM Posts USE INDEX(idx_posts_create_at_id)
 			LEFT JOIN
 				Channels
 			ON
 				Posts.ChannelId = Channels.Id
+			LEFT JOIN
+				Threads
+			ON
+				Posts.Id = Threads.PostId
 			WHERE
 				Posts.CreateAt > ?
 				OR
@@ -2672,12 +2872,16 @@ func (s *SqlPostStore) GetPostsBatchForIndexing(startTime int64, startPostID str
 		err = s.GetSearchReplicaX().Select(&posts, query, startTime, startTime, startPostID, limit)
 	} else {
 		query := `SELECT
-				Posts.*, Channels.TeamId
+				Posts.*, Channels.TeamId, COALESCE(Threads.ReplyCount, 0) AS ReplyCount
 			FROM Posts
 			LEFT JOIN
 				Channels
 			ON
 				Posts.ChannelId = Channels.Id
+			LEFT JOIN
+				Threads
+			ON
+				Posts.Id = Threads.PostId
 			WHERE
 				(Posts.CreateAt, Posts.Id) > (?, ?)
 			ORDER BY
@@ -2692,6 +2896,38 @@ func (s *SqlPostStore) GetPostsBatchForIndexing(startTime int64, startPostID str
 	return posts, nil
 }
 
+// GetFilteredPostsBatchForIndexing is GetPostsBatchForIndexing restricted to
+// the posts of a team or a channel, which are looked up by their channel to
+// use the channel indexes of the Posts table.
+func (s *SqlPostStore) GetFilteredPostsBatchForIndexing(startTime int64, startPostID string, filter store.IndexingBatchFilter, limit int) ([]*model.PostForIndexing, error) {
+	query := s.getQueryBuilder().
+		Select("Posts.*", "Channels.TeamId", "COALESCE(Threads.ReplyCount, 0) AS ReplyCount").
+		From("Posts").
+		LeftJoin("Channels ON Posts.ChannelId = Channels.Id").
+		LeftJoin("Threads ON Posts.Id = Threads.PostId").
+		Where(sq.Or{
+			sq.Gt{"Posts.CreateAt": startTime},
+			sq.And{
+				sq.Eq{"Posts.CreateAt": startTime},
+				sq.Gt{"Posts.Id": startPostID},
+			},
+		}).
+		OrderBy("Posts.CreateAt ASC", "Posts.Id ASC").
+		Limit(uint64(limit))
+
+	if filter.ChannelID != "" {
+		query = query.Where(sq.Eq{"Posts.ChannelId": filter.ChannelID})
+	} else if filter.TeamID != "" {
+		query = query.Where(sq.Expr("Posts.ChannelId IN (SELECT Id FROM Channels WHERE TeamId = ?)", filter.TeamID))
+	}
+
+	posts := []*model.PostForIndexing{}
+	if err := s.GetSearchReplicaX().SelectBuilder(&posts, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find Posts")
+	}
+	return posts, nil
+}
+
 // PermanentDeleteBatchForRetentionPolicies deletes a batch of records which are affected by
 // the global or a granular retention policy.
 // See `genericPermanentDeleteBatchForRetentionPolicies` for details.
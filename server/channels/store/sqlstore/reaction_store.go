@@ -208,7 +208,7 @@ func (s *SqlReactionStore) GetSingle(userID, postID, remoteID, emojiName string)
 	return reactions[0], nil
 }
 
-func (s *SqlReactionStore) DeleteAllWithEmojiName(emojiName string) error {
+func (s *SqlReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
 	var reactions []*model.Reaction
 	now := model.GetMillis()
 
@@ -225,7 +225,7 @@ func (s *SqlReactionStore) DeleteAllWithEmojiName(emojiName string) error {
 			Reactions
 		WHERE
 			EmojiName = ? AND COALESCE(DeleteAt, 0) = 0`, emojiName); err != nil {
-		return errors.Wrapf(err, "failed to get Reactions with emojiName=%s", emojiName)
+		return nil, errors.Wrapf(err, "failed to get Reactions with emojiName=%s", emojiName)
 	}
 
 	_, err := s.GetMaster().Exec(
@@ -236,11 +236,13 @@ func (s *SqlReactionStore) DeleteAllWithEmojiName(emojiName string) error {
 		WHERE
 			EmojiName = ? AND COALESCE(DeleteAt, 0) = 0`, now, now, emojiName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to delete Reactions with emojiName=%s", emojiName)
+		return nil, errors.Wrapf(err, "failed to delete Reactions with emojiName=%s", emojiName)
 	}
 
+	postIds := make([]string, 0, len(reactions))
 	for _, reaction := range reactions {
 		reaction := reaction
+		postIds = append(postIds, reaction.PostId)
 		_, err := s.GetMaster().Exec(UpdatePostHasReactionsOnDeleteQuery, now, reaction.PostId, reaction.PostId)
 		if err != nil {
 			mlog.Warn("Unable to update Post.HasReactions while removing reactions",
@@ -249,7 +251,7 @@ func (s *SqlReactionStore) DeleteAllWithEmojiName(emojiName string) error {
 		}
 	}
 
-	return nil
+	return model.RemoveDuplicateStrings(postIds), nil
 }
 
 func (s *SqlReactionStore) permanentDeleteReactions(userId string) ([]string, error) {
@@ -282,17 +284,17 @@ func (s *SqlReactionStore) permanentDeleteReactions(userId string) ([]string, er
 	return postIds, nil
 }
 
-func (s SqlReactionStore) PermanentDeleteByUser(userId string) error {
+func (s SqlReactionStore) PermanentDeleteByUser(userId string) ([]string, error) {
 	now := model.GetMillis()
 
 	postIds, err := s.permanentDeleteReactions(userId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	transaction, err := s.GetMaster().Beginx()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer finalizeTransactionX(transaction, &err)
 
@@ -306,10 +308,10 @@ func (s SqlReactionStore) PermanentDeleteByUser(userId string) error {
 		time.Sleep(10 * time.Millisecond)
 	}
 	if err = transaction.Commit(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return model.RemoveDuplicateStrings(postIds), nil
 }
 
 func (s *SqlReactionStore) DeleteOrphanedRowsByIds(r *model.RetentionIdsForDeletion) (int64, error) {
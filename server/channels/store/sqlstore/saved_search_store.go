@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	sq "github.com/mattermost/squirrel"
+)
+
+type SqlSavedSearchStore struct {
+	*SqlStore
+
+	savedSearchSelectQuery sq.SelectBuilder
+}
+
+func newSqlSavedSearchStore(sqlStore *SqlStore) store.SavedSearchStore {
+	s := &SqlSavedSearchStore{
+		SqlStore: sqlStore,
+	}
+
+	s.savedSearchSelectQuery = s.getQueryBuilder().
+		Select(
+			"SavedSearches.Id",
+			"SavedSearches.UserId",
+			"SavedSearches.TeamId",
+			"SavedSearches.Name",
+			"SavedSearches.Terms",
+			"SavedSearches.IsOrSearch",
+			"SavedSearches.Alert",
+			"SavedSearches.CreateAt",
+			"SavedSearches.UpdateAt",
+		).
+		From("SavedSearches")
+
+	return s
+}
+
+func (s *SqlSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	savedSearch.PreSave()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("SavedSearches").
+		Columns("Id", "UserId", "TeamId", "Name", "Terms", "IsOrSearch", "Alert", "CreateAt", "UpdateAt").
+		Values(savedSearch.Id, savedSearch.UserId, savedSearch.TeamId, savedSearch.Name, savedSearch.Terms, savedSearch.IsOrSearch, savedSearch.Alert, savedSearch.CreateAt, savedSearch.UpdateAt)
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		if IsUniqueConstraintError(err, []string{"Name", "savedsearches_userid_name_key", "idx_savedsearches_user_id_name"}) {
+			return nil, store.NewErrUniqueConstraint("Name")
+		}
+		return nil, errors.Wrapf(err, "failed to save SavedSearch with name=%s", savedSearch.Name)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	savedSearch.PreUpdate()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("SavedSearches").
+		Set("TeamId", savedSearch.TeamId).
+		Set("Name", savedSearch.Name).
+		Set("Terms", savedSearch.Terms).
+		Set("IsOrSearch", savedSearch.IsOrSearch).
+		Set("Alert", savedSearch.Alert).
+		Set("UpdateAt", savedSearch.UpdateAt).
+		Where(sq.Eq{"Id": savedSearch.Id})
+
+	result, err := s.GetMaster().ExecBuilder(query)
+	if err != nil {
+		if IsUniqueConstraintError(err, []string{"Name", "savedsearches_userid_name_key", "idx_savedsearches_user_id_name"}) {
+			return nil, store.NewErrUniqueConstraint("Name")
+		}
+		return nil, errors.Wrapf(err, "failed to update SavedSearch with id=%s", savedSearch.Id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return nil, store.NewErrNotFound("SavedSearch", savedSearch.Id)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Get(id string) (*model.SavedSearch, error) {
+	var savedSearch model.SavedSearch
+
+	query := s.savedSearchSelectQuery.Where(sq.Eq{"SavedSearches.Id": id})
+	if err := s.GetReplica().GetBuilder(&savedSearch, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("SavedSearch", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get SavedSearch with id=%s", id)
+	}
+
+	return &savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) GetForUser(userID string) ([]*model.SavedSearch, error) {
+	savedSearches := []*model.SavedSearch{}
+
+	query := s.savedSearchSelectQuery.
+		Where(sq.Eq{"SavedSearches.UserId": userID}).
+		OrderBy("SavedSearches.Name ASC")
+
+	if err := s.GetReplica().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get SavedSearches for user_id=%s", userID)
+	}
+
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) GetAlertsForTeam(teamID string) ([]*model.SavedSearch, error) {
+	savedSearches := []*model.SavedSearch{}
+
+	query := s.savedSearchSelectQuery.
+		Where(sq.Eq{
+			"SavedSearches.Alert":  true,
+			"SavedSearches.TeamId": []string{teamID, ""},
+		})
+
+	if err := s.GetReplica().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get SavedSearches with an alert for team_id=%s", teamID)
+	}
+
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) Delete(id string) error {
+	result, err := s.GetMaster().ExecBuilder(s.getQueryBuilder().Delete("SavedSearches").Where(sq.Eq{"Id": id}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete SavedSearch with id=%s", id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("SavedSearch", id)
+	}
+
+	return nil
+}
+
+func (s *SqlSavedSearchStore) PermanentDeleteByUser(userID string) error {
+	if _, err := s.GetMaster().ExecBuilder(s.getQueryBuilder().Delete("SavedSearches").Where(sq.Eq{"UserId": userID})); err != nil {
+		return errors.Wrapf(err, "failed to delete SavedSearches for user_id=%s", userID)
+	}
+
+	return nil
+}
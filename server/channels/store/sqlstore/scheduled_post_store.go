@@ -45,6 +45,12 @@ func (s *SqlScheduledPostStore) columns(prefix string) []string {
 		prefix + "ScheduledAt",
 		prefix + "ProcessedAt",
 		prefix + "ErrorCode",
+		prefix + "RecurrenceRule",
+		prefix + "OccurrenceCount",
+		prefix + "PausedAt",
+		prefix + "PublishAsBotId",
+		prefix + "RecurrenceStartAt",
+		prefix + "LastEditorId",
 	}
 }
 
@@ -63,6 +69,12 @@ func (s *SqlScheduledPostStore) scheduledPostToSlice(scheduledPost *model.Schedu
 		scheduledPost.ScheduledAt,
 		scheduledPost.ProcessedAt,
 		scheduledPost.ErrorCode,
+		scheduledPost.RecurrenceRule,
+		scheduledPost.OccurrenceCount,
+		scheduledPost.PausedAt,
+		scheduledPost.PublishAsBotId,
+		scheduledPost.RecurrenceStartAt,
+		scheduledPost.LastEditorId,
 	}
 }
 
@@ -122,6 +134,25 @@ func (s *SqlScheduledPostStore) GetScheduledPostsForUser(userId, teamId string)
 	return scheduledPosts, nil
 }
 
+func (s *SqlScheduledPostStore) GetScheduledPostsForChannel(channelId string) ([]*model.ScheduledPost, error) {
+	// return the scheduled posts of every user for this channel.
+	query := s.getQueryBuilder().
+		Select(s.columns("")...).
+		From("ScheduledPosts").
+		Where(sq.Eq{"ChannelId": channelId}).
+		OrderBy("ScheduledAt, CreateAt")
+
+	var scheduledPosts []*model.ScheduledPost
+
+	if err := s.GetReplica().SelectBuilder(&scheduledPosts, query); err != nil {
+		mlog.Error("SqlScheduledPostStore.GetScheduledPostsForChannel: failed to fetch scheduled posts for channel", mlog.String("channel_id", channelId), mlog.Err(err))
+
+		return nil, errors.Wrapf(err, "SqlScheduledPostStore.GetScheduledPostsForChannel: failed to fetch scheduled posts for channel, channelId: %s", channelId)
+	}
+
+	return scheduledPosts, nil
+}
+
 func (s *SqlScheduledPostStore) GetMaxMessageSize() int {
 	s.maxMessageSizeOnce.Do(func() {
 		var err error
@@ -139,14 +170,21 @@ func (s *SqlScheduledPostStore) GetPendingScheduledPosts(beforeTime, afterTime i
 	query := s.getQueryBuilder().
 		Select(s.columns("")...).
 		From("ScheduledPosts").
-		Where(sq.Eq{"ErrorCode": ""}).
+		Where(sq.Eq{"ErrorCode": "", "PausedAt": 0}).
 		OrderBy("ScheduledAt DESC", "Id").
 		Limit(perPage)
 
+	// Recurring posts are fetched however late they are, so that the missed
+	// occurrences can be skipped instead of ending the series.
+	afterTimeCondition := sq.Or{
+		sq.GtOrEq{"ScheduledAt": afterTime},
+		sq.NotEq{"RecurrenceRule": ""},
+	}
+
 	if lastScheduledPostId == "" {
 		query = query.Where(sq.And{
 			sq.LtOrEq{"ScheduledAt": beforeTime},
-			sq.GtOrEq{"ScheduledAt": afterTime},
+			afterTimeCondition,
 		})
 	}
 	if lastScheduledPostId != "" {
@@ -154,7 +192,7 @@ func (s *SqlScheduledPostStore) GetPendingScheduledPosts(beforeTime, afterTime i
 			Where(sq.Or{
 				sq.And{
 					sq.LtOrEq{"ScheduledAt": beforeTime},
-					sq.GtOrEq{"ScheduledAt": afterTime},
+					afterTimeCondition,
 				},
 				sq.And{
 					sq.Eq{"ScheduledAt": beforeTime},
@@ -241,6 +279,13 @@ func (s *SqlScheduledPostStore) toUpdateMap(scheduledPost *model.ScheduledPost)
 		"ScheduledAt": scheduledPost.ScheduledAt,
 		"ProcessedAt": now,
 		"ErrorCode":   scheduledPost.ErrorCode,
+
+		"RecurrenceRule":    scheduledPost.RecurrenceRule,
+		"OccurrenceCount":   scheduledPost.OccurrenceCount,
+		"PausedAt":          scheduledPost.PausedAt,
+		"PublishAsBotId":    scheduledPost.PublishAsBotId,
+		"RecurrenceStartAt": scheduledPost.RecurrenceStartAt,
+		"LastEditorId":      scheduledPost.LastEditorId,
 	}
 }
 
@@ -269,7 +314,7 @@ func (s *SqlScheduledPostStore) UpdateOldScheduledPosts(beforeTime int64) error
 		Set("ErrorCode", model.ScheduledPostErrorUnableToSend).
 		Set("ProcessedAt", model.GetMillis()).
 		Where(sq.And{
-			sq.Eq{"ErrorCode": ""},
+			sq.Eq{"ErrorCode": "", "RecurrenceRule": ""},
 			sq.Lt{"ScheduledAt": beforeTime},
 		})
 
@@ -121,6 +121,9 @@ type SqlStoreStores struct {
 	accessControlPolicy        store.AccessControlPolicyStore
 	Attributes                 store.AttributesStore
 	whitelist                  store.WhitelistStore
+	whitelistPolicy            store.WhitelistPolicyStore
+	savedSearch                store.SavedSearchStore
+	loginBan                   store.LoginBanStore
 	invite                     store.InviteStore
 }
 
@@ -278,6 +281,9 @@ func New(settings model.SqlSettings, logger mlog.LoggerIFace, metrics einterface
 	store.stores.accessControlPolicy = newSqlAccessControlPolicyStore(store, metrics)
 	store.stores.Attributes = newSqlAttributesStore(store, metrics)
 	store.stores.whitelist = newSqlWhitelistStore(store)
+	store.stores.whitelistPolicy = newSqlWhitelistPolicyStore(store)
+	store.stores.savedSearch = newSqlSavedSearchStore(store)
+	store.stores.loginBan = newSqlLoginBanStore(store)
 	store.stores.invite = newSqlInviteStore(store)
 
 	store.stores.preference.(*SqlPreferenceStore).deleteUnusedFeatures()
@@ -1405,6 +1411,18 @@ func (ss *SqlStore) Whitelist() store.WhitelistStore {
 	return ss.stores.whitelist
 }
 
+func (ss *SqlStore) WhitelistPolicy() store.WhitelistPolicyStore {
+	return ss.stores.whitelistPolicy
+}
+
+func (ss *SqlStore) SavedSearch() store.SavedSearchStore {
+	return ss.stores.savedSearch
+}
+
+func (ss *SqlStore) LoginBan() store.LoginBanStore {
+	return ss.stores.loginBan
+}
+
 func (ss *SqlStore) Invite() store.InviteStore {
 	return ss.stores.invite
 }
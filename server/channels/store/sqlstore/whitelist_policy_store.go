@@ -0,0 +1,257 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	sq "github.com/mattermost/squirrel"
+)
+
+type SqlWhitelistPolicyStore struct {
+	*SqlStore
+
+	policySelectQuery sq.SelectBuilder
+	targetSelectQuery sq.SelectBuilder
+}
+
+func newSqlWhitelistPolicyStore(sqlStore *SqlStore) store.WhitelistPolicyStore {
+	s := &SqlWhitelistPolicyStore{
+		SqlStore: sqlStore,
+	}
+
+	s.policySelectQuery = s.getQueryBuilder().
+		Select(
+			"WhitelistPolicies.Id",
+			"WhitelistPolicies.Name",
+			"WhitelistPolicies.Description",
+			"WhitelistPolicies.IPRanges",
+			"WhitelistPolicies.CreatorId",
+			"WhitelistPolicies.CreateAt",
+			"WhitelistPolicies.UpdateAt",
+		).
+		From("WhitelistPolicies")
+
+	s.targetSelectQuery = s.getQueryBuilder().
+		Select("PolicyId", "TargetType", "TargetId", "CreateAt").
+		From("WhitelistPolicyTargets")
+
+	return s
+}
+
+func (s *SqlWhitelistPolicyStore) Save(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	policy.PreSave()
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("WhitelistPolicies").
+		Columns("Id", "Name", "Description", "IPRanges", "CreatorId", "CreateAt", "UpdateAt").
+		Values(policy.Id, policy.Name, policy.Description, policy.IPRanges, policy.CreatorId, policy.CreateAt, policy.UpdateAt)
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		if IsUniqueConstraintError(err, []string{"Name", "whitelistpolicies_name_key", "idx_whitelistpolicies_name"}) {
+			return nil, store.NewErrUniqueConstraint("Name")
+		}
+		return nil, errors.Wrapf(err, "failed to save WhitelistPolicy with name=%s", policy.Name)
+	}
+
+	return policy, nil
+}
+
+func (s *SqlWhitelistPolicyStore) Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	policy.PreUpdate()
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("WhitelistPolicies").
+		Set("Name", policy.Name).
+		Set("Description", policy.Description).
+		Set("IPRanges", policy.IPRanges).
+		Set("UpdateAt", policy.UpdateAt).
+		Where(sq.Eq{"Id": policy.Id})
+
+	result, err := s.GetMaster().ExecBuilder(query)
+	if err != nil {
+		if IsUniqueConstraintError(err, []string{"Name", "whitelistpolicies_name_key", "idx_whitelistpolicies_name"}) {
+			return nil, store.NewErrUniqueConstraint("Name")
+		}
+		return nil, errors.Wrapf(err, "failed to update WhitelistPolicy with id=%s", policy.Id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return nil, store.NewErrNotFound("WhitelistPolicy", policy.Id)
+	}
+
+	return policy, nil
+}
+
+func (s *SqlWhitelistPolicyStore) Get(id string) (*model.WhitelistPolicy, error) {
+	var policy model.WhitelistPolicy
+
+	query := s.policySelectQuery.Where(sq.Eq{"WhitelistPolicies.Id": id})
+	if err := s.GetReplica().GetBuilder(&policy, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("WhitelistPolicy", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get WhitelistPolicy with id=%s", id)
+	}
+
+	return &policy, nil
+}
+
+func (s *SqlWhitelistPolicyStore) GetByName(name string) (*model.WhitelistPolicy, error) {
+	var policy model.WhitelistPolicy
+
+	query := s.policySelectQuery.Where(sq.Eq{"WhitelistPolicies.Name": name})
+	if err := s.GetReplica().GetBuilder(&policy, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("WhitelistPolicy", "name="+name)
+		}
+		return nil, errors.Wrapf(err, "failed to get WhitelistPolicy with name=%s", name)
+	}
+
+	return &policy, nil
+}
+
+func (s *SqlWhitelistPolicyStore) GetAll(offset, limit int) ([]*model.WhitelistPolicy, error) {
+	policies := []*model.WhitelistPolicy{}
+
+	query := s.policySelectQuery.
+		OrderBy("WhitelistPolicies.Name ASC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit))
+
+	if err := s.GetReplica().SelectBuilder(&policies, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get WhitelistPolicies")
+	}
+
+	return policies, nil
+}
+
+// GetForTargets returns every policy attached to at least one of the given
+// teams, groups or roles.
+func (s *SqlWhitelistPolicyStore) GetForTargets(teamIds, groupIds, roleNames []string) ([]*model.WhitelistPolicy, error) {
+	policies := []*model.WhitelistPolicy{}
+
+	targets := sq.Or{}
+	if len(teamIds) > 0 {
+		targets = append(targets, sq.Eq{"WhitelistPolicyTargets.TargetType": model.WhitelistPolicyTargetTypeTeam, "WhitelistPolicyTargets.TargetId": teamIds})
+	}
+	if len(groupIds) > 0 {
+		targets = append(targets, sq.Eq{"WhitelistPolicyTargets.TargetType": model.WhitelistPolicyTargetTypeGroup, "WhitelistPolicyTargets.TargetId": groupIds})
+	}
+	if len(roleNames) > 0 {
+		targets = append(targets, sq.Eq{"WhitelistPolicyTargets.TargetType": model.WhitelistPolicyTargetTypeRole, "WhitelistPolicyTargets.TargetId": roleNames})
+	}
+
+	if len(targets) == 0 {
+		return policies, nil
+	}
+
+	query := s.policySelectQuery.
+		Distinct().
+		InnerJoin("WhitelistPolicyTargets ON WhitelistPolicyTargets.PolicyId = WhitelistPolicies.Id").
+		Where(targets).
+		OrderBy("WhitelistPolicies.Name ASC")
+
+	if err := s.GetReplica().SelectBuilder(&policies, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get WhitelistPolicies for targets")
+	}
+
+	return policies, nil
+}
+
+func (s *SqlWhitelistPolicyStore) Delete(id string) (err error) {
+	transaction, err := s.GetMaster().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	if _, err = transaction.ExecBuilder(s.getQueryBuilder().Delete("WhitelistPolicyTargets").Where(sq.Eq{"PolicyId": id})); err != nil {
+		return errors.Wrapf(err, "failed to delete WhitelistPolicyTargets with policy_id=%s", id)
+	}
+
+	result, err := transaction.ExecBuilder(s.getQueryBuilder().Delete("WhitelistPolicies").Where(sq.Eq{"Id": id}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete WhitelistPolicy with id=%s", id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("WhitelistPolicy", id)
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return errors.Wrap(err, "commit_transaction")
+	}
+
+	return nil
+}
+
+func (s *SqlWhitelistPolicyStore) AddTarget(target *model.WhitelistPolicyTarget) error {
+	target.PreSave()
+	if err := target.IsValid(); err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("WhitelistPolicyTargets").
+		Columns("PolicyId", "TargetType", "TargetId", "CreateAt").
+		Values(target.PolicyId, target.TargetType, target.TargetId, target.CreateAt)
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		if IsUniqueConstraintError(err, []string{"PRIMARY", "whitelistpolicytargets_pkey"}) {
+			return store.NewErrConflict("WhitelistPolicyTarget", err, "policy_id="+target.PolicyId+", target_id="+target.TargetId)
+		}
+		return errors.Wrapf(err, "failed to save WhitelistPolicyTarget with policy_id=%s", target.PolicyId)
+	}
+
+	return nil
+}
+
+func (s *SqlWhitelistPolicyStore) RemoveTarget(target *model.WhitelistPolicyTarget) error {
+	query := s.getQueryBuilder().
+		Delete("WhitelistPolicyTargets").
+		Where(sq.Eq{
+			"PolicyId":   target.PolicyId,
+			"TargetType": target.TargetType,
+			"TargetId":   target.TargetId,
+		})
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete WhitelistPolicyTarget with policy_id=%s", target.PolicyId)
+	}
+
+	return nil
+}
+
+func (s *SqlWhitelistPolicyStore) GetTargets(policyId string) ([]*model.WhitelistPolicyTarget, error) {
+	targets := []*model.WhitelistPolicyTarget{}
+
+	query := s.targetSelectQuery.
+		Where(sq.Eq{"PolicyId": policyId}).
+		OrderBy("TargetType ASC", "TargetId ASC")
+
+	if err := s.GetReplica().SelectBuilder(&targets, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to get WhitelistPolicyTargets with policy_id=%s", policyId)
+	}
+
+	return targets, nil
+}
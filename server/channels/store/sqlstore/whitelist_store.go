@@ -6,13 +6,16 @@ package sqlstore
 import (
 	"github.com/pkg/errors"
 
-	sq "github.com/mattermost/squirrel"
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
+	sq "github.com/mattermost/squirrel"
 )
 
 type SqlWhitelistStore struct {
 	*SqlStore
+
+	whitelistSelectQuery sq.SelectBuilder
+	denialSelectQuery    sq.SelectBuilder
 }
 
 func newSqlWhitelistStore(sqlStore *SqlStore) store.WhitelistStore {
@@ -20,6 +23,14 @@ func newSqlWhitelistStore(sqlStore *SqlStore) store.WhitelistStore {
 		SqlStore: sqlStore,
 	}
 
+	s.whitelistSelectQuery = s.getQueryBuilder().
+		Select("UserId", "IP", "Description", "CreatorId", "CreateAt", "ExpiresAt").
+		From("Whitelist")
+
+	s.denialSelectQuery = s.getQueryBuilder().
+		Select("Id", "UserId", "IP", "Path", "Rule", "SessionId", "CreateAt", "Occurrences", "LastAt").
+		From("WhitelistDenials")
+
 	return s
 }
 
@@ -27,6 +38,10 @@ func (s SqlWhitelistStore) createIndexesIfNotExists() {
 	// Indexes are created via database migrations
 }
 
+func (s SqlWhitelistStore) ClearCaches() {}
+
+func (s SqlWhitelistStore) InvalidateWhitelistCacheForUser(userId string) {}
+
 func (s SqlWhitelistStore) Add(whitelistItem *model.WhitelistItem) error {
 	if len(whitelistItem.UserId) == 0 {
 		return store.NewErrInvalidInput("whitelist item", "user id", whitelistItem.UserId)
@@ -36,7 +51,12 @@ func (s SqlWhitelistStore) Add(whitelistItem *model.WhitelistItem) error {
 		return store.NewErrInvalidInput("whitelist item", "ip", whitelistItem.IP)
 	}
 
-	if _, err := s.GetMaster().NamedExec(`INSERT INTO Whitelist (UserId, IP) VALUES (:UserId, :IP)`, whitelistItem); err != nil {
+	query := s.getQueryBuilder().
+		Insert("Whitelist").
+		Columns("UserId", "IP", "Description", "CreatorId", "CreateAt", "ExpiresAt").
+		Values(whitelistItem.UserId, whitelistItem.IP, whitelistItem.Description, whitelistItem.CreatorId, whitelistItem.CreateAt, whitelistItem.ExpiresAt)
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
 		return errors.Wrapf(err, "failed to save whitelist item with user_id=%s and ip=%s", whitelistItem.UserId, whitelistItem.IP)
 	}
 
@@ -52,22 +72,168 @@ func (s SqlWhitelistStore) Delete(whitelistItem *model.WhitelistItem) error {
 	return nil
 }
 
-func (s SqlWhitelistStore) GetByUserId(userId string) ([]string, error) {
-	var ips []string
+func (s SqlWhitelistStore) GetByUserId(userId string) ([]*model.WhitelistItem, error) {
+	items := []*model.WhitelistItem{}
+
+	query := s.whitelistSelectQuery.
+		Where(sq.Eq{"UserId": userId}).
+		OrderBy("CreateAt ASC", "IP ASC")
+
+	if err := s.GetReplica().SelectBuilder(&items, query); err != nil {
+		return []*model.WhitelistItem{}, errors.Wrapf(err, "failed to find whitelist items for user_id=%s", userId)
+	}
+
+	return items, nil
+}
+
+// GetAllAfter gets up to limit entries of every user along with their usernames,
+// ordered by user and IP and starting after the given user and IP.
+func (s SqlWhitelistStore) GetAllAfter(limit int, afterUserId, afterIP string) ([]*model.WhitelistItemForExport, error) {
+	items := []*model.WhitelistItemForExport{}
+
+	query := s.getQueryBuilder().
+		Select("w.UserId", "w.IP", "w.Description", "w.CreatorId", "w.CreateAt", "w.ExpiresAt", "u.Username").
+		From("Whitelist w").
+		Join("Users u ON u.Id = w.UserId").
+		Where(sq.Or{
+			sq.Gt{"w.UserId": afterUserId},
+			sq.And{
+				sq.Eq{"w.UserId": afterUserId},
+				sq.Gt{"w.IP": afterIP},
+			},
+		}).
+		OrderBy("w.UserId ASC", "w.IP ASC").
+		Limit(uint64(limit))
+
+	if err := s.GetReplica().SelectBuilder(&items, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find whitelist items")
+	}
+
+	return items, nil
+}
+
+// GetPrefixSetByUserId gets the user's whitelist compiled into network prefixes.
+func (s SqlWhitelistStore) GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error) {
+	items, err := s.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewWhitelistPrefixSet(items), nil
+}
 
+// DeleteExpired removes every entry whose expiry is set and not after now,
+// returning the number of rows removed.
+func (s SqlWhitelistStore) DeleteExpired(now int64) (int64, error) {
 	query := s.getQueryBuilder().
-		Select("IP").
-		From("Whitelist").
-		Where(sq.Eq{"UserId": userId})
+		Delete("Whitelist").
+		Where(sq.And{
+			sq.Gt{"ExpiresAt": 0},
+			sq.LtOrEq{"ExpiresAt": now},
+		})
 
-	queryString, args, err := query.ToSql()
+	result, err := s.GetMaster().ExecBuilder(query)
 	if err != nil {
-		return []string{}, errors.Wrap(err, "whitelist_tosql")
+		return 0, errors.Wrap(err, "failed to delete expired whitelist items")
 	}
 
-	if err := s.GetReplica().Select(&ips, queryString, args...); err != nil {
-		return []string{}, errors.Wrap(err, "failed to find ips")
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
+
+func (s SqlWhitelistStore) SaveDenial(denial *model.WhitelistDenial) (*model.WhitelistDenial, error) {
+	denial.PreSave()
+	if err := denial.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("WhitelistDenials").
+		Columns("Id", "UserId", "IP", "Path", "Rule", "SessionId", "CreateAt", "Occurrences", "LastAt").
+		Values(denial.Id, denial.UserId, denial.IP, denial.Path, denial.Rule, denial.SessionId, denial.CreateAt, denial.Occurrences, denial.LastAt)
+
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save whitelist denial with user_id=%s", denial.UserId)
 	}
 
-	return ips, nil
-} 
\ No newline at end of file
+	return denial, nil
+}
+
+// IncrementDenial counts one more occurrence of a recorded denial, the latest at lastAt.
+func (s SqlWhitelistStore) IncrementDenial(id string, lastAt int64) error {
+	query := s.getQueryBuilder().
+		Update("WhitelistDenials").
+		Set("Occurrences", sq.Expr("Occurrences + 1")).
+		Set("LastAt", sq.Expr("GREATEST(LastAt, ?)", lastAt)).
+		Where(sq.Eq{"Id": id})
+
+	result, err := s.GetMaster().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to increment whitelist denial with id=%s", id)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return store.NewErrNotFound("WhitelistDenial", id)
+	}
+
+	return nil
+}
+
+// GetDenials returns the denials matching the filter, newest first.
+func (s SqlWhitelistStore) GetDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error) {
+	query := s.denialSelectQuery.
+		OrderBy("CreateAt DESC", "Id DESC").
+		Limit(uint64(filter.PerPage)).
+		Offset(uint64(filter.Page * filter.PerPage))
+
+	if filter.UserId != "" {
+		query = query.Where(sq.Eq{"UserId": filter.UserId})
+	}
+	if filter.IP != "" {
+		query = query.Where(sq.Eq{"IP": filter.IP})
+	}
+	if filter.Since > 0 {
+		query = query.Where(sq.GtOrEq{"CreateAt": filter.Since})
+	}
+	if filter.Until > 0 {
+		query = query.Where(sq.LtOrEq{"CreateAt": filter.Until})
+	}
+
+	denials := []*model.WhitelistDenial{}
+	if err := s.GetReplica().SelectBuilder(&denials, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find whitelist denials")
+	}
+
+	return denials, nil
+}
+
+// DeleteDenialsBefore removes up to limit denials recorded before endTime,
+// returning the number of rows removed.
+func (s SqlWhitelistStore) DeleteDenialsBefore(endTime int64, limit int64) (int64, error) {
+	var query string
+	if s.DriverName() == model.DatabaseDriverPostgres {
+		query = "DELETE FROM WhitelistDenials WHERE Id IN (SELECT Id FROM WhitelistDenials WHERE CreateAt < ? LIMIT ?)"
+	} else {
+		query = "DELETE FROM WhitelistDenials WHERE CreateAt < ? LIMIT ?"
+	}
+
+	result, err := s.GetMaster().Exec(query, endTime, limit)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete whitelist denials")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected, nil
+}
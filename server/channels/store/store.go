@@ -63,6 +63,9 @@ type Store interface {
 	SharedChannel() SharedChannelStore
 	Draft() DraftStore
 	Whitelist() WhitelistStore
+	WhitelistPolicy() WhitelistPolicyStore
+	SavedSearch() SavedSearchStore
+	LoginBan() LoginBanStore
 	Invite() InviteStore
 	MarkSystemRanUnitTests()
 	Close()
@@ -403,6 +406,7 @@ type PostStore interface {
 	GetPostsByIds(postIds []string) ([]*model.Post, error)
 	GetEditHistoryForPost(postID string) ([]*model.Post, error)
 	GetPostsBatchForIndexing(startTime int64, startPostID string, limit int) ([]*model.PostForIndexing, error)
+	GetFilteredPostsBatchForIndexing(startTime int64, startPostID string, filter IndexingBatchFilter, limit int) ([]*model.PostForIndexing, error)
 	PermanentDeleteBatchForRetentionPolicies(retentionPolicyBatchConfigs model.RetentionPolicyBatchConfigs, cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error)
 	PermanentDeleteBatch(endTime int64, limit int64) (int64, error)
 	GetOldest() (*model.Post, error)
@@ -750,9 +754,11 @@ type FileInfoStore interface {
 	PermanentDeleteBatch(ctx request.CTX, endTime int64, limit int64) (int64, error)
 	PermanentDeleteByUser(ctx request.CTX, userID string) (int64, error)
 	SetContent(ctx request.CTX, fileID, content string) error
+	SetContentExtractionFailure(ctx request.CTX, fileID, extractionErr string) error
 	Search(ctx request.CTX, paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.FileInfoList, error)
 	CountAll() (int64, error)
 	GetFilesBatchForIndexing(startTime int64, startFileID string, includeDeleted bool, limit int) ([]*model.FileForIndexing, error)
+	GetFilteredFilesBatchForIndexing(startTime int64, startFileID string, filter IndexingBatchFilter, limit int) ([]*model.FileForIndexing, error)
 	ClearCaches()
 	GetStorageUsage(allowFromCache, includeDeleted bool) (int64, error)
 	// GetUptoNSizeFileTime returns the CreateAt time of the last accessible file with a running-total size upto n bytes.
@@ -776,12 +782,16 @@ type ReactionStore interface {
 	GetForPostSince(postID string, since int64, excludeRemoteID string, inclDeleted bool) ([]*model.Reaction, error)
 	GetUniqueCountForPost(postID string) (int, error)
 	ExistsOnPost(postID string, emojiName string) (bool, error)
-	DeleteAllWithEmojiName(emojiName string) error
+	// DeleteAllWithEmojiName deletes the reactions with an emoji, returning
+	// the IDs of the posts they were removed from.
+	DeleteAllWithEmojiName(emojiName string) ([]string, error)
 	BulkGetForPosts(postIds []string) ([]*model.Reaction, error)
 	GetSingle(userID, postID, remoteID, emojiName string) (*model.Reaction, error)
 	DeleteOrphanedRowsByIds(r *model.RetentionIdsForDeletion) (int64, error)
 	PermanentDeleteBatch(endTime int64, limit int64) (int64, error)
-	PermanentDeleteByUser(userID string) error
+	// PermanentDeleteByUser deletes the reactions of a user, returning the IDs
+	// of the posts they were removed from.
+	PermanentDeleteByUser(userID string) ([]string, error)
 }
 
 type JobStore interface {
@@ -1091,6 +1101,7 @@ type ScheduledPostStore interface {
 	GetMaxMessageSize() int
 	CreateScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error)
 	GetScheduledPostsForUser(userId, teamId string) ([]*model.ScheduledPost, error)
+	GetScheduledPostsForChannel(channelId string) ([]*model.ScheduledPost, error)
 	GetPendingScheduledPosts(beforeTime, afterTime int64, lastScheduledPostId string, perPage uint64) ([]*model.ScheduledPost, error)
 	PermanentlyDeleteScheduledPosts(scheduledPostIDs []string) error
 	UpdatedScheduledPost(scheduledPost *model.ScheduledPost) error
@@ -1145,7 +1156,50 @@ type AttributesStore interface {
 type WhitelistStore interface {
 	Add(whitelistItem *model.WhitelistItem) error
 	Delete(whitelistItem *model.WhitelistItem) error
-	GetByUserId(userId string) ([]string, error)
+	GetByUserId(userId string) ([]*model.WhitelistItem, error)
+	GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error)
+	GetAllAfter(limit int, afterUserId, afterIP string) ([]*model.WhitelistItemForExport, error)
+	DeleteExpired(now int64) (int64, error)
+	SaveDenial(denial *model.WhitelistDenial) (*model.WhitelistDenial, error)
+	IncrementDenial(id string, lastAt int64) error
+	GetDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error)
+	DeleteDenialsBefore(endTime int64, limit int64) (int64, error)
+	InvalidateWhitelistCacheForUser(userId string)
+	ClearCaches()
+}
+
+type WhitelistPolicyStore interface {
+	Save(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error)
+	Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error)
+	Get(id string) (*model.WhitelistPolicy, error)
+	GetByName(name string) (*model.WhitelistPolicy, error)
+	GetAll(offset, limit int) ([]*model.WhitelistPolicy, error)
+	GetForTargets(teamIds, groupIds, roleNames []string) ([]*model.WhitelistPolicy, error)
+	Delete(id string) error
+	AddTarget(target *model.WhitelistPolicyTarget) error
+	RemoveTarget(target *model.WhitelistPolicyTarget) error
+	GetTargets(policyId string) ([]*model.WhitelistPolicyTarget, error)
+}
+
+type SavedSearchStore interface {
+	Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	Get(id string) (*model.SavedSearch, error)
+	GetForUser(userID string) ([]*model.SavedSearch, error)
+	// GetAlertsForTeam returns the saved searches with an alert for the team,
+	// including the ones for all teams.
+	GetAlertsForTeam(teamID string) ([]*model.SavedSearch, error)
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type LoginBanStore interface {
+	// Save stores the ban, replacing any earlier ban of the same address or range.
+	Save(ban *model.LoginBan) (*model.LoginBan, error)
+	GetActive(now int64) ([]*model.LoginBan, error)
+	Delete(ip string) error
+	DeleteAll() error
+	DeleteExpired(now int64) (int64, error)
 }
 
 type InviteStore interface {
@@ -1235,6 +1289,13 @@ type PostReminderMetadata struct {
 	Username   string
 }
 
+// IndexingBatchFilter restricts the posts and files of an indexing batch to
+// the channels of a team, or to a channel.
+type IndexingBatchFilter struct {
+	TeamID    string
+	ChannelID string
+}
+
 // SidebarCategorySearchOpts contains the options for a graphQL query
 // to get the sidebar categories.
 type SidebarCategorySearchOpts struct {
@@ -6,6 +6,7 @@ package storetest
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ func TestFileInfoStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStor
 	t.Run("FileInfoPermanentDeleteBatch", func(t *testing.T) { testFileInfoPermanentDeleteBatch(t, rctx, ss) })
 	t.Run("FileInfoPermanentDeleteByUser", func(t *testing.T) { testFileInfoPermanentDeleteByUser(t, rctx, ss) })
 	t.Run("FileInfoUpdateMinipreview", func(t *testing.T) { testFileInfoUpdateMinipreview(t, rctx, ss) })
+	t.Run("FileInfoSetContentExtractionFailure", func(t *testing.T) { testFileInfoSetContentExtractionFailure(t, rctx, ss) })
 	t.Run("GetFilesBatchForIndexing", func(t *testing.T) { testFileInfoStoreGetFilesBatchForIndexing(t, rctx, ss) })
 	t.Run("CountAll", func(t *testing.T) { testFileInfoStoreCountAll(t, rctx, ss) })
 	t.Run("GetStorageUsage", func(t *testing.T) { testFileInfoGetStorageUsage(t, rctx, ss) })
@@ -691,6 +693,49 @@ func testFileInfoUpdateMinipreview(t *testing.T, rctx request.CTX, ss store.Stor
 	require.Equal(t, *tinfo.MiniPreview, miniPreview)
 }
 
+func testFileInfoSetContentExtractionFailure(t *testing.T, rctx request.CTX, ss store.Store) {
+	info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "file.pdf",
+	})
+	require.NoError(t, err)
+	defer func() {
+		ss.FileInfo().PermanentDelete(rctx, info.Id)
+	}()
+
+	rinfo, err := ss.FileInfo().Get(info.Id)
+	require.NoError(t, err)
+	require.Zero(t, rinfo.ContentExtractionFailures)
+	require.Empty(t, rinfo.ContentExtractionError)
+
+	err = ss.FileInfo().SetContentExtractionFailure(rctx, info.Id, "first error")
+	require.NoError(t, err)
+	err = ss.FileInfo().SetContentExtractionFailure(rctx, info.Id, strings.Repeat("a", model.ContentExtractionErrorMaxLength+10))
+	require.NoError(t, err)
+
+	rinfo, err = ss.FileInfo().Get(info.Id)
+	require.NoError(t, err)
+	require.Equal(t, 2, rinfo.ContentExtractionFailures)
+	require.Equal(t, strings.Repeat("a", model.ContentExtractionErrorMaxLength), rinfo.ContentExtractionError)
+
+	// A multi-byte rune across the limit is left out rather than split
+	err = ss.FileInfo().SetContentExtractionFailure(rctx, info.Id, strings.Repeat("a", model.ContentExtractionErrorMaxLength-1)+"é")
+	require.NoError(t, err)
+
+	rinfo, err = ss.FileInfo().Get(info.Id)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("a", model.ContentExtractionErrorMaxLength-1), rinfo.ContentExtractionError)
+
+	err = ss.FileInfo().SetContent(rctx, info.Id, "content")
+	require.NoError(t, err)
+
+	rinfo, err = ss.FileInfo().Get(info.Id)
+	require.NoError(t, err)
+	require.Equal(t, "content", rinfo.Content)
+	require.Zero(t, rinfo.ContentExtractionFailures)
+	require.Empty(t, rinfo.ContentExtractionError)
+}
+
 func testFileInfoStoreGetFilesBatchForIndexing(t *testing.T, rctx request.CTX, ss store.Store) {
 	c1 := &model.Channel{}
 	c1.TeamId = model.NewId()
@@ -787,6 +832,23 @@ func testFileInfoStoreGetFilesBatchForIndexing(t *testing.T, rctx request.CTX, s
 	r, err = ss.FileInfo().GetFilesBatchForIndexing(r[0].CreateAt, r[0].Id, true, 2)
 	require.NoError(t, err)
 	require.Len(t, r, 0, "Expected 0 posts in results. Got %v", len(r))
+
+	// Filtering by team or channel, deleted files included
+	r, err = ss.FileInfo().GetFilteredFilesBatchForIndexing(f1.CreateAt-1, "", store.IndexingBatchFilter{ChannelID: c1.Id}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 2, "Expected 2 files in results. Got %v", len(r))
+	assert.Equal(t, f1.Id, r[0].Id)
+	assert.Equal(t, f3.Id, r[1].Id)
+
+	r, err = ss.FileInfo().GetFilteredFilesBatchForIndexing(r[0].CreateAt, r[0].Id, store.IndexingBatchFilter{ChannelID: c1.Id}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 1, "Expected 1 file in results. Got %v", len(r))
+	assert.Equal(t, f3.Id, r[0].Id)
+
+	r, err = ss.FileInfo().GetFilteredFilesBatchForIndexing(f1.CreateAt-1, "", store.IndexingBatchFilter{TeamID: c2.TeamId}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 1, "Expected 1 file in results. Got %v", len(r))
+	assert.Equal(t, f2.Id, r[0].Id)
 }
 
 func testFileInfoStoreCountAll(t *testing.T, rctx request.CTX, ss store.Store) {
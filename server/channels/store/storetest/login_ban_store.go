@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestLoginBanStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveAndGetActive", func(t *testing.T) { testLoginBanSaveAndGetActive(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testLoginBanDelete(t, rctx, ss) })
+	t.Run("DeleteExpired", func(t *testing.T) { testLoginBanDeleteExpired(t, rctx, ss) })
+}
+
+func testLoginBanSaveAndGetActive(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { require.NoError(t, ss.LoginBan().DeleteAll()) })
+
+	now := model.GetMillis()
+	address, err := ss.LoginBan().Save(&model.LoginBan{
+		IP:             "203.0.113.7/32",
+		Reason:         model.LoginBanReasonAddress,
+		FailedAttempts: 30,
+		ExpireAt:       now + 60000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", address.IP)
+	assert.NotZero(t, address.CreateAt)
+
+	subnet, err := ss.LoginBan().Save(&model.LoginBan{
+		IP:             "2001:db8::/64",
+		Reason:         model.LoginBanReasonSubnet,
+		FailedAttempts: 100,
+		ExpireAt:       now + 120000,
+	})
+	require.NoError(t, err)
+
+	_, err = ss.LoginBan().Save(&model.LoginBan{
+		IP:       "198.51.100.0/24",
+		Reason:   model.LoginBanReasonSubnet,
+		CreateAt: now - 120000,
+		ExpireAt: now - 60000,
+	})
+	require.NoError(t, err)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.LoginBan().Save(&model.LoginBan{IP: "unknown", Reason: model.LoginBanReasonAddress, ExpireAt: now + 60000})
+		var appErr *model.AppError
+		require.ErrorAs(t, err, &appErr)
+	})
+
+	bans, err := ss.LoginBan().GetActive(now)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.LoginBan{subnet, address}, bans)
+
+	t.Run("replaces the ban of the same address", func(t *testing.T) {
+		renewed, err := ss.LoginBan().Save(&model.LoginBan{
+			IP:             "203.0.113.7",
+			Reason:         model.LoginBanReasonAddress,
+			FailedAttempts: 31,
+			ExpireAt:       now + 180000,
+		})
+		require.NoError(t, err)
+
+		bans, err := ss.LoginBan().GetActive(now)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.LoginBan{renewed, subnet}, bans)
+	})
+}
+
+func testLoginBanDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { require.NoError(t, ss.LoginBan().DeleteAll()) })
+
+	now := model.GetMillis()
+	for _, ip := range []string{"203.0.113.7", "203.0.113.8"} {
+		_, err := ss.LoginBan().Save(&model.LoginBan{IP: ip, Reason: model.LoginBanReasonAddress, ExpireAt: now + 60000})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, ss.LoginBan().Delete("203.0.113.7"))
+
+	err := ss.LoginBan().Delete("203.0.113.7")
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	bans, err := ss.LoginBan().GetActive(now)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "203.0.113.8", bans[0].IP)
+
+	require.NoError(t, ss.LoginBan().DeleteAll())
+
+	bans, err = ss.LoginBan().GetActive(now)
+	require.NoError(t, err)
+	assert.Empty(t, bans)
+}
+
+func testLoginBanDeleteExpired(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Cleanup(func() { require.NoError(t, ss.LoginBan().DeleteAll()) })
+
+	now := model.GetMillis()
+	_, err := ss.LoginBan().Save(&model.LoginBan{IP: "203.0.113.7", Reason: model.LoginBanReasonAddress, CreateAt: now - 2000, ExpireAt: now - 1000})
+	require.NoError(t, err)
+	_, err = ss.LoginBan().Save(&model.LoginBan{IP: "203.0.113.8", Reason: model.LoginBanReasonAddress, CreateAt: now - 2000, ExpireAt: now})
+	require.NoError(t, err)
+	_, err = ss.LoginBan().Save(&model.LoginBan{IP: "203.0.113.9", Reason: model.LoginBanReasonAddress, ExpireAt: now + 60000})
+	require.NoError(t, err)
+
+	deleted, err := ss.LoginBan().DeleteExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	bans, err := ss.LoginBan().GetActive(0)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	assert.Equal(t, "203.0.113.9", bans[0].IP)
+}
@@ -5,9 +5,13 @@
 package mocks
 
 import (
+	mock "github.com/stretchr/testify/mock"
+
 	model "github.com/mattermost/mattermost/server/public/model"
+
 	request "github.com/mattermost/mattermost/server/public/shared/request"
-	mock "github.com/stretchr/testify/mock"
+
+	store "github.com/mattermost/mattermost/server/v8/channels/store"
 )
 
 // FileInfoStore is an autogenerated mock type for the FileInfoStore type
@@ -232,6 +236,36 @@ func (_m *FileInfoStore) GetFilesBatchForIndexing(startTime int64, startFileID s
 	return r0, r1
 }
 
+// GetFilteredFilesBatchForIndexing provides a mock function with given fields: startTime, startFileID, filter, limit
+func (_m *FileInfoStore) GetFilteredFilesBatchForIndexing(startTime int64, startFileID string, filter store.IndexingBatchFilter, limit int) ([]*model.FileForIndexing, error) {
+	ret := _m.Called(startTime, startFileID, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFilteredFilesBatchForIndexing")
+	}
+
+	var r0 []*model.FileForIndexing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, string, store.IndexingBatchFilter, int) ([]*model.FileForIndexing, error)); ok {
+		return rf(startTime, startFileID, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string, store.IndexingBatchFilter, int) []*model.FileForIndexing); ok {
+		r0 = rf(startTime, startFileID, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileForIndexing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string, store.IndexingBatchFilter, int) error); ok {
+		r1 = rf(startTime, startFileID, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetForPost provides a mock function with given fields: postID, readFromMaster, includeDeleted, allowFromCache
 func (_m *FileInfoStore) GetForPost(postID string, readFromMaster bool, includeDeleted bool, allowFromCache bool) ([]*model.FileInfo, error) {
 	ret := _m.Called(postID, readFromMaster, includeDeleted, allowFromCache)
@@ -619,6 +653,24 @@ func (_m *FileInfoStore) SetContent(ctx request.CTX, fileID string, content stri
 	return r0
 }
 
+// SetContentExtractionFailure provides a mock function with given fields: ctx, fileID, extractionErr
+func (_m *FileInfoStore) SetContentExtractionFailure(ctx request.CTX, fileID string, extractionErr string) error {
+	ret := _m.Called(ctx, fileID, extractionErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetContentExtractionFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.CTX, string, string) error); ok {
+		r0 = rf(ctx, fileID, extractionErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Upsert provides a mock function with given fields: rctx, info
 func (_m *FileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	ret := _m.Called(rctx, info)
@@ -0,0 +1,154 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/mattermost/mattermost/server/public/model"
+)
+
+// LoginBanStore is an autogenerated mock type for the LoginBanStore type
+type LoginBanStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ip
+func (_m *LoginBanStore) Delete(ip string) error {
+	ret := _m.Called(ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAll provides a mock function with no fields
+func (_m *LoginBanStore) DeleteAll() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *LoginBanStore) DeleteExpired(now int64) (int64, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetActive provides a mock function with given fields: now
+func (_m *LoginBanStore) GetActive(now int64) ([]*model.LoginBan, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActive")
+	}
+
+	var r0 []*model.LoginBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]*model.LoginBan, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []*model.LoginBan); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoginBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ban
+func (_m *LoginBanStore) Save(ban *model.LoginBan) (*model.LoginBan, error) {
+	ret := _m.Called(ban)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *model.LoginBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.LoginBan) (*model.LoginBan, error)); ok {
+		return rf(ban)
+	}
+	if rf, ok := ret.Get(0).(func(*model.LoginBan) *model.LoginBan); ok {
+		r0 = rf(ban)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.LoginBan) error); ok {
+		r1 = rf(ban)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLoginBanStore creates a new instance of LoginBanStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginBanStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginBanStore {
+	mock := &LoginBanStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
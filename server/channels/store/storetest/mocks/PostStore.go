@@ -189,6 +189,43 @@ func (_m *PostStore) Get(ctx context.Context, id string, opts model.GetPostsOpti
 	return r0, r1
 }
 
+// GetAllPosts provides a mock function with given fields: options
+func (_m *PostStore) GetAllPosts(options *model.GetAllPostsOptions) (*model.PostList, int, error) {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPosts")
+	}
+
+	var r0 *model.PostList
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*model.GetAllPostsOptions) (*model.PostList, int, error)); ok {
+		return rf(options)
+	}
+	if rf, ok := ret.Get(0).(func(*model.GetAllPostsOptions) *model.PostList); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.GetAllPostsOptions) int); ok {
+		r1 = rf(options)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*model.GetAllPostsOptions) error); ok {
+		r2 = rf(options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetDirectPostParentsForExportAfter provides a mock function with given fields: limit, afterID, includeArchivedChannels
 func (_m *PostStore) GetDirectPostParentsForExportAfter(limit int, afterID string, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
 	ret := _m.Called(limit, afterID, includeArchivedChannels)
@@ -267,6 +304,36 @@ func (_m *PostStore) GetEtag(channelID string, allowFromCache bool, collapsedThr
 	return r0
 }
 
+// GetFilteredPostsBatchForIndexing provides a mock function with given fields: startTime, startPostID, filter, limit
+func (_m *PostStore) GetFilteredPostsBatchForIndexing(startTime int64, startPostID string, filter store.IndexingBatchFilter, limit int) ([]*model.PostForIndexing, error) {
+	ret := _m.Called(startTime, startPostID, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFilteredPostsBatchForIndexing")
+	}
+
+	var r0 []*model.PostForIndexing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, string, store.IndexingBatchFilter, int) ([]*model.PostForIndexing, error)); ok {
+		return rf(startTime, startPostID, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string, store.IndexingBatchFilter, int) []*model.PostForIndexing); ok {
+		r0 = rf(startTime, startPostID, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostForIndexing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string, store.IndexingBatchFilter, int) error); ok {
+		r1 = rf(startTime, startPostID, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFlaggedPosts provides a mock function with given fields: userID, offset, limit
 func (_m *PostStore) GetFlaggedPosts(userID string, offset int, limit int) (*model.PostList, error) {
 	ret := _m.Called(userID, offset, limit)
@@ -1209,6 +1276,36 @@ func (_m *PostStore) RefreshPostStats() error {
 	return r0
 }
 
+// RemovePostsBetween provides a mock function with given fields: options
+func (_m *PostStore) RemovePostsBetween(options *model.RemovePostsBetweenOptions) ([]*model.Post, error) {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemovePostsBetween")
+	}
+
+	var r0 []*model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.RemovePostsBetweenOptions) ([]*model.Post, error)); ok {
+		return rf(options)
+	}
+	if rf, ok := ret.Get(0).(func(*model.RemovePostsBetweenOptions) []*model.Post); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.RemovePostsBetweenOptions) error); ok {
+		r1 = rf(options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: rctx, post
 func (_m *PostStore) Save(rctx request.CTX, post *model.Post) (*model.Post, error) {
 	ret := _m.Called(rctx, post)
@@ -1384,73 +1481,6 @@ func (_m *PostStore) Update(rctx request.CTX, newPost *model.Post, oldPost *mode
 	return r0, r1
 }
 
-// GetAllPosts provides a mock function with given fields: options
-func (_m *PostStore) GetAllPosts(options *model.GetAllPostsOptions) (*model.PostList, int, error) {
-	ret := _m.Called(options)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetAllPosts")
-	}
-
-	var r0 *model.PostList
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(*model.GetAllPostsOptions) (*model.PostList, int, error)); ok {
-		return rf(options)
-	}
-	if rf, ok := ret.Get(0).(func(*model.GetAllPostsOptions) *model.PostList); ok {
-		r0 = rf(options)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.PostList)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(*model.GetAllPostsOptions) int); ok {
-		r1 = rf(options)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-
-	if rf, ok := ret.Get(2).(func(*model.GetAllPostsOptions) error); ok {
-		r2 = rf(options)
-	} else {
-		r2 = ret.Error(2)
-	}
-
-	return r0, r1, r2
-}
-
-// RemovePostsBetween provides a mock function with given fields: options
-func (_m *PostStore) RemovePostsBetween(options *model.RemovePostsBetweenOptions) ([]*model.Post, error) {
-	ret := _m.Called(options)
-
-	if len(ret) == 0 {
-		panic("no return value specified for RemovePostsBetween")
-	}
-
-	var r0 []*model.Post
-	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.RemovePostsBetweenOptions) ([]*model.Post, error)); ok {
-		return rf(options)
-	}
-	if rf, ok := ret.Get(0).(func(*model.RemovePostsBetweenOptions) []*model.Post); ok {
-		r0 = rf(options)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Post)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(*model.RemovePostsBetweenOptions) error); ok {
-		r1 = rf(options)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // NewPostStore creates a new instance of PostStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPostStore(t interface {
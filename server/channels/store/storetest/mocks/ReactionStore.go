@@ -75,21 +75,33 @@ func (_m *ReactionStore) Delete(reaction *model.Reaction) (*model.Reaction, erro
 }
 
 // DeleteAllWithEmojiName provides a mock function with given fields: emojiName
-func (_m *ReactionStore) DeleteAllWithEmojiName(emojiName string) error {
+func (_m *ReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
 	ret := _m.Called(emojiName)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteAllWithEmojiName")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(emojiName)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
 		r0 = rf(emojiName)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(emojiName)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // DeleteOrphanedRowsByIds provides a mock function with given fields: r
@@ -295,21 +307,33 @@ func (_m *ReactionStore) PermanentDeleteBatch(endTime int64, limit int64) (int64
 }
 
 // PermanentDeleteByUser provides a mock function with given fields: userID
-func (_m *ReactionStore) PermanentDeleteByUser(userID string) error {
+func (_m *ReactionStore) PermanentDeleteByUser(userID string) ([]string, error) {
 	ret := _m.Called(userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PermanentDeleteByUser")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
 		r0 = rf(userID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // Save provides a mock function with given fields: reaction
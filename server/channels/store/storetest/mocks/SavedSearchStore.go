@@ -0,0 +1,216 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/mattermost/mattermost/server/public/model"
+)
+
+// SavedSearchStore is an autogenerated mock type for the SavedSearchStore type
+type SavedSearchStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *SavedSearchStore) Delete(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: id
+func (_m *SavedSearchStore) Get(id string) (*model.SavedSearch, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.SavedSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.SavedSearch, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.SavedSearch); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SavedSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlertsForTeam provides a mock function with given fields: teamID
+func (_m *SavedSearchStore) GetAlertsForTeam(teamID string) ([]*model.SavedSearch, error) {
+	ret := _m.Called(teamID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAlertsForTeam")
+	}
+
+	var r0 []*model.SavedSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*model.SavedSearch, error)); ok {
+		return rf(teamID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*model.SavedSearch); ok {
+		r0 = rf(teamID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SavedSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(teamID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForUser provides a mock function with given fields: userID
+func (_m *SavedSearchStore) GetForUser(userID string) ([]*model.SavedSearch, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForUser")
+	}
+
+	var r0 []*model.SavedSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*model.SavedSearch, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*model.SavedSearch); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SavedSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PermanentDeleteByUser provides a mock function with given fields: userID
+func (_m *SavedSearchStore) PermanentDeleteByUser(userID string) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PermanentDeleteByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: savedSearch
+func (_m *SavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	ret := _m.Called(savedSearch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *model.SavedSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.SavedSearch) (*model.SavedSearch, error)); ok {
+		return rf(savedSearch)
+	}
+	if rf, ok := ret.Get(0).(func(*model.SavedSearch) *model.SavedSearch); ok {
+		r0 = rf(savedSearch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SavedSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.SavedSearch) error); ok {
+		r1 = rf(savedSearch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: savedSearch
+func (_m *SavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	ret := _m.Called(savedSearch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *model.SavedSearch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.SavedSearch) (*model.SavedSearch, error)); ok {
+		return rf(savedSearch)
+	}
+	if rf, ok := ret.Get(0).(func(*model.SavedSearch) *model.SavedSearch); ok {
+		r0 = rf(savedSearch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SavedSearch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.SavedSearch) error); ok {
+		r1 = rf(savedSearch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSavedSearchStore creates a new instance of SavedSearchStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSavedSearchStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SavedSearchStore {
+	mock := &SavedSearchStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
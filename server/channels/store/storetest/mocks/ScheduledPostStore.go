@@ -122,6 +122,36 @@ func (_m *ScheduledPostStore) GetPendingScheduledPosts(beforeTime int64, afterTi
 	return r0, r1
 }
 
+// GetScheduledPostsForChannel provides a mock function with given fields: channelId
+func (_m *ScheduledPostStore) GetScheduledPostsForChannel(channelId string) ([]*model.ScheduledPost, error) {
+	ret := _m.Called(channelId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledPostsForChannel")
+	}
+
+	var r0 []*model.ScheduledPost
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*model.ScheduledPost, error)); ok {
+		return rf(channelId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*model.ScheduledPost); ok {
+		r0 = rf(channelId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ScheduledPost)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(channelId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetScheduledPostsForUser provides a mock function with given fields: userId, teamId
 func (_m *ScheduledPostStore) GetScheduledPostsForUser(userId string, teamId string) ([]*model.ScheduledPost, error) {
 	ret := _m.Called(userId, teamId)
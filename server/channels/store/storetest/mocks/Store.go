@@ -548,6 +548,26 @@ func (_m *Store) Group() store.GroupStore {
 	return r0
 }
 
+// Invite provides a mock function with no fields
+func (_m *Store) Invite() store.InviteStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invite")
+	}
+
+	var r0 store.InviteStore
+	if rf, ok := ret.Get(0).(func() store.InviteStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.InviteStore)
+		}
+	}
+
+	return r0
+}
+
 // Job provides a mock function with no fields
 func (_m *Store) Job() store.JobStore {
 	ret := _m.Called()
@@ -633,6 +653,26 @@ func (_m *Store) Logger() mlog.LoggerIFace {
 	return r0
 }
 
+// LoginBan provides a mock function with no fields
+func (_m *Store) LoginBan() store.LoginBanStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginBan")
+	}
+
+	var r0 store.LoginBanStore
+	if rf, ok := ret.Get(0).(func() store.LoginBanStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.LoginBanStore)
+		}
+	}
+
+	return r0
+}
+
 // MarkSystemRanUnitTests provides a mock function with no fields
 func (_m *Store) MarkSystemRanUnitTests() {
 	_m.Called()
@@ -1019,6 +1059,26 @@ func (_m *Store) Role() store.RoleStore {
 	return r0
 }
 
+// SavedSearch provides a mock function with no fields
+func (_m *Store) SavedSearch() store.SavedSearchStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavedSearch")
+	}
+
+	var r0 store.SavedSearchStore
+	if rf, ok := ret.Get(0).(func() store.SavedSearchStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SavedSearchStore)
+		}
+	}
+
+	return r0
+}
+
 // ScheduledPost provides a mock function with no fields
 func (_m *Store) ScheduledPost() store.ScheduledPostStore {
 	ret := _m.Called()
@@ -1403,20 +1463,20 @@ func (_m *Store) Whitelist() store.WhitelistStore {
 	return r0
 }
 
-// Invite provides a mock function with no fields
-func (_m *Store) Invite() store.InviteStore {
+// WhitelistPolicy provides a mock function with no fields
+func (_m *Store) WhitelistPolicy() store.WhitelistPolicyStore {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for Invite")
+		panic("no return value specified for WhitelistPolicy")
 	}
 
-	var r0 store.InviteStore
-	if rf, ok := ret.Get(0).(func() store.InviteStore); ok {
+	var r0 store.WhitelistPolicyStore
+	if rf, ok := ret.Get(0).(func() store.WhitelistPolicyStore); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(store.InviteStore)
+			r0 = ret.Get(0).(store.WhitelistPolicyStore)
 		}
 	}
 
@@ -0,0 +1,294 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/mattermost/mattermost/server/public/model"
+)
+
+// WhitelistPolicyStore is an autogenerated mock type for the WhitelistPolicyStore type
+type WhitelistPolicyStore struct {
+	mock.Mock
+}
+
+// AddTarget provides a mock function with given fields: target
+func (_m *WhitelistPolicyStore) AddTarget(target *model.WhitelistPolicyTarget) error {
+	ret := _m.Called(target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTarget")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicyTarget) error); ok {
+		r0 = rf(target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *WhitelistPolicyStore) Delete(id string) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: id
+func (_m *WhitelistPolicyStore) Get(id string) (*model.WhitelistPolicy, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.WhitelistPolicy, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.WhitelistPolicy); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: offset, limit
+func (_m *WhitelistPolicyStore) GetAll(offset int, limit int) ([]*model.WhitelistPolicy, error) {
+	ret := _m.Called(offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []*model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]*model.WhitelistPolicy, error)); ok {
+		return rf(offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []*model.WhitelistPolicy); ok {
+		r0 = rf(offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByName provides a mock function with given fields: name
+func (_m *WhitelistPolicyStore) GetByName(name string) (*model.WhitelistPolicy, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByName")
+	}
+
+	var r0 *model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.WhitelistPolicy, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.WhitelistPolicy); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForTargets provides a mock function with given fields: teamIds, groupIds, roleNames
+func (_m *WhitelistPolicyStore) GetForTargets(teamIds []string, groupIds []string, roleNames []string) ([]*model.WhitelistPolicy, error) {
+	ret := _m.Called(teamIds, groupIds, roleNames)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForTargets")
+	}
+
+	var r0 []*model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string, []string, []string) ([]*model.WhitelistPolicy, error)); ok {
+		return rf(teamIds, groupIds, roleNames)
+	}
+	if rf, ok := ret.Get(0).(func([]string, []string, []string) []*model.WhitelistPolicy); ok {
+		r0 = rf(teamIds, groupIds, roleNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, []string, []string) error); ok {
+		r1 = rf(teamIds, groupIds, roleNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTargets provides a mock function with given fields: policyId
+func (_m *WhitelistPolicyStore) GetTargets(policyId string) ([]*model.WhitelistPolicyTarget, error) {
+	ret := _m.Called(policyId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTargets")
+	}
+
+	var r0 []*model.WhitelistPolicyTarget
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*model.WhitelistPolicyTarget, error)); ok {
+		return rf(policyId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*model.WhitelistPolicyTarget); ok {
+		r0 = rf(policyId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistPolicyTarget)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(policyId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTarget provides a mock function with given fields: target
+func (_m *WhitelistPolicyStore) RemoveTarget(target *model.WhitelistPolicyTarget) error {
+	ret := _m.Called(target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTarget")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicyTarget) error); ok {
+		r0 = rf(target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: policy
+func (_m *WhitelistPolicyStore) Save(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	ret := _m.Called(policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicy) (*model.WhitelistPolicy, error)); ok {
+		return rf(policy)
+	}
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicy) *model.WhitelistPolicy); ok {
+		r0 = rf(policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.WhitelistPolicy) error); ok {
+		r1 = rf(policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: policy
+func (_m *WhitelistPolicyStore) Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	ret := _m.Called(policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *model.WhitelistPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicy) (*model.WhitelistPolicy, error)); ok {
+		return rf(policy)
+	}
+	if rf, ok := ret.Get(0).(func(*model.WhitelistPolicy) *model.WhitelistPolicy); ok {
+		r0 = rf(policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.WhitelistPolicy) error); ok {
+		r1 = rf(policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWhitelistPolicyStore creates a new instance of WhitelistPolicyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWhitelistPolicyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WhitelistPolicyStore {
+	mock := &WhitelistPolicyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
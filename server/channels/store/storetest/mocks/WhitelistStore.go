@@ -0,0 +1,299 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+// Regenerate this file using `make store-mocks`.
+
+package mocks
+
+import (
+	model "github.com/mattermost/mattermost/server/public/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WhitelistStore is an autogenerated mock type for the WhitelistStore type
+type WhitelistStore struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: whitelistItem
+func (_m *WhitelistStore) Add(whitelistItem *model.WhitelistItem) error {
+	ret := _m.Called(whitelistItem)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistItem) error); ok {
+		r0 = rf(whitelistItem)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearCaches provides a mock function with no fields
+func (_m *WhitelistStore) ClearCaches() {
+	_m.Called()
+}
+
+// Delete provides a mock function with given fields: whitelistItem
+func (_m *WhitelistStore) Delete(whitelistItem *model.WhitelistItem) error {
+	ret := _m.Called(whitelistItem)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistItem) error); ok {
+		r0 = rf(whitelistItem)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDenialsBefore provides a mock function with given fields: endTime, limit
+func (_m *WhitelistStore) DeleteDenialsBefore(endTime int64, limit int64) (int64, error) {
+	ret := _m.Called(endTime, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDenialsBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(endTime, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(endTime, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(endTime, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *WhitelistStore) DeleteExpired(now int64) (int64, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllAfter provides a mock function with given fields: limit, afterUserId, afterIP
+func (_m *WhitelistStore) GetAllAfter(limit int, afterUserId string, afterIP string) ([]*model.WhitelistItemForExport, error) {
+	ret := _m.Called(limit, afterUserId, afterIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllAfter")
+	}
+
+	var r0 []*model.WhitelistItemForExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, string) ([]*model.WhitelistItemForExport, error)); ok {
+		return rf(limit, afterUserId, afterIP)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, string) []*model.WhitelistItemForExport); ok {
+		r0 = rf(limit, afterUserId, afterIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistItemForExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, string) error); ok {
+		r1 = rf(limit, afterUserId, afterIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserId provides a mock function with given fields: userId
+func (_m *WhitelistStore) GetByUserId(userId string) ([]*model.WhitelistItem, error) {
+	ret := _m.Called(userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserId")
+	}
+
+	var r0 []*model.WhitelistItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*model.WhitelistItem, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*model.WhitelistItem); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDenials provides a mock function with given fields: filter
+func (_m *WhitelistStore) GetDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDenials")
+	}
+
+	var r0 []*model.WhitelistDenial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(*model.WhitelistDenialFilter) []*model.WhitelistDenial); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WhitelistDenial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.WhitelistDenialFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrefixSetByUserId provides a mock function with given fields: userId
+func (_m *WhitelistStore) GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error) {
+	ret := _m.Called(userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrefixSetByUserId")
+	}
+
+	var r0 *model.WhitelistPrefixSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.WhitelistPrefixSet, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.WhitelistPrefixSet); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistPrefixSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementDenial provides a mock function with given fields: id, lastAt
+func (_m *WhitelistStore) IncrementDenial(id string, lastAt int64) error {
+	ret := _m.Called(id, lastAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementDenial")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(id, lastAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InvalidateWhitelistCacheForUser provides a mock function with given fields: userId
+func (_m *WhitelistStore) InvalidateWhitelistCacheForUser(userId string) {
+	_m.Called(userId)
+}
+
+// SaveDenial provides a mock function with given fields: denial
+func (_m *WhitelistStore) SaveDenial(denial *model.WhitelistDenial) (*model.WhitelistDenial, error) {
+	ret := _m.Called(denial)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDenial")
+	}
+
+	var r0 *model.WhitelistDenial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.WhitelistDenial) (*model.WhitelistDenial, error)); ok {
+		return rf(denial)
+	}
+	if rf, ok := ret.Get(0).(func(*model.WhitelistDenial) *model.WhitelistDenial); ok {
+		r0 = rf(denial)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WhitelistDenial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.WhitelistDenial) error); ok {
+		r1 = rf(denial)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWhitelistStore creates a new instance of WhitelistStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWhitelistStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WhitelistStore {
+	mock := &WhitelistStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
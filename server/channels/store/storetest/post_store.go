@@ -4161,6 +4161,11 @@ func testPostStoreGetPostsBatchForIndexing(t *testing.T, rctx request.CTX, ss st
 	r, err := ss.Post().GetPostsBatchForIndexing(o1.CreateAt-1, "", 100)
 	require.NoError(t, err)
 	require.Len(t, r, 3, "Expected 3 posts in results. Got %v", len(r))
+	for _, post := range r {
+		if post.Id == o1.Id {
+			assert.Equal(t, int64(1), post.ReplyCount)
+		}
+	}
 
 	// Testing pagination
 	r, err = ss.Post().GetPostsBatchForIndexing(o1.CreateAt-1, "", 1)
@@ -4178,6 +4183,27 @@ func testPostStoreGetPostsBatchForIndexing(t *testing.T, rctx request.CTX, ss st
 	r, err = ss.Post().GetPostsBatchForIndexing(r[0].CreateAt, r[0].Id, 1)
 	require.NoError(t, err)
 	require.Len(t, r, 0, "Expected 0 post in results. Got %v", len(r))
+
+	// Filtering by team or channel
+	r, err = ss.Post().GetFilteredPostsBatchForIndexing(o1.CreateAt-1, "", store.IndexingBatchFilter{ChannelID: c1.Id}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 2, "Expected 2 posts in results. Got %v", len(r))
+	for _, post := range r {
+		assert.Equal(t, c1.Id, post.ChannelId)
+		assert.Equal(t, c1.TeamId, post.TeamId)
+		if post.Id == o1.Id {
+			assert.Equal(t, int64(1), post.ReplyCount)
+		}
+	}
+
+	r, err = ss.Post().GetFilteredPostsBatchForIndexing(r[0].CreateAt, r[0].Id, store.IndexingBatchFilter{ChannelID: c1.Id}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 1, "Expected 1 post in results. Got %v", len(r))
+
+	r, err = ss.Post().GetFilteredPostsBatchForIndexing(o1.CreateAt-1, "", store.IndexingBatchFilter{TeamID: c2.TeamId}, 100)
+	require.NoError(t, err)
+	require.Len(t, r, 1, "Expected 1 post in results. Got %v", len(r))
+	assert.Equal(t, c2.Id, r[0].ChannelId)
 }
 
 func testPostStorePermanentDeleteBatch(t *testing.T, rctx request.CTX, ss store.Store) {
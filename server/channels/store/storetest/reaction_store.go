@@ -537,8 +537,9 @@ func testReactionDeleteAllWithEmojiName(t *testing.T, rctx request.CTX, ss store
 		}
 	}
 
-	err := ss.Reaction().DeleteAllWithEmojiName(emojiToDelete)
+	postIds, err := ss.Reaction().DeleteAllWithEmojiName(emojiToDelete)
 	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{post.Id, post3.Id}, postIds)
 
 	// check that the reactions were deleted
 	returned, err := ss.Reaction().GetForPost(post.Id, false)
@@ -622,8 +623,9 @@ func testPermanentDeleteByUser(t *testing.T, rctx request.CTX, ss store.Store) {
 		require.NoError(t, err)
 	}
 
-	err := ss.Reaction().PermanentDeleteByUser(userId)
+	postIds, err := ss.Reaction().PermanentDeleteByUser(userId)
 	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{post.Id, post2.Id, post3.Id}, postIds)
 
 	// check that the reactions were deleted
 	returned, err := ss.Reaction().GetForPost(post.Id, false)
@@ -876,7 +878,7 @@ func testReactionDeadlock(t *testing.T, rctx request.CTX, ss store.Store) {
 	// 1st tx
 	go func() {
 		defer wg.Done()
-		err := ss.Reaction().DeleteAllWithEmojiName(reaction1.EmojiName)
+		_, err := ss.Reaction().DeleteAllWithEmojiName(reaction1.EmojiName)
 		require.NoError(t, err)
 	}()
 
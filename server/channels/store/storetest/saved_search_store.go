@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestSavedSearchStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveAndGet", func(t *testing.T) { testSavedSearchSaveAndGet(t, rctx, ss) })
+	t.Run("Update", func(t *testing.T) { testSavedSearchUpdate(t, rctx, ss) })
+	t.Run("GetAlertsForTeam", func(t *testing.T) { testSavedSearchGetAlertsForTeam(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testSavedSearchDelete(t, rctx, ss) })
+}
+
+func testSavedSearchSaveAndGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	saved, err := ss.SavedSearch().Save(&model.SavedSearch{
+		UserId: userID,
+		Name:   " outages ",
+		Terms:  "error OR failure in:alerts",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, saved.Id)
+	assert.Equal(t, "outages", saved.Name)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "outages", Terms: "error"})
+		var uniqueErr *store.ErrUniqueConstraint
+		require.ErrorAs(t, err, &uniqueErr)
+
+		_, err = ss.SavedSearch().Save(&model.SavedSearch{UserId: model.NewId(), Name: "outages", Terms: "error"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "empty"})
+		var appErr *model.AppError
+		require.ErrorAs(t, err, &appErr)
+	})
+
+	got, err := ss.SavedSearch().Get(saved.Id)
+	require.NoError(t, err)
+	assert.Equal(t, saved, got)
+
+	_, err = ss.SavedSearch().Get(model.NewId())
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	other, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "deployments", Terms: "deploy*"})
+	require.NoError(t, err)
+
+	savedSearches, err := ss.SavedSearch().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.SavedSearch{other, saved}, savedSearches)
+}
+
+func testSavedSearchUpdate(t *testing.T, rctx request.CTX, ss store.Store) {
+	savedSearch, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: model.NewId(), Name: "outages", Terms: "error"})
+	require.NoError(t, err)
+
+	savedSearch.Terms = "error OR failure"
+	savedSearch.Alert = true
+	updated, err := ss.SavedSearch().Update(savedSearch)
+	require.NoError(t, err)
+
+	got, err := ss.SavedSearch().Get(savedSearch.Id)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+	assert.True(t, got.Alert)
+
+	_, err = ss.SavedSearch().Update(&model.SavedSearch{Id: model.NewId(), UserId: model.NewId(), Name: "missing", Terms: "error", CreateAt: 1})
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testSavedSearchGetAlertsForTeam(t *testing.T, rctx request.CTX, ss store.Store) {
+	teamID := model.NewId()
+	userID := model.NewId()
+
+	teamAlert, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, TeamId: teamID, Name: "team", Terms: "error", Alert: true})
+	require.NoError(t, err)
+	allTeamsAlert, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "all teams", Terms: "error", Alert: true})
+	require.NoError(t, err)
+	otherTeamAlert, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, TeamId: model.NewId(), Name: "other team", Terms: "error", Alert: true})
+	require.NoError(t, err)
+	noAlert, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, TeamId: teamID, Name: "no alert", Terms: "error"})
+	require.NoError(t, err)
+
+	alerts, err := ss.SavedSearch().GetAlertsForTeam(teamID)
+	require.NoError(t, err)
+	assert.Contains(t, alerts, teamAlert)
+	assert.Contains(t, alerts, allTeamsAlert)
+	assert.NotContains(t, alerts, otherTeamAlert)
+	assert.NotContains(t, alerts, noAlert)
+
+	require.NoError(t, ss.SavedSearch().PermanentDeleteByUser(userID))
+}
+
+func testSavedSearchDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userID := model.NewId()
+	savedSearch, err := ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "outages", Terms: "error"})
+	require.NoError(t, err)
+	_, err = ss.SavedSearch().Save(&model.SavedSearch{UserId: userID, Name: "deployments", Terms: "deploy*"})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.SavedSearch().Delete(savedSearch.Id))
+
+	_, err = ss.SavedSearch().Get(savedSearch.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	err = ss.SavedSearch().Delete(savedSearch.Id)
+	require.ErrorAs(t, err, &nfErr)
+
+	require.NoError(t, ss.SavedSearch().PermanentDeleteByUser(userID))
+	savedSearches, err := ss.SavedSearch().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Empty(t, savedSearches)
+}
@@ -21,6 +21,8 @@ func TestScheduledPostStore(t *testing.T, rctx request.CTX, ss store.Store, s Sq
 	t.Run("UpdatedScheduledPost", func(t *testing.T) { testUpdatedScheduledPost(t, rctx, ss, s) })
 	t.Run("UpdateOldScheduledPosts", func(t *testing.T) { testUpdateOldScheduledPosts(t, rctx, ss, s) })
 	t.Run("PermanentDeleteByUser", func(t *testing.T) { testPermanentDeleteScheduledPostsByUser(t, rctx, ss, s) })
+	t.Run("RecurringScheduledPosts", func(t *testing.T) { testRecurringScheduledPosts(t, rctx, ss, s) })
+	t.Run("GetScheduledPostsForChannel", func(t *testing.T) { testGetScheduledPostsForChannel(t, rctx, ss, s) })
 }
 
 func testCreateScheduledPost(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
@@ -551,3 +553,142 @@ func testPermanentDeleteScheduledPostsByUser(t *testing.T, rctx request.CTX, ss
 		assert.NoError(t, err)
 	})
 }
+
+func testRecurringScheduledPosts(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      model.NewId(),
+		Type:        model.ChannelTypeOpen,
+		Name:        "channel_name",
+		DisplayName: "Channel Name",
+	}, 1000)
+	require.NoError(t, err)
+	defer func() { _ = ss.Channel().PermanentDelete(rctx, channel.Id) }()
+
+	now := model.GetMillis()
+	userId := model.NewId()
+
+	newScheduledPost := func(scheduledAt int64, recurrenceRule string) *model.ScheduledPost {
+		scheduledPost, err := ss.ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    userId,
+				ChannelId: channel.Id,
+				Message:   "this is a scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			RecurrenceRule: recurrenceRule,
+		})
+		require.NoError(t, err)
+		return scheduledPost
+	}
+
+	// Two days late, which only recurring posts are fetched at
+	oneOff := newScheduledPost(now-2*86400000, "")
+	recurring := newScheduledPost(now-2*86400000, "FREQ=DAILY")
+	paused := newScheduledPost(now-1000, "FREQ=WEEKLY;COUNT=3")
+	defer func() {
+		_ = ss.ScheduledPost().PermanentlyDeleteScheduledPosts([]string{oneOff.Id, recurring.Id, paused.Id})
+	}()
+
+	paused.PausedAt = now
+	paused.OccurrenceCount = 1
+	require.NoError(t, ss.ScheduledPost().UpdatedScheduledPost(paused))
+
+	t.Run("should save the recurrence", func(t *testing.T) {
+		got, err := ss.ScheduledPost().Get(paused.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=3", got.RecurrenceRule)
+		assert.Equal(t, 1, got.OccurrenceCount)
+		assert.Equal(t, now, got.PausedAt)
+		assert.Equal(t, now-1000, got.RecurrenceStartAt)
+	})
+
+	t.Run("should fetch late recurring posts but not paused ones", func(t *testing.T) {
+		scheduledPosts, err := ss.ScheduledPost().GetPendingScheduledPosts(now, now-86400000, "", 10)
+		require.NoError(t, err)
+		require.Len(t, scheduledPosts, 1)
+		assert.Equal(t, recurring.Id, scheduledPosts[0].Id)
+	})
+
+	t.Run("should not mark old recurring posts as unable to send", func(t *testing.T) {
+		require.NoError(t, ss.ScheduledPost().UpdateOldScheduledPosts(now))
+
+		got, err := ss.ScheduledPost().Get(oneOff.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorUnableToSend, got.ErrorCode)
+
+		got, err = ss.ScheduledPost().Get(recurring.Id)
+		require.NoError(t, err)
+		assert.Empty(t, got.ErrorCode)
+	})
+}
+
+func testGetScheduledPostsForChannel(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	newChannel := func() *model.Channel {
+		channel, err := ss.Channel().Save(rctx, &model.Channel{
+			TeamId:      model.NewId(),
+			Type:        model.ChannelTypeOpen,
+			Name:        "channel_" + model.NewId(),
+			DisplayName: "Channel Name",
+		}, 1000)
+		require.NoError(t, err)
+		return channel
+	}
+
+	channel := newChannel()
+	otherChannel := newChannel()
+	defer func() {
+		_ = ss.Channel().PermanentDelete(rctx, channel.Id)
+		_ = ss.Channel().PermanentDelete(rctx, otherChannel.Id)
+	}()
+
+	now := model.GetMillis()
+	botId := model.NewId()
+
+	newScheduledPost := func(userId, channelId string, scheduledAt int64) *model.ScheduledPost {
+		scheduledPost, err := ss.ScheduledPost().CreateScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				CreateAt:  model.GetMillis(),
+				UserId:    userId,
+				ChannelId: channelId,
+				Message:   "this is a scheduled post",
+			},
+			ScheduledAt:    scheduledAt,
+			PublishAsBotId: botId,
+		})
+		require.NoError(t, err)
+		return scheduledPost
+	}
+
+	later := newScheduledPost(model.NewId(), channel.Id, now+200000)
+	sooner := newScheduledPost(model.NewId(), channel.Id, now+100000)
+	other := newScheduledPost(model.NewId(), otherChannel.Id, now+100000)
+	defer func() {
+		_ = ss.ScheduledPost().PermanentlyDeleteScheduledPosts([]string{later.Id, sooner.Id, other.Id})
+	}()
+
+	t.Run("should get the scheduled posts of every user in the channel", func(t *testing.T) {
+		scheduledPosts, err := ss.ScheduledPost().GetScheduledPostsForChannel(channel.Id)
+		require.NoError(t, err)
+		require.Len(t, scheduledPosts, 2)
+		assert.Equal(t, sooner.Id, scheduledPosts[0].Id)
+		assert.Equal(t, later.Id, scheduledPosts[1].Id)
+		assert.Equal(t, botId, scheduledPosts[0].PublishAsBotId)
+	})
+
+	t.Run("should save the last editor", func(t *testing.T) {
+		editorId := model.NewId()
+		sooner.LastEditorId = editorId
+		require.NoError(t, ss.ScheduledPost().UpdatedScheduledPost(sooner))
+
+		got, err := ss.ScheduledPost().Get(sooner.Id)
+		require.NoError(t, err)
+		assert.Equal(t, editorId, got.LastEditorId)
+	})
+
+	t.Run("should get no scheduled posts for a channel without any", func(t *testing.T) {
+		scheduledPosts, err := ss.ScheduledPost().GetScheduledPostsForChannel(model.NewId())
+		require.NoError(t, err)
+		assert.Empty(t, scheduledPosts)
+	})
+}
@@ -71,6 +71,11 @@ type Store struct {
 	PropertyValueStore              mocks.PropertyValueStore
 	AccessControlPolicyStore        mocks.AccessControlPolicyStore
 	AttributesStore                 mocks.AttributesStore
+	WhitelistStore                  mocks.WhitelistStore
+	WhitelistPolicyStore            mocks.WhitelistPolicyStore
+	SavedSearchStore                mocks.SavedSearchStore
+	LoginBanStore                   mocks.LoginBanStore
+	InviteStore                     mocks.InviteStore
 }
 
 func (s *Store) SetContext(context context.Context)            { s.context = context }
@@ -162,6 +167,16 @@ func (s *Store) AccessControlPolicy() store.AccessControlPolicyStore {
 func (s *Store) Attributes() store.AttributesStore {
 	return &s.AttributesStore
 }
+func (s *Store) Whitelist() store.WhitelistStore { return &s.WhitelistStore }
+func (s *Store) WhitelistPolicy() store.WhitelistPolicyStore {
+	return &s.WhitelistPolicyStore
+}
+
+func (s *Store) SavedSearch() store.SavedSearchStore {
+	return &s.SavedSearchStore
+}
+func (s *Store) LoginBan() store.LoginBanStore { return &s.LoginBanStore }
+func (s *Store) Invite() store.InviteStore     { return &s.InviteStore }
 
 func (s *Store) AssertExpectations(t mock.TestingT) bool {
 	return mock.AssertExpectationsForObjects(t,
@@ -207,6 +222,11 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.ScheduledPostStore,
 		&s.AccessControlPolicyStore,
 		&s.AttributesStore,
+		&s.WhitelistStore,
+		&s.WhitelistPolicyStore,
+		&s.SavedSearchStore,
+		&s.LoginBanStore,
+		&s.InviteStore,
 	)
 }
 
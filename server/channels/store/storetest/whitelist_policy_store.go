@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestWhitelistPolicyStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("SaveAndGet", func(t *testing.T) { testWhitelistPolicySaveAndGet(t, rctx, ss) })
+	t.Run("Update", func(t *testing.T) { testWhitelistPolicyUpdate(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testWhitelistPolicyDelete(t, rctx, ss) })
+	t.Run("Targets", func(t *testing.T) { testWhitelistPolicyTargets(t, rctx, ss) })
+	t.Run("GetForTargets", func(t *testing.T) { testWhitelistPolicyGetForTargets(t, rctx, ss) })
+}
+
+func testWhitelistPolicySaveAndGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	policy := &model.WhitelistPolicy{
+		Name:        "office-" + model.NewId()[:8],
+		Description: "head office",
+		IPRanges:    []string{"10.1.2.3/8", "192.168.0.1", "10.0.0.0/8"},
+		CreatorId:   model.NewId(),
+	}
+
+	saved, err := ss.WhitelistPolicy().Save(policy)
+	require.NoError(t, err)
+	require.NotEmpty(t, saved.Id)
+	assert.Equal(t, model.StringArray{"10.0.0.0/8", "192.168.0.1"}, saved.IPRanges)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		_, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: policy.Name})
+		var uniqueErr *store.ErrUniqueConstraint
+		require.ErrorAs(t, err, &uniqueErr)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "bad-" + model.NewId()[:8], IPRanges: []string{"not-an-ip"}})
+		var appErr *model.AppError
+		require.ErrorAs(t, err, &appErr)
+	})
+
+	got, err := ss.WhitelistPolicy().Get(saved.Id)
+	require.NoError(t, err)
+	assert.Equal(t, saved, got)
+
+	got, err = ss.WhitelistPolicy().GetByName(saved.Name)
+	require.NoError(t, err)
+	assert.Equal(t, saved, got)
+
+	_, err = ss.WhitelistPolicy().Get(model.NewId())
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	policies, err := ss.WhitelistPolicy().GetAll(0, 1000)
+	require.NoError(t, err)
+	assert.Contains(t, policies, saved)
+}
+
+func testWhitelistPolicyUpdate(t *testing.T, rctx request.CTX, ss store.Store) {
+	policy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{
+		Name:     "vpn-" + model.NewId()[:8],
+		IPRanges: []string{"172.16.0.0/12"},
+	})
+	require.NoError(t, err)
+
+	policy.IPRanges = []string{"2001:db8::/32"}
+	policy.Description = "contractor vpn"
+	updated, err := ss.WhitelistPolicy().Update(policy)
+	require.NoError(t, err)
+
+	got, err := ss.WhitelistPolicy().Get(policy.Id)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+	assert.Equal(t, model.StringArray{"2001:db8::/32"}, got.IPRanges)
+
+	_, err = ss.WhitelistPolicy().Update(&model.WhitelistPolicy{Id: model.NewId(), Name: "missing", CreateAt: 1})
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testWhitelistPolicyDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	policy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "temp-" + model.NewId()[:8]})
+	require.NoError(t, err)
+
+	teamId := model.NewId()
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: teamId}))
+
+	require.NoError(t, ss.WhitelistPolicy().Delete(policy.Id))
+
+	_, err = ss.WhitelistPolicy().Get(policy.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	targets, err := ss.WhitelistPolicy().GetTargets(policy.Id)
+	require.NoError(t, err)
+	assert.Empty(t, targets)
+
+	err = ss.WhitelistPolicy().Delete(policy.Id)
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testWhitelistPolicyTargets(t *testing.T, rctx request.CTX, ss store.Store) {
+	policy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "targets-" + model.NewId()[:8]})
+	require.NoError(t, err)
+
+	teamTarget := &model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: model.NewId()}
+	roleTarget := &model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeRole, TargetId: model.SystemUserRoleId}
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(teamTarget))
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(roleTarget))
+
+	t.Run("duplicate target", func(t *testing.T) {
+		err := ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: teamTarget.TargetId})
+		var conflictErr *store.ErrConflict
+		require.ErrorAs(t, err, &conflictErr)
+	})
+
+	targets, err := ss.WhitelistPolicy().GetTargets(policy.Id)
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	assert.Equal(t, roleTarget, targets[0])
+	assert.Equal(t, teamTarget, targets[1])
+
+	require.NoError(t, ss.WhitelistPolicy().RemoveTarget(roleTarget))
+
+	targets, err = ss.WhitelistPolicy().GetTargets(policy.Id)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, teamTarget, targets[0])
+}
+
+func testWhitelistPolicyGetForTargets(t *testing.T, rctx request.CTX, ss store.Store) {
+	teamId := model.NewId()
+	groupId := model.NewId()
+	roleName := "custom_role_" + model.NewId()[:8]
+
+	teamPolicy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "team-" + model.NewId()[:8], IPRanges: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+	groupPolicy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "group-" + model.NewId()[:8], IPRanges: []string{"172.16.0.0/12"}})
+	require.NoError(t, err)
+	rolePolicy, err := ss.WhitelistPolicy().Save(&model.WhitelistPolicy{Name: "role-" + model.NewId()[:8], IPRanges: []string{"192.168.0.0/16"}})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: teamPolicy.Id, TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: teamId}))
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: groupPolicy.Id, TargetType: model.WhitelistPolicyTargetTypeGroup, TargetId: groupId}))
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: rolePolicy.Id, TargetType: model.WhitelistPolicyTargetTypeRole, TargetId: roleName}))
+	// A policy attached twice is only returned once
+	require.NoError(t, ss.WhitelistPolicy().AddTarget(&model.WhitelistPolicyTarget{PolicyId: teamPolicy.Id, TargetType: model.WhitelistPolicyTargetTypeRole, TargetId: roleName}))
+
+	t.Run("no targets", func(t *testing.T) {
+		policies, err := ss.WhitelistPolicy().GetForTargets(nil, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, policies)
+	})
+
+	t.Run("team only", func(t *testing.T) {
+		policies, err := ss.WhitelistPolicy().GetForTargets([]string{teamId}, nil, nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*model.WhitelistPolicy{teamPolicy}, policies)
+	})
+
+	t.Run("ids do not match across target types", func(t *testing.T) {
+		policies, err := ss.WhitelistPolicy().GetForTargets([]string{groupId}, nil, []string{teamId})
+		require.NoError(t, err)
+		assert.Empty(t, policies)
+	})
+
+	t.Run("all targets", func(t *testing.T) {
+		policies, err := ss.WhitelistPolicy().GetForTargets([]string{teamId}, []string{groupId}, []string{roleName})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*model.WhitelistPolicy{teamPolicy, groupPolicy, rolePolicy}, policies)
+	})
+}
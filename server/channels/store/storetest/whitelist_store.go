@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestWhitelistStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
+	t.Run("AddAndGet", func(t *testing.T) { testWhitelistAddAndGet(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testWhitelistDelete(t, rctx, ss) })
+	t.Run("GetPrefixSet", func(t *testing.T) { testWhitelistGetPrefixSet(t, rctx, ss) })
+	t.Run("GetAllAfter", func(t *testing.T) { testWhitelistGetAllAfter(t, rctx, ss) })
+	t.Run("DeleteExpired", func(t *testing.T) { testWhitelistDeleteExpired(t, rctx, ss) })
+	t.Run("Denials", func(t *testing.T) { testWhitelistDenials(t, rctx, ss) })
+}
+
+func testWhitelistAddAndGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+	creatorId := model.NewId()
+
+	single := &model.WhitelistItem{UserId: userId, IP: "10.0.0.1", CreatorId: creatorId, CreateAt: 1000}
+	require.NoError(t, ss.Whitelist().Add(single))
+
+	rangeItem := &model.WhitelistItem{
+		UserId:      userId,
+		IP:          "2001:db8:abcd:ffff:ffff:ffff:ffff:0/112",
+		Description: "contractor vpn",
+		CreatorId:   creatorId,
+		CreateAt:    2000,
+		ExpiresAt:   5000,
+	}
+	require.NoError(t, ss.Whitelist().Add(rangeItem))
+
+	t.Run("duplicate entry", func(t *testing.T) {
+		err := ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "10.0.0.1", CreateAt: 3000})
+		require.Error(t, err)
+	})
+
+	t.Run("missing user id", func(t *testing.T) {
+		err := ss.Whitelist().Add(&model.WhitelistItem{IP: "10.0.0.1"})
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	items, err := ss.Whitelist().GetByUserId(userId)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, single, items[0])
+	assert.Equal(t, rangeItem, items[1])
+
+	items, err = ss.Whitelist().GetByUserId(model.NewId())
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func testWhitelistDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "172.16.0.0/12", CreateAt: 1000}))
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "172.16.5.5", CreateAt: 2000}))
+
+	require.NoError(t, ss.Whitelist().Delete(&model.WhitelistItem{UserId: userId, IP: "172.16.0.0/12"}))
+
+	items, err := ss.Whitelist().GetByUserId(userId)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "172.16.5.5", items[0].IP)
+
+	// Deleting an entry that does not exist is not an error
+	require.NoError(t, ss.Whitelist().Delete(&model.WhitelistItem{UserId: userId, IP: "172.16.0.0/12"}))
+}
+
+func testWhitelistGetPrefixSet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+
+	set, err := ss.Whitelist().GetPrefixSetByUserId(userId)
+	require.NoError(t, err)
+	assert.Empty(t, set.Entries)
+
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "198.51.100.0/24", CreateAt: 1000}))
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "2001:db8::1", CreateAt: 2000, ExpiresAt: 3000}))
+
+	// Adding entries must not leave a stale set behind in cache layers
+	set, err = ss.Whitelist().GetPrefixSetByUserId(userId)
+	require.NoError(t, err)
+	require.Len(t, set.Entries, 2)
+	assert.Equal(t, "198.51.100.0/24", set.Entries[0].Prefix.String())
+	assert.Len(t, set.Active(3000), 1)
+
+	require.NoError(t, ss.Whitelist().Delete(&model.WhitelistItem{UserId: userId, IP: "198.51.100.0/24"}))
+
+	set, err = ss.Whitelist().GetPrefixSetByUserId(userId)
+	require.NoError(t, err)
+	require.Len(t, set.Entries, 1)
+	assert.Equal(t, "2001:db8::1/128", set.Entries[0].Prefix.String())
+}
+
+func testWhitelistGetAllAfter(t *testing.T, rctx request.CTX, ss store.Store) {
+	u1, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u1.Id)) }()
+	u2, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u2.Id)) }()
+
+	for _, item := range []*model.WhitelistItem{
+		{UserId: u1.Id, IP: "203.0.113.1", CreateAt: 1000},
+		{UserId: u1.Id, IP: "203.0.113.2", CreateAt: 1000},
+		{UserId: u2.Id, IP: "203.0.113.3", CreateAt: 1000, ExpiresAt: 5000},
+	} {
+		require.NoError(t, ss.Whitelist().Add(item))
+		defer func(item *model.WhitelistItem) { require.NoError(t, ss.Whitelist().Delete(item)) }(item)
+	}
+
+	// Other tests leave entries behind, so only the entries of these users are compared
+	var found []*model.WhitelistItemForExport
+	afterUserId, afterIP := "", ""
+	for {
+		items, err := ss.Whitelist().GetAllAfter(2, afterUserId, afterIP)
+		require.NoError(t, err)
+		for _, item := range items {
+			if item.UserId == u1.Id || item.UserId == u2.Id {
+				found = append(found, item)
+			}
+			afterUserId, afterIP = item.UserId, item.IP
+		}
+		if len(items) < 2 {
+			break
+		}
+	}
+
+	require.Len(t, found, 3)
+	byIP := map[string]*model.WhitelistItemForExport{}
+	for _, item := range found {
+		byIP[item.IP] = item
+	}
+	assert.Equal(t, u1.Username, byIP["203.0.113.1"].Username)
+	assert.Equal(t, u1.Username, byIP["203.0.113.2"].Username)
+	assert.Equal(t, u2.Username, byIP["203.0.113.3"].Username)
+	assert.Equal(t, int64(5000), byIP["203.0.113.3"].ExpiresAt)
+}
+
+func testWhitelistDeleteExpired(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "192.0.2.1", CreateAt: 1000}))
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "192.0.2.2", CreateAt: 1000, ExpiresAt: 2000}))
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "192.0.2.3", CreateAt: 1000, ExpiresAt: 3000}))
+	require.NoError(t, ss.Whitelist().Add(&model.WhitelistItem{UserId: userId, IP: "192.0.2.4", CreateAt: 1000, ExpiresAt: 4000}))
+
+	deleted, err := ss.Whitelist().DeleteExpired(3000)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(2))
+
+	items, err := ss.Whitelist().GetByUserId(userId)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "192.0.2.1", items[0].IP)
+	assert.Equal(t, "192.0.2.4", items[1].IP)
+}
+
+func testWhitelistDenials(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+	otherUserId := model.NewId()
+
+	first, err := ss.Whitelist().SaveDenial(&model.WhitelistDenial{UserId: userId, IP: "::ffff:10.0.0.1", Path: "/api/v4/users/me", Rule: model.WhitelistRuleNotWhitelisted, CreateAt: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", first.IP)
+	second, err := ss.Whitelist().SaveDenial(&model.WhitelistDenial{UserId: userId, IP: "10.0.0.2", Path: "/api/v4/websocket", Rule: model.WhitelistRuleEmptyDenied, CreateAt: 2000})
+	require.NoError(t, err)
+	third, err := ss.Whitelist().SaveDenial(&model.WhitelistDenial{UserId: otherUserId, IP: "10.0.0.1", Path: "/api/v4/posts", Rule: model.WhitelistRuleBotDenied, CreateAt: 3000})
+	require.NoError(t, err)
+
+	t.Run("invalid denial", func(t *testing.T) {
+		_, err := ss.Whitelist().SaveDenial(&model.WhitelistDenial{UserId: "abc", Rule: model.WhitelistRuleNotWhitelisted})
+		var appErr *model.AppError
+		require.ErrorAs(t, err, &appErr)
+	})
+
+	t.Run("by user", func(t *testing.T) {
+		denials, err := ss.Whitelist().GetDenials(&model.WhitelistDenialFilter{UserId: userId, PerPage: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.WhitelistDenial{second, first}, denials)
+	})
+
+	t.Run("by ip and time range", func(t *testing.T) {
+		denials, err := ss.Whitelist().GetDenials(&model.WhitelistDenialFilter{IP: "10.0.0.1", Since: 1500, Until: 3000, PerPage: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.WhitelistDenial{third}, denials)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		denials, err := ss.Whitelist().GetDenials(&model.WhitelistDenialFilter{UserId: userId, Page: 1, PerPage: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.WhitelistDenial{first}, denials)
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		require.NoError(t, ss.Whitelist().IncrementDenial(third.Id, 3500))
+		require.NoError(t, ss.Whitelist().IncrementDenial(third.Id, 3200))
+
+		denials, err := ss.Whitelist().GetDenials(&model.WhitelistDenialFilter{UserId: otherUserId, PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, denials, 1)
+		assert.Equal(t, 3, denials[0].Occurrences)
+		assert.Equal(t, int64(3500), denials[0].LastAt)
+		assert.Equal(t, int64(3000), denials[0].CreateAt)
+
+		err = ss.Whitelist().IncrementDenial(model.NewId(), 3500)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+
+	t.Run("delete before", func(t *testing.T) {
+		deleted, err := ss.Whitelist().DeleteDenialsBefore(2500, 1000)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(2))
+
+		denials, err := ss.Whitelist().GetDenials(&model.WhitelistDenialFilter{UserId: userId, PerPage: 10})
+		require.NoError(t, err)
+		assert.Empty(t, denials)
+	})
+}
@@ -39,6 +39,7 @@ type TimerLayer struct {
 	JobStore                        store.JobStore
 	LicenseStore                    store.LicenseStore
 	LinkMetadataStore               store.LinkMetadataStore
+	LoginBanStore                   store.LoginBanStore
 	NotifyAdminStore                store.NotifyAdminStore
 	OAuthStore                      store.OAuthStore
 	OutgoingOAuthConnectionStore    store.OutgoingOAuthConnectionStore
@@ -56,6 +57,7 @@ type TimerLayer struct {
 	RemoteClusterStore              store.RemoteClusterStore
 	RetentionPolicyStore            store.RetentionPolicyStore
 	RoleStore                       store.RoleStore
+	SavedSearchStore                store.SavedSearchStore
 	ScheduledPostStore              store.ScheduledPostStore
 	SchemeStore                     store.SchemeStore
 	SessionStore                    store.SessionStore
@@ -72,6 +74,7 @@ type TimerLayer struct {
 	UserTermsOfServiceStore         store.UserTermsOfServiceStore
 	WebhookStore                    store.WebhookStore
 	WhitelistStore                  store.WhitelistStore
+	WhitelistPolicyStore            store.WhitelistPolicyStore
 }
 
 func (s *TimerLayer) AccessControlPolicy() store.AccessControlPolicyStore {
@@ -154,6 +157,10 @@ func (s *TimerLayer) LinkMetadata() store.LinkMetadataStore {
 	return s.LinkMetadataStore
 }
 
+func (s *TimerLayer) LoginBan() store.LoginBanStore {
+	return s.LoginBanStore
+}
+
 func (s *TimerLayer) NotifyAdmin() store.NotifyAdminStore {
 	return s.NotifyAdminStore
 }
@@ -222,6 +229,10 @@ func (s *TimerLayer) Role() store.RoleStore {
 	return s.RoleStore
 }
 
+func (s *TimerLayer) SavedSearch() store.SavedSearchStore {
+	return s.SavedSearchStore
+}
+
 func (s *TimerLayer) ScheduledPost() store.ScheduledPostStore {
 	return s.ScheduledPostStore
 }
@@ -286,6 +297,10 @@ func (s *TimerLayer) Whitelist() store.WhitelistStore {
 	return s.WhitelistStore
 }
 
+func (s *TimerLayer) WhitelistPolicy() store.WhitelistPolicyStore {
+	return s.WhitelistPolicyStore
+}
+
 type TimerLayerAccessControlPolicyStore struct {
 	store.AccessControlPolicyStore
 	Root *TimerLayer
@@ -386,6 +401,11 @@ type TimerLayerLinkMetadataStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerLoginBanStore struct {
+	store.LoginBanStore
+	Root *TimerLayer
+}
+
 type TimerLayerNotifyAdminStore struct {
 	store.NotifyAdminStore
 	Root *TimerLayer
@@ -471,6 +491,11 @@ type TimerLayerRoleStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerSavedSearchStore struct {
+	store.SavedSearchStore
+	Root *TimerLayer
+}
+
 type TimerLayerScheduledPostStore struct {
 	store.ScheduledPostStore
 	Root *TimerLayer
@@ -551,6 +576,11 @@ type TimerLayerWhitelistStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerWhitelistPolicyStore struct {
+	store.WhitelistPolicyStore
+	Root *TimerLayer
+}
+
 func (s *TimerLayerAccessControlPolicyStore) Delete(c request.CTX, id string) error {
 	start := time.Now()
 
@@ -3916,6 +3946,22 @@ func (s *TimerLayerFileInfoStore) GetFilesBatchForIndexing(startTime int64, star
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) GetFilteredFilesBatchForIndexing(startTime int64, startFileID string, filter store.IndexingBatchFilter, limit int) ([]*model.FileForIndexing, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.GetFilteredFilesBatchForIndexing(startTime, startFileID, filter, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetFilteredFilesBatchForIndexing", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) GetForPost(postID string, readFromMaster bool, includeDeleted bool, allowFromCache bool) ([]*model.FileInfo, error) {
 	start := time.Now()
 
@@ -4171,6 +4217,22 @@ func (s *TimerLayerFileInfoStore) SetContent(ctx request.CTX, fileID string, con
 	return err
 }
 
+func (s *TimerLayerFileInfoStore) SetContentExtractionFailure(ctx request.CTX, fileID string, extractionErr string) error {
+	start := time.Now()
+
+	err := s.FileInfoStore.SetContentExtractionFailure(ctx, fileID, extractionErr)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.SetContentExtractionFailure", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	start := time.Now()
 
@@ -5451,6 +5513,86 @@ func (s *TimerLayerLinkMetadataStore) Save(linkMetadata *model.LinkMetadata) (*m
 	return result, err
 }
 
+func (s *TimerLayerLoginBanStore) Delete(ip string) error {
+	start := time.Now()
+
+	err := s.LoginBanStore.Delete(ip)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginBanStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerLoginBanStore) DeleteAll() error {
+	start := time.Now()
+
+	err := s.LoginBanStore.DeleteAll()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginBanStore.DeleteAll", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerLoginBanStore) DeleteExpired(now int64) (int64, error) {
+	start := time.Now()
+
+	result, err := s.LoginBanStore.DeleteExpired(now)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginBanStore.DeleteExpired", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerLoginBanStore) GetActive(now int64) ([]*model.LoginBan, error) {
+	start := time.Now()
+
+	result, err := s.LoginBanStore.GetActive(now)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginBanStore.GetActive", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerLoginBanStore) Save(ban *model.LoginBan) (*model.LoginBan, error) {
+	start := time.Now()
+
+	result, err := s.LoginBanStore.Save(ban)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("LoginBanStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerNotifyAdminStore) DeleteBefore(trial bool, now int64) error {
 	start := time.Now()
 
@@ -6202,6 +6344,22 @@ func (s *TimerLayerPostStore) Get(ctx context.Context, id string, opts model.Get
 	return result, err
 }
 
+func (s *TimerLayerPostStore) GetAllPosts(options *model.GetAllPostsOptions) (*model.PostList, int, error) {
+	start := time.Now()
+
+	result, resultVar1, err := s.PostStore.GetAllPosts(options)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetAllPosts", success, elapsed)
+	}
+	return result, resultVar1, err
+}
+
 func (s *TimerLayerPostStore) GetDirectPostParentsForExportAfter(limit int, afterID string, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
 	start := time.Now()
 
@@ -6250,6 +6408,22 @@ func (s *TimerLayerPostStore) GetEtag(channelID string, allowFromCache bool, col
 	return result
 }
 
+func (s *TimerLayerPostStore) GetFilteredPostsBatchForIndexing(startTime int64, startPostID string, filter store.IndexingBatchFilter, limit int) ([]*model.PostForIndexing, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.GetFilteredPostsBatchForIndexing(startTime, startPostID, filter, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetFilteredPostsBatchForIndexing", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) GetFlaggedPosts(userID string, offset int, limit int) (*model.PostList, error) {
 	start := time.Now()
 
@@ -6793,6 +6967,22 @@ func (s *TimerLayerPostStore) RefreshPostStats() error {
 	return err
 }
 
+func (s *TimerLayerPostStore) RemovePostsBetween(options *model.RemovePostsBetweenOptions) ([]*model.Post, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.RemovePostsBetween(options)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.RemovePostsBetween", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) Save(rctx request.CTX, post *model.Post) (*model.Post, error) {
 	start := time.Now()
 
@@ -7801,10 +7991,10 @@ func (s *TimerLayerReactionStore) Delete(reaction *model.Reaction) (*model.React
 	return result, err
 }
 
-func (s *TimerLayerReactionStore) DeleteAllWithEmojiName(emojiName string) error {
+func (s *TimerLayerReactionStore) DeleteAllWithEmojiName(emojiName string) ([]string, error) {
 	start := time.Now()
 
-	err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
+	result, err := s.ReactionStore.DeleteAllWithEmojiName(emojiName)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7814,7 +8004,7 @@ func (s *TimerLayerReactionStore) DeleteAllWithEmojiName(emojiName string) error
 		}
 		s.Root.Metrics.ObserveStoreMethodDuration("ReactionStore.DeleteAllWithEmojiName", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
 func (s *TimerLayerReactionStore) DeleteOrphanedRowsByIds(r *model.RetentionIdsForDeletion) (int64, error) {
@@ -7929,10 +8119,10 @@ func (s *TimerLayerReactionStore) PermanentDeleteBatch(endTime int64, limit int6
 	return result, err
 }
 
-func (s *TimerLayerReactionStore) PermanentDeleteByUser(userID string) error {
+func (s *TimerLayerReactionStore) PermanentDeleteByUser(userID string) ([]string, error) {
 	start := time.Now()
 
-	err := s.ReactionStore.PermanentDeleteByUser(userID)
+	result, err := s.ReactionStore.PermanentDeleteByUser(userID)
 
 	elapsed := float64(time.Since(start)) / float64(time.Second)
 	if s.Root.Metrics != nil {
@@ -7942,7 +8132,7 @@ func (s *TimerLayerReactionStore) PermanentDeleteByUser(userID string) error {
 		}
 		s.Root.Metrics.ObserveStoreMethodDuration("ReactionStore.PermanentDeleteByUser", success, elapsed)
 	}
-	return err
+	return result, err
 }
 
 func (s *TimerLayerReactionStore) Save(reaction *model.Reaction) (*model.Reaction, error) {
@@ -8585,6 +8775,118 @@ func (s *TimerLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
 	return result, err
 }
 
+func (s *TimerLayerSavedSearchStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerSavedSearchStore) Get(id string) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetAlertsForTeam(teamID string) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetAlertsForTeam(teamID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetAlertsForTeam", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetForUser(userID string) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetForUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) PermanentDeleteByUser(userID string) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.PermanentDeleteByUser(userID)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.PermanentDeleteByUser", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Save(savedSearch)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Update(savedSearch)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Update", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerScheduledPostStore) CreateScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
 	start := time.Now()
 
@@ -8649,6 +8951,22 @@ func (s *TimerLayerScheduledPostStore) GetPendingScheduledPosts(beforeTime int64
 	return result, err
 }
 
+func (s *TimerLayerScheduledPostStore) GetScheduledPostsForChannel(channelId string) ([]*model.ScheduledPost, error) {
+	start := time.Now()
+
+	result, err := s.ScheduledPostStore.GetScheduledPostsForChannel(channelId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ScheduledPostStore.GetScheduledPostsForChannel", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerScheduledPostStore) GetScheduledPostsForUser(userId string, teamId string) ([]*model.ScheduledPost, error) {
 	start := time.Now()
 
@@ -13312,6 +13630,21 @@ func (s *TimerLayerWhitelistStore) Add(whitelistItem *model.WhitelistItem) error
 	return err
 }
 
+func (s *TimerLayerWhitelistStore) ClearCaches() {
+	start := time.Now()
+
+	s.WhitelistStore.ClearCaches()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if true {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.ClearCaches", success, elapsed)
+	}
+}
+
 func (s *TimerLayerWhitelistStore) Delete(whitelistItem *model.WhitelistItem) error {
 	start := time.Now()
 
@@ -13328,7 +13661,55 @@ func (s *TimerLayerWhitelistStore) Delete(whitelistItem *model.WhitelistItem) er
 	return err
 }
 
-func (s *TimerLayerWhitelistStore) GetByUserId(userId string) ([]string, error) {
+func (s *TimerLayerWhitelistStore) DeleteDenialsBefore(endTime int64, limit int64) (int64, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.DeleteDenialsBefore(endTime, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.DeleteDenialsBefore", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistStore) DeleteExpired(now int64) (int64, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.DeleteExpired(now)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.DeleteExpired", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistStore) GetAllAfter(limit int, afterUserId string, afterIP string) ([]*model.WhitelistItemForExport, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.GetAllAfter(limit, afterUserId, afterIP)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.GetAllAfter", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistStore) GetByUserId(userId string) ([]*model.WhitelistItem, error) {
 	start := time.Now()
 
 	result, err := s.WhitelistStore.GetByUserId(userId)
@@ -13344,6 +13725,245 @@ func (s *TimerLayerWhitelistStore) GetByUserId(userId string) ([]string, error)
 	return result, err
 }
 
+func (s *TimerLayerWhitelistStore) GetDenials(filter *model.WhitelistDenialFilter) ([]*model.WhitelistDenial, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.GetDenials(filter)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.GetDenials", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistStore) GetPrefixSetByUserId(userId string) (*model.WhitelistPrefixSet, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.GetPrefixSetByUserId(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.GetPrefixSetByUserId", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistStore) IncrementDenial(id string, lastAt int64) error {
+	start := time.Now()
+
+	err := s.WhitelistStore.IncrementDenial(id, lastAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.IncrementDenial", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWhitelistStore) InvalidateWhitelistCacheForUser(userId string) {
+	start := time.Now()
+
+	s.WhitelistStore.InvalidateWhitelistCacheForUser(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if true {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.InvalidateWhitelistCacheForUser", success, elapsed)
+	}
+}
+
+func (s *TimerLayerWhitelistStore) SaveDenial(denial *model.WhitelistDenial) (*model.WhitelistDenial, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistStore.SaveDenial(denial)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistStore.SaveDenial", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) AddTarget(target *model.WhitelistPolicyTarget) error {
+	start := time.Now()
+
+	err := s.WhitelistPolicyStore.AddTarget(target)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.AddTarget", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.WhitelistPolicyStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) Get(id string) (*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) GetAll(offset int, limit int) ([]*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.GetAll(offset, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.GetAll", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) GetByName(name string) (*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.GetByName(name)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.GetByName", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) GetForTargets(teamIds []string, groupIds []string, roleNames []string) ([]*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.GetForTargets(teamIds, groupIds, roleNames)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.GetForTargets", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) GetTargets(policyId string) ([]*model.WhitelistPolicyTarget, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.GetTargets(policyId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.GetTargets", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) RemoveTarget(target *model.WhitelistPolicyTarget) error {
+	start := time.Now()
+
+	err := s.WhitelistPolicyStore.RemoveTarget(target)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.RemoveTarget", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) Save(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.Save(policy)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerWhitelistPolicyStore) Update(policy *model.WhitelistPolicy) (*model.WhitelistPolicy, error) {
+	start := time.Now()
+
+	result, err := s.WhitelistPolicyStore.Update(policy)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("WhitelistPolicyStore.Update", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayer) Close() {
 	s.Store.Close()
 }
@@ -13406,6 +14026,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.JobStore = &TimerLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
 	newStore.LicenseStore = &TimerLayerLicenseStore{LicenseStore: childStore.License(), Root: &newStore}
 	newStore.LinkMetadataStore = &TimerLayerLinkMetadataStore{LinkMetadataStore: childStore.LinkMetadata(), Root: &newStore}
+	newStore.LoginBanStore = &TimerLayerLoginBanStore{LoginBanStore: childStore.LoginBan(), Root: &newStore}
 	newStore.NotifyAdminStore = &TimerLayerNotifyAdminStore{NotifyAdminStore: childStore.NotifyAdmin(), Root: &newStore}
 	newStore.OAuthStore = &TimerLayerOAuthStore{OAuthStore: childStore.OAuth(), Root: &newStore}
 	newStore.OutgoingOAuthConnectionStore = &TimerLayerOutgoingOAuthConnectionStore{OutgoingOAuthConnectionStore: childStore.OutgoingOAuthConnection(), Root: &newStore}
@@ -13423,6 +14044,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.RemoteClusterStore = &TimerLayerRemoteClusterStore{RemoteClusterStore: childStore.RemoteCluster(), Root: &newStore}
 	newStore.RetentionPolicyStore = &TimerLayerRetentionPolicyStore{RetentionPolicyStore: childStore.RetentionPolicy(), Root: &newStore}
 	newStore.RoleStore = &TimerLayerRoleStore{RoleStore: childStore.Role(), Root: &newStore}
+	newStore.SavedSearchStore = &TimerLayerSavedSearchStore{SavedSearchStore: childStore.SavedSearch(), Root: &newStore}
 	newStore.ScheduledPostStore = &TimerLayerScheduledPostStore{ScheduledPostStore: childStore.ScheduledPost(), Root: &newStore}
 	newStore.SchemeStore = &TimerLayerSchemeStore{SchemeStore: childStore.Scheme(), Root: &newStore}
 	newStore.SessionStore = &TimerLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
@@ -13439,5 +14061,6 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.UserTermsOfServiceStore = &TimerLayerUserTermsOfServiceStore{UserTermsOfServiceStore: childStore.UserTermsOfService(), Root: &newStore}
 	newStore.WebhookStore = &TimerLayerWebhookStore{WebhookStore: childStore.Webhook(), Root: &newStore}
 	newStore.WhitelistStore = &TimerLayerWhitelistStore{WhitelistStore: childStore.Whitelist(), Root: &newStore}
+	newStore.WhitelistPolicyStore = &TimerLayerWhitelistPolicyStore{WhitelistPolicyStore: childStore.WhitelistPolicy(), Root: &newStore}
 	return &newStore
 }
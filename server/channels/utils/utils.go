@@ -8,6 +8,7 @@ import (
 	"math"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"slices"
 	"strings"
@@ -113,6 +114,101 @@ func GetIPAddress(r *http.Request, trustedProxyIPHeader []string) string {
 	return host
 }
 
+// ParseTrustedProxies parses a list of addresses and CIDR ranges, skipping
+// entries that are invalid.
+func ParseTrustedProxies(proxies []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if prefix, err := model.ParseWhitelistPrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// GetClientIPAddress returns the address of the client that originated the
+// request. The configured headers are only honored when the direct peer is a
+// trusted proxy, and each header is walked from the right, skipping trusted
+// proxies, so that hops prepended by the client cannot be used to spoof an
+// address. With no trusted proxies configured it behaves like GetIPAddress.
+func GetClientIPAddress(r *http.Request, trustedProxyIPHeader []string, trustedProxies []netip.Prefix) string {
+	if len(trustedProxies) == 0 {
+		return GetIPAddress(r, trustedProxyIPHeader)
+	}
+
+	remote, ok := parseHop(r.RemoteAddr)
+	if !ok {
+		return ""
+	}
+
+	if !prefixesContain(trustedProxies, remote) {
+		return remote.String()
+	}
+
+	for _, proxyHeader := range trustedProxyIPHeader {
+		if address, ok := rightmostUntrustedHop(r.Header.Values(proxyHeader), trustedProxies); ok {
+			return address.String()
+		}
+	}
+
+	return remote.String()
+}
+
+// rightmostUntrustedHop walks the comma separated hops of a forwarding header
+// from right to left and returns the first one that is not a trusted proxy.
+// When every hop is trusted the leftmost one is returned. A malformed hop ends
+// the walk, since nothing to its left can be trusted.
+func rightmostUntrustedHop(values []string, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	var hops []string
+	for _, value := range values {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	var leftmost netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+
+		address, ok := parseHop(hop)
+		if !ok {
+			return netip.Addr{}, false
+		}
+
+		if !prefixesContain(trustedProxies, address) {
+			return address, true
+		}
+		leftmost = address
+	}
+
+	return leftmost, leftmost.IsValid()
+}
+
+// parseHop parses an address that may carry a port, unmapping IPv4-mapped
+// IPv6 addresses and dropping any zone.
+func parseHop(hop string) (netip.Addr, bool) {
+	address, err := netip.ParseAddr(hop)
+	if err != nil {
+		addrPort, portErr := netip.ParseAddrPort(hop)
+		if portErr != nil {
+			return netip.Addr{}, false
+		}
+		address = addrPort.Addr()
+	}
+
+	return address.Unmap().WithZone(""), true
+}
+
+func prefixesContain(prefixes []netip.Prefix, address netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(address) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetHostnameFromSiteURL(siteURL string) string {
 	u, err := url.Parse(siteURL)
 	if err != nil {
@@ -197,6 +197,147 @@ func TestGetIPAddress(t *testing.T) {
 	})
 }
 
+func TestGetClientIPAddress(t *testing.T) {
+	trustedProxies := ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::/32", "not-a-cidr"})
+	headers := []string{"X-Forwarded-For", "X-Real-Ip"}
+
+	t.Run("Invalid trusted proxies are ignored", func(t *testing.T) {
+		assert.Len(t, trustedProxies, 2)
+	})
+
+	testCases := []struct {
+		Name       string
+		Header     http.Header
+		RemoteAddr string
+		Headers    []string
+		Expected   string
+	}{
+		{
+			Name:       "Direct connection without headers",
+			RemoteAddr: "203.0.113.5:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Spoofed X-Forwarded-For from an untrusted peer",
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7"}},
+			RemoteAddr: "203.0.113.5:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Spoofed X-Real-Ip from an untrusted peer",
+			Header:     http.Header{"X-Real-Ip": []string{"198.51.100.7"}},
+			RemoteAddr: "203.0.113.5:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Single hop added by a trusted proxy",
+			Header:     http.Header{"X-Forwarded-For": []string{"203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Client prepends a spoofed hop behind a trusted proxy",
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7, 203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Chain of trusted proxies is skipped",
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7, 203.0.113.5, 10.2.2.2, 10.3.3.3"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Multiple X-Forwarded-For header lines are joined",
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7", "203.0.113.5, 10.2.2.2"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Spoofed trusted-looking hop does not hide the real client",
+			Header:     http.Header{"X-Forwarded-For": []string{"10.9.9.9, 203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Every hop trusted returns the leftmost",
+			Header:     http.Header{"X-Forwarded-For": []string{"10.4.4.4, 10.2.2.2"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "10.4.4.4",
+		},
+		{
+			Name:       "Malformed hop stops the walk",
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7, garbage, 10.2.2.2"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    []string{"X-Forwarded-For"},
+			Expected:   "10.1.1.1",
+		},
+		{
+			Name:       "Malformed X-Forwarded-For falls back to X-Real-Ip",
+			Header:     http.Header{"X-Forwarded-For": []string{"garbage"}, "X-Real-Ip": []string{"203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "Headers that are not configured are ignored",
+			Header:     http.Header{"X-Real-Ip": []string{"203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    []string{"X-Forwarded-For"},
+			Expected:   "10.1.1.1",
+		},
+		{
+			Name:       "Hop with a port",
+			Header:     http.Header{"X-Forwarded-For": []string{"203.0.113.5:5555"}},
+			RemoteAddr: "10.1.1.1:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "IPv6 trusted proxy and IPv4-mapped client",
+			Header:     http.Header{"X-Forwarded-For": []string{"::ffff:203.0.113.5"}},
+			RemoteAddr: "[2001:db8::1]:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+		{
+			Name:       "IPv4-mapped peer inside a trusted range",
+			Header:     http.Header{"X-Forwarded-For": []string{"203.0.113.5"}},
+			RemoteAddr: "[::ffff:10.1.1.1]:4321",
+			Headers:    headers,
+			Expected:   "203.0.113.5",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := &http.Request{Header: tc.Header, RemoteAddr: tc.RemoteAddr}
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+			assert.Equal(t, tc.Expected, GetClientIPAddress(r, tc.Headers, trustedProxies))
+		})
+	}
+
+	t.Run("Without trusted proxies the configured headers are honored as before", func(t *testing.T) {
+		r := &http.Request{
+			Header:     http.Header{"X-Forwarded-For": []string{"198.51.100.7, 203.0.113.5"}},
+			RemoteAddr: "10.1.1.1:4321",
+		}
+		assert.Equal(t, GetIPAddress(r, headers), GetClientIPAddress(r, headers, nil))
+		assert.Equal(t, "198.51.100.7", GetClientIPAddress(r, headers, nil))
+	})
+}
+
 func TestRemoveStringFromSlice(t *testing.T) {
 	a := []string{"one", "two", "three", "four", "five", "six"}
 	expected := []string{"one", "two", "three", "five", "six"}
@@ -412,6 +412,28 @@ func (c *Context) RequirePolicyId() *Context {
 	return c
 }
 
+func (c *Context) RequireSavedSearchId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.SavedSearchId) {
+		c.SetInvalidURLParam("saved_search_id")
+	}
+	return c
+}
+
+func (c *Context) RequirePolicyName() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if c.Params.PolicyName == "" || len(c.Params.PolicyName) > model.WhitelistPolicyNameMaxLength {
+		c.SetInvalidURLParam("policy_name")
+	}
+	return c
+}
+
 func (c *Context) RequireAppId() *Context {
 	if c.Err != nil {
 		return c
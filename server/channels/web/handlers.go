@@ -82,6 +82,38 @@ type Handler struct {
 	cspShaDirective string
 }
 
+// checkIPWhitelist denies the request if the address of the session's user is not
+// whitelisted, recording the denial for the denied-access history.
+func checkIPWhitelist(c *Context, w http.ResponseWriter, r *http.Request) {
+	session := c.AppContext.Session()
+	decision, err := c.App.EvaluateUserIPWhitelist(c.AppContext, session.UserId, c.AppContext.IPAddress())
+	if err != nil {
+		c.Logger.Error("Error checking IP whitelist", mlog.Err(err))
+		c.Err = model.NewAppError("ServeHTTP", "api.context.whitelist.check_error.app_error", nil, "", http.StatusInternalServerError)
+		return
+	}
+	if decision.Allowed {
+		return
+	}
+
+	c.Logger.Warn("Access denied: IP not in whitelist",
+		mlog.String("user_id", session.UserId),
+		mlog.String("ip", c.AppContext.IPAddress()),
+		mlog.String("path", r.URL.Path),
+		mlog.String("rule", decision.Rule))
+	c.App.RecordWhitelistDenial(c.AppContext, &model.WhitelistDenial{
+		UserId:    session.UserId,
+		IP:        c.AppContext.IPAddress(),
+		Path:      r.URL.Path,
+		Rule:      decision.Rule,
+		SessionId: session.Id,
+	})
+
+	// Clear session cookie and return 401 to force redirect to login page, similar to session expiry
+	c.RemoveSessionCookie(w, r)
+	c.Err = model.NewAppError("ServeHTTP", "api.context.ip_whitelist_denied.app_error", nil, "", http.StatusUnauthorized)
+}
+
 func generateDevCSP(c Context) string {
 	var devCSP []string
 
@@ -183,7 +215,7 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	c.AppContext = request.NewContext(
 		context.Background(),
 		requestID,
-		utils.GetIPAddress(r, c.App.Config().ServiceSettings.TrustedProxyIPHeader),
+		c.App.GetClientIPAddress(r),
 		r.Header.Get("X-Forwarded-For"),
 		r.URL.Path,
 		r.UserAgent(),
@@ -230,36 +262,10 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("Referrer-Policy", "no-referrer")
 
-	// Get all possible IP addresses for whitelist checking
-	userIps := append(r.Header["X-Forwarded-For"], r.Header["X-Real-IP"]...)
-	// Also include the direct client IP (important for localhost and non-proxied connections)
-	clientIP := utils.GetIPAddress(r, c.App.Config().ServiceSettings.TrustedProxyIPHeader)
-	if clientIP != "" {
-		userIps = append(userIps, clientIP)
-	}
-
 	if h.IsStatic {
-		fmt.Println("\n\n\nSTATIC", r.URL.Path, c.AppContext.Session() != nil)
 		// we need to check if users are logged in and then whitelisted
-		if c.AppContext.Session() != nil {
-			// check if user is whitelisted
-			isWhitelisted, err := c.App.CheckUserIPWhitelisted(c.AppContext, c.AppContext.Session().UserId, userIps)
-			fmt.Println("\n\n\nIs whitelisted 111: ", isWhitelisted, "Error: ", err, "IP: ", r.Header, r.Header["X-Forwarded-For"], r.Header["X-Real-IP"])
-			if err != nil {
-				c.Logger.Error("Error checking IP whitelist", mlog.Err(err))
-				c.Err = model.NewAppError("ServeHTTP", "api.context.whitelist.check_error.app_error", nil, "", http.StatusInternalServerError)
-				return
-			}
-			if !isWhitelisted {
-				c.Logger.Warn("Access denied: IP not in whitelist",
-					mlog.String("user_id", c.AppContext.Session().UserId),
-					mlog.String("ip", c.AppContext.IPAddress()),
-					mlog.String("path", r.URL.Path))
-				// Clear session cookie and return 401 to force redirect to login page, similar to session expiry
-				c.RemoveSessionCookie(w, r)
-				c.Err = model.NewAppError("ServeHTTP", "api.context.ip_whitelist_denied.app_error",
-					nil, "", http.StatusUnauthorized)
-			}
+		if c.AppContext.Session() != nil && !c.App.IsWhitelistExcludedPath(r.URL.Path) {
+			checkIPWhitelist(c, w, r)
 		}
 		// Instruct the browser not to display us in an iframe unless is the same origin for anti-clickjacking
 		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
@@ -303,7 +309,7 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		// Rate limit by UserID
 		if c.App.Srv().RateLimiter != nil {
-			rateLimitExceeded = c.App.Srv().RateLimiter.UserIdRateLimit(c.AppContext.Session().UserId, w)
+			rateLimitExceeded = c.App.Srv().RateLimiter.UserIdRateLimit(c.AppContext.Session().UserId, w, r)
 			if rateLimitExceeded {
 				return
 			}
@@ -349,30 +355,9 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		c.SessionRequired()
 	}
 
-	fmt.Println("\n\n\nChecking whitelisted 222", h.RequireSession, r.URL.Path)
-	if h.RequireSession {
-		// Skip whitelist check for certain paths (login, public endpoints, etc.)
-		shouldSkip := h.shouldSkipWhitelistCheck(r.URL.Path)
-		fmt.Println("\n\n\nShould skip: ", shouldSkip)
-		if !shouldSkip {
-			isWhitelisted, err := c.App.CheckUserIPWhitelisted(c.AppContext, c.AppContext.Session().UserId, userIps)
-			fmt.Println("\n\n\nIs whitelisted 222: ", isWhitelisted, "Error: ", err, r.URL.Path)
-			if err != nil {
-				fmt.Println("\n\n\nError checking IP whitelist")
-				c.Logger.Error("Error checking IP whitelist", mlog.Err(err))
-				c.Err = model.NewAppError("ServeHTTP", "api.context.whitelist.check_error.app_error", nil, "", http.StatusInternalServerError)
-			} else if !isWhitelisted {
-				c.Logger.Warn("Access denied: IP not in whitelist",
-					mlog.String("user_id", c.AppContext.Session().UserId),
-					mlog.String("ip", c.AppContext.IPAddress()),
-					mlog.String("path", r.URL.Path))
-				// Clear session cookie and return 401 to force redirect to login page, similar to session expiry
-				c.RemoveSessionCookie(w, r)
-				c.Err = model.NewAppError("ServeHTTP", "api.context.ip_whitelist_denied.app_error",
-					nil, "", http.StatusUnauthorized)
-			}
-			fmt.Println("\n\n\nAccess granted: IP whitelisted")
-		}
+	// Skip whitelist check for certain paths (login, public endpoints, etc.)
+	if h.RequireSession && !c.App.IsWhitelistExcludedPath(r.URL.Path) {
+		checkIPWhitelist(c, w, r)
 	}
 
 	if c.Err == nil && h.RequireMfa {
@@ -562,42 +547,6 @@ func (h *Handler) checkCSRFToken(c *Context, r *http.Request, token string, toke
 	return csrfCheckNeeded, csrfCheckPassed
 }
 
-// shouldSkipWhitelistCheck determines if the whitelist check should be skipped for certain paths
-func (h *Handler) shouldSkipWhitelistCheck(path string) bool {
-	skipPaths := []string{
-		"/api/v4/users/login",
-		"/api/v4/users/logout",
-		"/api/v4/users/me",                      // Get current user info
-		"/api/v4/users/me/teams",                // Get user's teams after login
-		"/api/v4/users/me/preferences",          // Get user preferences
-		"/api/v4/users/me/teams/members",        // Get user's team memberships
-		"/api/v4/users/me/teams/unread",         // Get unread team counts
-		"/api/v4/system/ping",
-		"/api/v4/config/client",
-		"/api/v4/license/client",                // Get license info
-		"/api/v4/teams",                         // List all teams (needed for team selection page)
-		"/api/v4/teams/invite/",                 // Team invite info lookup (before joining)
-		"/api/v4/teams/members/invite",          // Team invite join endpoint
-		"/api/v4/roles/names",                   // Get role information
-		"/api/v4/users/ids",                     // Get user info by IDs
-		"/api/v4/custom_profile_attributes/",    // Custom profile attributes
-		"/api/v4/plugins/webapp",                // Plugin manifests
-		"/login",
-		"/signup",
-		"/static/",
-		"/fonts/",
-		"/images/",
-	}
-
-	for _, skipPath := range skipPaths {
-		if strings.HasPrefix(path, skipPath) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // APIHandler provides a handler for API endpoints which do not require the user to be logged in order for access to be
 // granted.
 func (w *Web) APIHandler(h func(*Context, http.ResponseWriter, *http.Request)) http.Handler {
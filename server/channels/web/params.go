@@ -35,6 +35,8 @@ type Params struct {
 	ChannelId                          string
 	PostId                             string
 	PolicyId                           string
+	PolicyName                         string
+	SavedSearchId                      string
 	FileId                             string
 	Filename                           string
 	UploadId                           string
@@ -143,6 +145,8 @@ func ParamsFromRequest(r *http.Request) *Params {
 
 	params.PostId = props["post_id"]
 	params.PolicyId = props["policy_id"]
+	params.PolicyName = props["policy_name"]
+	params.SavedSearchId = props["saved_search_id"]
 	params.FileId = props["file_id"]
 	params.Filename = query.Get("filename")
 	params.UploadId = props["upload_id"]
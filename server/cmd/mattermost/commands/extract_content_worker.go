@@ -0,0 +1,32 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost/server/v8/platform/services/docextractor"
+)
+
+// ExtractContentWorkerCmd is run by the server to extract the content of the files in a
+// subprocess, when FileSettings.ExtractContentInWorker is enabled.
+var ExtractContentWorkerCmd = &cobra.Command{
+	Use:    docextractor.WorkerCommandName,
+	Short:  "Extract the content of a file sent by the server on the standard input",
+	Hidden: true,
+	// The standard output is read by the server, so nothing else must be written to it
+	PersistentPreRun: func(command *cobra.Command, args []string) {},
+	RunE:             extractContentWorkerCmdF,
+	SilenceUsage:     true,
+}
+
+func init() {
+	RootCmd.AddCommand(ExtractContentWorkerCmd)
+}
+
+func extractContentWorkerCmdF(command *cobra.Command, args []string) error {
+	return docextractor.RunWorker(os.Stdin, os.Stdout)
+}
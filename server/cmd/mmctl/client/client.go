@@ -163,4 +163,23 @@ type Client interface {
 	DeletePreferences(ctx context.Context, userId string, preferences model.Preferences) (*model.Response, error)
 	PermanentDeletePost(ctx context.Context, postID string) (*model.Response, error)
 	DeletePost(ctx context.Context, postId string) (*model.Response, error)
+	GetWhitelistPolicies(ctx context.Context, page, perPage int) ([]*model.WhitelistPolicy, *model.Response, error)
+	GetWhitelistPolicy(ctx context.Context, policyID string) (*model.WhitelistPolicy, *model.Response, error)
+	GetWhitelistPolicyByName(ctx context.Context, name string) (*model.WhitelistPolicy, *model.Response, error)
+	CreateWhitelistPolicy(ctx context.Context, policy *model.WhitelistPolicy) (*model.WhitelistPolicy, *model.Response, error)
+	PatchWhitelistPolicy(ctx context.Context, policyID string, patch *model.WhitelistPolicyPatch) (*model.WhitelistPolicy, *model.Response, error)
+	DeleteWhitelistPolicy(ctx context.Context, policyID string) (*model.Response, error)
+	GetWhitelistPolicyTargets(ctx context.Context, policyID string) ([]*model.WhitelistPolicyTarget, *model.Response, error)
+	AddWhitelistPolicyTarget(ctx context.Context, policyID, targetType, targetID string) (*model.WhitelistPolicyTarget, *model.Response, error)
+	RemoveWhitelistPolicyTarget(ctx context.Context, policyID, targetType, targetID string) (*model.Response, error)
+	GetUserWhitelist(ctx context.Context, userID string) ([]*model.WhitelistItem, *model.Response, error)
+	AddUserToWhitelist(ctx context.Context, userID string, item *model.WhitelistItem) (*model.WhitelistItem, *model.Response, error)
+	RemoveUserFromWhitelist(ctx context.Context, userID, ip string, revokeSessions bool) (*model.Response, error)
+	GetAllWhitelistItems(ctx context.Context, afterUserID, afterIP string, perPage int) ([]*model.WhitelistItemForExport, *model.Response, error)
+	CreateBleveSnapshot(ctx context.Context) (*model.Job, *model.Response, error)
+	GetBleveSnapshots(ctx context.Context) ([]*model.BleveSnapshot, *model.Response, error)
+	RestoreBleveSnapshot(ctx context.Context, name string) (*model.BleveSnapshot, *model.Response, error)
+	GetLoginBans(ctx context.Context) ([]*model.LoginBan, *model.Response, error)
+	RemoveLoginBan(ctx context.Context, ip string) (*model.Response, error)
+	ClearLoginBans(ctx context.Context) (*model.Response, error)
 }
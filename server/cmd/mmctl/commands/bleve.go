@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+)
+
+var BleveCmd = &cobra.Command{
+	Use:   "bleve",
+	Short: "Management of the Bleve search indexes",
+}
+
+var BleveSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Take a snapshot of the Bleve indexes",
+	Long: `Create the job taking a snapshot of the Bleve indexes without stopping the search engine and storing it in the file store.
+The snapshot is listed once the job is done, and can be restored with the restore command instead of indexing everything again.`,
+	Example: "  bleve snapshot",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(bleveSnapshotCmdF),
+}
+
+var BleveSnapshotListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the snapshots of the Bleve indexes",
+	Example: "  bleve snapshot list",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(bleveSnapshotListCmdF),
+}
+
+var BleveRestoreCmd = &cobra.Command{
+	Use:   "restore [snapshot]",
+	Short: "Restore a snapshot of the Bleve indexes",
+	Long: `Replace the Bleve indexes by the ones of a snapshot of the file store.
+The changes made since the snapshot was taken are only searchable once indexed again.`,
+	Example: "  bleve restore 20250102-030405-k3n8w1qa",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(bleveRestoreCmdF),
+}
+
+func init() {
+	BleveSnapshotCmd.AddCommand(
+		BleveSnapshotListCmd,
+	)
+
+	BleveCmd.AddCommand(
+		BleveSnapshotCmd,
+		BleveRestoreCmd,
+	)
+
+	RootCmd.AddCommand(BleveCmd)
+}
+
+func bleveSnapshotCmdF(c client.Client, command *cobra.Command, args []string) error {
+	job, _, err := c.CreateBleveSnapshot(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "failed to take the snapshot")
+	}
+
+	printer.PrintT("Snapshot job {{.Id}} created", job)
+	return nil
+}
+
+func bleveSnapshotListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	snapshots, _, err := c.GetBleveSnapshots(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the snapshots")
+	}
+
+	for _, snapshot := range snapshots {
+		printer.PrintT("{{.Name}}: server {{.ServerVersion}}, {{.Size}} bytes, {{index .DocCounts \"posts\"}} posts, {{index .DocCounts \"files\"}} files", snapshot)
+	}
+
+	return nil
+}
+
+func bleveRestoreCmdF(c client.Client, command *cobra.Command, args []string) error {
+	snapshot, _, err := c.RestoreBleveSnapshot(context.TODO(), args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to restore the snapshot")
+	}
+
+	printer.PrintT("Snapshot {{.Name}} restored", snapshot)
+	return nil
+}
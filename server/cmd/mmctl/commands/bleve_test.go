@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestBleveSnapshotCmd() {
+	s.Run("Take a snapshot", func() {
+		printer.Clean()
+
+		job := &model.Job{Id: model.NewId(), Type: model.JobTypeBleveSnapshot}
+
+		s.client.
+			EXPECT().
+			CreateBleveSnapshot(context.TODO()).
+			Return(job, &model.Response{}, nil).
+			Times(1)
+
+		err := bleveSnapshotCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(job, printer.GetLines()[0])
+	})
+
+	s.Run("Fail to take a snapshot", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			CreateBleveSnapshot(context.TODO()).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := bleveSnapshotCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().EqualError(err, "failed to take the snapshot: mock error")
+		s.Require().Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestBleveSnapshotListCmd() {
+	s.Run("List snapshots", func() {
+		printer.Clean()
+
+		snapshots := []*model.BleveSnapshot{
+			{Name: "20250102-030405-k3n8w1qa", DocCounts: map[string]uint64{"posts": 10, "files": 2}},
+			{Name: "20250101-030405-p1x9e2rt", DocCounts: map[string]uint64{"posts": 8, "files": 1}},
+		}
+
+		s.client.
+			EXPECT().
+			GetBleveSnapshots(context.TODO()).
+			Return(snapshots, &model.Response{}, nil).
+			Times(1)
+
+		err := bleveSnapshotListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(snapshots[0], printer.GetLines()[0])
+		s.Require().Equal(snapshots[1], printer.GetLines()[1])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestBleveRestoreCmd() {
+	s.Run("Restore a snapshot", func() {
+		printer.Clean()
+
+		snapshot := &model.BleveSnapshot{Name: "20250102-030405-k3n8w1qa"}
+
+		s.client.
+			EXPECT().
+			RestoreBleveSnapshot(context.TODO(), snapshot.Name).
+			Return(snapshot, &model.Response{}, nil).
+			Times(1)
+
+		err := bleveRestoreCmdF(s.client, &cobra.Command{}, []string{snapshot.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(snapshot, printer.GetLines()[0])
+	})
+
+	s.Run("Fail to restore a snapshot", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			RestoreBleveSnapshot(context.TODO(), "unknown").
+			Return(nil, &model.Response{StatusCode: 404}, errors.New("mock error")).
+			Times(1)
+
+		err := bleveRestoreCmdF(s.client, &cobra.Command{}, []string{"unknown"})
+		s.Require().EqualError(err, "failed to restore the snapshot: mock error")
+		s.Require().Empty(printer.GetLines())
+	})
+}
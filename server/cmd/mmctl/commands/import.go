@@ -350,6 +350,7 @@ type Statistics struct {
 	Channels       uint64 `json:"channels"`
 	Users          uint64 `json:"users"`
 	Emojis         uint64 `json:"emojis"`
+	Whitelist      uint64 `json:"whitelist"`
 	Posts          uint64 `json:"posts"`
 	DirectChannels uint64 `json:"direct_channels"`
 	DirectPosts    uint64 `json:"direct_posts"`
@@ -515,6 +516,7 @@ func importValidateCmdF(c client.Client, command *cobra.Command, args []string)
 		DirectChannels: (validator.DirectChannelCount()),
 		DirectPosts:    (validator.DirectPostCount()),
 		Emojis:         (validator.Emojis()),
+		Whitelist:      validator.WhitelistEntries(),
 		Attachments:    uint64(len(validator.Attachments())),
 	}
 
@@ -556,6 +558,7 @@ func printStatistics(stat Statistics) {
 		"Channels        {{ .Channels }}\n" +
 		"Users           {{ .Users }}\n" +
 		"Emojis          {{ .Emojis }}\n" +
+		"Whitelist       {{ .Whitelist }}\n" +
 		"Posts           {{ .Posts }}\n" +
 		"Direct Channels {{ .DirectChannels }}\n" +
 		"Direct Posts    {{ .DirectPosts }}\n" +
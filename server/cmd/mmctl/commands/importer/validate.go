@@ -67,6 +67,7 @@ type Validator struct { //nolint:govet
 	directChannels uint64
 	directPosts    uint64
 	emojis         map[string]ImportFileInfo
+	whitelist      uint64
 
 	maxPostSize int
 
@@ -88,6 +89,7 @@ const (
 	LineTypeDirectChannel = "direct_channel"
 	LineTypeDirectPost    = "direct_post"
 	LineTypeEmoji         = "emoji"
+	LineTypeWhitelist     = "whitelist"
 )
 
 func NewValidator(
@@ -176,6 +178,10 @@ func (v *Validator) Emojis() uint64 {
 	return uint64(len(v.emojis))
 }
 
+func (v *Validator) WhitelistEntries() uint64 {
+	return v.whitelist
+}
+
 func (v *Validator) StartTime() time.Time {
 	return v.start
 }
@@ -421,6 +427,8 @@ func (v *Validator) validateLine(info ImportFileInfo, line imports.LineImportDat
 		err = v.validateDirectPost(info, line)
 	case LineTypeEmoji:
 		err = v.validateEmoji(info, line)
+	case LineTypeWhitelist:
+		err = v.validateWhitelist(info, line)
 	default:
 		err = v.onError(&ImportValidationError{
 			ImportFileInfo: info,
@@ -982,6 +990,38 @@ func (v *Validator) validateDirectPost(info ImportFileInfo, line imports.LineImp
 	return nil
 }
 
+func (v *Validator) validateWhitelist(info ImportFileInfo, line imports.LineImportData) (err error) {
+	ivErr := validateNotNil(info, "whitelist", line.Whitelist, func(data imports.WhitelistImportData) *ImportValidationError {
+		appErr := imports.ValidateWhitelistImportData(&data)
+		if appErr != nil {
+			return &ImportValidationError{
+				ImportFileInfo: info,
+				FieldName:      "whitelist",
+				Err:            appErr,
+			}
+		}
+
+		if _, ok := v.users[*data.Username]; !ok {
+			return &ImportValidationError{
+				ImportFileInfo: info,
+				FieldName:      "whitelist.username",
+				Err:            fmt.Errorf("reference to unknown user %q", *data.Username),
+			}
+		}
+
+		return nil
+	})
+	if ivErr != nil {
+		if err = v.onError(ivErr); err != nil {
+			return err
+		}
+	}
+
+	v.whitelist++
+
+	return nil
+}
+
 func (v *Validator) validateEmoji(info ImportFileInfo, line imports.LineImportData) (err error) {
 	ivErr := validateNotNil(info, "emoji", line.Emoji, func(data imports.EmojiImportData) *ImportValidationError {
 		appErr := imports.ValidateEmojiImportData(&data)
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var LoginBanCmd = &cobra.Command{
+	Use:   "login-ban",
+	Short: "Management of the addresses banned after failed logins",
+}
+
+var LoginBanListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the active login bans",
+	Example: "  login-ban list",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(loginBanListCmdF),
+}
+
+var LoginBanClearCmd = &cobra.Command{
+	Use:   "clear [ips]",
+	Short: "Lift login bans",
+	Long:  "Lift the login bans of the given IP addresses or CIDR ranges, or every ban with --all. Their failed logins are forgotten as well.",
+	Example: `  login-ban clear 203.0.113.7 198.51.100.0/24
+  login-ban clear --all`,
+	RunE: withClient(loginBanClearCmdF),
+}
+
+func init() {
+	LoginBanClearCmd.Flags().Bool("all", false, "Lift every login ban")
+
+	LoginBanCmd.AddCommand(
+		LoginBanListCmd,
+		LoginBanClearCmd,
+	)
+
+	RootCmd.AddCommand(LoginBanCmd)
+}
+
+func loginBanListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	bans, _, err := c.GetLoginBans(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch login bans")
+	}
+
+	for _, ban := range bans {
+		printer.PrintT("{{.IP}}: {{.FailedAttempts}} failed logins from the {{.Reason}}", ban)
+	}
+
+	return nil
+}
+
+func loginBanClearCmdF(c client.Client, command *cobra.Command, args []string) error {
+	all, _ := command.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		return errors.New("either IP addresses or --all must be given")
+	}
+
+	if all {
+		if _, err := c.ClearLoginBans(context.TODO()); err != nil {
+			return errors.Wrap(err, "failed to lift login bans")
+		}
+
+		printer.Print("Lifted every login ban")
+		return nil
+	}
+
+	var result *multierror.Error
+	for _, ip := range args {
+		if _, err := c.RemoveLoginBan(context.TODO(), ip); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to lift the login ban of %q: %w", ip, err))
+			continue
+		}
+
+		printer.PrintT("Lifted the login ban of {{.IP}}", &model.LoginBan{IP: ip})
+	}
+
+	return result.ErrorOrNil()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestLoginBanListCmd() {
+	s.Run("List login bans", func() {
+		printer.Clean()
+
+		bans := []*model.LoginBan{
+			{IP: "203.0.113.7/32", Reason: model.LoginBanReasonAddress, FailedAttempts: 30},
+			{IP: "198.51.100.0/24", Reason: model.LoginBanReasonSubnet, FailedAttempts: 100},
+		}
+
+		s.client.
+			EXPECT().
+			GetLoginBans(context.TODO()).
+			Return(bans, &model.Response{}, nil).
+			Times(1)
+
+		err := loginBanListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(bans[0], printer.GetLines()[0])
+		s.Require().Equal(bans[1], printer.GetLines()[1])
+	})
+
+	s.Run("Fail to list login bans", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetLoginBans(context.TODO()).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := loginBanListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().EqualError(err, "failed to fetch login bans: mock error")
+		s.Require().Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestLoginBanClearCmd() {
+	s.Run("Lift the bans of addresses", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			RemoveLoginBan(context.TODO(), "203.0.113.7").
+			Return(&model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			RemoveLoginBan(context.TODO(), "198.51.100.0/24").
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := loginBanClearCmdF(s.client, &cobra.Command{}, []string{"203.0.113.7", "198.51.100.0/24"})
+		s.Require().ErrorContains(err, `failed to lift the login ban of "198.51.100.0/24": mock error`)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&model.LoginBan{IP: "203.0.113.7"}, printer.GetLines()[0])
+	})
+
+	s.Run("Lift every ban", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			ClearLoginBans(context.TODO()).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", true, "")
+
+		err := loginBanClearCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Require either addresses or --all", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", true, "")
+
+		err := loginBanClearCmdF(s.client, cmd, []string{"203.0.113.7"})
+		s.Require().EqualError(err, "either IP addresses or --all must be given")
+
+		err = loginBanClearCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().EqualError(err, "either IP addresses or --all must be given")
+	})
+}
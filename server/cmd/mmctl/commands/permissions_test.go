@@ -248,6 +248,7 @@ func (s *MmctlUnitTestSuite) TestResetPermissionsCmd() {
 			"order_bookmark_private_channel",
 			"manage_public_channel_banner",
 			"manage_private_channel_banner",
+			"manage_channel_scheduled_posts",
 		}
 		expectedPatch := &model.RolePatch{
 			Permissions: &expectedPermissions,
@@ -0,0 +1,781 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var WhitelistCmd = &cobra.Command{
+	Use:   "whitelist",
+	Short: "Management of the IP whitelist",
+}
+
+var WhitelistListCmd = &cobra.Command{
+	Use:   "list [users]",
+	Short: "List IP whitelist entries",
+	Long:  "List the IP whitelist entries of the given users, or of every user if none is given.",
+	Example: `  whitelist list
+  whitelist list user@example.com john`,
+	RunE: withClient(whitelistListCmdF),
+}
+
+var WhitelistAddCmd = &cobra.Command{
+	Use:     "add [user] [ips]",
+	Short:   "Add IP addresses or ranges to a user's whitelist",
+	Example: `  whitelist add john 203.0.113.7 10.20.0.0/16 --description "Office" --expires-in 720h`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(whitelistAddCmdF),
+}
+
+var WhitelistRemoveCmd = &cobra.Command{
+	Use:     "remove [user] [ips]",
+	Short:   "Remove IP addresses or ranges from a user's whitelist",
+	Example: "  whitelist remove john 203.0.113.7 --revoke-sessions",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(whitelistRemoveCmdF),
+}
+
+var WhitelistExportCmd = &cobra.Command{
+	Use:   "export [users]",
+	Short: "Export IP whitelist entries",
+	Long:  "Export the active IP whitelist entries of the given users, or of every user if none is given, as CSV or JSON.",
+	Example: `  whitelist export --output whitelist.csv
+  whitelist export john --format json --output john.json`,
+	RunE: withClient(whitelistExportCmdF),
+}
+
+var WhitelistImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import IP whitelist entries",
+	Long: `Import IP whitelist entries from a CSV or JSON file, as written by the export command.
+Users are referenced by the username or user_id column. Entries that already exist are skipped.`,
+	Example: `  whitelist import whitelist.csv --dry-run
+  whitelist import whitelist.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(whitelistImportCmdF),
+}
+
+var WhitelistPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Management of IP whitelist policies",
+	Long:  "Manage named IP whitelist policies that apply to every member of a team, group or role.",
+}
+
+var WhitelistPolicyListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List IP whitelist policies",
+	Example: "  whitelist policy list",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(whitelistPolicyListCmdF),
+}
+
+var WhitelistPolicyShowCmd = &cobra.Command{
+	Use:     "show [policy]",
+	Short:   "Show an IP whitelist policy",
+	Long:    "Show the IP whitelist policy specified by its name or ID",
+	Example: "  whitelist policy show office-network",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(whitelistPolicyShowCmdF),
+}
+
+var WhitelistPolicyCreateCmd = &cobra.Command{
+	Use:     "create [name]",
+	Short:   "Create an IP whitelist policy",
+	Example: `  whitelist policy create office-network --ip-range 203.0.113.0/24 --ip-range 2001:db8::/48 --description "Head office"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(whitelistPolicyCreateCmdF),
+}
+
+var WhitelistPolicyEditCmd = &cobra.Command{
+	Use:     "edit [policy]",
+	Short:   "Edit an IP whitelist policy",
+	Long:    "Change the name, description or IP ranges of a policy. Passing --ip-range replaces all of the existing ranges.",
+	Example: "  whitelist policy edit office-network --ip-range 203.0.113.0/24 --ip-range 198.51.100.7",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(whitelistPolicyEditCmdF),
+}
+
+var WhitelistPolicyDeleteCmd = &cobra.Command{
+	Use:     "delete [policy]",
+	Short:   "Delete an IP whitelist policy",
+	Long:    "Delete an IP whitelist policy and detach it from every team, group and role",
+	Example: "  whitelist policy delete office-network",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(whitelistPolicyDeleteCmdF),
+}
+
+var WhitelistPolicyTargetsCmd = &cobra.Command{
+	Use:     "targets [policy]",
+	Short:   "List the teams, groups and roles a policy is attached to",
+	Example: "  whitelist policy targets office-network",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(whitelistPolicyTargetsCmdF),
+}
+
+var WhitelistPolicyAddTargetCmd = &cobra.Command{
+	Use:   "add-target [policy] [team|group|role] [target]",
+	Short: "Attach a policy to a team, group or role",
+	Long:  "Attach a policy to a team (name or ID), a group (ID) or a role (name).",
+	Example: `  whitelist policy add-target office-network team myteam
+  whitelist policy add-target office-network role system_user`,
+	Args: cobra.ExactArgs(3),
+	RunE: withClient(whitelistPolicyAddTargetCmdF),
+}
+
+var WhitelistPolicyRemoveTargetCmd = &cobra.Command{
+	Use:     "remove-target [policy] [team|group|role] [target]",
+	Short:   "Detach a policy from a team, group or role",
+	Example: "  whitelist policy remove-target office-network team myteam",
+	Args:    cobra.ExactArgs(3),
+	RunE:    withClient(whitelistPolicyRemoveTargetCmdF),
+}
+
+func init() {
+	WhitelistAddCmd.Flags().String("description", "", "Description of the entries")
+	WhitelistAddCmd.Flags().Duration("expires-in", 0, "Time after which the entries expire, e.g. 24h. By default they never expire")
+
+	WhitelistRemoveCmd.Flags().Bool("revoke-sessions", false, "Revoke the sessions of the user that are no longer whitelisted")
+
+	WhitelistExportCmd.Flags().String("format", whitelistFormatCSV, "Format of the exported entries, csv or json")
+	WhitelistExportCmd.Flags().String("output", "", "File to write the entries to. Defaults to the standard output")
+
+	WhitelistImportCmd.Flags().String("format", "", "Format of the file, csv or json. Defaults to the file extension")
+	WhitelistImportCmd.Flags().Bool("dry-run", false, "Only validate the file without adding any entry")
+
+	WhitelistPolicyListCmd.Flags().Int("page", 0, "Page number to fetch for the list of policies")
+	WhitelistPolicyListCmd.Flags().Int("per-page", DefaultPageSize, "Number of policies to be fetched")
+
+	WhitelistPolicyCreateCmd.Flags().String("description", "", "Policy description")
+	WhitelistPolicyCreateCmd.Flags().StringArray("ip-range", []string{}, "IP address or CIDR range allowed by the policy (required)")
+	_ = WhitelistPolicyCreateCmd.MarkFlagRequired("ip-range")
+
+	WhitelistPolicyEditCmd.Flags().String("name", "", "New policy name")
+	WhitelistPolicyEditCmd.Flags().String("description", "", "New policy description")
+	WhitelistPolicyEditCmd.Flags().StringArray("ip-range", []string{}, "IP address or CIDR range allowed by the policy")
+
+	WhitelistPolicyCmd.AddCommand(
+		WhitelistPolicyListCmd,
+		WhitelistPolicyShowCmd,
+		WhitelistPolicyCreateCmd,
+		WhitelistPolicyEditCmd,
+		WhitelistPolicyDeleteCmd,
+		WhitelistPolicyTargetsCmd,
+		WhitelistPolicyAddTargetCmd,
+		WhitelistPolicyRemoveTargetCmd,
+	)
+
+	WhitelistCmd.AddCommand(
+		WhitelistListCmd,
+		WhitelistAddCmd,
+		WhitelistRemoveCmd,
+		WhitelistExportCmd,
+		WhitelistImportCmd,
+		WhitelistPolicyCmd,
+	)
+
+	RootCmd.AddCommand(WhitelistCmd)
+}
+
+const (
+	whitelistFormatCSV  = "csv"
+	whitelistFormatJSON = "json"
+
+	whitelistPageSize = 200
+)
+
+var whitelistCSVHeader = []string{"username", "user_id", "ip", "description", "creator_id", "create_at", "expires_at"}
+
+// getWhitelistItems gets the active entries of the given users, or of every
+// user if no user is given.
+func getWhitelistItems(c client.Client, userArgs []string) ([]*model.WhitelistItemForExport, error) {
+	var items []*model.WhitelistItemForExport
+
+	if len(userArgs) == 0 {
+		now := model.GetMillis()
+		afterUserID, afterIP := "", ""
+		for {
+			page, _, err := c.GetAllWhitelistItems(context.TODO(), afterUserID, afterIP, whitelistPageSize)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to fetch whitelist entries")
+			}
+
+			for _, item := range page {
+				afterUserID, afterIP = item.UserId, item.IP
+				// Expired entries are only kept until the cleanup job runs
+				if !item.IsExpired(now) {
+					items = append(items, item)
+				}
+			}
+
+			if len(page) < whitelistPageSize {
+				return items, nil
+			}
+		}
+	}
+
+	users, err := getUsersFromArgs(c, userArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		userItems, _, err := c.GetUserWhitelist(context.TODO(), user.Id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch the whitelist of user %q", user.Username)
+		}
+		for _, item := range userItems {
+			items = append(items, &model.WhitelistItemForExport{WhitelistItem: *item, Username: user.Username})
+		}
+	}
+
+	return items, nil
+}
+
+func whitelistListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	items, err := getWhitelistItems(c, args)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		printer.PrintT("{{.Username}}: {{.IP}}{{if .Description}} ({{.Description}}){{end}}", item)
+	}
+
+	return nil
+}
+
+func whitelistAddCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	description, _ := command.Flags().GetString("description")
+	expiresIn, _ := command.Flags().GetDuration("expires-in")
+	if expiresIn < 0 {
+		return errors.New("--expires-in must not be negative")
+	}
+
+	var expiresAt int64
+	if expiresIn > 0 {
+		expiresAt = model.GetMillis() + expiresIn.Milliseconds()
+	}
+
+	var result *multierror.Error
+	for _, ip := range args[1:] {
+		item, _, err := c.AddUserToWhitelist(context.TODO(), user.Id, &model.WhitelistItem{
+			IP:          ip,
+			Description: description,
+			ExpiresAt:   expiresAt,
+		})
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to add %q to the whitelist of user %q: %w", ip, user.Username, err))
+			continue
+		}
+
+		printer.PrintT("Added {{.IP}} to the whitelist", item)
+	}
+
+	return result.ErrorOrNil()
+}
+
+func whitelistRemoveCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user, err := getUserFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	revokeSessions, _ := command.Flags().GetBool("revoke-sessions")
+
+	var result *multierror.Error
+	for _, ip := range args[1:] {
+		if _, err := c.RemoveUserFromWhitelist(context.TODO(), user.Id, ip, revokeSessions); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to remove %q from the whitelist of user %q: %w", ip, user.Username, err))
+			continue
+		}
+
+		printer.PrintT("Removed {{.IP}} from the whitelist", &model.WhitelistItem{UserId: user.Id, IP: ip})
+	}
+
+	return result.ErrorOrNil()
+}
+
+func whitelistExportCmdF(c client.Client, command *cobra.Command, args []string) error {
+	format, _ := command.Flags().GetString("format")
+	if format != whitelistFormatCSV && format != whitelistFormatJSON {
+		return errors.Errorf("invalid format %q, must be csv or json", format)
+	}
+
+	items, err := getWhitelistItems(c, args)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = command.OutOrStdout()
+	if output, _ := command.Flags().GetString("output"); output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the output file")
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeWhitelistItems(w, format, items); err != nil {
+		return errors.Wrap(err, "failed to write the whitelist entries")
+	}
+
+	return nil
+}
+
+func writeWhitelistItems(w io.Writer, format string, items []*model.WhitelistItemForExport) error {
+	if format == whitelistFormatJSON {
+		if items == nil {
+			items = []*model.WhitelistItemForExport{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(whitelistCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range items {
+		record := []string{
+			item.Username,
+			item.UserId,
+			item.IP,
+			item.Description,
+			item.CreatorId,
+			strconv.FormatInt(item.CreateAt, 10),
+			strconv.FormatInt(item.ExpiresAt, 10),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// whitelistImportEntry is an entry read from an import file, along with the
+// line or element it was read from to report errors.
+type whitelistImportEntry struct {
+	position string
+	user     string
+	item     *model.WhitelistItem
+}
+
+func readWhitelistImportEntries(r io.Reader, format string) ([]*whitelistImportEntry, error) {
+	if format == whitelistFormatJSON {
+		var items []*model.WhitelistItemForExport
+		if err := json.NewDecoder(r).Decode(&items); err != nil {
+			return nil, errors.Wrap(err, "failed to decode the JSON file")
+		}
+
+		entries := make([]*whitelistImportEntry, 0, len(items))
+		for i, item := range items {
+			if item == nil {
+				return nil, errors.Errorf("entry %d: the entry is empty", i+1)
+			}
+			user := item.Username
+			if user == "" {
+				user = item.UserId
+			}
+			entries = append(entries, &whitelistImportEntry{
+				position: fmt.Sprintf("entry %d", i+1),
+				user:     user,
+				item:     &model.WhitelistItem{IP: item.IP, Description: item.Description, ExpiresAt: item.ExpiresAt},
+			})
+		}
+		return entries, nil
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the CSV file")
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasUsername := columns["username"]
+	_, hasUserID := columns["user_id"]
+	if _, ok := columns["ip"]; !ok || (!hasUsername && !hasUserID) {
+		return nil, errors.New("the CSV header must have an ip column and a username or user_id column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	entries := make([]*whitelistImportEntry, 0, len(records)-1)
+	for i, record := range records[1:] {
+		position := fmt.Sprintf("line %d", i+2)
+
+		var expiresAt int64
+		if value := field(record, "expires_at"); value != "" {
+			if expiresAt, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, errors.Errorf("%s: invalid expires_at %q", position, value)
+			}
+		}
+
+		user := field(record, "username")
+		if user == "" {
+			user = field(record, "user_id")
+		}
+		entries = append(entries, &whitelistImportEntry{
+			position: position,
+			user:     user,
+			item:     &model.WhitelistItem{IP: field(record, "ip"), Description: field(record, "description"), ExpiresAt: expiresAt},
+		})
+	}
+
+	return entries, nil
+}
+
+// validateWhitelistImportEntry checks an entry the way the server does, so that a
+// dry run reports the errors the import would run into.
+func validateWhitelistImportEntry(entry *whitelistImportEntry, now int64) error {
+	if entry.user == "" {
+		return errors.New("missing user")
+	}
+
+	normalized, err := model.NormalizeWhitelistIP(entry.item.IP)
+	if err != nil {
+		return errors.Errorf("invalid IP address or range %q", entry.item.IP)
+	}
+	entry.item.IP = normalized
+
+	if utf8.RuneCountInString(entry.item.Description) > model.WhitelistItemDescriptionMaxLength {
+		return errors.Errorf("description is longer than %d characters", model.WhitelistItemDescriptionMaxLength)
+	}
+
+	if entry.item.ExpiresAt < 0 {
+		return errors.New("invalid expires_at")
+	}
+	if entry.item.IsExpired(now) {
+		return errors.Errorf("the entry expired on %s", time.UnixMilli(entry.item.ExpiresAt).UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func whitelistImportCmdF(c client.Client, command *cobra.Command, args []string) error {
+	format, _ := command.Flags().GetString("format")
+	if format == "" {
+		format = whitelistFormatCSV
+		if strings.EqualFold(filepath.Ext(args[0]), ".json") {
+			format = whitelistFormatJSON
+		}
+	}
+	if format != whitelistFormatCSV && format != whitelistFormatJSON {
+		return errors.Errorf("invalid format %q, must be csv or json", format)
+	}
+	dryRun, _ := command.Flags().GetBool("dry-run")
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to open the import file")
+	}
+	defer file.Close()
+
+	entries, err := readWhitelistImportEntries(file, format)
+	if err != nil {
+		return err
+	}
+
+	// Validate every entry before adding any, so that a broken file is not half imported
+	now := model.GetMillis()
+	users := map[string]*model.User{}
+	existing := map[string]map[string]bool{}
+	var toAdd []*whitelistImportEntry
+	failed := 0
+	skipped := 0
+	for _, entry := range entries {
+		if err := validateWhitelistImportEntry(entry, now); err != nil {
+			printer.PrintError(fmt.Sprintf("%s: %s", entry.position, err))
+			failed++
+			continue
+		}
+
+		user, ok := users[entry.user]
+		if !ok {
+			if user, err = getUserFromArg(c, entry.user); err != nil {
+				printer.PrintError(fmt.Sprintf("%s: %s", entry.position, err))
+				failed++
+				continue
+			}
+			users[entry.user] = user
+		}
+
+		if _, ok := existing[user.Id]; !ok {
+			userItems, _, err := c.GetUserWhitelist(context.TODO(), user.Id)
+			if err != nil {
+				return errors.Wrapf(err, "failed to fetch the whitelist of user %q", user.Username)
+			}
+			existing[user.Id] = map[string]bool{}
+			for _, item := range userItems {
+				existing[user.Id][item.IP] = true
+			}
+		}
+
+		if existing[user.Id][entry.item.IP] {
+			skipped++
+			continue
+		}
+		existing[user.Id][entry.item.IP] = true
+
+		entry.item.UserId = user.Id
+		toAdd = append(toAdd, entry)
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d entries are invalid, no entry was imported", failed, len(entries))
+	}
+
+	if dryRun {
+		printer.PrintT("Validated {{.Total}} entries: {{.Added}} would be added and {{.Skipped}} already exist", whitelistImportResult{Total: len(entries), Added: len(toAdd), Skipped: skipped})
+		return nil
+	}
+
+	added := 0
+	var result *multierror.Error
+	for _, entry := range toAdd {
+		if _, _, err := c.AddUserToWhitelist(context.TODO(), entry.item.UserId, entry.item); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s: failed to add %q: %w", entry.position, entry.item.IP, err))
+			continue
+		}
+		added++
+	}
+
+	printer.PrintT("Imported {{.Total}} entries: {{.Added}} added and {{.Skipped}} already existed", whitelistImportResult{Total: len(entries), Added: added, Skipped: skipped})
+	return result.ErrorOrNil()
+}
+
+type whitelistImportResult struct {
+	Total   int `json:"total"`
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+}
+
+// getWhitelistPolicyFromArg looks up a policy by ID first and falls back to
+// its name.
+func getWhitelistPolicyFromArg(c client.Client, policyArg string) (*model.WhitelistPolicy, error) {
+	if model.IsValidId(policyArg) {
+		if policy, _, err := c.GetWhitelistPolicy(context.TODO(), policyArg); err == nil {
+			return policy, nil
+		}
+	}
+
+	policy, _, err := c.GetWhitelistPolicyByName(context.TODO(), policyArg)
+	if err != nil {
+		return nil, errors.Errorf("unable to find whitelist policy %q", policyArg)
+	}
+
+	return policy, nil
+}
+
+// getWhitelistPolicyTargetId resolves the target argument to the identifier
+// stored for the given target type.
+func getWhitelistPolicyTargetId(c client.Client, targetType, targetArg string) (string, error) {
+	switch targetType {
+	case model.WhitelistPolicyTargetTypeTeam:
+		team := getTeamFromTeamArg(c, targetArg)
+		if team == nil {
+			return "", errors.Errorf("unable to find team %q", targetArg)
+		}
+		return team.Id, nil
+	case model.WhitelistPolicyTargetTypeGroup, model.WhitelistPolicyTargetTypeRole:
+		return targetArg, nil
+	default:
+		return "", errors.Errorf("invalid target type %q, must be one of team, group or role", targetType)
+	}
+}
+
+func whitelistPolicyListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	page, err := command.Flags().GetInt("page")
+	if err != nil {
+		return err
+	}
+	perPage, err := command.Flags().GetInt("per-page")
+	if err != nil {
+		return err
+	}
+
+	policies, _, err := c.GetWhitelistPolicies(context.TODO(), page, perPage)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch whitelist policies")
+	}
+
+	for _, policy := range policies {
+		printer.PrintT("{{.Id}}: {{.Name}} ({{len .IPRanges}} ranges)", policy)
+	}
+
+	return nil
+}
+
+func whitelistPolicyShowCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT("Id: {{.Id}}\nName: {{.Name}}\nDescription: {{.Description}}\nIP ranges: {{range .IPRanges}}\n  {{.}}{{end}}", policy)
+	return nil
+}
+
+func whitelistPolicyCreateCmdF(c client.Client, command *cobra.Command, args []string) error {
+	description, _ := command.Flags().GetString("description")
+	ipRanges, _ := command.Flags().GetStringArray("ip-range")
+
+	policy := &model.WhitelistPolicy{
+		Name:        args[0],
+		Description: description,
+		IPRanges:    ipRanges,
+	}
+
+	created, _, err := c.CreateWhitelistPolicy(context.TODO(), policy)
+	if err != nil {
+		return errors.Wrap(err, "failed to create whitelist policy")
+	}
+
+	printer.PrintT("Whitelist policy {{.Name}} created with id {{.Id}}", created)
+	return nil
+}
+
+func whitelistPolicyEditCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	patch := &model.WhitelistPolicyPatch{}
+	if command.Flags().Changed("name") {
+		name, _ := command.Flags().GetString("name")
+		patch.Name = &name
+	}
+	if command.Flags().Changed("description") {
+		description, _ := command.Flags().GetString("description")
+		patch.Description = &description
+	}
+	if command.Flags().Changed("ip-range") {
+		ipRanges, _ := command.Flags().GetStringArray("ip-range")
+		patch.IPRanges = &ipRanges
+	}
+
+	if patch.Name == nil && patch.Description == nil && patch.IPRanges == nil {
+		return errors.New("at least one of --name, --description or --ip-range must be provided")
+	}
+
+	patched, _, err := c.PatchWhitelistPolicy(context.TODO(), policy.Id, patch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update whitelist policy %q", args[0])
+	}
+
+	printer.PrintT("Whitelist policy {{.Name}} updated", patched)
+	return nil
+}
+
+func whitelistPolicyDeleteCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.DeleteWhitelistPolicy(context.TODO(), policy.Id); err != nil {
+		return errors.Wrapf(err, "failed to delete whitelist policy %q", args[0])
+	}
+
+	printer.PrintT("Whitelist policy {{.Name}} deleted", policy)
+	return nil
+}
+
+func whitelistPolicyTargetsCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	targets, _, err := c.GetWhitelistPolicyTargets(context.TODO(), policy.Id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch targets of whitelist policy %q", args[0])
+	}
+
+	for _, target := range targets {
+		printer.PrintT("{{.TargetType}}: {{.TargetId}}", target)
+	}
+
+	return nil
+}
+
+func whitelistPolicyAddTargetCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	targetId, err := getWhitelistPolicyTargetId(c, args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	target, _, err := c.AddWhitelistPolicyTarget(context.TODO(), policy.Id, args[1], targetId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to attach whitelist policy %q to %s %q", args[0], args[1], args[2])
+	}
+
+	printer.PrintT("Whitelist policy attached to {{.TargetType}} {{.TargetId}}", target)
+	return nil
+}
+
+func whitelistPolicyRemoveTargetCmdF(c client.Client, command *cobra.Command, args []string) error {
+	policy, err := getWhitelistPolicyFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	targetId, err := getWhitelistPolicyTargetId(c, args[1], args[2])
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.RemoveWhitelistPolicyTarget(context.TODO(), policy.Id, args[1], targetId); err != nil {
+		return errors.Wrapf(err, "failed to detach whitelist policy %q from %s %q", args[0], args[1], args[2])
+	}
+
+	printer.PrintT("Whitelist policy detached from {{.TargetType}} {{.TargetId}}", &model.WhitelistPolicyTarget{TargetType: args[1], TargetId: targetId})
+	return nil
+}
@@ -0,0 +1,510 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestWhitelistListCmd() {
+	user := &model.User{Id: model.NewId(), Username: "john"}
+
+	s.Run("List the entries of every user", func() {
+		printer.Clean()
+
+		active := &model.WhitelistItemForExport{WhitelistItem: model.WhitelistItem{UserId: user.Id, IP: "10.0.0.0/8"}, Username: user.Username}
+		expired := &model.WhitelistItemForExport{WhitelistItem: model.WhitelistItem{UserId: user.Id, IP: "10.0.0.1", ExpiresAt: 1}, Username: user.Username}
+
+		s.client.
+			EXPECT().
+			GetAllWhitelistItems(context.TODO(), "", "", whitelistPageSize).
+			Return([]*model.WhitelistItemForExport{active, expired}, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(active, printer.GetLines()[0])
+	})
+
+	s.Run("List the entries of a user", func() {
+		printer.Clean()
+
+		item := &model.WhitelistItem{UserId: user.Id, IP: "192.0.2.1"}
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserWhitelist(context.TODO(), user.Id).
+			Return([]*model.WhitelistItem{item}, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistListCmdF(s.client, &cobra.Command{}, []string{user.Username})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&model.WhitelistItemForExport{WhitelistItem: *item, Username: user.Username}, printer.GetLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistAddCmd() {
+	user := &model.User{Id: model.NewId(), Username: "john"}
+
+	s.Run("Add entries", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("description", "office", "")
+		cmd.Flags().Duration("expires-in", 0, "")
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		for _, ip := range []string{"192.0.2.1", "10.0.0.0/8"} {
+			s.client.
+				EXPECT().
+				AddUserToWhitelist(context.TODO(), user.Id, &model.WhitelistItem{IP: ip, Description: "office"}).
+				Return(&model.WhitelistItem{UserId: user.Id, IP: ip, Description: "office"}, &model.Response{}, nil).
+				Times(1)
+		}
+
+		err := whitelistAddCmdF(s.client, cmd, []string{user.Username, "192.0.2.1", "10.0.0.0/8"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+	})
+
+	s.Run("Fail to add an entry", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("description", "", "")
+		cmd.Flags().Duration("expires-in", 0, "")
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddUserToWhitelist(context.TODO(), user.Id, &model.WhitelistItem{IP: "nope"}).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := whitelistAddCmdF(s.client, cmd, []string{user.Username, "nope"})
+		s.Require().ErrorContains(err, `failed to add "nope" to the whitelist of user "john": mock error`)
+		s.Require().Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistRemoveCmd() {
+	s.Run("Remove an entry and revoke sessions", func() {
+		printer.Clean()
+
+		user := &model.User{Id: model.NewId(), Username: "john"}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("revoke-sessions", true, "")
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			RemoveUserFromWhitelist(context.TODO(), user.Id, "192.0.2.1", true).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		err := whitelistRemoveCmdF(s.client, cmd, []string{user.Username, "192.0.2.1"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistExportCmd() {
+	item := &model.WhitelistItemForExport{
+		WhitelistItem: model.WhitelistItem{UserId: model.NewId(), IP: "10.0.0.0/8", Description: "VPN, office", CreateAt: 1000},
+		Username:      "john",
+	}
+
+	s.Run("Export as CSV", func() {
+		printer.Clean()
+
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		cmd.Flags().String("format", whitelistFormatCSV, "")
+		cmd.Flags().String("output", "", "")
+
+		s.client.
+			EXPECT().
+			GetAllWhitelistItems(context.TODO(), "", "", whitelistPageSize).
+			Return([]*model.WhitelistItemForExport{item}, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistExportCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Equal("username,user_id,ip,description,creator_id,create_at,expires_at\n"+
+			"john,"+item.UserId+",10.0.0.0/8,\"VPN, office\",,1000,0\n", out.String())
+	})
+
+	s.Run("Invalid format", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("format", "xml", "")
+		cmd.Flags().String("output", "", "")
+
+		err := whitelistExportCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, `invalid format "xml", must be csv or json`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistImportCmd() {
+	user := &model.User{Id: model.NewId(), Username: "john"}
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(s.T().TempDir(), name)
+		s.Require().NoError(os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	newCmd := func(dryRun bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("format", "", "")
+		cmd.Flags().Bool("dry-run", dryRun, "")
+		return cmd
+	}
+
+	expectUser := func() {
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), user.Username, "").
+			Return(user, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetUserWhitelist(context.TODO(), user.Id).
+			Return([]*model.WhitelistItem{{UserId: user.Id, IP: "192.0.2.1"}}, &model.Response{}, nil).
+			Times(1)
+	}
+
+	s.Run("Dry run does not add entries", func() {
+		printer.Clean()
+
+		path := writeFile("whitelist.csv", "username,ip,description\njohn,192.0.2.1,\njohn,10.1.2.3/8,VPN\n")
+		expectUser()
+
+		err := whitelistImportCmdF(s.client, newCmd(true), []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(whitelistImportResult{Total: 2, Added: 1, Skipped: 1}, printer.GetLines()[0])
+	})
+
+	s.Run("Import JSON", func() {
+		printer.Clean()
+
+		path := writeFile("whitelist.json", `[{"username":"john","ip":"192.0.2.1"},{"username":"john","ip":"10.1.2.3/8","description":"VPN"}]`)
+		expectUser()
+
+		s.client.
+			EXPECT().
+			AddUserToWhitelist(context.TODO(), user.Id, &model.WhitelistItem{UserId: user.Id, IP: "10.0.0.0/8", Description: "VPN"}).
+			Return(&model.WhitelistItem{UserId: user.Id, IP: "10.0.0.0/8", Description: "VPN"}, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistImportCmdF(s.client, newCmd(false), []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(whitelistImportResult{Total: 2, Added: 1, Skipped: 1}, printer.GetLines()[0])
+	})
+
+	s.Run("Invalid entries abort the import", func() {
+		printer.Clean()
+
+		path := writeFile("whitelist.csv", "username,ip\njohn,not-an-ip\n,192.0.2.1\n")
+
+		err := whitelistImportCmdF(s.client, newCmd(false), []string{path})
+		s.Require().EqualError(err, "2 of 2 entries are invalid, no entry was imported")
+		s.Require().Len(printer.GetErrorLines(), 2)
+		s.Require().Equal(`line 2: invalid IP address or range "not-an-ip"`, printer.GetErrorLines()[0])
+		s.Require().Equal("line 3: missing user", printer.GetErrorLines()[1])
+	})
+
+	s.Run("Missing columns", func() {
+		path := writeFile("whitelist.csv", "name,address\njohn,192.0.2.1\n")
+
+		err := whitelistImportCmdF(s.client, newCmd(false), []string{path})
+		s.Require().EqualError(err, "the CSV header must have an ip column and a username or user_id column")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistPolicyListCmd() {
+	s.Run("List policies", func() {
+		printer.Clean()
+
+		policy := &model.WhitelistPolicy{Id: model.NewId(), Name: "office", IPRanges: []string{"10.0.0.0/8"}}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", 200, "")
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicies(context.TODO(), 0, 200).
+			Return([]*model.WhitelistPolicy{policy}, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyListCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(policy, printer.GetLines()[0])
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("Fail to list policies", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("page", 0, "")
+		cmd.Flags().Int("per-page", 200, "")
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicies(context.TODO(), 0, 200).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := whitelistPolicyListCmdF(s.client, cmd, []string{})
+		s.Require().Error(err)
+		s.Require().Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistPolicyShowCmd() {
+	s.Run("Show policy by id", func() {
+		printer.Clean()
+
+		policy := &model.WhitelistPolicy{Id: model.NewId(), Name: "office"}
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicy(context.TODO(), policy.Id).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyShowCmdF(s.client, &cobra.Command{}, []string{policy.Id})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(policy, printer.GetLines()[0])
+	})
+
+	s.Run("Show policy by name", func() {
+		printer.Clean()
+
+		policy := &model.WhitelistPolicy{Id: model.NewId(), Name: "office"}
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyShowCmdF(s.client, &cobra.Command{}, []string{policy.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(policy, printer.GetLines()[0])
+	})
+
+	s.Run("Fail to find policy", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), "missing").
+			Return(nil, &model.Response{StatusCode: 404}, errors.New("not found")).
+			Times(1)
+
+		err := whitelistPolicyShowCmdF(s.client, &cobra.Command{}, []string{"missing"})
+		s.Require().EqualError(err, `unable to find whitelist policy "missing"`)
+		s.Require().Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistPolicyCreateCmd() {
+	s.Run("Create policy", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("description", "", "")
+		cmd.Flags().StringArray("ip-range", []string{}, "")
+		_ = cmd.Flags().Set("description", "Head office")
+		_ = cmd.Flags().Set("ip-range", "10.0.0.0/8")
+		_ = cmd.Flags().Set("ip-range", "192.168.1.1")
+
+		expected := &model.WhitelistPolicy{
+			Name:        "office",
+			Description: "Head office",
+			IPRanges:    []string{"10.0.0.0/8", "192.168.1.1"},
+		}
+		created := &model.WhitelistPolicy{Id: model.NewId(), Name: "office"}
+
+		s.client.
+			EXPECT().
+			CreateWhitelistPolicy(context.TODO(), expected).
+			Return(created, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyCreateCmdF(s.client, cmd, []string{"office"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(created, printer.GetLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistPolicyEditCmd() {
+	policy := &model.WhitelistPolicy{Id: model.NewId(), Name: "office"}
+
+	newEditCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "", "")
+		cmd.Flags().String("description", "", "")
+		cmd.Flags().StringArray("ip-range", []string{}, "")
+		return cmd
+	}
+
+	s.Run("Edit policy ranges", func() {
+		printer.Clean()
+
+		cmd := newEditCmd()
+		_ = cmd.Flags().Set("ip-range", "172.16.0.0/12")
+
+		ranges := []string{"172.16.0.0/12"}
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchWhitelistPolicy(context.TODO(), policy.Id, &model.WhitelistPolicyPatch{IPRanges: &ranges}).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyEditCmdF(s.client, cmd, []string{policy.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Edit without changes", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyEditCmdF(s.client, newEditCmd(), []string{policy.Name})
+		s.Require().EqualError(err, "at least one of --name, --description or --ip-range must be provided")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestWhitelistPolicyAddTargetCmd() {
+	policy := &model.WhitelistPolicy{Id: model.NewId(), Name: "office"}
+
+	s.Run("Attach to a team by name", func() {
+		printer.Clean()
+
+		team := &model.Team{Id: model.NewId(), Name: "myteam"}
+		target := &model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeTeam, TargetId: team.Id}
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(context.TODO(), team.Name, "").
+			Return(nil, &model.Response{}, errors.New("not found")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName(context.TODO(), team.Name, "").
+			Return(team, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddWhitelistPolicyTarget(context.TODO(), policy.Id, model.WhitelistPolicyTargetTypeTeam, team.Id).
+			Return(target, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyAddTargetCmdF(s.client, &cobra.Command{}, []string{policy.Name, "team", team.Name})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(target, printer.GetLines()[0])
+	})
+
+	s.Run("Attach to a role", func() {
+		printer.Clean()
+
+		target := &model.WhitelistPolicyTarget{PolicyId: policy.Id, TargetType: model.WhitelistPolicyTargetTypeRole, TargetId: model.SystemUserRoleId}
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			AddWhitelistPolicyTarget(context.TODO(), policy.Id, model.WhitelistPolicyTargetTypeRole, model.SystemUserRoleId).
+			Return(target, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyAddTargetCmdF(s.client, &cobra.Command{}, []string{policy.Name, "role", model.SystemUserRoleId})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+	})
+
+	s.Run("Invalid target type", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetWhitelistPolicyByName(context.TODO(), policy.Name).
+			Return(policy, &model.Response{}, nil).
+			Times(1)
+
+		err := whitelistPolicyAddTargetCmdF(s.client, &cobra.Command{}, []string{policy.Name, "channel", "town-square"})
+		s.Require().EqualError(err, `invalid target type "channel", must be one of team, group or role`)
+		s.Require().Empty(printer.GetLines())
+	})
+}
@@ -72,6 +72,38 @@ func (mr *MockClientMockRecorder) AddTeamMember(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamMember", reflect.TypeOf((*MockClient)(nil).AddTeamMember), arg0, arg1, arg2)
 }
 
+// AddUserToWhitelist mocks base method.
+func (m *MockClient) AddUserToWhitelist(arg0 context.Context, arg1 string, arg2 *model.WhitelistItem) (*model.WhitelistItem, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserToWhitelist", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.WhitelistItem)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddUserToWhitelist indicates an expected call of AddUserToWhitelist.
+func (mr *MockClientMockRecorder) AddUserToWhitelist(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserToWhitelist", reflect.TypeOf((*MockClient)(nil).AddUserToWhitelist), arg0, arg1, arg2)
+}
+
+// AddWhitelistPolicyTarget mocks base method.
+func (m *MockClient) AddWhitelistPolicyTarget(arg0 context.Context, arg1, arg2, arg3 string) (*model.WhitelistPolicyTarget, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWhitelistPolicyTarget", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.WhitelistPolicyTarget)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddWhitelistPolicyTarget indicates an expected call of AddWhitelistPolicyTarget.
+func (mr *MockClientMockRecorder) AddWhitelistPolicyTarget(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWhitelistPolicyTarget", reflect.TypeOf((*MockClient)(nil).AddWhitelistPolicyTarget), arg0, arg1, arg2, arg3)
+}
+
 // AssignBot mocks base method.
 func (m *MockClient) AssignBot(arg0 context.Context, arg1, arg2 string) (*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -119,6 +151,21 @@ func (mr *MockClientMockRecorder) CheckIntegrity(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIntegrity", reflect.TypeOf((*MockClient)(nil).CheckIntegrity), arg0)
 }
 
+// ClearLoginBans mocks base method.
+func (m *MockClient) ClearLoginBans(arg0 context.Context) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginBans", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearLoginBans indicates an expected call of ClearLoginBans.
+func (mr *MockClientMockRecorder) ClearLoginBans(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginBans", reflect.TypeOf((*MockClient)(nil).ClearLoginBans), arg0)
+}
+
 // ClearServerBusy mocks base method.
 func (m *MockClient) ClearServerBusy(arg0 context.Context) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -166,6 +213,22 @@ func (mr *MockClientMockRecorder) ConvertUserToBot(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertUserToBot", reflect.TypeOf((*MockClient)(nil).ConvertUserToBot), arg0, arg1)
 }
 
+// CreateBleveSnapshot mocks base method.
+func (m *MockClient) CreateBleveSnapshot(arg0 context.Context) (*model.Job, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBleveSnapshot", arg0)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateBleveSnapshot indicates an expected call of CreateBleveSnapshot.
+func (mr *MockClientMockRecorder) CreateBleveSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBleveSnapshot", reflect.TypeOf((*MockClient)(nil).CreateBleveSnapshot), arg0)
+}
+
 // CreateBot mocks base method.
 func (m *MockClient) CreateBot(arg0 context.Context, arg1 *model.Bot) (*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -342,6 +405,22 @@ func (mr *MockClientMockRecorder) CreateUserAccessToken(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserAccessToken", reflect.TypeOf((*MockClient)(nil).CreateUserAccessToken), arg0, arg1, arg2)
 }
 
+// CreateWhitelistPolicy mocks base method.
+func (m *MockClient) CreateWhitelistPolicy(arg0 context.Context, arg1 *model.WhitelistPolicy) (*model.WhitelistPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWhitelistPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.WhitelistPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateWhitelistPolicy indicates an expected call of CreateWhitelistPolicy.
+func (mr *MockClientMockRecorder) CreateWhitelistPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWhitelistPolicy", reflect.TypeOf((*MockClient)(nil).CreateWhitelistPolicy), arg0, arg1)
+}
+
 // DeleteChannel mocks base method.
 func (m *MockClient) DeleteChannel(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -462,6 +541,21 @@ func (mr *MockClientMockRecorder) DeletePreferences(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferences", reflect.TypeOf((*MockClient)(nil).DeletePreferences), arg0, arg1, arg2)
 }
 
+// DeleteWhitelistPolicy mocks base method.
+func (m *MockClient) DeleteWhitelistPolicy(arg0 context.Context, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWhitelistPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWhitelistPolicy indicates an expected call of DeleteWhitelistPolicy.
+func (mr *MockClientMockRecorder) DeleteWhitelistPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWhitelistPolicy", reflect.TypeOf((*MockClient)(nil).DeleteWhitelistPolicy), arg0, arg1)
+}
+
 // DemoteUserToGuest mocks base method.
 func (m *MockClient) DemoteUserToGuest(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -619,6 +713,38 @@ func (mr *MockClientMockRecorder) GetAllTeams(arg0, arg1, arg2, arg3 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockClient)(nil).GetAllTeams), arg0, arg1, arg2, arg3)
 }
 
+// GetAllWhitelistItems mocks base method.
+func (m *MockClient) GetAllWhitelistItems(arg0 context.Context, arg1, arg2 string, arg3 int) ([]*model.WhitelistItemForExport, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWhitelistItems", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*model.WhitelistItemForExport)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllWhitelistItems indicates an expected call of GetAllWhitelistItems.
+func (mr *MockClientMockRecorder) GetAllWhitelistItems(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWhitelistItems", reflect.TypeOf((*MockClient)(nil).GetAllWhitelistItems), arg0, arg1, arg2, arg3)
+}
+
+// GetBleveSnapshots mocks base method.
+func (m *MockClient) GetBleveSnapshots(arg0 context.Context) ([]*model.BleveSnapshot, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBleveSnapshots", arg0)
+	ret0, _ := ret[0].([]*model.BleveSnapshot)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBleveSnapshots indicates an expected call of GetBleveSnapshots.
+func (mr *MockClientMockRecorder) GetBleveSnapshots(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBleveSnapshots", reflect.TypeOf((*MockClient)(nil).GetBleveSnapshots), arg0)
+}
+
 // GetBots mocks base method.
 func (m *MockClient) GetBots(arg0 context.Context, arg1, arg2 int, arg3 string) ([]*model.Bot, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -957,6 +1083,22 @@ func (mr *MockClientMockRecorder) GetLdapGroups(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLdapGroups", reflect.TypeOf((*MockClient)(nil).GetLdapGroups), arg0)
 }
 
+// GetLoginBans mocks base method.
+func (m *MockClient) GetLoginBans(arg0 context.Context) ([]*model.LoginBan, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginBans", arg0)
+	ret0, _ := ret[0].([]*model.LoginBan)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLoginBans indicates an expected call of GetLoginBans.
+func (mr *MockClientMockRecorder) GetLoginBans(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginBans", reflect.TypeOf((*MockClient)(nil).GetLoginBans), arg0)
+}
+
 // GetLogs mocks base method.
 func (m *MockClient) GetLogs(arg0 context.Context, arg1, arg2 int) ([]string, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1437,6 +1579,22 @@ func (mr *MockClientMockRecorder) GetUserByUsername(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockClient)(nil).GetUserByUsername), arg0, arg1, arg2)
 }
 
+// GetUserWhitelist mocks base method.
+func (m *MockClient) GetUserWhitelist(arg0 context.Context, arg1 string) ([]*model.WhitelistItem, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWhitelist", arg0, arg1)
+	ret0, _ := ret[0].([]*model.WhitelistItem)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserWhitelist indicates an expected call of GetUserWhitelist.
+func (mr *MockClientMockRecorder) GetUserWhitelist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWhitelist", reflect.TypeOf((*MockClient)(nil).GetUserWhitelist), arg0, arg1)
+}
+
 // GetUsers mocks base method.
 func (m *MockClient) GetUsers(arg0 context.Context, arg1, arg2 int, arg3 string) ([]*model.User, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1501,6 +1659,70 @@ func (mr *MockClientMockRecorder) GetUsersWithCustomQueryParameters(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithCustomQueryParameters", reflect.TypeOf((*MockClient)(nil).GetUsersWithCustomQueryParameters), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GetWhitelistPolicies mocks base method.
+func (m *MockClient) GetWhitelistPolicies(arg0 context.Context, arg1, arg2 int) ([]*model.WhitelistPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWhitelistPolicies", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.WhitelistPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWhitelistPolicies indicates an expected call of GetWhitelistPolicies.
+func (mr *MockClientMockRecorder) GetWhitelistPolicies(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWhitelistPolicies", reflect.TypeOf((*MockClient)(nil).GetWhitelistPolicies), arg0, arg1, arg2)
+}
+
+// GetWhitelistPolicy mocks base method.
+func (m *MockClient) GetWhitelistPolicy(arg0 context.Context, arg1 string) (*model.WhitelistPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWhitelistPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*model.WhitelistPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWhitelistPolicy indicates an expected call of GetWhitelistPolicy.
+func (mr *MockClientMockRecorder) GetWhitelistPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWhitelistPolicy", reflect.TypeOf((*MockClient)(nil).GetWhitelistPolicy), arg0, arg1)
+}
+
+// GetWhitelistPolicyByName mocks base method.
+func (m *MockClient) GetWhitelistPolicyByName(arg0 context.Context, arg1 string) (*model.WhitelistPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWhitelistPolicyByName", arg0, arg1)
+	ret0, _ := ret[0].(*model.WhitelistPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWhitelistPolicyByName indicates an expected call of GetWhitelistPolicyByName.
+func (mr *MockClientMockRecorder) GetWhitelistPolicyByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWhitelistPolicyByName", reflect.TypeOf((*MockClient)(nil).GetWhitelistPolicyByName), arg0, arg1)
+}
+
+// GetWhitelistPolicyTargets mocks base method.
+func (m *MockClient) GetWhitelistPolicyTargets(arg0 context.Context, arg1 string) ([]*model.WhitelistPolicyTarget, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWhitelistPolicyTargets", arg0, arg1)
+	ret0, _ := ret[0].([]*model.WhitelistPolicyTarget)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWhitelistPolicyTargets indicates an expected call of GetWhitelistPolicyTargets.
+func (mr *MockClientMockRecorder) GetWhitelistPolicyTargets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWhitelistPolicyTargets", reflect.TypeOf((*MockClient)(nil).GetWhitelistPolicyTargets), arg0, arg1)
+}
+
 // InstallMarketplacePlugin mocks base method.
 func (m *MockClient) InstallMarketplacePlugin(arg0 context.Context, arg1 *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1767,6 +1989,22 @@ func (mr *MockClientMockRecorder) PatchTeam(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTeam", reflect.TypeOf((*MockClient)(nil).PatchTeam), arg0, arg1, arg2)
 }
 
+// PatchWhitelistPolicy mocks base method.
+func (m *MockClient) PatchWhitelistPolicy(arg0 context.Context, arg1 string, arg2 *model.WhitelistPolicyPatch) (*model.WhitelistPolicy, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchWhitelistPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.WhitelistPolicy)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchWhitelistPolicy indicates an expected call of PatchWhitelistPolicy.
+func (mr *MockClientMockRecorder) PatchWhitelistPolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchWhitelistPolicy", reflect.TypeOf((*MockClient)(nil).PatchWhitelistPolicy), arg0, arg1, arg2)
+}
+
 // PermanentDeleteAllUsers mocks base method.
 func (m *MockClient) PermanentDeleteAllUsers(arg0 context.Context) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1903,6 +2141,21 @@ func (mr *MockClientMockRecorder) RemoveLicenseFile(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLicenseFile", reflect.TypeOf((*MockClient)(nil).RemoveLicenseFile), arg0)
 }
 
+// RemoveLoginBan mocks base method.
+func (m *MockClient) RemoveLoginBan(arg0 context.Context, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLoginBan", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLoginBan indicates an expected call of RemoveLoginBan.
+func (mr *MockClientMockRecorder) RemoveLoginBan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLoginBan", reflect.TypeOf((*MockClient)(nil).RemoveLoginBan), arg0, arg1)
+}
+
 // RemovePlugin mocks base method.
 func (m *MockClient) RemovePlugin(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1948,6 +2201,36 @@ func (mr *MockClientMockRecorder) RemoveUserFromChannel(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromChannel", reflect.TypeOf((*MockClient)(nil).RemoveUserFromChannel), arg0, arg1, arg2)
 }
 
+// RemoveUserFromWhitelist mocks base method.
+func (m *MockClient) RemoveUserFromWhitelist(arg0 context.Context, arg1, arg2 string, arg3 bool) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserFromWhitelist", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUserFromWhitelist indicates an expected call of RemoveUserFromWhitelist.
+func (mr *MockClientMockRecorder) RemoveUserFromWhitelist(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromWhitelist", reflect.TypeOf((*MockClient)(nil).RemoveUserFromWhitelist), arg0, arg1, arg2, arg3)
+}
+
+// RemoveWhitelistPolicyTarget mocks base method.
+func (m *MockClient) RemoveWhitelistPolicyTarget(arg0 context.Context, arg1, arg2, arg3 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWhitelistPolicyTarget", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveWhitelistPolicyTarget indicates an expected call of RemoveWhitelistPolicyTarget.
+func (mr *MockClientMockRecorder) RemoveWhitelistPolicyTarget(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWhitelistPolicyTarget", reflect.TypeOf((*MockClient)(nil).RemoveWhitelistPolicyTarget), arg0, arg1, arg2, arg3)
+}
+
 // ResetSamlAuthDataToEmail mocks base method.
 func (m *MockClient) ResetSamlAuthDataToEmail(arg0 context.Context, arg1, arg2 bool, arg3 []string) (int64, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1964,6 +2247,22 @@ func (mr *MockClientMockRecorder) ResetSamlAuthDataToEmail(arg0, arg1, arg2, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSamlAuthDataToEmail", reflect.TypeOf((*MockClient)(nil).ResetSamlAuthDataToEmail), arg0, arg1, arg2, arg3)
 }
 
+// RestoreBleveSnapshot mocks base method.
+func (m *MockClient) RestoreBleveSnapshot(arg0 context.Context, arg1 string) (*model.BleveSnapshot, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBleveSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(*model.BleveSnapshot)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RestoreBleveSnapshot indicates an expected call of RestoreBleveSnapshot.
+func (mr *MockClientMockRecorder) RestoreBleveSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBleveSnapshot", reflect.TypeOf((*MockClient)(nil).RestoreBleveSnapshot), arg0, arg1)
+}
+
 // RestoreChannel mocks base method.
 func (m *MockClient) RestoreChannel(arg0 context.Context, arg1 string) (*model.Channel, *model.Response, error) {
 	m.ctrl.T.Helper()
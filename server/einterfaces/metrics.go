@@ -24,6 +24,7 @@ type MetricsInterface interface {
 
 	IncrementHTTPRequest()
 	IncrementHTTPError()
+	IncrementHTTPRateLimitError()
 
 	IncrementClusterRequest()
 	ObserveClusterRequestDuration(elapsed float64)
@@ -67,6 +68,8 @@ type MetricsInterface interface {
 	IncrementFileIndexCounter()
 	IncrementUserIndexCounter()
 	IncrementChannelIndexCounter()
+	ObserveFileExtractionDuration(extractor string, success bool, elapsed float64)
+	IncrementFileExtractionFailureCounter(extractor, reason string)
 
 	ObservePluginHookDuration(pluginID, hookName string, success bool, elapsed float64)
 	ObservePluginMultiHookIterationDuration(pluginID string, elapsed float64)
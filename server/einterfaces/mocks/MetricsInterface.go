@@ -108,6 +108,11 @@ func (_m *MetricsInterface) IncrementEtagMissCounter(route string) {
 	_m.Called(route)
 }
 
+// IncrementFileExtractionFailureCounter provides a mock function with given fields: extractor, reason
+func (_m *MetricsInterface) IncrementFileExtractionFailureCounter(extractor string, reason string) {
+	_m.Called(extractor, reason)
+}
+
 // IncrementFileIndexCounter provides a mock function with no fields
 func (_m *MetricsInterface) IncrementFileIndexCounter() {
 	_m.Called()
@@ -123,6 +128,11 @@ func (_m *MetricsInterface) IncrementHTTPError() {
 	_m.Called()
 }
 
+// IncrementHTTPRateLimitError provides a mock function with no fields
+func (_m *MetricsInterface) IncrementHTTPRateLimitError() {
+	_m.Called()
+}
+
 // IncrementHTTPRequest provides a mock function with no fields
 func (_m *MetricsInterface) IncrementHTTPRequest() {
 	_m.Called()
@@ -403,6 +413,11 @@ func (_m *MetricsInterface) ObserveEnabledUsers(users int64) {
 	_m.Called(users)
 }
 
+// ObserveFileExtractionDuration provides a mock function with given fields: extractor, success, elapsed
+func (_m *MetricsInterface) ObserveFileExtractionDuration(extractor string, success bool, elapsed float64) {
+	_m.Called(extractor, success, elapsed)
+}
+
 // ObserveFilesSearchDuration provides a mock function with given fields: elapsed
 func (_m *MetricsInterface) ObserveFilesSearchDuration(elapsed float64) {
 	_m.Called(elapsed)
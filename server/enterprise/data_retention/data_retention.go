@@ -0,0 +1,241 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package data_retention
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+	ejobs "github.com/mattermost/mattermost/server/v8/einterfaces/jobs"
+)
+
+type DataRetentionInterfaceImpl struct {
+	Server *app.Server
+}
+
+type DataRetentionJobInterfaceImpl struct {
+	Server *app.Server
+}
+
+func init() {
+	app.RegisterJobsDataRetentionJobInterface(func(s *app.Server) ejobs.DataRetentionJobInterface {
+		return &DataRetentionJobInterfaceImpl{s}
+	})
+	app.RegisterDataRetentionInterface(func(app *app.App) einterfaces.DataRetentionInterface {
+		return &DataRetentionInterfaceImpl{app.Srv()}
+	})
+}
+
+func (dr *DataRetentionInterfaceImpl) GetGlobalPolicy() (*model.GlobalRetentionPolicy, *model.AppError) {
+	settings := dr.Server.Config().DataRetentionSettings
+	now := model.GetMillis()
+
+	policy := &model.GlobalRetentionPolicy{
+		MessageDeletionEnabled: *settings.EnableMessageDeletion,
+		FileDeletionEnabled:    *settings.EnableFileDeletion,
+	}
+	if policy.MessageDeletionEnabled {
+		policy.MessageRetentionCutoff = now - (time.Duration(settings.GetMessageRetentionHours()) * time.Hour).Milliseconds()
+	}
+	if policy.FileDeletionEnabled {
+		policy.FileRetentionCutoff = now - (time.Duration(settings.GetFileRetentionHours()) * time.Hour).Milliseconds()
+	}
+	return policy, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetPolicies(offset, limit int) (*model.RetentionPolicyWithTeamAndChannelCountsList, *model.AppError) {
+	policies, err := dr.Server.Store().RetentionPolicy().GetAll(offset, limit)
+	if err != nil {
+		return nil, newInternalError("GetPolicies", err)
+	}
+	count, appErr := dr.GetPoliciesCount()
+	if appErr != nil {
+		return nil, appErr
+	}
+	return &model.RetentionPolicyWithTeamAndChannelCountsList{Policies: policies, TotalCount: count}, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetPoliciesCount() (int64, *model.AppError) {
+	count, err := dr.Server.Store().RetentionPolicy().GetCount()
+	if err != nil {
+		return 0, newInternalError("GetPoliciesCount", err)
+	}
+	return count, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetPolicy(policyID string) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	policy, err := dr.Server.Store().RetentionPolicy().Get(policyID)
+	if err != nil {
+		return nil, newPolicyError("GetPolicy", err)
+	}
+	return policy, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) CreatePolicy(policy *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	if policy.DisplayName == "" || policy.PostDurationDays == nil || !isValidPostDuration(*policy.PostDurationDays) {
+		return nil, newInvalidPolicyError("CreatePolicy", nil)
+	}
+
+	saved, err := dr.Server.Store().RetentionPolicy().Save(policy)
+	if err != nil {
+		return nil, newPolicyError("CreatePolicy", err)
+	}
+	return saved, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) PatchPolicy(patch *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	if patch.PostDurationDays != nil && !isValidPostDuration(*patch.PostDurationDays) {
+		return nil, newInvalidPolicyError("PatchPolicy", nil)
+	}
+
+	// The policy is read first, so that a missing policy isn't taken for missing teams or channels
+	if _, appErr := dr.GetPolicy(patch.ID); appErr != nil {
+		return nil, appErr
+	}
+
+	patched, err := dr.Server.Store().RetentionPolicy().Patch(patch)
+	if err != nil {
+		return nil, newPolicyError("PatchPolicy", err)
+	}
+	return patched, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) DeletePolicy(policyID string) *model.AppError {
+	if _, appErr := dr.GetPolicy(policyID); appErr != nil {
+		return appErr
+	}
+
+	if err := dr.Server.Store().RetentionPolicy().Delete(policyID); err != nil {
+		return newInternalError("DeletePolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetTeamsForPolicy(policyID string, offset, limit int) (*model.TeamsWithCount, *model.AppError) {
+	teams, err := dr.Server.Store().RetentionPolicy().GetTeams(policyID, offset, limit)
+	if err != nil {
+		return nil, newInternalError("GetTeamsForPolicy", err)
+	}
+	count, err := dr.Server.Store().RetentionPolicy().GetTeamsCount(policyID)
+	if err != nil {
+		return nil, newInternalError("GetTeamsForPolicy", err)
+	}
+	return &model.TeamsWithCount{Teams: teams, TotalCount: count}, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) AddTeamsToPolicy(policyID string, teamIDs []string) *model.AppError {
+	if _, appErr := dr.GetPolicy(policyID); appErr != nil {
+		return appErr
+	}
+
+	if err := dr.Server.Store().RetentionPolicy().AddTeams(policyID, teamIDs); err != nil {
+		return newPolicyError("AddTeamsToPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetentionInterfaceImpl) RemoveTeamsFromPolicy(policyID string, teamIDs []string) *model.AppError {
+	if _, appErr := dr.GetPolicy(policyID); appErr != nil {
+		return appErr
+	}
+
+	if err := dr.Server.Store().RetentionPolicy().RemoveTeams(policyID, teamIDs); err != nil {
+		return newPolicyError("RemoveTeamsFromPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetChannelsForPolicy(policyID string, offset, limit int) (*model.ChannelsWithCount, *model.AppError) {
+	channels, err := dr.Server.Store().RetentionPolicy().GetChannels(policyID, offset, limit)
+	if err != nil {
+		return nil, newInternalError("GetChannelsForPolicy", err)
+	}
+	count, err := dr.Server.Store().RetentionPolicy().GetChannelsCount(policyID)
+	if err != nil {
+		return nil, newInternalError("GetChannelsForPolicy", err)
+	}
+	return &model.ChannelsWithCount{Channels: channels, TotalCount: count}, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) AddChannelsToPolicy(policyID string, channelIDs []string) *model.AppError {
+	if _, appErr := dr.GetPolicy(policyID); appErr != nil {
+		return appErr
+	}
+
+	if err := dr.Server.Store().RetentionPolicy().AddChannels(policyID, channelIDs); err != nil {
+		return newPolicyError("AddChannelsToPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetentionInterfaceImpl) RemoveChannelsFromPolicy(policyID string, channelIDs []string) *model.AppError {
+	if _, appErr := dr.GetPolicy(policyID); appErr != nil {
+		return appErr
+	}
+
+	if err := dr.Server.Store().RetentionPolicy().RemoveChannels(policyID, channelIDs); err != nil {
+		return newPolicyError("RemoveChannelsFromPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetTeamPoliciesForUser(userID string, offset, limit int) (*model.RetentionPolicyForTeamList, *model.AppError) {
+	policies, err := dr.Server.Store().RetentionPolicy().GetTeamPoliciesForUser(userID, offset, limit)
+	if err != nil {
+		return nil, newInternalError("GetTeamPoliciesForUser", err)
+	}
+	count, err := dr.Server.Store().RetentionPolicy().GetTeamPoliciesCountForUser(userID)
+	if err != nil {
+		return nil, newInternalError("GetTeamPoliciesForUser", err)
+	}
+	return &model.RetentionPolicyForTeamList{Policies: policies, TotalCount: count}, nil
+}
+
+func (dr *DataRetentionInterfaceImpl) GetChannelPoliciesForUser(userID string, offset, limit int) (*model.RetentionPolicyForChannelList, *model.AppError) {
+	policies, err := dr.Server.Store().RetentionPolicy().GetChannelPoliciesForUser(userID, offset, limit)
+	if err != nil {
+		return nil, newInternalError("GetChannelPoliciesForUser", err)
+	}
+	count, err := dr.Server.Store().RetentionPolicy().GetChannelPoliciesCountForUser(userID)
+	if err != nil {
+		return nil, newInternalError("GetChannelPoliciesForUser", err)
+	}
+	return &model.RetentionPolicyForChannelList{Policies: policies, TotalCount: count}, nil
+}
+
+// isValidPostDuration tells whether a policy keeps the posts for at least a day, or forever with -1.
+func isValidPostDuration(days int64) bool {
+	return days == -1 || days > 0
+}
+
+// newPolicyError maps the errors of the store about a missing policy, or about missing teams
+// or channels given to a policy.
+func newPolicyError(where string, err error) *model.AppError {
+	var nfErr *store.ErrNotFound
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return model.NewAppError("DataRetention."+where, "ent.data_retention.policies.not_found", nil, "", http.StatusNotFound).Wrap(err)
+	case errors.As(err, &nfErr):
+		return newInvalidPolicyError(where, err)
+	}
+	return newInternalError(where, err)
+}
+
+func newInvalidPolicyError(where string, err error) *model.AppError {
+	appErr := model.NewAppError("DataRetention."+where, "ent.data_retention.policies.invalid_policy", nil, "", http.StatusBadRequest)
+	if err != nil {
+		appErr.Wrap(err)
+	}
+	return appErr
+}
+
+func newInternalError(where string, err error) *model.AppError {
+	return model.NewAppError("DataRetention."+where, "ent.data_retention.policies.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+}
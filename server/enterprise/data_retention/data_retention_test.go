@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package data_retention
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/api4"
+)
+
+func TestGetGlobalPolicy(t *testing.T) {
+	th := api4.Setup(t)
+	defer th.TearDown()
+
+	dataRetention := &DataRetentionInterfaceImpl{th.App.Srv()}
+
+	t.Run("nothing is deleted by default", func(t *testing.T) {
+		policy, appErr := dataRetention.GetGlobalPolicy()
+		require.Nil(t, appErr)
+		assert.False(t, policy.MessageDeletionEnabled)
+		assert.False(t, policy.FileDeletionEnabled)
+		assert.Zero(t, policy.MessageRetentionCutoff)
+		assert.Zero(t, policy.FileRetentionCutoff)
+	})
+
+	t.Run("gives the cutoffs of the enabled deletions", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.DataRetentionSettings.EnableMessageDeletion = true
+			*cfg.DataRetentionSettings.MessageRetentionHours = 48
+		})
+
+		before := model.GetMillis()
+		policy, appErr := dataRetention.GetGlobalPolicy()
+		require.Nil(t, appErr)
+		assert.True(t, policy.MessageDeletionEnabled)
+		assert.False(t, policy.FileDeletionEnabled)
+		assert.GreaterOrEqual(t, policy.MessageRetentionCutoff, before-48*60*60*1000)
+		assert.LessOrEqual(t, policy.MessageRetentionCutoff, model.GetMillis()-48*60*60*1000)
+		assert.Zero(t, policy.FileRetentionCutoff)
+	})
+}
+
+func TestPolicies(t *testing.T) {
+	th := api4.Setup(t).InitBasic()
+	defer th.TearDown()
+
+	dataRetention := &DataRetentionInterfaceImpl{th.App.Srv()}
+
+	policy, appErr := dataRetention.CreatePolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+		RetentionPolicy: model.RetentionPolicy{
+			DisplayName:      "Policy",
+			PostDurationDays: model.NewPointer(int64(30)),
+		},
+		TeamIDs:    []string{th.BasicTeam.Id},
+		ChannelIDs: []string{th.BasicChannel.Id},
+	})
+	require.Nil(t, appErr)
+	assert.Equal(t, int64(1), policy.TeamCount)
+	assert.Equal(t, int64(1), policy.ChannelCount)
+
+	t.Run("rejects the invalid policies", func(t *testing.T) {
+		for name, invalid := range map[string]*model.RetentionPolicyWithTeamAndChannelIDs{
+			"no display name": {RetentionPolicy: model.RetentionPolicy{PostDurationDays: model.NewPointer(int64(30))}},
+			"no duration":     {RetentionPolicy: model.RetentionPolicy{DisplayName: "Policy"}},
+			"zero duration":   {RetentionPolicy: model.RetentionPolicy{DisplayName: "Policy", PostDurationDays: model.NewPointer(int64(0))}},
+			"missing team":    {RetentionPolicy: model.RetentionPolicy{DisplayName: "Policy", PostDurationDays: model.NewPointer(int64(30))}, TeamIDs: []string{model.NewId()}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, appErr := dataRetention.CreatePolicy(invalid)
+				require.NotNil(t, appErr)
+				assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			})
+		}
+	})
+
+	t.Run("lists the policies", func(t *testing.T) {
+		policies, appErr := dataRetention.GetPolicies(0, 10)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), policies.TotalCount)
+		require.Len(t, policies.Policies, 1)
+		assert.Equal(t, policy.ID, policies.Policies[0].ID)
+
+		teams, appErr := dataRetention.GetTeamsForPolicy(policy.ID, 0, 10)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), teams.TotalCount)
+
+		channelPolicies, appErr := dataRetention.GetChannelPoliciesForUser(th.BasicUser.Id, 0, 10)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), channelPolicies.TotalCount)
+		assert.Equal(t, th.BasicChannel.Id, channelPolicies.Policies[0].ChannelID)
+	})
+
+	t.Run("patches a policy", func(t *testing.T) {
+		patched, appErr := dataRetention.PatchPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+			RetentionPolicy: model.RetentionPolicy{ID: policy.ID, PostDurationDays: model.NewPointer(int64(-1))},
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(-1), *patched.PostDurationDays)
+
+		_, appErr = dataRetention.PatchPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+			RetentionPolicy: model.RetentionPolicy{ID: model.NewId(), DisplayName: "Other"},
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("changes the channels of a policy", func(t *testing.T) {
+		appErr := dataRetention.RemoveChannelsFromPolicy(policy.ID, []string{th.BasicChannel.Id})
+		require.Nil(t, appErr)
+
+		channels, appErr := dataRetention.GetChannelsForPolicy(policy.ID, 0, 10)
+		require.Nil(t, appErr)
+		assert.Zero(t, channels.TotalCount)
+
+		appErr = dataRetention.AddChannelsToPolicy(model.NewId(), []string{th.BasicChannel.Id})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("deletes a policy", func(t *testing.T) {
+		require.Nil(t, dataRetention.DeletePolicy(policy.ID))
+
+		_, appErr := dataRetention.GetPolicy(policy.ID)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		appErr = dataRetention.DeletePolicy(policy.ID)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
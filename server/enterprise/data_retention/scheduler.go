@@ -0,0 +1,43 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package data_retention
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	ejobs "github.com/mattermost/mattermost/server/v8/einterfaces/jobs"
+)
+
+// MakeScheduler schedules the deletion job daily at the DeletionJobStartTime, as long as the
+// global policy deletes messages or files, or there are team or channel policies.
+func (dr *DataRetentionJobInterfaceImpl) MakeScheduler() ejobs.Scheduler {
+	startTime := func(cfg *model.Config) *time.Time {
+		parsedTime, err := time.Parse("15:04", *cfg.DataRetentionSettings.DeletionJobStartTime)
+		if err != nil {
+			dr.Server.Jobs.Logger().Error(
+				"Cannot determine next schedule time for data retention. DeletionJobStartTime config value is invalid.",
+				mlog.String("deletion_job_start_time", *cfg.DataRetentionSettings.DeletionJobStartTime),
+			)
+			return nil
+		}
+		return &parsedTime
+	}
+	return jobs.NewDailyScheduler(dr.Server.Jobs, model.JobTypeDataRetention, startTime, dr.isEnabled)
+}
+
+func (dr *DataRetentionJobInterfaceImpl) isEnabled(cfg *model.Config) bool {
+	if *cfg.DataRetentionSettings.EnableMessageDeletion || *cfg.DataRetentionSettings.EnableFileDeletion {
+		return true
+	}
+
+	count, err := dr.Server.Store().RetentionPolicy().GetCount()
+	if err != nil {
+		dr.Server.Jobs.Logger().Warn("Failed to count the data retention policies", mlog.Err(err))
+		return false
+	}
+	return count > 0
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package data_retention
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const jobName = "DataRetention"
+
+// Keys of the job data with the number of rows deleted by a run
+const (
+	JobDataDeletedPosts                = "deleted_posts"
+	JobDataDeletedReactions            = "deleted_reactions"
+	JobDataDeletedFiles                = "deleted_files"
+	JobDataDeletedChannelMemberHistory = "deleted_channel_member_history"
+	JobDataDeletedThreads              = "deleted_threads"
+	JobDataDeletedThreadMemberships    = "deleted_thread_memberships"
+	JobDataDeletedOrphanedRows         = "deleted_orphaned_rows"
+)
+
+// retentionIdsTablePosts is the table name of the ids of the posts deleted by the policies,
+// kept until their reactions, files and search index entries are deleted too.
+const retentionIdsTablePosts = "Posts"
+
+// MakeWorker makes the worker deleting the posts, reactions, files and search index entries
+// older than the global policy, or than the team and channel policies overriding it.
+func (dr *DataRetentionJobInterfaceImpl) MakeWorker() model.Worker {
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer dr.Server.Jobs.HandleJobPanic(logger, job)
+
+		run := newDeletionRun(app.New(app.ServerConnector(dr.Server.Channels())), logger, job)
+		return run.execute()
+	}
+	return jobs.NewSimpleWorker(jobName, dr.Server.Jobs, execute, dr.isEnabled)
+}
+
+type deletionRun struct {
+	app    *app.App
+	rctx   request.CTX
+	logger mlog.LoggerIFace
+	job    *model.Job
+
+	batchConfigs       model.RetentionPolicyBatchConfigs
+	filesEndTime       int64
+	idsBatchSize       int
+	timeBetweenBatches time.Duration
+
+	deleted map[string]int64
+}
+
+func newDeletionRun(a *app.App, logger mlog.LoggerIFace, job *model.Job) *deletionRun {
+	settings := a.Config().DataRetentionSettings
+	now := model.GetMillis()
+
+	run := &deletionRun{
+		app:    a,
+		rctx:   request.EmptyContext(logger),
+		logger: logger,
+		job:    job,
+		batchConfigs: model.RetentionPolicyBatchConfigs{
+			// The team and channel policies are applied from now on, the global one from its cutoff
+			Now:                 now,
+			Limit:               int64(*settings.BatchSize),
+			PreservePinnedPosts: *settings.PreservePinnedPosts,
+		},
+		idsBatchSize:       *settings.RetentionIdsBatchSize,
+		timeBetweenBatches: time.Duration(*settings.TimeBetweenBatchesMilliseconds) * time.Millisecond,
+		deleted:            map[string]int64{},
+	}
+	if *settings.EnableMessageDeletion {
+		run.batchConfigs.GlobalPolicyEndTime = now - (time.Duration(settings.GetMessageRetentionHours()) * time.Hour).Milliseconds()
+	}
+	if *settings.EnableFileDeletion {
+		run.filesEndTime = now - (time.Duration(settings.GetFileRetentionHours()) * time.Hour).Milliseconds()
+	}
+	return run
+}
+
+func (r *deletionRun) execute() error {
+	r.logger.Info("Starting the data retention job",
+		mlog.Int("message_end_time", r.batchConfigs.GlobalPolicyEndTime),
+		mlog.Int("file_end_time", r.filesEndTime),
+	)
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{JobDataDeletedPosts, r.deletePosts},
+		{JobDataDeletedChannelMemberHistory, r.deleteChannelMemberHistory},
+		{JobDataDeletedThreads, r.deleteThreads},
+		{JobDataDeletedThreadMemberships, r.deleteThreadMemberships},
+		{JobDataDeletedFiles, r.deleteFiles},
+		{JobDataDeletedOrphanedRows, r.deleteOrphanedRows},
+	}
+	for i, step := range steps {
+		if err := step.run(); err != nil {
+			r.saveJobData()
+			return model.NewAppError("DataRetentionWorker", "ent.data_retention.run_failed.error", nil, "step="+step.name, http.StatusInternalServerError).Wrap(err)
+		}
+		r.saveJobData()
+		if appErr := r.app.Srv().Jobs.SetJobProgress(r.job, int64((i+1)*99/len(steps))); appErr != nil {
+			r.logger.Warn("Failed to set the job progress", mlog.Err(appErr))
+		}
+	}
+
+	r.purgeElasticsearchIndexes()
+
+	r.logger.Info("Data retention job finished",
+		mlog.Int(JobDataDeletedPosts, r.deleted[JobDataDeletedPosts]),
+		mlog.Int(JobDataDeletedReactions, r.deleted[JobDataDeletedReactions]),
+		mlog.Int(JobDataDeletedFiles, r.deleted[JobDataDeletedFiles]),
+	)
+	return nil
+}
+
+func (r *deletionRun) saveJobData() {
+	if r.job.Data == nil {
+		r.job.Data = make(model.StringMap)
+	}
+	for key, deleted := range r.deleted {
+		r.job.Data[key] = strconv.FormatInt(deleted, 10)
+	}
+}
+
+// deleteByPolicies calls a batch deletion of the store until all the policies are done,
+// calling afterBatch after each batch.
+func (r *deletionRun) deleteByPolicies(key string, deleteBatch func(model.RetentionPolicyBatchConfigs, model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error), afterBatch func() error) error {
+	var cursor model.RetentionPolicyCursor
+	for {
+		deleted, nextCursor, err := deleteBatch(r.batchConfigs, cursor)
+		if err != nil {
+			return err
+		}
+		cursor = nextCursor
+		r.deleted[key] += deleted
+
+		if afterBatch != nil {
+			if err := afterBatch(); err != nil {
+				return err
+			}
+		}
+
+		if cursor.ChannelPoliciesDone && cursor.TeamPoliciesDone && cursor.GlobalPoliciesDone {
+			return nil
+		}
+		time.Sleep(r.timeBetweenBatches)
+	}
+}
+
+func (r *deletionRun) deletePosts() error {
+	// The posts left by an interrupted run are cleaned up first
+	if err := r.cleanUpDeletedPosts(); err != nil {
+		return err
+	}
+	return r.deleteByPolicies(JobDataDeletedPosts, r.app.Srv().Store().Post().PermanentDeleteBatchForRetentionPolicies, r.cleanUpDeletedPosts)
+}
+
+// cleanUpDeletedPosts deletes the reactions, the files and the search index entries of the
+// posts deleted by the policies.
+func (r *deletionRun) cleanUpDeletedPosts() error {
+	for {
+		rows, err := r.app.Srv().Store().RetentionPolicy().GetIdsForDeletionByTableName(retentionIdsTablePosts, r.idsBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			for _, postID := range row.Ids {
+				if err := r.deletePostFiles(postID); err != nil {
+					return err
+				}
+				r.purgePost(postID)
+			}
+
+			// This deletes the row of ids as well
+			deleted, err := r.app.Srv().Store().Reaction().DeleteOrphanedRowsByIds(row)
+			if err != nil {
+				return err
+			}
+			r.deleted[JobDataDeletedReactions] += deleted
+		}
+	}
+}
+
+func (r *deletionRun) deletePostFiles(postID string) error {
+	fileInfos, err := r.app.Srv().Store().FileInfo().GetForPost(postID, true, true, false)
+	if err != nil {
+		return err
+	}
+	if len(fileInfos) == 0 {
+		return nil
+	}
+
+	r.app.RemoveFilesFromFileStore(r.rctx, fileInfos)
+	if err := r.app.Srv().Store().FileInfo().PermanentDeleteForPost(r.rctx, postID); err != nil {
+		return err
+	}
+	r.app.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(postID, true)
+	r.app.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(postID, false)
+
+	for _, fileInfo := range fileInfos {
+		r.purgeFile(fileInfo.Id)
+	}
+	r.deleted[JobDataDeletedFiles] += int64(len(fileInfos))
+	return nil
+}
+
+func (r *deletionRun) deleteChannelMemberHistory() error {
+	return r.deleteByPolicies(JobDataDeletedChannelMemberHistory, r.app.Srv().Store().ChannelMemberHistory().PermanentDeleteBatchForRetentionPolicies, nil)
+}
+
+func (r *deletionRun) deleteThreads() error {
+	return r.deleteByPolicies(JobDataDeletedThreads, r.app.Srv().Store().Thread().PermanentDeleteBatchForRetentionPolicies, nil)
+}
+
+func (r *deletionRun) deleteThreadMemberships() error {
+	return r.deleteByPolicies(JobDataDeletedThreadMemberships, r.app.Srv().Store().Thread().PermanentDeleteBatchThreadMembershipsForRetentionPolicies, nil)
+}
+
+// deleteFiles deletes the files older than the global policy, with the posts they are attached
+// to or not. The team and channel policies only apply to the files of the posts they delete.
+func (r *deletionRun) deleteFiles() error {
+	if r.filesEndTime <= 0 {
+		return nil
+	}
+
+	var startTime int64
+	var startFileID string
+	limit := int(r.batchConfigs.Limit)
+	for {
+		files, err := r.app.Srv().Store().FileInfo().GetFilesBatchForIndexing(startTime, startFileID, true, limit)
+		if err != nil {
+			return err
+		}
+
+		done := len(files) < limit
+		fileInfos := make([]*model.FileInfo, 0, len(files))
+		for _, file := range files {
+			if file.CreateAt >= r.filesEndTime {
+				done = true
+				break
+			}
+			startTime, startFileID = file.CreateAt, file.Id
+
+			// The files of the channel bookmarks stay as long as the bookmarks
+			if file.CreatorId == model.BookmarkFileOwner {
+				continue
+			}
+			fileInfos = append(fileInfos, &file.FileInfo)
+		}
+
+		r.app.RemoveFilesFromFileStore(r.rctx, fileInfos)
+		for _, fileInfo := range fileInfos {
+			if err := r.app.Srv().Store().FileInfo().PermanentDelete(r.rctx, fileInfo.Id); err != nil {
+				return err
+			}
+			if fileInfo.PostId != "" {
+				r.app.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(fileInfo.PostId, true)
+				r.app.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(fileInfo.PostId, false)
+			}
+			r.purgeFile(fileInfo.Id)
+		}
+		r.deleted[JobDataDeletedFiles] += int64(len(fileInfos))
+
+		if done {
+			return nil
+		}
+		time.Sleep(r.timeBetweenBatches)
+	}
+}
+
+// deleteOrphanedRows deletes the rows left over by the deleted posts, channels and teams.
+func (r *deletionRun) deleteOrphanedRows() error {
+	stores := []func(limit int) (int64, error){
+		r.app.Srv().Store().Preference().DeleteOrphanedRows,
+		r.app.Srv().Store().Thread().DeleteOrphanedRows,
+		r.app.Srv().Store().ChannelMemberHistory().DeleteOrphanedRows,
+		r.app.Srv().Store().RetentionPolicy().DeleteOrphanedRows,
+	}
+	limit := int(r.batchConfigs.Limit)
+	for _, deleteOrphanedRows := range stores {
+		for {
+			deleted, err := deleteOrphanedRows(limit)
+			if err != nil {
+				return err
+			}
+			r.deleted[JobDataDeletedOrphanedRows] += deleted
+			if deleted < int64(limit) {
+				break
+			}
+			time.Sleep(r.timeBetweenBatches)
+		}
+	}
+	return nil
+}
+
+// purgePost deletes a post from the search indexes keyed by post id. The Elasticsearch posts
+// are spread over indexes by date, which are dropped with the global policy instead.
+func (r *deletionRun) purgePost(postID string) {
+	broker := r.app.SearchEngine()
+	for _, engine := range broker.GetActiveEngines() {
+		if engine == broker.ElasticsearchEngine {
+			continue
+		}
+		if appErr := engine.DeletePost(&model.Post{Id: postID}); appErr != nil {
+			r.logger.Warn("Failed to delete a post from the search index", mlog.String("engine", engine.GetName()), mlog.String("post_id", postID), mlog.Err(appErr))
+		}
+	}
+}
+
+func (r *deletionRun) purgeFile(fileID string) {
+	for _, engine := range r.app.SearchEngine().GetActiveEngines() {
+		if appErr := engine.DeleteFile(fileID); appErr != nil {
+			r.logger.Warn("Failed to delete a file from the search index", mlog.String("engine", engine.GetName()), mlog.String("file_id", fileID), mlog.Err(appErr))
+		}
+	}
+}
+
+// purgeElasticsearchIndexes drops the Elasticsearch post indexes older than the global policy.
+func (r *deletionRun) purgeElasticsearchIndexes() {
+	engine := r.app.SearchEngine().ElasticsearchEngine
+	if r.batchConfigs.GlobalPolicyEndTime <= 0 || engine == nil || !engine.IsActive() {
+		return
+	}
+
+	if appErr := engine.DataRetentionDeleteIndexes(r.rctx, time.UnixMilli(r.batchConfigs.GlobalPolicyEndTime)); appErr != nil {
+		r.logger.Warn("Failed to delete the Elasticsearch indexes older than the data retention policy", mlog.Err(appErr))
+	}
+}
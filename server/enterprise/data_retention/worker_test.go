@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package data_retention
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/api4"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestDeletionRun(t *testing.T) {
+	th := api4.Setup(t).InitBasic()
+	defer th.TearDown()
+
+	ss := th.App.Srv().Store()
+	now := model.GetMillis()
+	daysAgo := func(days int) int64 {
+		return now - (time.Duration(days) * 24 * time.Hour).Milliseconds()
+	}
+
+	savePost := func(channelID string, createAt int64) *model.Post {
+		post, err := ss.Post().Save(th.Context, &model.Post{
+			ChannelId: channelID,
+			UserId:    th.BasicUser.Id,
+			Message:   "message",
+			CreateAt:  createAt,
+		})
+		require.NoError(t, err)
+		return post
+	}
+	saveFile := func(postID string, createAt int64) *model.FileInfo {
+		path := "data_retention/" + model.NewId() + "/file.txt"
+		_, appErr := th.App.WriteFile(bytes.NewReader([]byte("content")), path)
+		require.Nil(t, appErr)
+
+		fileInfo, err := ss.FileInfo().Save(th.Context, &model.FileInfo{
+			CreatorId: th.BasicUser.Id,
+			PostId:    postID,
+			ChannelId: th.BasicChannel.Id,
+			Name:      "file.txt",
+			Path:      path,
+			CreateAt:  createAt,
+		})
+		require.NoError(t, err)
+		return fileInfo
+	}
+	runJob := func() *model.Job {
+		job := &model.Job{Id: model.NewId(), Type: model.JobTypeDataRetention}
+		require.NoError(t, newDeletionRun(th.App, th.TestLogger, job).execute())
+		return job
+	}
+
+	t.Run("applies the channel policies", func(t *testing.T) {
+		_, appErr := th.App.CreateRetentionPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+			RetentionPolicy: model.RetentionPolicy{
+				DisplayName:      "Channel policy",
+				PostDurationDays: model.NewPointer(int64(7)),
+			},
+			ChannelIDs: []string{th.BasicChannel.Id},
+		})
+		require.Nil(t, appErr)
+
+		oldPost := savePost(th.BasicChannel.Id, daysAgo(10))
+		_, err := ss.Reaction().Save(&model.Reaction{UserId: th.BasicUser.Id, PostId: oldPost.Id, EmojiName: "smile"})
+		require.NoError(t, err)
+		oldFile := saveFile(oldPost.Id, daysAgo(10))
+		recentPost := savePost(th.BasicChannel.Id, daysAgo(1))
+		otherChannelPost := savePost(th.BasicChannel2.Id, daysAgo(10))
+
+		job := runJob()
+		assert.Equal(t, "1", job.Data[JobDataDeletedPosts])
+		assert.Equal(t, "1", job.Data[JobDataDeletedReactions])
+		assert.Equal(t, "1", job.Data[JobDataDeletedFiles])
+
+		_, err = ss.Post().GetSingle(th.Context, oldPost.Id, true)
+		var nfErr *store.ErrNotFound
+		assert.ErrorAs(t, err, &nfErr)
+		reactions, err := ss.Reaction().GetForPost(oldPost.Id, false)
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+		_, err = ss.FileInfo().Get(oldFile.Id)
+		assert.ErrorAs(t, err, &nfErr)
+		exists, appErr := th.App.FileExists(oldFile.Path)
+		require.Nil(t, appErr)
+		assert.False(t, exists)
+
+		_, err = ss.Post().GetSingle(th.Context, recentPost.Id, true)
+		assert.NoError(t, err)
+		_, err = ss.Post().GetSingle(th.Context, otherChannelPost.Id, true)
+		assert.NoError(t, err)
+
+		rows, err := ss.RetentionPolicy().GetIdsForDeletionByTableName(retentionIdsTablePosts, 10)
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+
+	t.Run("applies the global policy to the files", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.DataRetentionSettings.EnableFileDeletion = true
+			*cfg.DataRetentionSettings.FileRetentionHours = 24 * 30
+		})
+		defer th.App.UpdateConfig(func(cfg *model.Config) {
+			*cfg.DataRetentionSettings.EnableFileDeletion = false
+		})
+
+		oldFile := saveFile("", daysAgo(40))
+		recentFile := saveFile("", daysAgo(20))
+
+		job := runJob()
+		assert.Equal(t, "1", job.Data[JobDataDeletedFiles])
+
+		var nfErr *store.ErrNotFound
+		_, err := ss.FileInfo().Get(oldFile.Id)
+		assert.ErrorAs(t, err, &nfErr)
+		_, err = ss.FileInfo().Get(recentFile.Id)
+		assert.NoError(t, err)
+	})
+}
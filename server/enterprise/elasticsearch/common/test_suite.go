@@ -772,7 +772,7 @@ func (c *CommonTestSuite) TestSearchFiles() {
 			},
 		}
 
-		fileIds, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
+		fileIds, _, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
 		c.Nil(err)
 		c.Contains(fileIds, file1.Id)
 		c.NotContains(fileIds, file2.Id)
@@ -790,7 +790,7 @@ func (c *CommonTestSuite) TestSearchFiles() {
 			},
 		}
 
-		fileIds, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
+		fileIds, _, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
 		c.Nil(err)
 		c.NotContains(fileIds, file1.Id)
 		c.Contains(fileIds, file2.Id)
@@ -807,7 +807,7 @@ func (c *CommonTestSuite) TestSearchFiles() {
 			},
 		}
 
-		fileIds, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
+		fileIds, _, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
 		c.Nil(err)
 		c.Contains(fileIds, file1.Id)
 		c.NotContains(fileIds, file2.Id)
@@ -825,7 +825,7 @@ func (c *CommonTestSuite) TestSearchFiles() {
 			},
 		}
 
-		fileIds, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
+		fileIds, _, err := c.ESImpl.SearchFiles(channels, searchParams, 0, 10)
 		c.Nil(err)
 		c.Contains(fileIds, file1.Id)
 		c.NotContains(fileIds, file2.Id)
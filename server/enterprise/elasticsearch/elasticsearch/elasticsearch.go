@@ -1455,12 +1455,12 @@ func (es *ElasticsearchInterfaceImpl) IndexFile(file *model.FileInfo, channelId
 	return nil
 }
 
-func (es *ElasticsearchInterfaceImpl) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+func (es *ElasticsearchInterfaceImpl) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError) {
 	es.mutex.RLock()
 	defer es.mutex.RUnlock()
 
 	if atomic.LoadInt32(&es.ready) == 0 {
-		return []string{}, model.NewAppError("Elasticsearch.SearchPosts", "ent.elasticsearch.search_files.disabled", map[string]any{"Backend": model.ElasticsearchSettingsESBackend}, "", http.StatusInternalServerError)
+		return []string{}, nil, model.NewAppError("Elasticsearch.SearchPosts", "ent.elasticsearch.search_files.disabled", map[string]any{"Backend": model.ElasticsearchSettingsESBackend}, "", http.StatusInternalServerError)
 	}
 
 	var channelIds []string
@@ -1680,7 +1680,7 @@ func (es *ElasticsearchInterfaceImpl) SearchFiles(channels model.ChannelList, se
 		if *es.Platform.Config().ElasticsearchSettings.Trace == "error" {
 			errorStr = "Query=" + getJSONOrErrorStr(query) + ", " + errorStr
 		}
-		return []string{}, model.NewAppError("Elasticsearch.SearchFiles", "ent.elasticsearch.search_files.search_failed", nil, errorStr, http.StatusInternalServerError)
+		return []string{}, nil, model.NewAppError("Elasticsearch.SearchFiles", "ent.elasticsearch.search_files.search_failed", nil, errorStr, http.StatusInternalServerError)
 	}
 
 	fileIds := make([]string, len(searchResult.Hits.Hits))
@@ -1688,12 +1688,12 @@ func (es *ElasticsearchInterfaceImpl) SearchFiles(channels model.ChannelList, se
 	for i, hit := range searchResult.Hits.Hits {
 		var file common.ESFile
 		if err := json.Unmarshal(hit.Source_, &file); err != nil {
-			return fileIds, model.NewAppError("Elasticsearch.SearchFiles", "ent.elasticsearch.search_files.unmarshall_file_failed", nil, "", http.StatusInternalServerError).Wrap(err)
+			return fileIds, nil, model.NewAppError("Elasticsearch.SearchFiles", "ent.elasticsearch.search_files.unmarshall_file_failed", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 		fileIds[i] = file.Id
 	}
 
-	return fileIds, nil
+	return fileIds, nil, nil
 }
 
 func (es *ElasticsearchInterfaceImpl) DeleteFile(fileID string) *model.AppError {
@@ -1579,12 +1579,12 @@ func (os *OpensearchInterfaceImpl) IndexFile(file *model.FileInfo, channelId str
 	return nil
 }
 
-func (os *OpensearchInterfaceImpl) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+func (os *OpensearchInterfaceImpl) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError) {
 	os.mutex.RLock()
 	defer os.mutex.RUnlock()
 
 	if atomic.LoadInt32(&os.ready) == 0 {
-		return []string{}, model.NewAppError("Opensearch.SearchPosts", "ent.elasticsearch.search_files.disabled", map[string]any{"Backend": model.ElasticsearchSettingsOSBackend}, "", http.StatusInternalServerError)
+		return []string{}, nil, model.NewAppError("Opensearch.SearchPosts", "ent.elasticsearch.search_files.disabled", map[string]any{"Backend": model.ElasticsearchSettingsOSBackend}, "", http.StatusInternalServerError)
 	}
 
 	var channelIds []string
@@ -1794,7 +1794,7 @@ func (os *OpensearchInterfaceImpl) SearchFiles(channels model.ChannelList, searc
 		}},
 	})
 	if err != nil {
-		return []string{}, model.NewAppError("Opensearch.SearchFiles", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return []string{}, nil, model.NewAppError("Opensearch.SearchFiles", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
 	searchResult, err := os.client.Search(ctx, &opensearchapi.SearchReq{
@@ -1810,7 +1810,7 @@ func (os *OpensearchInterfaceImpl) SearchFiles(channels model.ChannelList, searc
 		if *os.Platform.Config().ElasticsearchSettings.Trace == "error" {
 			errorStr = "Query=" + getJSONOrErrorStr(query) + ", " + errorStr
 		}
-		return []string{}, model.NewAppError("Opensearch.SearchFiles", "ent.elasticsearch.search_files.search_failed", nil, errorStr, http.StatusInternalServerError)
+		return []string{}, nil, model.NewAppError("Opensearch.SearchFiles", "ent.elasticsearch.search_files.search_failed", nil, errorStr, http.StatusInternalServerError)
 	}
 
 	fileIds := make([]string, len(searchResult.Hits.Hits))
@@ -1818,12 +1818,12 @@ func (os *OpensearchInterfaceImpl) SearchFiles(channels model.ChannelList, searc
 	for i, hit := range searchResult.Hits.Hits {
 		var file common.ESFile
 		if err := json.Unmarshal(hit.Source, &file); err != nil {
-			return fileIds, model.NewAppError("Opensearch.SearchFiles", "ent.elasticsearch.search_files.unmarshall_file_failed", nil, "", http.StatusInternalServerError).Wrap(err)
+			return fileIds, nil, model.NewAppError("Opensearch.SearchFiles", "ent.elasticsearch.search_files.unmarshall_file_failed", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 		fileIds[i] = file.Id
 	}
 
-	return fileIds, nil
+	return fileIds, nil, nil
 }
 
 func (os *OpensearchInterfaceImpl) DeleteFile(fileID string) *model.AppError {
@@ -71,9 +71,10 @@ type MetricsInterfaceImpl struct {
 	PostBroadcastCounter  prometheus.Counter
 	PostFileAttachCounter prometheus.Counter
 
-	HTTPRequestsCounter prometheus.Counter
-	HTTPErrorsCounter   prometheus.Counter
-	HTTPWebsocketsGauge *prometheus.GaugeVec
+	HTTPRequestsCounter        prometheus.Counter
+	HTTPErrorsCounter          prometheus.Counter
+	HTTPRateLimitErrorsCounter prometheus.Counter
+	HTTPWebsocketsGauge        *prometheus.GaugeVec
 
 	ClusterRequestsDuration prometheus.Histogram
 	ClusterRequestsCounter  prometheus.Counter
@@ -165,6 +166,8 @@ type MetricsInterfaceImpl struct {
 	SearchFileIndexCounter     prometheus.Counter
 	SearchUserIndexCounter     prometheus.Counter
 	SearchChannelIndexCounter  prometheus.Counter
+	SearchFileExtractionTimes  *prometheus.HistogramVec
+	SearchFileExtractionErrors *prometheus.CounterVec
 	ActiveUsers                prometheus.Gauge
 
 	PluginHookTimeHistogram            *prometheus.HistogramVec
@@ -446,6 +449,15 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 	})
 	m.Registry.MustRegister(m.HTTPErrorsCounter)
 
+	m.HTTPRateLimitErrorsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemHTTP,
+		Name:        "rate_limit_errors_total",
+		Help:        "The total number of http API requests let through because the rate limiter failed, such as when Redis is unreachable.",
+		ConstLabels: additionalLabels,
+	})
+	m.Registry.MustRegister(m.HTTPRateLimitErrorsCounter)
+
 	// Cluster Subsystem
 
 	m.ClusterHealthGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
@@ -529,6 +541,18 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 		model.ClusterEventPluginEvent,
 		model.ClusterEventInvalidateCacheForTermsOfService,
 		model.ClusterEventBusyStateChanged,
+		model.ClusterEventWhitelistRevokeUser,
+		model.ClusterEventInvalidateCacheForWhitelist,
+		model.ClusterEventInvalidateCacheForWhitelistPolicies,
+		model.ClusterEventInvalidateCacheForLoginBans,
+		model.ClusterEventInvalidateCacheForGroupsByUser,
+		model.ClusterEventBleveIndexOperation,
+		model.ClusterEventBleveSearchRequest,
+		model.ClusterEventBleveSearchResponse,
+		model.ClusterEventBleveOwnerRequest,
+		model.ClusterEventBleveOwnerAnnouncement,
+		model.ClusterEventBleveRestoreSnapshot,
+		model.ClusterEventInvalidateSavedSearchAlerts,
 	} {
 		m.ClusterEventMap[event] = m.ClusterEventTypeCounters.With(prometheus.Labels{"name": string(event)})
 	}
@@ -844,6 +868,29 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 	})
 	m.Registry.MustRegister(m.SearchChannelIndexCounter)
 
+	m.SearchFileExtractionTimes = prometheus.NewHistogramVec(
+		withLabels(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: MetricsSubsystemSearch,
+			Name:      "file_extraction_duration_seconds",
+			Help:      "Time to extract the content of a file, by extractor.",
+		}),
+		[]string{"extractor", "success"},
+	)
+	m.Registry.MustRegister(m.SearchFileExtractionTimes)
+
+	m.SearchFileExtractionErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemSearch,
+			Name:        "file_extraction_failures_total",
+			Help:        "The total number of failed file content extractions, by extractor and reason.",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"extractor", "reason"},
+	)
+	m.Registry.MustRegister(m.SearchFileExtractionErrors)
+
 	// Plugin Subsystem
 
 	m.PluginHookTimeHistogram = prometheus.NewHistogramVec(
@@ -1739,6 +1786,10 @@ func (mi *MetricsInterfaceImpl) IncrementHTTPError() {
 	mi.HTTPErrorsCounter.Inc()
 }
 
+func (mi *MetricsInterfaceImpl) IncrementHTTPRateLimitError() {
+	mi.HTTPRateLimitErrorsCounter.Inc()
+}
+
 func (mi *MetricsInterfaceImpl) IncrementClusterRequest() {
 	mi.ClusterRequestsCounter.Inc()
 }
@@ -1884,6 +1935,14 @@ func (mi *MetricsInterfaceImpl) IncrementChannelIndexCounter() {
 	mi.SearchChannelIndexCounter.Inc()
 }
 
+func (mi *MetricsInterfaceImpl) ObserveFileExtractionDuration(extractor string, success bool, elapsed float64) {
+	mi.SearchFileExtractionTimes.With(prometheus.Labels{"extractor": extractor, "success": strconv.FormatBool(success)}).Observe(elapsed)
+}
+
+func (mi *MetricsInterfaceImpl) IncrementFileExtractionFailureCounter(extractor, reason string) {
+	mi.SearchFileExtractionErrors.With(prometheus.Labels{"extractor": extractor, "reason": reason}).Inc()
+}
+
 func (mi *MetricsInterfaceImpl) ObservePluginHookDuration(pluginID, hookName string, success bool, elapsed float64) {
 	mi.PluginHookTimeHistogram.With(prometheus.Labels{"plugin_id": pluginID, "hook_name": hookName, "success": strconv.FormatBool(success)}).Observe(elapsed)
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// incrementCounterScript increments the count of the counter at KEYS[1], sets its update time to
+// ARGV[1] and its TTL to ARGV[2] milliseconds, returning the new count.
+var incrementCounterScript = rueidis.NewLuaScript(`
+local count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('HSET', KEYS[1], 'update_at', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return count
+`)
+
+// Counter counts the events of a key, such as failed logins, with the time of the last one.
+type Counter struct {
+	Count    int64
+	UpdateAt int64
+}
+
+// CounterStore keeps counters that are forgotten a while after their last increment. The
+// increments are atomic, across the nodes of a cluster when the counters are kept in Redis.
+type CounterStore interface {
+	// Increment adds one to the counter of the key, sets its update time to now and its
+	// expiry to the ttl, returning the counter.
+	Increment(key string, now int64, ttl time.Duration) (Counter, error)
+	// Get returns the counter of the key, with a zero count when it doesn't exist.
+	Get(key string) (Counter, error)
+	// Remove forgets the counters of the keys.
+	Remove(keys ...string) error
+	// Purge forgets every counter.
+	Purge() error
+}
+
+// NewCounterStore returns a store for the counters with the given name, shared through Redis
+// when the provider uses Redis, or kept in memory by each node otherwise.
+func NewCounterStore(provider Provider, name string, size int) CounterStore {
+	redisProvider, ok := provider.(*redisProvider)
+	if !ok {
+		return &memoryCounterStore{
+			cache: NewLRU(&CacheOptions{Name: name, Size: size}),
+		}
+	}
+
+	prefix := "counter:" + name + ":"
+	if redisProvider.cachePrefix != "" {
+		prefix = redisProvider.cachePrefix + ":" + prefix
+	}
+	return &RedisCounterStore{
+		client: redisProvider.client,
+		prefix: prefix,
+	}
+}
+
+// RedisCounterStore keeps the counters in Redis hashes, incremented by a script so that
+// concurrent increments from any node are all counted.
+type RedisCounterStore struct {
+	client rueidis.Client
+	prefix string
+}
+
+var _ CounterStore = (*RedisCounterStore)(nil)
+
+func (s *RedisCounterStore) Increment(key string, now int64, ttl time.Duration) (Counter, error) {
+	count, err := incrementCounterScript.Exec(context.Background(), s.client,
+		[]string{s.prefix + key},
+		[]string{strconv.FormatInt(now, 10), strconv.FormatInt(ttlForRedis(ttl).Milliseconds(), 10)},
+	).AsInt64()
+	if err != nil {
+		return Counter{}, err
+	}
+	return Counter{Count: count, UpdateAt: now}, nil
+}
+
+func (s *RedisCounterStore) Get(key string) (Counter, error) {
+	values, err := s.client.Do(context.Background(),
+		s.client.B().Hmget().Key(s.prefix+key).Field("count", "update_at").Build(),
+	).ToArray()
+	if err != nil {
+		return Counter{}, err
+	}
+	if len(values) != 2 {
+		return Counter{}, errors.New("unexpected reply to the HMGET command")
+	}
+
+	var counter Counter
+	if counter.Count, err = values[0].AsInt64(); rueidis.IsRedisNil(err) {
+		return Counter{}, nil
+	} else if err != nil {
+		return Counter{}, err
+	}
+	if counter.UpdateAt, err = values[1].AsInt64(); err != nil && !rueidis.IsRedisNil(err) {
+		return Counter{}, err
+	}
+	return counter, nil
+}
+
+func (s *RedisCounterStore) Remove(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, s.prefix+key)
+	}
+	return s.client.Do(context.Background(), s.client.B().Del().Key(prefixed...).Build()).Error()
+}
+
+func (s *RedisCounterStore) Purge() error {
+	var scan rueidis.ScanEntry
+	var err error
+	for more := true; more; more = scan.Cursor != 0 {
+		scan, err = s.client.Do(context.Background(),
+			s.client.B().Scan().
+				Cursor(scan.Cursor).
+				Match(s.prefix+"*").
+				Count(100).
+				Build()).AsScanEntry()
+		if err != nil {
+			return err
+		}
+
+		keys := sliceMapper(scan.Elements, func(elem string) string {
+			return strings.TrimPrefix(elem, s.prefix)
+		})
+		if err = s.Remove(keys...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memoryCounterStore keeps the counters in an LRU cache, locking it around the increments.
+type memoryCounterStore struct {
+	mut   sync.Mutex
+	cache Cache
+}
+
+func (s *memoryCounterStore) Increment(key string, now int64, ttl time.Duration) (Counter, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	counter, err := s.get(key)
+	if err != nil {
+		return Counter{}, err
+	}
+	counter.Count++
+	counter.UpdateAt = now
+
+	if err := s.cache.SetWithExpiry(key, counter, ttl); err != nil {
+		return Counter{}, err
+	}
+	return counter, nil
+}
+
+func (s *memoryCounterStore) Get(key string) (Counter, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.get(key)
+}
+
+func (s *memoryCounterStore) get(key string) (Counter, error) {
+	var counter Counter
+	if err := s.cache.Get(key, &counter); err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return Counter{}, err
+	}
+	return counter, nil
+}
+
+func (s *memoryCounterStore) Remove(keys ...string) error {
+	return s.cache.RemoveMulti(keys)
+}
+
+func (s *memoryCounterStore) Purge() error {
+	return s.cache.Purge()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCounterStore(t *testing.T) {
+	s := NewCounterStore(NewProvider(), "counters", 128)
+
+	t.Run("missing counter", func(t *testing.T) {
+		counter, err := s.Get("missing")
+		require.NoError(t, err)
+		require.Equal(t, Counter{}, counter)
+	})
+
+	t.Run("concurrent increments are all counted", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.Increment("concurrent", 1000, time.Minute)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		counter, err := s.Get("concurrent")
+		require.NoError(t, err)
+		require.Equal(t, Counter{Count: 50, UpdateAt: 1000}, counter)
+	})
+
+	t.Run("increment returns the counter", func(t *testing.T) {
+		counter, err := s.Increment("increment", 1000, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, Counter{Count: 1, UpdateAt: 1000}, counter)
+
+		counter, err = s.Increment("increment", 2000, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, Counter{Count: 2, UpdateAt: 2000}, counter)
+	})
+
+	t.Run("expired counter starts again", func(t *testing.T) {
+		_, err := s.Increment("expired", 1000, time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		counter, err := s.Increment("expired", 2000, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, Counter{Count: 1, UpdateAt: 2000}, counter)
+	})
+
+	t.Run("remove and purge", func(t *testing.T) {
+		_, err := s.Increment("a", 1000, time.Minute)
+		require.NoError(t, err)
+		_, err = s.Increment("b", 1000, time.Minute)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Remove("a"))
+		counter, err := s.Get("a")
+		require.NoError(t, err)
+		require.Zero(t, counter.Count)
+
+		require.NoError(t, s.Purge())
+		counter, err = s.Get("b")
+		require.NoError(t, err)
+		require.Zero(t, counter.Count)
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/throttled/throttled"
+)
+
+// compareAndSwapScript sets the key to ARGV[2] with a TTL of ARGV[3] milliseconds when its value
+// is ARGV[1], returning -1 when the key doesn't exist.
+var compareAndSwapScript = rueidis.NewLuaScript(`
+local v = redis.call('GET', KEYS[1])
+if v == false then
+	return -1
+end
+if v ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// RedisGCRAStore keeps the state of rate limiters in Redis, so that the limits are shared by
+// all the nodes of a cluster.
+type RedisGCRAStore struct {
+	client rueidis.Client
+	prefix string
+}
+
+var _ throttled.GCRAStore = (*RedisGCRAStore)(nil)
+
+// NewGCRAStore returns a store for the state of the rate limiter with the given name, shared
+// through Redis when the provider uses Redis, or nil when the state is to be kept in memory.
+func NewGCRAStore(provider Provider, name string) *RedisGCRAStore {
+	redisProvider, ok := provider.(*redisProvider)
+	if !ok {
+		return nil
+	}
+
+	prefix := "ratelimit:" + name + ":"
+	if redisProvider.cachePrefix != "" {
+		prefix = redisProvider.cachePrefix + ":" + prefix
+	}
+	return &RedisGCRAStore{
+		client: redisProvider.client,
+		prefix: prefix,
+	}
+}
+
+// GetWithTime returns the value of the key, or -1 when it doesn't exist, with the time of the
+// Redis server, shared by all the nodes.
+func (s *RedisGCRAStore) GetWithTime(key string) (int64, time.Time, error) {
+	results := s.client.DoMulti(context.Background(),
+		s.client.B().Get().Key(s.prefix+key).Build(),
+		s.client.B().Time().Build(),
+	)
+
+	serverTime, err := results[1].AsStrSlice()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(serverTime) != 2 {
+		return 0, time.Time{}, errors.New("unexpected reply to the TIME command")
+	}
+	seconds, err := strconv.ParseInt(serverTime[0], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	microseconds, err := strconv.ParseInt(serverTime[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	now := time.Unix(seconds, microseconds*int64(time.Microsecond))
+
+	value, err := results[0].AsInt64()
+	if rueidis.IsRedisNil(err) {
+		return -1, now, nil
+	} else if err != nil {
+		return 0, now, err
+	}
+	return value, now, nil
+}
+
+// SetIfNotExistsWithTTL sets the value of the key unless it exists, returning whether it was set.
+func (s *RedisGCRAStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	err := s.client.Do(context.Background(),
+		s.client.B().Set().Key(s.prefix+key).Value(strconv.FormatInt(value, 10)).Nx().Px(ttlForRedis(ttl)).Build(),
+	).Error()
+	if rueidis.IsRedisNil(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwapWithTTL atomically sets the key to new when its value is old, returning whether
+// it was set. A missing key isn't set.
+func (s *RedisGCRAStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	swapped, err := compareAndSwapScript.Exec(context.Background(), s.client,
+		[]string{s.prefix + key},
+		[]string{strconv.FormatInt(old, 10), strconv.FormatInt(new, 10), strconv.FormatInt(ttlForRedis(ttl).Milliseconds(), 10)},
+	).AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return swapped == 1, nil
+}
+
+// ttlForRedis rounds a TTL up to the millisecond, the precision of Redis.
+func ttlForRedis(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return time.Millisecond
+	}
+	rounded := ttl.Truncate(time.Millisecond)
+	if rounded < ttl {
+		rounded += time.Millisecond
+	}
+	return rounded
+}
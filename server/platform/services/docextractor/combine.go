@@ -4,6 +4,7 @@
 package docextractor
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -11,6 +12,7 @@ import (
 
 type combineExtractor struct {
 	logger        mlog.LoggerIFace
+	tracker       *attemptTracker
 	SubExtractors []Extractor
 }
 
@@ -31,17 +33,31 @@ func (ce *combineExtractor) Match(filename string) bool {
 	return false
 }
 
+// Extract tries the extractors matching the document in turn. An extractor failing falls back
+// on the next ones, and its error is returned if none of them finds any text, so that the
+// documents failing to be extracted are told apart from the ones without text.
 func (ce *combineExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	var firstErr error
 	for _, extractor := range ce.SubExtractors {
 		if extractor.Match(filename) {
-			r.Seek(0, io.SeekStart)
+			if _, err := r.Seek(0, io.SeekStart); err != nil {
+				return "", fmt.Errorf("error reading the document: %w", err)
+			}
+			attempt := ce.tracker.start(extractor.Name())
 			text, err := extractor.Extract(filename, r)
+			ce.tracker.finish(attempt, err)
 			if err != nil {
 				ce.logger.Warn("Unable to extract file content", mlog.String("file_name", filename), mlog.String("extractor", extractor.Name()), mlog.Err(err))
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", extractor.Name(), err)
+				}
+				continue
+			}
+			if text == "" && firstErr != nil {
 				continue
 			}
 			return text, nil
 		}
 	}
-	return "", nil
+	return "", firstErr
 }
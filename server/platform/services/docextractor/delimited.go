@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"encoding/csv"
+	"io"
+	"path"
+	"strings"
+)
+
+var delimiterByExtensions = map[string]rune{
+	"csv": ',',
+	"tsv": '\t',
+}
+
+// delimitedExtractor extracts the values of comma or tab separated files,
+// reading no more than the size and cells limits of the format. The rest of
+// a larger file is left out of the text rather than read as plain text.
+type delimitedExtractor struct {
+	Limits formatLimits
+}
+
+func (de *delimitedExtractor) Name() string {
+	return "delimitedExtractor"
+}
+
+func (de *delimitedExtractor) Match(filename string) bool {
+	_, ok := delimiterByExtensions[strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".")]
+	return ok
+}
+
+func (de *delimitedExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	reader := csv.NewReader(io.LimitReader(r, de.Limits.MaxSize))
+	reader.Comma = delimiterByExtensions[strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".")]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	var text strings.Builder
+	cells := 0
+	for cells < de.Limits.MaxCells {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if cells == 0 {
+				return "", err
+			}
+			// The records read before a malformed one, or a file cut by
+			// the size limit, are kept
+			break
+		}
+
+		rowCells := 0
+		for _, field := range record {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if rowCells > 0 {
+				text.WriteString("\t")
+			}
+			text.WriteString(field)
+			rowCells++
+			cells++
+			if cells >= de.Limits.MaxCells {
+				break
+			}
+		}
+		if rowCells > 0 {
+			text.WriteString("\n")
+		}
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelimitedExtractor(t *testing.T) {
+	extractor := delimitedExtractor{Limits: delimitedLimits}
+
+	t.Run("csv", func(t *testing.T) {
+		require.True(t, extractor.Match("report.csv"))
+		text, err := extractor.Extract("report.csv", strings.NewReader("account,amount\n\"Acme, Inc.\",1200\nGlobex,,\n"))
+		require.NoError(t, err)
+		require.Equal(t, "account\tamount\nAcme, Inc.\t1200\nGlobex\n", text)
+	})
+
+	t.Run("tsv", func(t *testing.T) {
+		require.True(t, extractor.Match("report.TSV"))
+		text, err := extractor.Extract("report.tsv", strings.NewReader("account\tamount\nAcme, Inc.\t1200\n"))
+		require.NoError(t, err)
+		require.Equal(t, "account\tamount\nAcme, Inc.\t1200\n", text)
+	})
+
+	t.Run("stops at the limits", func(t *testing.T) {
+		content := strings.Repeat("a,b,c\n", 1000)
+
+		limited := delimitedExtractor{Limits: formatLimits{MaxSize: delimitedLimits.MaxSize, MaxCells: 4}}
+		text, err := limited.Extract("report.csv", strings.NewReader(content))
+		require.NoError(t, err)
+		require.Equal(t, "a\tb\tc\na\n", text)
+
+		limited = delimitedExtractor{Limits: formatLimits{MaxSize: 12, MaxCells: delimitedLimits.MaxCells}}
+		text, err = limited.Extract("report.csv", strings.NewReader(content))
+		require.NoError(t, err)
+		require.Equal(t, "a\tb\tc\na\tb\tc\n", text)
+	})
+}
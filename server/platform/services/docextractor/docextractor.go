@@ -5,6 +5,7 @@ package docextractor
 
 import (
 	"io"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -14,23 +15,61 @@ type ExtractSettings struct {
 	ArchiveRecursion bool
 	MMPreviewURL     string
 	MMPreviewSecret  string
+
+	// Timeout is the time given to the extraction of a document, unlimited when zero.
+	Timeout time.Duration
+	// MaxMemory is the memory in bytes the extraction of a document may use, unlimited when zero.
+	// It is only enforced in a worker: in the server process, the documents larger than it are
+	// refused, but the memory used to extract the other ones isn't limited.
+	MaxMemory int64
+	// WorkerCommand is the command of the subprocess the documents are extracted in. They are
+	// extracted in the server process when empty.
+	WorkerCommand []string
+	// Observer is notified of each extractor tried on a document.
+	Observer Observer
 }
 
+// Observer is called with the time taken by an extractor, and with its error when it failed,
+// timed out or ran out of memory.
+type Observer func(extractor string, elapsed time.Duration, err error)
+
 // Extract extract the text from a document using the system default extractors
 func Extract(logger mlog.LoggerIFace, filename string, r io.ReadSeeker, settings ExtractSettings) (string, error) {
 	return ExtractWithExtraExtractors(logger, filename, r, settings, []Extractor{})
 }
 
 // ExtractWithExtraExtractors extract the text from a document using the provided extractors beside the system default extractors.
+// The documents are extracted in the server process when extra extractors are provided, as they can't be passed to a worker.
 func ExtractWithExtraExtractors(logger mlog.LoggerIFace, filename string, r io.ReadSeeker, settings ExtractSettings, extraExtractors []Extractor) (string, error) {
+	tracker := newAttemptTracker(settings.Observer)
+	if len(settings.WorkerCommand) > 0 && len(extraExtractors) == 0 {
+		return extractInWorker(logger, filename, r, settings, tracker)
+	}
+
+	enabledExtractors := newEnabledExtractors(logger, settings, extraExtractors, tracker)
+	if !enabledExtractors.Match(filename) {
+		return "", nil
+	}
+	return extractWithLimits(enabledExtractors, filename, r, settings, tracker)
+}
+
+func newEnabledExtractors(logger mlog.LoggerIFace, settings ExtractSettings, extraExtractors []Extractor, tracker *attemptTracker) *combineExtractor {
 	enabledExtractors := &combineExtractor{
-		logger: logger,
+		logger:  logger,
+		tracker: tracker,
 	}
 	for _, extraExtractor := range extraExtractors {
 		enabledExtractors.Add(extraExtractor)
 	}
 	enabledExtractors.Add(&documentExtractor{})
 	enabledExtractors.Add(&pdfExtractor{})
+	enabledExtractors.Add(&spreadsheetExtractor{Limits: spreadsheetLimits})
+	enabledExtractors.Add(&openDocumentExtractor{Extension: "ods", Limits: spreadsheetLimits})
+	enabledExtractors.Add(&openDocumentExtractor{Extension: "odp", Limits: presentationLimits})
+	enabledExtractors.Add(&delimitedExtractor{Limits: delimitedLimits})
+	enabledExtractors.Add(&emailExtractor{Limits: emailLimits})
+	enabledExtractors.Add(&msgExtractor{Limits: emailLimits})
+	enabledExtractors.Add(&notebookExtractor{Limits: notebookLimits})
 
 	if settings.ArchiveRecursion {
 		enabledExtractors.Add(&archiveExtractor{SubExtractor: enabledExtractors})
@@ -43,8 +82,5 @@ func ExtractWithExtraExtractors(logger mlog.LoggerIFace, filename string, r io.R
 	}
 	enabledExtractors.Add(&plainExtractor{})
 
-	if enabledExtractors.Match(filename) {
-		return enabledExtractors.Extract(filename, r)
-	}
-	return "", nil
+	return enabledExtractors
 }
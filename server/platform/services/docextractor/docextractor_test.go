@@ -124,6 +124,22 @@ func TestExtract(t *testing.T) {
 			[]string{},
 			false,
 		},
+		{
+			"Odp file",
+			"sample-doc.odp",
+			ExtractSettings{},
+			[]string{"simple", "document", "contains"},
+			[]string{},
+			false,
+		},
+		{
+			"Csv file",
+			"channel-role-has-permission.csv",
+			ExtractSettings{},
+			[]string{"higher-scoped scheme has the permission", "TRUE\tTRUE"},
+			[]string{`""`},
+			false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -157,7 +173,8 @@ func TestExtract(t *testing.T) {
 		data, err := testutils.ReadTestFile("sample-doc.pdf")
 		require.NoError(t, err)
 		text, err := Extract(logger, "sample-doc.docx", bytes.NewReader(data), ExtractSettings{})
-		require.NoError(t, err)
+		// The error of the docx extractor is returned, as the plain text fallback finds no text
+		require.Error(t, err)
 		require.Equal(t, "", text)
 	})
 }
@@ -211,4 +228,10 @@ func TestExtractWithExtraExtractors(t *testing.T) {
 		assert.Contains(t, text, "document")
 		assert.Contains(t, text, "contains")
 	})
+
+	t.Run("failing extractor without any text found", func(t *testing.T) {
+		text, err := ExtractWithExtraExtractors(logger, "file.bin", bytes.NewReader([]byte{0, 1, 2}), ExtractSettings{}, []Extractor{&failingExtractor{}})
+		require.ErrorContains(t, err, "this always fail")
+		assert.Empty(t, text)
+	})
 }
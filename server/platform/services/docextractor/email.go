@@ -0,0 +1,299 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/jaytaylor/html2text"
+	"github.com/richardlehane/mscfb"
+	"golang.org/x/net/html/charset"
+)
+
+// emailMaxDepth is how deep the multipart and forwarded messages of an email
+// are read.
+const emailMaxDepth = 10
+
+var emailWordDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// emailExtractor extracts the headers, the text and the attachment names of
+// the MIME emails. The limit on cells applies to the parts of the email.
+type emailExtractor struct {
+	Limits formatLimits
+}
+
+func (ee *emailExtractor) Name() string {
+	return "emailExtractor"
+}
+
+func (ee *emailExtractor) Match(filename string) bool {
+	return strings.ToLower(path.Ext(filename)) == ".eml"
+}
+
+func (ee *emailExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	message, err := mail.ReadMessage(io.LimitReader(r, ee.Limits.MaxSize))
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	parts := 0
+	ee.writeMessage(&text, message.Header, message.Body, &parts, 0)
+	return text.String(), nil
+}
+
+type emailHeader interface {
+	Get(key string) string
+}
+
+func (ee *emailExtractor) writeMessage(text *strings.Builder, header emailHeader, body io.Reader, parts *int, depth int) {
+	for _, key := range []string{"Subject", "From", "To", "Cc"} {
+		if value := header.Get(key); value != "" {
+			text.WriteString(key + ": " + decodeEmailHeader(value) + "\n")
+		}
+	}
+	text.WriteString("\n")
+
+	ee.writePart(text, header, body, parts, depth)
+}
+
+// writePart writes the text of a part of an email, and only the name of its
+// attachments. The text of the parts cut by the size limit is kept.
+func (ee *emailExtractor) writePart(text *strings.Builder, header emailHeader, body io.Reader, parts *int, depth int) {
+	*parts++
+	if *parts > ee.Limits.MaxCells || depth > emailMaxDepth {
+		return
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	_, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if filename != "" {
+		text.WriteString(decodeEmailHeader(filename) + "\n")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return
+			}
+			ee.writePart(text, part.Header, part, parts, depth+1)
+			if *parts > ee.Limits.MaxCells {
+				return
+			}
+		}
+	case mediaType == "message/rfc822":
+		message, err := mail.ReadMessage(decodeEmailBody(header, params, body))
+		if err != nil {
+			return
+		}
+		ee.writeMessage(text, message.Header, message.Body, parts, depth+1)
+	case mediaType == "text/plain", mediaType == "text/html":
+		data, _ := io.ReadAll(decodeEmailBody(header, params, body))
+		content := string(data)
+		if mediaType == "text/html" {
+			if converted, err := html2text.FromString(content, html2text.Options{TextOnly: true}); err == nil {
+				content = converted
+			}
+		}
+		text.WriteString(strings.TrimSpace(content) + "\n")
+	}
+}
+
+// decodeEmailBody decodes the transfer encoding and the charset of a part.
+func decodeEmailBody(header emailHeader, params map[string]string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	if label := params["charset"]; label != "" {
+		if reader, err := charset.NewReaderLabel(label, body); err == nil {
+			body = reader
+		}
+	}
+	return body
+}
+
+func decodeEmailHeader(value string) string {
+	decoded, err := emailWordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// The properties of Outlook messages read, by their tag
+const (
+	msgPropertySubject            = 0x0037
+	msgPropertySenderName         = 0x0C1A
+	msgPropertySenderEmailAddress = 0x0C1F
+	msgPropertyDisplayCc          = 0x0E03
+	msgPropertyDisplayTo          = 0x0E04
+	msgPropertyBody               = 0x1000
+	msgPropertyAttachFilename     = 0x3704
+	msgPropertyAttachLongFilename = 0x3707
+
+	msgPropertyTypeString8  = 0x001E
+	msgPropertyTypeUnicode  = 0x001F
+	msgPropertyStreamPrefix = "__substg1.0_"
+	msgAttachmentPrefix     = "__attach_version1.0_"
+)
+
+// msgExtractor extracts the headers, the text and the attachment names of the
+// Outlook messages, which are compound files with a stream per property. The
+// limit on cells applies to the attachments.
+type msgExtractor struct {
+	Limits formatLimits
+}
+
+func (me *msgExtractor) Name() string {
+	return "msgExtractor"
+}
+
+func (me *msgExtractor) Match(filename string) bool {
+	return strings.ToLower(path.Ext(filename)) == ".msg"
+}
+
+func (me *msgExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	data, err := readLimited(r, me.Limits.MaxSize)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := mscfb.New(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	properties := map[uint16]string{}
+	var attachments []string
+	attachmentNames := map[string]map[uint16]string{}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		tag, ok := parseMsgPropertyStreamName(entry.Name)
+		if !ok {
+			continue
+		}
+
+		var names map[uint16]string
+		switch {
+		case len(entry.Path) == 0:
+			if tag.id != msgPropertySubject && tag.id != msgPropertySenderName && tag.id != msgPropertySenderEmailAddress &&
+				tag.id != msgPropertyDisplayTo && tag.id != msgPropertyDisplayCc && tag.id != msgPropertyBody {
+				continue
+			}
+		case len(entry.Path) == 1 && strings.HasPrefix(entry.Path[0], msgAttachmentPrefix):
+			if tag.id != msgPropertyAttachFilename && tag.id != msgPropertyAttachLongFilename {
+				continue
+			}
+			names = attachmentNames[entry.Path[0]]
+			if names == nil {
+				if len(attachmentNames) >= me.Limits.MaxCells {
+					continue
+				}
+				names = map[uint16]string{}
+				attachmentNames[entry.Path[0]] = names
+				attachments = append(attachments, entry.Path[0])
+			}
+		default:
+			continue
+		}
+
+		value := make([]byte, entry.Size)
+		if _, err := io.ReadFull(entry, value); err != nil {
+			return "", fmt.Errorf("error reading the %s property: %w", entry.Name, err)
+		}
+		if names != nil {
+			names[tag.id] = decodeMsgString(tag.typ, value)
+		} else {
+			properties[tag.id] = decodeMsgString(tag.typ, value)
+		}
+	}
+
+	var text strings.Builder
+	if subject := properties[msgPropertySubject]; subject != "" {
+		text.WriteString("Subject: " + subject + "\n")
+	}
+	if from := strings.TrimSpace(properties[msgPropertySenderName] + " " + properties[msgPropertySenderEmailAddress]); from != "" {
+		text.WriteString("From: " + from + "\n")
+	}
+	if to := properties[msgPropertyDisplayTo]; to != "" {
+		text.WriteString("To: " + to + "\n")
+	}
+	if cc := properties[msgPropertyDisplayCc]; cc != "" {
+		text.WriteString("Cc: " + cc + "\n")
+	}
+	text.WriteString("\n" + strings.TrimSpace(properties[msgPropertyBody]) + "\n")
+
+	for _, attachment := range attachments {
+		name := attachmentNames[attachment][msgPropertyAttachLongFilename]
+		if name == "" {
+			name = attachmentNames[attachment][msgPropertyAttachFilename]
+		}
+		if name != "" {
+			text.WriteString(name + "\n")
+		}
+	}
+
+	return text.String(), nil
+}
+
+type msgPropertyTag struct {
+	id  uint16
+	typ uint16
+}
+
+// parseMsgPropertyStreamName parses the tag of the string properties from the
+// name of their stream, like __substg1.0_0037001F for a unicode subject.
+func parseMsgPropertyStreamName(name string) (msgPropertyTag, bool) {
+	hex, ok := strings.CutPrefix(name, msgPropertyStreamPrefix)
+	if !ok || len(hex) != 8 {
+		return msgPropertyTag{}, false
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return msgPropertyTag{}, false
+	}
+
+	tag := msgPropertyTag{id: uint16(value >> 16), typ: uint16(value)}
+	return tag, tag.typ == msgPropertyTypeUnicode || tag.typ == msgPropertyTypeString8
+}
+
+func decodeMsgString(typ uint16, value []byte) string {
+	if typ == msgPropertyTypeString8 {
+		return strings.TrimRight(string(value), "\x00")
+	}
+
+	units := make([]uint16, len(value)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(value[i*2:])
+	}
+	return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailExtractor(t *testing.T) {
+	email := strings.ReplaceAll(`From: Finance <finance@example.com>
+To: ops@example.com
+Subject: =?utf-8?q?Invoice_r=C3=A9sum=C3=A9?=
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+Please pay the invoice before Friday =E2=80=94 thanks.
+--inner
+Content-Type: text/html; charset=utf-8
+
+<p>Please pay the <b>invoice</b> before Friday</p>
+--inner--
+--outer
+Content-Type: application/pdf; name="invoice-2024.pdf"
+Content-Disposition: attachment; filename="invoice-2024.pdf"
+Content-Transfer-Encoding: base64
+
+c2VjcmV0IGF0dGFjaG1lbnQ=
+--outer--
+`, "\n", "\r\n")
+
+	t.Run("extracts the headers, the text and the attachment names", func(t *testing.T) {
+		extractor := emailExtractor{Limits: emailLimits}
+		require.True(t, extractor.Match("invoice.eml"))
+
+		text, err := extractor.Extract("invoice.eml", strings.NewReader(email))
+		require.NoError(t, err)
+		require.Contains(t, text, "Subject: Invoice résumé")
+		require.Contains(t, text, "From: Finance <finance@example.com>")
+		require.Contains(t, text, "To: ops@example.com")
+		require.Contains(t, text, "Please pay the invoice before Friday — thanks.")
+		require.Contains(t, text, "invoice-2024.pdf")
+		require.NotContains(t, text, "<b>")
+		require.NotContains(t, text, "secret attachment")
+	})
+
+	t.Run("stops at the parts limit", func(t *testing.T) {
+		extractor := emailExtractor{Limits: formatLimits{MaxSize: emailLimits.MaxSize, MaxCells: 3}}
+		text, err := extractor.Extract("invoice.eml", strings.NewReader(email))
+		require.NoError(t, err)
+		require.Contains(t, text, "thanks")
+		require.NotContains(t, text, "invoice-2024.pdf")
+	})
+}
+
+func TestParseMsgPropertyStreamName(t *testing.T) {
+	tag, ok := parseMsgPropertyStreamName("__substg1.0_0037001F")
+	require.True(t, ok)
+	require.Equal(t, msgPropertyTag{id: msgPropertySubject, typ: msgPropertyTypeUnicode}, tag)
+
+	_, ok = parseMsgPropertyStreamName("__substg1.0_10090102")
+	require.False(t, ok)
+
+	_, ok = parseMsgPropertyStreamName("__properties_version1.0")
+	require.False(t, ok)
+
+	require.Equal(t, "Invoice", decodeMsgString(msgPropertyTypeUnicode, []byte{'I', 0, 'n', 0, 'v', 0, 'o', 0, 'i', 0, 'c', 0, 'e', 0, 0, 0}))
+	require.Equal(t, "Invoice", decodeMsgString(msgPropertyTypeString8, []byte("Invoice\x00")))
+}
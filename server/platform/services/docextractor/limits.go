@@ -0,0 +1,64 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// formatLimits bounds the work done to extract the text of a format, so that a
+// huge or crafted file can't stall the content extraction job.
+type formatLimits struct {
+	// MaxSize is the largest file read, and for the zipped formats the
+	// largest uncompressed part read.
+	MaxSize int64
+	// MaxCells is the most spreadsheet or table cells, notebook cells or
+	// email parts read. The ones after it are left out of the text.
+	MaxCells int
+}
+
+var (
+	spreadsheetLimits  = formatLimits{MaxSize: 50 * 1024 * 1024, MaxCells: 200000}
+	presentationLimits = formatLimits{MaxSize: 50 * 1024 * 1024, MaxCells: 20000}
+	delimitedLimits    = formatLimits{MaxSize: 20 * 1024 * 1024, MaxCells: 200000}
+	emailLimits        = formatLimits{MaxSize: 25 * 1024 * 1024, MaxCells: 100}
+	notebookLimits     = formatLimits{MaxSize: 20 * 1024 * 1024, MaxCells: 2000}
+)
+
+// readLimited reads a file, failing if it's larger than maxSize.
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file larger than the %d bytes limit", maxSize)
+	}
+	return data, nil
+}
+
+// openZipLimited reads a zipped file, failing if it's larger than maxSize.
+func openZipLimited(r io.Reader, maxSize int64) (*zip.Reader, error) {
+	data, err := readLimited(r, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// openZipPart opens a part of a zipped file, reading no more than maxSize
+// uncompressed bytes of it.
+func openZipPart(zr *zip.Reader, name string, maxSize int64) (io.ReadCloser, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, maxSize), f}, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+)
+
+// notebookExtractor extracts the markdown and the code of Jupyter notebooks,
+// leaving out the outputs of the cells, which are mostly data and images.
+type notebookExtractor struct {
+	Limits formatLimits
+}
+
+type notebookSource []string
+
+// UnmarshalJSON reads the source of a cell, which is either a string or a list
+// of lines.
+func (s *notebookSource) UnmarshalJSON(data []byte) error {
+	var source string
+	if err := json.Unmarshal(data, &source); err == nil {
+		*s = notebookSource{source}
+		return nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return err
+	}
+	*s = lines
+	return nil
+}
+
+func (ne *notebookExtractor) Name() string {
+	return "notebookExtractor"
+}
+
+func (ne *notebookExtractor) Match(filename string) bool {
+	return strings.ToLower(path.Ext(filename)) == ".ipynb"
+}
+
+func (ne *notebookExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	data, err := readLimited(r, ne.Limits.MaxSize)
+	if err != nil {
+		return "", err
+	}
+
+	var notebook struct {
+		Cells []struct {
+			CellType string         `json:"cell_type"`
+			Source   notebookSource `json:"source"`
+		} `json:"cells"`
+	}
+	if err := json.Unmarshal(data, &notebook); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for i, cell := range notebook.Cells {
+		if i >= ne.Limits.MaxCells {
+			break
+		}
+		if cell.CellType != "markdown" && cell.CellType != "code" && cell.CellType != "raw" {
+			continue
+		}
+
+		source := strings.TrimSpace(strings.Join(cell.Source, ""))
+		if source != "" {
+			text.WriteString(source + "\n\n")
+		}
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotebookExtractor(t *testing.T) {
+	notebook := `{
+  "cells": [
+    {"cell_type": "markdown", "metadata": {}, "source": ["# Quarterly revenue\n", "Grouped by region"]},
+    {"cell_type": "code", "metadata": {}, "source": "df.groupby('region').sum()", "outputs": [{"output_type": "stream", "text": ["secret output"]}]},
+    {"cell_type": "markdown", "metadata": {}, "source": []}
+  ],
+  "metadata": {},
+  "nbformat": 4,
+  "nbformat_minor": 5
+}`
+
+	t.Run("extracts the markdown and the code", func(t *testing.T) {
+		extractor := notebookExtractor{Limits: notebookLimits}
+		require.True(t, extractor.Match("analysis.ipynb"))
+
+		text, err := extractor.Extract("analysis.ipynb", strings.NewReader(notebook))
+		require.NoError(t, err)
+		require.Equal(t, "# Quarterly revenue\nGrouped by region\n\ndf.groupby('region').sum()\n\n", text)
+	})
+
+	t.Run("stops at the cells limit", func(t *testing.T) {
+		extractor := notebookExtractor{Limits: formatLimits{MaxSize: notebookLimits.MaxSize, MaxCells: 1}}
+		text, err := extractor.Extract("analysis.ipynb", strings.NewReader(notebook))
+		require.NoError(t, err)
+		require.NotContains(t, text, "groupby")
+	})
+
+	t.Run("fails on the files over the size limit", func(t *testing.T) {
+		extractor := notebookExtractor{Limits: formatLimits{MaxSize: 10, MaxCells: notebookLimits.MaxCells}}
+		_, err := extractor.Extract("analysis.ipynb", strings.NewReader(notebook))
+		require.Error(t, err)
+	})
+}
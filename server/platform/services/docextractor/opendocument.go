@@ -0,0 +1,102 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	openDocumentTableNamespace = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	openDocumentTextNamespace  = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// openDocumentExtractor extracts the text of OpenDocument spreadsheets and
+// presentations, with the cells of their tables separated by tabs and the
+// table rows by new lines.
+type openDocumentExtractor struct {
+	Extension string
+	Limits    formatLimits
+}
+
+func (oe *openDocumentExtractor) Name() string {
+	return "openDocumentExtractor"
+}
+
+func (oe *openDocumentExtractor) Match(filename string) bool {
+	return strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".") == oe.Extension
+}
+
+func (oe *openDocumentExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	zr, err := openZipLimited(r, oe.Limits.MaxSize)
+	if err != nil {
+		return "", err
+	}
+
+	part, err := openZipPart(zr, "content.xml", oe.Limits.MaxSize)
+	if err != nil {
+		return "", err
+	}
+	defer part.Close()
+
+	var text strings.Builder
+	cells := 0
+	rowCells := 0
+	decoder := xml.NewDecoder(part)
+	for cells < oe.Limits.MaxCells {
+		token, err := decoder.Token()
+		if err != nil {
+			// The text read before a part cut by the size limit is kept
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name {
+			case xml.Name{Space: openDocumentTableNamespace, Local: "table"}:
+				// The names of the sheets, or of the tables of a presentation
+				for _, attr := range t.Attr {
+					if attr.Name.Space == openDocumentTableNamespace && attr.Name.Local == "name" {
+						text.WriteString(attr.Value + "\n")
+					}
+				}
+			case xml.Name{Space: openDocumentTableNamespace, Local: "table-row"}:
+				rowCells = 0
+			case xml.Name{Space: openDocumentTableNamespace, Local: "table-cell"}:
+				// The repeated cells are written once, as they are mostly the
+				// empty cells filling a sheet up to its last column and row
+				if rowCells > 0 {
+					text.WriteString("\t")
+				}
+				rowCells++
+				cells++
+			case xml.Name{Space: openDocumentTextNamespace, Local: "s"}:
+				text.WriteString(" ")
+			case xml.Name{Space: openDocumentTextNamespace, Local: "tab"}:
+				text.WriteString("\t")
+			case xml.Name{Space: openDocumentTextNamespace, Local: "line-break"}:
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name {
+			case xml.Name{Space: openDocumentTableNamespace, Local: "table-row"}:
+				text.WriteString("\n")
+				rowCells = 0
+			case xml.Name{Space: openDocumentTextNamespace, Local: "p"}, xml.Name{Space: openDocumentTextNamespace, Local: "h"}:
+				if rowCells == 0 {
+					text.WriteString("\n")
+				} else {
+					text.WriteString(" ")
+				}
+			}
+		case xml.CharData:
+			text.Write(t)
+		}
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDocumentExtractor(t *testing.T) {
+	data := makeZipFile(t, map[string]string{
+		"content.xml": `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:spreadsheet>` +
+			`<table:table table:name="Inventory">` +
+			`<table:table-row><table:table-cell><text:p>Laptops</text:p></table:table-cell><table:table-cell><text:p>42</text:p></table:table-cell><table:table-cell table:number-columns-repeated="16000"/></table:table-row>` +
+			`<table:table-row><table:table-cell><text:p>Docking<text:s/>stations</text:p></table:table-cell><table:table-cell><text:p>7</text:p></table:table-cell></table:table-row>` +
+			`</table:table></office:spreadsheet></office:body></office:document-content>`,
+	})
+
+	t.Run("extracts the tables", func(t *testing.T) {
+		extractor := openDocumentExtractor{Extension: "ods", Limits: spreadsheetLimits}
+		require.True(t, extractor.Match("inventory.ods"))
+		require.False(t, extractor.Match("inventory.odp"))
+
+		text, err := extractor.Extract("inventory.ods", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, "Inventory\nLaptops \t42 \t\nDocking stations \t7 \n", text)
+	})
+
+	t.Run("stops at the cells limit", func(t *testing.T) {
+		extractor := openDocumentExtractor{Extension: "ods", Limits: formatLimits{MaxSize: spreadsheetLimits.MaxSize, MaxCells: 2}}
+		text, err := extractor.Extract("inventory.ods", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Contains(t, text, "Laptops")
+		require.NotContains(t, text, "Docking")
+	})
+}
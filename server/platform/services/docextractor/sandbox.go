@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrExtractionTimeout is returned when the extraction of a document takes longer than its timeout.
+	ErrExtractionTimeout = errors.New("content extraction timed out")
+	// ErrExtractionMemoryLimit is returned when the extraction of a document needs more memory than allowed.
+	ErrExtractionMemoryLimit = errors.New("content extraction exceeded the memory limit")
+)
+
+// Reasons of the extraction failures, as given by FailureReason
+const (
+	FailureReasonError   = "error"
+	FailureReasonTimeout = "timeout"
+	FailureReasonMemory  = "memory"
+)
+
+// FailureReason gives the reason of an extraction error, to tell the timeouts and the memory
+// limits apart from the errors of the extractors.
+func FailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrExtractionTimeout):
+		return FailureReasonTimeout
+	case errors.Is(err, ErrExtractionMemoryLimit):
+		return FailureReasonMemory
+	}
+	return FailureReasonError
+}
+
+// extractWithLimits runs the extraction of a document in the server process. As the extractors
+// can't be stopped, an extraction going over its timeout is left running in the background
+// until it ends, on a copy of the document the caller may close in the meantime. The memory
+// can't be limited either, so the documents larger than the memory limit are refused instead:
+// only a worker enforces the memory limit during the extraction.
+func extractWithLimits(extractor Extractor, filename string, r io.ReadSeeker, settings ExtractSettings, tracker *attemptTracker) (string, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", fmt.Errorf("error getting the size of the document: %w", err)
+	}
+	if settings.MaxMemory > 0 && size > settings.MaxMemory {
+		return "", fmt.Errorf("%w: the document is larger than %d bytes", ErrExtractionMemoryLimit, settings.MaxMemory)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error reading the document: %w", err)
+	}
+
+	if settings.Timeout <= 0 {
+		return extractor.Extract(filename, r)
+	}
+
+	document, release, err := copyDocument(r, settings.MaxMemory)
+	if err != nil {
+		return "", err
+	}
+
+	type result struct {
+		text string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer release()
+		text, err := extractor.Extract(filename, document)
+		done <- result{text, err}
+	}()
+
+	timer := time.NewTimer(settings.Timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		return res.text, res.err
+	case <-timer.C:
+		tracker.abort(ErrExtractionTimeout)
+		return "", ErrExtractionTimeout
+	}
+}
+
+// copyDocument copies a document for an extraction that may outlive its caller. The documents
+// are copied in memory when they are within the memory limit, and to a temporary file removed
+// by the returned function otherwise.
+func copyDocument(r io.Reader, maxMemory int64) (io.ReadSeeker, func(), error) {
+	if maxMemory > 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading the document: %w", err)
+		}
+		return bytes.NewReader(data), func() {}, nil
+	}
+
+	file, err := os.CreateTemp("", "docextractor-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating a copy of the document: %w", err)
+	}
+	release := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		release()
+		return nil, nil, fmt.Errorf("error copying the document: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		release()
+		return nil, nil, fmt.Errorf("error reading the document: %w", err)
+	}
+	// Hides Close from the extractors, as some of them close the documents they read
+	return struct{ io.ReadSeeker }{file}, release, nil
+}
+
+type extractionAttempt struct {
+	id        int
+	extractor string
+	started   time.Time
+}
+
+// attemptTracker keeps the extractors running on a document, to time them and to report the
+// ones interrupted by a timeout or by the memory limit.
+type attemptTracker struct {
+	onStart  func(attempt *extractionAttempt)
+	onFinish func(attempt *extractionAttempt, elapsed time.Duration, err error)
+
+	mutex   sync.Mutex
+	nextID  int
+	running []*extractionAttempt
+	aborted bool
+}
+
+func newAttemptTracker(observer Observer) *attemptTracker {
+	tracker := &attemptTracker{}
+	if observer != nil {
+		tracker.onFinish = func(attempt *extractionAttempt, elapsed time.Duration, err error) {
+			observer(attempt.extractor, elapsed, err)
+		}
+	}
+	return tracker
+}
+
+func (t *attemptTracker) start(extractor string) *extractionAttempt {
+	return t.startAt(extractor, time.Now())
+}
+
+func (t *attemptTracker) startAt(extractor string, started time.Time) *extractionAttempt {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	attempt := &extractionAttempt{id: t.nextID, extractor: extractor, started: started}
+	t.nextID++
+	t.running = append(t.running, attempt)
+	t.mutex.Unlock()
+
+	if t.onStart != nil {
+		t.onStart(attempt)
+	}
+	return attempt
+}
+
+func (t *attemptTracker) finish(attempt *extractionAttempt, err error) {
+	if attempt == nil {
+		return
+	}
+	t.finishAfter(attempt, time.Since(attempt.started), err)
+}
+
+// finishAfter reports an attempt with the given duration, unless the attempts were aborted.
+func (t *attemptTracker) finishAfter(attempt *extractionAttempt, elapsed time.Duration, err error) {
+	if t == nil || attempt == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	index := slices.Index(t.running, attempt)
+	if t.aborted || index < 0 {
+		t.mutex.Unlock()
+		return
+	}
+	t.running = slices.Delete(t.running, index, index+1)
+	t.mutex.Unlock()
+
+	if t.onFinish != nil {
+		t.onFinish(attempt, elapsed, err)
+	}
+}
+
+// abort reports the attempts still running with the given error, the innermost first, and
+// ignores the ones finishing afterwards.
+func (t *attemptTracker) abort(err error) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	running := t.running
+	t.running = nil
+	t.aborted = true
+	t.mutex.Unlock()
+
+	if t.onFinish == nil {
+		return
+	}
+	for i := len(running) - 1; i >= 0; i-- {
+		t.onFinish(running[i], time.Since(running[i].started), err)
+	}
+}
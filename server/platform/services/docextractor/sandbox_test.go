@@ -0,0 +1,259 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+)
+
+// testWorkerArg makes the test binary run as a content extraction worker.
+const testWorkerArg = "docextractor-test-worker"
+
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[len(os.Args)-1] == testWorkerArg {
+		if err := RunWorker(os.Stdin, os.Stdout); err != nil {
+			os.Stderr.WriteString(err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+type slowExtractor struct {
+	done chan struct{}
+}
+
+func (se *slowExtractor) Name() string {
+	return "slowExtractor"
+}
+
+func (se *slowExtractor) Match(filename string) bool {
+	return strings.HasSuffix(filename, ".slow")
+}
+
+func (se *slowExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	<-se.done
+	return "too late", nil
+}
+
+// readingExtractor reads the document once released.
+type readingExtractor struct {
+	release chan struct{}
+	read    chan string
+}
+
+func (re *readingExtractor) Name() string {
+	return "readingExtractor"
+}
+
+func (re *readingExtractor) Match(filename string) bool {
+	return strings.HasSuffix(filename, ".slow")
+}
+
+func (re *readingExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	<-re.release
+	data, err := io.ReadAll(r)
+	if err != nil {
+		re.read <- err.Error()
+	} else {
+		re.read <- string(data)
+	}
+	return "", nil
+}
+
+type observedAttempt struct {
+	extractor string
+	err       error
+}
+
+type testObserver struct {
+	mutex    sync.Mutex
+	attempts []observedAttempt
+}
+
+func (to *testObserver) observe(extractor string, elapsed time.Duration, err error) {
+	to.mutex.Lock()
+	defer to.mutex.Unlock()
+	to.attempts = append(to.attempts, observedAttempt{extractor, err})
+}
+
+func (to *testObserver) get() []observedAttempt {
+	to.mutex.Lock()
+	defer to.mutex.Unlock()
+	return append([]observedAttempt{}, to.attempts...)
+}
+
+func TestExtractWithLimits(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("observes the extractors tried", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.pdf")
+		require.NoError(t, err)
+
+		observer := &testObserver{}
+		settings := ExtractSettings{Timeout: time.Minute, Observer: observer.observe}
+		text, err := ExtractWithExtraExtractors(logger, "sample-doc.pdf", bytes.NewReader(data), settings, []Extractor{&failingExtractor{}})
+		require.NoError(t, err)
+		assert.Contains(t, text, "simple")
+
+		// The documentExtractor may also be tried, depending on the tools installed
+		attempts := observer.get()
+		require.GreaterOrEqual(t, len(attempts), 2)
+		assert.Equal(t, "failingExtractor", attempts[0].extractor)
+		assert.Error(t, attempts[0].err)
+		assert.Equal(t, "pdfExtractor", attempts[len(attempts)-1].extractor)
+		assert.NoError(t, attempts[len(attempts)-1].err)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		extractor := &slowExtractor{done: make(chan struct{})}
+		defer close(extractor.done)
+
+		observer := &testObserver{}
+		settings := ExtractSettings{Timeout: 50 * time.Millisecond, Observer: observer.observe}
+		text, err := ExtractWithExtraExtractors(logger, "file.slow", bytes.NewReader([]byte("content")), settings, []Extractor{extractor})
+		require.ErrorIs(t, err, ErrExtractionTimeout)
+		assert.Empty(t, text)
+		assert.Equal(t, FailureReasonTimeout, FailureReason(err))
+
+		require.Len(t, observer.get(), 1)
+		assert.Equal(t, "slowExtractor", observer.get()[0].extractor)
+		assert.ErrorIs(t, observer.get()[0].err, ErrExtractionTimeout)
+	})
+
+	t.Run("reads a copy of the document after timing out", func(t *testing.T) {
+		for name, maxMemory := range map[string]int64{"in memory": 1024, "in a file": 0} {
+			t.Run(name, func(t *testing.T) {
+				file, err := os.CreateTemp(t.TempDir(), "document")
+				require.NoError(t, err)
+				_, err = file.WriteString("content")
+				require.NoError(t, err)
+
+				extractor := &readingExtractor{release: make(chan struct{}), read: make(chan string, 1)}
+				settings := ExtractSettings{Timeout: 50 * time.Millisecond, MaxMemory: maxMemory}
+				_, err = ExtractWithExtraExtractors(logger, "file.slow", file, settings, []Extractor{extractor})
+				require.ErrorIs(t, err, ErrExtractionTimeout)
+
+				// The caller closes the document once the extraction returns
+				require.NoError(t, file.Close())
+				close(extractor.release)
+				assert.Equal(t, "content", <-extractor.read)
+			})
+		}
+	})
+
+	t.Run("refuses the documents larger than the memory limit", func(t *testing.T) {
+		text, err := Extract(logger, "file.txt", bytes.NewReader([]byte("some plain text")), ExtractSettings{MaxMemory: 5})
+		require.ErrorIs(t, err, ErrExtractionMemoryLimit)
+		assert.Empty(t, text)
+		assert.Equal(t, FailureReasonMemory, FailureReason(err))
+
+		text, err = Extract(logger, "file.txt", bytes.NewReader([]byte("some plain text")), ExtractSettings{MaxMemory: 1024})
+		require.NoError(t, err)
+		assert.Equal(t, "some plain text", text)
+	})
+
+	t.Run("tells the other errors apart", func(t *testing.T) {
+		assert.Equal(t, FailureReasonError, FailureReason(errors.New("error")))
+	})
+}
+
+func TestExtractInWorker(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	workerCommand := []string{os.Args[0], testWorkerArg}
+
+	t.Run("extracts the text", func(t *testing.T) {
+		data, err := testutils.ReadTestFile("sample-doc.pdf")
+		require.NoError(t, err)
+
+		observer := &testObserver{}
+		settings := ExtractSettings{
+			Timeout:       time.Minute,
+			MaxMemory:     1024 * 1024 * 1024,
+			WorkerCommand: workerCommand,
+			Observer:      observer.observe,
+		}
+		text, err := Extract(logger, "sample-doc.pdf", bytes.NewReader(data), settings)
+		require.NoError(t, err)
+		assert.Contains(t, text, "simple")
+		assert.Contains(t, text, "document")
+
+		attempts := observer.get()
+		require.NotEmpty(t, attempts)
+		assert.Equal(t, "pdfExtractor", attempts[len(attempts)-1].extractor)
+		assert.NoError(t, attempts[len(attempts)-1].err)
+	})
+
+	t.Run("goes over the memory limit", func(t *testing.T) {
+		settings := ExtractSettings{
+			MaxMemory:     1024,
+			WorkerCommand: workerCommand,
+		}
+		text, err := Extract(logger, "file.txt", bytes.NewReader([]byte("some plain text")), settings)
+		require.ErrorIs(t, err, ErrExtractionMemoryLimit)
+		assert.Empty(t, text)
+	})
+
+	t.Run("fails to start", func(t *testing.T) {
+		settings := ExtractSettings{
+			WorkerCommand: []string{os.Args[0] + "-missing"},
+		}
+		_, err := Extract(logger, "file.txt", bytes.NewReader([]byte("some plain text")), settings)
+		require.Error(t, err)
+		assert.Equal(t, FailureReasonError, FailureReason(err))
+	})
+}
+
+func TestReplayWorkerEvents(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	t.Run("reports the interrupted extractors", func(t *testing.T) {
+		var events bytes.Buffer
+		started := time.Now().Add(-time.Second)
+		events.WriteString(`{"type":"start","id":0,"extractor":"archiveExtractor","started":"` + started.Format(time.RFC3339Nano) + `"}` + "\n")
+		events.WriteString(`{"type":"start","id":1,"extractor":"pdfExtractor","started":"` + started.Format(time.RFC3339Nano) + `"}` + "\n")
+
+		observer := &testObserver{}
+		tracker := newAttemptTracker(observer.observe)
+		_, hasResult, err := replayWorkerEvents(logger, "file.zip", &events, tracker)
+		require.NoError(t, err)
+		assert.False(t, hasResult)
+		assert.Empty(t, observer.get())
+
+		tracker.abort(ErrExtractionTimeout)
+		require.Len(t, observer.get(), 2)
+		assert.Equal(t, "pdfExtractor", observer.get()[0].extractor)
+		assert.Equal(t, "archiveExtractor", observer.get()[1].extractor)
+		assert.ErrorIs(t, observer.get()[1].err, ErrExtractionTimeout)
+	})
+
+	t.Run("gets the result", func(t *testing.T) {
+		events := bytes.NewBufferString(`{"type":"start","id":0,"extractor":"plainExtractor"}` + "\n" +
+			`{"type":"finish","id":0,"extractor":"plainExtractor","duration":1000}` + "\n" +
+			`{"type":"result","text":"some text"}` + "\n")
+
+		observer := &testObserver{}
+		text, hasResult, err := replayWorkerEvents(logger, "file.txt", events, newAttemptTracker(observer.observe))
+		require.NoError(t, err)
+		assert.True(t, hasResult)
+		assert.Equal(t, "some text", text)
+		require.Len(t, observer.get(), 1)
+		assert.Equal(t, "plainExtractor", observer.get()[0].extractor)
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// spreadsheetExtractor extracts the sheet names and the cell values of Office
+// Open XML spreadsheets, streaming the sheets so that only the shared strings
+// are kept in memory.
+type spreadsheetExtractor struct {
+	Limits formatLimits
+}
+
+func (se *spreadsheetExtractor) Name() string {
+	return "spreadsheetExtractor"
+}
+
+func (se *spreadsheetExtractor) Match(filename string) bool {
+	return strings.ToLower(path.Ext(filename)) == ".xlsx"
+}
+
+func (se *spreadsheetExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	zr, err := openZipLimited(r, se.Limits.MaxSize)
+	if err != nil {
+		return "", err
+	}
+
+	sheets, err := se.readSheets(zr)
+	if err != nil {
+		return "", err
+	}
+
+	sharedStrings, err := se.readSharedStrings(zr)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	cells := 0
+	for _, sheet := range sheets {
+		text.WriteString(sheet.name + "\n")
+		if err := se.readSheet(zr, sheet.path, sharedStrings, &text, &cells); err != nil {
+			return text.String(), nil
+		}
+		if cells >= se.Limits.MaxCells {
+			break
+		}
+	}
+
+	return text.String(), nil
+}
+
+type spreadsheetSheet struct {
+	name string
+	path string
+}
+
+// readSheets gets the names and the paths of the sheets, in their order in
+// the workbook.
+func (se *spreadsheetExtractor) readSheets(zr *zip.Reader) ([]spreadsheetSheet, error) {
+	var rels struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := se.decodePart(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.Id] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.Id] = path.Join("xl", rel.Target)
+		}
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			Id   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := se.decodePart(zr, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	sheets := make([]spreadsheetSheet, 0, len(workbook.Sheets))
+	for _, sheet := range workbook.Sheets {
+		if target, ok := targets[sheet.Id]; ok {
+			sheets = append(sheets, spreadsheetSheet{name: sheet.Name, path: target})
+		}
+	}
+	return sheets, nil
+}
+
+func (se *spreadsheetExtractor) decodePart(zr *zip.Reader, name string, v any) error {
+	part, err := openZipPart(zr, name, se.Limits.MaxSize)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+
+	return xml.NewDecoder(part).Decode(v)
+}
+
+// readSharedStrings gets the strings the cells refer to by index, joining the
+// runs of the rich text ones.
+func (se *spreadsheetExtractor) readSharedStrings(zr *zip.Reader) ([]string, error) {
+	part, err := openZipPart(zr, "xl/sharedStrings.xml", se.Limits.MaxSize)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	var sharedStrings []string
+	var current strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(part)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// The strings read before a part cut by the size limit are kept
+			return sharedStrings, nil
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				// Skip the phonetic guides of the East Asian strings
+				if err := decoder.Skip(); err != nil {
+					return sharedStrings, nil
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				sharedStrings = append(sharedStrings, current.String())
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+// readSheet writes the values of the cells of a sheet, one row per line, until
+// the cells limit is reached.
+func (se *spreadsheetExtractor) readSheet(zr *zip.Reader, name string, sharedStrings []string, text *strings.Builder, cells *int) error {
+	part, err := openZipPart(zr, name, se.Limits.MaxSize)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+
+	var cellType string
+	var value strings.Builder
+	inValue := false
+	rowCells := 0
+	decoder := xml.NewDecoder(part)
+	for *cells < se.Limits.MaxCells {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				rowCells = 0
+			case "c":
+				cellType = ""
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "t" {
+						cellType = attr.Value
+					}
+				}
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			case "f":
+				// Formulas aren't part of the values
+				if err := decoder.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				cellValue := spreadsheetCellValue(cellType, value.String(), sharedStrings)
+				if cellValue == "" {
+					continue
+				}
+				if rowCells > 0 {
+					text.WriteString("\t")
+				}
+				text.WriteString(cellValue)
+				rowCells++
+				*cells++
+			case "row":
+				if rowCells > 0 {
+					text.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+
+	if rowCells > 0 {
+		text.WriteString("\n")
+	}
+	return nil
+}
+
+func spreadsheetCellValue(cellType, value string, sharedStrings []string) string {
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || index < 0 || index >= len(sharedStrings) {
+			return ""
+		}
+		return sharedStrings[index]
+	case "b":
+		if strings.TrimSpace(value) == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return strings.TrimSpace(value)
+}
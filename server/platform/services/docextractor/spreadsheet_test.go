@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func makeZipFile(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func makeXlsxFile(t *testing.T) []byte {
+	return makeZipFile(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Budget" sheetId="1" r:id="rId1"/><sheet name="Notes" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Travel</t></si><si><r><t>Office </t></r><r><t>supplies</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1"><v>1200</v></c></row>
+<row r="2"><c r="A2" t="s"><v>1</v></c><c r="B2"><f>SUM(B1:B1)</f><v>350</v></c><c r="C2" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Approved by finance</t></is></c></row>
+</sheetData></worksheet>`,
+	})
+}
+
+func TestSpreadsheetExtractor(t *testing.T) {
+	data := makeXlsxFile(t)
+
+	t.Run("extracts the sheets", func(t *testing.T) {
+		extractor := spreadsheetExtractor{Limits: spreadsheetLimits}
+		require.True(t, extractor.Match("budget.XLSX"))
+
+		text, err := extractor.Extract("budget.xlsx", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, "Budget\nTravel\t1200\nOffice supplies\t350\tTRUE\nNotes\nApproved by finance\n", text)
+	})
+
+	t.Run("stops at the cells limit", func(t *testing.T) {
+		extractor := spreadsheetExtractor{Limits: formatLimits{MaxSize: spreadsheetLimits.MaxSize, MaxCells: 3}}
+		text, err := extractor.Extract("budget.xlsx", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, "Budget\nTravel\t1200\nOffice supplies\n", text)
+	})
+
+	t.Run("fails on the files over the size limit", func(t *testing.T) {
+		extractor := spreadsheetExtractor{Limits: formatLimits{MaxSize: 100, MaxCells: spreadsheetLimits.MaxCells}}
+		_, err := extractor.Extract("budget.xlsx", bytes.NewReader(data))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// WorkerCommandName is the command of the server binary running a content extraction worker.
+const WorkerCommandName = "extract_content_worker"
+
+const (
+	// workerExitMemoryLimit is the exit code of a worker going over its memory limit.
+	workerExitMemoryLimit = 3
+
+	workerMemoryCheckInterval = 50 * time.Millisecond
+	workerWaitDelay           = time.Second
+	workerStderrMaxLength     = 512
+)
+
+const (
+	workerEventStart  = "start"
+	workerEventFinish = "finish"
+	workerEventResult = "result"
+)
+
+// workerRequest is the first line a worker reads, followed by the content of the document.
+type workerRequest struct {
+	Filename         string `json:"filename"`
+	ArchiveRecursion bool   `json:"archive_recursion"`
+	MMPreviewURL     string `json:"mm_preview_url"`
+	MMPreviewSecret  string `json:"mm_preview_secret"`
+	MaxMemory        int64  `json:"max_memory"`
+}
+
+// workerEvent is written by a worker, one per line, when an extractor starts and finishes,
+// and with the text of the document at last.
+type workerEvent struct {
+	Type      string        `json:"type"`
+	ID        int           `json:"id,omitempty"`
+	Extractor string        `json:"extractor,omitempty"`
+	Started   time.Time     `json:"started,omitzero"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Text      string        `json:"text,omitempty"`
+}
+
+// extractInWorker runs the extraction of a document in a subprocess, which is killed when
+// going over the timeout, and exits by itself when going over the memory limit.
+func extractInWorker(logger mlog.LoggerIFace, filename string, r io.ReadSeeker, settings ExtractSettings, tracker *attemptTracker) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error reading the document: %w", err)
+	}
+
+	request, err := json.Marshal(workerRequest{
+		Filename:         filename,
+		ArchiveRecursion: settings.ArchiveRecursion,
+		MMPreviewURL:     settings.MMPreviewURL,
+		MMPreviewSecret:  settings.MMPreviewSecret,
+		MaxMemory:        settings.MaxMemory,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding the worker request: %w", err)
+	}
+
+	ctx := context.Background()
+	if settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, settings.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, settings.WorkerCommand[0], settings.WorkerCommand[1:]...)
+	cmd.Stdin = io.MultiReader(bytes.NewReader(append(request, '\n')), r)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = workerWaitDelay
+	runErr := cmd.Run()
+
+	text, hasResult, extractErr := replayWorkerEvents(logger, filename, &stdout, tracker)
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = ErrExtractionTimeout
+	case errors.As(runErr, &exitErr) && exitErr.ExitCode() == workerExitMemoryLimit:
+		err = ErrExtractionMemoryLimit
+	case runErr != nil:
+		output := stderr.Bytes()
+		if len(output) > workerStderrMaxLength {
+			output = output[len(output)-workerStderrMaxLength:]
+		}
+		err = fmt.Errorf("content extraction worker failed: %w: %s", runErr, bytes.TrimSpace(output))
+	case !hasResult:
+		err = errors.New("content extraction worker returned no result")
+	default:
+		return text, extractErr
+	}
+
+	tracker.abort(err)
+	return "", err
+}
+
+// replayWorkerEvents reports the extractors tried by a worker to the tracker, and gets the text
+// of the document when the worker got to the end of the extraction.
+func replayWorkerEvents(logger mlog.LoggerIFace, filename string, r io.Reader, tracker *attemptTracker) (string, bool, error) {
+	attempts := map[int]*extractionAttempt{}
+	decoder := json.NewDecoder(r)
+	for {
+		var event workerEvent
+		if err := decoder.Decode(&event); err != nil {
+			// The events written before the worker was killed are kept
+			return "", false, nil
+		}
+
+		switch event.Type {
+		case workerEventStart:
+			attempts[event.ID] = tracker.startAt(event.Extractor, event.Started)
+		case workerEventFinish:
+			var err error
+			if event.Error != "" {
+				err = errors.New(event.Error)
+				logger.Warn("Unable to extract file content", mlog.String("file_name", filename), mlog.String("extractor", event.Extractor), mlog.Err(err))
+			}
+			tracker.finishAfter(attempts[event.ID], event.Duration, err)
+		case workerEventResult:
+			if event.Error != "" {
+				return event.Text, true, errors.New(event.Error)
+			}
+			return event.Text, true, nil
+		}
+	}
+}
+
+// RunWorker extracts the content of a document sent by a server running the extractions in a
+// worker, writing the extractors tried and the text of the document to w. The process exits
+// with a dedicated code when it goes over the memory limit it is given.
+func RunWorker(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("error reading the worker request: %w", err)
+	}
+
+	var request workerRequest
+	if err = json.Unmarshal(line, &request); err != nil {
+		return fmt.Errorf("error decoding the worker request: %w", err)
+	}
+
+	if request.MaxMemory > 0 {
+		debug.SetMemoryLimit(request.MaxMemory)
+		checkWorkerMemory(request.MaxMemory)
+		go func() {
+			for range time.Tick(workerMemoryCheckInterval) {
+				checkWorkerMemory(request.MaxMemory)
+			}
+		}()
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("error reading the document: %w", err)
+	}
+
+	// The warnings of the extractors are logged by the server, from the events
+	logger, err := mlog.NewLogger()
+	if err != nil {
+		return err
+	}
+	defer logger.Shutdown()
+
+	encoder := json.NewEncoder(w)
+	tracker := &attemptTracker{
+		onStart: func(attempt *extractionAttempt) {
+			encoder.Encode(workerEvent{Type: workerEventStart, ID: attempt.id, Extractor: attempt.extractor, Started: attempt.started})
+		},
+		onFinish: func(attempt *extractionAttempt, elapsed time.Duration, err error) {
+			event := workerEvent{Type: workerEventFinish, ID: attempt.id, Extractor: attempt.extractor, Duration: elapsed}
+			if err != nil {
+				event.Error = err.Error()
+			}
+			encoder.Encode(event)
+		},
+	}
+
+	settings := ExtractSettings{
+		ArchiveRecursion: request.ArchiveRecursion,
+		MMPreviewURL:     request.MMPreviewURL,
+		MMPreviewSecret:  request.MMPreviewSecret,
+	}
+	result := workerEvent{Type: workerEventResult}
+	enabledExtractors := newEnabledExtractors(logger, settings, nil, tracker)
+	if enabledExtractors.Match(request.Filename) {
+		text, err := enabledExtractors.Extract(request.Filename, bytes.NewReader(data))
+		result.Text = text
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	return encoder.Encode(result)
+}
+
+// checkWorkerMemory exits the worker when the memory it got from the system, and didn't give
+// back, goes over the limit.
+func checkWorkerMemory(limit int64) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+
+	used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	if used > uint64(limit) {
+		fmt.Fprintf(os.Stderr, "content extraction worker went over its memory limit: %d bytes used, %d bytes allowed\n", used, limit)
+		os.Exit(workerExitMemoryLimit)
+	}
+}
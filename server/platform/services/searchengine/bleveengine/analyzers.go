@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/analysis/lang/de"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/analysis/lang/es"
+	"github.com/blevesearch/bleve/v2/analysis/lang/fr"
+	"github.com/blevesearch/bleve/v2/analysis/lang/it"
+	"github.com/blevesearch/bleve/v2/analysis/lang/nl"
+	"github.com/blevesearch/bleve/v2/analysis/lang/pt"
+	"github.com/blevesearch/bleve/v2/analysis/lang/ru"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/token/porter"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/exception"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	numericTokenizerName  = "mm_numeric"
+	numericAnalyzerPrefix = "mm_numeric_"
+
+	// numericTokenPattern matches numbers with thousands or decimal separators,
+	// an optional currency symbol and an optional percent sign, so that values
+	// such as "1,234", "$100" or "12.5%" are kept as a single token.
+	numericTokenPattern = `\p{Sc}?\b\d+(?:[.,]\d+)*\b%?`
+)
+
+// analyzerTokenFilters holds the token filters of each configurable analyzer.
+// They are the filters of the Bleve analyzer of the same name, which is used
+// as is unless numeric tokens are preserved.
+var analyzerTokenFilters = map[string][]string{
+	model.BleveAnalyzerStandard:   {lowercase.Name, en.StopName},
+	model.BleveAnalyzerCJK:        {cjk.WidthName, lowercase.Name, cjk.BigramName},
+	model.BleveAnalyzerEnglish:    {en.PossessiveName, lowercase.Name, en.StopName, porter.Name},
+	model.BleveAnalyzerGerman:     {lowercase.Name, de.StopName, de.NormalizeName, de.LightStemmerName},
+	model.BleveAnalyzerSpanish:    {lowercase.Name, es.NormalizeName, es.StopName, es.LightStemmerName},
+	model.BleveAnalyzerFrench:     {fr.ElisionName, lowercase.Name, fr.StopName, fr.LightStemmerName},
+	model.BleveAnalyzerItalian:    {it.ElisionName, lowercase.Name, it.StopName, it.LightStemmerName},
+	model.BleveAnalyzerDutch:      {lowercase.Name, nl.StopName, nl.SnowballStemmerName},
+	model.BleveAnalyzerPortuguese: {lowercase.Name, pt.StopName, pt.LightStemmerName},
+	model.BleveAnalyzerRussian:    {lowercase.Name, ru.StopName, ru.SnowballStemmerName},
+}
+
+// addTextAnalyzer registers the analyzer configured for an index in its mapping
+// and returns the name the text fields of the index should be analyzed with.
+func addTextAnalyzer(indexMapping *mapping.IndexMappingImpl, analyzer string, preserveNumericTokens bool) (string, error) {
+	tokenFilters, ok := analyzerTokenFilters[analyzer]
+	if !ok {
+		return "", fmt.Errorf("unknown analyzer %q", analyzer)
+	}
+
+	if !preserveNumericTokens {
+		return analyzer, nil
+	}
+
+	if err := indexMapping.AddCustomTokenizer(numericTokenizerName, map[string]any{
+		"type":       exception.Name,
+		"exceptions": []string{numericTokenPattern},
+		"tokenizer":  unicode.Name,
+	}); err != nil {
+		return "", err
+	}
+
+	name := numericAnalyzerPrefix + analyzer
+	if err := indexMapping.AddCustomAnalyzer(name, map[string]any{
+		"type":          custom.Name,
+		"tokenizer":     numericTokenizerName,
+		"token_filters": tokenFilters,
+	}); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// isDefaultTextAnalysis reports whether the text of an index is analyzed the
+// same way as the fields the index mapping doesn't declare.
+func isDefaultTextAnalysis(analyzer string, preserveNumericTokens bool) bool {
+	return analyzer == model.BleveAnalyzerStandard && !preserveNumericTokens
+}
+
+func newTextFieldMapping(analyzer string) *mapping.FieldMapping {
+	fieldMapping := mapping.NewTextFieldMapping()
+	fieldMapping.Analyzer = analyzer
+	return fieldMapping
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+func newAnalyzerTestEngine(t *testing.T, updateSettings func(*model.BleveSettings)) *BleveEngine {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.BleveSettings.EnableIndexing = model.NewPointer(true)
+	cfg.BleveSettings.IndexDir = model.NewPointer(t.TempDir())
+	if updateSettings != nil {
+		updateSettings(&cfg.BleveSettings)
+	}
+	return NewBleveEngine(cfg)
+}
+
+func searchMessages(t *testing.T, index bleve.Index, terms string) []string {
+	messageQ := bleve.NewMatchQuery(terms)
+	messageQ.SetField("Message")
+	messageQ.SetOperator(query.MatchQueryOperatorAnd)
+
+	results, err := index.Search(bleve.NewSearchRequest(messageQ))
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, hit := range results.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestTextAnalyzers(t *testing.T) {
+	posts := map[string]string{
+		"price":   "The price went up to $100 yesterday",
+		"amount":  "We sold 1,234 units, about 12.5% more",
+		"chinese": "今天我們討論搜尋功能",
+		"version": "Upgraded to v2 of the API",
+	}
+
+	indexPosts := func(t *testing.T, engine *BleveEngine) {
+		for id, message := range posts {
+			require.NoError(t, engine.PostIndex.Index(id, &BLVPost{Id: id, Message: message}))
+		}
+	}
+
+	t.Run("standard analyzer", func(t *testing.T) {
+		engine := newAnalyzerTestEngine(t, nil)
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+		indexPosts(t, engine)
+
+		assert.Equal(t, []string{"price"}, searchMessages(t, engine.PostIndex, "100"))
+		// Each ideograph is a token, so any text with the same characters matches
+		assert.Equal(t, []string{"chinese"}, searchMessages(t, engine.PostIndex, "尋搜"))
+	})
+
+	t.Run("cjk analyzer", func(t *testing.T) {
+		engine := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+			settings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerCJK)
+		})
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+		indexPosts(t, engine)
+
+		assert.Equal(t, []string{"chinese"}, searchMessages(t, engine.PostIndex, "搜尋"))
+		assert.Equal(t, []string{"chinese"}, searchMessages(t, engine.PostIndex, "討論搜尋"))
+		assert.Empty(t, searchMessages(t, engine.PostIndex, "尋搜"))
+	})
+
+	t.Run("numeric tokens are kept intact", func(t *testing.T) {
+		engine := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+			settings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerCJK)
+			settings.PreserveNumericTokens = model.NewPointer(true)
+		})
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+		indexPosts(t, engine)
+
+		assert.Equal(t, []string{"price"}, searchMessages(t, engine.PostIndex, "$100"))
+		assert.Empty(t, searchMessages(t, engine.PostIndex, "100"))
+		assert.Equal(t, []string{"amount"}, searchMessages(t, engine.PostIndex, "1,234"))
+		assert.Equal(t, []string{"amount"}, searchMessages(t, engine.PostIndex, "12.5%"))
+		assert.Equal(t, []string{"version"}, searchMessages(t, engine.PostIndex, "v2"))
+		assert.Equal(t, []string{"chinese"}, searchMessages(t, engine.PostIndex, "搜尋"))
+	})
+
+	t.Run("language stemmer", func(t *testing.T) {
+		engine := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+			settings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerEnglish)
+		})
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+		indexPosts(t, engine)
+
+		assert.Equal(t, []string{"version"}, searchMessages(t, engine.PostIndex, "upgrading"))
+	})
+}
+
+func TestPostTextAnalyzer(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+		settings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerEnglish)
+	})
+	assert.Nil(t, engine.PostTextAnalyzer())
+
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	analyzer := engine.PostTextAnalyzer()
+	require.NotNil(t, analyzer)
+	assert.Equal(t, analyzer("upgrading"), analyzer("Upgraded"))
+	assert.Empty(t, analyzer("the"))
+
+	post := &model.Post{Message: "Upgraded to v2 of the API"}
+	assert.True(t, model.ParseSearchParams("upgrading api", 0)[0].MatchesPost(post, analyzer))
+	assert.False(t, model.ParseSearchParams("upgrading api", 0)[0].MatchesPost(post, nil))
+}
+
+func TestOutdatedIndexes(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	require.NoError(t, engine.PostIndex.Index("post", &BLVPost{Id: "post", Message: "搜尋功能"}))
+	assert.Empty(t, engine.OutdatedIndexes())
+
+	t.Run("reopening with the same settings keeps the indexes", func(t *testing.T) {
+		require.Nil(t, engine.Stop())
+		require.Nil(t, engine.Start())
+		assert.Empty(t, engine.OutdatedIndexes())
+	})
+
+	t.Run("changing the analysis marks the indexes as outdated", func(t *testing.T) {
+		cfg := engine.cfg.Clone()
+		cfg.BleveSettings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerCJK)
+		cfg.BleveSettings.UserIndexAnalyzer = model.NewPointer(model.BleveAnalyzerCJK)
+		engine.UpdateConfig(cfg)
+
+		assert.Equal(t, []string{PostIndex, UserIndex}, engine.OutdatedIndexes())
+
+		// Searches keep using the mapping the index was created with
+		assert.Equal(t, []string{"post"}, searchMessages(t, engine.PostIndex, "尋搜"))
+	})
+
+	t.Run("rebuilding applies the new mapping", func(t *testing.T) {
+		rebuilt, appErr := engine.RebuildOutdatedIndexes(request.TestContext(t))
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{PostIndex, UserIndex}, rebuilt)
+		assert.Empty(t, engine.OutdatedIndexes())
+
+		count, err := engine.PostIndex.DocCount()
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		require.NoError(t, engine.PostIndex.Index("post", &BLVPost{Id: "post", Message: "搜尋功能"}))
+		assert.Equal(t, []string{"post"}, searchMessages(t, engine.PostIndex, "搜尋"))
+		assert.Empty(t, searchMessages(t, engine.PostIndex, "尋搜"))
+
+		require.Nil(t, engine.Stop())
+		require.Nil(t, engine.Start())
+		assert.Empty(t, engine.OutdatedIndexes())
+	})
+
+	require.Nil(t, engine.Stop())
+}
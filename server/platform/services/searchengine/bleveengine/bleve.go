@@ -4,13 +4,16 @@
 package bleveengine
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
@@ -39,11 +42,25 @@ type BleveEngine struct {
 	ready        int32
 	cfg          *model.Config
 	indexSync    bool
+
+	// outdatedIndexes holds the indexes whose mapping differs from the one
+	// built from the current configuration.
+	outdatedIndexes map[string]bool
+
+	// The cluster the indexes are shared with, see cluster.go.
+	cluster         Cluster
+	logger          mlog.LoggerIFace
+	clusterMut      sync.Mutex
+	ownerID         string
+	ownerKnown      chan struct{}
+	pendingSearches map[string]chan *clusterSearchResponse
+	clusterSearches chan struct{} // holds a token for every search of another node being run
 }
 
 var keywordMapping *mapping.FieldMapping
 var standardMapping *mapping.FieldMapping
 var dateMapping *mapping.FieldMapping
+var booleanMapping *mapping.FieldMapping
 
 func init() {
 	keywordMapping = bleve.NewTextFieldMapping()
@@ -53,9 +70,13 @@ func init() {
 	standardMapping.Analyzer = standard.Name
 
 	dateMapping = bleve.NewNumericFieldMapping()
+
+	booleanMapping = bleve.NewBooleanFieldMapping()
 }
 
-func getChannelIndexMapping() *mapping.IndexMappingImpl {
+func getChannelIndexMapping(settings model.BleveSettings) (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+
 	channelMapping := bleve.NewDocumentMapping()
 	channelMapping.AddFieldMappingsAt("Id", keywordMapping)
 	channelMapping.AddFieldMappingsAt("Type", keywordMapping)
@@ -64,48 +85,80 @@ func getChannelIndexMapping() *mapping.IndexMappingImpl {
 	channelMapping.AddFieldMappingsAt("UserIDs", keywordMapping)
 	channelMapping.AddFieldMappingsAt("TeamMemberIDs", keywordMapping)
 
-	indexMapping := bleve.NewIndexMapping()
+	// Fields missing from the mapping are indexed with the standard analyzer,
+	// so the name is only mapped when another analysis is configured. This
+	// keeps the mapping of the indexes created before it could be configured.
+	if !isDefaultTextAnalysis(*settings.ChannelIndexAnalyzer, *settings.PreserveNumericTokens) {
+		analyzer, err := addTextAnalyzer(indexMapping, *settings.ChannelIndexAnalyzer, *settings.PreserveNumericTokens)
+		if err != nil {
+			return nil, err
+		}
+		channelMapping.AddFieldMappingsAt("DisplayName", newTextFieldMapping(analyzer))
+	}
+
 	indexMapping.AddDocumentMapping("_default", channelMapping)
 
-	return indexMapping
+	return indexMapping, nil
 }
 
-func getPostIndexMapping() *mapping.IndexMappingImpl {
+func getPostIndexMapping(settings model.BleveSettings) (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+
+	analyzer, err := addTextAnalyzer(indexMapping, *settings.PostIndexAnalyzer, *settings.PreserveNumericTokens)
+	if err != nil {
+		return nil, err
+	}
+	textMapping := newTextFieldMapping(analyzer)
+
 	postMapping := bleve.NewDocumentMapping()
 	postMapping.AddFieldMappingsAt("Id", keywordMapping)
 	postMapping.AddFieldMappingsAt("TeamId", keywordMapping)
 	postMapping.AddFieldMappingsAt("ChannelId", keywordMapping)
 	postMapping.AddFieldMappingsAt("UserId", keywordMapping)
 	postMapping.AddFieldMappingsAt("CreateAt", dateMapping)
-	postMapping.AddFieldMappingsAt("Message", standardMapping)
+	postMapping.AddFieldMappingsAt("Message", textMapping)
 	postMapping.AddFieldMappingsAt("Type", keywordMapping)
 	postMapping.AddFieldMappingsAt("Hashtags", standardMapping)
-	postMapping.AddFieldMappingsAt("Attachments", standardMapping)
+	postMapping.AddFieldMappingsAt("Attachments", textMapping)
+	postMapping.AddFieldMappingsAt("HasFiles", booleanMapping)
+	postMapping.AddFieldMappingsAt("HasLink", booleanMapping)
+	postMapping.AddFieldMappingsAt("IsPinned", booleanMapping)
+	postMapping.AddFieldMappingsAt("IsThreadRoot", booleanMapping)
+	postMapping.AddFieldMappingsAt("Reactions", keywordMapping)
+	postMapping.AddFieldMappingsAt("Priority", keywordMapping)
 
-	indexMapping := bleve.NewIndexMapping()
 	indexMapping.AddDocumentMapping("_default", postMapping)
 
-	return indexMapping
+	return indexMapping, nil
 }
 
-func getFileIndexMapping() *mapping.IndexMappingImpl {
+func getFileIndexMapping(settings model.BleveSettings) (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+
+	analyzer, err := addTextAnalyzer(indexMapping, *settings.FileIndexAnalyzer, *settings.PreserveNumericTokens)
+	if err != nil {
+		return nil, err
+	}
+	textMapping := newTextFieldMapping(analyzer)
+
 	fileMapping := bleve.NewDocumentMapping()
 	fileMapping.AddFieldMappingsAt("Id", keywordMapping)
 	fileMapping.AddFieldMappingsAt("CreatorId", keywordMapping)
 	fileMapping.AddFieldMappingsAt("ChannelId", keywordMapping)
 	fileMapping.AddFieldMappingsAt("CreateAt", dateMapping)
-	fileMapping.AddFieldMappingsAt("Name", standardMapping)
-	fileMapping.AddFieldMappingsAt("Content", standardMapping)
+	fileMapping.AddFieldMappingsAt("Name", textMapping)
+	fileMapping.AddFieldMappingsAt("Content", textMapping)
 	fileMapping.AddFieldMappingsAt("Extension", keywordMapping)
-	fileMapping.AddFieldMappingsAt("Content", standardMapping)
+	fileMapping.AddFieldMappingsAt("Content", textMapping)
 
-	indexMapping := bleve.NewIndexMapping()
 	indexMapping.AddDocumentMapping("_default", fileMapping)
 
-	return indexMapping
+	return indexMapping, nil
 }
 
-func getUserIndexMapping() *mapping.IndexMappingImpl {
+func getUserIndexMapping(settings model.BleveSettings) (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+
 	userMapping := bleve.NewDocumentMapping()
 	userMapping.AddFieldMappingsAt("Id", keywordMapping)
 	userMapping.AddFieldMappingsAt("SuggestionsWithFullname", keywordMapping)
@@ -113,15 +166,60 @@ func getUserIndexMapping() *mapping.IndexMappingImpl {
 	userMapping.AddFieldMappingsAt("TeamsIds", keywordMapping)
 	userMapping.AddFieldMappingsAt("ChannelsIds", keywordMapping)
 
-	indexMapping := bleve.NewIndexMapping()
+	// As for channels, the names are only mapped when another analysis than
+	// the one of the fields missing from the mapping is configured.
+	if !isDefaultTextAnalysis(*settings.UserIndexAnalyzer, *settings.PreserveNumericTokens) {
+		analyzer, err := addTextAnalyzer(indexMapping, *settings.UserIndexAnalyzer, *settings.PreserveNumericTokens)
+		if err != nil {
+			return nil, err
+		}
+		textMapping := newTextFieldMapping(analyzer)
+		userMapping.AddFieldMappingsAt("Nickname", textMapping)
+		userMapping.AddFieldMappingsAt("FullName", textMapping)
+	}
+
 	indexMapping.AddDocumentMapping("_default", userMapping)
 
-	return indexMapping
+	return indexMapping, nil
+}
+
+// getIndexMapping builds the mapping of an index from the text analysis
+// configured for it. Any change to a mapping, such as a new field, makes the
+// next indexing job empty and rebuild the existing indexes on upgrade.
+func getIndexMapping(indexName string, settings model.BleveSettings) (*mapping.IndexMappingImpl, error) {
+	switch indexName {
+	case PostIndex:
+		return getPostIndexMapping(settings)
+	case FileIndex:
+		return getFileIndexMapping(settings)
+	case UserIndex:
+		return getUserIndexMapping(settings)
+	case ChannelIndex:
+		return getChannelIndexMapping(settings)
+	}
+	return nil, fmt.Errorf("unknown index %q", indexName)
+}
+
+// indexMappingsEqual compares the mapping an index was created with to the
+// one it would be created with now.
+func indexMappingsEqual(current mapping.IndexMapping, expected *mapping.IndexMappingImpl) bool {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return false
+	}
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(currentJSON, expectedJSON)
 }
 
 func NewBleveEngine(cfg *model.Config) *BleveEngine {
 	return &BleveEngine{
-		cfg: cfg,
+		cfg:             cfg,
+		ownerKnown:      make(chan struct{}),
+		pendingSearches: map[string]chan *clusterSearchResponse{},
+		clusterSearches: make(chan struct{}, clusterSearchMaxConcurrent),
 	}
 }
 
@@ -129,9 +227,20 @@ func (b *BleveEngine) getIndexDir(indexName string) string {
 	return filepath.Join(*b.cfg.BleveSettings.IndexDir, indexName+".bleve")
 }
 
-func (b *BleveEngine) createOrOpenIndex(indexName string, mapping *mapping.IndexMappingImpl) (bleve.Index, error) {
+func (b *BleveEngine) createOrOpenIndex(indexName string) (bleve.Index, error) {
+	mapping, err := getIndexMapping(indexName, b.cfg.BleveSettings)
+	if err != nil {
+		return nil, err
+	}
+
 	indexPath := b.getIndexDir(indexName)
 	if index, err := bleve.Open(indexPath); err == nil {
+		// The index keeps being used with the mapping it was created with
+		// until an indexing job rebuilds it.
+		if !indexMappingsEqual(index.Mapping(), mapping) {
+			mlog.Warn("The mapping of the Bleve index has changed, run a Bleve indexing job to rebuild it", mlog.String("index", indexName))
+			b.outdatedIndexes[indexName] = true
+		}
 		return index, nil
 	}
 
@@ -150,23 +259,25 @@ func (b *BleveEngine) openIndexes() *model.AppError {
 		return model.NewAppError("Bleveengine.Start", "bleveengine.already_started.error", nil, "", http.StatusInternalServerError)
 	}
 
+	b.outdatedIndexes = map[string]bool{}
+
 	var err error
-	b.PostIndex, err = b.createOrOpenIndex(PostIndex, getPostIndexMapping())
+	b.PostIndex, err = b.createOrOpenIndex(PostIndex)
 	if err != nil {
 		return model.NewAppError("Bleveengine.Start", "bleveengine.create_post_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	b.FileIndex, err = b.createOrOpenIndex(FileIndex, getFileIndexMapping())
+	b.FileIndex, err = b.createOrOpenIndex(FileIndex)
 	if err != nil {
 		return model.NewAppError("Bleveengine.Start", "bleveengine.create_file_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	b.UserIndex, err = b.createOrOpenIndex(UserIndex, getUserIndexMapping())
+	b.UserIndex, err = b.createOrOpenIndex(UserIndex)
 	if err != nil {
 		return model.NewAppError("Bleveengine.Start", "bleveengine.create_user_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	b.ChannelIndex, err = b.createOrOpenIndex(ChannelIndex, getChannelIndexMapping())
+	b.ChannelIndex, err = b.createOrOpenIndex(ChannelIndex)
 	if err != nil {
 		return model.NewAppError("Bleveengine.Start", "bleveengine.create_channel_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -277,6 +388,12 @@ func (b *BleveEngine) PurgeIndexes(rctx request.CTX) *model.AppError {
 		return nil
 	}
 
+	b.replicate(&clusterIndexOperation{Type: indexOpPurgeIndexes})
+	return b.purgeIndexes(rctx)
+}
+
+func (b *BleveEngine) purgeIndexes(rctx request.CTX) *model.AppError {
+
 	b.Mutex.Lock()
 	defer b.Mutex.Unlock()
 
@@ -292,11 +409,122 @@ func (b *BleveEngine) PurgeIndexes(rctx request.CTX) *model.AppError {
 	return b.openIndexes()
 }
 
+// OutdatedIndexes returns the indexes that need to be rebuilt for the
+// configured text analysis to apply.
+func (b *BleveEngine) OutdatedIndexes() []string {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	indexes := make([]string, 0, len(b.outdatedIndexes))
+	for indexName := range b.outdatedIndexes {
+		indexes = append(indexes, indexName)
+	}
+	sort.Strings(indexes)
+	return indexes
+}
+
+// RebuildOutdatedIndexes replaces the outdated indexes by empty ones created
+// with the current mapping and returns their names. Their documents have to be
+// indexed again afterwards.
+func (b *BleveEngine) RebuildOutdatedIndexes(rctx request.CTX) ([]string, *model.AppError) {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+
+	if !b.IsActive() {
+		return nil, nil
+	}
+
+	indexes := make([]string, 0, len(b.outdatedIndexes))
+	for indexName := range b.outdatedIndexes {
+		indexes = append(indexes, indexName)
+	}
+	sort.Strings(indexes)
+
+	for _, indexName := range indexes {
+		rctx.Logger().Info("Rebuilding Bleve index with the new mapping", mlog.String("index", indexName))
+
+		if err := b.recreateIndex(indexName); err != nil {
+			return nil, model.NewAppError("Bleveengine.RebuildOutdatedIndexes", "bleveengine.rebuild_index.error", map[string]any{"Index": indexName}, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return indexes, nil
+}
+
+// recreateIndex replaces an open index by an empty one created with the
+// current mapping. It must be called with the mutex locked.
+func (b *BleveEngine) recreateIndex(indexName string) error {
+	index := b.getIndex(indexName)
+	if err := (*index).Close(); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(b.getIndexDir(indexName)); err != nil {
+		return err
+	}
+
+	newIndex, err := b.createOrOpenIndex(indexName)
+	if err != nil {
+		// The closed index can't be used anymore, so the engine stops
+		// until the indexes can be opened again
+		atomic.StoreInt32(&b.ready, 0)
+		return err
+	}
+	*index = newIndex
+	delete(b.outdatedIndexes, indexName)
+
+	return nil
+}
+
+func (b *BleveEngine) getIndex(indexName string) *bleve.Index {
+	switch indexName {
+	case PostIndex:
+		return &b.PostIndex
+	case FileIndex:
+		return &b.FileIndex
+	case UserIndex:
+		return &b.UserIndex
+	case ChannelIndex:
+		return &b.ChannelIndex
+	}
+	return nil
+}
+
+// PurgeIndexList replaces the given indexes, among posts, files, channels
+// and users, by empty ones.
 func (b *BleveEngine) PurgeIndexList(rctx request.CTX, indexes []string) *model.AppError {
-	return model.NewAppError("Bleve.PurgeIndex", "bleveengine.purge_list.not_implemented", nil, "not implemented", http.StatusNotFound)
+	if *b.cfg.BleveSettings.IndexDir == "" {
+		return nil
+	}
+
+	for _, indexName := range indexes {
+		if b.getIndex(indexName) == nil {
+			return model.NewAppError("Bleveengine.PurgeIndexList", "bleveengine.purge_list.unknown_index", map[string]any{"Index": indexName}, "", http.StatusBadRequest)
+		}
+	}
+
+	b.replicate(&clusterIndexOperation{Type: indexOpPurgeIndexList, Indexes: indexes})
+	return b.purgeIndexList(rctx, indexes)
 }
 
-func (b *BleveEngine) DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError {
+func (b *BleveEngine) purgeIndexList(rctx request.CTX, indexes []string) *model.AppError {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+
+	for _, indexName := range indexes {
+		rctx.Logger().Info("Purging Bleve index", mlog.String("index", indexName))
+
+		var err error
+		if b.IsActive() {
+			err = b.recreateIndex(indexName)
+		} else {
+			err = os.RemoveAll(b.getIndexDir(indexName))
+		}
+		if err != nil {
+			return model.NewAppError("Bleveengine.PurgeIndexList", "bleveengine.purge_index.error", map[string]any{"Index": indexName}, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
 	return nil
 }
 
@@ -312,6 +540,33 @@ func (b *BleveEngine) IsSearchEnabled() bool {
 	return *b.cfg.BleveSettings.EnableSearching
 }
 
+// PostTextAnalyzer returns the analysis of the messages of the posts index,
+// to match the posts that aren't indexed yet like a search of the index. It
+// returns nil while the engine isn't active.
+func (b *BleveEngine) PostTextAnalyzer() model.SearchTextAnalyzer {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	if !b.IsActive() {
+		return nil
+	}
+
+	indexMapping := b.PostIndex.Mapping()
+	analyzer := indexMapping.AnalyzerNamed(indexMapping.AnalyzerNameForPath("Message"))
+	if analyzer == nil {
+		return nil
+	}
+
+	return func(text string) []string {
+		tokens := analyzer.Analyze([]byte(text))
+		words := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			words = append(words, string(token.Term))
+		}
+		return words
+	}
+}
+
 func (b *BleveEngine) UpdateConfig(cfg *model.Config) {
 	b.Mutex.Lock()
 	defer b.Mutex.Unlock()
@@ -322,16 +577,24 @@ func (b *BleveEngine) UpdateConfig(cfg *model.Config) {
 
 	mlog.Info("UpdateConf Bleve")
 
-	if *cfg.BleveSettings.EnableIndexing != *b.cfg.BleveSettings.EnableIndexing || *cfg.BleveSettings.IndexDir != *b.cfg.BleveSettings.IndexDir {
+	if *cfg.BleveSettings.EnableIndexing != *b.cfg.BleveSettings.EnableIndexing || *cfg.BleveSettings.IndexDir != *b.cfg.BleveSettings.IndexDir || textAnalysisChanged(cfg.BleveSettings, b.cfg.BleveSettings) {
 		if err := b.closeIndexes(); err != nil {
 			mlog.Error("Error closing Bleve indexes to update the config", mlog.Err(err))
 			return
 		}
-		b.cfg = cfg
+		b.setConfig(cfg)
 		if err := b.openIndexes(); err != nil {
 			mlog.Error("Error opening Bleve indexes after updating the config", mlog.Err(err))
 		}
 		return
 	}
-	b.cfg = cfg
+	b.setConfig(cfg)
+}
+
+func textAnalysisChanged(settings, oldSettings model.BleveSettings) bool {
+	return *settings.PostIndexAnalyzer != *oldSettings.PostIndexAnalyzer ||
+		*settings.FileIndexAnalyzer != *oldSettings.FileIndexAnalyzer ||
+		*settings.ChannelIndexAnalyzer != *oldSettings.ChannelIndexAnalyzer ||
+		*settings.UserIndexAnalyzer != *oldSettings.UserIndexAnalyzer ||
+		*settings.PreserveNumericTokens != *oldSettings.PreserveNumericTokens
 }
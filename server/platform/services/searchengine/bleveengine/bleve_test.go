@@ -222,7 +222,7 @@ func (s *BleveEngineTestSuite) TestDeletePosts() {
 	query := bleve.NewTermQuery(userID)
 	query.SetField("UserId")
 	search := bleve.NewSearchRequest(query)
-	count, err := s.BleveEngine.deletePosts(search, 1)
+	count, err := s.BleveEngine.deletePosts(search, 1, nil)
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), 10, int(count))
 
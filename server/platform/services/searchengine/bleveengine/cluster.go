@@ -0,0 +1,635 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// In the leader cluster mode the indexes are shared by the nodes of the
+// cluster: every node applies the changes made to the indexes on any of them,
+// including the batches of the indexing jobs, to its own copy, and the leader
+// owns the indexes. The owner runs the indexing jobs and answers the searches
+// of the other nodes, so every node returns the same results. When the leader
+// changes, the new leader takes the ownership over with the copy it kept up
+// to date.
+
+const clusterSearchTimeout = 10 * time.Second
+
+// clusterSearchMaxConcurrent is the number of searches of the other nodes the
+// owner runs at once. The searches beyond it are rejected, and the nodes that
+// sent them fall back as they do when the owner doesn't answer in time.
+const clusterSearchMaxConcurrent = 32
+
+// clusterIndexBatchSize is the number of documents of a batch sent to the
+// other nodes in one cluster message.
+const clusterIndexBatchSize = 100
+
+// clusterOwnerWaitTimeout is how long a search waits for the owner to be
+// announced before running on the copy of the node instead.
+var clusterOwnerWaitTimeout = 2 * time.Second
+
+const (
+	indexOpIndexPost          = "index_post"
+	indexOpDeletePost         = "delete_post"
+	indexOpDeleteChannelPosts = "delete_channel_posts"
+	indexOpDeleteUserPosts    = "delete_user_posts"
+	indexOpIndexChannel       = "index_channel"
+	indexOpDeleteChannel      = "delete_channel"
+	indexOpIndexUser          = "index_user"
+	indexOpDeleteUser         = "delete_user"
+	indexOpIndexFile          = "index_file"
+	indexOpDeleteFile         = "delete_file"
+	indexOpDeletePostFiles    = "delete_post_files"
+	indexOpDeleteUserFiles    = "delete_user_files"
+	indexOpDeleteFilesBatch   = "delete_files_batch"
+	indexOpPurgeIndexes       = "purge_indexes"
+	indexOpPurgeIndexList     = "purge_index_list"
+	indexOpDeletePostsBefore  = "delete_posts_before"
+	indexOpDeleteFilesBefore  = "delete_files_before"
+	indexOpIndexDocuments     = "index_documents"
+
+	searchTypePosts          = "posts"
+	searchTypeFiles          = "files"
+	searchTypeChannels       = "channels"
+	searchTypeUsersInChannel = "users_in_channel"
+	searchTypeUsersInTeam    = "users_in_team"
+)
+
+// ClusterEvents are the cluster events the engine handles with HandleClusterMessage.
+var ClusterEvents = []model.ClusterEvent{
+	model.ClusterEventBleveIndexOperation,
+	model.ClusterEventBleveSearchRequest,
+	model.ClusterEventBleveSearchResponse,
+	model.ClusterEventBleveOwnerRequest,
+	model.ClusterEventBleveOwnerAnnouncement,
+}
+
+// Cluster is the part of the cluster interface the engine shares its indexes
+// through.
+type Cluster interface {
+	IsLeader() bool
+	GetMyClusterInfo() *model.ClusterInfo
+	GetClusterInfos() ([]*model.ClusterInfo, error)
+	SendClusterMessage(msg *model.ClusterMessage)
+	SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error
+}
+
+// clusterIndexOperation is a change made to the indexes of a node, which the
+// other nodes apply to their copy.
+type clusterIndexOperation struct {
+	Type    string      `json:"type"`
+	Post    *BLVPost    `json:"post,omitempty"`
+	Channel *BLVChannel `json:"channel,omitempty"`
+	User    *BLVUser    `json:"user,omitempty"`
+	File    *BLVFile    `json:"file,omitempty"`
+	Id      string      `json:"id,omitempty"`
+	EndTime int64       `json:"end_time,omitempty"`
+	Limit   int64       `json:"limit,omitempty"`
+	Indexes []string    `json:"indexes,omitempty"`
+
+	Documents *DocumentBatch `json:"documents,omitempty"`
+}
+
+// DocumentBatch holds the documents indexed and deleted in one batch of an
+// index, see IndexDocuments.
+type DocumentBatch struct {
+	Index      string        `json:"index"`
+	Posts      []*BLVPost    `json:"posts,omitempty"`
+	Files      []*BLVFile    `json:"files,omitempty"`
+	Channels   []*BLVChannel `json:"channels,omitempty"`
+	Users      []*BLVUser    `json:"users,omitempty"`
+	DeletedIds []string      `json:"deleted_ids,omitempty"`
+}
+
+type clusterSearchRequest struct {
+	Type                 string                   `json:"type"`
+	Channels             model.ChannelList        `json:"channels,omitempty"`
+	SearchParams         []*model.SearchParams    `json:"search_params,omitempty"`
+	Page                 int                      `json:"page,omitempty"`
+	PerPage              int                      `json:"per_page,omitempty"`
+	TeamId               string                   `json:"team_id,omitempty"`
+	ChannelId            string                   `json:"channel_id,omitempty"`
+	UserId               string                   `json:"user_id,omitempty"`
+	Term                 string                   `json:"term,omitempty"`
+	IsGuest              bool                     `json:"is_guest,omitempty"`
+	IncludeDeleted       bool                     `json:"include_deleted,omitempty"`
+	RestrictedToChannels []string                 `json:"restricted_to_channels"`
+	UserSearchOptions    *model.UserSearchOptions `json:"user_search_options,omitempty"`
+}
+
+type clusterSearchResponse struct {
+	Ids             []string                     `json:"ids"`
+	NotInChannelIds []string                     `json:"not_in_channel_ids,omitempty"`
+	PostMatches     model.PostSearchMatches      `json:"post_matches,omitempty"`
+	FileSnippets    model.FileInfoSearchSnippets `json:"file_snippets,omitempty"`
+	Error           *model.AppError              `json:"error,omitempty"`
+}
+
+// SetCluster sets the cluster the indexes are shared with in the leader
+// cluster mode.
+func (b *BleveEngine) SetCluster(cluster Cluster, logger mlog.LoggerIFace) {
+	b.clusterMut.Lock()
+	defer b.clusterMut.Unlock()
+
+	b.cluster = cluster
+	b.logger = logger
+}
+
+// setConfig replaces the configuration under clusterMut, as sharedCluster
+// reads it on every change and search.
+func (b *BleveEngine) setConfig(cfg *model.Config) {
+	b.clusterMut.Lock()
+	defer b.clusterMut.Unlock()
+
+	b.cfg = cfg
+}
+
+// sharedCluster returns the cluster the indexes are shared with, or nil when
+// they are not shared.
+func (b *BleveEngine) sharedCluster() Cluster {
+	b.clusterMut.Lock()
+	defer b.clusterMut.Unlock()
+
+	if b.cluster == nil || !*b.cfg.ClusterSettings.Enable || *b.cfg.BleveSettings.ClusterMode != model.BleveClusterModeLeader {
+		return nil
+	}
+	return b.cluster
+}
+
+// isShared reports whether the indexes are shared by the nodes of the cluster.
+func (b *BleveEngine) isShared() bool {
+	return b.sharedCluster() != nil
+}
+
+// IsShared reports whether the indexes are shared through the cluster.
+func (b *BleveEngine) IsShared() bool {
+	return b.isShared()
+}
+
+// IsIndexOwner reports whether the node owns the indexes, running the
+// indexing jobs. It always does unless the indexes are shared.
+func (b *BleveEngine) IsIndexOwner() bool {
+	cluster := b.sharedCluster()
+	return cluster == nil || cluster.IsLeader()
+}
+
+// NodeID returns the id of the node in the cluster, or an empty string when
+// the server isn't part of a cluster.
+func (b *BleveEngine) NodeID() string {
+	b.clusterMut.Lock()
+	cluster := b.cluster
+	b.clusterMut.Unlock()
+
+	if cluster == nil {
+		return ""
+	}
+	if info := cluster.GetMyClusterInfo(); info != nil {
+		return info.Id
+	}
+	return ""
+}
+
+// LiveNodeIDs returns the ids of the nodes of the cluster, which is empty when
+// the server isn't part of a cluster.
+func (b *BleveEngine) LiveNodeIDs() (map[string]bool, error) {
+	b.clusterMut.Lock()
+	cluster := b.cluster
+	b.clusterMut.Unlock()
+
+	nodeIDs := map[string]bool{}
+	if cluster == nil {
+		return nodeIDs, nil
+	}
+	infos, err := cluster.GetClusterInfos()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		nodeIDs[info.Id] = true
+	}
+	if info := cluster.GetMyClusterInfo(); info != nil {
+		nodeIDs[info.Id] = true
+	}
+	return nodeIDs, nil
+}
+
+func (b *BleveEngine) forwardsSearches() bool {
+	return !b.IsIndexOwner()
+}
+
+// ClusterLeaderChanged makes the new leader the owner of the shared indexes.
+func (b *BleveEngine) ClusterLeaderChanged() {
+	cluster := b.sharedCluster()
+	if cluster == nil {
+		return
+	}
+
+	if !cluster.IsLeader() {
+		b.requestOwner(cluster)
+		return
+	}
+
+	nodeID := cluster.GetMyClusterInfo().Id
+	previousOwnerID := b.setOwner(nodeID)
+
+	b.logger.Info("Taking the ownership of the shared Bleve indexes", mlog.String("previous_owner_id", previousOwnerID))
+	cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.ClusterEventBleveOwnerAnnouncement,
+		SendType: model.ClusterSendReliable,
+		Data:     []byte(nodeID),
+	})
+}
+
+// setOwner sets the node that owns the indexes, waking up the searches that
+// wait for it to be known, and returns the previous one.
+func (b *BleveEngine) setOwner(ownerID string) string {
+	b.clusterMut.Lock()
+	defer b.clusterMut.Unlock()
+
+	previousOwnerID := b.ownerID
+	b.ownerID = ownerID
+	if ownerID != "" && previousOwnerID == "" {
+		close(b.ownerKnown)
+	} else if ownerID == "" && previousOwnerID != "" {
+		b.ownerKnown = make(chan struct{})
+	}
+	return previousOwnerID
+}
+
+// owner returns the node that owns the indexes, if known, and a channel
+// closed once it is.
+func (b *BleveEngine) owner() (string, <-chan struct{}) {
+	b.clusterMut.Lock()
+	defer b.clusterMut.Unlock()
+
+	return b.ownerID, b.ownerKnown
+}
+
+// HandleClusterMessage handles the cluster messages of the ClusterEvents.
+func (b *BleveEngine) HandleClusterMessage(msg *model.ClusterMessage) {
+	switch msg.Event {
+	case model.ClusterEventBleveIndexOperation:
+		var op clusterIndexOperation
+		if err := json.Unmarshal(msg.Data, &op); err != nil {
+			b.logger.Warn("Failed to decode Bleve index operation", mlog.Err(err))
+			return
+		}
+		if appErr := b.applyIndexOperation(request.EmptyContext(b.logger), &op); appErr != nil {
+			b.logger.Error("Failed to apply Bleve index operation from another node", mlog.String("operation", op.Type), mlog.Err(appErr))
+		}
+
+	case model.ClusterEventBleveSearchRequest:
+		// Searches can take a while, so they don't hold the other messages up
+		select {
+		case b.clusterSearches <- struct{}{}:
+			go func() {
+				defer func() { <-b.clusterSearches }()
+				b.answerSearch(msg)
+			}()
+		default:
+			b.sendSearchResponse(msg, &clusterSearchResponse{
+				Error: model.NewAppError("Bleveengine.HandleClusterMessage", "bleveengine.cluster.busy.error", nil, "", http.StatusServiceUnavailable),
+			})
+		}
+
+	case model.ClusterEventBleveSearchResponse:
+		b.clusterMut.Lock()
+		responseCh, ok := b.pendingSearches[msg.Props["request_id"]]
+		b.clusterMut.Unlock()
+		if !ok {
+			return
+		}
+
+		var res clusterSearchResponse
+		if err := json.Unmarshal(msg.Data, &res); err != nil {
+			res.Error = model.NewAppError("Bleveengine.HandleClusterMessage", "bleveengine.cluster.search.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		select {
+		case responseCh <- &res:
+		default:
+		}
+
+	case model.ClusterEventBleveOwnerRequest:
+		if cluster := b.sharedCluster(); cluster != nil && cluster.IsLeader() {
+			if err := cluster.SendClusterMessageToNode(msg.Props["node_id"], &model.ClusterMessage{
+				Event:    model.ClusterEventBleveOwnerAnnouncement,
+				SendType: model.ClusterSendReliable,
+				Data:     []byte(cluster.GetMyClusterInfo().Id),
+			}); err != nil {
+				b.logger.Warn("Failed to announce the owner of the shared Bleve indexes", mlog.String("node_id", msg.Props["node_id"]), mlog.Err(err))
+			}
+		}
+
+	case model.ClusterEventBleveOwnerAnnouncement:
+		b.setOwner(string(msg.Data))
+	}
+}
+
+// replicate sends a change made to the indexes to the other nodes.
+func (b *BleveEngine) replicate(op *clusterIndexOperation) {
+	cluster := b.sharedCluster()
+	if cluster == nil {
+		return
+	}
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		b.logger.Warn("Failed to encode Bleve index operation", mlog.String("operation", op.Type), mlog.Err(err))
+		return
+	}
+	cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.ClusterEventBleveIndexOperation,
+		SendType: model.ClusterSendReliable,
+		Data:     data,
+	})
+}
+
+func (b *BleveEngine) applyIndexOperation(rctx request.CTX, op *clusterIndexOperation) *model.AppError {
+	if !b.IsActive() {
+		return nil
+	}
+
+	switch op.Type {
+	case indexOpIndexPost:
+		return b.indexPost(op.Post)
+	case indexOpDeletePost:
+		return b.deletePost(op.Id)
+	case indexOpDeleteChannelPosts:
+		return b.deleteChannelPosts(rctx, op.Id)
+	case indexOpDeleteUserPosts:
+		return b.deleteUserPosts(rctx, op.Id)
+	case indexOpIndexChannel:
+		return b.indexChannel(op.Channel)
+	case indexOpDeleteChannel:
+		return b.deleteChannel(op.Id)
+	case indexOpIndexUser:
+		return b.indexUser(op.User)
+	case indexOpDeleteUser:
+		return b.deleteUser(op.Id)
+	case indexOpIndexFile:
+		return b.indexFile(op.File)
+	case indexOpDeleteFile:
+		return b.deleteFile(op.Id)
+	case indexOpDeletePostFiles:
+		return b.deletePostFiles(rctx, op.Id)
+	case indexOpDeleteUserFiles:
+		return b.deleteUserFiles(rctx, op.Id)
+	case indexOpDeleteFilesBatch:
+		return b.deleteFilesBatch(rctx, op.EndTime, op.Limit)
+	case indexOpPurgeIndexes:
+		return b.purgeIndexes(rctx)
+	case indexOpPurgeIndexList:
+		return b.purgeIndexList(rctx, op.Indexes)
+	case indexOpDeletePostsBefore:
+		_, appErr := b.deletePostsBefore(op.EndTime, nil)
+		return appErr
+	case indexOpDeleteFilesBefore:
+		_, appErr := b.deleteFilesBefore(op.EndTime, nil)
+		return appErr
+	case indexOpIndexDocuments:
+		return b.indexDocuments(op.Documents)
+	}
+
+	return model.NewAppError("Bleveengine.applyIndexOperation", "bleveengine.cluster.unknown_operation.error", map[string]any{"Operation": op.Type}, "", http.StatusBadRequest)
+}
+
+// IndexDocuments applies a batch of an indexing job to the index and sends
+// it to the other nodes, so that their copies have every document when they
+// take the ownership over. Large batches are sent in several messages.
+func (b *BleveEngine) IndexDocuments(batch *DocumentBatch) *model.AppError {
+	if appErr := b.indexDocuments(batch); appErr != nil {
+		return appErr
+	}
+
+	if !b.isShared() {
+		return nil
+	}
+
+	for posts := range slices.Chunk(batch.Posts, clusterIndexBatchSize) {
+		b.replicate(&clusterIndexOperation{Type: indexOpIndexDocuments, Documents: &DocumentBatch{Index: batch.Index, Posts: posts}})
+	}
+	for files := range slices.Chunk(batch.Files, clusterIndexBatchSize) {
+		b.replicate(&clusterIndexOperation{Type: indexOpIndexDocuments, Documents: &DocumentBatch{Index: batch.Index, Files: files}})
+	}
+	for channels := range slices.Chunk(batch.Channels, clusterIndexBatchSize) {
+		b.replicate(&clusterIndexOperation{Type: indexOpIndexDocuments, Documents: &DocumentBatch{Index: batch.Index, Channels: channels}})
+	}
+	for users := range slices.Chunk(batch.Users, clusterIndexBatchSize) {
+		b.replicate(&clusterIndexOperation{Type: indexOpIndexDocuments, Documents: &DocumentBatch{Index: batch.Index, Users: users}})
+	}
+	for ids := range slices.Chunk(batch.DeletedIds, clusterIndexBatchSize) {
+		b.replicate(&clusterIndexOperation{Type: indexOpIndexDocuments, Documents: &DocumentBatch{Index: batch.Index, DeletedIds: ids}})
+	}
+
+	return nil
+}
+
+func (b *BleveEngine) indexDocuments(batch *DocumentBatch) *model.AppError {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	var index bleve.Index
+	switch batch.Index {
+	case PostIndex:
+		index = b.PostIndex
+	case FileIndex:
+		index = b.FileIndex
+	case ChannelIndex:
+		index = b.ChannelIndex
+	case UserIndex:
+		index = b.UserIndex
+	default:
+		return model.NewAppError("Bleveengine.indexDocuments", "bleveengine.index_documents.unknown_index.error", map[string]any{"Index": batch.Index}, "", http.StatusBadRequest)
+	}
+
+	bleveBatch := index.NewBatch()
+	for _, post := range batch.Posts {
+		bleveBatch.Index(post.Id, post)
+	}
+	for _, file := range batch.Files {
+		bleveBatch.Index(file.Id, file)
+	}
+	for _, channel := range batch.Channels {
+		bleveBatch.Index(channel.Id, channel)
+	}
+	for _, user := range batch.Users {
+		bleveBatch.Index(user.Id, user)
+	}
+	for _, id := range batch.DeletedIds {
+		bleveBatch.Delete(id)
+	}
+
+	if err := index.Batch(bleveBatch); err != nil {
+		return model.NewAppError("Bleveengine.indexDocuments", "bleveengine.index_documents.error", map[string]any{"Index": batch.Index}, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (b *BleveEngine) requestOwner(cluster Cluster) {
+	cluster.SendClusterMessage(&model.ClusterMessage{
+		Event:    model.ClusterEventBleveOwnerRequest,
+		SendType: model.ClusterSendReliable,
+		Props:    map[string]string{"node_id": cluster.GetMyClusterInfo().Id},
+	})
+}
+
+// forgetOwner stops sending the searches to a node which doesn't own the
+// indexes anymore and asks the cluster for the new owner.
+func (b *BleveEngine) forgetOwner(cluster Cluster, ownerID string) {
+	b.clusterMut.Lock()
+	if b.ownerID == ownerID {
+		b.ownerID = ""
+		b.ownerKnown = make(chan struct{})
+	}
+	b.clusterMut.Unlock()
+
+	b.requestOwner(cluster)
+}
+
+// searchOnOwner runs a search on the node that owns the indexes. While the
+// owner is unknown or can't be reached, the search runs on the copy of the
+// node, which only lacks the changes that have not reached it yet.
+func (b *BleveEngine) searchOnOwner(req *clusterSearchRequest) (*clusterSearchResponse, *model.AppError) {
+	cluster := b.sharedCluster()
+	if cluster == nil {
+		return b.searchLocally(req)
+	}
+
+	ownerID, ownerKnown := b.owner()
+	if ownerID == "" {
+		b.requestOwner(cluster)
+		select {
+		case <-ownerKnown:
+			ownerID, _ = b.owner()
+		case <-time.After(clusterOwnerWaitTimeout):
+		}
+	}
+	if ownerID == "" {
+		b.logger.Debug("The owner of the shared Bleve indexes is unknown, searching the local copy")
+		return b.searchLocally(req)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.searchOnOwner", "bleveengine.cluster.search.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	requestID := model.NewId()
+	responseCh := make(chan *clusterSearchResponse, 1)
+	b.clusterMut.Lock()
+	b.pendingSearches[requestID] = responseCh
+	b.clusterMut.Unlock()
+	defer func() {
+		b.clusterMut.Lock()
+		delete(b.pendingSearches, requestID)
+		b.clusterMut.Unlock()
+	}()
+
+	if err := cluster.SendClusterMessageToNode(ownerID, &model.ClusterMessage{
+		Event:    model.ClusterEventBleveSearchRequest,
+		SendType: model.ClusterSendReliable,
+		Props: map[string]string{
+			"request_id": requestID,
+			"node_id":    cluster.GetMyClusterInfo().Id,
+		},
+		Data: data,
+	}); err != nil {
+		b.logger.Warn("Failed to send a search to the owner of the shared Bleve indexes, searching the local copy", mlog.String("owner_id", ownerID), mlog.Err(err))
+		b.forgetOwner(cluster, ownerID)
+		return b.searchLocally(req)
+	}
+
+	select {
+	case res := <-responseCh:
+		if res.Error != nil {
+			if res.Error.Id == "bleveengine.cluster.not_owner.error" {
+				b.forgetOwner(cluster, ownerID)
+			}
+			return nil, res.Error
+		}
+		return res, nil
+	case <-time.After(clusterSearchTimeout):
+		return nil, model.NewAppError("Bleveengine.searchOnOwner", "bleveengine.cluster.search_timeout.error", map[string]any{"OwnerId": ownerID}, "", http.StatusGatewayTimeout)
+	}
+}
+
+func (b *BleveEngine) searchLocally(req *clusterSearchRequest) (*clusterSearchResponse, *model.AppError) {
+	res := b.runSearch(req)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+// answerSearch runs the search another node asked for and sends the
+// results back.
+func (b *BleveEngine) answerSearch(msg *model.ClusterMessage) {
+	var res clusterSearchResponse
+	var req clusterSearchRequest
+	if !b.IsIndexOwner() {
+		res.Error = model.NewAppError("Bleveengine.answerSearch", "bleveengine.cluster.not_owner.error", nil, "", http.StatusServiceUnavailable)
+	} else if err := json.Unmarshal(msg.Data, &req); err != nil {
+		res.Error = model.NewAppError("Bleveengine.answerSearch", "bleveengine.cluster.search.error", nil, "", http.StatusBadRequest).Wrap(err)
+	} else {
+		res = b.runSearch(&req)
+	}
+
+	b.sendSearchResponse(msg, &res)
+}
+
+// sendSearchResponse sends the response to a search to the node it came from.
+func (b *BleveEngine) sendSearchResponse(msg *model.ClusterMessage, res *clusterSearchResponse) {
+	// Only the detailed error is encoded, not the wrapped one
+	if res.Error != nil && res.Error.Unwrap() != nil {
+		res.Error.DetailedError = strings.TrimPrefix(res.Error.DetailedError+", "+res.Error.Unwrap().Error(), ", ")
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		b.logger.Warn("Failed to encode Bleve search results", mlog.Err(err))
+		return
+	}
+
+	b.clusterMut.Lock()
+	cluster := b.cluster
+	b.clusterMut.Unlock()
+	if err := cluster.SendClusterMessageToNode(msg.Props["node_id"], &model.ClusterMessage{
+		Event:    model.ClusterEventBleveSearchResponse,
+		SendType: model.ClusterSendReliable,
+		Props:    map[string]string{"request_id": msg.Props["request_id"]},
+		Data:     data,
+	}); err != nil {
+		b.logger.Warn("Failed to send Bleve search results", mlog.String("node_id", msg.Props["node_id"]), mlog.Err(err))
+	}
+}
+
+func (b *BleveEngine) runSearch(req *clusterSearchRequest) clusterSearchResponse {
+	var res clusterSearchResponse
+	switch req.Type {
+	case searchTypePosts:
+		res.Ids, res.PostMatches, res.Error = b.searchPosts(req.Channels, req.SearchParams, req.Page, req.PerPage)
+	case searchTypeFiles:
+		res.Ids, res.FileSnippets, res.Error = b.searchFiles(req.Channels, req.SearchParams, req.Page, req.PerPage)
+	case searchTypeChannels:
+		res.Ids, res.Error = b.searchChannels(req.TeamId, req.UserId, req.Term, req.IsGuest, req.IncludeDeleted)
+	case searchTypeUsersInChannel:
+		res.Ids, res.NotInChannelIds, res.Error = b.searchUsersInChannel(req.TeamId, req.ChannelId, req.RestrictedToChannels, req.Term, req.UserSearchOptions)
+	case searchTypeUsersInTeam:
+		res.Ids, res.Error = b.searchUsersInTeam(req.TeamId, req.RestrictedToChannels, req.Term, req.UserSearchOptions)
+	default:
+		res.Error = model.NewAppError("Bleveengine.runSearch", "bleveengine.cluster.unknown_search.error", map[string]any{"Type": req.Type}, "", http.StatusBadRequest)
+	}
+	return res
+}
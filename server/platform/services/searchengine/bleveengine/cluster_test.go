@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// testCluster delivers the cluster messages between engines of the same process.
+type testCluster struct {
+	mut     sync.Mutex
+	leader  string
+	engines map[string]*BleveEngine
+}
+
+type testClusterNode struct {
+	cluster *testCluster
+	id      string
+}
+
+func (n *testClusterNode) IsLeader() bool {
+	n.cluster.mut.Lock()
+	defer n.cluster.mut.Unlock()
+	return n.cluster.leader == n.id
+}
+
+func (n *testClusterNode) GetMyClusterInfo() *model.ClusterInfo {
+	return &model.ClusterInfo{Id: n.id}
+}
+
+func (n *testClusterNode) GetClusterInfos() ([]*model.ClusterInfo, error) {
+	infos := []*model.ClusterInfo{}
+	for id := range n.cluster.engines {
+		infos = append(infos, &model.ClusterInfo{Id: id})
+	}
+	return infos, nil
+}
+
+func (n *testClusterNode) SendClusterMessage(msg *model.ClusterMessage) {
+	for id, engine := range n.cluster.engines {
+		if id != n.id {
+			engine.HandleClusterMessage(msg)
+		}
+	}
+}
+
+func (n *testClusterNode) SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error {
+	engine, ok := n.cluster.engines[nodeID]
+	if !ok {
+		return fmt.Errorf("unknown node %q", nodeID)
+	}
+	engine.HandleClusterMessage(msg)
+	return nil
+}
+
+func (c *testCluster) setLeader(id string) {
+	c.mut.Lock()
+	c.leader = id
+	c.mut.Unlock()
+}
+
+func TestSharedIndexes(t *testing.T) {
+	cluster := &testCluster{engines: map[string]*BleveEngine{}}
+	for _, id := range []string{"node1", "node2"} {
+		engine := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+			settings.ClusterMode = model.NewPointer(model.BleveClusterModeLeader)
+		})
+		engine.cfg.ClusterSettings.Enable = model.NewPointer(true)
+		engine.SetCluster(&testClusterNode{cluster: cluster, id: id}, mlog.CreateConsoleTestLogger(t))
+		require.Nil(t, engine.Start())
+		defer engine.Stop()
+		cluster.engines[id] = engine
+	}
+	node1, node2 := cluster.engines["node1"], cluster.engines["node2"]
+
+	cluster.setLeader("node1")
+	node1.ClusterLeaderChanged()
+	node2.ClusterLeaderChanged()
+	assert.True(t, node1.IsIndexOwner())
+	assert.False(t, node2.IsIndexOwner())
+
+	channel := &model.Channel{Id: model.NewId()}
+	userID := model.NewId()
+
+	t.Run("changes are applied on every node", func(t *testing.T) {
+		post := createPost(userID, channel.Id)
+		post.Message = "replicated message"
+		require.Nil(t, node2.IndexPost(post, model.NewId()))
+
+		assert.Equal(t, []string{post.Id}, searchMessages(t, node1.PostIndex, "replicated"))
+		assert.Equal(t, []string{post.Id}, searchMessages(t, node2.PostIndex, "replicated"))
+
+		require.Nil(t, node2.DeletePost(post))
+		assert.Empty(t, searchMessages(t, node1.PostIndex, "replicated"))
+		assert.Empty(t, searchMessages(t, node2.PostIndex, "replicated"))
+	})
+
+	// Indexed by a job of the owner
+	post := createPost(userID, channel.Id)
+	post.Message = "bulk indexed message"
+
+	t.Run("batches of the jobs are applied on every node", func(t *testing.T) {
+		batch := &DocumentBatch{Index: PostIndex, Posts: []*BLVPost{BLVPostFromPost(post, model.NewId())}}
+		// Large batches are sent in several messages
+		for range clusterIndexBatchSize {
+			batch.Posts = append(batch.Posts, BLVPostFromPost(createPost(userID, channel.Id), model.NewId()))
+		}
+		deletedPost := createPost(userID, channel.Id)
+		deletedPost.Message = "deleted message"
+		require.Nil(t, node1.IndexDocuments(&DocumentBatch{Index: PostIndex, Posts: []*BLVPost{BLVPostFromPost(deletedPost, model.NewId())}}))
+		assert.Equal(t, []string{deletedPost.Id}, searchMessages(t, node2.PostIndex, "deleted"))
+		batch.DeletedIds = []string{deletedPost.Id}
+
+		require.Nil(t, node1.IndexDocuments(batch))
+
+		assert.Equal(t, []string{post.Id}, searchMessages(t, node2.PostIndex, "bulk"))
+		assert.Empty(t, searchMessages(t, node2.PostIndex, "deleted"))
+		count1, err := node1.PostIndex.DocCount()
+		require.NoError(t, err)
+		count2, err := node2.PostIndex.DocCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(clusterIndexBatchSize+1), count2)
+		assert.Equal(t, count1, count2)
+	})
+
+	// A change that hasn't reached the other node yet
+	ownerPost := createPost(userID, channel.Id)
+	ownerPost.Message = "owner only message"
+	require.Nil(t, node1.indexPost(BLVPostFromPost(ownerPost, model.NewId())))
+
+	t.Run("searches are answered by the owner", func(t *testing.T) {
+		ids, matches, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("owner", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{ownerPost.Id}, ids)
+		assert.Equal(t, model.PostSearchMatches{ownerPost.Id: {"owner"}}, matches)
+		assert.Empty(t, searchMessages(t, node2.PostIndex, "owner"))
+	})
+
+	t.Run("the ownership follows the leader", func(t *testing.T) {
+		cluster.setLeader("node2")
+		node2.ClusterLeaderChanged()
+		node1.ClusterLeaderChanged()
+		assert.False(t, node1.IsIndexOwner())
+		assert.True(t, node2.IsIndexOwner())
+
+		// The new owner has the documents indexed by the jobs of the previous one
+		ids, _, appErr := node1.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("bulk", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, ids)
+	})
+
+	t.Run("searches sent to a node which isn't the owner anymore are rejected", func(t *testing.T) {
+		cluster.setLeader("node3")
+		node2.setOwner("node1")
+
+		_, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("bulk", 0), 0, 20)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.cluster.not_owner.error", appErr.Id)
+		assert.Empty(t, node2.ownerID)
+	})
+
+	t.Run("searches run on the local copy while the owner is unknown", func(t *testing.T) {
+		timeout := clusterOwnerWaitTimeout
+		clusterOwnerWaitTimeout = 10 * time.Millisecond
+		defer func() { clusterOwnerWaitTimeout = timeout }()
+
+		ids, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("bulk", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, ids)
+	})
+
+	t.Run("searches wait for the owner to be announced", func(t *testing.T) {
+		cluster.setLeader("node1")
+		node1.ClusterLeaderChanged()
+		node2.forgetOwner(node2.sharedCluster(), "node1")
+
+		// The owner answers the request for it right away in the test cluster
+		ids, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("owner", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{ownerPost.Id}, ids)
+	})
+
+	t.Run("searches are rejected while the owner runs too many", func(t *testing.T) {
+		for range clusterSearchMaxConcurrent {
+			node1.clusterSearches <- struct{}{}
+		}
+		_, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("owner", 0), 0, 20)
+		for range clusterSearchMaxConcurrent {
+			<-node1.clusterSearches
+		}
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.cluster.busy.error", appErr.Id)
+
+		ids, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("owner", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{ownerPost.Id}, ids)
+	})
+
+	t.Run("indexes aren't shared in the local mode", func(t *testing.T) {
+		cfg := node2.cfg.Clone()
+		cfg.BleveSettings.ClusterMode = model.NewPointer(model.BleveClusterModeLocal)
+		node2.UpdateConfig(cfg)
+
+		assert.True(t, node2.IsIndexOwner())
+		ids, _, appErr := node2.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("owner", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Empty(t, ids)
+	})
+}
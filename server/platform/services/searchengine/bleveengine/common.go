@@ -4,12 +4,16 @@
 package bleveengine
 
 import (
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
 )
 
+var linkRegexp = regexp.MustCompile(`(?i)https?://`)
+
 type BLVChannel struct {
 	Id            string
 	Type          model.ChannelType
@@ -17,6 +21,7 @@ type BLVChannel struct {
 	TeamId        []string
 	TeamMemberIDs []string
 	NameSuggest   []string
+	DisplayName   string
 }
 
 type BLVUser struct {
@@ -25,6 +30,8 @@ type BLVUser struct {
 	SuggestionsWithoutFullname []string
 	TeamsIds                   []string
 	ChannelsIds                []string
+	Nickname                   string
+	FullName                   string
 }
 
 type BLVPost struct {
@@ -37,6 +44,14 @@ type BLVPost struct {
 	Type        string
 	Hashtags    []string
 	Attachments string
+	// The properties searched for by the has: is: reacted: and priority:
+	// filters.
+	HasFiles     bool
+	HasLink      bool
+	IsPinned     bool
+	IsThreadRoot bool
+	Reactions    []string
+	Priority     string
 }
 
 type BLVFile struct {
@@ -60,6 +75,7 @@ func BLVChannelFromChannel(channel *model.Channel, userIDs, teamMemberIDs []stri
 		NameSuggest:   append(displayNameInputs, nameInputs...),
 		UserIDs:       userIDs,
 		TeamMemberIDs: teamMemberIDs,
+		DisplayName:   channel.DisplayName,
 	}
 }
 
@@ -74,9 +90,9 @@ func BLVUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *
 		fullnameStrings = append(fullnameStrings, user.LastName)
 	}
 
+	fullname := strings.Join(fullnameStrings, " ")
 	fullnameSuggestions := []string{}
 	if len(fullnameStrings) > 0 {
-		fullname := strings.Join(fullnameStrings, " ")
 		fullnameSuggestions = searchengine.GetSuggestionInputsSplitBy(fullname, " ")
 	}
 
@@ -93,6 +109,8 @@ func BLVUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *
 		SuggestionsWithoutFullname: usernameAndNicknameSuggestions,
 		TeamsIds:                   teamsIds,
 		ChannelsIds:                channelsIds,
+		Nickname:                   user.Nickname,
+		FullName:                   fullname,
 	}
 }
 
@@ -118,17 +136,38 @@ func BLVPostFromPost(post *model.Post, teamId string) *BLVPost {
 	return BLVPostFromPostForIndexing(p)
 }
 
+// BLVPostFromPostForIndexing converts a post to its document. The reactions
+// and the priority are read from the metadata of the post, and whether it is
+// the root of a thread from its reply count.
 func BLVPostFromPostForIndexing(post *model.PostForIndexing) *BLVPost {
-	return &BLVPost{
-		Id:        post.Id,
-		TeamId:    post.TeamId,
-		ChannelId: post.ChannelId,
-		UserId:    post.UserId,
-		CreateAt:  post.CreateAt,
-		Message:   post.Message,
-		Type:      post.Type,
-		Hashtags:  strings.Fields(post.Hashtags),
+	blvPost := &BLVPost{
+		Id:           post.Id,
+		TeamId:       post.TeamId,
+		ChannelId:    post.ChannelId,
+		UserId:       post.UserId,
+		CreateAt:     post.CreateAt,
+		Message:      post.Message,
+		Type:         post.Type,
+		Hashtags:     strings.Fields(post.Hashtags),
+		HasFiles:     len(post.FileIds) > 0,
+		HasLink:      linkRegexp.MatchString(post.Message),
+		IsPinned:     post.IsPinned,
+		IsThreadRoot: post.RootId == "" && post.ReplyCount > 0,
+		Priority:     model.SearchPriorityStandard,
+	}
+
+	if post.Metadata != nil {
+		for _, reaction := range post.Metadata.Reactions {
+			if reaction.DeleteAt == 0 && !slices.Contains(blvPost.Reactions, reaction.EmojiName) {
+				blvPost.Reactions = append(blvPost.Reactions, reaction.EmojiName)
+			}
+		}
+		if priority := post.Metadata.Priority; priority != nil && priority.Priority != nil && *priority.Priority != "" {
+			blvPost.Priority = *priority.Priority
+		}
 	}
+
+	return blvPost
 }
 
 func splitFilenameWords(name string) string {
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"html"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	htmlhighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// newHighlightRequest highlights the fields with the HTML highlighter, which
+// escapes the fragments and surrounds the matching terms with <mark> tags.
+func newHighlightRequest(fields ...string) *bleve.HighlightRequest {
+	highlight := bleve.NewHighlightWithStyle(htmlhighlighter.Name)
+	for _, field := range fields {
+		highlight.AddField(field)
+	}
+	return highlight
+}
+
+// getMatchesForFragments gets the text highlighted in the fragments of the
+// fields of a hit, in the order it appears and without duplicates.
+func getMatchesForFragments(fragments map[string][]string, fields ...string) []string {
+	matches := []string{}
+	seen := map[string]bool{}
+
+	for _, field := range fields {
+		for _, fragment := range fragments[field] {
+			for {
+				var found bool
+				_, fragment, found = strings.Cut(fragment, highlightStart)
+				if !found {
+					break
+				}
+
+				var match string
+				match, fragment, found = strings.Cut(fragment, highlightEnd)
+				if !found {
+					break
+				}
+
+				// Markdown around a word is part of the token, as with Elasticsearch
+				match = strings.Trim(html.UnescapeString(match), "_*~")
+				if match != "" && !seen[match] {
+					seen[match] = true
+					matches = append(matches, match)
+				}
+			}
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestGetMatchesForFragments(t *testing.T) {
+	fragments := map[string][]string{
+		"Message":  {"the <mark>quick</mark> brown fox and the <mark>Quick</mark> one", "<mark>**bold**</mark> &amp; <mark>R&amp;D</mark>"},
+		"Hashtags": {"#<mark>quick</mark>"},
+		"Other":    {"<mark>ignored</mark>"},
+	}
+
+	assert.Equal(t, []string{"quick", "Quick", "bold", "R&D"}, getMatchesForFragments(fragments, "Message", "Hashtags"))
+	assert.Empty(t, getMatchesForFragments(fragments, "Attachments"))
+	assert.Empty(t, getMatchesForFragments(map[string][]string{"Message": {"<mark>unterminated"}}, "Message"))
+}
+
+func TestSearchHighlighting(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	channel := &model.Channel{Id: model.NewId()}
+	userId := model.NewId()
+
+	t.Run("posts", func(t *testing.T) {
+		post := createPost(userId, channel.Id)
+		post.Message = "Deploying the <b>release</b> tonight, see #release notes"
+		post.Hashtags = "#release"
+		require.Nil(t, engine.IndexPost(post, model.NewId()))
+
+		other := createPost(userId, channel.Id)
+		other.Message = "Nothing to see here"
+		require.Nil(t, engine.IndexPost(other, model.NewId()))
+
+		ids, matches, appErr := engine.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("release", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, ids)
+		assert.Equal(t, model.PostSearchMatches{post.Id: {"release"}}, matches)
+
+		ids, matches, appErr = engine.SearchPosts(model.ChannelList{channel}, model.ParseSearchParams("#release", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, ids)
+		assert.Equal(t, model.PostSearchMatches{post.Id: {"release"}}, matches)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		file := &model.FileInfo{
+			Id:        model.NewId(),
+			CreatorId: userId,
+			CreateAt:  model.GetMillis(),
+			Name:      "summary.txt",
+			Extension: "txt",
+			Content:   "Quarterly report: revenue <grew> by 10% & costs were stable",
+		}
+		require.Nil(t, engine.IndexFile(file, channel.Id))
+
+		ids, snippets, appErr := engine.SearchFiles(model.ChannelList{channel}, model.ParseSearchParams("revenue", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{file.Id}, ids)
+		assert.Equal(t, model.FileInfoSearchSnippets{
+			file.Id: {"Quarterly report: <mark>revenue</mark> &lt;grew&gt; by 10% &amp; costs were stable"},
+		}, snippets)
+
+		// Matching the name only gives no snippet
+		ids, snippets, appErr = engine.SearchFiles(model.ChannelList{channel}, model.ParseSearchParams("summary", 0), 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{file.Id}, ids)
+		assert.Empty(t, snippets)
+	})
+}
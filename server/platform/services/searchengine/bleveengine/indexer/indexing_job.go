@@ -5,8 +5,11 @@ package indexer
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
 )
 
@@ -24,8 +28,21 @@ const (
 	estimatedFilesCount   = 100000
 	estimatedChannelCount = 100000
 	estimatedUserCount    = 10000
+
+	// An indexing job left in progress without being updated for that long
+	// isn't run anymore, as when the server running it crashed.
+	staleJobTimeout     = 5 * time.Minute
+	staleJobCheckPeriod = time.Minute
 )
 
+// checkpointKeys are the keys of the job data tracking the progress of a job,
+// for it to resume where it stopped.
+var checkpointKeys = []string{
+	"start_time", "original_start_time",
+	"start_post_id", "start_channel_id", "start_user_id", "start_file_id",
+	"done_entity_types", "done_posts_count", "done_channels_count", "done_users_count", "done_files_count",
+}
+
 type BleveIndexerWorker struct {
 	name string
 	// stateMut protects stopCh and helps enforce
@@ -81,10 +98,78 @@ type IndexingProgress struct {
 	DoneUsersCount  int64
 	DoneUsers       bool
 	LastUserID      string
+
+	// The team or channel the documents to index belong to, if any.
+	TeamID    string
+	ChannelID string
 }
 
 func (ip *IndexingProgress) CurrentProgress() int64 {
-	return (ip.DonePostsCount + ip.DoneChannelsCount + ip.DoneUsersCount + ip.DoneFilesCount) * 100 / (ip.TotalPostsCount + ip.TotalChannelsCount + ip.TotalUsersCount + ip.TotalFilesCount)
+	total := ip.TotalPostsCount + ip.TotalChannelsCount + ip.TotalUsersCount + ip.TotalFilesCount
+	if total == 0 {
+		return 0
+	}
+	// The totals are estimated when filtering the documents
+	return min((ip.DonePostsCount+ip.DoneChannelsCount+ip.DoneUsersCount+ip.DoneFilesCount)*100/total, 100)
+}
+
+// batchFilter returns the filter of the posts and files to index, when the
+// job indexes a team or a channel.
+func (ip *IndexingProgress) batchFilter() (store.IndexingBatchFilter, bool) {
+	filter := store.IndexingBatchFilter{TeamID: ip.TeamID, ChannelID: ip.ChannelID}
+	return filter, filter.TeamID != "" || filter.ChannelID != ""
+}
+
+func (ip *IndexingProgress) includesChannel(channel *model.Channel) bool {
+	if ip.ChannelID != "" {
+		return channel.Id == ip.ChannelID
+	}
+	return ip.TeamID == "" || channel.TeamId == ip.TeamID
+}
+
+func (ip *IndexingProgress) includesUser(user *model.UserForIndexing) bool {
+	if ip.ChannelID != "" {
+		return slices.Contains(user.ChannelsIds, ip.ChannelID)
+	}
+	return ip.TeamID == "" || slices.Contains(user.TeamsIds, ip.TeamID)
+}
+
+// doneEntityTypes returns the entity types that are indexed, or excluded
+// from the job.
+func (ip *IndexingProgress) doneEntityTypes() string {
+	var done []string
+	for entityType, isDone := range map[string]bool{
+		bleveengine.PostIndex:    ip.DonePosts,
+		bleveengine.ChannelIndex: ip.DoneChannels,
+		bleveengine.UserIndex:    ip.DoneUsers,
+		bleveengine.FileIndex:    ip.DoneFiles,
+	} {
+		if isDone {
+			done = append(done, entityType)
+		}
+	}
+	slices.Sort(done)
+	return strings.Join(done, ",")
+}
+
+// setDoneEntityTypes marks the given entity types as done.
+func (ip *IndexingProgress) setDoneEntityTypes(entityTypes string) error {
+	for _, entityType := range strings.Split(entityTypes, ",") {
+		switch strings.TrimSpace(entityType) {
+		case bleveengine.PostIndex:
+			ip.DonePosts = true
+		case bleveengine.ChannelIndex:
+			ip.DoneChannels = true
+		case bleveengine.UserIndex:
+			ip.DoneUsers = true
+		case bleveengine.FileIndex:
+			ip.DoneFiles = true
+		case "":
+		default:
+			return fmt.Errorf("unknown entity type %q", entityType)
+		}
+	}
+	return nil
 }
 
 func (ip *IndexingProgress) IsDone() bool {
@@ -121,17 +206,66 @@ func (worker *BleveIndexerWorker) Run() {
 		worker.stoppedCh <- true
 	}()
 
+	worker.resumeStaleJobs()
+	ticker := time.NewTicker(staleJobCheckPeriod)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-worker.stopCh:
 			worker.logger.Debug("Worker: Received stop signal")
 			return
+		case <-ticker.C:
+			worker.resumeStaleJobs()
 		case job := <-worker.jobs:
 			worker.DoJob(&job)
 		}
 	}
 }
 
+// resumeStaleJobs sets back to pending the indexing jobs left in progress by
+// a server that crashed, for them to resume from their last checkpoint. A job
+// is only taken from the node running it when that node left the cluster.
+func (worker *BleveIndexerWorker) resumeStaleJobs() {
+	// Only the node owning the indexes runs the indexing jobs
+	if !worker.engine.IsIndexOwner() {
+		return
+	}
+
+	inProgressJobs, err := worker.jobServer.Store.Job().GetAllByTypeAndStatus(request.EmptyContext(worker.logger), model.JobTypeBlevePostIndexing, model.JobStatusInProgress)
+	if err != nil {
+		worker.logger.Warn("Worker: Failed to get the indexing jobs in progress", mlog.Err(err))
+		return
+	}
+
+	nodeID := worker.engine.NodeID()
+	var liveNodeIDs map[string]bool
+	staleTime := model.GetMillis() - staleJobTimeout.Milliseconds()
+	for _, job := range inProgressJobs {
+		// The worker doesn't run any job while checking them, so the jobs of
+		// its node were left by a previous run of the server.
+		if job.Data["node_id"] != nodeID {
+			if job.LastActivityAt >= staleTime {
+				continue
+			}
+			if liveNodeIDs == nil {
+				if liveNodeIDs, err = worker.engine.LiveNodeIDs(); err != nil {
+					worker.logger.Warn("Worker: Failed to get the nodes of the cluster", mlog.Err(err))
+					return
+				}
+			}
+			if liveNodeIDs[job.Data["node_id"]] {
+				continue
+			}
+		}
+		if _, err := worker.jobServer.Store.Job().UpdateStatusOptimistically(job.Id, model.JobStatusInProgress, model.JobStatusPending); err != nil {
+			worker.logger.Warn("Worker: Failed to set a stale indexing job back to pending", mlog.String("job_id", job.Id), mlog.Err(err))
+			continue
+		}
+		worker.logger.Info("Worker: Set a stale indexing job back to pending", mlog.String("job_id", job.Id), mlog.Int("last_activity_at", job.LastActivityAt))
+	}
+}
+
 func (worker *BleveIndexerWorker) Stop() {
 	worker.stateMut.Lock()
 	defer worker.stateMut.Unlock()
@@ -150,6 +284,12 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
 	logger.Debug("Worker: Received a new candidate job.")
 
+	// When the indexes are shared by the cluster, only their owner indexes
+	if !worker.engine.IsIndexOwner() {
+		logger.Debug("Worker: Skipping job as this node doesn't own the shared indexes")
+		return
+	}
+
 	var appErr *model.AppError
 	job, appErr = worker.jobServer.ClaimJob(job)
 	if appErr != nil {
@@ -161,6 +301,12 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 
 	logger.Info("Worker: Indexing job claimed by worker")
 
+	// Tells the other nodes the job isn't stale as long as the node is alive
+	if job.Data == nil {
+		job.Data = make(model.StringMap)
+	}
+	job.Data["node_id"] = worker.engine.NodeID()
+
 	if !worker.engine.IsActive() {
 		appError := model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.engine_inactive", nil, "", http.StatusInternalServerError)
 		if err := worker.jobServer.SetJobError(job, appError); err != nil {
@@ -169,6 +315,27 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 		return
 	}
 
+	// Indexes whose text analysis changed are rebuilt empty, so all of their
+	// documents have to be indexed again whatever range the job was created with.
+	rebuiltIndexes, appErr := worker.engine.RebuildOutdatedIndexes(request.EmptyContext(logger))
+	if appErr != nil {
+		logger.Error("Worker: Failed to rebuild the outdated indexes", mlog.Err(appErr))
+		if err := worker.jobServer.SetJobError(job, appErr); err != nil {
+			logger.Error("Worker: Failed to set job error", mlog.Err(err), mlog.NamedErr("set_error", appErr))
+		}
+		return
+	}
+	if len(rebuiltIndexes) > 0 {
+		logger.Info("Worker: Rebuilt the indexes whose mapping changed, indexing everything again", mlog.Array("indexes", rebuiltIndexes))
+		for _, key := range append([]string{"end_time", "entity_types", "team_id", "channel_id"}, checkpointKeys...) {
+			delete(job.Data, key)
+		}
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["rebuilt_indexes"] = strings.Join(rebuiltIndexes, ",")
+	}
+
 	progress := IndexingProgress{
 		Now:          time.Now(),
 		DonePosts:    false,
@@ -233,42 +400,39 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 		progress.LastFileID = id
 	}
 
-	// Counting all posts may fail or timeout when the posts table is large. If this happens, log a warning, but carry
-	// on with the indexing job anyway. The only issue is that the progress % reporting will be inaccurate.
-	if count, err := worker.jobServer.Store.Post().AnalyticsPostCount(&model.PostCountOptions{}); err != nil {
-		logger.Warn("Worker: Failed to fetch total post count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
-		progress.TotalPostsCount = estimatedPostCount
-	} else {
-		progress.TotalPostsCount = count
-	}
-
-	// Same possible fail as above can happen when counting channels
-	if count, err := worker.jobServer.Store.Channel().AnalyticsTypeCount("", ""); err != nil {
-		logger.Warn("Worker: Failed to fetch total channel count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
-		progress.TotalChannelsCount = estimatedChannelCount
-	} else {
-		progress.TotalChannelsCount = count
+	// The job was interrupted, start_time being the time it stopped at
+	if originalStartString, ok := job.Data["original_start_time"]; ok {
+		originalStartInt, err := strconv.ParseInt(originalStartString, 10, 64)
+		if err != nil {
+			logger.Error("Worker: Failed to parse original_start_time for job", mlog.String("original_start_time", originalStartString), mlog.Err(err))
+			appError := model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.parse_start_time.error", nil, "", http.StatusInternalServerError).Wrap(err)
+			if err := worker.jobServer.SetJobError(job, appError); err != nil {
+				logger.Error("Worker: Failed to set job error", mlog.Err(err), mlog.NamedErr("set_error", appError))
+			}
+			return
+		}
+		progress.StartAtTime = originalStartInt
+		logger.Info("Worker: Resuming indexing job", mlog.Int("start_time", progress.LastEntityTime), mlog.String("done_entity_types", job.Data["done_entity_types"]))
+	}
+	for key, count := range map[string]*int64{
+		"done_posts_count":    &progress.DonePostsCount,
+		"done_channels_count": &progress.DoneChannelsCount,
+		"done_users_count":    &progress.DoneUsersCount,
+		"done_files_count":    &progress.DoneFilesCount,
+	} {
+		// The counts only serve reporting the progress
+		*count, _ = strconv.ParseInt(job.Data[key], 10, 64)
+	}
+
+	if appErr = worker.setFilters(&progress, job.Data); appErr != nil {
+		logger.Error("Worker: Failed to parse the filters of the job", mlog.Err(appErr))
+		if err := worker.jobServer.SetJobError(job, appErr); err != nil {
+			logger.Error("Worker: Failed to set job error", mlog.Err(err), mlog.NamedErr("set_error", appErr))
+		}
+		return
 	}
 
-	// Same possible fail as above can happen when counting users
-	if count, err := worker.jobServer.Store.User().Count(model.UserCountOptions{
-		IncludeBotAccounts: true, // This actually doesn't join with the bots table
-		// since ExcludeRegularUsers is set to false
-	}); err != nil {
-		logger.Warn("Worker: Failed to fetch total user count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
-		progress.TotalUsersCount = estimatedUserCount
-	} else {
-		progress.TotalUsersCount = count
-	}
-
-	// Counting all files may fail or timeout when the file_info table is large. If this happens, log a warning, but carry
-	// on with the indexing job anyway. The only issue is that the progress % reporting will be inaccurate.
-	if count, err := worker.jobServer.Store.FileInfo().CountAll(); err != nil {
-		logger.Warn("Worker: Failed to fetch total file info count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
-		progress.TotalFilesCount = estimatedFilesCount
-	} else {
-		progress.TotalFilesCount = count
-	}
+	worker.countTotals(logger, &progress)
 
 	var cancelContext request.CTX = request.EmptyContext(worker.logger)
 	cancelCtx, cancelCancelWatcher := context.WithCancel(context.Background())
@@ -315,6 +479,11 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 			job.Data["start_file_id"] = progress.LastFileID
 			job.Data["original_start_time"] = strconv.FormatInt(progress.StartAtTime, 10)
 			job.Data["end_time"] = strconv.FormatInt(progress.EndAtTime, 10)
+			job.Data["done_entity_types"] = progress.doneEntityTypes()
+			job.Data["done_posts_count"] = strconv.FormatInt(progress.DonePostsCount, 10)
+			job.Data["done_channels_count"] = strconv.FormatInt(progress.DoneChannelsCount, 10)
+			job.Data["done_users_count"] = strconv.FormatInt(progress.DoneUsersCount, 10)
+			job.Data["done_files_count"] = strconv.FormatInt(progress.DoneFilesCount, 10)
 
 			if err := worker.jobServer.SetJobProgress(job, progress.CurrentProgress()); err != nil {
 				logger.Error("Worker: Failed to set progress for job", mlog.Err(err))
@@ -338,6 +507,104 @@ func (worker *BleveIndexerWorker) DoJob(job *model.Job) {
 	}
 }
 
+// setFilters applies the entity types, team and channel filters of the job
+// data, and marks the entity types the job is done with.
+func (worker *BleveIndexerWorker) setFilters(progress *IndexingProgress, data model.StringMap) *model.AppError {
+	if entityTypes := data["entity_types"]; entityTypes != "" {
+		var selected IndexingProgress
+		if err := selected.setDoneEntityTypes(entityTypes); err != nil {
+			return model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.parse_entity_types.error", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		progress.DonePosts = !selected.DonePosts
+		progress.DoneChannels = !selected.DoneChannels
+		progress.DoneUsers = !selected.DoneUsers
+		progress.DoneFiles = !selected.DoneFiles
+	}
+
+	if err := progress.setDoneEntityTypes(data["done_entity_types"]); err != nil {
+		return model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.parse_entity_types.error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	progress.TeamID = data["team_id"]
+	progress.ChannelID = data["channel_id"]
+	switch {
+	case progress.TeamID != "" && progress.ChannelID != "":
+		return model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.team_and_channel.error", nil, "", http.StatusBadRequest)
+
+	case progress.ChannelID != "":
+		if !model.IsValidId(progress.ChannelID) {
+			return model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.invalid_channel_id.error", nil, "", http.StatusBadRequest)
+		}
+
+	case progress.TeamID != "":
+		if !model.IsValidId(progress.TeamID) {
+			return model.NewAppError("BleveIndexerWorker", "bleveengine.indexer.do_job.invalid_team_id.error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// countTotals counts the documents to index for reporting the progress. The
+// counts of the documents the job filters out are estimated.
+func (worker *BleveIndexerWorker) countTotals(logger mlog.LoggerIFace, progress *IndexingProgress) {
+	// Counting all posts may fail or timeout when the posts table is large. If this happens, log a warning, but carry
+	// on with the indexing job anyway. The only issue is that the progress % reporting will be inaccurate.
+	if progress.DonePosts {
+		progress.TotalPostsCount = progress.DonePostsCount
+	} else if progress.ChannelID != "" {
+		if channel, err := worker.jobServer.Store.Channel().Get(progress.ChannelID, false); err != nil {
+			logger.Warn("Worker: Failed to fetch the channel of the job. An estimated value will be used for progress reporting.", mlog.Err(err))
+			progress.TotalPostsCount = estimatedPostCount
+		} else {
+			progress.TotalPostsCount = channel.TotalMsgCount
+		}
+	} else if count, err := worker.jobServer.Store.Post().AnalyticsPostCount(&model.PostCountOptions{TeamId: progress.TeamID}); err != nil {
+		logger.Warn("Worker: Failed to fetch total post count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
+		progress.TotalPostsCount = estimatedPostCount
+	} else {
+		progress.TotalPostsCount = count
+	}
+
+	// Same possible fail as above can happen when counting channels
+	if progress.DoneChannels {
+		progress.TotalChannelsCount = progress.DoneChannelsCount
+	} else if progress.ChannelID != "" {
+		progress.TotalChannelsCount = 1
+	} else if count, err := worker.jobServer.Store.Channel().AnalyticsTypeCount(progress.TeamID, ""); err != nil {
+		logger.Warn("Worker: Failed to fetch total channel count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
+		progress.TotalChannelsCount = estimatedChannelCount
+	} else {
+		progress.TotalChannelsCount = count
+	}
+
+	// Same possible fail as above can happen when counting users
+	if progress.DoneUsers {
+		progress.TotalUsersCount = progress.DoneUsersCount
+	} else if count, err := worker.jobServer.Store.User().Count(model.UserCountOptions{
+		IncludeBotAccounts: true, // This actually doesn't join with the bots table
+		// since ExcludeRegularUsers is set to false
+		TeamId:    progress.TeamID,
+		ChannelId: progress.ChannelID,
+	}); err != nil {
+		logger.Warn("Worker: Failed to fetch total user count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
+		progress.TotalUsersCount = estimatedUserCount
+	} else {
+		progress.TotalUsersCount = count
+	}
+
+	// Counting all files may fail or timeout when the file_info table is large. If this happens, log a warning, but carry
+	// on with the indexing job anyway. The only issue is that the progress % reporting will be inaccurate.
+	if progress.DoneFiles {
+		progress.TotalFilesCount = progress.DoneFilesCount
+	} else if count, err := worker.jobServer.Store.FileInfo().CountAll(); err != nil {
+		logger.Warn("Worker: Failed to fetch total file info count for job. An estimated value will be used for progress reporting.", mlog.Err(err))
+		progress.TotalFilesCount = estimatedFilesCount
+	} else {
+		progress.TotalFilesCount = count
+	}
+}
+
 func (worker *BleveIndexerWorker) IndexBatch(logger mlog.LoggerIFace, progress IndexingProgress) (IndexingProgress, *model.AppError) {
 	if !progress.DonePosts {
 		return worker.IndexPostsBatch(logger, progress)
@@ -360,7 +627,11 @@ func (worker *BleveIndexerWorker) IndexPostsBatch(logger mlog.LoggerIFace, progr
 	tries := 0
 	for posts == nil {
 		var err error
-		posts, err = worker.jobServer.Store.Post().GetPostsBatchForIndexing(progress.LastEntityTime, progress.LastPostID, *worker.jobServer.Config().BleveSettings.BatchSize)
+		if filter, ok := progress.batchFilter(); ok {
+			posts, err = worker.jobServer.Store.Post().GetFilteredPostsBatchForIndexing(progress.LastEntityTime, progress.LastPostID, filter, *worker.jobServer.Config().BleveSettings.BatchSize)
+		} else {
+			posts, err = worker.jobServer.Store.Post().GetPostsBatchForIndexing(progress.LastEntityTime, progress.LastPostID, *worker.jobServer.Config().BleveSettings.BatchSize)
+		}
 		if err != nil {
 			if tries >= 10 {
 				return progress, model.NewAppError("IndexPostsBatch", "app.post.get_posts_batch_for_indexing.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
@@ -381,6 +652,9 @@ func (worker *BleveIndexerWorker) IndexPostsBatch(logger mlog.LoggerIFace, progr
 		return progress, nil
 	}
 
+	if err := worker.addPostSearchProperties(posts); err != nil {
+		return progress, err
+	}
 	lastPost, err := worker.BulkIndexPosts(posts, progress)
 	if err != nil {
 		return progress, err
@@ -401,22 +675,60 @@ func (worker *BleveIndexerWorker) IndexPostsBatch(logger mlog.LoggerIFace, progr
 	return progress, nil
 }
 
+// addPostSearchProperties loads the reactions and the priorities of the posts,
+// searched for by the reacted: and priority: filters.
+func (worker *BleveIndexerWorker) addPostSearchProperties(posts []*model.PostForIndexing) *model.AppError {
+	postsByID := make(map[string]*model.PostForIndexing, len(posts))
+	postIDs := make([]string, 0, len(posts))
+	reactedPostIDs := []string{}
+	for _, post := range posts {
+		if post.Metadata == nil {
+			post.Metadata = &model.PostMetadata{}
+		}
+		postsByID[post.Id] = post
+		postIDs = append(postIDs, post.Id)
+		if post.HasReactions {
+			reactedPostIDs = append(reactedPostIDs, post.Id)
+		}
+	}
+
+	if len(reactedPostIDs) > 0 {
+		reactions, err := worker.jobServer.Store.Reaction().BulkGetForPosts(reactedPostIDs)
+		if err != nil {
+			return model.NewAppError("IndexPostsBatch", "bleveengine.indexer.do_job.get_post_search_properties.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		for _, reaction := range reactions {
+			if post, ok := postsByID[reaction.PostId]; ok {
+				post.Metadata.Reactions = append(post.Metadata.Reactions, reaction)
+			}
+		}
+	}
+
+	priorities, err := worker.jobServer.Store.PostPriority().GetForPosts(postIDs)
+	if err != nil {
+		return model.NewAppError("IndexPostsBatch", "bleveengine.indexer.do_job.get_post_search_properties.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	for _, priority := range priorities {
+		if post, ok := postsByID[priority.PostId]; ok {
+			post.Metadata.Priority = priority
+		}
+	}
+
+	return nil
+}
+
 func (worker *BleveIndexerWorker) BulkIndexPosts(posts []*model.PostForIndexing, progress IndexingProgress) (*model.Post, *model.AppError) {
-	batch := worker.engine.PostIndex.NewBatch()
+	batch := &bleveengine.DocumentBatch{Index: bleveengine.PostIndex}
 
 	for _, post := range posts {
 		if post.DeleteAt == 0 {
-			searchPost := bleveengine.BLVPostFromPostForIndexing(post)
-			batch.Index(searchPost.Id, searchPost)
+			batch.Posts = append(batch.Posts, bleveengine.BLVPostFromPostForIndexing(post))
 		} else {
-			batch.Delete(post.Id)
+			batch.DeletedIds = append(batch.DeletedIds, post.Id)
 		}
 	}
 
-	worker.engine.Mutex.RLock()
-	defer worker.engine.Mutex.RUnlock()
-
-	if err := worker.engine.PostIndex.Batch(batch); err != nil {
+	if err := worker.engine.IndexDocuments(batch); err != nil {
 		return nil, model.NewAppError("BleveIndexerWorker.BulkIndexPosts", "bleveengine.indexer.do_job.bulk_index_posts.batch_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return &posts[len(posts)-1].Post, nil
@@ -428,7 +740,11 @@ func (worker *BleveIndexerWorker) IndexFilesBatch(logger mlog.LoggerIFace, progr
 	tries := 0
 	for files == nil {
 		var err error
-		files, err = worker.jobServer.Store.FileInfo().GetFilesBatchForIndexing(progress.LastEntityTime, progress.LastFileID, true, *worker.jobServer.Config().BleveSettings.BatchSize)
+		if filter, ok := progress.batchFilter(); ok {
+			files, err = worker.jobServer.Store.FileInfo().GetFilteredFilesBatchForIndexing(progress.LastEntityTime, progress.LastFileID, filter, *worker.jobServer.Config().BleveSettings.BatchSize)
+		} else {
+			files, err = worker.jobServer.Store.FileInfo().GetFilesBatchForIndexing(progress.LastEntityTime, progress.LastFileID, true, *worker.jobServer.Config().BleveSettings.BatchSize)
+		}
 		if err != nil {
 			if tries >= 10 {
 				return progress, model.NewAppError("IndexFilesBatch", "app.post.get_files_batch_for_indexing.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
@@ -469,21 +785,17 @@ func (worker *BleveIndexerWorker) IndexFilesBatch(logger mlog.LoggerIFace, progr
 }
 
 func (worker *BleveIndexerWorker) BulkIndexFiles(files []*model.FileForIndexing, progress IndexingProgress) (*model.FileInfo, *model.AppError) {
-	batch := worker.engine.FileIndex.NewBatch()
+	batch := &bleveengine.DocumentBatch{Index: bleveengine.FileIndex}
 
 	for _, file := range files {
 		if file.ShouldIndex() {
-			searchFile := bleveengine.BLVFileFromFileForIndexing(file)
-			batch.Index(searchFile.Id, searchFile)
+			batch.Files = append(batch.Files, bleveengine.BLVFileFromFileForIndexing(file))
 		} else {
-			batch.Delete(file.Id)
+			batch.DeletedIds = append(batch.DeletedIds, file.Id)
 		}
 	}
 
-	worker.engine.Mutex.RLock()
-	defer worker.engine.Mutex.RUnlock()
-
-	if err := worker.engine.FileIndex.Batch(batch); err != nil {
+	if err := worker.engine.IndexDocuments(batch); err != nil {
 		return nil, model.NewAppError("BleveIndexerWorker.BulkIndexPosts", "bleveengine.indexer.do_job.bulk_index_files.batch_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return &files[len(files)-1].FileInfo, nil
@@ -515,9 +827,14 @@ func (worker *BleveIndexerWorker) IndexChannelsBatch(logger mlog.LoggerIFace, pr
 		return progress, nil
 	}
 
-	lastChannel, err := worker.BulkIndexChannels(logger, channels, progress)
-	if err != nil {
-		return progress, err
+	lastChannel := channels[len(channels)-1]
+	channels = slices.DeleteFunc(channels, func(channel *model.Channel) bool {
+		return !progress.includesChannel(channel)
+	})
+	if len(channels) > 0 {
+		if _, err := worker.BulkIndexChannels(logger, channels, progress); err != nil {
+			return progress, err
+		}
 	}
 
 	// Our exit condition is when the last channel's createAt reaches the initial endAtTime
@@ -536,7 +853,7 @@ func (worker *BleveIndexerWorker) IndexChannelsBatch(logger mlog.LoggerIFace, pr
 }
 
 func (worker *BleveIndexerWorker) BulkIndexChannels(logger mlog.LoggerIFace, channels []*model.Channel, progress IndexingProgress) (*model.Channel, *model.AppError) {
-	batch := worker.engine.ChannelIndex.NewBatch()
+	batch := &bleveengine.DocumentBatch{Index: bleveengine.ChannelIndex}
 
 	for _, channel := range channels {
 		if channel.DeleteAt == 0 {
@@ -555,17 +872,13 @@ func (worker *BleveIndexerWorker) BulkIndexChannels(logger mlog.LoggerIFace, cha
 				return nil, model.NewAppError("BleveIndexerWorker.BulkIndexChannels", "bleveengine.indexer.do_job.bulk_index_channels.batch_error", nil, "", http.StatusInternalServerError).Wrap(err)
 			}
 
-			searchChannel := bleveengine.BLVChannelFromChannel(channel, userIDs, teamMemberIDs)
-			batch.Index(searchChannel.Id, searchChannel)
+			batch.Channels = append(batch.Channels, bleveengine.BLVChannelFromChannel(channel, userIDs, teamMemberIDs))
 		} else {
-			batch.Delete(channel.Id)
+			batch.DeletedIds = append(batch.DeletedIds, channel.Id)
 		}
 	}
 
-	worker.engine.Mutex.RLock()
-	defer worker.engine.Mutex.RUnlock()
-
-	if err := worker.engine.ChannelIndex.Batch(batch); err != nil {
+	if err := worker.engine.IndexDocuments(batch); err != nil {
 		return nil, model.NewAppError("BleveIndexerWorker.BulkIndexChannels", "bleveengine.indexer.do_job.bulk_index_channels.batch_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return channels[len(channels)-1], nil
@@ -597,9 +910,14 @@ func (worker *BleveIndexerWorker) IndexUsersBatch(logger mlog.LoggerIFace, progr
 		return progress, nil
 	}
 
-	lastUser, err := worker.BulkIndexUsers(logger, users, progress)
-	if err != nil {
-		return progress, err
+	lastUser := users[len(users)-1]
+	users = slices.DeleteFunc(users, func(user *model.UserForIndexing) bool {
+		return !progress.includesUser(user)
+	})
+	if len(users) > 0 {
+		if _, err := worker.BulkIndexUsers(logger, users, progress); err != nil {
+			return progress, err
+		}
 	}
 
 	// Our exit condition is when the last user's createAt reaches the initial endAtTime
@@ -617,21 +935,17 @@ func (worker *BleveIndexerWorker) IndexUsersBatch(logger mlog.LoggerIFace, progr
 }
 
 func (worker *BleveIndexerWorker) BulkIndexUsers(logger mlog.LoggerIFace, users []*model.UserForIndexing, progress IndexingProgress) (*model.UserForIndexing, *model.AppError) {
-	batch := worker.engine.UserIndex.NewBatch()
+	batch := &bleveengine.DocumentBatch{Index: bleveengine.UserIndex}
 
 	for _, user := range users {
 		if user.DeleteAt == 0 {
-			searchUser := bleveengine.BLVUserFromUserForIndexing(user)
-			batch.Index(searchUser.Id, searchUser)
+			batch.Users = append(batch.Users, bleveengine.BLVUserFromUserForIndexing(user))
 		} else {
-			batch.Delete(user.Id)
+			batch.DeletedIds = append(batch.DeletedIds, user.Id)
 		}
 	}
 
-	worker.engine.Mutex.RLock()
-	defer worker.engine.Mutex.RUnlock()
-
-	if err := worker.engine.UserIndex.Batch(batch); err != nil {
+	if err := worker.engine.IndexDocuments(batch); err != nil {
 		return nil, model.NewAppError("BleveIndexerWorker.BulkIndexUsers", "bleveengine.indexer.do_job.bulk_index_users.batch_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return users[len(users)-1], nil
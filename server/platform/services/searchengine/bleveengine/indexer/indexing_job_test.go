@@ -7,13 +7,16 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
 	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
@@ -52,6 +55,7 @@ func TestBleveIndexer(t *testing.T) {
 				IndexDir:       model.NewPointer(tempDir),
 			},
 		}
+		cfg.BleveSettings.SetDefaults()
 
 		jobServer := &jobs.JobServer{
 			Store: mockStore,
@@ -73,3 +77,174 @@ func TestBleveIndexer(t *testing.T) {
 		worker.DoJob(job)
 	})
 }
+
+func TestIndexingProgressEntityTypes(t *testing.T) {
+	var progress IndexingProgress
+	require.NoError(t, progress.setDoneEntityTypes("users, posts"))
+	assert.True(t, progress.DonePosts)
+	assert.False(t, progress.DoneChannels)
+	assert.True(t, progress.DoneUsers)
+	assert.False(t, progress.DoneFiles)
+	assert.Equal(t, "posts,users", progress.doneEntityTypes())
+
+	require.NoError(t, progress.setDoneEntityTypes(""))
+	assert.Equal(t, "posts,users", progress.doneEntityTypes())
+
+	require.Error(t, progress.setDoneEntityTypes("posts,emojis"))
+}
+
+func TestSetFilters(t *testing.T) {
+	mockStore := &storetest.Store{}
+	defer mockStore.AssertExpectations(t)
+
+	worker := &BleveIndexerWorker{
+		jobServer: &jobs.JobServer{Store: mockStore},
+	}
+
+	t.Run("entity types", func(t *testing.T) {
+		var progress IndexingProgress
+		require.Nil(t, worker.setFilters(&progress, model.StringMap{"entity_types": "channels,files"}))
+		assert.Equal(t, "posts,users", progress.doneEntityTypes())
+		_, filtered := progress.batchFilter()
+		assert.False(t, filtered)
+	})
+
+	t.Run("resumed job", func(t *testing.T) {
+		var progress IndexingProgress
+		require.Nil(t, worker.setFilters(&progress, model.StringMap{"entity_types": "channels,files", "done_entity_types": "channels,posts,users"}))
+		assert.Equal(t, "channels,posts,users", progress.doneEntityTypes())
+	})
+
+	t.Run("unknown entity type", func(t *testing.T) {
+		var progress IndexingProgress
+		appErr := worker.setFilters(&progress, model.StringMap{"entity_types": "reactions"})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.indexer.do_job.parse_entity_types.error", appErr.Id)
+	})
+
+	t.Run("team", func(t *testing.T) {
+		teamID := model.NewId()
+		channel := &model.Channel{Id: model.NewId(), TeamId: teamID}
+
+		var progress IndexingProgress
+		require.Nil(t, worker.setFilters(&progress, model.StringMap{"team_id": teamID}))
+		filter, filtered := progress.batchFilter()
+		assert.True(t, filtered)
+		assert.Equal(t, store.IndexingBatchFilter{TeamID: teamID}, filter)
+		assert.True(t, progress.includesChannel(channel))
+		assert.False(t, progress.includesChannel(&model.Channel{Id: model.NewId(), TeamId: model.NewId()}))
+		assert.True(t, progress.includesUser(&model.UserForIndexing{TeamsIds: []string{model.NewId(), teamID}}))
+		assert.False(t, progress.includesUser(&model.UserForIndexing{TeamsIds: []string{model.NewId()}}))
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		channelID := model.NewId()
+
+		var progress IndexingProgress
+		require.Nil(t, worker.setFilters(&progress, model.StringMap{"channel_id": channelID}))
+		filter, filtered := progress.batchFilter()
+		assert.True(t, filtered)
+		assert.Equal(t, store.IndexingBatchFilter{ChannelID: channelID}, filter)
+		assert.True(t, progress.includesUser(&model.UserForIndexing{ChannelsIds: []string{channelID}}))
+		assert.False(t, progress.includesUser(&model.UserForIndexing{ChannelsIds: []string{model.NewId()}}))
+	})
+
+	t.Run("team and channel", func(t *testing.T) {
+		var progress IndexingProgress
+		appErr := worker.setFilters(&progress, model.StringMap{"team_id": model.NewId(), "channel_id": model.NewId()})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.indexer.do_job.team_and_channel.error", appErr.Id)
+	})
+}
+
+func TestCurrentProgress(t *testing.T) {
+	assert.Equal(t, int64(0), (&IndexingProgress{}).CurrentProgress())
+	assert.Equal(t, int64(50), (&IndexingProgress{TotalPostsCount: 10, DonePostsCount: 4, TotalUsersCount: 10, DoneUsersCount: 6}).CurrentProgress())
+	// The totals of the filtered documents are estimated
+	assert.Equal(t, int64(100), (&IndexingProgress{TotalPostsCount: 10, DonePostsCount: 12}).CurrentProgress())
+}
+
+type testCluster struct {
+	nodeID  string
+	nodeIDs []string
+}
+
+func (c *testCluster) IsLeader() bool { return true }
+
+func (c *testCluster) GetMyClusterInfo() *model.ClusterInfo {
+	return &model.ClusterInfo{Id: c.nodeID}
+}
+
+func (c *testCluster) GetClusterInfos() ([]*model.ClusterInfo, error) {
+	infos := []*model.ClusterInfo{}
+	for _, nodeID := range c.nodeIDs {
+		infos = append(infos, &model.ClusterInfo{Id: nodeID})
+	}
+	return infos, nil
+}
+
+func (c *testCluster) SendClusterMessage(msg *model.ClusterMessage) {}
+
+func (c *testCluster) SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error {
+	return nil
+}
+
+func TestResumeStaleJobs(t *testing.T) {
+	staleTime := model.GetMillis() - (2 * staleJobTimeout).Milliseconds()
+	activeTime := model.GetMillis() - time.Second.Milliseconds()
+	newJob := func(nodeID string, lastActivityAt int64) *model.Job {
+		return &model.Job{
+			Id:             model.NewId(),
+			Type:           model.JobTypeBlevePostIndexing,
+			Status:         model.JobStatusInProgress,
+			LastActivityAt: lastActivityAt,
+			Data:           model.StringMap{"node_id": nodeID},
+		}
+	}
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+
+	t.Run("without a cluster", func(t *testing.T) {
+		mockStore := &storetest.Store{}
+		defer mockStore.AssertExpectations(t)
+
+		// The jobs in progress were left by a previous run of the server
+		staleJob := newJob("", staleTime)
+		activeJob := newJob("", activeTime)
+		mockStore.JobStore.On("GetAllByTypeAndStatus", mock.Anything, model.JobTypeBlevePostIndexing, model.JobStatusInProgress).Return([]*model.Job{staleJob, activeJob}, nil)
+		mockStore.JobStore.On("UpdateStatusOptimistically", staleJob.Id, model.JobStatusInProgress, model.JobStatusPending).Return(staleJob, nil).Once()
+		mockStore.JobStore.On("UpdateStatusOptimistically", activeJob.Id, model.JobStatusInProgress, model.JobStatusPending).Return(activeJob, nil).Once()
+
+		worker := &BleveIndexerWorker{
+			jobServer: &jobs.JobServer{Store: mockStore},
+			engine:    bleveengine.NewBleveEngine(cfg),
+			logger:    mlog.CreateConsoleTestLogger(t),
+		}
+
+		worker.resumeStaleJobs()
+	})
+
+	t.Run("in a cluster", func(t *testing.T) {
+		mockStore := &storetest.Store{}
+		defer mockStore.AssertExpectations(t)
+
+		ownJob := newJob("node1", activeTime)
+		liveNodeJob := newJob("node2", staleTime)
+		leftNodeJob := newJob("node3", staleTime)
+		activeLeftNodeJob := newJob("node4", activeTime)
+		mockStore.JobStore.On("GetAllByTypeAndStatus", mock.Anything, model.JobTypeBlevePostIndexing, model.JobStatusInProgress).Return([]*model.Job{ownJob, liveNodeJob, leftNodeJob, activeLeftNodeJob}, nil)
+		mockStore.JobStore.On("UpdateStatusOptimistically", ownJob.Id, model.JobStatusInProgress, model.JobStatusPending).Return(ownJob, nil).Once()
+		mockStore.JobStore.On("UpdateStatusOptimistically", leftNodeJob.Id, model.JobStatusInProgress, model.JobStatusPending).Return(leftNodeJob, nil).Once()
+
+		engine := bleveengine.NewBleveEngine(cfg)
+		engine.SetCluster(&testCluster{nodeID: "node1", nodeIDs: []string{"node1", "node2"}}, mlog.CreateConsoleTestLogger(t))
+		worker := &BleveIndexerWorker{
+			jobServer: &jobs.JobServer{Store: mockStore},
+			engine:    engine,
+			logger:    mlog.CreateConsoleTestLogger(t),
+		}
+
+		worker.resumeStaleJobs()
+	})
+}
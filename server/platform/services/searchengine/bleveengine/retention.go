@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// DataRetentionDeleteIndexes deletes the posts and files created before the
+// cutoff from the indexes.
+func (b *BleveEngine) DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError {
+	endTime := model.GetMillisForTime(cutoff)
+
+	deletedPosts, appErr := b.DeletePostsBefore(endTime, nil)
+	if appErr != nil {
+		return appErr
+	}
+
+	deletedFiles, appErr := b.DeleteFilesBefore(endTime, nil)
+	if appErr != nil {
+		return appErr
+	}
+
+	rctx.Logger().Info("Deleted posts and files older than the data retention cutoff from the Bleve indexes", mlog.Time("cutoff", cutoff), mlog.Int("deleted_posts", deletedPosts), mlog.Int("deleted_files", deletedFiles))
+
+	return nil
+}
+
+// createdBeforeQuery matches the documents created before endTime.
+func createdBeforeQuery(endTime int64) *bleve.SearchRequest {
+	endTimeFloat := float64(endTime)
+	inclusive := false
+	query := bleve.NewNumericRangeInclusiveQuery(nil, &endTimeFloat, nil, &inclusive)
+	query.SetField("CreateAt")
+	return bleve.NewSearchRequest(query)
+}
+
+// CountPostsBefore counts the indexed posts created before endTime.
+func (b *BleveEngine) CountPostsBefore(endTime int64) (int64, *model.AppError) {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	search := createdBeforeQuery(endTime)
+	search.Size = 0
+	results, err := b.PostIndex.Search(search)
+	if err != nil {
+		return 0, model.NewAppError("Bleveengine.CountPostsBefore", "bleveengine.count_posts_before.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return int64(results.Total), nil
+}
+
+// CountFilesBefore counts the indexed files created before endTime.
+func (b *BleveEngine) CountFilesBefore(endTime int64) (int64, *model.AppError) {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	search := createdBeforeQuery(endTime)
+	search.Size = 0
+	results, err := b.FileIndex.Search(search)
+	if err != nil {
+		return 0, model.NewAppError("Bleveengine.CountFilesBefore", "bleveengine.count_files_before.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return int64(results.Total), nil
+}
+
+// DeletePostsBefore deletes the posts created before endTime and returns how
+// many were deleted. onBatch, if set, is called after each batch with the
+// number of posts deleted so far.
+func (b *BleveEngine) DeletePostsBefore(endTime int64, onBatch func(deleted int64)) (int64, *model.AppError) {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeletePostsBefore, EndTime: endTime})
+	return b.deletePostsBefore(endTime, onBatch)
+}
+
+func (b *BleveEngine) deletePostsBefore(endTime int64, onBatch func(deleted int64)) (int64, *model.AppError) {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	deleted, err := b.deletePosts(createdBeforeQuery(endTime), DeletePostsBatchSize, onBatch)
+	if err != nil {
+		return 0, model.NewAppError("Bleveengine.DeletePostsBefore", "bleveengine.delete_posts_before.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return deleted, nil
+}
+
+// DeleteFilesBefore deletes the files created before endTime and returns how
+// many were deleted. onBatch, if set, is called after each batch with the
+// number of files deleted so far.
+func (b *BleveEngine) DeleteFilesBefore(endTime int64, onBatch func(deleted int64)) (int64, *model.AppError) {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteFilesBefore, EndTime: endTime})
+	return b.deleteFilesBefore(endTime, onBatch)
+}
+
+func (b *BleveEngine) deleteFilesBefore(endTime int64, onBatch func(deleted int64)) (int64, *model.AppError) {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	deleted, err := b.deleteFiles(createdBeforeQuery(endTime), DeleteFilesBatchSize, onBatch)
+	if err != nil {
+		return 0, model.NewAppError("Bleveengine.DeleteFilesBefore", "bleveengine.delete_files_before.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return deleted, nil
+}
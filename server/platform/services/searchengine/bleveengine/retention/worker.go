@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package retention
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+)
+
+const jobName = "BleveDataRetention"
+
+// MakeWorker makes the worker deleting the posts and files created before the
+// job's "end_time", in milliseconds, from the Bleve indexes. The job is only
+// run on demand: the data retention job removes the posts and files it deletes
+// by policy from the indexes itself, which keeps the posts of the teams and
+// channels with a longer policy than the global one searchable.
+func MakeWorker(jobServer *jobs.JobServer, engine *bleveengine.BleveEngine) *jobs.SimpleWorker {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.BleveSettings.EnableIndexing
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		if !engine.IsActive() {
+			return model.NewAppError("BleveDataRetentionWorker", "bleveengine.indexer.do_job.engine_inactive", nil, "", http.StatusInternalServerError)
+		}
+
+		endTime, err := getEndTime(job)
+		if err != nil {
+			return err
+		}
+
+		totalPosts, appErr := engine.CountPostsBefore(endTime)
+		if appErr != nil {
+			return appErr
+		}
+		totalFiles, appErr := engine.CountFilesBefore(endTime)
+		if appErr != nil {
+			return appErr
+		}
+
+		var deletedPosts, deletedFiles int64
+		reportProgress := func() {
+			if totalPosts+totalFiles == 0 {
+				return
+			}
+			progress := (deletedPosts + deletedFiles) * 100 / (totalPosts + totalFiles)
+			if progress > 99 {
+				progress = 99
+			}
+			if appErr := jobServer.SetJobProgress(job, progress); appErr != nil {
+				logger.Warn("Failed to set the job progress", mlog.Err(appErr))
+			}
+		}
+
+		logger.Info("Deleting old posts from the Bleve index", mlog.Int("end_time", endTime), mlog.Int("total", totalPosts))
+		if _, appErr = engine.DeletePostsBefore(endTime, func(deleted int64) {
+			deletedPosts = deleted
+			reportProgress()
+		}); appErr != nil {
+			return appErr
+		}
+
+		logger.Info("Deleting old files from the Bleve index", mlog.Int("end_time", endTime), mlog.Int("total", totalFiles))
+		if _, appErr = engine.DeleteFilesBefore(endTime, func(deleted int64) {
+			deletedFiles = deleted
+			reportProgress()
+		}); appErr != nil {
+			return appErr
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["deleted_posts"] = strconv.FormatInt(deletedPosts, 10)
+		job.Data["deleted_files"] = strconv.FormatInt(deletedFiles, 10)
+
+		logger.Info("Deleted old posts and files from the Bleve indexes", mlog.Int("deleted_posts", deletedPosts), mlog.Int("deleted_files", deletedFiles))
+		return nil
+	}
+	return jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+}
+
+// getEndTime returns the time before which posts and files are deleted.
+func getEndTime(job *model.Job) (int64, error) {
+	endTime, err := strconv.ParseInt(job.Data["end_time"], 10, 64)
+	if err != nil || endTime <= 0 {
+		return 0, model.NewAppError("BleveDataRetentionWorker", "bleveengine.data_retention.parse_end_time.error", nil, "end_time="+job.Data["end_time"], http.StatusBadRequest).Wrap(err)
+	}
+	return endTime, nil
+}
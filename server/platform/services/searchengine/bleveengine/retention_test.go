@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+func TestDeleteBefore(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	channelID := model.NewId()
+	userID := model.NewId()
+
+	var oldPosts []*model.Post
+	for i := 0; i < 3; i++ {
+		post := createPost(userID, channelID)
+		post.CreateAt = int64(1000 + i)
+		require.Nil(t, engine.IndexPost(post, model.NewId()))
+		oldPosts = append(oldPosts, post)
+	}
+	recentPost := createPost(userID, channelID)
+	recentPost.CreateAt = 2000
+	require.Nil(t, engine.IndexPost(recentPost, model.NewId()))
+
+	for _, createAt := range []int64{1000, 2000} {
+		file := &model.FileInfo{Id: model.NewId(), CreatorId: userID, CreateAt: createAt, Name: "file.txt", Extension: "txt"}
+		require.Nil(t, engine.IndexFile(file, channelID))
+	}
+
+	t.Run("posts", func(t *testing.T) {
+		count, appErr := engine.CountPostsBefore(2000)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(3), count)
+
+		var batches []int64
+		deleted, appErr := engine.DeletePostsBefore(2000, func(deleted int64) {
+			batches = append(batches, deleted)
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(3), deleted)
+		assert.Equal(t, []int64{3}, batches)
+
+		for _, post := range oldPosts {
+			doc, err := engine.PostIndex.Document(post.Id)
+			require.NoError(t, err)
+			assert.Nil(t, doc)
+		}
+		doc, err := engine.PostIndex.Document(recentPost.Id)
+		require.NoError(t, err)
+		assert.NotNil(t, doc)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		count, appErr := engine.CountFilesBefore(2000)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), count)
+
+		deleted, appErr := engine.DeleteFilesBefore(2000, nil)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), deleted)
+
+		numberDocs, err := engine.FileIndex.DocCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), numberDocs)
+	})
+}
+
+func TestPurgeIndexList(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	rctx := request.TestContext(t)
+	channel := &model.Channel{Id: model.NewId(), Name: "town-square", DisplayName: "Town Square", Type: model.ChannelTypeOpen, TeamId: model.NewId()}
+	require.Nil(t, engine.IndexChannel(rctx, channel, nil, nil))
+	require.Nil(t, engine.IndexPost(createPost(model.NewId(), channel.Id), model.NewId()))
+
+	t.Run("unknown index", func(t *testing.T) {
+		appErr := engine.PurgeIndexList(rctx, []string{ChannelIndex, "unknown"})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.purge_list.unknown_index", appErr.Id)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+
+		numberDocs, err := engine.ChannelIndex.DocCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), numberDocs)
+	})
+
+	t.Run("only the given indexes are purged", func(t *testing.T) {
+		require.Nil(t, engine.PurgeIndexList(rctx, []string{ChannelIndex}))
+
+		numberDocs, err := engine.ChannelIndex.DocCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), numberDocs)
+
+		numberDocs, err = engine.PostIndex.DocCount()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), numberDocs)
+	})
+}
@@ -5,6 +5,7 @@ package bleveengine
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/blevesearch/bleve/v2"
@@ -18,11 +19,20 @@ import (
 const DeletePostsBatchSize = 500
 const DeleteFilesBatchSize = 500
 
+// postHighlightFields are the fields of the post index the matches of the
+// search results are highlighted in.
+var postHighlightFields = []string{"Message", "Attachments", "Hashtags"}
+
 func (b *BleveEngine) IndexPost(post *model.Post, teamId string) *model.AppError {
+	blvPost := BLVPostFromPost(post, teamId)
+	b.replicate(&clusterIndexOperation{Type: indexOpIndexPost, Post: blvPost})
+	return b.indexPost(blvPost)
+}
+
+func (b *BleveEngine) indexPost(blvPost *BLVPost) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	blvPost := BLVPostFromPost(post, teamId)
 	if err := b.PostIndex.Index(blvPost.Id, blvPost); err != nil {
 		return model.NewAppError("Bleveengine.IndexPost", "bleveengine.index_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -30,6 +40,17 @@ func (b *BleveEngine) IndexPost(post *model.Post, teamId string) *model.AppError
 }
 
 func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
+	if b.forwardsSearches() {
+		res, appErr := b.searchOnOwner(&clusterSearchRequest{Type: searchTypePosts, Channels: channels, SearchParams: searchParams, Page: page, PerPage: perPage})
+		if appErr != nil {
+			return nil, nil, appErr
+		}
+		return res.Ids, res.PostMatches, nil
+	}
+	return b.searchPosts(channels, searchParams, page, perPage)
+}
+
+func (b *BleveEngine) searchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
 	channelQueries := []query.Query{}
 	for _, channel := range channels {
 		channelIdQ := bleve.NewTermQuery(channel.Id)
@@ -145,9 +166,15 @@ func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*mo
 					notFilters = append(notFilters, onDateQ)
 				}
 			}
+
+			postPropertyFilters, postPropertyNotFilters := postPropertyFilterQueries(params)
+			filters = append(filters, postPropertyFilters...)
+			notFilters = append(notFilters, postPropertyNotFilters...)
 		}
 
-		if params.IsHashtag {
+		if params.Query != nil {
+			termQueries = append(termQueries, searchQueryToBleve(params.Query))
+		} else if params.IsHashtag {
 			if params.Terms != "" {
 				hashtagQ := bleve.NewMatchQuery(params.Terms)
 				hashtagQ.SetField("Hashtags")
@@ -191,7 +218,8 @@ func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*mo
 
 	allTermsQ := bleve.NewBooleanQuery()
 	allTermsQ.AddMustNot(notTermQueries...)
-	if searchParams[0].OrTerms {
+	// The query of an advanced search already combines its terms
+	if searchParams[0].OrTerms && searchParams[0].Query == nil {
 		allTermsQ.AddShould(termQueries...)
 	} else {
 		allTermsQ.AddMust(termQueries...)
@@ -213,6 +241,7 @@ func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*mo
 
 	search := bleve.NewSearchRequestOptions(query, perPage, page*perPage, false)
 	search.SortBy([]string{"-CreateAt"})
+	search.Highlight = newHighlightRequest(postHighlightFields...)
 	results, err := b.PostIndex.Search(search)
 	if err != nil {
 		return nil, nil, model.NewAppError("Bleveengine.SearchPosts", "bleveengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
@@ -223,12 +252,118 @@ func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*mo
 
 	for _, r := range results.Hits {
 		postIds = append(postIds, r.ID)
+		if postMatches := getMatchesForFragments(r.Fragments, postHighlightFields...); len(postMatches) > 0 {
+			matches[r.ID] = postMatches
+		}
 	}
 
 	return postIds, matches, nil
 }
 
-func (b *BleveEngine) deletePosts(searchRequest *bleve.SearchRequest, batchSize int) (int64, error) {
+// postPropertyFields are the boolean fields of the has: and is: filters.
+var postPropertyFields = map[string]string{
+	model.SearchHasFile:      "HasFiles",
+	model.SearchHasLink:      "HasLink",
+	model.SearchIsPinned:     "IsPinned",
+	model.SearchIsThreadRoot: "IsThreadRoot",
+}
+
+// postPropertyFilterQueries returns the queries of the has: is: reacted: and
+// priority: filters. All of them have to match, except for the priorities
+// since a post only has one.
+func postPropertyFilterQueries(params *model.SearchParams) ([]query.Query, []query.Query) {
+	propertyQuery := func(value string) query.Query {
+		propertyQ := bleve.NewBoolFieldQuery(true)
+		propertyQ.SetField(postPropertyFields[value])
+		return propertyQ
+	}
+	keywordQuery := func(field string, value string) query.Query {
+		keywordQ := bleve.NewTermQuery(value)
+		keywordQ.SetField(field)
+		return keywordQ
+	}
+	priorityQuery := func(priorities []string) query.Query {
+		priorityQueries := make([]query.Query, 0, len(priorities))
+		for _, priority := range priorities {
+			priorityQueries = append(priorityQueries, keywordQuery("Priority", priority))
+		}
+		return bleve.NewDisjunctionQuery(priorityQueries...)
+	}
+
+	var filters, notFilters []query.Query
+	for _, value := range append(slices.Clone(params.Has), params.Is...) {
+		filters = append(filters, propertyQuery(value))
+	}
+	for _, value := range append(slices.Clone(params.ExcludedHas), params.ExcludedIs...) {
+		notFilters = append(notFilters, propertyQuery(value))
+	}
+	for _, emojiName := range params.Reactions {
+		filters = append(filters, keywordQuery("Reactions", emojiName))
+	}
+	for _, emojiName := range params.ExcludedReactions {
+		notFilters = append(notFilters, keywordQuery("Reactions", emojiName))
+	}
+	if len(params.Priorities) > 0 {
+		filters = append(filters, priorityQuery(params.Priorities))
+	}
+	if len(params.ExcludedPriorities) > 0 {
+		notFilters = append(notFilters, priorityQuery(params.ExcludedPriorities))
+	}
+
+	return filters, notFilters
+}
+
+// searchQueryToBleve compiles the syntax tree of an advanced search into a
+// query on the messages.
+func searchQueryToBleve(searchQuery *model.SearchQuery) query.Query {
+	switch searchQuery.Type {
+	case model.SearchQueryTypeAnd:
+		andQ := bleve.NewBooleanQuery()
+		for _, child := range searchQuery.Children {
+			if child.Type == model.SearchQueryTypeNot {
+				andQ.AddMustNot(searchQueryToBleve(child.Children[0]))
+			} else {
+				andQ.AddMust(searchQueryToBleve(child))
+			}
+		}
+		return andQ
+
+	case model.SearchQueryTypeOr:
+		alternatives := make([]query.Query, 0, len(searchQuery.Children))
+		for _, child := range searchQuery.Children {
+			alternatives = append(alternatives, searchQueryToBleve(child))
+		}
+		return bleve.NewDisjunctionQuery(alternatives...)
+
+	case model.SearchQueryTypeNot:
+		notQ := bleve.NewBooleanQuery()
+		notQ.AddMustNot(searchQueryToBleve(searchQuery.Children[0]))
+		return notQ
+
+	case model.SearchQueryTypePhrase:
+		phraseQ := bleve.NewMatchPhraseQuery(searchQuery.Value)
+		phraseQ.SetField("Message")
+		return phraseQ
+
+	case model.SearchQueryTypeRegexp:
+		regexpQ := bleve.NewRegexpQuery("(?i)" + searchQuery.Value)
+		regexpQ.SetField("Message")
+		return regexpQ
+	}
+
+	if strings.HasSuffix(searchQuery.Value, "*") {
+		wildcardQ := bleve.NewWildcardQuery(strings.ToLower(searchQuery.Value))
+		wildcardQ.SetField("Message")
+		return wildcardQ
+	}
+	messageQ := bleve.NewMatchQuery(searchQuery.Value)
+	messageQ.SetField("Message")
+	return messageQ
+}
+
+// deletePosts deletes the posts matching the search request, calling onBatch,
+// if set, with the number of posts deleted so far after each batch.
+func (b *BleveEngine) deletePosts(searchRequest *bleve.SearchRequest, batchSize int, onBatch func(deleted int64)) (int64, error) {
 	resultsCount := int64(0)
 
 	for {
@@ -248,6 +383,9 @@ func (b *BleveEngine) deletePosts(searchRequest *bleve.SearchRequest, batchSize
 			return -1, err
 		}
 		resultsCount += int64(results.Hits.Len())
+		if onBatch != nil {
+			onBatch(resultsCount)
+		}
 		if results.Hits.Len() < batchSize {
 			break
 		}
@@ -257,13 +395,18 @@ func (b *BleveEngine) deletePosts(searchRequest *bleve.SearchRequest, batchSize
 }
 
 func (b *BleveEngine) DeleteChannelPosts(rctx request.CTX, channelID string) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteChannelPosts, Id: channelID})
+	return b.deleteChannelPosts(rctx, channelID)
+}
+
+func (b *BleveEngine) deleteChannelPosts(rctx request.CTX, channelID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
 	query := bleve.NewTermQuery(channelID)
 	query.SetField("ChannelId")
 	search := bleve.NewSearchRequest(query)
-	deleted, err := b.deletePosts(search, DeletePostsBatchSize)
+	deleted, err := b.deletePosts(search, DeletePostsBatchSize, nil)
 	if err != nil {
 		return model.NewAppError("Bleveengine.DeleteChannelPosts",
 			"bleveengine.delete_channel_posts.error", nil,
@@ -276,13 +419,18 @@ func (b *BleveEngine) DeleteChannelPosts(rctx request.CTX, channelID string) *mo
 }
 
 func (b *BleveEngine) DeleteUserPosts(rctx request.CTX, userID string) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteUserPosts, Id: userID})
+	return b.deleteUserPosts(rctx, userID)
+}
+
+func (b *BleveEngine) deleteUserPosts(rctx request.CTX, userID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
 	query := bleve.NewTermQuery(userID)
 	query.SetField("UserId")
 	search := bleve.NewSearchRequest(query)
-	deleted, err := b.deletePosts(search, DeletePostsBatchSize)
+	deleted, err := b.deletePosts(search, DeletePostsBatchSize, nil)
 	if err != nil {
 		return model.NewAppError("Bleveengine.DeleteUserPosts",
 			"bleveengine.delete_user_posts.error", nil,
@@ -295,27 +443,48 @@ func (b *BleveEngine) DeleteUserPosts(rctx request.CTX, userID string) *model.Ap
 }
 
 func (b *BleveEngine) DeletePost(post *model.Post) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeletePost, Id: post.Id})
+	return b.deletePost(post.Id)
+}
+
+func (b *BleveEngine) deletePost(postID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	if err := b.PostIndex.Delete(post.Id); err != nil {
+	if err := b.PostIndex.Delete(postID); err != nil {
 		return model.NewAppError("Bleveengine.DeletePost", "bleveengine.delete_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return nil
 }
 
 func (b *BleveEngine) IndexChannel(_ request.CTX, channel *model.Channel, userIDs, teamMemberIDs []string) *model.AppError {
+	blvChannel := BLVChannelFromChannel(channel, userIDs, teamMemberIDs)
+	b.replicate(&clusterIndexOperation{Type: indexOpIndexChannel, Channel: blvChannel})
+	return b.indexChannel(blvChannel)
+}
+
+func (b *BleveEngine) indexChannel(blvChannel *BLVChannel) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	blvChannel := BLVChannelFromChannel(channel, userIDs, teamMemberIDs)
 	if err := b.ChannelIndex.Index(blvChannel.Id, blvChannel); err != nil {
 		return model.NewAppError("Bleveengine.IndexChannel", "bleveengine.index_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return nil
 }
 
-func (b *BleveEngine) SearchChannels(teamId, userID, term string, isGuest, _ bool) ([]string, *model.AppError) {
+func (b *BleveEngine) SearchChannels(teamId, userID, term string, isGuest, includeDeleted bool) ([]string, *model.AppError) {
+	if b.forwardsSearches() {
+		res, appErr := b.searchOnOwner(&clusterSearchRequest{Type: searchTypeChannels, TeamId: teamId, UserId: userID, Term: term, IsGuest: isGuest, IncludeDeleted: includeDeleted})
+		if appErr != nil {
+			return nil, appErr
+		}
+		return res.Ids, nil
+	}
+	return b.searchChannels(teamId, userID, term, isGuest, includeDeleted)
+}
+
+func (b *BleveEngine) searchChannels(teamId, userID, term string, isGuest, _ bool) ([]string, *model.AppError) {
 	// This query essentially boils down to (if teamID is passed):
 	// match teamID == <>
 	// AND
@@ -373,9 +542,7 @@ func (b *BleveEngine) SearchChannels(teamId, userID, term string, isGuest, _ boo
 	}
 
 	if term != "" {
-		nameSuggestQ := bleve.NewPrefixQuery(strings.ToLower(term))
-		nameSuggestQ.SetField("NameSuggest")
-		queries = append(queries, nameSuggestQ)
+		queries = append(queries, nameTermQuery(term, "NameSuggest", "DisplayName"))
 	}
 
 	query := bleve.NewSearchRequest(bleve.NewConjunctionQuery(queries...))
@@ -393,21 +560,56 @@ func (b *BleveEngine) SearchChannels(teamId, userID, term string, isGuest, _ boo
 	return channelIds, nil
 }
 
+// nameTermQuery matches the term as the prefix of one of the suggestions, or
+// as text of the name fields analyzed the way the index is configured to. The
+// latter finds names whose words aren't separated by spaces, like CJK names.
+func nameTermQuery(term, suggestionsField string, nameFields ...string) query.Query {
+	suggestionsQ := bleve.NewPrefixQuery(strings.ToLower(term))
+	suggestionsQ.SetField(suggestionsField)
+
+	termQ := bleve.NewDisjunctionQuery(suggestionsQ)
+	for _, field := range nameFields {
+		nameQ := bleve.NewMatchQuery(term)
+		nameQ.SetField(field)
+		nameQ.SetOperator(query.MatchQueryOperatorAnd)
+		termQ.AddQuery(nameQ)
+	}
+
+	return termQ
+}
+
+func userTermQuery(term string, allowFullNames bool) query.Query {
+	if allowFullNames {
+		return nameTermQuery(term, "SuggestionsWithFullname", "Nickname", "FullName")
+	}
+	return nameTermQuery(term, "SuggestionsWithoutFullname", "Nickname")
+}
+
 func (b *BleveEngine) DeleteChannel(channel *model.Channel) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteChannel, Id: channel.Id})
+	return b.deleteChannel(channel.Id)
+}
+
+func (b *BleveEngine) deleteChannel(channelID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	if err := b.ChannelIndex.Delete(channel.Id); err != nil {
+	if err := b.ChannelIndex.Delete(channelID); err != nil {
 		return model.NewAppError("Bleveengine.DeleteChannel", "bleveengine.delete_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return nil
 }
 
 func (b *BleveEngine) IndexUser(_ request.CTX, user *model.User, teamsIds, channelsIds []string) *model.AppError {
+	blvUser := BLVUserFromUserAndTeams(user, teamsIds, channelsIds)
+	b.replicate(&clusterIndexOperation{Type: indexOpIndexUser, User: blvUser})
+	return b.indexUser(blvUser)
+}
+
+func (b *BleveEngine) indexUser(blvUser *BLVUser) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	blvUser := BLVUserFromUserAndTeams(user, teamsIds, channelsIds)
 	if err := b.UserIndex.Index(blvUser.Id, blvUser); err != nil {
 		return model.NewAppError("Bleveengine.IndexUser", "bleveengine.index_user.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
@@ -415,6 +617,17 @@ func (b *BleveEngine) IndexUser(_ request.CTX, user *model.User, teamsIds, chann
 }
 
 func (b *BleveEngine) SearchUsersInChannel(teamId, channelId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, []string, *model.AppError) {
+	if b.forwardsSearches() {
+		res, appErr := b.searchOnOwner(&clusterSearchRequest{Type: searchTypeUsersInChannel, TeamId: teamId, ChannelId: channelId, RestrictedToChannels: restrictedToChannels, Term: term, UserSearchOptions: options})
+		if appErr != nil {
+			return nil, nil, appErr
+		}
+		return res.Ids, res.NotInChannelIds, nil
+	}
+	return b.searchUsersInChannel(teamId, channelId, restrictedToChannels, term, options)
+}
+
+func (b *BleveEngine) searchUsersInChannel(teamId, channelId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, []string, *model.AppError) {
 	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
 		return []string{}, []string{}, nil
 	}
@@ -422,12 +635,7 @@ func (b *BleveEngine) SearchUsersInChannel(teamId, channelId string, restrictedT
 	// users in channel
 	var queries []query.Query
 	if term != "" {
-		termQ := bleve.NewPrefixQuery(strings.ToLower(term))
-		if options.AllowFullNames {
-			termQ.SetField("SuggestionsWithFullname")
-		} else {
-			termQ.SetField("SuggestionsWithoutFullname")
-		}
+		termQ := userTermQuery(term, options.AllowFullNames)
 		queries = append(queries, termQ)
 	}
 
@@ -448,12 +656,7 @@ func (b *BleveEngine) SearchUsersInChannel(teamId, channelId string, restrictedT
 	boolQ := bleve.NewBooleanQuery()
 
 	if term != "" {
-		termQ := bleve.NewPrefixQuery(strings.ToLower(term))
-		if options.AllowFullNames {
-			termQ.SetField("SuggestionsWithFullname")
-		} else {
-			termQ.SetField("SuggestionsWithoutFullname")
-		}
+		termQ := userTermQuery(term, options.AllowFullNames)
 		boolQ.AddMust(termQ)
 	}
 
@@ -495,6 +698,17 @@ func (b *BleveEngine) SearchUsersInChannel(teamId, channelId string, restrictedT
 }
 
 func (b *BleveEngine) SearchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError) {
+	if b.forwardsSearches() {
+		res, appErr := b.searchOnOwner(&clusterSearchRequest{Type: searchTypeUsersInTeam, TeamId: teamId, RestrictedToChannels: restrictedToChannels, Term: term, UserSearchOptions: options})
+		if appErr != nil {
+			return nil, appErr
+		}
+		return res.Ids, nil
+	}
+	return b.searchUsersInTeam(teamId, restrictedToChannels, term, options)
+}
+
+func (b *BleveEngine) searchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError) {
 	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
 		return []string{}, nil
 	}
@@ -506,12 +720,7 @@ func (b *BleveEngine) SearchUsersInTeam(teamId string, restrictedToChannels []st
 		boolQ := bleve.NewBooleanQuery()
 
 		if term != "" {
-			termQ := bleve.NewPrefixQuery(strings.ToLower(term))
-			if options.AllowFullNames {
-				termQ.SetField("SuggestionsWithFullname")
-			} else {
-				termQ.SetField("SuggestionsWithoutFullname")
-			}
+			termQ := userTermQuery(term, options.AllowFullNames)
 			boolQ.AddMust(termQ)
 		}
 
@@ -553,27 +762,48 @@ func (b *BleveEngine) SearchUsersInTeam(teamId string, restrictedToChannels []st
 }
 
 func (b *BleveEngine) DeleteUser(user *model.User) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteUser, Id: user.Id})
+	return b.deleteUser(user.Id)
+}
+
+func (b *BleveEngine) deleteUser(userID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	if err := b.UserIndex.Delete(user.Id); err != nil {
+	if err := b.UserIndex.Delete(userID); err != nil {
 		return model.NewAppError("Bleveengine.DeleteUser", "bleveengine.delete_user.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return nil
 }
 
 func (b *BleveEngine) IndexFile(file *model.FileInfo, channelId string) *model.AppError {
+	blvFile := BLVFileFromFileInfo(file, channelId)
+	b.replicate(&clusterIndexOperation{Type: indexOpIndexFile, File: blvFile})
+	return b.indexFile(blvFile)
+}
+
+func (b *BleveEngine) indexFile(blvFile *BLVFile) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
-	blvFile := BLVFileFromFileInfo(file, channelId)
 	if err := b.FileIndex.Index(blvFile.Id, blvFile); err != nil {
 		return model.NewAppError("Bleveengine.IndexFile", "bleveengine.index_file.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 	return nil
 }
 
-func (b *BleveEngine) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+func (b *BleveEngine) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError) {
+	if b.forwardsSearches() {
+		res, appErr := b.searchOnOwner(&clusterSearchRequest{Type: searchTypeFiles, Channels: channels, SearchParams: searchParams, Page: page, PerPage: perPage})
+		if appErr != nil {
+			return nil, nil, appErr
+		}
+		return res.Ids, res.FileSnippets, nil
+	}
+	return b.searchFiles(channels, searchParams, page, perPage)
+}
+
+func (b *BleveEngine) searchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError) {
 	channelQueries := []query.Query{}
 	for _, channel := range channels {
 		channelIdQ := bleve.NewTermQuery(channel.Id)
@@ -767,21 +997,34 @@ func (b *BleveEngine) SearchFiles(channels model.ChannelList, searchParams []*mo
 
 	search := bleve.NewSearchRequestOptions(query, perPage, page*perPage, false)
 	search.SortBy([]string{"-CreateAt"})
+	search.Highlight = newHighlightRequest("Content")
 	results, err := b.FileIndex.Search(search)
 	if err != nil {
-		return nil, model.NewAppError("Bleveengine.SearchFiles", "bleveengine.search_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return nil, nil, model.NewAppError("Bleveengine.SearchFiles", "bleveengine.search_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
 	fileIds := []string{}
+	snippets := model.FileInfoSearchSnippets{}
 
 	for _, r := range results.Hits {
 		fileIds = append(fileIds, r.ID)
+		// Fragments without a highlighted term are only the start of the content
+		for _, fragment := range r.Fragments["Content"] {
+			if strings.Contains(fragment, highlightStart) {
+				snippets[r.ID] = append(snippets[r.ID], fragment)
+			}
+		}
 	}
 
-	return fileIds, nil
+	return fileIds, snippets, nil
 }
 
 func (b *BleveEngine) DeleteFile(fileID string) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteFile, Id: fileID})
+	return b.deleteFile(fileID)
+}
+
+func (b *BleveEngine) deleteFile(fileID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
@@ -791,7 +1034,9 @@ func (b *BleveEngine) DeleteFile(fileID string) *model.AppError {
 	return nil
 }
 
-func (b *BleveEngine) deleteFiles(searchRequest *bleve.SearchRequest, batchSize int) (int64, error) {
+// deleteFiles deletes the files matching the search request, calling onBatch,
+// if set, with the number of files deleted so far after each batch.
+func (b *BleveEngine) deleteFiles(searchRequest *bleve.SearchRequest, batchSize int, onBatch func(deleted int64)) (int64, error) {
 	resultsCount := int64(0)
 
 	for {
@@ -811,6 +1056,9 @@ func (b *BleveEngine) deleteFiles(searchRequest *bleve.SearchRequest, batchSize
 			return -1, err
 		}
 		resultsCount += int64(results.Hits.Len())
+		if onBatch != nil {
+			onBatch(resultsCount)
+		}
 		if results.Hits.Len() < batchSize {
 			break
 		}
@@ -820,13 +1068,18 @@ func (b *BleveEngine) deleteFiles(searchRequest *bleve.SearchRequest, batchSize
 }
 
 func (b *BleveEngine) DeleteUserFiles(rctx request.CTX, userID string) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteUserFiles, Id: userID})
+	return b.deleteUserFiles(rctx, userID)
+}
+
+func (b *BleveEngine) deleteUserFiles(rctx request.CTX, userID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
 	query := bleve.NewTermQuery(userID)
 	query.SetField("CreatorId")
 	search := bleve.NewSearchRequest(query)
-	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize)
+	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize, nil)
 	if err != nil {
 		return model.NewAppError("Bleveengine.DeleteUserFiles",
 			"bleveengine.delete_user_files.error", nil,
@@ -839,13 +1092,18 @@ func (b *BleveEngine) DeleteUserFiles(rctx request.CTX, userID string) *model.Ap
 }
 
 func (b *BleveEngine) DeletePostFiles(rctx request.CTX, postID string) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeletePostFiles, Id: postID})
+	return b.deletePostFiles(rctx, postID)
+}
+
+func (b *BleveEngine) deletePostFiles(rctx request.CTX, postID string) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
 	query := bleve.NewTermQuery(postID)
 	query.SetField("PostId")
 	search := bleve.NewSearchRequest(query)
-	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize)
+	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize, nil)
 	if err != nil {
 		return model.NewAppError("Bleveengine.DeletePostFiles",
 			"bleveengine.delete_post_files.error", nil,
@@ -858,6 +1116,11 @@ func (b *BleveEngine) DeletePostFiles(rctx request.CTX, postID string) *model.Ap
 }
 
 func (b *BleveEngine) DeleteFilesBatch(rctx request.CTX, endTime, limit int64) *model.AppError {
+	b.replicate(&clusterIndexOperation{Type: indexOpDeleteFilesBatch, EndTime: endTime, Limit: limit})
+	return b.deleteFilesBatch(rctx, endTime, limit)
+}
+
+func (b *BleveEngine) deleteFilesBatch(rctx request.CTX, endTime, limit int64) *model.AppError {
 	b.Mutex.RLock()
 	defer b.Mutex.RUnlock()
 
@@ -867,7 +1130,7 @@ func (b *BleveEngine) DeleteFilesBatch(rctx request.CTX, endTime, limit int64) *
 	search := bleve.NewSearchRequestOptions(query, int(limit), 0, false)
 	search.SortBy([]string{"-CreateAt"})
 
-	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize)
+	deleted, err := b.deleteFiles(search, DeleteFilesBatchSize, nil)
 	if err != nil {
 		return model.NewAppError("Bleveengine.DeleteFilesBatch",
 			"bleveengine.delete_files_batch.error", nil,
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSearchPostsWithQuery(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	alerts := &model.Channel{Id: model.NewId()}
+	other := &model.Channel{Id: model.NewId()}
+	userID := model.NewId()
+
+	indexPost := func(channel *model.Channel, message string) string {
+		post := createPost(userID, channel.Id)
+		post.Message = message
+		require.Nil(t, engine.IndexPost(post, model.NewId()))
+		return post.Id
+	}
+	errorPost := indexPost(alerts, "Disk error on the database server")
+	failurePost := indexPost(alerts, "Backup failure, see the known issue")
+	errnoPost := indexPost(alerts, "Errno 28 when writing the logs")
+	deployPost := indexPost(alerts, "Deployment of the failover done")
+	otherPost := indexPost(other, "Same error in another channel")
+
+	for _, testCase := range []struct {
+		Terms    string
+		Expected []string
+	}{
+		{`error OR failure -"known issue"`, []string{errorPost, otherPost}},
+		{`error OR failure`, []string{errorPost, failurePost, otherPost}},
+		{`(disk OR backup) -(failure OR logs)`, []string{errorPost}},
+		{`/err(or|no)/`, []string{errorPost, errnoPost, otherPost}},
+		{`/fail.*/ -failure`, []string{deployPost}},
+		{`(deploy* OR errno) -"failover done"`, []string{errnoPost}},
+	} {
+		t.Run(testCase.Terms, func(t *testing.T) {
+			paramsList, appErr := model.ParseSearchParamsWithQuery(testCase.Terms, 0)
+			require.Nil(t, appErr)
+
+			ids, _, appErr := engine.SearchPosts(model.ChannelList{alerts, other}, paramsList, 0, 20)
+			require.Nil(t, appErr)
+			assert.ElementsMatch(t, testCase.Expected, ids)
+		})
+	}
+}
+
+func TestSearchPostsWithPostPropertyFilters(t *testing.T) {
+	engine := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, engine.Start())
+	defer engine.Stop()
+
+	channel := &model.Channel{Id: model.NewId()}
+	userID := model.NewId()
+
+	indexPost := func(message string, update func(post *model.Post)) string {
+		post := createPost(userID, channel.Id)
+		post.Message = message
+		post.Metadata = &model.PostMetadata{}
+		update(post)
+		require.Nil(t, engine.IndexPost(post, model.NewId()))
+		return post.Id
+	}
+	filePost := indexPost("release notes attached", func(post *model.Post) {
+		post.FileIds = model.StringArray{model.NewId()}
+	})
+	linkPost := indexPost("release notes at https://example.com/notes", func(post *model.Post) {})
+	pinnedPost := indexPost("release checklist", func(post *model.Post) {
+		post.IsPinned = true
+		post.Metadata.Reactions = []*model.Reaction{{EmojiName: "tada"}, {EmojiName: "eyes", DeleteAt: 1}}
+	})
+	rootPost := indexPost("release discussion", func(post *model.Post) {
+		post.ReplyCount = 2
+		post.Metadata.Priority = &model.PostPriority{Priority: model.NewPointer(model.PostPriorityUrgent)}
+	})
+	importantPost := indexPost("release blocker", func(post *model.Post) {
+		post.Metadata.Priority = &model.PostPriority{Priority: model.NewPointer(model.PostPriorityImportant)}
+		post.Metadata.Reactions = []*model.Reaction{{EmojiName: "eyes"}}
+	})
+
+	for _, testCase := range []struct {
+		Terms    string
+		Expected []string
+	}{
+		{"release has:file", []string{filePost}},
+		{"has:link", []string{linkPost}},
+		{"release -has:file -has:link", []string{pinnedPost, rootPost, importantPost}},
+		{"is:pinned", []string{pinnedPost}},
+		{"release is:thread-root", []string{rootPost}},
+		{"reacted:tada", []string{pinnedPost}},
+		{"reacted::eyes:", []string{importantPost}},
+		{"release -reacted:eyes", []string{filePost, linkPost, pinnedPost, rootPost}},
+		{"priority:urgent", []string{rootPost}},
+		{"priority:urgent priority:important", []string{rootPost, importantPost}},
+		{"release -priority:standard", []string{rootPost, importantPost}},
+		{"(checklist OR discussion) is:pinned", []string{pinnedPost}},
+	} {
+		t.Run(testCase.Terms, func(t *testing.T) {
+			paramsList, appErr := model.ParseSearchParamsWithQuery(testCase.Terms, 0)
+			require.Nil(t, appErr)
+
+			ids, _, appErr := engine.SearchPosts(model.ChannelList{channel}, paramsList, 0, 20)
+			require.Nil(t, appErr)
+			assert.ElementsMatch(t, testCase.Expected, ids)
+		})
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const snapshotManifestName = "manifest.json"
+
+// snapshotIndexes holds the indexes a snapshot is made of.
+var snapshotIndexes = []string{PostIndex, FileIndex, ChannelIndex, UserIndex}
+
+// Snapshot writes to w a zip archive of a consistent copy of every index,
+// taken without stopping the engine, and returns the description of the
+// snapshot. When the indexes are shared, only their owner has a complete copy
+// to take the snapshot from.
+func (b *BleveEngine) Snapshot(rctx request.CTX, w io.Writer) (*model.BleveSnapshot, *model.AppError) {
+	if !b.IsIndexOwner() {
+		return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.not_owner.error", nil, "", http.StatusConflict)
+	}
+
+	dir, err := os.MkdirTemp("", "bleve_snapshot")
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	defer os.RemoveAll(dir)
+
+	snapshot, appErr := b.copyIndexes(dir)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if err := writeSnapshotArchive(w, dir, snapshot); err != nil {
+		return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.write.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Took a snapshot of the Bleve indexes", mlog.Any("doc_counts", snapshot.DocCounts))
+	return snapshot, nil
+}
+
+// copyIndexes copies every index to its own directory of dir. The number of
+// documents of the copies is counted from the copies themselves, as the
+// indexes keep being updated while they are copied.
+func (b *BleveEngine) copyIndexes(dir string) (*model.BleveSnapshot, *model.AppError) {
+	b.Mutex.RLock()
+	defer b.Mutex.RUnlock()
+
+	if !b.IsActive() {
+		return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.engine_inactive.error", nil, "", http.StatusBadRequest)
+	}
+
+	snapshot := &model.BleveSnapshot{
+		Version:       model.BleveSnapshotVersion,
+		ServerVersion: model.CurrentVersion,
+		CreateAt:      model.GetMillis(),
+		SettingsHash:  snapshotSettingsHash(b.cfg.BleveSettings),
+		DocCounts:     make(map[string]uint64, len(snapshotIndexes)),
+	}
+
+	mappings := make([]mapping.IndexMapping, 0, len(snapshotIndexes))
+	for _, indexName := range snapshotIndexes {
+		mappings = append(mappings, (*b.getIndex(indexName)).Mapping())
+
+		index, ok := (*b.getIndex(indexName)).(bleve.IndexCopyable)
+		if !ok {
+			return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "the index can't be copied: "+indexName, http.StatusInternalServerError)
+		}
+
+		indexDir := filepath.Join(dir, indexName+".bleve")
+		if err := os.MkdirAll(indexDir, 0700); err != nil {
+			return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		if err := index.CopyTo(bleve.FileSystemDirectory(indexDir)); err != nil {
+			return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		count, _, err := inspectIndex(indexDir)
+		if err != nil {
+			return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		snapshot.DocCounts[indexName] = count
+	}
+
+	mappingHash, err := snapshotMappingHash(mappings)
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.Snapshot", "bleveengine.snapshot.copy.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	snapshot.MappingHash = mappingHash
+
+	return snapshot, nil
+}
+
+// inspectIndex opens the index of a directory to count its documents and
+// read the mapping it was created with.
+func inspectIndex(indexDir string) (uint64, mapping.IndexMapping, error) {
+	index, err := bleve.Open(indexDir)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer index.Close()
+
+	count, err := index.DocCount()
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, index.Mapping(), nil
+}
+
+// snapshotMappingHash hashes the mappings of the indexes, in the order of
+// snapshotIndexes, for a snapshot to only be restored where the indexes are
+// created with the same mappings.
+func snapshotMappingHash(mappings []mapping.IndexMapping) (string, error) {
+	hash := sha256.New()
+	for _, indexMapping := range mappings {
+		mappingJSON, err := json.Marshal(indexMapping)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(mappingJSON)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// expectedMappingHash hashes the mappings the indexes are created with under
+// the given settings.
+func expectedMappingHash(settings model.BleveSettings) (string, error) {
+	mappings := make([]mapping.IndexMapping, 0, len(snapshotIndexes))
+	for _, indexName := range snapshotIndexes {
+		indexMapping, err := getIndexMapping(indexName, settings)
+		if err != nil {
+			return "", err
+		}
+		mappings = append(mappings, indexMapping)
+	}
+	return snapshotMappingHash(mappings)
+}
+
+// snapshotSettingsHash hashes the settings changing how the documents are
+// analyzed, which are those textAnalysisChanged compares.
+func snapshotSettingsHash(settings model.BleveSettings) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%t",
+		*settings.PostIndexAnalyzer,
+		*settings.FileIndexAnalyzer,
+		*settings.ChannelIndexAnalyzer,
+		*settings.UserIndexAnalyzer,
+		*settings.PreserveNumericTokens,
+	)))
+	return hex.EncodeToString(hash[:])
+}
+
+// writeSnapshotArchive writes the manifest describing the snapshot followed
+// by the files of dir.
+func writeSnapshotArchive(w io.Writer, dir string, snapshot *model.BleveSnapshot) error {
+	zipWriter := zip.NewWriter(w)
+
+	manifestWriter, err := zipWriter.Create(snapshotManifestName)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(manifestWriter).Encode(snapshot); err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(dir, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		fileWriter, err := zipWriter.Create(filepath.ToSlash(relPath))
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(fileWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zipWriter.Close()
+}
+
+// RestoreSnapshot replaces every index by the one of a snapshot archive. The
+// snapshot is checked to be of the supported version, to be taken with the
+// current text analysis settings and mappings, and its indexes to hold the
+// documents and mappings it was taken with before the current indexes are
+// replaced.
+func (b *BleveEngine) RestoreSnapshot(rctx request.CTX, r io.ReaderAt, size int64) (*model.BleveSnapshot, *model.AppError) {
+	if *b.cfg.BleveSettings.IndexDir == "" {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.no_index_dir.error", nil, "", http.StatusBadRequest)
+	}
+
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.invalid.error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	snapshot, err := readSnapshotManifest(zipReader)
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.invalid.error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+	if snapshot.Version != model.BleveSnapshotVersion {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.version.error", map[string]any{"Version": snapshot.Version, "ExpectedVersion": model.BleveSnapshotVersion}, "", http.StatusBadRequest)
+	}
+	if snapshot.SettingsHash != snapshotSettingsHash(b.cfg.BleveSettings) {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.settings.error", nil, "", http.StatusBadRequest)
+	}
+	mappingHash, err := expectedMappingHash(b.cfg.BleveSettings)
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.mapping.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if snapshot.MappingHash != mappingHash {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.mapping.error", nil, "", http.StatusBadRequest)
+	}
+
+	// The indexes are extracted next to the current ones for them to be
+	// moved in place
+	if err = os.MkdirAll(*b.cfg.BleveSettings.IndexDir, 0700); err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.extract.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	dir, err := os.MkdirTemp(*b.cfg.BleveSettings.IndexDir, ".restore")
+	if err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.extract.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = extractSnapshotArchive(zipReader, dir); err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.extract.error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	// The manifest is only trusted once the indexes are found to match it
+	mappings := make([]mapping.IndexMapping, 0, len(snapshotIndexes))
+	for _, indexName := range snapshotIndexes {
+		count, indexMapping, err := inspectIndex(filepath.Join(dir, indexName+".bleve"))
+		if err != nil {
+			return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.open_index.error", map[string]any{"Index": indexName}, "", http.StatusBadRequest).Wrap(err)
+		}
+		if count != snapshot.DocCounts[indexName] {
+			return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.doc_count.error", map[string]any{"Index": indexName}, fmt.Sprintf("expected=%d, found=%d", snapshot.DocCounts[indexName], count), http.StatusBadRequest)
+		}
+		mappings = append(mappings, indexMapping)
+	}
+	if indexesHash, err := snapshotMappingHash(mappings); err != nil || indexesHash != snapshot.MappingHash {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.mapping.error", nil, "", http.StatusBadRequest).Wrap(err)
+	}
+
+	if err = b.replaceIndexes(dir); err != nil {
+		return nil, model.NewAppError("Bleveengine.RestoreSnapshot", "bleveengine.restore_snapshot.replace.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Restored a snapshot of the Bleve indexes", mlog.Int("create_at", snapshot.CreateAt), mlog.String("server_version", snapshot.ServerVersion))
+	return snapshot, nil
+}
+
+func readSnapshotManifest(zipReader *zip.Reader) (*model.BleveSnapshot, error) {
+	manifestFile, err := zipReader.Open(snapshotManifestName)
+	if err != nil {
+		return nil, err
+	}
+	defer manifestFile.Close()
+
+	var snapshot model.BleveSnapshot
+	if err := json.NewDecoder(manifestFile).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// extractSnapshotArchive extracts the files of the indexes to dir, rejecting
+// those that would be written outside of their index directory.
+func extractSnapshotArchive(zipReader *zip.Reader, dir string) error {
+	for _, file := range zipReader.File {
+		if file.Name == snapshotManifestName || strings.HasSuffix(file.Name, "/") {
+			continue
+		}
+
+		indexDir, _, _ := strings.Cut(file.Name, "/")
+		if !isSnapshotIndexDir(indexDir) || !filepath.IsLocal(file.Name) || path.Clean(file.Name) != file.Name {
+			return fmt.Errorf("unexpected file %q", file.Name)
+		}
+
+		if err := extractSnapshotFile(file, filepath.Join(dir, filepath.FromSlash(file.Name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSnapshotIndexDir(name string) bool {
+	for _, indexName := range snapshotIndexes {
+		if name == indexName+".bleve" {
+			return true
+		}
+	}
+	return false
+}
+
+func extractSnapshotFile(file *zip.File, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// replaceIndexes moves the indexes of dir in place of the current ones, which
+// are moved back if the new ones can't be opened.
+func (b *BleveEngine) replaceIndexes(dir string) error {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+
+	wasActive := b.IsActive()
+	if appErr := b.closeIndexes(); appErr != nil {
+		return appErr
+	}
+
+	previousDir := filepath.Join(dir, "previous")
+	if err := os.Mkdir(previousDir, 0700); err != nil {
+		return err
+	}
+
+	var replaced []string
+	rollback := func() {
+		for _, indexName := range replaced {
+			if err := os.RemoveAll(b.getIndexDir(indexName)); err != nil {
+				mlog.Error("Failed to remove the restored Bleve index", mlog.String("index", indexName), mlog.Err(err))
+				continue
+			}
+			if err := os.Rename(filepath.Join(previousDir, indexName+".bleve"), b.getIndexDir(indexName)); err != nil && !os.IsNotExist(err) {
+				mlog.Error("Failed to move the previous Bleve index back", mlog.String("index", indexName), mlog.Err(err))
+			}
+		}
+		if wasActive {
+			if appErr := b.openIndexes(); appErr != nil {
+				mlog.Error("Failed to open the previous Bleve indexes", mlog.Err(appErr))
+			}
+		}
+	}
+
+	for _, indexName := range snapshotIndexes {
+		indexDir := b.getIndexDir(indexName)
+		if err := os.Rename(indexDir, filepath.Join(previousDir, indexName+".bleve")); err != nil && !os.IsNotExist(err) {
+			rollback()
+			return err
+		}
+		replaced = append(replaced, indexName)
+
+		if err := os.Rename(filepath.Join(dir, indexName+".bleve"), indexDir); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	if wasActive {
+		if appErr := b.openIndexes(); appErr != nil {
+			rollback()
+			return appErr
+		}
+	}
+
+	return nil
+}
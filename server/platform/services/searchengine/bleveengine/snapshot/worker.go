@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package snapshot
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+)
+
+const workerName = "BleveSnapshot"
+
+type AppIface interface {
+	CreateBleveSnapshot(rctx request.CTX) (*model.BleveSnapshot, *model.AppError)
+}
+
+// Worker takes the snapshots of the Bleve indexes. Unlike a simple worker, it
+// leaves the jobs to the node owning the indexes when they are shared, as
+// only that node has a complete copy of them.
+type Worker struct {
+	stop      chan bool
+	stopped   chan bool
+	jobs      chan model.Job
+	jobServer *jobs.JobServer
+	logger    mlog.LoggerIFace
+	engine    *bleveengine.BleveEngine
+	app       AppIface
+}
+
+func MakeWorker(jobServer *jobs.JobServer, engine *bleveengine.BleveEngine, app AppIface) *Worker {
+	if engine == nil {
+		return nil
+	}
+	return &Worker{
+		stop:      make(chan bool, 1),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+		jobServer: jobServer,
+		logger:    jobServer.Logger().With(mlog.String("worker_name", workerName)),
+		engine:    engine,
+		app:       app,
+	}
+}
+
+func (worker *Worker) Run() {
+	worker.logger.Debug("Worker started")
+
+	defer func() {
+		worker.logger.Debug("Worker finished")
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			worker.logger.Debug("Worker received stop signal")
+			return
+		case job := <-worker.jobs:
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *Worker) Stop() {
+	worker.logger.Debug("Worker stopping")
+	worker.stop <- true
+	<-worker.stopped
+}
+
+func (worker *Worker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *Worker) IsEnabled(cfg *model.Config) bool {
+	return *cfg.BleveSettings.EnableIndexing
+}
+
+func (worker *Worker) DoJob(job *model.Job) {
+	logger := worker.logger.With(jobs.JobLoggerFields(job)...)
+	logger.Debug("Worker: Received a new candidate job.")
+
+	if !worker.engine.IsIndexOwner() {
+		logger.Debug("Worker: Skipping job as this node doesn't own the shared indexes")
+		return
+	}
+
+	var appErr *model.AppError
+	job, appErr = worker.jobServer.ClaimJob(job)
+	if appErr != nil {
+		logger.Warn("Worker: Error occurred while trying to claim job", mlog.Err(appErr))
+		return
+	} else if job == nil {
+		return
+	}
+
+	defer worker.jobServer.HandleJobPanic(logger, job)
+
+	if !worker.engine.IsActive() {
+		worker.setJobError(logger, job, model.NewAppError("BleveSnapshotWorker", "bleveengine.indexer.do_job.engine_inactive", nil, "", http.StatusInternalServerError))
+		return
+	}
+
+	snapshot, appErr := worker.app.CreateBleveSnapshot(request.EmptyContext(logger))
+	if appErr != nil {
+		logger.Error("Worker: Failed to take the Bleve snapshot", mlog.Err(appErr))
+		worker.setJobError(logger, job, appErr)
+		return
+	}
+
+	if job.Data == nil {
+		job.Data = make(model.StringMap)
+	}
+	job.Data["snapshot_name"] = snapshot.Name
+	job.Data["snapshot_size"] = strconv.FormatInt(snapshot.Size, 10)
+
+	logger.Info("Worker: Took a snapshot of the Bleve indexes", mlog.String("snapshot_name", snapshot.Name))
+	// Saves the data of the job along with its progress
+	if err := worker.jobServer.SetJobProgress(job, 100); err != nil {
+		logger.Error("Worker: Failed to update progress for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+		return
+	}
+	if err := worker.jobServer.SetJobSuccess(job); err != nil {
+		logger.Error("Worker: Failed to set success for job", mlog.Err(err))
+		worker.setJobError(logger, job, err)
+	}
+}
+
+func (worker *Worker) setJobError(logger mlog.LoggerIFace, job *model.Job, appError *model.AppError) {
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		logger.Error("Worker: Failed to set job error", mlog.Err(err))
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+func TestSnapshot(t *testing.T) {
+	rctx := request.TestContext(t)
+
+	source := newAnalyzerTestEngine(t, nil)
+	require.Nil(t, source.Start())
+	defer source.Stop()
+
+	channelID := model.NewId()
+	post := createPost(model.NewId(), channelID)
+	post.Message = "snapshotted message"
+	require.Nil(t, source.IndexPost(post, model.NewId()))
+
+	var archive bytes.Buffer
+	snapshot, appErr := source.Snapshot(rctx, &archive)
+	require.Nil(t, appErr)
+	assert.Equal(t, model.BleveSnapshotVersion, snapshot.Version)
+	assert.NotEmpty(t, snapshot.MappingHash)
+	assert.NotEmpty(t, snapshot.SettingsHash)
+	assert.Equal(t, map[string]uint64{PostIndex: 1, FileIndex: 0, ChannelIndex: 0, UserIndex: 0}, snapshot.DocCounts)
+
+	// The engine keeps working after the snapshot
+	other := createPost(model.NewId(), channelID)
+	other.Message = "later message"
+	require.Nil(t, source.IndexPost(other, model.NewId()))
+
+	t.Run("restore", func(t *testing.T) {
+		target := newAnalyzerTestEngine(t, nil)
+		require.Nil(t, target.Start())
+		defer target.Stop()
+
+		unrelated := createPost(model.NewId(), channelID)
+		unrelated.Message = "unrelated message"
+		require.Nil(t, target.IndexPost(unrelated, model.NewId()))
+
+		restored, appErr := target.RestoreSnapshot(rctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.Nil(t, appErr)
+		assert.Equal(t, snapshot.DocCounts, restored.DocCounts)
+
+		require.True(t, target.IsActive())
+		assert.Equal(t, []string{post.Id}, searchMessages(t, target.PostIndex, "message"))
+
+		// The restored indexes keep being updated
+		require.Nil(t, target.IndexPost(other, model.NewId()))
+		assert.ElementsMatch(t, []string{post.Id, other.Id}, searchMessages(t, target.PostIndex, "message"))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		target := newAnalyzerTestEngine(t, nil)
+		require.Nil(t, target.Start())
+		defer target.Stop()
+
+		var invalidArchive bytes.Buffer
+		zipWriter := zip.NewWriter(&invalidArchive)
+		manifestWriter, err := zipWriter.Create(snapshotManifestName)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(manifestWriter).Encode(&model.BleveSnapshot{Version: model.BleveSnapshotVersion + 1}))
+		require.NoError(t, zipWriter.Close())
+
+		_, appErr := target.RestoreSnapshot(rctx, bytes.NewReader(invalidArchive.Bytes()), int64(invalidArchive.Len()))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.restore_snapshot.version.error", appErr.Id)
+		assert.True(t, target.IsActive())
+	})
+
+	t.Run("other text analysis settings", func(t *testing.T) {
+		target := newAnalyzerTestEngine(t, func(settings *model.BleveSettings) {
+			settings.PostIndexAnalyzer = model.NewPointer(model.BleveAnalyzerEnglish)
+		})
+		require.Nil(t, target.Start())
+		defer target.Stop()
+
+		_, appErr := target.RestoreSnapshot(rctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.restore_snapshot.settings.error", appErr.Id)
+		assert.True(t, target.IsActive())
+	})
+
+	t.Run("other mappings", func(t *testing.T) {
+		target := newAnalyzerTestEngine(t, nil)
+		require.Nil(t, target.Start())
+		defer target.Stop()
+
+		otherSnapshot := *snapshot
+		otherSnapshot.MappingHash = "other"
+
+		var invalidArchive bytes.Buffer
+		zipWriter := zip.NewWriter(&invalidArchive)
+		manifestWriter, err := zipWriter.Create(snapshotManifestName)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(manifestWriter).Encode(&otherSnapshot))
+		require.NoError(t, zipWriter.Close())
+
+		_, appErr := target.RestoreSnapshot(rctx, bytes.NewReader(invalidArchive.Bytes()), int64(invalidArchive.Len()))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.restore_snapshot.mapping.error", appErr.Id)
+		assert.True(t, target.IsActive())
+	})
+
+	t.Run("files outside of the indexes", func(t *testing.T) {
+		target := newAnalyzerTestEngine(t, nil)
+		require.Nil(t, target.Start())
+		defer target.Stop()
+
+		var invalidArchive bytes.Buffer
+		zipWriter := zip.NewWriter(&invalidArchive)
+		manifestWriter, err := zipWriter.Create(snapshotManifestName)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(manifestWriter).Encode(snapshot))
+		_, err = zipWriter.Create("posts.bleve/../../outside")
+		require.NoError(t, err)
+		require.NoError(t, zipWriter.Close())
+
+		_, appErr := target.RestoreSnapshot(rctx, bytes.NewReader(invalidArchive.Bytes()), int64(invalidArchive.Len()))
+		require.NotNil(t, appErr)
+		assert.Equal(t, "bleveengine.restore_snapshot.extract.error", appErr.Id)
+		assert.True(t, target.IsActive())
+	})
+}
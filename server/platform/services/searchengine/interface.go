@@ -40,7 +40,7 @@ type SearchEngineInterface interface {
 	SearchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError)
 	DeleteUser(user *model.User) *model.AppError
 	IndexFile(file *model.FileInfo, channelId string) *model.AppError
-	SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError)
+	SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError)
 	DeleteFile(fileID string) *model.AppError
 	DeletePostFiles(rctx request.CTX, postID string) *model.AppError
 	DeleteUserFiles(rctx request.CTX, userID string) *model.AppError
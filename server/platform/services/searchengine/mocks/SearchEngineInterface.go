@@ -572,7 +572,7 @@ func (_m *SearchEngineInterface) SearchChannels(teamId string, userID string, te
 }
 
 // SearchFiles provides a mock function with given fields: channels, searchParams, page, perPage
-func (_m *SearchEngineInterface) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page int, perPage int) ([]string, *model.AppError) {
+func (_m *SearchEngineInterface) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page int, perPage int) ([]string, model.FileInfoSearchSnippets, *model.AppError) {
 	ret := _m.Called(channels, searchParams, page, perPage)
 
 	if len(ret) == 0 {
@@ -580,8 +580,9 @@ func (_m *SearchEngineInterface) SearchFiles(channels model.ChannelList, searchP
 	}
 
 	var r0 []string
-	var r1 *model.AppError
-	if rf, ok := ret.Get(0).(func(model.ChannelList, []*model.SearchParams, int, int) ([]string, *model.AppError)); ok {
+	var r1 model.FileInfoSearchSnippets
+	var r2 *model.AppError
+	if rf, ok := ret.Get(0).(func(model.ChannelList, []*model.SearchParams, int, int) ([]string, model.FileInfoSearchSnippets, *model.AppError)); ok {
 		return rf(channels, searchParams, page, perPage)
 	}
 	if rf, ok := ret.Get(0).(func(model.ChannelList, []*model.SearchParams, int, int) []string); ok {
@@ -592,15 +593,23 @@ func (_m *SearchEngineInterface) SearchFiles(channels model.ChannelList, searchP
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(model.ChannelList, []*model.SearchParams, int, int) *model.AppError); ok {
+	if rf, ok := ret.Get(1).(func(model.ChannelList, []*model.SearchParams, int, int) model.FileInfoSearchSnippets); ok {
 		r1 = rf(channels, searchParams, page, perPage)
 	} else {
 		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*model.AppError)
+			r1 = ret.Get(1).(model.FileInfoSearchSnippets)
 		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(model.ChannelList, []*model.SearchParams, int, int) *model.AppError); ok {
+		r2 = rf(channels, searchParams, page, perPage)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*model.AppError)
+		}
+	}
+
+	return r0, r1, r2
 }
 
 // SearchPosts provides a mock function with given fields: channels, searchParams, page, perPage
@@ -7,6 +7,10 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 )
 
+// bleveEngineName is the name of the Bleve engine, which can't be imported
+// from its package.
+const bleveEngineName = "bleve"
+
 func NewBroker(cfg *model.Config) *Broker {
 	return &Broker{
 		cfg: cfg,
@@ -51,6 +55,25 @@ func (seb *Broker) GetActiveEngines() []SearchEngineInterface {
 	return engines
 }
 
+// SupportsSearchQuery reports whether an engine runs the syntax tree of the
+// advanced searches, which only Bleve does. The other search engines would
+// only look for all of its terms, and the database can't match regular
+// expressions without scanning every message.
+func SupportsSearchQuery(engine SearchEngineInterface) bool {
+	return engine.GetName() == bleveEngineName
+}
+
+// RegexpSearchEnabled reports whether the posts are searched by an engine
+// matching regular expressions.
+func (seb *Broker) RegexpSearchEnabled() bool {
+	for _, engine := range seb.GetActiveEngines() {
+		if engine.IsSearchEnabled() && SupportsSearchQuery(engine) {
+			return true
+		}
+	}
+	return false
+}
+
 func (seb *Broker) ActiveEngine() string {
 	activeEngines := seb.GetActiveEngines()
 	if len(activeEngines) > 0 {
@@ -40,3 +40,31 @@ func TestActiveEngine(t *testing.T) {
 
 	assert.Equal(t, "none", b.ActiveEngine())
 }
+
+func TestRegexpSearchEnabled(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+
+	b := NewBroker(cfg)
+
+	esMock := &mocks.SearchEngineInterface{}
+	esMock.On("IsActive").Return(true)
+	esMock.On("IsSearchEnabled").Return(true)
+	esMock.On("GetName").Return("elasticsearch")
+
+	bleveMock := &mocks.SearchEngineInterface{}
+	bleveMock.On("IsActive").Return(true)
+	bleveMock.On("IsIndexingEnabled").Return(true)
+	bleveMock.On("IsSearchEnabled").Return(true)
+	bleveMock.On("GetName").Return("bleve")
+
+	assert.False(t, b.RegexpSearchEnabled())
+
+	b.ElasticsearchEngine = esMock
+	assert.False(t, b.RegexpSearchEnabled())
+
+	b.BleveEngine = bleveMock
+	assert.True(t, b.RegexpSearchEnabled())
+	assert.False(t, SupportsSearchQuery(esMock))
+	assert.True(t, SupportsSearchQuery(bleveMock))
+}
@@ -79,6 +79,8 @@ const (
 	TrackConfigWrangler            = "config_wrangler"
 	TrackConfigConnectedWorkspaces = "config_connected_workspaces"
 	TrackConfigAccessControl       = "config_access_control"
+	TrackConfigWhitelist           = "config_whitelist"
+	TrackConfigLoginThrottle       = "config_login_throttle"
 	TrackFeatureFlags              = "config_feature_flags"
 	TrackPermissionsGeneral        = "permissions_general"
 	TrackPermissionsSystemScheme   = "permissions_system_scheme"
@@ -666,6 +668,9 @@ func (ts *TelemetryService) trackConfig() {
 		"isabsolute_directory":          filepath.IsAbs(*cfg.FileSettings.Directory),
 		"extract_content":               *cfg.FileSettings.ExtractContent,
 		"archive_recursion":             *cfg.FileSettings.ArchiveRecursion,
+		"extract_content_timeout":       *cfg.FileSettings.ExtractContentTimeoutSeconds,
+		"extract_content_max_memory":    *cfg.FileSettings.ExtractContentMaxMemoryMB,
+		"extract_content_in_worker":     *cfg.FileSettings.ExtractContentInWorker,
 		"amazon_s3_ssl":                 *cfg.FileSettings.AmazonS3SSL,
 		"amazon_s3_sse":                 *cfg.FileSettings.AmazonS3SSE,
 		"amazon_s3_signv2":              *cfg.FileSettings.AmazonS3SignV2,
@@ -713,6 +718,7 @@ func (ts *TelemetryService) trackConfig() {
 		"max_burst":                *cfg.RateLimitSettings.MaxBurst,
 		"memory_store_size":        *cfg.RateLimitSettings.MemoryStoreSize,
 		"isdefault_vary_by_header": isDefault(cfg.RateLimitSettings.VaryByHeader, ""),
+		"route_quotas":             len(cfg.RateLimitSettings.RouteQuotas),
 	}
 
 	configs[TrackConfigPrivacy] = map[string]any{
@@ -951,6 +957,12 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_searching":         *cfg.BleveSettings.EnableSearching,
 		"enable_autocomplete":      *cfg.BleveSettings.EnableAutocomplete,
 		"bulk_indexing_batch_size": *cfg.BleveSettings.BatchSize,
+		"post_index_analyzer":      *cfg.BleveSettings.PostIndexAnalyzer,
+		"file_index_analyzer":      *cfg.BleveSettings.FileIndexAnalyzer,
+		"channel_index_analyzer":   *cfg.BleveSettings.ChannelIndexAnalyzer,
+		"user_index_analyzer":      *cfg.BleveSettings.UserIndexAnalyzer,
+		"preserve_numeric_tokens":  *cfg.BleveSettings.PreserveNumericTokens,
+		"cluster_mode":             *cfg.BleveSettings.ClusterMode,
 	}
 
 	configs[TrackConfigExport] = map[string]any{
@@ -979,6 +991,36 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_channel_scope_access_control":   *cfg.AccessControlSettings.EnableChannelScopeAccessControl,
 	}
 
+	configs[TrackConfigWhitelist] = map[string]any{
+		"enable":                         *cfg.WhitelistSettings.Enable,
+		"bot_access":                     *cfg.WhitelistSettings.BotAccess,
+		"oauth_app_access":               *cfg.WhitelistSettings.OAuthAppAccess,
+		"personal_access_token_access":   *cfg.WhitelistSettings.PersonalAccessTokenAccess,
+		"empty_whitelist_action":         *cfg.WhitelistSettings.EmptyWhitelistAction,
+		"denied_access_retention_days":   *cfg.WhitelistSettings.DeniedAccessRetentionDays,
+		"enable_self_service_enrollment": *cfg.WhitelistSettings.EnableSelfServiceEnrollment,
+		"enrollment_link_expiry_minutes": *cfg.WhitelistSettings.EnrollmentLinkExpiryMinutes,
+		"enrollment_entry_expiry_hours":  *cfg.WhitelistSettings.EnrollmentEntryExpiryHours,
+		"enrollment_rate_limit_per_hour": *cfg.WhitelistSettings.EnrollmentRateLimitPerHour,
+		"enrollment_methods_count":       len(cfg.WhitelistSettings.EnrollmentMethods),
+		"bypass_roles_count":             len(cfg.WhitelistSettings.BypassRoles),
+		"excluded_paths_count":           len(cfg.WhitelistSettings.ExcludedPaths),
+	}
+
+	configs[TrackConfigLoginThrottle] = map[string]any{
+		"enable":                        *cfg.LoginThrottleSettings.Enable,
+		"backoff_after_attempts":        *cfg.LoginThrottleSettings.BackoffAfterAttempts,
+		"subnet_backoff_after_attempts": *cfg.LoginThrottleSettings.SubnetBackoffAfterAttempts,
+		"max_backoff_seconds":           *cfg.LoginThrottleSettings.MaxBackoffSeconds,
+		"max_attempts_per_address":      *cfg.LoginThrottleSettings.MaxAttemptsPerAddress,
+		"max_attempts_per_subnet":       *cfg.LoginThrottleSettings.MaxAttemptsPerSubnet,
+		"ipv4_subnet_prefix_length":     *cfg.LoginThrottleSettings.IPv4SubnetPrefixLength,
+		"ipv6_subnet_prefix_length":     *cfg.LoginThrottleSettings.IPv6SubnetPrefixLength,
+		"attempt_window_minutes":        *cfg.LoginThrottleSettings.AttemptWindowMinutes,
+		"ban_duration_minutes":          *cfg.LoginThrottleSettings.BanDurationMinutes,
+		"exempt_addresses_count":        len(cfg.LoginThrottleSettings.ExemptAddresses),
+	}
+
 	// Convert feature flags to map[string]any for sending
 	flags := cfg.FeatureFlags.ToMap()
 	interfaceFlags := make(map[string]any)
@@ -0,0 +1,39 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+)
+
+const (
+	// BleveSnapshotVersion is the version of the format of the Bleve index
+	// snapshots. Snapshots of another version can't be restored.
+	BleveSnapshotVersion = 2
+
+	BleveSnapshotsDirectory = "bleve_snapshots"
+)
+
+var bleveSnapshotNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// BleveSnapshot describes a copy of the Bleve indexes stored in the file store.
+type BleveSnapshot struct {
+	Name          string            `json:"name"`
+	Version       int               `json:"version"`
+	ServerVersion string            `json:"server_version"`
+	CreateAt      int64             `json:"create_at"`
+	Size          int64             `json:"size"`
+	DocCounts     map[string]uint64 `json:"doc_counts"`
+	// MappingHash and SettingsHash identify the mappings of the indexes and
+	// the text analysis settings of the snapshot, which are required to be
+	// the current ones to restore it.
+	MappingHash  string `json:"mapping_hash"`
+	SettingsHash string `json:"settings_hash"`
+}
+
+// IsValidBleveSnapshotName reports whether a snapshot name is safe to use as
+// a file name.
+func IsValidBleveSnapshotName(name string) bool {
+	return bleveSnapshotNameRegexp.MatchString(name)
+}
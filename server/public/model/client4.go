@@ -638,6 +638,34 @@ func (c *Client4) accessControlPolicyRoute(policyID string) string {
 	return fmt.Sprintf(c.accessControlPoliciesRoute()+"/%v", url.PathEscape(policyID))
 }
 
+func (c *Client4) whitelistPoliciesRoute() string {
+	return "/whitelist/policies"
+}
+
+func (c *Client4) whitelistEntriesRoute() string {
+	return "/whitelist/entries"
+}
+
+func (c *Client4) whitelistDenialsRoute() string {
+	return "/whitelist/denials"
+}
+
+func (c *Client4) whitelistPolicyRoute(policyID string) string {
+	return fmt.Sprintf(c.whitelistPoliciesRoute()+"/%v", url.PathEscape(policyID))
+}
+
+func (c *Client4) loginBansRoute() string {
+	return "/login_bans"
+}
+
+func (c *Client4) savedSearchesRoute() string {
+	return "/saved_searches"
+}
+
+func (c *Client4) savedSearchRoute(savedSearchID string) string {
+	return fmt.Sprintf(c.savedSearchesRoute()+"/%v", url.PathEscape(savedSearchID))
+}
+
 func (c *Client4) GetServerLimits(ctx context.Context) (*ServerLimits, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.limitsRoute()+"/users", "")
 	if err != nil {
@@ -721,6 +749,102 @@ func (c *Client4) DeleteScheduledPost(ctx context.Context, scheduledPostId strin
 	return &deletedScheduledPost, BuildResponse(r), nil
 }
 
+func (c *Client4) PauseScheduledPost(ctx context.Context, scheduledPostId string) (*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.postsRoute()+"/schedule/"+scheduledPostId+"/pause", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var scheduledPost ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&scheduledPost); err != nil {
+		return nil, nil, NewAppError("PauseScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &scheduledPost, BuildResponse(r), nil
+}
+
+func (c *Client4) ResumeScheduledPost(ctx context.Context, scheduledPostId string) (*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.postsRoute()+"/schedule/"+scheduledPostId+"/resume", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var scheduledPost ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&scheduledPost); err != nil {
+		return nil, nil, NewAppError("ResumeScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &scheduledPost, BuildResponse(r), nil
+}
+
+func (c *Client4) SkipScheduledPostOccurrence(ctx context.Context, scheduledPostId string) (*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.postsRoute()+"/schedule/"+scheduledPostId+"/skip", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var scheduledPost ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&scheduledPost); err != nil {
+		return nil, nil, NewAppError("SkipScheduledPostOccurrence", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &scheduledPost, BuildResponse(r), nil
+}
+
+func (c *Client4) channelScheduledPostsRoute(channelId string) string {
+	return c.channelRoute(channelId) + "/scheduled_posts"
+}
+
+// GetChannelScheduledPosts returns the scheduled posts of every user in a channel.
+func (c *Client4) GetChannelScheduledPosts(ctx context.Context, channelId string) ([]*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.channelScheduledPostsRoute(channelId), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var scheduledPosts []*ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&scheduledPosts); err != nil {
+		return nil, nil, NewAppError("GetChannelScheduledPosts", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return scheduledPosts, BuildResponse(r), nil
+}
+
+// UpdateChannelScheduledPost updates a scheduled post of any user in its channel.
+func (c *Client4) UpdateChannelScheduledPost(ctx context.Context, scheduledPost *ScheduledPost) (*ScheduledPost, *Response, error) {
+	buf, err := json.Marshal(scheduledPost)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateChannelScheduledPost", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPut(ctx, c.channelScheduledPostsRoute(scheduledPost.ChannelId)+"/"+scheduledPost.Id, string(buf))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var updatedScheduledPost ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&updatedScheduledPost); err != nil {
+		return nil, nil, NewAppError("UpdateChannelScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &updatedScheduledPost, BuildResponse(r), nil
+}
+
+// DeleteChannelScheduledPost cancels a scheduled post of any user in a channel.
+func (c *Client4) DeleteChannelScheduledPost(ctx context.Context, channelId, scheduledPostId string) (*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.channelScheduledPostsRoute(channelId)+"/"+scheduledPostId)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+
+	defer closeBody(r)
+	var deletedScheduledPost ScheduledPost
+	if err := json.NewDecoder(r.Body).Decode(&deletedScheduledPost); err != nil {
+		return nil, nil, NewAppError("DeleteChannelScheduledPost", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &deletedScheduledPost, BuildResponse(r), nil
+}
+
 func (c *Client4) bookmarksRoute(channelId string) string {
 	return c.channelRoute(channelId) + "/bookmarks"
 }
@@ -6481,6 +6605,49 @@ func (c *Client4) PurgeBleveIndexes(ctx context.Context) (*Response, error) {
 	return BuildResponse(r), nil
 }
 
+// CreateBleveSnapshot creates the job storing a snapshot of the Bleve indexes
+// in the file store.
+func (c *Client4) CreateBleveSnapshot(ctx context.Context) (*Job, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.bleveRoute()+"/snapshots", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var job Job
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, nil, NewAppError("CreateBleveSnapshot", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &job, BuildResponse(r), nil
+}
+
+// GetBleveSnapshots returns the snapshots of the Bleve indexes, the most recent first.
+func (c *Client4) GetBleveSnapshots(ctx context.Context) ([]*BleveSnapshot, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.bleveRoute()+"/snapshots", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var snapshots []*BleveSnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshots); err != nil {
+		return nil, nil, NewAppError("GetBleveSnapshots", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return snapshots, BuildResponse(r), nil
+}
+
+// RestoreBleveSnapshot replaces the Bleve indexes by the ones of a snapshot.
+func (c *Client4) RestoreBleveSnapshot(ctx context.Context, name string) (*BleveSnapshot, *Response, error) {
+	r, err := c.DoAPIPost(ctx, c.bleveRoute()+"/snapshots/"+url.PathEscape(name)+"/restore", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var snapshot BleveSnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		return nil, nil, NewAppError("RestoreBleveSnapshot", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &snapshot, BuildResponse(r), nil
+}
+
 // Data Retention Section
 
 // GetDataRetentionPolicy will get the current global data retention policy details.
@@ -9782,3 +9949,440 @@ func (c *Client4) SearchChannelsForAccessControlPolicy(ctx context.Context, poli
 
 	return &channels, BuildResponse(r), nil
 }
+
+// GetWhitelistPolicies returns a page of IP whitelist policies.
+func (c *Client4) GetWhitelistPolicies(ctx context.Context, page, perPage int) ([]*WhitelistPolicy, *Response, error) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	r, err := c.DoAPIGet(ctx, c.whitelistPoliciesRoute()+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var policies []*WhitelistPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policies); err != nil {
+		return nil, nil, NewAppError("GetWhitelistPolicies", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return policies, BuildResponse(r), nil
+}
+
+// CreateWhitelistPolicy creates a new IP whitelist policy.
+func (c *Client4) CreateWhitelistPolicy(ctx context.Context, policy *WhitelistPolicy) (*WhitelistPolicy, *Response, error) {
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return nil, nil, NewAppError("CreateWhitelistPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.whitelistPoliciesRoute(), b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var p WhitelistPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return nil, nil, NewAppError("CreateWhitelistPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &p, BuildResponse(r), nil
+}
+
+func (c *Client4) GetWhitelistPolicy(ctx context.Context, policyID string) (*WhitelistPolicy, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.whitelistPolicyRoute(policyID), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var policy WhitelistPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, nil, NewAppError("GetWhitelistPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &policy, BuildResponse(r), nil
+}
+
+func (c *Client4) GetWhitelistPolicyByName(ctx context.Context, name string) (*WhitelistPolicy, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.whitelistPoliciesRoute()+"/name/"+url.PathEscape(name), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var policy WhitelistPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, nil, NewAppError("GetWhitelistPolicyByName", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &policy, BuildResponse(r), nil
+}
+
+func (c *Client4) PatchWhitelistPolicy(ctx context.Context, policyID string, patch *WhitelistPolicyPatch) (*WhitelistPolicy, *Response, error) {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchWhitelistPolicy", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPutBytes(ctx, c.whitelistPolicyRoute(policyID)+"/patch", b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var policy WhitelistPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		return nil, nil, NewAppError("PatchWhitelistPolicy", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &policy, BuildResponse(r), nil
+}
+
+func (c *Client4) DeleteWhitelistPolicy(ctx context.Context, policyID string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.whitelistPolicyRoute(policyID))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+func (c *Client4) GetWhitelistPolicyTargets(ctx context.Context, policyID string) ([]*WhitelistPolicyTarget, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.whitelistPolicyRoute(policyID)+"/targets", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var targets []*WhitelistPolicyTarget
+	if err := json.NewDecoder(r.Body).Decode(&targets); err != nil {
+		return nil, nil, NewAppError("GetWhitelistPolicyTargets", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return targets, BuildResponse(r), nil
+}
+
+// AddWhitelistPolicyTarget attaches a policy to a team, group or role.
+func (c *Client4) AddWhitelistPolicyTarget(ctx context.Context, policyID, targetType, targetID string) (*WhitelistPolicyTarget, *Response, error) {
+	b, err := json.Marshal(&WhitelistPolicyTarget{TargetType: targetType, TargetId: targetID})
+	if err != nil {
+		return nil, nil, NewAppError("AddWhitelistPolicyTarget", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.whitelistPolicyRoute(policyID)+"/targets", b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var target WhitelistPolicyTarget
+	if err := json.NewDecoder(r.Body).Decode(&target); err != nil {
+		return nil, nil, NewAppError("AddWhitelistPolicyTarget", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &target, BuildResponse(r), nil
+}
+
+// RemoveWhitelistPolicyTarget detaches a policy from a team, group or role.
+func (c *Client4) RemoveWhitelistPolicyTarget(ctx context.Context, policyID, targetType, targetID string) (*Response, error) {
+	b, err := json.Marshal(&WhitelistPolicyTarget{TargetType: targetType, TargetId: targetID})
+	if err != nil {
+		return nil, NewAppError("RemoveWhitelistPolicyTarget", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIDeleteBytes(ctx, c.whitelistPolicyRoute(policyID)+"/targets", b)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+func whitelistDenialFilterQuery(filter *WhitelistDenialFilter) string {
+	v := url.Values{}
+	if filter.UserId != "" {
+		v.Set("user_id", filter.UserId)
+	}
+	if filter.IP != "" {
+		v.Set("ip", filter.IP)
+	}
+	if filter.Since != 0 {
+		v.Set("since", strconv.FormatInt(filter.Since, 10))
+	}
+	if filter.Until != 0 {
+		v.Set("until", strconv.FormatInt(filter.Until, 10))
+	}
+	v.Set("page", strconv.Itoa(filter.Page))
+	v.Set("per_page", strconv.Itoa(filter.PerPage))
+	return "?" + v.Encode()
+}
+
+// GetWhitelistDenials returns a page of requests denied by the IP whitelist, newest first.
+func (c *Client4) GetWhitelistDenials(ctx context.Context, filter *WhitelistDenialFilter) ([]*WhitelistDenial, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.whitelistDenialsRoute()+whitelistDenialFilterQuery(filter), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var denials []*WhitelistDenial
+	if err := json.NewDecoder(r.Body).Decode(&denials); err != nil {
+		return nil, nil, NewAppError("GetWhitelistDenials", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return denials, BuildResponse(r), nil
+}
+
+// ExportWhitelistDenials returns every denial matching the filter as CSV. Page
+// and PerPage of the filter are ignored.
+func (c *Client4) ExportWhitelistDenials(ctx context.Context, filter *WhitelistDenialFilter) ([]byte, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.whitelistDenialsRoute()+"/export"+whitelistDenialFilterQuery(filter), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildResponse(r), NewAppError("ExportWhitelistDenials", "model.client.export_whitelist_denials.app_error", nil, "", r.StatusCode).Wrap(err)
+	}
+	return data, BuildResponse(r), nil
+}
+
+// GetUserWhitelist returns the whitelist entries of a user that have not expired yet.
+func (c *Client4) GetUserWhitelist(ctx context.Context, userId string) ([]*WhitelistItem, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userId)+"/whitelist", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var response struct {
+		Entries []*WhitelistItem `json:"entries"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, nil, NewAppError("GetUserWhitelist", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return response.Entries, BuildResponse(r), nil
+}
+
+// AddUserToWhitelist adds an IP address or CIDR range to a user's whitelist. Only the
+// IP, Description and ExpiresAt fields of the item are used.
+func (c *Client4) AddUserToWhitelist(ctx context.Context, userId string, item *WhitelistItem) (*WhitelistItem, *Response, error) {
+	b, err := json.Marshal(map[string]any{
+		"ip":          item.IP,
+		"description": item.Description,
+		"expires_at":  item.ExpiresAt,
+	})
+	if err != nil {
+		return nil, nil, NewAppError("AddUserToWhitelist", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.userRoute(userId)+"/whitelist", b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var response struct {
+		Entry *WhitelistItem `json:"entry"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, nil, NewAppError("AddUserToWhitelist", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return response.Entry, BuildResponse(r), nil
+}
+
+// RemoveUserFromWhitelist removes an IP address or CIDR range from a user's whitelist,
+// revoking the sessions the user no longer is whitelisted for if revokeSessions is true.
+func (c *Client4) RemoveUserFromWhitelist(ctx context.Context, userId, ip string, revokeSessions bool) (*Response, error) {
+	b, err := json.Marshal(map[string]any{
+		"ip":              ip,
+		"revoke_sessions": revokeSessions,
+	})
+	if err != nil {
+		return nil, NewAppError("RemoveUserFromWhitelist", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIDeleteBytes(ctx, c.userRoute(userId)+"/whitelist", b)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+// GetAllWhitelistItems returns a page of the whitelist entries of every user, ordered by
+// user and IP. Pass the user id and IP of the last entry to get the following page.
+func (c *Client4) GetAllWhitelistItems(ctx context.Context, afterUserId, afterIP string, perPage int) ([]*WhitelistItemForExport, *Response, error) {
+	v := url.Values{}
+	v.Set("after_user_id", afterUserId)
+	v.Set("after_ip", afterIP)
+	v.Set("per_page", strconv.Itoa(perPage))
+
+	r, err := c.DoAPIGet(ctx, c.whitelistEntriesRoute()+"?"+v.Encode(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var items []*WhitelistItemForExport
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		return nil, nil, NewAppError("GetAllWhitelistItems", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return items, BuildResponse(r), nil
+}
+
+// GetLoginBans returns the addresses and subnets whose logins are refused by the
+// login throttle, until their ban expires.
+func (c *Client4) GetLoginBans(ctx context.Context) ([]*LoginBan, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.loginBansRoute(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var bans []*LoginBan
+	if err := json.NewDecoder(r.Body).Decode(&bans); err != nil {
+		return nil, nil, NewAppError("GetLoginBans", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return bans, BuildResponse(r), nil
+}
+
+// RemoveLoginBan lifts the ban of an address or CIDR range and forgets its failed logins.
+func (c *Client4) RemoveLoginBan(ctx context.Context, ip string) (*Response, error) {
+	b, err := json.Marshal(map[string]any{"ip": ip})
+	if err != nil {
+		return nil, NewAppError("RemoveLoginBan", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIDeleteBytes(ctx, c.loginBansRoute(), b)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+// ClearLoginBans lifts every ban and forgets every failed login counted by the login throttle.
+func (c *Client4) ClearLoginBans(ctx context.Context) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.loginBansRoute()+"/all")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+// CreateSavedSearch saves a new search for the current user.
+func (c *Client4) CreateSavedSearch(ctx context.Context, savedSearch *SavedSearch) (*SavedSearch, *Response, error) {
+	b, err := json.Marshal(savedSearch)
+	if err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.savedSearchesRoute(), b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var s SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &s, BuildResponse(r), nil
+}
+
+// GetSavedSearchesForUser returns the saved searches of a user ordered by name.
+func (c *Client4) GetSavedSearchesForUser(ctx context.Context, userID string) ([]*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userRoute(userID)+"/saved_searches", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearches []*SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearches); err != nil {
+		return nil, nil, NewAppError("GetSavedSearchesForUser", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return savedSearches, BuildResponse(r), nil
+}
+
+func (c *Client4) GetSavedSearch(ctx context.Context, savedSearchID string) (*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.savedSearchRoute(savedSearchID), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearch SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearch); err != nil {
+		return nil, nil, NewAppError("GetSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &savedSearch, BuildResponse(r), nil
+}
+
+func (c *Client4) PatchSavedSearch(ctx context.Context, savedSearchID string, patch *SavedSearchPatch) (*SavedSearch, *Response, error) {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPutBytes(ctx, c.savedSearchRoute(savedSearchID)+"/patch", b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearch SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearch); err != nil {
+		return nil, nil, NewAppError("PatchSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &savedSearch, BuildResponse(r), nil
+}
+
+func (c *Client4) DeleteSavedSearch(ctx context.Context, savedSearchID string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.savedSearchRoute(savedSearchID))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r), nil
+}
+
+// SearchPostsWithSavedSearch runs a saved search, using the page, the number of
+// posts per page and the time zone offset of the parameters.
+func (c *Client4) SearchPostsWithSavedSearch(ctx context.Context, savedSearchID string, params *SearchParameter) (*PostSearchResults, *Response, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, nil, NewAppError("SearchPostsWithSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.savedSearchRoute(savedSearchID)+"/search", b)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var psr PostSearchResults
+	if err := json.NewDecoder(r.Body).Decode(&psr); err != nil {
+		return nil, nil, NewAppError("SearchPostsWithSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return &psr, BuildResponse(r), nil
+}
@@ -42,6 +42,18 @@ const (
 	ClusterEventPluginEvent                                 ClusterEvent = "plugin_event"
 	ClusterEventInvalidateCacheForTermsOfService            ClusterEvent = "inv_terms_of_service"
 	ClusterEventBusyStateChanged                            ClusterEvent = "busy_state_change"
+	ClusterEventWhitelistRevokeUser                         ClusterEvent = "whitelist_revoke_user"
+	ClusterEventInvalidateCacheForWhitelist                 ClusterEvent = "inv_whitelist"
+	ClusterEventInvalidateCacheForWhitelistPolicies         ClusterEvent = "inv_whitelist_policies"
+	ClusterEventInvalidateCacheForLoginBans                 ClusterEvent = "inv_login_bans"
+	ClusterEventInvalidateCacheForGroupsByUser              ClusterEvent = "inv_groups_by_user"
+	ClusterEventBleveIndexOperation                         ClusterEvent = "bleve_index_operation"
+	ClusterEventBleveSearchRequest                          ClusterEvent = "bleve_search_request"
+	ClusterEventBleveSearchResponse                         ClusterEvent = "bleve_search_response"
+	ClusterEventBleveOwnerRequest                           ClusterEvent = "bleve_owner_request"
+	ClusterEventBleveOwnerAnnouncement                      ClusterEvent = "bleve_owner_announcement"
+	ClusterEventBleveRestoreSnapshot                        ClusterEvent = "bleve_restore_snapshot"
+	ClusterEventInvalidateSavedSearchAlerts                 ClusterEvent = "inv_saved_search_alerts"
 	// Note: if you are adding a new event, please also add it in the slice of
 	// m.ClusterEventMap in metrics/metrics.go file.
 
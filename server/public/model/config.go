@@ -137,6 +137,9 @@ const (
 	FileSettingsDefaultS3UploadPartSizeBytes       = 5 * 1024 * 1024   // 5MB
 	FileSettingsDefaultS3ExportUploadPartSizeBytes = 100 * 1024 * 1024 // 100MB
 
+	FileSettingsDefaultExtractContentTimeoutSeconds = 60
+	FileSettingsDefaultExtractContentMaxMemoryMB    = 512
+
 	ImportSettingsDefaultDirectory     = "./import"
 	ImportSettingsDefaultRetentionDays = 30
 
@@ -229,6 +232,20 @@ const (
 	BleveSettingsDefaultIndexDir  = ""
 	BleveSettingsDefaultBatchSize = 10000
 
+	BleveAnalyzerStandard   = "standard"
+	BleveAnalyzerCJK        = "cjk"
+	BleveAnalyzerEnglish    = "en"
+	BleveAnalyzerGerman     = "de"
+	BleveAnalyzerSpanish    = "es"
+	BleveAnalyzerFrench     = "fr"
+	BleveAnalyzerItalian    = "it"
+	BleveAnalyzerDutch      = "nl"
+	BleveAnalyzerPortuguese = "pt"
+	BleveAnalyzerRussian    = "ru"
+
+	BleveClusterModeLocal  = "local"
+	BleveClusterModeLeader = "leader"
+
 	DataRetentionSettingsDefaultMessageRetentionDays           = 365
 	DataRetentionSettingsDefaultMessageRetentionHours          = 0
 	DataRetentionSettingsDefaultFileRetentionDays              = 365
@@ -301,8 +318,57 @@ const (
 	StorageClassGlacierIR          = "GLACIER_IR"
 	StorageClassSnow               = "SNOW"
 	StorageClassExpressOnezone     = "EXPRESS_ONEZONE"
+
+	WhitelistAccessEnforce = "enforce"
+	WhitelistAccessBypass  = "bypass"
+	WhitelistAccessDeny    = "deny"
+
+	WhitelistEmptyActionDeny  = "deny"
+	WhitelistEmptyActionAllow = "allow"
+
+	WhitelistEnrollmentMethodEmail = "email"
+	WhitelistEnrollmentMethodMfa   = "mfa"
+)
+
+const (
+	WhitelistSettingsDefaultDeniedAccessRetentionDays   = 90
+	WhitelistSettingsDefaultEnrollmentLinkExpiryMinutes = 60
+	WhitelistSettingsDefaultEnrollmentRateLimitPerHour  = 3
+)
+
+const (
+	LoginThrottleSettingsDefaultBackoffAfterAttempts       = 5
+	LoginThrottleSettingsDefaultSubnetBackoffAfterAttempts = 20
+	LoginThrottleSettingsDefaultMaxBackoffSeconds          = 300
+	LoginThrottleSettingsDefaultMaxAttemptsPerAddress      = 30
+	LoginThrottleSettingsDefaultMaxAttemptsPerSubnet       = 100
+	LoginThrottleSettingsDefaultIPv4SubnetPrefixLength     = 24
+	LoginThrottleSettingsDefaultIPv6SubnetPrefixLength     = 64
+	LoginThrottleSettingsDefaultAttemptWindowMinutes       = 15
+	LoginThrottleSettingsDefaultBanDurationMinutes         = 60
 )
 
+// WhitelistSettingsDefaultExcludedPaths are the paths a user needs to reach
+// before the client can tell them their address is not whitelisted.
+var WhitelistSettingsDefaultExcludedPaths = []string{
+	"/api/v4/users/login",
+	"/api/v4/users/logout",
+	"/api/v4/users/me",
+	"/api/v4/users/ids",
+	"/api/v4/system/ping",
+	"/api/v4/config/client",
+	"/api/v4/license/client",
+	"/api/v4/teams",
+	"/api/v4/roles/names",
+	"/api/v4/custom_profile_attributes/",
+	"/api/v4/plugins/webapp",
+	"/login",
+	"/signup",
+	"/static/",
+	"/fonts/",
+	"/images/",
+}
+
 func GetDefaultAppCustomURLSchemes() []string {
 	return []string{"mmauth://", "mmauthbeta://"}
 }
@@ -349,6 +415,7 @@ type ServiceSettings struct {
 	LetsEncryptCertificateCacheFile     *string  `access:"environment_web_server,write_restrictable,cloud_restrictable"` // telemetry: none
 	Forward80To443                      *bool    `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	TrustedProxyIPHeader                []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+	TrustedProxyCIDRs                   []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
 	ReadTimeout                         *int     `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	WriteTimeout                        *int     `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	IdleTimeout                         *int     `access:"write_restrictable,cloud_restrictable"`
@@ -631,6 +698,10 @@ func (s *ServiceSettings) SetDefaults(isUpdate bool) {
 		s.TrustedProxyIPHeader = []string{}
 	}
 
+	if s.TrustedProxyCIDRs == nil {
+		s.TrustedProxyCIDRs = []string{}
+	}
+
 	if s.TimeBetweenUserTypingUpdatesMilliseconds == nil {
 		s.TimeBetweenUserTypingUpdatesMilliseconds = NewPointer(int64(5000))
 	}
@@ -1721,6 +1792,9 @@ type FileSettings struct {
 	EnablePublicLink                   *bool   `access:"site_public_links,cloud_restrictable"`
 	ExtractContent                     *bool   `access:"environment_file_storage,write_restrictable"`
 	ArchiveRecursion                   *bool   `access:"environment_file_storage,write_restrictable"`
+	ExtractContentTimeoutSeconds       *int    `access:"environment_file_storage,write_restrictable"`
+	ExtractContentMaxMemoryMB          *int    `access:"environment_file_storage,write_restrictable"`
+	ExtractContentInWorker             *bool   `access:"environment_file_storage,write_restrictable,cloud_restrictable"`
 	PublicLinkSalt                     *string `access:"site_public_links,cloud_restrictable"`                           // telemetry: none
 	InitialFont                        *string `access:"environment_file_storage,cloud_restrictable"`                    // telemetry: none
 	AmazonS3AccessKeyId                *string `access:"environment_file_storage,write_restrictable,cloud_restrictable"` // telemetry: none
@@ -1801,6 +1875,18 @@ func (s *FileSettings) SetDefaults(isUpdate bool) {
 		s.ArchiveRecursion = NewPointer(false)
 	}
 
+	if s.ExtractContentTimeoutSeconds == nil {
+		s.ExtractContentTimeoutSeconds = NewPointer(FileSettingsDefaultExtractContentTimeoutSeconds)
+	}
+
+	if s.ExtractContentMaxMemoryMB == nil {
+		s.ExtractContentMaxMemoryMB = NewPointer(FileSettingsDefaultExtractContentMaxMemoryMB)
+	}
+
+	if s.ExtractContentInWorker == nil {
+		s.ExtractContentInWorker = NewPointer(false)
+	}
+
 	if isUpdate {
 		// When updating an existing configuration, ensure link salt has been specified.
 		if s.PublicLinkSalt == nil || *s.PublicLinkSalt == "" {
@@ -2116,14 +2202,33 @@ func (s *EmailSettings) SetDefaults(isUpdate bool) {
 	}
 }
 
+// Routes grouping the endpoints a RateLimitRouteQuota can apply to, besides the paths
+const (
+	RateLimitRouteLogin      = "login"
+	RateLimitRouteSearch     = "search"
+	RateLimitRouteFileUpload = "file_upload"
+	RateLimitRouteCreatePost = "create_post"
+)
+
+// RateLimitRouteQuota overrides the quota of the rate limiter for a route, either one of the
+// named routes or a path where "*" matches any segment, like "/api/v4/channels/*/members".
+// An empty method matches all the methods.
+type RateLimitRouteQuota struct {
+	Route    *string `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	Method   *string `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	PerSec   *int    `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	MaxBurst *int    `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+}
+
 type RateLimitSettings struct {
-	Enable           *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	PerSec           *int   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	MaxBurst         *int   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	MemoryStoreSize  *int   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	VaryByRemoteAddr *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	VaryByUser       *bool  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
-	VaryByHeader     string `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	Enable           *bool                  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	PerSec           *int                   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	MaxBurst         *int                   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	MemoryStoreSize  *int                   `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	VaryByRemoteAddr *bool                  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	VaryByUser       *bool                  `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	VaryByHeader     string                 `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
+	RouteQuotas      []*RateLimitRouteQuota `access:"environment_rate_limiting,write_restrictable,cloud_restrictable"`
 }
 
 func (s *RateLimitSettings) SetDefaults() {
@@ -2150,6 +2255,29 @@ func (s *RateLimitSettings) SetDefaults() {
 	if s.VaryByUser == nil {
 		s.VaryByUser = NewPointer(false)
 	}
+
+	if s.RouteQuotas == nil {
+		s.RouteQuotas = []*RateLimitRouteQuota{}
+	}
+
+	for _, quota := range s.RouteQuotas {
+		if quota.Route == nil {
+			quota.Route = NewPointer("")
+		}
+
+		if quota.Method == nil {
+			quota.Method = NewPointer("")
+		}
+
+		// The quota of the other routes is used unless overridden
+		if quota.PerSec == nil {
+			quota.PerSec = NewPointer(*s.PerSec)
+		}
+
+		if quota.MaxBurst == nil {
+			quota.MaxBurst = NewPointer(*s.MaxBurst)
+		}
+	}
 }
 
 type PrivacySettings struct {
@@ -3098,6 +3226,12 @@ type BleveSettings struct {
 	EnableAutocomplete            *bool   `access:"experimental_bleve"`
 	BulkIndexingTimeWindowSeconds *int    `json:",omitempty"` // telemetry: none
 	BatchSize                     *int    `access:"experimental_bleve"`
+	PostIndexAnalyzer             *string `access:"experimental_bleve"`
+	FileIndexAnalyzer             *string `access:"experimental_bleve"`
+	ChannelIndexAnalyzer          *string `access:"experimental_bleve"`
+	UserIndexAnalyzer             *string `access:"experimental_bleve"`
+	PreserveNumericTokens         *bool   `access:"experimental_bleve"`
+	ClusterMode                   *string `access:"experimental_bleve"`
 }
 
 func (bs *BleveSettings) SetDefaults() {
@@ -3120,6 +3254,41 @@ func (bs *BleveSettings) SetDefaults() {
 	if bs.BatchSize == nil {
 		bs.BatchSize = NewPointer(BleveSettingsDefaultBatchSize)
 	}
+
+	if bs.PostIndexAnalyzer == nil {
+		bs.PostIndexAnalyzer = NewPointer(BleveAnalyzerStandard)
+	}
+
+	if bs.FileIndexAnalyzer == nil {
+		bs.FileIndexAnalyzer = NewPointer(BleveAnalyzerStandard)
+	}
+
+	if bs.ChannelIndexAnalyzer == nil {
+		bs.ChannelIndexAnalyzer = NewPointer(BleveAnalyzerStandard)
+	}
+
+	if bs.UserIndexAnalyzer == nil {
+		bs.UserIndexAnalyzer = NewPointer(BleveAnalyzerStandard)
+	}
+
+	if bs.PreserveNumericTokens == nil {
+		bs.PreserveNumericTokens = NewPointer(false)
+	}
+
+	if bs.ClusterMode == nil {
+		bs.ClusterMode = NewPointer(BleveClusterModeLocal)
+	}
+}
+
+// IsValidBleveAnalyzer reports whether name is one of the analyzers that can
+// be configured for a Bleve index.
+func IsValidBleveAnalyzer(name string) bool {
+	switch name {
+	case BleveAnalyzerStandard, BleveAnalyzerCJK, BleveAnalyzerEnglish, BleveAnalyzerGerman, BleveAnalyzerSpanish,
+		BleveAnalyzerFrench, BleveAnalyzerItalian, BleveAnalyzerDutch, BleveAnalyzerPortuguese, BleveAnalyzerRussian:
+		return true
+	}
+	return false
 }
 
 type DataRetentionSettings struct {
@@ -3740,6 +3909,252 @@ func (s *AccessControlSettings) SetDefaults() {
 	}
 }
 
+// WhitelistSettings controls how the IP whitelist is enforced.
+type WhitelistSettings struct {
+	Enable *bool `access:"write_restrictable,cloud_restrictable"`
+	// System or team roles whose holders are not subject to the whitelist.
+	BypassRoles []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+	// Permissions that exempt the user from the whitelist when granted by any of
+	// their system or team roles.
+	BypassPermissions []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+	// How bot accounts are handled: enforce, bypass or deny.
+	BotAccess *string `access:"write_restrictable,cloud_restrictable"`
+	// How sessions created through an OAuth app are handled: enforce, bypass or deny.
+	OAuthAppAccess *string `access:"write_restrictable,cloud_restrictable"`
+	// How sessions authenticated with a personal access token are handled: enforce, bypass or deny.
+	PersonalAccessTokenAccess *string `access:"write_restrictable,cloud_restrictable"`
+	// Request paths that are never checked. See CompileWhitelistPathPattern.
+	ExcludedPaths []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+	// Whether a user without any whitelisted address is denied or allowed.
+	EmptyWhitelistAction *string `access:"write_restrictable,cloud_restrictable"`
+	// Days to keep the denied-access history. 0 keeps it forever.
+	DeniedAccessRetentionDays *int `access:"write_restrictable,cloud_restrictable"`
+	// Whether a user denied at login can add the address themselves.
+	EnableSelfServiceEnrollment *bool `access:"write_restrictable,cloud_restrictable"`
+	// How self-service enrollment is verified: email and/or mfa.
+	EnrollmentMethods []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+	// Minutes an emailed enrollment link stays valid.
+	EnrollmentLinkExpiryMinutes *int `access:"write_restrictable,cloud_restrictable"`
+	// Hours an enrolled address stays whitelisted. 0 keeps it until removed.
+	EnrollmentEntryExpiryHours *int `access:"write_restrictable,cloud_restrictable"`
+	// Maximum enrollments, pending or completed, per user per hour.
+	EnrollmentRateLimitPerHour *int `access:"write_restrictable,cloud_restrictable"`
+}
+
+func (s *WhitelistSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewPointer(true)
+	}
+
+	if s.BypassRoles == nil {
+		s.BypassRoles = []string{TeamAdminRoleId}
+	}
+
+	if s.BypassPermissions == nil {
+		s.BypassPermissions = []string{PermissionManageSystem.Id}
+	}
+
+	if s.BotAccess == nil {
+		s.BotAccess = NewPointer(WhitelistAccessEnforce)
+	}
+
+	if s.OAuthAppAccess == nil {
+		s.OAuthAppAccess = NewPointer(WhitelistAccessEnforce)
+	}
+
+	if s.PersonalAccessTokenAccess == nil {
+		s.PersonalAccessTokenAccess = NewPointer(WhitelistAccessEnforce)
+	}
+
+	if s.ExcludedPaths == nil {
+		s.ExcludedPaths = append([]string(nil), WhitelistSettingsDefaultExcludedPaths...)
+	}
+
+	if s.EmptyWhitelistAction == nil {
+		s.EmptyWhitelistAction = NewPointer(WhitelistEmptyActionDeny)
+	}
+
+	if s.DeniedAccessRetentionDays == nil {
+		s.DeniedAccessRetentionDays = NewPointer(WhitelistSettingsDefaultDeniedAccessRetentionDays)
+	}
+
+	if s.EnableSelfServiceEnrollment == nil {
+		s.EnableSelfServiceEnrollment = NewPointer(false)
+	}
+
+	if s.EnrollmentMethods == nil {
+		s.EnrollmentMethods = []string{WhitelistEnrollmentMethodEmail, WhitelistEnrollmentMethodMfa}
+	}
+
+	if s.EnrollmentLinkExpiryMinutes == nil {
+		s.EnrollmentLinkExpiryMinutes = NewPointer(WhitelistSettingsDefaultEnrollmentLinkExpiryMinutes)
+	}
+
+	if s.EnrollmentEntryExpiryHours == nil {
+		s.EnrollmentEntryExpiryHours = NewPointer(0)
+	}
+
+	if s.EnrollmentRateLimitPerHour == nil {
+		s.EnrollmentRateLimitPerHour = NewPointer(WhitelistSettingsDefaultEnrollmentRateLimitPerHour)
+	}
+}
+
+func (s *WhitelistSettings) isValid() *AppError {
+	for _, access := range []string{*s.BotAccess, *s.OAuthAppAccess, *s.PersonalAccessTokenAccess} {
+		if access != WhitelistAccessEnforce && access != WhitelistAccessBypass && access != WhitelistAccessDeny {
+			return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.access.app_error", map[string]any{"Value": access}, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EmptyWhitelistAction != WhitelistEmptyActionDeny && *s.EmptyWhitelistAction != WhitelistEmptyActionAllow {
+		return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.empty_action.app_error", map[string]any{"Value": *s.EmptyWhitelistAction}, "", http.StatusBadRequest)
+	}
+
+	if *s.DeniedAccessRetentionDays < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.denied_access_retention_days.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, method := range s.EnrollmentMethods {
+		if method != WhitelistEnrollmentMethodEmail && method != WhitelistEnrollmentMethodMfa {
+			return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.enrollment_method.app_error", map[string]any{"Value": method}, "", http.StatusBadRequest)
+		}
+	}
+
+	if *s.EnrollmentLinkExpiryMinutes <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.enrollment_link_expiry.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.EnrollmentEntryExpiryHours < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.enrollment_entry_expiry.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.EnrollmentRateLimitPerHour <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.enrollment_rate_limit.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, role := range s.BypassRoles {
+		if !IsValidRoleName(role) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.bypass_role.app_error", map[string]any{"Value": role}, "", http.StatusBadRequest)
+		}
+	}
+
+	for _, pattern := range s.ExcludedPaths {
+		if _, err := CompileWhitelistPathPattern(pattern); err != nil {
+			return NewAppError("Config.IsValid", "model.config.is_valid.whitelist.excluded_path.app_error", map[string]any{"Value": pattern}, "", http.StatusBadRequest).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// LoginThrottleSettings limits the failed logins per client address and
+// subnet, whatever the username, to slow down brute-force and
+// credential-stuffing attacks.
+type LoginThrottleSettings struct {
+	Enable *bool `access:"write_restrictable,cloud_restrictable"`
+	// Failed logins from an address after which every further attempt has to
+	// wait, twice as long after each failure.
+	BackoffAfterAttempts *int `access:"write_restrictable,cloud_restrictable"`
+	// Failed logins from a subnet after which every further attempt from it has
+	// to wait, so that rotating addresses doesn't escape the backoff.
+	SubnetBackoffAfterAttempts *int `access:"write_restrictable,cloud_restrictable"`
+	// Longest wait between two attempts, in seconds.
+	MaxBackoffSeconds *int `access:"write_restrictable,cloud_restrictable"`
+	// Failed logins from an address that get it banned. 0 never bans addresses.
+	MaxAttemptsPerAddress *int `access:"write_restrictable,cloud_restrictable"`
+	// Failed logins from a subnet that get it banned. 0 never bans subnets.
+	MaxAttemptsPerSubnet *int `access:"write_restrictable,cloud_restrictable"`
+	// Prefix lengths of the subnet of an IPv4 and of an IPv6 address.
+	IPv4SubnetPrefixLength *int `access:"write_restrictable,cloud_restrictable"`
+	IPv6SubnetPrefixLength *int `access:"write_restrictable,cloud_restrictable"`
+	// Minutes after the last failed login at which the failures are forgotten.
+	AttemptWindowMinutes *int `access:"write_restrictable,cloud_restrictable"`
+	// Minutes a ban lasts.
+	BanDurationMinutes *int `access:"write_restrictable,cloud_restrictable"`
+	// Addresses and CIDR ranges that are never throttled, such as the NAT of an office.
+	ExemptAddresses []string `access:"write_restrictable,cloud_restrictable"` // telemetry: none
+}
+
+func (s *LoginThrottleSettings) SetDefaults() {
+	if s.Enable == nil {
+		s.Enable = NewPointer(false)
+	}
+
+	if s.BackoffAfterAttempts == nil {
+		s.BackoffAfterAttempts = NewPointer(LoginThrottleSettingsDefaultBackoffAfterAttempts)
+	}
+
+	if s.SubnetBackoffAfterAttempts == nil {
+		s.SubnetBackoffAfterAttempts = NewPointer(LoginThrottleSettingsDefaultSubnetBackoffAfterAttempts)
+	}
+
+	if s.MaxBackoffSeconds == nil {
+		s.MaxBackoffSeconds = NewPointer(LoginThrottleSettingsDefaultMaxBackoffSeconds)
+	}
+
+	if s.MaxAttemptsPerAddress == nil {
+		s.MaxAttemptsPerAddress = NewPointer(LoginThrottleSettingsDefaultMaxAttemptsPerAddress)
+	}
+
+	if s.MaxAttemptsPerSubnet == nil {
+		s.MaxAttemptsPerSubnet = NewPointer(LoginThrottleSettingsDefaultMaxAttemptsPerSubnet)
+	}
+
+	if s.IPv4SubnetPrefixLength == nil {
+		s.IPv4SubnetPrefixLength = NewPointer(LoginThrottleSettingsDefaultIPv4SubnetPrefixLength)
+	}
+
+	if s.IPv6SubnetPrefixLength == nil {
+		s.IPv6SubnetPrefixLength = NewPointer(LoginThrottleSettingsDefaultIPv6SubnetPrefixLength)
+	}
+
+	if s.AttemptWindowMinutes == nil {
+		s.AttemptWindowMinutes = NewPointer(LoginThrottleSettingsDefaultAttemptWindowMinutes)
+	}
+
+	if s.BanDurationMinutes == nil {
+		s.BanDurationMinutes = NewPointer(LoginThrottleSettingsDefaultBanDurationMinutes)
+	}
+
+	if s.ExemptAddresses == nil {
+		s.ExemptAddresses = []string{}
+	}
+}
+
+func (s *LoginThrottleSettings) isValid() *AppError {
+	if *s.BackoffAfterAttempts <= 0 || *s.SubnetBackoffAfterAttempts <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.backoff_after_attempts.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.MaxBackoffSeconds <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.max_backoff.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.MaxAttemptsPerAddress < 0 || *s.MaxAttemptsPerSubnet < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.max_attempts.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.IPv4SubnetPrefixLength < 1 || *s.IPv4SubnetPrefixLength > 32 || *s.IPv6SubnetPrefixLength < 1 || *s.IPv6SubnetPrefixLength > 128 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.subnet_prefix_length.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.AttemptWindowMinutes <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.attempt_window.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.BanDurationMinutes <= 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.ban_duration.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, address := range s.ExemptAddresses {
+		if _, err := ParseWhitelistPrefix(address); err != nil {
+			return NewAppError("Config.IsValid", "model.config.is_valid.login_throttle.exempt_address.app_error", map[string]any{"Value": address}, "", http.StatusBadRequest).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
 type ConfigFunc func() *Config
 
 const (
@@ -3833,6 +4248,8 @@ type Config struct {
 	WranglerSettings            WranglerSettings
 	ConnectedWorkspacesSettings ConnectedWorkspacesSettings
 	AccessControlSettings       AccessControlSettings
+	WhitelistSettings           WhitelistSettings
+	LoginThrottleSettings       LoginThrottleSettings
 }
 
 func (o *Config) Auditable() map[string]any {
@@ -3951,6 +4368,8 @@ func (o *Config) SetDefaults() {
 	o.WranglerSettings.SetDefaults()
 	o.ConnectedWorkspacesSettings.SetDefaults(isUpdate, o.ExperimentalSettings)
 	o.AccessControlSettings.SetDefaults()
+	o.WhitelistSettings.SetDefaults()
+	o.LoginThrottleSettings.SetDefaults()
 }
 
 func (o *Config) IsValid() *AppError {
@@ -4054,6 +4473,14 @@ func (o *Config) IsValid() *AppError {
 		return appErr
 	}
 
+	if appErr := o.WhitelistSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
+	if appErr := o.LoginThrottleSettings.isValid(); appErr != nil {
+		return appErr
+	}
+
 	if o.SupportSettings.ReportAProblemType != nil {
 		if *o.SupportSettings.ReportAProblemType == SupportSettingsReportAProblemTypeMail {
 			if o.SupportSettings.ReportAProblemMail == nil {
@@ -4179,6 +4606,14 @@ func (s *FileSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.image_decoder_concurrency.app_error", map[string]any{"Value": *s.MaxImageDecoderConcurrency}, "", http.StatusBadRequest)
 	}
 
+	if *s.ExtractContentTimeoutSeconds < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.extract_content_timeout.app_error", map[string]any{"Value": *s.ExtractContentTimeoutSeconds}, "", http.StatusBadRequest)
+	}
+
+	if *s.ExtractContentMaxMemoryMB < 0 {
+		return NewAppError("Config.IsValid", "model.config.is_valid.extract_content_max_memory.app_error", map[string]any{"Value": *s.ExtractContentMaxMemoryMB}, "", http.StatusBadRequest)
+	}
+
 	if *s.AmazonS3RequestTimeoutMilliseconds <= 0 {
 		return NewAppError("Config.IsValid", "model.config.is_valid.amazons3_timeout.app_error", map[string]any{"Value": *s.MaxImageDecoderConcurrency}, "", http.StatusBadRequest)
 	}
@@ -4239,9 +4674,33 @@ func (s *RateLimitSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.max_burst.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	for _, quota := range s.RouteQuotas {
+		if !quota.isValid() {
+			return NewAppError("Config.IsValid", "model.config.is_valid.rate_limit_route_quota.app_error", map[string]any{"Route": *quota.Route}, "", http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
+func (q *RateLimitRouteQuota) isValid() bool {
+	switch *q.Route {
+	case RateLimitRouteLogin, RateLimitRouteSearch, RateLimitRouteFileUpload, RateLimitRouteCreatePost:
+	default:
+		if !strings.HasPrefix(*q.Route, "/") {
+			return false
+		}
+	}
+
+	switch *q.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return false
+	}
+
+	return *q.PerSec > 0 && *q.MaxBurst > 0
+}
+
 func (s *LdapSettings) isValid() *AppError {
 	if !(*s.ConnectionSecurity == ConnSecurityNone || *s.ConnectionSecurity == ConnSecurityTLS || *s.ConnectionSecurity == ConnSecurityStarttls) {
 		return NewAppError("Config.IsValid", "model.config.is_valid.ldap_security.app_error", nil, "", http.StatusBadRequest)
@@ -4412,6 +4871,12 @@ func (s *ServiceSettings) isValid() *AppError {
 		}
 	}
 
+	for _, cidr := range s.TrustedProxyCIDRs {
+		if _, err := ParseWhitelistPrefix(cidr); err != nil {
+			return NewAppError("Config.IsValid", "model.config.is_valid.trusted_proxy_cidr.app_error", map[string]any{"Value": cidr}, "", http.StatusBadRequest).Wrap(err)
+		}
+	}
+
 	if *s.MaximumPayloadSizeBytes <= 0 {
 		return NewAppError("Config.IsValid", "model.config.is_valid.max_payload_size.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -4590,6 +5055,21 @@ func (bs *BleveSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.bleve_search.bulk_indexing_batch_size.app_error", map[string]any{"BatchSize": minBatchSize}, "", http.StatusBadRequest)
 	}
 
+	for setting, analyzer := range map[string]string{
+		"BleveSettings.PostIndexAnalyzer":    *bs.PostIndexAnalyzer,
+		"BleveSettings.FileIndexAnalyzer":    *bs.FileIndexAnalyzer,
+		"BleveSettings.ChannelIndexAnalyzer": *bs.ChannelIndexAnalyzer,
+		"BleveSettings.UserIndexAnalyzer":    *bs.UserIndexAnalyzer,
+	} {
+		if !IsValidBleveAnalyzer(analyzer) {
+			return NewAppError("Config.IsValid", "model.config.is_valid.bleve_search.analyzer.app_error", map[string]any{"Setting": setting, "Analyzer": analyzer}, "", http.StatusBadRequest)
+		}
+	}
+
+	if *bs.ClusterMode != BleveClusterModeLocal && *bs.ClusterMode != BleveClusterModeLeader {
+		return NewAppError("Config.IsValid", "model.config.is_valid.bleve_search.cluster_mode.app_error", map[string]any{"ClusterMode": *bs.ClusterMode}, "", http.StatusBadRequest)
+	}
+
 	return nil
 }
 
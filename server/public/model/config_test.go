@@ -147,6 +147,18 @@ func TestServiceSettingsIsValid(t *testing.T) {
 			},
 			ExpectError: false,
 		},
+		"TrustedProxyCIDRs with addresses and ranges": {
+			ServiceSettings: ServiceSettings{
+				TrustedProxyCIDRs: []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"},
+			},
+			ExpectError: false,
+		},
+		"TrustedProxyCIDRs with an invalid range": {
+			ServiceSettings: ServiceSettings{
+				TrustedProxyCIDRs: []string{"10.0.0.0/33"},
+			},
+			ExpectError: true,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			test.ServiceSettings.SetDefaults(false)
@@ -1050,6 +1062,37 @@ func TestImageProxySettingsIsValid(t *testing.T) {
 	}
 }
 
+func TestRateLimitSettingsRouteQuotas(t *testing.T) {
+	t.Run("quotas default to the global quota", func(t *testing.T) {
+		rls := RateLimitSettings{
+			RouteQuotas: []*RateLimitRouteQuota{{Route: NewPointer(RateLimitRouteLogin), PerSec: NewPointer(1)}},
+		}
+		rls.SetDefaults()
+
+		assert.Equal(t, "", *rls.RouteQuotas[0].Method)
+		assert.Equal(t, 1, *rls.RouteQuotas[0].PerSec)
+		assert.Equal(t, *rls.MaxBurst, *rls.RouteQuotas[0].MaxBurst)
+		assert.Nil(t, rls.isValid())
+	})
+
+	for name, quota := range map[string]*RateLimitRouteQuota{
+		"unknown route":    {Route: NewPointer("logins")},
+		"empty route":      {Route: NewPointer("")},
+		"invalid method":   {Route: NewPointer("/api/v4/users"), Method: NewPointer("get")},
+		"invalid rate":     {Route: NewPointer(RateLimitRouteSearch), PerSec: NewPointer(0)},
+		"invalid maxburst": {Route: NewPointer(RateLimitRouteSearch), MaxBurst: NewPointer(-1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rls := RateLimitSettings{RouteQuotas: []*RateLimitRouteQuota{quota}}
+			rls.SetDefaults()
+
+			appErr := rls.isValid()
+			require.NotNil(t, appErr)
+			assert.Equal(t, "model.config.is_valid.rate_limit_route_quota.app_error", appErr.Id)
+		})
+	}
+}
+
 func TestLdapSettingsIsValid(t *testing.T) {
 	for _, test := range []struct {
 		Name         string
@@ -2342,3 +2385,234 @@ func TestFilterConfig(t *testing.T) {
 		require.False(t, ok)
 	})
 }
+
+func TestWhitelistSettingsIsValid(t *testing.T) {
+	for name, test := range map[string]struct {
+		WhitelistSettings WhitelistSettings
+		ExpectError       bool
+	}{
+		"defaults": {
+			WhitelistSettings: WhitelistSettings{},
+		},
+		"bypass bots and deny oauth apps": {
+			WhitelistSettings: WhitelistSettings{
+				BotAccess:      NewPointer(WhitelistAccessBypass),
+				OAuthAppAccess: NewPointer(WhitelistAccessDeny),
+			},
+		},
+		"unknown bot access": {
+			WhitelistSettings: WhitelistSettings{
+				BotAccess: NewPointer("allow"),
+			},
+			ExpectError: true,
+		},
+		"deny personal access tokens": {
+			WhitelistSettings: WhitelistSettings{
+				PersonalAccessTokenAccess: NewPointer(WhitelistAccessDeny),
+			},
+		},
+		"unknown personal access token access": {
+			WhitelistSettings: WhitelistSettings{
+				PersonalAccessTokenAccess: NewPointer("allow"),
+			},
+			ExpectError: true,
+		},
+		"unknown empty whitelist action": {
+			WhitelistSettings: WhitelistSettings{
+				EmptyWhitelistAction: NewPointer("enforce"),
+			},
+			ExpectError: true,
+		},
+		"invalid bypass role": {
+			WhitelistSettings: WhitelistSettings{
+				BypassRoles: []string{"System Admin"},
+			},
+			ExpectError: true,
+		},
+		"relative excluded path": {
+			WhitelistSettings: WhitelistSettings{
+				ExcludedPaths: []string{"api/v4/system/ping"},
+			},
+			ExpectError: true,
+		},
+		"keep denied access forever": {
+			WhitelistSettings: WhitelistSettings{
+				DeniedAccessRetentionDays: NewPointer(0),
+			},
+		},
+		"negative denied access retention": {
+			WhitelistSettings: WhitelistSettings{
+				DeniedAccessRetentionDays: NewPointer(-1),
+			},
+			ExpectError: true,
+		},
+		"email only enrollment": {
+			WhitelistSettings: WhitelistSettings{
+				EnableSelfServiceEnrollment: NewPointer(true),
+				EnrollmentMethods:           []string{WhitelistEnrollmentMethodEmail},
+				EnrollmentEntryExpiryHours:  NewPointer(24),
+			},
+		},
+		"unknown enrollment method": {
+			WhitelistSettings: WhitelistSettings{
+				EnrollmentMethods: []string{"sms"},
+			},
+			ExpectError: true,
+		},
+		"zero enrollment link expiry": {
+			WhitelistSettings: WhitelistSettings{
+				EnrollmentLinkExpiryMinutes: NewPointer(0),
+			},
+			ExpectError: true,
+		},
+		"negative enrollment entry expiry": {
+			WhitelistSettings: WhitelistSettings{
+				EnrollmentEntryExpiryHours: NewPointer(-1),
+			},
+			ExpectError: true,
+		},
+		"zero enrollment rate limit": {
+			WhitelistSettings: WhitelistSettings{
+				EnrollmentRateLimitPerHour: NewPointer(0),
+			},
+			ExpectError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.WhitelistSettings.SetDefaults()
+
+			appErr := test.WhitelistSettings.isValid()
+			if test.ExpectError {
+				assert.NotNil(t, appErr)
+			} else {
+				assert.Nil(t, appErr)
+			}
+		})
+	}
+}
+
+func TestLoginThrottleSettingsIsValid(t *testing.T) {
+	for name, test := range map[string]struct {
+		LoginThrottleSettings LoginThrottleSettings
+		ExpectError           bool
+	}{
+		"defaults": {
+			LoginThrottleSettings: LoginThrottleSettings{},
+		},
+		"never ban": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				Enable:                NewPointer(true),
+				MaxAttemptsPerAddress: NewPointer(0),
+				MaxAttemptsPerSubnet:  NewPointer(0),
+			},
+		},
+		"exempt office network": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				ExemptAddresses: []string{"203.0.113.0/24", "2001:db8::1"},
+			},
+		},
+		"zero backoff attempts": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				BackoffAfterAttempts: NewPointer(0),
+			},
+			ExpectError: true,
+		},
+		"zero subnet backoff attempts": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				SubnetBackoffAfterAttempts: NewPointer(0),
+			},
+			ExpectError: true,
+		},
+		"negative max attempts": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				MaxAttemptsPerSubnet: NewPointer(-1),
+			},
+			ExpectError: true,
+		},
+		"ipv4 subnet too long": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				IPv4SubnetPrefixLength: NewPointer(33),
+			},
+			ExpectError: true,
+		},
+		"zero ban duration": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				BanDurationMinutes: NewPointer(0),
+			},
+			ExpectError: true,
+		},
+		"invalid exempt address": {
+			LoginThrottleSettings: LoginThrottleSettings{
+				ExemptAddresses: []string{"office"},
+			},
+			ExpectError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.LoginThrottleSettings.SetDefaults()
+
+			appErr := test.LoginThrottleSettings.isValid()
+			if test.ExpectError {
+				assert.NotNil(t, appErr)
+			} else {
+				assert.Nil(t, appErr)
+			}
+		})
+	}
+}
+
+func TestBleveSettingsIsValid(t *testing.T) {
+	for name, test := range map[string]struct {
+		BleveSettings BleveSettings
+		ExpectError   bool
+	}{
+		"defaults": {
+			BleveSettings: BleveSettings{},
+		},
+		"cjk posts and preserved numeric tokens": {
+			BleveSettings: BleveSettings{
+				PostIndexAnalyzer:     NewPointer(BleveAnalyzerCJK),
+				PreserveNumericTokens: NewPointer(true),
+			},
+		},
+		"language stemmer for files": {
+			BleveSettings: BleveSettings{
+				FileIndexAnalyzer: NewPointer(BleveAnalyzerGerman),
+			},
+		},
+		"unknown channel analyzer": {
+			BleveSettings: BleveSettings{
+				ChannelIndexAnalyzer: NewPointer("keyword"),
+			},
+			ExpectError: true,
+		},
+		"empty user analyzer": {
+			BleveSettings: BleveSettings{
+				UserIndexAnalyzer: NewPointer(""),
+			},
+			ExpectError: true,
+		},
+		"leader cluster mode": {
+			BleveSettings: BleveSettings{
+				ClusterMode: NewPointer(BleveClusterModeLeader),
+			},
+		},
+		"unknown cluster mode": {
+			BleveSettings: BleveSettings{
+				ClusterMode: NewPointer("replicated"),
+			},
+			ExpectError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			test.BleveSettings.SetDefaults()
+
+			appErr := test.BleveSettings.isValid()
+			if test.ExpectError {
+				assert.NotNil(t, appErr)
+			} else {
+				assert.Nil(t, appErr)
+			}
+		})
+	}
+}
@@ -13,6 +13,12 @@ import (
 const (
 	FileinfoSortByCreated = "CreateAt"
 	FileinfoSortBySize    = "Size"
+
+	// MaxContentExtractionFailures is the number of failed attempts after which the content
+	// of a file isn't extracted anymore.
+	MaxContentExtractionFailures = 3
+	// ContentExtractionErrorMaxLength is the length the recorded extraction errors are cut to.
+	ContentExtractionErrorMaxLength = 256
 )
 
 // GetFileInfosOptions contains options for getting FileInfos
@@ -57,6 +63,10 @@ type FileInfo struct {
 	Content         string  `json:"-"`
 	RemoteId        *string `json:"remote_id"`
 	Archived        bool    `json:"archived"`
+	// ContentExtractionFailures counts the failed attempts to extract the content of the file,
+	// which is no longer extracted once it reaches MaxContentExtractionFailures.
+	ContentExtractionFailures int    `json:"-"`
+	ContentExtractionError    string `json:"-"`
 }
 
 func (fi *FileInfo) Auditable() map[string]any {
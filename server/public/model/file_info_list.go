@@ -14,6 +14,8 @@ type FileInfoList struct {
 	PrevFileInfoId string               `json:"prev_file_info_id"`
 	// If there are inaccessible files, FirstInaccessibleFileTime is the time of the latest inaccessible file
 	FirstInaccessibleFileTime int64 `json:"first_inaccessible_file_time"`
+	// Snippets is only set on search results, when the search engine provides them
+	Snippets FileInfoSearchSnippets `json:"snippets,omitempty"`
 }
 
 func NewFileInfoList() *FileInfoList {
@@ -5,6 +5,10 @@ package model
 
 type FileInfoSearchMatches map[string][]string
 
+// FileInfoSearchSnippets holds, by file id, HTML escaped fragments of the file
+// contents around the search matches, with the matching terms within <mark> tags.
+type FileInfoSearchSnippets map[string][]string
+
 type FileInfoSearchResults struct {
 	*FileInfoList
 	Matches FileInfoSearchMatches `json:"matches"`
@@ -16,6 +16,8 @@ const (
 	JobTypeElasticsearchPostIndexing     = "elasticsearch_post_indexing"
 	JobTypeElasticsearchPostAggregation  = "elasticsearch_post_aggregation"
 	JobTypeBlevePostIndexing             = "bleve_post_indexing"
+	JobTypeBleveDataRetention            = "bleve_data_retention"
+	JobTypeBleveSnapshot                 = "bleve_snapshot"
 	JobTypeLdapSync                      = "ldap_sync"
 	JobTypeMigrations                    = "migrations"
 	JobTypePlugins                       = "plugins"
@@ -45,6 +47,8 @@ const (
 	JobTypeDeleteDmsPreferencesMigration = "delete_dms_preferences_migration"
 	JobTypeMobileSessionMetadata         = "mobile_session_metadata"
 	JobTypeAccessControlSync             = "access_control_sync"
+	JobTypeCleanupExpiredWhitelist       = "cleanup_expired_whitelist"
+	JobTypeCleanupExpiredLoginBans       = "cleanup_expired_login_bans"
 
 	JobStatusPending         = "pending"
 	JobStatusInProgress      = "in_progress"
@@ -61,6 +65,8 @@ var AllJobTypes = [...]string{
 	JobTypeElasticsearchPostIndexing,
 	JobTypeElasticsearchPostAggregation,
 	JobTypeBlevePostIndexing,
+	JobTypeBleveDataRetention,
+	JobTypeBleveSnapshot,
 	JobTypeLdapSync,
 	JobTypeMigrations,
 	JobTypePlugins,
@@ -76,6 +82,8 @@ var AllJobTypes = [...]string{
 	JobTypeLastAccessiblePost,
 	JobTypeLastAccessibleFile,
 	JobTypeCleanupDesktopTokens,
+	JobTypeCleanupExpiredWhitelist,
+	JobTypeCleanupExpiredLoginBans,
 	JobTypeRefreshMaterializedViews,
 	JobTypeMobileSessionMetadata,
 }
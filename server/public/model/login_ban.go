@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"net/netip"
+)
+
+const (
+	// The failed logins that led to a ban came from the address itself, or
+	// from anywhere in its subnet.
+	LoginBanReasonAddress = "address"
+	LoginBanReasonSubnet  = "subnet"
+)
+
+// LoginBan refuses the logins from an address or subnet until it expires. Bans
+// are created by the login throttle, see LoginThrottleSettings.
+type LoginBan struct {
+	IP             string `json:"ip"`
+	Reason         string `json:"reason"`
+	FailedAttempts int    `json:"failed_attempts"`
+	CreateAt       int64  `json:"create_at"`
+	ExpireAt       int64  `json:"expire_at"`
+}
+
+func (o *LoginBan) Auditable() map[string]any {
+	return map[string]any{
+		"ip":              o.IP,
+		"reason":          o.Reason,
+		"failed_attempts": o.FailedAttempts,
+		"create_at":       o.CreateAt,
+		"expire_at":       o.ExpireAt,
+	}
+}
+
+func (o *LoginBan) PreSave() {
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	if normalized, err := NormalizeWhitelistIP(o.IP); err == nil {
+		o.IP = normalized
+	}
+}
+
+func (o *LoginBan) IsValid() *AppError {
+	if _, err := ParseWhitelistPrefix(o.IP); err != nil || len(o.IP) > WhitelistItemIPMaxLength {
+		return NewAppError("LoginBan.IsValid", "model.login_ban.is_valid.ip.app_error", nil, "ip="+o.IP, http.StatusBadRequest)
+	}
+
+	if o.Reason != LoginBanReasonAddress && o.Reason != LoginBanReasonSubnet {
+		return NewAppError("LoginBan.IsValid", "model.login_ban.is_valid.reason.app_error", nil, "ip="+o.IP, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("LoginBan.IsValid", "model.login_ban.is_valid.create_at.app_error", nil, "ip="+o.IP, http.StatusBadRequest)
+	}
+
+	if o.ExpireAt <= o.CreateAt {
+		return NewAppError("LoginBan.IsValid", "model.login_ban.is_valid.expire_at.app_error", nil, "ip="+o.IP, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Prefix returns the network covered by the ban.
+func (o *LoginBan) Prefix() (netip.Prefix, error) {
+	return ParseWhitelistPrefix(o.IP)
+}
+
+// IsExpired reports whether the ban expired at or before the given time in milliseconds.
+func (o *LoginBan) IsExpired(now int64) bool {
+	return o.ExpireAt <= now
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginBanPreSave(t *testing.T) {
+	o := LoginBan{IP: "203.0.113.77/24"}
+	o.PreSave()
+
+	assert.NotZero(t, o.CreateAt)
+	assert.Equal(t, "203.0.113.0/24", o.IP)
+
+	o = LoginBan{IP: "::ffff:203.0.113.7", CreateAt: 1}
+	o.PreSave()
+	assert.Equal(t, int64(1), o.CreateAt)
+	assert.Equal(t, "203.0.113.7", o.IP)
+}
+
+func TestLoginBanIsValid(t *testing.T) {
+	o := LoginBan{}
+	assert.NotNil(t, o.IsValid())
+
+	o.IP = "2001:db8::/64"
+	assert.NotNil(t, o.IsValid())
+
+	o.Reason = LoginBanReasonSubnet
+	assert.NotNil(t, o.IsValid())
+
+	o.CreateAt = GetMillis()
+	assert.NotNil(t, o.IsValid())
+
+	o.ExpireAt = o.CreateAt + 1000
+	assert.Nil(t, o.IsValid())
+
+	o.Reason = "manual"
+	assert.NotNil(t, o.IsValid())
+
+	o.Reason = LoginBanReasonAddress
+	o.IP = "not an address"
+	assert.NotNil(t, o.IsValid())
+}
+
+func TestLoginBanPrefix(t *testing.T) {
+	o := LoginBan{IP: "203.0.113.0/24", ExpireAt: 10}
+
+	prefix, err := o.Prefix()
+	require.NoError(t, err)
+	assert.Equal(t, 24, prefix.Bits())
+
+	assert.False(t, o.IsExpired(9))
+	assert.True(t, o.IsExpired(10))
+}
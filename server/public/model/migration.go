@@ -55,4 +55,5 @@ const (
 	MigrationRemoveGetAnalyticsPermission              = "remove_get_analytics_permission"
 	MigrationAddSysconsoleMobileSecurityPermission     = "add_sysconsole_mobile_security_permission"
 	MigrationKeyAddChannelBannerPermissions            = "add_channel_banner_permissions"
+	MigrationKeyAddChannelScheduledPostsPermission     = "add_channel_scheduled_posts_permission"
 )
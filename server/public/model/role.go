@@ -916,6 +916,7 @@ func MakeDefaultRoles() map[string]*Role {
 			PermissionOrderBookmarkPrivateChannel.Id,
 			PermissionManagePublicChannelBanner.Id,
 			PermissionManagePrivateChannelBanner.Id,
+			PermissionManageChannelScheduledPosts.Id,
 		},
 		SchemeManaged: true,
 		BuiltIn:       true,
@@ -1004,6 +1005,7 @@ func MakeDefaultRoles() map[string]*Role {
 			PermissionOrderBookmarkPrivateChannel.Id,
 			PermissionManagePublicChannelBanner.Id,
 			PermissionManagePrivateChannelBanner.Id,
+			PermissionManageChannelScheduledPosts.Id,
 		},
 		SchemeManaged: true,
 		BuiltIn:       true,
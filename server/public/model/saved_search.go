@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	SavedSearchNameMaxLength  = 64
+	SavedSearchTermsMaxLength = 1024
+	SavedSearchMaxPerUser     = 100
+)
+
+// SavedSearch is a search a user keeps to run it again. With Alert set, the
+// user also gets a direct message for every new post the search matches.
+type SavedSearch struct {
+	Id         string `json:"id"`
+	UserId     string `json:"user_id"`
+	TeamId     string `json:"team_id"` // Empty to search all the teams of the user
+	Name       string `json:"name"`
+	Terms      string `json:"terms"`
+	IsOrSearch bool   `json:"is_or_search"`
+	Alert      bool   `json:"alert"`
+	CreateAt   int64  `json:"create_at"`
+	UpdateAt   int64  `json:"update_at"`
+}
+
+type SavedSearchPatch struct {
+	Name       *string `json:"name"`
+	Terms      *string `json:"terms"`
+	IsOrSearch *bool   `json:"is_or_search"`
+	Alert      *bool   `json:"alert"`
+}
+
+func (s *SavedSearch) Auditable() map[string]any {
+	return map[string]any{
+		"id":           s.Id,
+		"user_id":      s.UserId,
+		"team_id":      s.TeamId,
+		"name":         s.Name,
+		"is_or_search": s.IsOrSearch,
+		"alert":        s.Alert,
+		"create_at":    s.CreateAt,
+		"update_at":    s.UpdateAt,
+	}
+}
+
+func (s *SavedSearch) PreSave() {
+	if s.Id == "" {
+		s.Id = NewId()
+	}
+
+	s.CreateAt = GetMillis()
+	s.UpdateAt = s.CreateAt
+	s.normalize()
+}
+
+func (s *SavedSearch) PreUpdate() {
+	s.UpdateAt = GetMillis()
+	s.normalize()
+}
+
+func (s *SavedSearch) normalize() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Terms = strings.TrimSpace(s.Terms)
+}
+
+func (s *SavedSearch) Patch(patch *SavedSearchPatch) {
+	if patch.Name != nil {
+		s.Name = *patch.Name
+	}
+	if patch.Terms != nil {
+		s.Terms = *patch.Terms
+	}
+	if patch.IsOrSearch != nil {
+		s.IsOrSearch = *patch.IsOrSearch
+	}
+	if patch.Alert != nil {
+		s.Alert = *patch.Alert
+	}
+}
+
+func (s *SavedSearch) IsValid() *AppError {
+	if !IsValidId(s.Id) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(s.UserId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.user_id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.TeamId != "" && !IsValidId(s.TeamId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.team_id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.Name == "" || utf8.RuneCountInString(s.Name) > SavedSearchNameMaxLength {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.name.app_error", map[string]any{"MaxLength": SavedSearchNameMaxLength}, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.Terms == "" || utf8.RuneCountInString(s.Terms) > SavedSearchTermsMaxLength {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.terms.app_error", map[string]any{"MaxLength": SavedSearchTermsMaxLength}, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.CreateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.create_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.UpdateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.update_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
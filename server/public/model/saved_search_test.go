@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchPreSave(t *testing.T) {
+	savedSearch := SavedSearch{
+		UserId: NewId(),
+		Name:   "  outages ",
+		Terms:  " error OR failure ",
+	}
+	savedSearch.PreSave()
+
+	assert.True(t, IsValidId(savedSearch.Id))
+	assert.NotZero(t, savedSearch.CreateAt)
+	assert.Equal(t, savedSearch.CreateAt, savedSearch.UpdateAt)
+	assert.Equal(t, "outages", savedSearch.Name)
+	assert.Equal(t, "error OR failure", savedSearch.Terms)
+}
+
+func TestSavedSearchPatch(t *testing.T) {
+	savedSearch := &SavedSearch{Name: "outages", Terms: "error", Alert: true}
+	savedSearch.Patch(&SavedSearchPatch{Terms: NewPointer("error OR failure"), Alert: NewPointer(false)})
+
+	assert.Equal(t, "outages", savedSearch.Name)
+	assert.Equal(t, "error OR failure", savedSearch.Terms)
+	assert.False(t, savedSearch.Alert)
+}
+
+func TestSavedSearchIsValid(t *testing.T) {
+	valid := func() *SavedSearch {
+		return &SavedSearch{
+			Id:       NewId(),
+			UserId:   NewId(),
+			Name:     "outages",
+			Terms:    "error OR failure in:alerts",
+			CreateAt: 1,
+			UpdateAt: 1,
+		}
+	}
+
+	require.Nil(t, valid().IsValid())
+
+	testCases := []struct {
+		Name    string
+		Modify  func(s *SavedSearch)
+		ErrorId string
+	}{
+		{"invalid id", func(s *SavedSearch) { s.Id = "abc" }, "model.saved_search.is_valid.id.app_error"},
+		{"invalid user id", func(s *SavedSearch) { s.UserId = "" }, "model.saved_search.is_valid.user_id.app_error"},
+		{"invalid team id", func(s *SavedSearch) { s.TeamId = "abc" }, "model.saved_search.is_valid.team_id.app_error"},
+		{"empty name", func(s *SavedSearch) { s.Name = "" }, "model.saved_search.is_valid.name.app_error"},
+		{"long name", func(s *SavedSearch) { s.Name = strings.Repeat("a", SavedSearchNameMaxLength+1) }, "model.saved_search.is_valid.name.app_error"},
+		{"empty terms", func(s *SavedSearch) { s.Terms = "" }, "model.saved_search.is_valid.terms.app_error"},
+		{"long terms", func(s *SavedSearch) { s.Terms = strings.Repeat("a", SavedSearchTermsMaxLength+1) }, "model.saved_search.is_valid.terms.app_error"},
+		{"missing create at", func(s *SavedSearch) { s.CreateAt = 0 }, "model.saved_search.is_valid.create_at.app_error"},
+		{"missing update at", func(s *SavedSearch) { s.UpdateAt = 0 }, "model.saved_search.is_valid.update_at.app_error"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			savedSearch := valid()
+			testCase.Modify(savedSearch)
+			appErr := savedSearch.IsValid()
+			require.NotNil(t, appErr)
+			assert.Equal(t, testCase.ErrorId, appErr.Id)
+		})
+	}
+}
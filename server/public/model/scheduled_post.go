@@ -6,6 +6,9 @@ package model
 import (
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
+	"time"
 )
 
 const (
@@ -19,6 +22,7 @@ const (
 	ScheduledPostErrorThreadDeleted           = "thread_deleted"
 	ScheduledPostErrorUnableToSend            = "unable_to_send"
 	ScheduledPostErrorInvalidPost             = "invalid_post"
+	ScheduledPostErrorCodeBotUnavailable      = "bot_unavailable"
 )
 
 // allow scheduled posts to be created up to
@@ -28,10 +32,32 @@ const scheduledPostMaxTimeGap = -5000
 
 type ScheduledPost struct {
 	Draft
-	Id          string `json:"id"`
+	Id string `json:"id"`
+	// ScheduledAt is the time the post is sent at, or the time of its next
+	// occurrence if it recurs.
 	ScheduledAt int64  `json:"scheduled_at"`
 	ProcessedAt int64  `json:"processed_at"`
 	ErrorCode   string `json:"error_code"`
+
+	// RecurrenceRule makes the post recur, as an RFC 5545 RRULE subset parsed
+	// by ParseRecurrenceRule. Occurrences are in the timezone of the user.
+	RecurrenceRule string `json:"recurrence_rule"`
+	// OccurrenceCount is the number of occurrences of a recurring post that
+	// have passed, whether they were sent or skipped.
+	OccurrenceCount int `json:"occurrence_count"`
+	// PausedAt is the time a recurring post was paused at, or zero.
+	PausedAt int64 `json:"paused_at"`
+	// RecurrenceStartAt is the time the series of a recurring post starts at,
+	// whose time of day every occurrence keeps. It follows ScheduledAt when
+	// the post is created, and when its time is changed.
+	RecurrenceStartAt int64 `json:"recurrence_start_at"`
+
+	// PublishAsBotId is the user ID of the bot the post is published as, or
+	// empty to publish it as its author.
+	PublishAsBotId string `json:"publish_as_bot_id"`
+	// LastEditorId is the ID of the user who last updated the post, who may
+	// be a channel admin rather than its author.
+	LastEditorId string `json:"last_editor_id"`
 }
 
 func (s *ScheduledPost) IsValid(maxMessageSize int) *AppError {
@@ -64,6 +90,16 @@ func (s *ScheduledPost) BaseIsValid() *AppError {
 		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.processed_at.app_error", nil, "id="+s.Id, http.StatusBadRequest)
 	}
 
+	if s.PublishAsBotId != "" && !IsValidId(s.PublishAsBotId) {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.publish_as_bot_id.app_error", nil, "id="+s.Id, http.StatusBadRequest)
+	}
+
+	if s.RecurrenceRule != "" {
+		if _, err := ParseRecurrenceRule(s.RecurrenceRule); err != nil {
+			return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.recurrence_rule.app_error", map[string]any{"Error": err.Error()}, "id="+s.Id, http.StatusBadRequest).Wrap(err)
+		}
+	}
+
 	return nil
 }
 
@@ -74,6 +110,10 @@ func (s *ScheduledPost) PreSave() {
 
 	s.ProcessedAt = 0
 	s.ErrorCode = ""
+	s.OccurrenceCount = 0
+	s.PausedAt = 0
+	s.RecurrenceStartAt = s.ScheduledAt
+	s.LastEditorId = ""
 
 	s.Draft.PreSave()
 }
@@ -83,10 +123,60 @@ func (s *ScheduledPost) PreUpdate() {
 	s.Draft.PreCommit()
 }
 
+func (s *ScheduledPost) IsRecurring() bool {
+	return s.RecurrenceRule != ""
+}
+
+func (s *ScheduledPost) IsPaused() bool {
+	return s.PausedAt != 0
+}
+
+// PublisherId is the ID of the user the post is published as.
+func (s *ScheduledPost) PublisherId() string {
+	if s.PublishAsBotId != "" {
+		return s.PublishAsBotId
+	}
+
+	return s.UserId
+}
+
+// Reschedule moves a recurring post to its first occurrence after the given
+// time, counting every occurrence it passes. It returns false once the series
+// has ended, leaving the post as it was.
+func (s *ScheduledPost) Reschedule(after int64, loc *time.Location) bool {
+	rule, err := ParseRecurrenceRule(s.RecurrenceRule)
+	if err != nil {
+		return false
+	}
+
+	start := s.RecurrenceStartAt
+	if start == 0 {
+		start = s.ScheduledAt
+	}
+
+	next, count := s.ScheduledAt, s.OccurrenceCount
+	for next <= after {
+		count++
+		if rule.Count > 0 && count >= rule.Count {
+			return false
+		}
+
+		nextTime := rule.Next(time.UnixMilli(next), time.UnixMilli(start), loc)
+		if nextTime.IsZero() {
+			return false
+		}
+		next = nextTime.UnixMilli()
+	}
+
+	s.ScheduledAt = next
+	s.OccurrenceCount = count
+	return true
+}
+
 // ToPost converts a scheduled post toa  regular, mattermost post object.
 func (s *ScheduledPost) ToPost() (*Post, error) {
 	post := &Post{
-		UserId:    s.UserId,
+		UserId:    s.PublisherId(),
 		ChannelId: s.ChannelId,
 		Message:   s.Message,
 		FileIds:   s.FileIds,
@@ -144,6 +234,12 @@ func (s *ScheduledPost) Auditable() map[string]any {
 		"props":      s.GetProps(),
 		"file_ids":   s.FileIds,
 		"metadata":   metaData,
+
+		"recurrence_rule": s.RecurrenceRule,
+		"paused_at":       s.PausedAt,
+
+		"publish_as_bot_id": s.PublishAsBotId,
+		"last_editor_id":    s.LastEditorId,
 	}
 }
 
@@ -153,6 +249,33 @@ func (s *ScheduledPost) RestoreNonUpdatableFields(originalScheduledPost *Schedul
 	s.UserId = originalScheduledPost.UserId
 	s.ChannelId = originalScheduledPost.ChannelId
 	s.RootId = originalScheduledPost.RootId
+	s.OccurrenceCount = originalScheduledPost.OccurrenceCount
+	s.PausedAt = originalScheduledPost.PausedAt
+	s.LastEditorId = originalScheduledPost.LastEditorId
+
+	// The series starts anew at the time it's moved to
+	s.RecurrenceStartAt = originalScheduledPost.RecurrenceStartAt
+	if s.ScheduledAt != originalScheduledPost.ScheduledAt || s.RecurrenceStartAt == 0 {
+		s.RecurrenceStartAt = s.ScheduledAt
+	}
+}
+
+// HasSameContent reports whether two scheduled posts publish the same
+// message, files, props and priority.
+func (s *ScheduledPost) HasSameContent(o *ScheduledPost) bool {
+	return s.Message == o.Message &&
+		slices.Equal(s.FileIds, o.FileIds) &&
+		sameStringInterface(s.GetProps(), o.GetProps()) &&
+		sameStringInterface(s.Priority, o.Priority) &&
+		reflect.DeepEqual(s.GetPriority(), o.GetPriority())
+}
+
+// sameStringInterface compares two maps, treating nil and empty as equal.
+func sameStringInterface(a, b StringInterface) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
 }
 
 func (s *ScheduledPost) SanitizeInput() {
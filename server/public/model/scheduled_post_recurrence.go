@@ -0,0 +1,230 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceFrequencyDaily   = "DAILY"
+	RecurrenceFrequencyWeekly  = "WEEKLY"
+	RecurrenceFrequencyMonthly = "MONTHLY"
+
+	RecurrenceRuleMaxLength = 256
+
+	recurrenceUntilLayout = "20060102T150405Z"
+
+	// recurrenceMaxIterations bounds the search for the next occurrence of
+	// rules that can never match, like the 31st of every other February.
+	recurrenceMaxIterations = 1000
+)
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RecurrenceRule is the subset of the RFC 5545 RRULE that scheduled posts
+// support: FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY without ordinals,
+// BYMONTHDAY, and either COUNT or UNTIL. The start of the series and the time
+// of day of every occurrence are those of the first scheduled time.
+type RecurrenceRule struct {
+	Frequency  string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      int64
+}
+
+// ParseRecurrenceRule parses a rule such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=10".
+// The "RRULE:" prefix is optional.
+func ParseRecurrenceRule(rule string) (*RecurrenceRule, error) {
+	if len(rule) > RecurrenceRuleMaxLength {
+		return nil, fmt.Errorf("rule is longer than %d characters", RecurrenceRuleMaxLength)
+	}
+
+	r := &RecurrenceRule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(strings.TrimPrefix(strings.ToUpper(rule), "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate rule part %s", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			if value != RecurrenceFrequencyDaily && value != RecurrenceFrequencyWeekly && value != RecurrenceFrequencyMonthly {
+				return nil, fmt.Errorf("unsupported frequency %s", value)
+			}
+			r.Frequency = value
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("invalid interval %s", value)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("unsupported day %s", day)
+				}
+				if !slices.Contains(r.ByDay, weekday) {
+					r.ByDay = append(r.ByDay, weekday)
+				}
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid day of the month %s", day)
+				}
+				if !slices.Contains(r.ByMonthDay, monthDay) {
+					r.ByMonthDay = append(r.ByMonthDay, monthDay)
+				}
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err != nil || r.Count < 1 {
+				return nil, fmt.Errorf("invalid count %s", value)
+			}
+		case "UNTIL":
+			until, err := time.Parse(recurrenceUntilLayout, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid until %s, must be in UTC like 20060102T150405Z", value)
+			}
+			r.Until = until.UnixMilli()
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+
+	if r.Frequency == "" {
+		return nil, fmt.Errorf("missing frequency")
+	}
+	if r.Count > 0 && r.Until > 0 {
+		return nil, fmt.Errorf("count and until can't be combined")
+	}
+	if len(r.ByMonthDay) > 0 && r.Frequency != RecurrenceFrequencyMonthly {
+		return nil, fmt.Errorf("days of the month are only supported with a monthly frequency")
+	}
+	if len(r.ByDay) > 0 && r.Frequency == RecurrenceFrequencyMonthly {
+		return nil, fmt.Errorf("days of the week are not supported with a monthly frequency")
+	}
+
+	return r, nil
+}
+
+// Next returns the first occurrence of the series after the previous one, at
+// the time of day of the start of the series in the given location. Taking it
+// from the start rather than from the previous occurrence keeps it across
+// daylight saving time changes, even after an occurrence whose time didn't
+// exist on its day and was moved by the change. It returns the zero time once
+// the series has ended by its UNTIL, or when the rule can never match again.
+func (r *RecurrenceRule) Next(previous, start time.Time, loc *time.Location) time.Time {
+	previous, start = previous.In(loc), start.In(loc)
+	hour, minute, sec := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, 0, loc)
+	}
+
+	var next time.Time
+	switch r.Frequency {
+	case RecurrenceFrequencyDaily:
+		next = r.nextDaily(previous, at)
+	case RecurrenceFrequencyWeekly:
+		next = r.nextWeekly(previous, at)
+	case RecurrenceFrequencyMonthly:
+		next = r.nextMonthly(previous, start, at)
+	}
+
+	if r.Until > 0 && next.UnixMilli() > r.Until {
+		return time.Time{}
+	}
+
+	return next
+}
+
+func (r *RecurrenceRule) nextDaily(previous time.Time, at func(int, time.Month, int) time.Time) time.Time {
+	year, month, day := previous.Date()
+	for i := 1; i <= recurrenceMaxIterations; i++ {
+		candidate := at(year, month, day+i*r.Interval)
+		if len(r.ByDay) == 0 || slices.Contains(r.ByDay, candidate.Weekday()) {
+			return candidate
+		}
+	}
+
+	return time.Time{}
+}
+
+func (r *RecurrenceRule) nextWeekly(previous time.Time, at func(int, time.Month, int) time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		year, month, day := previous.Date()
+		return at(year, month, day+7*r.Interval)
+	}
+
+	// Weeks start on Monday, as the RFC 5545 WKST default
+	year, month, day := previous.Date()
+	weekStart := day - (int(previous.Weekday())+6)%7
+	for week := 0; week <= recurrenceMaxIterations; week += r.Interval {
+		for offset := range 7 {
+			candidate := at(year, month, weekStart+7*week+offset)
+			if candidate.After(previous) && slices.Contains(r.ByDay, candidate.Weekday()) {
+				return candidate
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+func (r *RecurrenceRule) nextMonthly(previous, start time.Time, at func(int, time.Month, int) time.Time) time.Time {
+	monthDays := r.ByMonthDay
+	if len(monthDays) == 0 {
+		monthDays = []int{start.Day()}
+	}
+
+	year, month, _ := previous.Date()
+	for i := 0; i <= recurrenceMaxIterations; i += r.Interval {
+		firstOfMonth := at(year, month+time.Month(i), 1)
+		daysInMonth := firstOfMonth.AddDate(0, 1, -1).Day()
+
+		var candidates []time.Time
+		for _, monthDay := range monthDays {
+			if monthDay < 0 {
+				monthDay += daysInMonth + 1
+			}
+			// Months without the day are skipped, as RFC 5545 requires
+			if monthDay < 1 || monthDay > daysInMonth {
+				continue
+			}
+
+			candidate := at(firstOfMonth.Year(), firstOfMonth.Month(), monthDay)
+			if candidate.After(previous) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) > 0 {
+			return slices.MinFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	t.Run("valid rules", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10")
+		require.NoError(t, err)
+		assert.Equal(t, &RecurrenceRule{
+			Frequency: RecurrenceFrequencyWeekly,
+			Interval:  2,
+			ByDay:     []time.Weekday{time.Monday, time.Friday},
+			Count:     10,
+		}, rule)
+
+		rule, err = ParseRecurrenceRule("freq=monthly;bymonthday=1,-1;until=20260101T000000Z")
+		require.NoError(t, err)
+		assert.Equal(t, &RecurrenceRule{
+			Frequency:  RecurrenceFrequencyMonthly,
+			Interval:   1,
+			ByMonthDay: []int{1, -1},
+			Until:      time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		}, rule)
+	})
+
+	for name, rule := range map[string]string{
+		"empty":                      "",
+		"missing frequency":          "INTERVAL=2",
+		"yearly frequency":           "FREQ=YEARLY",
+		"zero interval":              "FREQ=DAILY;INTERVAL=0",
+		"ordinal day":                "FREQ=WEEKLY;BYDAY=1MO",
+		"zero day of the month":      "FREQ=MONTHLY;BYMONTHDAY=0",
+		"day of the month too large": "FREQ=MONTHLY;BYMONTHDAY=32",
+		"day of the month in weekly": "FREQ=WEEKLY;BYMONTHDAY=1",
+		"day of the week in monthly": "FREQ=MONTHLY;BYDAY=MO",
+		"count and until":            "FREQ=DAILY;COUNT=2;UNTIL=20260101T000000Z",
+		"local until":                "FREQ=DAILY;UNTIL=20260101T000000",
+		"duplicate part":             "FREQ=DAILY;FREQ=WEEKLY",
+		"unsupported part":           "FREQ=DAILY;BYHOUR=9",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRecurrenceRule(rule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	next := func(t *testing.T, rule string, previous time.Time) time.Time {
+		t.Helper()
+		r, err := ParseRecurrenceRule(rule)
+		require.NoError(t, err)
+		return r.Next(previous, previous, loc)
+	}
+
+	// Friday
+	friday := time.Date(2025, time.March, 7, 9, 30, 0, 0, loc)
+
+	t.Run("daily keeps the time of day across daylight saving time", func(t *testing.T) {
+		// Daylight saving time starts on Sunday March 9th
+		got := next(t, "FREQ=DAILY;INTERVAL=2", friday)
+		assert.Equal(t, time.Date(2025, time.March, 9, 9, 30, 0, 0, loc), got)
+		assert.Equal(t, 47*time.Hour, got.Sub(friday))
+	})
+
+	t.Run("daily keeps the time of day of the start after a time that didn't exist", func(t *testing.T) {
+		r, err := ParseRecurrenceRule("FREQ=DAILY")
+		require.NoError(t, err)
+
+		// 2:30 doesn't exist on March 9th, when the clocks move from 2:00 to 3:00
+		start := time.Date(2025, time.March, 8, 2, 30, 0, 0, loc)
+		moved := r.Next(start, start, loc)
+		assert.NotEqual(t, 2, moved.Hour())
+		assert.Equal(t, time.Date(2025, time.March, 10, 2, 30, 0, 0, loc), r.Next(moved, start, loc))
+	})
+
+	t.Run("monthly keeps the day of the start", func(t *testing.T) {
+		r, err := ParseRecurrenceRule("FREQ=MONTHLY")
+		require.NoError(t, err)
+
+		start := time.Date(2025, time.January, 30, 9, 30, 0, 0, loc)
+		march := time.Date(2025, time.March, 30, 9, 30, 0, 0, loc)
+		assert.Equal(t, time.Date(2025, time.April, 30, 9, 30, 0, 0, loc), r.Next(march, start, loc))
+	})
+
+	t.Run("weekdays skip the weekend", func(t *testing.T) {
+		got := next(t, "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", friday)
+		assert.Equal(t, time.Date(2025, time.March, 10, 9, 30, 0, 0, loc), got)
+	})
+
+	t.Run("weekly on the same day", func(t *testing.T) {
+		got := next(t, "FREQ=WEEKLY", friday)
+		assert.Equal(t, time.Date(2025, time.March, 14, 9, 30, 0, 0, loc), got)
+	})
+
+	t.Run("weekly on several days every other week", func(t *testing.T) {
+		rule := "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,FR"
+		tuesday := time.Date(2025, time.March, 4, 9, 30, 0, 0, loc)
+		assert.Equal(t, friday, next(t, rule, tuesday))
+		assert.Equal(t, time.Date(2025, time.March, 18, 9, 30, 0, 0, loc), next(t, rule, friday))
+	})
+
+	t.Run("monthly skips the months without the day", func(t *testing.T) {
+		january := time.Date(2025, time.January, 31, 9, 30, 0, 0, loc)
+		assert.Equal(t, time.Date(2025, time.March, 31, 9, 30, 0, 0, loc), next(t, "FREQ=MONTHLY", january))
+	})
+
+	t.Run("monthly on the last day", func(t *testing.T) {
+		january := time.Date(2025, time.January, 31, 9, 30, 0, 0, loc)
+		assert.Equal(t, time.Date(2025, time.February, 28, 9, 30, 0, 0, loc), next(t, "FREQ=MONTHLY;BYMONTHDAY=-1", january))
+	})
+
+	t.Run("monthly on several days", func(t *testing.T) {
+		rule := "FREQ=MONTHLY;BYMONTHDAY=1,15"
+		assert.Equal(t, time.Date(2025, time.March, 15, 9, 30, 0, 0, loc), next(t, rule, friday))
+		assert.Equal(t, time.Date(2025, time.April, 1, 9, 30, 0, 0, loc), next(t, rule, time.Date(2025, time.March, 15, 9, 30, 0, 0, loc)))
+	})
+
+	t.Run("until ends the series", func(t *testing.T) {
+		assert.True(t, next(t, "FREQ=DAILY;UNTIL=20250308T000000Z", friday).IsZero())
+		assert.False(t, next(t, "FREQ=DAILY;UNTIL=20250309T000000Z", friday).IsZero())
+	})
+
+	t.Run("rule that never matches", func(t *testing.T) {
+		february := time.Date(2025, time.February, 1, 9, 30, 0, 0, loc)
+		assert.True(t, next(t, "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", february).IsZero())
+	})
+}
+
+func TestScheduledPostReschedule(t *testing.T) {
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("moves to the next occurrence", func(t *testing.T) {
+		s := &ScheduledPost{ScheduledAt: start.UnixMilli(), RecurrenceRule: "FREQ=DAILY"}
+		require.True(t, s.Reschedule(start.UnixMilli(), time.UTC))
+		assert.Equal(t, start.Add(day).UnixMilli(), s.ScheduledAt)
+		assert.Equal(t, 1, s.OccurrenceCount)
+	})
+
+	t.Run("passes the missed occurrences", func(t *testing.T) {
+		s := &ScheduledPost{ScheduledAt: start.UnixMilli(), RecurrenceRule: "FREQ=DAILY"}
+		require.True(t, s.Reschedule(start.Add(3*day+time.Hour).UnixMilli(), time.UTC))
+		assert.Equal(t, start.Add(4*day).UnixMilli(), s.ScheduledAt)
+		assert.Equal(t, 4, s.OccurrenceCount)
+	})
+
+	t.Run("ends after count occurrences", func(t *testing.T) {
+		s := &ScheduledPost{ScheduledAt: start.UnixMilli(), RecurrenceRule: "FREQ=DAILY;COUNT=2"}
+		require.True(t, s.Reschedule(s.ScheduledAt, time.UTC))
+		require.False(t, s.Reschedule(s.ScheduledAt, time.UTC))
+		assert.Equal(t, start.Add(day).UnixMilli(), s.ScheduledAt)
+		assert.Equal(t, 1, s.OccurrenceCount)
+	})
+
+	t.Run("keeps the time of day of the start", func(t *testing.T) {
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		start := time.Date(2025, time.March, 8, 2, 30, 0, 0, loc)
+		s := &ScheduledPost{ScheduledAt: time.Date(2025, time.March, 9, 3, 30, 0, 0, loc).UnixMilli(), RecurrenceRule: "FREQ=DAILY", RecurrenceStartAt: start.UnixMilli()}
+		require.True(t, s.Reschedule(s.ScheduledAt, loc))
+		assert.Equal(t, time.Date(2025, time.March, 10, 2, 30, 0, 0, loc).UnixMilli(), s.ScheduledAt)
+	})
+
+	t.Run("ends at until", func(t *testing.T) {
+		s := &ScheduledPost{ScheduledAt: start.UnixMilli(), RecurrenceRule: "FREQ=WEEKLY;UNTIL=20250305T000000Z"}
+		assert.False(t, s.Reschedule(s.ScheduledAt, time.UTC))
+	})
+
+	t.Run("is not recurring", func(t *testing.T) {
+		s := &ScheduledPost{ScheduledAt: start.UnixMilli()}
+		assert.False(t, s.IsRecurring())
+		assert.False(t, s.Reschedule(s.ScheduledAt, time.UTC))
+	})
+}
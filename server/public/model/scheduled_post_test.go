@@ -0,0 +1,62 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledPostHasSameContent(t *testing.T) {
+	newScheduledPost := func() *ScheduledPost {
+		s := &ScheduledPost{
+			Draft: Draft{
+				Message: "this is an announcement",
+				FileIds: []string{"file1"},
+			},
+			ScheduledAt: 1000,
+		}
+		s.SetProps(StringInterface{"key": "value"})
+		return s
+	}
+
+	t.Run("same content at another time", func(t *testing.T) {
+		other := newScheduledPost()
+		other.ScheduledAt = 2000
+		other.PublishAsBotId = NewId()
+		assert.True(t, newScheduledPost().HasSameContent(other))
+	})
+
+	t.Run("empty and missing props", func(t *testing.T) {
+		s := &ScheduledPost{Draft: Draft{Message: "message"}}
+		other := &ScheduledPost{Draft: Draft{Message: "message", FileIds: []string{}}}
+		other.SetProps(StringInterface{})
+		assert.True(t, s.HasSameContent(other))
+	})
+
+	t.Run("edited message", func(t *testing.T) {
+		other := newScheduledPost()
+		other.Message = "this is an edited announcement"
+		assert.False(t, newScheduledPost().HasSameContent(other))
+	})
+
+	t.Run("edited files", func(t *testing.T) {
+		other := newScheduledPost()
+		other.FileIds = []string{"file1", "file2"}
+		assert.False(t, newScheduledPost().HasSameContent(other))
+	})
+
+	t.Run("edited props", func(t *testing.T) {
+		other := newScheduledPost()
+		other.SetProps(StringInterface{"key": "other value"})
+		assert.False(t, newScheduledPost().HasSameContent(other))
+	})
+
+	t.Run("edited priority", func(t *testing.T) {
+		other := newScheduledPost()
+		other.Metadata = &PostMetadata{Priority: &PostPriority{Priority: NewPointer(PostPriorityUrgent)}}
+		assert.False(t, newScheduledPost().HasSameContent(other))
+	})
+}
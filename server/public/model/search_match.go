@@ -0,0 +1,312 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var searchMatchTermsRegexp = regexp.MustCompile(`"[^"]*"|\S+`)
+var searchMatchLinkRegexp = regexp.MustCompile(`(?i)https?://`)
+
+// SearchTextAnalyzer splits a text into the words a search engine indexes it
+// as, such as lowercase stemmed words without the stop words.
+type SearchTextAnalyzer func(text string) []string
+
+// MatchesPost reports whether the search finds the post, for the posts that
+// aren't searchable yet. The channels and users of the filters have to be ids.
+//
+// The words of the message and of the terms are split by the analyzer of the
+// search engine, to match the same words it would. Without one, words are
+// matched as they are written, like the MySQL full-text search does: unlike
+// the stemming of the other engines, "upgrade" doesn't match "upgraded", and
+// the terms with Chinese, Japanese or Korean characters are matched as parts
+// of the words.
+func (p *SearchParams) MatchesPost(post *Post, analyzer SearchTextAnalyzer) bool {
+	return p.MatchesPostWithRegexps(post, analyzer, nil)
+}
+
+// MatchesPostWithRegexps is like MatchesPost, with the regular expressions of
+// the query compiled by CompileRegexps, when matching many posts.
+func (p *SearchParams) MatchesPostWithRegexps(post *Post, analyzer SearchTextAnalyzer, regexps SearchRegexps) bool {
+	if !p.matchesPostFilters(post) {
+		return false
+	}
+
+	message := newSearchMatchText(post.Message, analyzer)
+	if p.Query != nil {
+		return message.matchesQuery(p.Query, regexps)
+	}
+
+	if p.IsHashtag {
+		hashtags := newSearchMatchText(post.Hashtags, nil)
+		return hashtags.matchesTerms(p.Terms, p.OrTerms, true) && !hashtags.matchesTerms(p.ExcludedTerms, true, false)
+	}
+	return message.matchesTerms(p.Terms, p.OrTerms, true) && !message.matchesTerms(p.ExcludedTerms, true, false)
+}
+
+// SearchRegexps are the compiled regular expressions of a search query, by
+// their values. The expressions that don't compile are nil.
+type SearchRegexps map[string]*regexp.Regexp
+
+// CompileRegexps compiles the regular expressions of the query, to match them
+// against many posts with MatchesPostWithRegexps.
+func (p *SearchParams) CompileRegexps() SearchRegexps {
+	regexps := SearchRegexps{}
+	if p.Query != nil {
+		regexps.add(p.Query)
+	}
+	return regexps
+}
+
+func (r SearchRegexps) add(query *SearchQuery) {
+	if query.Type == SearchQueryTypeRegexp {
+		if _, ok := r[query.Value]; !ok {
+			r[query.Value] = compileSearchRegexp(query.Value)
+		}
+	}
+	for _, child := range query.Children {
+		r.add(child)
+	}
+}
+
+// compileSearchRegexp compiles the regular expression of a query to match
+// whole words, or returns nil when it isn't valid.
+func compileSearchRegexp(value string) *regexp.Regexp {
+	re, err := regexp.Compile(`(?i)\b(?:` + value + `)\b`)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+func (p *SearchParams) matchesPostFilters(post *Post) bool {
+	if len(p.InChannels) > 0 && !slices.Contains(p.InChannels, post.ChannelId) {
+		return false
+	}
+	if slices.Contains(p.ExcludedChannels, post.ChannelId) {
+		return false
+	}
+	if len(p.FromUsers) > 0 && !slices.Contains(p.FromUsers, post.UserId) {
+		return false
+	}
+	if slices.Contains(p.ExcludedUsers, post.UserId) {
+		return false
+	}
+
+	if p.OnDate != "" {
+		start, end := p.GetOnDateMillis()
+		if post.CreateAt < start || post.CreateAt > end {
+			return false
+		}
+	}
+	if p.ExcludedDate != "" {
+		start, end := p.GetExcludedDateMillis()
+		if post.CreateAt >= start && post.CreateAt <= end {
+			return false
+		}
+	}
+	if p.AfterDate != "" && post.CreateAt < p.GetAfterDateMillis() {
+		return false
+	}
+	if p.BeforeDate != "" && post.CreateAt > p.GetBeforeDateMillis() {
+		return false
+	}
+	if p.ExcludedAfterDate != "" && post.CreateAt >= p.GetExcludedAfterDateMillis() {
+		return false
+	}
+	if p.ExcludedBeforeDate != "" && post.CreateAt <= p.GetExcludedBeforeDateMillis() {
+		return false
+	}
+
+	for _, value := range append(slices.Clone(p.Has), p.Is...) {
+		if !postHasSearchProperty(post, value) {
+			return false
+		}
+	}
+	for _, value := range append(slices.Clone(p.ExcludedHas), p.ExcludedIs...) {
+		if postHasSearchProperty(post, value) {
+			return false
+		}
+	}
+
+	var reactions []string
+	priority := SearchPriorityStandard
+	if post.Metadata != nil {
+		for _, reaction := range post.Metadata.Reactions {
+			if reaction.DeleteAt == 0 {
+				reactions = append(reactions, reaction.EmojiName)
+			}
+		}
+		if postPriority := post.Metadata.Priority; postPriority != nil && postPriority.Priority != nil && *postPriority.Priority != "" {
+			priority = *postPriority.Priority
+		}
+	}
+	for _, emojiName := range p.Reactions {
+		if !slices.Contains(reactions, emojiName) {
+			return false
+		}
+	}
+	for _, emojiName := range p.ExcludedReactions {
+		if slices.Contains(reactions, emojiName) {
+			return false
+		}
+	}
+	if len(p.Priorities) > 0 && !slices.Contains(p.Priorities, priority) {
+		return false
+	}
+	if slices.Contains(p.ExcludedPriorities, priority) {
+		return false
+	}
+
+	return true
+}
+
+func postHasSearchProperty(post *Post, value string) bool {
+	switch value {
+	case SearchHasFile:
+		return len(post.FileIds) > 0
+	case SearchHasLink:
+		return searchMatchLinkRegexp.MatchString(post.Message)
+	case SearchIsPinned:
+		return post.IsPinned
+	case SearchIsThreadRoot:
+		return post.RootId == "" && post.ReplyCount > 0
+	}
+	return false
+}
+
+// searchMatchText is a text prepared for matching search terms.
+type searchMatchText struct {
+	text     string
+	words    []string
+	analyzer SearchTextAnalyzer
+}
+
+func newSearchMatchText(text string, analyzer SearchTextAnalyzer) *searchMatchText {
+	text = strings.ToLower(text)
+	if analyzer != nil {
+		return &searchMatchText{text: text, words: analyzer(text), analyzer: analyzer}
+	}
+	return &searchMatchText{text: text, words: splitSearchMatchWords(text, false)}
+}
+
+// splitSearchMatchWords splits a text into words, keeping the wildcards of the
+// search terms when asked to.
+func splitSearchMatchWords(text string, keepWildcards bool) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && (!keepWildcards || r != '*')
+	})
+}
+
+func isCJKSearchTerm(term string) bool {
+	for _, r := range term {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTerms reports whether all the terms are found, or any of them when
+// anyTerm is set. Without terms, it returns whether they are optional.
+func (t *searchMatchText) matchesTerms(terms string, anyTerm, optional bool) bool {
+	found := searchMatchTermsRegexp.FindAllString(strings.ToLower(terms), -1)
+	if len(found) == 0 {
+		return optional
+	}
+	for _, term := range found {
+		var matched bool
+		if strings.HasPrefix(term, `"`) {
+			matched = t.matchesPhrase(strings.Trim(term, `"`))
+		} else {
+			matched = t.matchesTerm(term)
+		}
+		if matched == anyTerm {
+			return anyTerm
+		}
+	}
+	return !anyTerm
+}
+
+func (t *searchMatchText) matchesTerm(term string) bool {
+	term = strings.ToLower(term)
+	if t.analyzer != nil && !strings.HasSuffix(term, "*") {
+		return t.matchesWords(t.analyzer(term))
+	}
+	if isCJKSearchTerm(term) {
+		return strings.Contains(t.text, strings.TrimSuffix(term, "*"))
+	}
+
+	words := splitSearchMatchWords(term, true)
+	if len(words) != 1 {
+		return t.matchesPhrase(term)
+	}
+	if prefix, ok := strings.CutSuffix(words[0], "*"); ok {
+		// The prefixes aren't analyzed, as the search engines don't either
+		return slices.ContainsFunc(t.words, func(word string) bool {
+			return strings.HasPrefix(word, prefix)
+		})
+	}
+	return slices.Contains(t.words, words[0])
+}
+
+func (t *searchMatchText) matchesPhrase(phrase string) bool {
+	phrase = strings.ToLower(phrase)
+	if t.analyzer != nil {
+		return t.matchesWords(t.analyzer(phrase))
+	}
+	if isCJKSearchTerm(phrase) {
+		return strings.Contains(t.text, phrase)
+	}
+
+	return t.matchesWords(splitSearchMatchWords(phrase, false))
+}
+
+// matchesWords reports whether the text has the words next to each other. A
+// term without words, such as a stop word, matches nothing.
+func (t *searchMatchText) matchesWords(words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for i := 0; i+len(words) <= len(t.words); i++ {
+		if slices.Equal(t.words[i:i+len(words)], words) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *searchMatchText) matchesQuery(query *SearchQuery, regexps SearchRegexps) bool {
+	switch query.Type {
+	case SearchQueryTypeAnd:
+		for _, child := range query.Children {
+			if !t.matchesQuery(child, regexps) {
+				return false
+			}
+		}
+		return true
+	case SearchQueryTypeOr:
+		for _, child := range query.Children {
+			if t.matchesQuery(child, regexps) {
+				return true
+			}
+		}
+		return false
+	case SearchQueryTypeNot:
+		return !t.matchesQuery(query.Children[0], regexps)
+	case SearchQueryTypePhrase:
+		return t.matchesPhrase(query.Value)
+	case SearchQueryTypeRegexp:
+		re, ok := regexps[query.Value]
+		if !ok {
+			re = compileSearchRegexp(query.Value)
+		}
+		return re != nil && re.MatchString(t.text)
+	}
+	return t.matchesTerm(query.Value)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchParamsMatchesPost(t *testing.T) {
+	channelID := NewId()
+	userID := NewId()
+	post := &Post{
+		Id:        NewId(),
+		ChannelId: channelID,
+		UserId:    userID,
+		CreateAt:  1704067200000, // 2024-01-01
+		Message:   "The **deployment** of release 1,234 failed, see https://example.com/logs 今天我們討論搜尋功能",
+		Hashtags:  "#release #deploy",
+		FileIds:   StringArray{NewId()},
+		Metadata: &PostMetadata{
+			Reactions: []*Reaction{{EmojiName: "eyes"}},
+			Priority:  &PostPriority{Priority: NewPointer(PostPriorityUrgent)},
+		},
+	}
+
+	for terms, expected := range map[string]bool{
+		"deployment":                        true,
+		"Deployment failed":                 true,
+		"deployment missing":                false,
+		"deploy*":                           true,
+		`"release 1,234 failed"`:            true,
+		`"failed release"`:                  false,
+		"1,234":                             true,
+		"搜尋":                                true,
+		"deployment -failed":                false,
+		"deployment -succeeded":             true,
+		"#release":                          true,
+		"#other":                            false,
+		"deployment has:file has:link":      true,
+		"deployment is:pinned":              false,
+		"deployment -is:thread-root":        true,
+		"deployment reacted:eyes":           true,
+		"deployment -reacted:eyes":          false,
+		"deployment priority:important":     false,
+		"deployment -priority:standard":     true,
+		"deployment after:2023-12-31":       true,
+		"deployment before:2023-12-31":      false,
+		"deployment on:2024-01-01":          true,
+		"(succeeded OR failed) -/warn.*/":   true,
+		`(succeeded OR failed) -"1,234"`:    false,
+		`/fail(ed|ure)/`:                    true,
+		`(missing OR deploy*) has:file`:     true,
+		`deployment -(failed OR succeeded)`: false,
+	} {
+		t.Run(terms, func(t *testing.T) {
+			paramsList, appErr := ParseSearchParamsWithQuery(terms, 0)
+			require.Nil(t, appErr)
+
+			matched := false
+			for _, params := range paramsList {
+				matched = matched || params.MatchesPost(post, nil)
+			}
+			assert.Equal(t, expected, matched)
+		})
+	}
+
+	t.Run("or search", func(t *testing.T) {
+		params := ParseSearchParams("missing failed", 0)[0]
+		assert.False(t, params.MatchesPost(post, nil))
+		params.OrTerms = true
+		assert.True(t, params.MatchesPost(post, nil))
+	})
+
+	t.Run("channel and user filters", func(t *testing.T) {
+		assert.True(t, (&SearchParams{Terms: "failed", InChannels: []string{channelID}, FromUsers: []string{userID}}).MatchesPost(post, nil))
+		assert.False(t, (&SearchParams{Terms: "failed", InChannels: []string{NewId()}}).MatchesPost(post, nil))
+		assert.False(t, (&SearchParams{Terms: "failed", ExcludedUsers: []string{userID}}).MatchesPost(post, nil))
+	})
+
+	t.Run("compiled regular expressions", func(t *testing.T) {
+		paramsList, appErr := ParseSearchParamsWithQuery(`/fail(ed|ure)/ -/warn.*/ /fail(ed|ure)/`, 0)
+		require.Nil(t, appErr)
+		params := paramsList[0]
+
+		regexps := params.CompileRegexps()
+		require.Len(t, regexps, 2)
+		assert.True(t, params.MatchesPostWithRegexps(post, nil, regexps))
+
+		// The compiled expressions are the ones matched
+		regexps["fail(ed|ure)"] = regexp.MustCompile("missing")
+		assert.False(t, params.MatchesPostWithRegexps(post, nil, regexps))
+	})
+
+	t.Run("invalid regular expression", func(t *testing.T) {
+		params := &SearchParams{Query: &SearchQuery{Type: SearchQueryTypeRegexp, Value: "fail("}}
+		regexps := params.CompileRegexps()
+		require.Contains(t, regexps, "fail(")
+		assert.Nil(t, regexps["fail("])
+		assert.False(t, params.MatchesPostWithRegexps(post, nil, regexps))
+		assert.False(t, params.MatchesPost(post, nil))
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		// Strips the plural of the words, and drops "the" as a stop word
+		analyzer := func(text string) []string {
+			var words []string
+			for _, word := range strings.Fields(strings.ToLower(text)) {
+				if word != "the" {
+					words = append(words, strings.TrimSuffix(word, "s"))
+				}
+			}
+			return words
+		}
+		post := &Post{Message: "the deployments failed"}
+
+		assert.False(t, ParseSearchParams("deployment", 0)[0].MatchesPost(post, nil))
+		assert.True(t, ParseSearchParams("deployment", 0)[0].MatchesPost(post, analyzer))
+		assert.True(t, ParseSearchParams(`"deployment failed"`, 0)[0].MatchesPost(post, analyzer))
+		assert.True(t, ParseSearchParams("deploy*", 0)[0].MatchesPost(post, analyzer))
+		assert.False(t, ParseSearchParams("the", 0)[0].MatchesPost(post, analyzer))
+	})
+}
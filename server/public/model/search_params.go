@@ -6,10 +6,25 @@ package model
 import (
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
 
+const (
+	SearchHasFile          = "file"
+	SearchHasLink          = "link"
+	SearchIsPinned         = "pinned"
+	SearchIsThreadRoot     = "thread-root"
+	SearchPriorityStandard = "standard"
+)
+
+var (
+	SearchHasValues      = []string{SearchHasFile, SearchHasLink}
+	SearchIsValues       = []string{SearchIsPinned, SearchIsThreadRoot}
+	SearchPriorityValues = []string{PostPriorityUrgent, PostPriorityImportant, SearchPriorityStandard}
+)
+
 var searchTermPuncStart = regexp.MustCompile(`^[^\pL\d\s#"]+`)
 var searchTermPuncEnd = regexp.MustCompile(`[^\pL\p{M}\d\s*"]+$`)
 
@@ -35,6 +50,27 @@ type SearchParams struct {
 	// True if this search doesn't originate from a "current user".
 	SearchWithoutUserId bool   `json:"search_without_user_id,omitempty"`
 	Modifier            string `json:"modifier"`
+	// Query is the syntax tree of the terms of an advanced search, replacing
+	// Terms and ExcludedTerms when set.
+	Query *SearchQuery `json:"query,omitempty"`
+	// The properties of the posts, which all have to match.
+	Has                []string `json:"has,omitempty"`
+	ExcludedHas        []string `json:"excluded_has,omitempty"`
+	Is                 []string `json:"is,omitempty"`
+	ExcludedIs         []string `json:"excluded_is,omitempty"`
+	Reactions          []string `json:"reactions,omitempty"`
+	ExcludedReactions  []string `json:"excluded_reactions,omitempty"`
+	Priorities         []string `json:"priorities,omitempty"`
+	ExcludedPriorities []string `json:"excluded_priorities,omitempty"`
+}
+
+// HasPostPropertyFilters reports whether the search is narrowed by
+// properties of the posts.
+func (p *SearchParams) HasPostPropertyFilters() bool {
+	return len(p.Has) > 0 || len(p.ExcludedHas) > 0 ||
+		len(p.Is) > 0 || len(p.ExcludedIs) > 0 ||
+		len(p.Reactions) > 0 || len(p.ExcludedReactions) > 0 ||
+		len(p.Priorities) > 0 || len(p.ExcludedPriorities) > 0
 }
 
 // Returns the epoch timestamp of the start of the day specified by SearchParams.AfterDate
@@ -109,7 +145,11 @@ func (p *SearchParams) GetExcludedDateMillis() (int64, int64) {
 	return GetStartOfDayMillis(date, p.TimeZoneOffset), GetEndOfDayMillis(date, p.TimeZoneOffset)
 }
 
-var searchFlags = [...]string{"from", "channel", "in", "before", "after", "on", "ext"}
+var searchFlags = [...]string{"from", "channel", "in", "before", "after", "on", "ext", "has", "is", "reacted", "priority"}
+
+// postPropertySearchFlags are the flags narrowing the search by properties of
+// the posts.
+var postPropertySearchFlags = []string{"has", "is", "reacted", "priority"}
 
 type flag struct {
 	name    string
@@ -270,9 +310,12 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 	excludedDate := ""
 	excludedExtensions := []string{}
 	extensions := []string{}
+	propertyFlags := []flag{}
 
 	for _, flag := range flags {
-		if flag.name == "in" || flag.name == "channel" {
+		if slices.Contains(postPropertySearchFlags, flag.name) {
+			propertyFlags = append(propertyFlags, flag)
+		} else if flag.name == "in" || flag.name == "channel" {
 			if flag.exclude {
 				excludedChannels = append(excludedChannels, flag.value)
 			} else {
@@ -363,7 +406,8 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 			len(extensions) != 0 || len(excludedExtensions) != 0 ||
 			afterDate != "" || excludedAfterDate != "" ||
 			beforeDate != "" || excludedBeforeDate != "" ||
-			onDate != "" || excludedDate != "") {
+			onDate != "" || excludedDate != "" ||
+			len(propertyFlags) != 0) {
 		paramsList = append(paramsList, &SearchParams{
 			Terms:              "",
 			ExcludedTerms:      "",
@@ -384,6 +428,12 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 		})
 	}
 
+	for _, params := range paramsList {
+		for _, flag := range propertyFlags {
+			params.setFlag(flag)
+		}
+	}
+
 	return paramsList
 }
 
@@ -393,6 +443,33 @@ func IsSearchParamsListValid(paramsList []*SearchParams) *AppError {
 		if params.IncludeDeletedChannels != paramsList[0].IncludeDeletedChannels {
 			return NewAppError("IsSearchParamsListValid", "model.search_params_list.is_valid.include_deleted_channels.app_error", nil, "", http.StatusInternalServerError)
 		}
+		if appErr := params.isPostPropertyFiltersValid(); appErr != nil {
+			return appErr
+		}
+	}
+	return nil
+}
+
+func (p *SearchParams) isPostPropertyFiltersValid() *AppError {
+	for _, filter := range []struct {
+		name    string
+		values  []string
+		allowed []string
+	}{
+		{"has", append(slices.Clone(p.Has), p.ExcludedHas...), SearchHasValues},
+		{"is", append(slices.Clone(p.Is), p.ExcludedIs...), SearchIsValues},
+		{"priority", append(slices.Clone(p.Priorities), p.ExcludedPriorities...), SearchPriorityValues},
+	} {
+		for _, value := range filter.values {
+			if !slices.Contains(filter.allowed, value) {
+				return NewAppError("IsSearchParamsListValid", "model.search_params_list.is_valid.filter_value.app_error", map[string]any{"Filter": filter.name, "Value": value, "Allowed": strings.Join(filter.allowed, ", ")}, "", http.StatusBadRequest)
+			}
+		}
+	}
+	for _, emojiName := range append(slices.Clone(p.Reactions), p.ExcludedReactions...) {
+		if emojiName == "" || len(emojiName) > EmojiNameMaxLength || !IsValidAlphaNumHyphenUnderscorePlus(emojiName) {
+			return NewAppError("IsSearchParamsListValid", "model.search_params_list.is_valid.filter_value.app_error", map[string]any{"Filter": "reacted", "Value": emojiName, "Allowed": "emoji names"}, "", http.StatusBadRequest)
+		}
 	}
 	return nil
 }
@@ -4,6 +4,7 @@
 package model
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -1840,4 +1841,57 @@ func TestIsSearchParamsListValid(t *testing.T) {
 
 	appErr = IsSearchParamsListValid([]*SearchParams{})
 	assert.Nil(t, appErr)
+
+	appErr = IsSearchParamsListValid([]*SearchParams{{Has: []string{"file"}, ExcludedIs: []string{"thread-root"}, Reactions: []string{"+1"}, Priorities: []string{"urgent"}}})
+	assert.Nil(t, appErr)
+
+	for _, params := range []*SearchParams{
+		{Has: []string{"video"}},
+		{ExcludedIs: []string{"archived"}},
+		{Priorities: []string{"high"}},
+		{ExcludedReactions: []string{"not an emoji"}},
+	} {
+		appErr = IsSearchParamsListValid([]*SearchParams{params})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.search_params_list.is_valid.filter_value.app_error", appErr.Id)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	}
+}
+
+func TestParseSearchParamsPostPropertyFilters(t *testing.T) {
+	t.Run("filters alone", func(t *testing.T) {
+		paramsList := ParseSearchParams("has:file -is:pinned reacted::Tada: priority:URGENT", 0)
+		require.Len(t, paramsList, 1)
+		assert.Equal(t, "", paramsList[0].Terms)
+		assert.Equal(t, []string{SearchHasFile}, paramsList[0].Has)
+		assert.Equal(t, []string{SearchIsPinned}, paramsList[0].ExcludedIs)
+		assert.Equal(t, []string{"tada"}, paramsList[0].Reactions)
+		assert.Equal(t, []string{PostPriorityUrgent}, paramsList[0].Priorities)
+		assert.True(t, paramsList[0].HasPostPropertyFilters())
+	})
+
+	t.Run("filters apply to every hashtag and plain search", func(t *testing.T) {
+		paramsList := ParseSearchParams("release #deploy has:link is:thread-root -reacted:eyes", 0)
+		require.Len(t, paramsList, 2)
+		for _, params := range paramsList {
+			assert.Equal(t, []string{SearchHasLink}, params.Has)
+			assert.Equal(t, []string{SearchIsThreadRoot}, params.Is)
+			assert.Equal(t, []string{"eyes"}, params.ExcludedReactions)
+		}
+	})
+
+	t.Run("advanced searches", func(t *testing.T) {
+		paramsList, appErr := ParseSearchParamsWithQuery("(error OR failure) has:file -priority:standard", 0)
+		require.Nil(t, appErr)
+		require.Len(t, paramsList, 1)
+		assert.NotNil(t, paramsList[0].Query)
+		assert.Equal(t, []string{SearchHasFile}, paramsList[0].Has)
+		assert.Equal(t, []string{SearchPriorityStandard}, paramsList[0].ExcludedPriorities)
+	})
+
+	t.Run("no filters", func(t *testing.T) {
+		paramsList := ParseSearchParams("words in:town-square", 0)
+		require.Len(t, paramsList, 1)
+		assert.False(t, paramsList[0].HasPostPropertyFilters())
+	})
 }
@@ -0,0 +1,526 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+const (
+	SearchQueryMaxDepth         = 5
+	SearchQueryMaxNodes         = 50
+	SearchQueryMaxRegexpLength  = 100
+	searchQueryOrOperator       = "OR"
+	searchQueryAndOperator      = "AND"
+	searchQueryExclusionPrefix  = '-'
+	searchQueryRegexpDelimiter  = '/'
+	searchQueryPhraseDelimiter  = '"'
+	searchQueryGroupStart       = '('
+	searchQueryGroupEnd         = ')'
+	searchQueryFlagValueDivider = ":"
+)
+
+type SearchQueryType string
+
+const (
+	// SearchQueryTypeTerm matches a word. A trailing asterisk matches the
+	// words starting with the rest of the value.
+	SearchQueryTypeTerm SearchQueryType = "term"
+	// SearchQueryTypePhrase matches consecutive words.
+	SearchQueryTypePhrase SearchQueryType = "phrase"
+	// SearchQueryTypeRegexp matches the words the whole regular expression
+	// matches, ignoring case.
+	SearchQueryTypeRegexp SearchQueryType = "regexp"
+	SearchQueryTypeAnd    SearchQueryType = "and"
+	SearchQueryTypeOr     SearchQueryType = "or"
+	SearchQueryTypeNot    SearchQueryType = "not"
+)
+
+var (
+	advancedSearchRegexp = regexp.MustCompile(`^-?/.+/$`)
+	// Flags would override the ones the search engine matches words with
+	searchQueryRegexpFlags = regexp.MustCompile(`\(\?[^:]`)
+)
+
+// SearchQuery is a node of the syntax tree of a search query. Terms, phrases
+// and regular expressions have a value, the other nodes have children.
+type SearchQuery struct {
+	Type     SearchQueryType `json:"type"`
+	Value    string          `json:"value,omitempty"`
+	Children []*SearchQuery  `json:"children,omitempty"`
+}
+
+// ContainsType reports whether the query or any of its descendants is of the
+// given type.
+func (q *SearchQuery) ContainsType(queryType SearchQueryType) bool {
+	if q.Type == queryType {
+		return true
+	}
+	for _, child := range q.Children {
+		if child.ContainsType(queryType) {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludedTerms returns the terms and quoted phrases the query looks for,
+// leaving out the excluded ones and the regular expressions.
+func (q *SearchQuery) IncludedTerms() []string {
+	switch q.Type {
+	case SearchQueryTypeTerm:
+		return []string{q.Value}
+	case SearchQueryTypePhrase:
+		return []string{`"` + q.Value + `"`}
+	case SearchQueryTypeAnd, SearchQueryTypeOr:
+		terms := []string{}
+		for _, child := range q.Children {
+			terms = append(terms, child.IncludedTerms()...)
+		}
+		return terms
+	}
+	return []string{}
+}
+
+type searchQueryTokenType int
+
+const (
+	searchQueryTokenWord searchQueryTokenType = iota
+	searchQueryTokenPhrase
+	searchQueryTokenRegexp
+	searchQueryTokenFlag
+	searchQueryTokenGroupStart
+	searchQueryTokenGroupEnd
+	searchQueryTokenOr
+)
+
+type searchQueryToken struct {
+	tokenType searchQueryTokenType
+	value     string
+	flagName  string
+	exclude   bool
+}
+
+// isAdvancedSearchQuery reports whether the text uses the syntax only the
+// search query parser understands: OR, parentheses or regular expressions.
+func isAdvancedSearchQuery(text string) bool {
+	for _, word := range splitWords(text) {
+		if word == searchQueryOrOperator ||
+			strings.HasPrefix(word, string(searchQueryGroupStart)) ||
+			strings.HasPrefix(word, string([]rune{searchQueryExclusionPrefix, searchQueryGroupStart})) ||
+			advancedSearchRegexp.MatchString(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenizeSearchQuery(text string) ([]searchQueryToken, *AppError) {
+	runes := []rune(text)
+	tokens := []searchQueryToken{}
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		exclude := false
+		if runes[i] == searchQueryExclusionPrefix && i+1 < len(runes) &&
+			(runes[i+1] == searchQueryGroupStart || runes[i+1] == searchQueryPhraseDelimiter || runes[i+1] == searchQueryRegexpDelimiter) {
+			exclude = true
+			i++
+		}
+
+		switch runes[i] {
+		case searchQueryGroupStart:
+			tokens = append(tokens, searchQueryToken{tokenType: searchQueryTokenGroupStart, exclude: exclude})
+			i++
+			continue
+		case searchQueryGroupEnd:
+			tokens = append(tokens, searchQueryToken{tokenType: searchQueryTokenGroupEnd})
+			i++
+			continue
+		case searchQueryPhraseDelimiter:
+			end := indexRune(runes, i+1, searchQueryPhraseDelimiter)
+			if end == -1 {
+				return nil, NewAppError("ParseSearchQuery", "model.search_query.unterminated_phrase.app_error", nil, "", http.StatusBadRequest)
+			}
+			phrase := strings.Join(strings.Fields(string(runes[i+1:end])), " ")
+			if phrase != "" {
+				tokens = append(tokens, searchQueryToken{tokenType: searchQueryTokenPhrase, value: phrase, exclude: exclude})
+			}
+			i = end + 1
+			continue
+		case searchQueryRegexpDelimiter:
+			// A slash without a closing one starts a word, such as a path
+			if end := indexRune(runes, i+1, searchQueryRegexpDelimiter); end > i+1 {
+				tokens = append(tokens, searchQueryToken{tokenType: searchQueryTokenRegexp, value: string(runes[i+1 : end]), exclude: exclude})
+				i = end + 1
+				continue
+			}
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != searchQueryGroupStart && runes[i] != searchQueryGroupEnd {
+			i++
+		}
+		word := string(runes[start:i])
+		if exclude {
+			word = string(searchQueryExclusionPrefix) + word
+		}
+
+		switch {
+		case word == searchQueryOrOperator:
+			tokens = append(tokens, searchQueryToken{tokenType: searchQueryTokenOr})
+		case word == searchQueryAndOperator:
+			// Terms are matched together by default
+		default:
+			if token, ok := parseSearchQueryFlag(word); ok {
+				tokens = append(tokens, token)
+				continue
+			}
+
+			token := searchQueryToken{tokenType: searchQueryTokenWord, exclude: strings.HasPrefix(word, string(searchQueryExclusionPrefix))}
+			word = searchTermPuncStart.ReplaceAllString(word, "")
+			word = searchTermPuncEnd.ReplaceAllString(word, "")
+			token.value = hashtagStart.ReplaceAllString(word, "#")
+			if token.value != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	// The value of a flag can follow it, as in "in: town-square"
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].tokenType == searchQueryTokenFlag && tokens[i].value == "" {
+			if i+1 == len(tokens) || tokens[i+1].tokenType != searchQueryTokenWord {
+				return nil, NewAppError("ParseSearchQuery", "model.search_query.missing_filter_value.app_error", map[string]any{"Filter": tokens[i].flagName}, "", http.StatusBadRequest)
+			}
+			tokens[i].value = tokens[i+1].value
+			tokens = append(tokens[:i+1], tokens[i+2:]...)
+		}
+	}
+
+	return tokens, nil
+}
+
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseSearchQueryFlag(word string) (searchQueryToken, bool) {
+	colon := strings.Index(word, searchQueryFlagValueDivider)
+	if colon == -1 {
+		return searchQueryToken{}, false
+	}
+
+	name := word[:colon]
+	exclude := strings.HasPrefix(name, string(searchQueryExclusionPrefix))
+	if exclude {
+		name = name[1:]
+	}
+	for _, searchFlag := range searchFlags {
+		if strings.EqualFold(name, searchFlag) {
+			return searchQueryToken{tokenType: searchQueryTokenFlag, flagName: searchFlag, value: word[colon+1:], exclude: exclude}, true
+		}
+	}
+	return searchQueryToken{}, false
+}
+
+type searchQueryParser struct {
+	tokens []searchQueryToken
+	pos    int
+	depth  int
+	nodes  int
+	flags  []flag
+}
+
+// parseSearchQuery parses the text of an advanced search into a syntax tree
+// and the search flags applying to all of it.
+func parseSearchQuery(text string) (*SearchQuery, []flag, *AppError) {
+	tokens, appErr := tokenizeSearchQuery(text)
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+
+	p := &searchQueryParser{tokens: tokens}
+	query, appErr := p.parseAnd()
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+	if p.pos < len(p.tokens) {
+		// Only a closing parenthesis stops parsing before the end
+		return nil, nil, NewAppError("ParseSearchQuery", "model.search_query.unbalanced_parentheses.app_error", nil, "", http.StatusBadRequest)
+	}
+	if query == nil {
+		return nil, nil, NewAppError("ParseSearchQuery", "model.search_query.empty_expression.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	return query, p.flags, nil
+}
+
+func (p *searchQueryParser) peek() *searchQueryToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *searchQueryParser) newNode(queryType SearchQueryType, value string, children ...*SearchQuery) (*SearchQuery, *AppError) {
+	p.nodes++
+	if p.nodes > SearchQueryMaxNodes {
+		return nil, NewAppError("ParseSearchQuery", "model.search_query.too_complex.app_error", map[string]any{"MaxTerms": SearchQueryMaxNodes, "MaxDepth": SearchQueryMaxDepth}, "", http.StatusBadRequest)
+	}
+	return &SearchQuery{Type: queryType, Value: value, Children: children}, nil
+}
+
+// parseAnd parses the expressions up to the next closing parenthesis, which
+// all have to match. It returns nil when there are only flags.
+func (p *searchQueryParser) parseAnd() (*SearchQuery, *AppError) {
+	var children []*SearchQuery
+	for token := p.peek(); token != nil && token.tokenType != searchQueryTokenGroupEnd; token = p.peek() {
+		if token.tokenType == searchQueryTokenFlag {
+			if p.depth > 0 {
+				return nil, NewAppError("ParseSearchQuery", "model.search_query.filter_in_group.app_error", map[string]any{"Filter": token.flagName}, "", http.StatusBadRequest)
+			}
+			p.flags = append(p.flags, flag{token.flagName, token.value, token.exclude})
+			p.pos++
+			continue
+		}
+
+		child, appErr := p.parseOr()
+		if appErr != nil {
+			return nil, appErr
+		}
+		children = append(children, child)
+	}
+
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return p.newNode(SearchQueryTypeAnd, "", children...)
+}
+
+// parseOr parses an expression and the ones it is joined to with OR, which
+// binds tighter than the implicit AND between expressions.
+func (p *searchQueryParser) parseOr() (*SearchQuery, *AppError) {
+	var alternatives []*SearchQuery
+	for {
+		token := p.peek()
+		switch {
+		case token == nil || token.tokenType == searchQueryTokenOr || token.tokenType == searchQueryTokenGroupEnd:
+			return nil, NewAppError("ParseSearchQuery", "model.search_query.empty_expression.app_error", nil, "", http.StatusBadRequest)
+		case token.tokenType == searchQueryTokenFlag:
+			return nil, NewAppError("ParseSearchQuery", "model.search_query.filter_in_group.app_error", map[string]any{"Filter": token.flagName}, "", http.StatusBadRequest)
+		}
+
+		alternative, appErr := p.parseUnary()
+		if appErr != nil {
+			return nil, appErr
+		}
+		alternatives = append(alternatives, alternative)
+
+		if token := p.peek(); token == nil || token.tokenType != searchQueryTokenOr {
+			break
+		}
+		p.pos++
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return p.newNode(SearchQueryTypeOr, "", alternatives...)
+}
+
+func (p *searchQueryParser) parseUnary() (*SearchQuery, *AppError) {
+	token := p.tokens[p.pos]
+	p.pos++
+
+	var node *SearchQuery
+	var appErr *AppError
+	switch token.tokenType {
+	case searchQueryTokenWord:
+		node, appErr = p.newNode(SearchQueryTypeTerm, token.value)
+	case searchQueryTokenPhrase:
+		node, appErr = p.newNode(SearchQueryTypePhrase, token.value)
+	case searchQueryTokenRegexp:
+		if appErr = validateSearchQueryRegexp(token.value); appErr == nil {
+			node, appErr = p.newNode(SearchQueryTypeRegexp, token.value)
+		}
+	case searchQueryTokenGroupStart:
+		p.depth++
+		if p.depth > SearchQueryMaxDepth {
+			return nil, NewAppError("ParseSearchQuery", "model.search_query.too_complex.app_error", map[string]any{"MaxTerms": SearchQueryMaxNodes, "MaxDepth": SearchQueryMaxDepth}, "", http.StatusBadRequest)
+		}
+		node, appErr = p.parseAnd()
+		if appErr != nil {
+			return nil, appErr
+		}
+		if end := p.peek(); end == nil || end.tokenType != searchQueryTokenGroupEnd {
+			return nil, NewAppError("ParseSearchQuery", "model.search_query.unbalanced_parentheses.app_error", nil, "", http.StatusBadRequest)
+		}
+		p.pos++
+		p.depth--
+		if node == nil {
+			return nil, NewAppError("ParseSearchQuery", "model.search_query.empty_expression.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if token.exclude {
+		return p.newNode(SearchQueryTypeNot, "", node)
+	}
+	return node, nil
+}
+
+// validateSearchQueryRegexp checks the regular expression only uses the
+// syntax that applies to the single words the search engine matches.
+func validateSearchQueryRegexp(pattern string) *AppError {
+	if len(pattern) > SearchQueryMaxRegexpLength {
+		return NewAppError("ParseSearchQuery", "model.search_query.regexp_too_long.app_error", map[string]any{"Max": SearchQueryMaxRegexpLength}, "", http.StatusBadRequest)
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return NewAppError("ParseSearchQuery", "model.search_query.invalid_regexp.app_error", map[string]any{"Pattern": pattern}, "", http.StatusBadRequest).Wrap(err)
+	}
+	if searchQueryRegexpFlags.MatchString(pattern) || hasUnsupportedRegexpSyntax(parsed) {
+		return NewAppError("ParseSearchQuery", "model.search_query.unsupported_regexp.app_error", map[string]any{"Pattern": pattern}, "", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// hasUnsupportedRegexpSyntax reports whether the regular expression uses
+// anchors or word boundaries, which don't apply to the single words matched,
+// or lazy repetitions.
+func hasUnsupportedRegexpSyntax(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	}
+	if re.Flags&syntax.NonGreedy != 0 {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasUnsupportedRegexpSyntax(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSearchParamsWithQuery parses the text of a search like
+// ParseSearchParams. When the text uses OR, parentheses or regular
+// expressions, it returns a single SearchParams whose Query is the syntax
+// tree of the terms, or an error when the query can't be parsed.
+func ParseSearchParamsWithQuery(text string, timeZoneOffset int) ([]*SearchParams, *AppError) {
+	if !isAdvancedSearchQuery(text) {
+		return ParseSearchParams(text, timeZoneOffset), nil
+	}
+
+	query, flags, appErr := parseSearchQuery(text)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	params := &SearchParams{
+		// Terms are kept for what doesn't support the query
+		Terms:              strings.Join(query.IncludedTerms(), " "),
+		Query:              query,
+		InChannels:         []string{},
+		ExcludedChannels:   []string{},
+		FromUsers:          []string{},
+		ExcludedUsers:      []string{},
+		Extensions:         []string{},
+		ExcludedExtensions: []string{},
+		TimeZoneOffset:     timeZoneOffset,
+	}
+	for _, f := range flags {
+		params.setFlag(f)
+	}
+
+	return []*SearchParams{params}, nil
+}
+
+func (p *SearchParams) setFlag(f flag) {
+	switch f.name {
+	case "in", "channel":
+		if f.exclude {
+			p.ExcludedChannels = append(p.ExcludedChannels, f.value)
+		} else {
+			p.InChannels = append(p.InChannels, f.value)
+		}
+	case "from":
+		if f.exclude {
+			p.ExcludedUsers = append(p.ExcludedUsers, f.value)
+		} else {
+			p.FromUsers = append(p.FromUsers, f.value)
+		}
+	case "after":
+		if f.exclude {
+			p.ExcludedAfterDate = f.value
+		} else {
+			p.AfterDate = f.value
+		}
+	case "before":
+		if f.exclude {
+			p.ExcludedBeforeDate = f.value
+		} else {
+			p.BeforeDate = f.value
+		}
+	case "on":
+		if f.exclude {
+			p.ExcludedDate = f.value
+		} else {
+			p.OnDate = f.value
+		}
+	case "ext":
+		if f.exclude {
+			p.ExcludedExtensions = append(p.ExcludedExtensions, f.value)
+		} else {
+			p.Extensions = append(p.Extensions, f.value)
+		}
+	case "has":
+		if f.exclude {
+			p.ExcludedHas = append(p.ExcludedHas, strings.ToLower(f.value))
+		} else {
+			p.Has = append(p.Has, strings.ToLower(f.value))
+		}
+	case "is":
+		if f.exclude {
+			p.ExcludedIs = append(p.ExcludedIs, strings.ToLower(f.value))
+		} else {
+			p.Is = append(p.Is, strings.ToLower(f.value))
+		}
+	case "reacted":
+		// The emoji can be written as in messages, such as :tada:
+		emojiName := strings.ToLower(strings.Trim(f.value, ":"))
+		if f.exclude {
+			p.ExcludedReactions = append(p.ExcludedReactions, emojiName)
+		} else {
+			p.Reactions = append(p.Reactions, emojiName)
+		}
+	case "priority":
+		if f.exclude {
+			p.ExcludedPriorities = append(p.ExcludedPriorities, strings.ToLower(f.value))
+		} else {
+			p.Priorities = append(p.Priorities, strings.ToLower(f.value))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func term(value string) *SearchQuery {
+	return &SearchQuery{Type: SearchQueryTypeTerm, Value: value}
+}
+
+func TestParseSearchParamsWithQuery(t *testing.T) {
+	t.Run("simple searches keep being parsed as before", func(t *testing.T) {
+		for _, input := range []string{"", "words words", `-word "a phrase" in:town-square`, "#hashtag -#other", "smile :)", "/api/v4"} {
+			paramsList, appErr := ParseSearchParamsWithQuery(input, 0)
+			require.Nil(t, appErr)
+			assert.Equal(t, ParseSearchParams(input, 0), paramsList, input)
+		}
+	})
+
+	for _, testCase := range []struct {
+		Name   string
+		Input  string
+		Query  *SearchQuery
+		Terms  string
+		Params SearchParams
+	}{
+		{
+			Name:  "or with a filter and an excluded phrase",
+			Input: `error OR failure in:alerts -"known issue"`,
+			Query: &SearchQuery{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+				{Type: SearchQueryTypeOr, Children: []*SearchQuery{term("error"), term("failure")}},
+				{Type: SearchQueryTypeNot, Children: []*SearchQuery{{Type: SearchQueryTypePhrase, Value: "known issue"}}},
+			}},
+			Terms:  "error failure",
+			Params: SearchParams{InChannels: []string{"alerts"}},
+		},
+		{
+			Name:  "grouping",
+			Input: `(error -"known issue") OR failure from: alice`,
+			Query: &SearchQuery{Type: SearchQueryTypeOr, Children: []*SearchQuery{
+				{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+					term("error"),
+					{Type: SearchQueryTypeNot, Children: []*SearchQuery{{Type: SearchQueryTypePhrase, Value: "known issue"}}},
+				}},
+				term("failure"),
+			}},
+			Terms:  "error failure",
+			Params: SearchParams{FromUsers: []string{"alice"}},
+		},
+		{
+			Name:  "excluded group and explicit and",
+			Input: `deploy AND -(staging OR test*) after:2024-01-01`,
+			Query: &SearchQuery{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+				term("deploy"),
+				{Type: SearchQueryTypeNot, Children: []*SearchQuery{
+					{Type: SearchQueryTypeOr, Children: []*SearchQuery{term("staging"), term("test*")}},
+				}},
+			}},
+			Terms:  "deploy",
+			Params: SearchParams{AfterDate: "2024-01-01"},
+		},
+		{
+			Name:  "regular expression",
+			Input: `/err(or|no)/ -/warn.*/`,
+			Query: &SearchQuery{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+				{Type: SearchQueryTypeRegexp, Value: "err(or|no)"},
+				{Type: SearchQueryTypeNot, Children: []*SearchQuery{{Type: SearchQueryTypeRegexp, Value: "warn.*"}}},
+			}},
+		},
+		{
+			Name:  "punctuation and hashtags",
+			Input: `(#release, OR "v2") ##deploy`,
+			Query: &SearchQuery{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+				{Type: SearchQueryTypeOr, Children: []*SearchQuery{term("#release"), {Type: SearchQueryTypePhrase, Value: "v2"}}},
+				term("#deploy"),
+			}},
+			Terms: `#release "v2" #deploy`,
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			paramsList, appErr := ParseSearchParamsWithQuery(testCase.Input, 60)
+			require.Nil(t, appErr)
+			require.Len(t, paramsList, 1)
+
+			expected := testCase.Params
+			expected.Query = testCase.Query
+			expected.Terms = testCase.Terms
+			expected.TimeZoneOffset = 60
+			for _, list := range []*[]string{&expected.InChannels, &expected.ExcludedChannels, &expected.FromUsers, &expected.ExcludedUsers, &expected.Extensions, &expected.ExcludedExtensions} {
+				if *list == nil {
+					*list = []string{}
+				}
+			}
+			assert.Equal(t, &expected, paramsList[0])
+		})
+	}
+}
+
+func TestParseSearchParamsWithQueryErrors(t *testing.T) {
+	for input, errorID := range map[string]string{
+		"(error OR failure":                  "model.search_query.unbalanced_parentheses.app_error",
+		"(error OR failure))":                "model.search_query.unbalanced_parentheses.app_error",
+		`(error OR "failure)`:                "model.search_query.unterminated_phrase.app_error",
+		"error OR":                           "model.search_query.empty_expression.app_error",
+		"OR error":                           "model.search_query.empty_expression.app_error",
+		"() error":                           "model.search_query.empty_expression.app_error",
+		"(in:alerts error) failure":          "model.search_query.filter_in_group.app_error",
+		"error OR from:alice":                "model.search_query.filter_in_group.app_error",
+		"error OR OR failure":                "model.search_query.empty_expression.app_error",
+		"(error OR failure) in:":             "model.search_query.missing_filter_value.app_error",
+		"/err(or/":                           "model.search_query.invalid_regexp.app_error",
+		"/^error/":                           "model.search_query.unsupported_regexp.app_error",
+		`/err\b/`:                            "model.search_query.unsupported_regexp.app_error",
+		"/err.*?/":                           "model.search_query.unsupported_regexp.app_error",
+		"/(?i)error/":                        "model.search_query.unsupported_regexp.app_error",
+		"/" + strings.Repeat("a", 101) + "/": "model.search_query.regexp_too_long.app_error",
+		strings.Repeat("(", 6) + strings.Repeat(")", 6): "model.search_query.too_complex.app_error",
+		"(" + strings.Repeat("a OR ", 50) + "a)":        "model.search_query.too_complex.app_error",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, appErr := ParseSearchParamsWithQuery(input, 0)
+			require.NotNil(t, appErr)
+			assert.Equal(t, errorID, appErr.Id)
+		})
+	}
+}
+
+func TestSearchQueryContainsType(t *testing.T) {
+	query := &SearchQuery{Type: SearchQueryTypeAnd, Children: []*SearchQuery{
+		term("error"),
+		{Type: SearchQueryTypeNot, Children: []*SearchQuery{{Type: SearchQueryTypeRegexp, Value: "warn.*"}}},
+	}}
+	assert.True(t, query.ContainsType(SearchQueryTypeRegexp))
+	assert.False(t, query.ContainsType(SearchQueryTypeOr))
+	assert.Equal(t, []string{"error"}, query.IncludedTerms())
+}
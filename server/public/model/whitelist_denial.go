@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	WhitelistDenialPathMaxLength = 512
+
+	// Rules recorded when access is allowed.
+	WhitelistRuleDisabled          = "disabled"
+	WhitelistRuleBypassRole        = "bypass_role"
+	WhitelistRuleBypassPermission  = "bypass_permission"
+	WhitelistRuleBotBypass         = "bot_bypass"
+	WhitelistRuleOAuthAppBypass    = "oauth_app_bypass"
+	WhitelistRuleAccessTokenBypass = "access_token_bypass"
+	WhitelistRuleEmptyAllowed      = "empty_whitelist_allowed"
+	WhitelistRuleUserEntry         = "user_entry"
+	WhitelistRulePolicy            = "policy"
+
+	// Rules recorded when access is denied.
+	WhitelistRuleNotWhitelisted    = "not_whitelisted"
+	WhitelistRuleEmptyDenied       = "empty_whitelist_denied"
+	WhitelistRuleBotDenied         = "bot_denied"
+	WhitelistRuleOAuthAppDenied    = "oauth_app_denied"
+	WhitelistRuleAccessTokenDenied = "access_token_denied"
+)
+
+// WhitelistDecision is the outcome of checking a user's address against the
+// whitelist. Rule names the setting or entry that decided it and Match, when
+// set, the role, permission, entry or policy that matched.
+type WhitelistDecision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule"`
+	Match   string `json:"match,omitempty"`
+}
+
+// WhitelistDenial records a request that was refused because of the IP whitelist.
+// Repeated denials of the same user, address and rule are coalesced into one
+// record, counting them in Occurrences with the latest one at LastAt.
+type WhitelistDenial struct {
+	Id          string `json:"id"`
+	UserId      string `json:"user_id"`
+	IP          string `json:"ip"`
+	Path        string `json:"path"`
+	Rule        string `json:"rule"`
+	SessionId   string `json:"session_id"`
+	CreateAt    int64  `json:"create_at"`
+	Occurrences int    `json:"occurrences"`
+	LastAt      int64  `json:"last_at"`
+}
+
+// WhitelistDenialFilter selects denials for the history endpoint. Zero values
+// do not filter; Since and Until are inclusive bounds in milliseconds.
+type WhitelistDenialFilter struct {
+	UserId  string
+	IP      string
+	Since   int64
+	Until   int64
+	Page    int
+	PerPage int
+}
+
+func (o *WhitelistDenial) Auditable() map[string]any {
+	return map[string]any{
+		"id":          o.Id,
+		"user_id":     o.UserId,
+		"ip":          o.IP,
+		"path":        o.Path,
+		"rule":        o.Rule,
+		"session_id":  o.SessionId,
+		"create_at":   o.CreateAt,
+		"occurrences": o.Occurrences,
+		"last_at":     o.LastAt,
+	}
+}
+
+func (o *WhitelistDenial) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	if o.Occurrences < 1 {
+		o.Occurrences = 1
+	}
+
+	if o.LastAt == 0 {
+		o.LastAt = o.CreateAt
+	}
+
+	if normalized, err := NormalizeWhitelistIP(o.IP); err == nil {
+		o.IP = normalized
+	}
+
+	// Long paths are truncated rather than rejected so the denial is still recorded
+	if len(o.Path) > WhitelistDenialPathMaxLength {
+		o.Path = o.Path[:WhitelistDenialPathMaxLength]
+	}
+}
+
+func (o *WhitelistDenial) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("WhitelistDenial.IsValid", "model.whitelist_denial.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("WhitelistDenial.IsValid", "model.whitelist_denial.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.IP) > WhitelistItemIPMaxLength {
+		return NewAppError("WhitelistDenial.IsValid", "model.whitelist_denial.is_valid.ip.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Rule == "" {
+		return NewAppError("WhitelistDenial.IsValid", "model.whitelist_denial.is_valid.rule.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("WhitelistDenial.IsValid", "model.whitelist_denial.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// WhitelistDenialCSVHeader is the header row of the CSV export.
+var WhitelistDenialCSVHeader = []string{"id", "create_at", "user_id", "ip", "path", "rule", "session_id", "occurrences", "last_at"}
+
+// CSVRecord returns the denial as a row matching WhitelistDenialCSVHeader.
+func (o *WhitelistDenial) CSVRecord() []string {
+	return []string{o.Id, strconv.FormatInt(o.CreateAt, 10), o.UserId, o.IP, o.Path, o.Rule, o.SessionId, strconv.Itoa(o.Occurrences), strconv.FormatInt(o.LastAt, 10)}
+}
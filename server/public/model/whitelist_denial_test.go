@@ -0,0 +1,91 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistDenialPreSave(t *testing.T) {
+	o := WhitelistDenial{
+		UserId: NewId(),
+		IP:     "2001:DB8::0:1",
+		Path:   "/" + strings.Repeat("a", WhitelistDenialPathMaxLength),
+		Rule:   WhitelistRuleNotWhitelisted,
+	}
+	o.PreSave()
+
+	assert.True(t, IsValidId(o.Id))
+	assert.NotZero(t, o.CreateAt)
+	assert.Equal(t, 1, o.Occurrences)
+	assert.Equal(t, o.CreateAt, o.LastAt)
+	assert.Equal(t, "2001:db8::1", o.IP)
+	assert.Len(t, o.Path, WhitelistDenialPathMaxLength)
+
+	id, createAt := o.Id, o.CreateAt
+	o.PreSave()
+	assert.Equal(t, id, o.Id)
+	assert.Equal(t, createAt, o.CreateAt)
+
+	// Addresses that cannot be parsed are kept as they were received
+	o.IP = "unknown"
+	o.PreSave()
+	assert.Equal(t, "unknown", o.IP)
+}
+
+func TestWhitelistDenialIsValid(t *testing.T) {
+	o := WhitelistDenial{}
+	assert.NotNil(t, o.IsValid())
+
+	o.Id = NewId()
+	assert.NotNil(t, o.IsValid())
+
+	o.UserId = NewId()
+	assert.NotNil(t, o.IsValid())
+
+	o.Rule = WhitelistRuleEmptyDenied
+	assert.NotNil(t, o.IsValid())
+
+	o.CreateAt = GetMillis()
+	assert.Nil(t, o.IsValid())
+
+	o.IP = strings.Repeat("1", WhitelistItemIPMaxLength+1)
+	assert.NotNil(t, o.IsValid())
+}
+
+func TestWhitelistDenialCSVRecord(t *testing.T) {
+	o := WhitelistDenial{
+		Id:          NewId(),
+		UserId:      NewId(),
+		IP:          "10.0.0.1",
+		Path:        "/api/v4/users/me",
+		Rule:        WhitelistRuleNotWhitelisted,
+		SessionId:   NewId(),
+		CreateAt:    1700000000000,
+		Occurrences: 3,
+		LastAt:      1700000060000,
+	}
+
+	record := o.CSVRecord()
+	require.Len(t, record, len(WhitelistDenialCSVHeader))
+
+	row := map[string]string{}
+	for i, column := range WhitelistDenialCSVHeader {
+		row[column] = record[i]
+	}
+	assert.Equal(t, o.Id, row["id"])
+	assert.Equal(t, strconv.FormatInt(o.CreateAt, 10), row["create_at"])
+	assert.Equal(t, o.UserId, row["user_id"])
+	assert.Equal(t, o.IP, row["ip"])
+	assert.Equal(t, o.Path, row["path"])
+	assert.Equal(t, o.Rule, row["rule"])
+	assert.Equal(t, o.SessionId, row["session_id"])
+	assert.Equal(t, "3", row["occurrences"])
+	assert.Equal(t, strconv.FormatInt(o.LastAt, 10), row["last_at"])
+}
@@ -5,13 +5,35 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
-// WhitelistItem represents an IP address whitelisted for a specific user
+const (
+	// WhitelistItemIPMaxLength fits the longest textual IPv6 address followed by a /128 suffix.
+	WhitelistItemIPMaxLength          = 49
+	WhitelistItemDescriptionMaxLength = 255
+)
+
+// WhitelistItem represents an IP address or CIDR range whitelisted for a specific user
 type WhitelistItem struct {
-	UserId string `json:"user_id"` // User ID
-	IP     string `json:"ip"`      // IP address from the whitelist
+	UserId      string `json:"user_id"`     // User ID
+	IP          string `json:"ip"`          // IP address or CIDR range from the whitelist
+	Description string `json:"description"` // Optional free-form note
+	CreatorId   string `json:"creator_id"`  // User who added the entry
+	CreateAt    int64  `json:"create_at"`
+	ExpiresAt   int64  `json:"expires_at"` // Zero means the entry never expires
+}
+
+// WhitelistItemForExport is a whitelist entry along with the username of its user.
+type WhitelistItemForExport struct {
+	WhitelistItem
+	Username string `json:"username"`
 }
 
 func (o *WhitelistItem) ToJSON() string {
@@ -25,14 +47,206 @@ func WhitelistItemFromJSON(data io.Reader) *WhitelistItem {
 	return o
 }
 
+func (o *WhitelistItem) Auditable() map[string]any {
+	return map[string]any{
+		"user_id":     o.UserId,
+		"ip":          o.IP,
+		"description": o.Description,
+		"creator_id":  o.CreatorId,
+		"create_at":   o.CreateAt,
+		"expires_at":  o.ExpiresAt,
+	}
+}
+
+// PreSave normalizes the IP to its canonical notation and sets the creation time.
+func (o *WhitelistItem) PreSave() {
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	if ip, err := NormalizeWhitelistIP(o.IP); err == nil {
+		o.IP = ip
+	}
+
+	o.Description = strings.TrimSpace(o.Description)
+}
+
 func (o *WhitelistItem) IsValid() *AppError {
 	if !IsValidId(o.UserId) {
-		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.user_id.app_error", nil, "", 400)
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.IP) == 0 || len(o.IP) > WhitelistItemIPMaxLength {
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.ip.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, err := ParseWhitelistPrefix(o.IP); err != nil {
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.ip.app_error", nil, "", http.StatusBadRequest).Wrap(err)
 	}
 
-	if len(o.IP) == 0 || len(o.IP) > 39 {
-		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.ip.app_error", nil, "", 400)
+	if utf8.RuneCountInString(o.Description) > WhitelistItemDescriptionMaxLength {
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.description.app_error", map[string]any{"MaxLength": WhitelistItemDescriptionMaxLength}, "", http.StatusBadRequest)
+	}
+
+	if o.CreatorId != "" && !IsValidId(o.CreatorId) {
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.creator_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.ExpiresAt < 0 || (o.ExpiresAt != 0 && o.CreateAt != 0 && o.ExpiresAt <= o.CreateAt) {
+		return NewAppError("WhitelistItem.IsValid", "model.whitelist_item.is_valid.expires_at.app_error", nil, "", http.StatusBadRequest)
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// Prefix returns the network covered by the entry. A bare address is
+// treated as a single-host prefix.
+func (o *WhitelistItem) Prefix() (netip.Prefix, error) {
+	return ParseWhitelistPrefix(o.IP)
+}
+
+// IsExpired reports whether the entry has an expiry that is at or before the given time in milliseconds.
+func (o *WhitelistItem) IsExpired(now int64) bool {
+	return o.ExpiresAt != 0 && o.ExpiresAt <= now
+}
+
+// ParseWhitelistPrefix parses either a literal IPv4/IPv6 address or a CIDR
+// range. Host bits in a CIDR are masked off and IPv4-mapped IPv6 addresses are
+// unmapped so that equivalent inputs produce the same prefix.
+func ParseWhitelistPrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr := prefix.Addr()
+		bits := prefix.Bits()
+		if addr.Is4In6() && bits >= 96 {
+			addr = addr.Unmap()
+			bits -= 96
+		}
+		return netip.PrefixFrom(addr, bits).Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap().WithZone("")
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// NormalizeWhitelistIP returns the canonical textual form of an address or
+// CIDR range. Single hosts are written without a prefix length so that they
+// keep matching entries stored before ranges were supported.
+func NormalizeWhitelistIP(s string) (string, error) {
+	prefix, err := ParseWhitelistPrefix(s)
+	if err != nil {
+		return "", err
+	}
+
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String(), nil
+	}
+
+	return prefix.String(), nil
+}
+
+// WhitelistPrefixesContain reports whether any of the given addresses falls
+// inside one of the prefixes. Addresses that fail to parse are ignored.
+func WhitelistPrefixesContain(prefixes []netip.Prefix, ipAddresses []string) bool {
+	for _, ipAddress := range ipAddresses {
+		addr, err := netip.ParseAddr(strings.TrimSpace(ipAddress))
+		if err != nil {
+			continue
+		}
+		addr = addr.Unmap().WithZone("")
+
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WhitelistPrefixEntry is a whitelist entry compiled into the network it covers.
+type WhitelistPrefixEntry struct {
+	Prefix    netip.Prefix `json:"prefix"`
+	ExpiresAt int64        `json:"expires_at"` // Zero means the entry never expires
+}
+
+// WhitelistPrefixSet holds the compiled whitelist of a user. Entries that fail
+// to parse are kept aside in Invalid so callers can report them.
+type WhitelistPrefixSet struct {
+	Entries []WhitelistPrefixEntry `json:"entries"`
+	Invalid []string               `json:"invalid"`
+}
+
+// NewWhitelistPrefixSet compiles the given whitelist entries. Expired entries are
+// kept, as the set is filtered by Active when it is checked.
+func NewWhitelistPrefixSet(items []*WhitelistItem) *WhitelistPrefixSet {
+	set := &WhitelistPrefixSet{
+		Entries: make([]WhitelistPrefixEntry, 0, len(items)),
+	}
+
+	for _, item := range items {
+		prefix, err := item.Prefix()
+		if err != nil {
+			set.Invalid = append(set.Invalid, item.IP)
+			continue
+		}
+		set.Entries = append(set.Entries, WhitelistPrefixEntry{Prefix: prefix, ExpiresAt: item.ExpiresAt})
+	}
+
+	return set
+}
+
+// Active returns the prefixes of the entries that have not expired at the given time in milliseconds.
+func (s *WhitelistPrefixSet) Active(now int64) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		if entry.ExpiresAt != 0 && entry.ExpiresAt <= now {
+			continue
+		}
+		prefixes = append(prefixes, entry.Prefix)
+	}
+
+	return prefixes
+}
+
+// CompileWhitelistPathPattern compiles an excluded path pattern. A pattern
+// without wildcards matches every path it is a prefix of. Otherwise it must
+// match the whole path, with "*" matching within a single path segment and
+// "**" matching across segments, e.g. "/api/v4/users/*/image" or "/plugins/**".
+func CompileWhitelistPathPattern(pattern string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, errors.New("path pattern must start with /")
+	}
+
+	if !strings.Contains(pattern, "*") {
+		return regexp.Compile("^" + regexp.QuoteMeta(pattern))
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i, part := range strings.Split(pattern, "**") {
+		if i > 0 {
+			expr.WriteString(".*")
+		}
+		for j, segment := range strings.Split(part, "*") {
+			if j > 0 {
+				expr.WriteString("[^/]*")
+			}
+			expr.WriteString(regexp.QuoteMeta(segment))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
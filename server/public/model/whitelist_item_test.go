@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistItemIsValid(t *testing.T) {
+	o := WhitelistItem{}
+	assert.NotNil(t, o.IsValid())
+
+	o.UserId = NewId()
+	assert.NotNil(t, o.IsValid())
+
+	o.IP = "not-an-ip"
+	assert.NotNil(t, o.IsValid())
+
+	o.IP = "10.0.0.0/33"
+	assert.NotNil(t, o.IsValid())
+
+	o.IP = "10.0.0.1"
+	assert.Nil(t, o.IsValid())
+
+	o.IP = "10.0.0.0/24"
+	assert.Nil(t, o.IsValid())
+
+	o.IP = "2001:db8::/32"
+	assert.Nil(t, o.IsValid())
+
+	o.Description = strings.Repeat("a", WhitelistItemDescriptionMaxLength+1)
+	assert.NotNil(t, o.IsValid())
+	o.Description = "office"
+
+	o.CreatorId = "bad"
+	assert.NotNil(t, o.IsValid())
+	o.CreatorId = NewId()
+	assert.Nil(t, o.IsValid())
+
+	o.CreateAt = 2000
+	o.ExpiresAt = 1000
+	assert.NotNil(t, o.IsValid())
+
+	o.ExpiresAt = 3000
+	assert.Nil(t, o.IsValid())
+
+	o.ExpiresAt = -1
+	assert.NotNil(t, o.IsValid())
+}
+
+func TestWhitelistItemPreSave(t *testing.T) {
+	o := WhitelistItem{UserId: NewId(), IP: " 192.168.1.77/24 ", Description: " vpn "}
+	o.PreSave()
+
+	assert.Equal(t, "192.168.1.0/24", o.IP)
+	assert.Equal(t, "vpn", o.Description)
+	assert.NotZero(t, o.CreateAt)
+
+	o = WhitelistItem{UserId: NewId(), IP: "::ffff:10.1.2.3"}
+	o.PreSave()
+	assert.Equal(t, "10.1.2.3", o.IP)
+}
+
+func TestWhitelistItemIsExpired(t *testing.T) {
+	o := WhitelistItem{}
+	assert.False(t, o.IsExpired(GetMillis()))
+
+	o.ExpiresAt = 1000
+	assert.False(t, o.IsExpired(999))
+	assert.True(t, o.IsExpired(1000))
+	assert.True(t, o.IsExpired(1001))
+}
+
+func TestParseWhitelistPrefix(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      bool
+	}{
+		{"10.0.0.1", "10.0.0.1/32", false},
+		{"10.0.0.1/8", "10.0.0.0/8", false},
+		{"2001:db8::1", "2001:db8::1/128", false},
+		{"2001:db8::1/64", "2001:db8::/64", false},
+		{"::ffff:192.168.0.1", "192.168.0.1/32", false},
+		{"::ffff:192.168.0.0/120", "192.168.0.0/24", false},
+		{"fe80::1%eth0", "fe80::1/128", false},
+		{"", "", true},
+		{"10.0.0.256", "", true},
+		{"10.0.0.0/", "", true},
+		{"2001:db8::/129", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			prefix, err := ParseWhitelistPrefix(tc.input)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, prefix.String())
+		})
+	}
+}
+
+func TestNormalizeWhitelistIP(t *testing.T) {
+	ip, err := NormalizeWhitelistIP("10.0.0.1/32")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ip)
+
+	ip, err = NormalizeWhitelistIP("10.0.0.1/16")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/16", ip)
+
+	_, err = NormalizeWhitelistIP("nope")
+	require.Error(t, err)
+}
+
+func TestWhitelistPrefixesContain(t *testing.T) {
+	v4, err := ParseWhitelistPrefix("192.168.10.0/24")
+	require.NoError(t, err)
+	v6, err := ParseWhitelistPrefix("2001:db8:abcd::/48")
+	require.NoError(t, err)
+	assert.True(t, WhitelistPrefixesContain([]netip.Prefix{v4, v6}, []string{"192.168.10.200"}))
+	assert.True(t, WhitelistPrefixesContain([]netip.Prefix{v4, v6}, []string{"::ffff:192.168.10.3"}))
+	assert.True(t, WhitelistPrefixesContain([]netip.Prefix{v4, v6}, []string{"garbage", "2001:db8:abcd:12::1"}))
+	assert.False(t, WhitelistPrefixesContain([]netip.Prefix{v4, v6}, []string{"192.168.11.1", "2001:db8:abce::1"}))
+	assert.False(t, WhitelistPrefixesContain(nil, []string{"192.168.10.1"}))
+}
+
+func TestNewWhitelistPrefixSet(t *testing.T) {
+	set := NewWhitelistPrefixSet([]*WhitelistItem{
+		{IP: "192.168.10.0/24"},
+		{IP: "not an address"},
+		{IP: "2001:db8::1", ExpiresAt: 1000},
+	})
+
+	require.Len(t, set.Entries, 2)
+	assert.Equal(t, []string{"not an address"}, set.Invalid)
+	assert.Equal(t, "192.168.10.0/24", set.Entries[0].Prefix.String())
+	assert.Equal(t, int64(1000), set.Entries[1].ExpiresAt)
+
+	assert.Len(t, set.Active(999), 2)
+	active := set.Active(1000)
+	require.Len(t, active, 1)
+	assert.Equal(t, "192.168.10.0/24", active[0].String())
+
+	assert.Empty(t, NewWhitelistPrefixSet(nil).Active(0))
+}
+
+func TestCompileWhitelistPathPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{"/api/v4/users/login", "/api/v4/users/login", true},
+		{"/api/v4/users/login", "/api/v4/users/login/switch", true},
+		{"/api/v4/users/login", "/api/v4/users/logout", false},
+		{"/api/v4/users/*/image", "/api/v4/users/abc/image", true},
+		{"/api/v4/users/*/image", "/api/v4/users/abc/def/image", false},
+		{"/api/v4/users/*/image", "/api/v4/users/abc/image/default", false},
+		{"/plugins/**", "/plugins/com.example/api/hook", true},
+		{"/plugins/**/static", "/plugins/a/b/static", true},
+		{"/static/*.js", "/static/main.js", true},
+		{"/static/*.js", "/static/mainXjs", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			re, err := CompileWhitelistPathPattern(tc.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, re.MatchString(tc.path))
+		})
+	}
+
+	_, err := CompileWhitelistPathPattern("api/v4/users")
+	require.Error(t, err)
+}
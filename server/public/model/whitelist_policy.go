@@ -0,0 +1,197 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	WhitelistPolicyTargetTypeTeam  = "team"
+	WhitelistPolicyTargetTypeGroup = "group"
+	WhitelistPolicyTargetTypeRole  = "role"
+
+	WhitelistPolicyNameMaxLength        = 64
+	WhitelistPolicyDescriptionMaxLength = 1024
+	WhitelistPolicyMaxIPRanges          = 1000
+)
+
+// WhitelistPolicy is a named set of IP addresses and CIDR ranges that can be
+// attached to teams, groups or roles. Members of any attached target may
+// connect from the policy's ranges in addition to their personal whitelist.
+type WhitelistPolicy struct {
+	Id          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	IPRanges    StringArray `json:"ip_ranges"`
+	CreatorId   string      `json:"creator_id"`
+	CreateAt    int64       `json:"create_at"`
+	UpdateAt    int64       `json:"update_at"`
+}
+
+type WhitelistPolicyPatch struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	IPRanges    *[]string `json:"ip_ranges"`
+}
+
+// WhitelistPolicyTarget attaches a policy to a team, a group or a role. For
+// roles the TargetId holds the role name rather than an id.
+type WhitelistPolicyTarget struct {
+	PolicyId   string `json:"policy_id"`
+	TargetType string `json:"target_type"`
+	TargetId   string `json:"target_id"`
+	CreateAt   int64  `json:"create_at"`
+}
+
+func (p *WhitelistPolicy) Auditable() map[string]any {
+	return map[string]any{
+		"id":          p.Id,
+		"name":        p.Name,
+		"description": p.Description,
+		"ip_ranges":   p.IPRanges,
+		"creator_id":  p.CreatorId,
+		"create_at":   p.CreateAt,
+		"update_at":   p.UpdateAt,
+	}
+}
+
+func (p *WhitelistPolicy) PreSave() {
+	if p.Id == "" {
+		p.Id = NewId()
+	}
+
+	p.CreateAt = GetMillis()
+	p.UpdateAt = p.CreateAt
+	p.normalize()
+}
+
+func (p *WhitelistPolicy) PreUpdate() {
+	p.UpdateAt = GetMillis()
+	p.normalize()
+}
+
+// normalize trims the name and description and rewrites the ranges to their
+// canonical form, dropping duplicates. Ranges that fail to parse are kept as
+// is so that IsValid can report them.
+func (p *WhitelistPolicy) normalize() {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Description = strings.TrimSpace(p.Description)
+
+	seen := make(map[string]bool, len(p.IPRanges))
+	ranges := make(StringArray, 0, len(p.IPRanges))
+	for _, ipRange := range p.IPRanges {
+		if normalized, err := NormalizeWhitelistIP(ipRange); err == nil {
+			ipRange = normalized
+		}
+		if seen[ipRange] {
+			continue
+		}
+		seen[ipRange] = true
+		ranges = append(ranges, ipRange)
+	}
+	p.IPRanges = ranges
+}
+
+func (p *WhitelistPolicy) IsValid() *AppError {
+	if !IsValidId(p.Id) {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if p.Name == "" || utf8.RuneCountInString(p.Name) > WhitelistPolicyNameMaxLength || !IsValidAlphaNumHyphenUnderscore(p.Name, false) {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.name.app_error", map[string]any{"MaxLength": WhitelistPolicyNameMaxLength}, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(p.Description) > WhitelistPolicyDescriptionMaxLength {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.description.app_error", map[string]any{"MaxLength": WhitelistPolicyDescriptionMaxLength}, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if len(p.IPRanges) > WhitelistPolicyMaxIPRanges {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.ip_ranges_count.app_error", map[string]any{"Max": WhitelistPolicyMaxIPRanges}, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	for _, ipRange := range p.IPRanges {
+		if _, err := ParseWhitelistPrefix(ipRange); err != nil {
+			return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.ip_range.app_error", map[string]any{"Range": ipRange}, "id="+p.Id, http.StatusBadRequest).Wrap(err)
+		}
+	}
+
+	if p.CreatorId != "" && !IsValidId(p.CreatorId) {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.creator_id.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if p.CreateAt == 0 {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.create_at.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	if p.UpdateAt == 0 {
+		return NewAppError("WhitelistPolicy.IsValid", "model.whitelist_policy.is_valid.update_at.app_error", nil, "id="+p.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (p *WhitelistPolicy) Patch(patch *WhitelistPolicyPatch) {
+	if patch.Name != nil {
+		p.Name = *patch.Name
+	}
+
+	if patch.Description != nil {
+		p.Description = *patch.Description
+	}
+
+	if patch.IPRanges != nil {
+		p.IPRanges = *patch.IPRanges
+	}
+}
+
+// Prefixes returns the parsed ranges of the policy, skipping any that are
+// invalid.
+func (p *WhitelistPolicy) Prefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(p.IPRanges))
+	for _, ipRange := range p.IPRanges {
+		if prefix, err := ParseWhitelistPrefix(ipRange); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+func (t *WhitelistPolicyTarget) Auditable() map[string]any {
+	return map[string]any{
+		"policy_id":   t.PolicyId,
+		"target_type": t.TargetType,
+		"target_id":   t.TargetId,
+	}
+}
+
+func (t *WhitelistPolicyTarget) PreSave() {
+	if t.CreateAt == 0 {
+		t.CreateAt = GetMillis()
+	}
+}
+
+func (t *WhitelistPolicyTarget) IsValid() *AppError {
+	if !IsValidId(t.PolicyId) {
+		return NewAppError("WhitelistPolicyTarget.IsValid", "model.whitelist_policy_target.is_valid.policy_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	switch t.TargetType {
+	case WhitelistPolicyTargetTypeTeam, WhitelistPolicyTargetTypeGroup:
+		if !IsValidId(t.TargetId) {
+			return NewAppError("WhitelistPolicyTarget.IsValid", "model.whitelist_policy_target.is_valid.target_id.app_error", nil, "target_id="+t.TargetId, http.StatusBadRequest)
+		}
+	case WhitelistPolicyTargetTypeRole:
+		if !IsValidRoleName(t.TargetId) {
+			return NewAppError("WhitelistPolicyTarget.IsValid", "model.whitelist_policy_target.is_valid.target_id.app_error", nil, "target_id="+t.TargetId, http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("WhitelistPolicyTarget.IsValid", "model.whitelist_policy_target.is_valid.target_type.app_error", nil, "target_type="+t.TargetType, http.StatusBadRequest)
+	}
+
+	return nil
+}
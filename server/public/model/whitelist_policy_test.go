@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitelistPolicyPreSave(t *testing.T) {
+	policy := WhitelistPolicy{
+		Name:        "  office ",
+		Description: " head office ",
+		IPRanges:    []string{"10.1.2.3/8", "::ffff:192.168.1.1", "10.0.0.0/8", "bogus"},
+	}
+	policy.PreSave()
+
+	assert.True(t, IsValidId(policy.Id))
+	assert.NotZero(t, policy.CreateAt)
+	assert.Equal(t, policy.CreateAt, policy.UpdateAt)
+	assert.Equal(t, "office", policy.Name)
+	assert.Equal(t, "head office", policy.Description)
+	assert.Equal(t, StringArray{"10.0.0.0/8", "192.168.1.1", "bogus"}, policy.IPRanges)
+}
+
+func TestWhitelistPolicyIsValid(t *testing.T) {
+	valid := func() *WhitelistPolicy {
+		return &WhitelistPolicy{
+			Id:        NewId(),
+			Name:      "office_network",
+			IPRanges:  []string{"10.0.0.0/8", "2001:db8::1"},
+			CreatorId: NewId(),
+			CreateAt:  1,
+			UpdateAt:  1,
+		}
+	}
+
+	require.Nil(t, valid().IsValid())
+
+	testCases := []struct {
+		Name     string
+		Modify   func(p *WhitelistPolicy)
+		ErrorId  string
+		HasError bool
+	}{
+		{"invalid id", func(p *WhitelistPolicy) { p.Id = "abc" }, "model.whitelist_policy.is_valid.id.app_error", true},
+		{"empty name", func(p *WhitelistPolicy) { p.Name = "" }, "model.whitelist_policy.is_valid.name.app_error", true},
+		{"name with spaces", func(p *WhitelistPolicy) { p.Name = "head office" }, "model.whitelist_policy.is_valid.name.app_error", true},
+		{"long name", func(p *WhitelistPolicy) { p.Name = strings.Repeat("a", WhitelistPolicyNameMaxLength+1) }, "model.whitelist_policy.is_valid.name.app_error", true},
+		{"long description", func(p *WhitelistPolicy) {
+			p.Description = strings.Repeat("a", WhitelistPolicyDescriptionMaxLength+1)
+		}, "model.whitelist_policy.is_valid.description.app_error", true},
+		{"too many ranges", func(p *WhitelistPolicy) {
+			p.IPRanges = make(StringArray, WhitelistPolicyMaxIPRanges+1)
+		}, "model.whitelist_policy.is_valid.ip_ranges_count.app_error", true},
+		{"invalid range", func(p *WhitelistPolicy) { p.IPRanges = []string{"10.0.0.0/33"} }, "model.whitelist_policy.is_valid.ip_range.app_error", true},
+		{"invalid creator", func(p *WhitelistPolicy) { p.CreatorId = "abc" }, "model.whitelist_policy.is_valid.creator_id.app_error", true},
+		{"empty creator", func(p *WhitelistPolicy) { p.CreatorId = "" }, "", false},
+		{"no ranges", func(p *WhitelistPolicy) { p.IPRanges = nil }, "", false},
+		{"missing create at", func(p *WhitelistPolicy) { p.CreateAt = 0 }, "model.whitelist_policy.is_valid.create_at.app_error", true},
+		{"missing update at", func(p *WhitelistPolicy) { p.UpdateAt = 0 }, "model.whitelist_policy.is_valid.update_at.app_error", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			policy := valid()
+			tc.Modify(policy)
+			appErr := policy.IsValid()
+			if !tc.HasError {
+				require.Nil(t, appErr)
+				return
+			}
+			require.NotNil(t, appErr)
+			assert.Equal(t, tc.ErrorId, appErr.Id)
+		})
+	}
+}
+
+func TestWhitelistPolicyPatch(t *testing.T) {
+	policy := &WhitelistPolicy{Name: "office", Description: "old", IPRanges: []string{"10.0.0.0/8"}}
+
+	description := "new"
+	policy.Patch(&WhitelistPolicyPatch{Description: &description})
+	assert.Equal(t, "office", policy.Name)
+	assert.Equal(t, "new", policy.Description)
+	assert.Equal(t, StringArray{"10.0.0.0/8"}, policy.IPRanges)
+
+	ranges := []string{"192.168.0.0/16"}
+	name := "branch"
+	policy.Patch(&WhitelistPolicyPatch{Name: &name, IPRanges: &ranges})
+	assert.Equal(t, "branch", policy.Name)
+	assert.Equal(t, StringArray{"192.168.0.0/16"}, policy.IPRanges)
+}
+
+func TestWhitelistPolicyPrefixes(t *testing.T) {
+	policy := &WhitelistPolicy{IPRanges: []string{"10.0.0.0/8", "bogus", "2001:db8::1"}}
+
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::1/128"),
+	}, policy.Prefixes())
+}
+
+func TestWhitelistPolicyTargetIsValid(t *testing.T) {
+	policyId := NewId()
+
+	testCases := []struct {
+		Name    string
+		Target  WhitelistPolicyTarget
+		ErrorId string
+	}{
+		{"team", WhitelistPolicyTarget{PolicyId: policyId, TargetType: WhitelistPolicyTargetTypeTeam, TargetId: NewId()}, ""},
+		{"group", WhitelistPolicyTarget{PolicyId: policyId, TargetType: WhitelistPolicyTargetTypeGroup, TargetId: NewId()}, ""},
+		{"role", WhitelistPolicyTarget{PolicyId: policyId, TargetType: WhitelistPolicyTargetTypeRole, TargetId: SystemUserRoleId}, ""},
+		{"invalid policy id", WhitelistPolicyTarget{PolicyId: "abc", TargetType: WhitelistPolicyTargetTypeTeam, TargetId: NewId()}, "model.whitelist_policy_target.is_valid.policy_id.app_error"},
+		{"team with name", WhitelistPolicyTarget{PolicyId: policyId, TargetType: WhitelistPolicyTargetTypeTeam, TargetId: "myteam"}, "model.whitelist_policy_target.is_valid.target_id.app_error"},
+		{"invalid role name", WhitelistPolicyTarget{PolicyId: policyId, TargetType: WhitelistPolicyTargetTypeRole, TargetId: "Bad Role"}, "model.whitelist_policy_target.is_valid.target_id.app_error"},
+		{"unknown type", WhitelistPolicyTarget{PolicyId: policyId, TargetType: "channel", TargetId: NewId()}, "model.whitelist_policy_target.is_valid.target_type.app_error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			appErr := tc.Target.IsValid()
+			if tc.ErrorId == "" {
+				require.Nil(t, appErr)
+				return
+			}
+			require.NotNil(t, appErr)
+			assert.Equal(t, tc.ErrorId, appErr.Id)
+		})
+	}
+}
@@ -247,6 +247,9 @@ export default class PermissionsTree extends React.PureComponent<Props, State> {
         if (license?.IsLicensed === 'true' && (license?.LDAPGroups === 'true' || config.EnableCustomGroups === 'true') && !postsGroup.permissions.includes(Permissions.USE_GROUP_MENTIONS)) {
             postsGroup.permissions.push(Permissions.USE_GROUP_MENTIONS);
         }
+        if (config.ScheduledPosts === 'true' && !postsGroup.permissions.includes(Permissions.MANAGE_CHANNEL_SCHEDULED_POSTS)) {
+            postsGroup.permissions.push(Permissions.MANAGE_CHANNEL_SCHEDULED_POSTS);
+        }
         postsGroup.permissions.push({
             id: Permissions.CREATE_POST,
             combined: true,
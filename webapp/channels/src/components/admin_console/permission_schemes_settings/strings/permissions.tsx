@@ -645,4 +645,14 @@ export const permissionRolesStrings: Record<string, Record<string, MessageDescri
             defaultMessage: 'Enable, disable and edit channel banner.',
         },
     }),
+    manage_channel_scheduled_posts: defineMessages({
+        name: {
+            id: 'admin.permissions.permission.manage_channel_scheduled_posts.name',
+            defaultMessage: 'Manage Channel Scheduled Posts',
+        },
+        description: {
+            id: 'admin.permissions.permission.manage_channel_scheduled_posts.description',
+            defaultMessage: 'View, edit and cancel the messages that members scheduled in the channel, and publish them as a bot.',
+        },
+    }),
 };
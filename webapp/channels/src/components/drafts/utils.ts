@@ -47,6 +47,10 @@ const errorCodeToErrorMessage = defineMessages<ScheduledPostErrorCode>({
         id: 'scheduled_post.error_code.invalid_post',
         defaultMessage: 'Invalid Post',
     },
+    bot_unavailable: {
+        id: 'scheduled_post.error_code.bot_unavailable',
+        defaultMessage: 'Bot Unavailable',
+    },
 });
 
 export function getErrorStringFromCode(intl: IntlShape, errorCode: ScheduledPostErrorCode = 'unknown') {
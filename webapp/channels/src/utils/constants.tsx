@@ -1319,6 +1319,7 @@ export const PermissionsScope = {
     [Permissions.ORDER_BOOKMARK_PRIVATE_CHANNEL]: 'channel_scope',
     [Permissions.MANAGE_PUBLIC_CHANNEL_BANNER]: 'channel_scope',
     [Permissions.MANAGE_PRIVATE_CHANNEL_BANNER]: 'channel_scope',
+    [Permissions.MANAGE_CHANNEL_SCHEDULED_POSTS]: 'channel_scope',
 };
 
 export const DefaultRolePermissions = {
@@ -1400,6 +1401,7 @@ export const DefaultRolePermissions = {
         Permissions.ORDER_BOOKMARK_PRIVATE_CHANNEL,
         Permissions.MANAGE_PUBLIC_CHANNEL_BANNER,
         Permissions.MANAGE_PRIVATE_CHANNEL_BANNER,
+        Permissions.MANAGE_CHANNEL_SCHEDULED_POSTS,
     ],
     team_admin: [
         Permissions.EDIT_OTHERS_POSTS,
@@ -1437,6 +1439,7 @@ export const DefaultRolePermissions = {
         Permissions.ORDER_BOOKMARK_PRIVATE_CHANNEL,
         Permissions.MANAGE_PUBLIC_CHANNEL_BANNER,
         Permissions.MANAGE_PRIVATE_CHANNEL_BANNER,
+        Permissions.MANAGE_CHANNEL_SCHEDULED_POSTS,
     ],
     guests: [
         Permissions.EDIT_POST,
@@ -2185,6 +2188,10 @@ export const searchHintOptions = [{searchTerm: 'From:', message: defineMessage({
     {searchTerm: 'On:', message: defineMessage({id: 'search_list_option.on', defaultMessage: 'Messages on a date'})},
     {searchTerm: 'Before:', message: defineMessage({id: 'search_list_option.before', defaultMessage: 'Messages before a date'})},
     {searchTerm: 'After:', message: defineMessage({id: 'search_list_option.after', defaultMessage: 'Messages after a date'})},
+    {searchTerm: 'Has:', message: defineMessage({id: 'search_list_option.has', defaultMessage: 'Messages with a file or a link'})},
+    {searchTerm: 'Is:', message: defineMessage({id: 'search_list_option.is', defaultMessage: 'Pinned messages or thread roots'})},
+    {searchTerm: 'Reacted:', message: defineMessage({id: 'search_list_option.reacted', defaultMessage: 'Messages with a reaction'})},
+    {searchTerm: 'Priority:', message: defineMessage({id: 'search_list_option.priority', defaultMessage: 'Messages with a priority'})},
     {searchTerm: '-', message: defineMessage({id: 'search_list_option.exclude', defaultMessage: 'Exclude search terms'}), additionalDisplay: '—'},
     {searchTerm: '""', message: defineMessage({id: 'search_list_option.phrases', defaultMessage: 'Messages with phrases'})},
 ];
@@ -4464,6 +4464,49 @@ export default class Client4 {
         );
     };
 
+    pauseScheduledPost = (schedulePostId: string, connectionId: string) => {
+        return this.doFetchWithResponse<ScheduledPost>(
+            `${this.getPostsRoute()}/schedule/${schedulePostId}/pause`,
+            {method: 'post', headers: {'Connection-Id': connectionId}},
+        );
+    };
+
+    resumeScheduledPost = (schedulePostId: string, connectionId: string) => {
+        return this.doFetchWithResponse<ScheduledPost>(
+            `${this.getPostsRoute()}/schedule/${schedulePostId}/resume`,
+            {method: 'post', headers: {'Connection-Id': connectionId}},
+        );
+    };
+
+    skipScheduledPostOccurrence = (schedulePostId: string, connectionId: string) => {
+        return this.doFetchWithResponse<ScheduledPost>(
+            `${this.getPostsRoute()}/schedule/${schedulePostId}/skip`,
+            {method: 'post', headers: {'Connection-Id': connectionId}},
+        );
+    };
+
+    // get the scheduled posts of every user in a channel
+    getChannelScheduledPosts = (channelId: string) => {
+        return this.doFetchWithResponse<ScheduledPost[]>(
+            `${this.getChannelRoute(channelId)}/scheduled_posts`,
+            {method: 'get'},
+        );
+    };
+
+    updateChannelScheduledPost = (schedulePost: ScheduledPost, connectionId: string) => {
+        return this.doFetchWithResponse<ScheduledPost>(
+            `${this.getChannelRoute(schedulePost.channel_id)}/scheduled_posts/${schedulePost.id}`,
+            {method: 'put', body: JSON.stringify(schedulePost), headers: {'Connection-Id': connectionId}},
+        );
+    };
+
+    deleteChannelScheduledPost = (channelId: string, schedulePostId: string, connectionId: string) => {
+        return this.doFetchWithResponse<ScheduledPost>(
+            `${this.getChannelRoute(channelId)}/scheduled_posts/${schedulePostId}`,
+            {method: 'delete', headers: {'Connection-Id': connectionId}},
+        );
+    };
+
     restorePostVersion = (postId: string, restoreVersionId: string, connectionId: string) => {
         return this.doFetchWithResponse<Post>(
             `${this.getPostRoute(postId)}/restore/${restoreVersionId}`,
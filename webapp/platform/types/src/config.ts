@@ -309,6 +309,7 @@ export type ServiceSettings = {
     LetsEncryptCertificateCacheFile: string;
     Forward80To443: boolean;
     TrustedProxyIPHeader: string[];
+    TrustedProxyCIDRs: string[];
     ReadTimeout: number;
     WriteTimeout: number;
     IdleTimeout: number;
@@ -621,6 +622,14 @@ export type RateLimitSettings = {
     VaryByRemoteAddr: boolean;
     VaryByUser: boolean;
     VaryByHeader: string;
+    RouteQuotas: RateLimitRouteQuota[];
+};
+
+export type RateLimitRouteQuota = {
+    Route: string;
+    Method: string;
+    PerSec: number;
+    MaxBurst: number;
 };
 
 export type PrivacySettings = {
@@ -877,6 +886,12 @@ export type BleveSettings = {
     EnableSearching: boolean;
     EnableAutocomplete: boolean;
     BatchSize: number;
+    PostIndexAnalyzer: string;
+    FileIndexAnalyzer: string;
+    ChannelIndexAnalyzer: string;
+    UserIndexAnalyzer: string;
+    PreserveNumericTokens: boolean;
+    ClusterMode: string;
 };
 
 export type DataRetentionSettings = {
@@ -983,6 +998,37 @@ export type AccessControlSettings = {
     EnableChannelScopeAccessControl: boolean;
 };
 
+export type WhitelistSettings = {
+    Enable: boolean;
+    BypassRoles: string[];
+    BypassPermissions: string[];
+    BotAccess: 'enforce' | 'bypass' | 'deny';
+    OAuthAppAccess: 'enforce' | 'bypass' | 'deny';
+    PersonalAccessTokenAccess: 'enforce' | 'bypass' | 'deny';
+    ExcludedPaths: string[];
+    EmptyWhitelistAction: 'deny' | 'allow';
+    DeniedAccessRetentionDays: number;
+    EnableSelfServiceEnrollment: boolean;
+    EnrollmentMethods: Array<'email' | 'mfa'>;
+    EnrollmentLinkExpiryMinutes: number;
+    EnrollmentEntryExpiryHours: number;
+    EnrollmentRateLimitPerHour: number;
+};
+
+export type LoginThrottleSettings = {
+    Enable: boolean;
+    BackoffAfterAttempts: number;
+    SubnetBackoffAfterAttempts: number;
+    MaxBackoffSeconds: number;
+    MaxAttemptsPerAddress: number;
+    MaxAttemptsPerSubnet: number;
+    IPv4SubnetPrefixLength: number;
+    IPv6SubnetPrefixLength: number;
+    AttemptWindowMinutes: number;
+    BanDurationMinutes: number;
+    ExemptAddresses: string[];
+};
+
 export type AdminConfig = {
     ServiceSettings: ServiceSettings;
     TeamSettings: TeamSettings;
@@ -1029,6 +1075,8 @@ export type AdminConfig = {
     WranglerSettings: WranglerSettings;
     ConnectedWorkspacesSettings: ConnectedWorkspacesSettings;
     AccessControlSettings: AccessControlSettings;
+    WhitelistSettings: WhitelistSettings;
+    LoginThrottleSettings: LoginThrottleSettings;
 };
 
 export type ReplicaLagSetting = {
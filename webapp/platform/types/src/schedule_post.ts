@@ -4,12 +4,26 @@
 import type {Draft} from './drafts';
 import type {Post} from './posts';
 
-export type ScheduledPostErrorCode = 'unknown' | 'channel_archived' | 'channel_not_found' | 'user_missing' | 'user_deleted' | 'no_channel_permission' | 'no_channel_member' | 'thread_deleted' | 'unable_to_send' | 'invalid_post';
+export type ScheduledPostErrorCode = 'unknown' | 'channel_archived' | 'channel_not_found' | 'user_missing' | 'user_deleted' | 'no_channel_permission' | 'no_channel_member' | 'thread_deleted' | 'unable_to_send' | 'invalid_post' | 'bot_unavailable';
 
 export type SchedulingInfo = {
     scheduled_at: number;
     processed_at?: number;
     error_code?: ScheduledPostErrorCode;
+
+    // An RFC 5545 RRULE subset such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=10", in the user's timezone.
+    recurrence_rule?: string;
+    occurrence_count?: number;
+    paused_at?: number;
+
+    // The start of the series, whose time of day every occurrence keeps.
+    recurrence_start_at?: number;
+
+    // The user ID of the bot the post is published as, or empty to publish it as its author.
+    publish_as_bot_id?: string;
+
+    // The user ID of whoever last updated the post, who may be a channel admin rather than its author.
+    last_editor_id?: string;
 }
 
 export type ScheduledPost = Omit<Draft, 'delete_at'> & SchedulingInfo & {
@@ -31,10 +45,25 @@ export type ScheduledPostsState = {
     };
 }
 
+export function isRecurringScheduledPost(scheduledPost: SchedulingInfo): boolean {
+    return Boolean(scheduledPost.recurrence_rule);
+}
+
+// getScheduledPostNextRunAt returns the time the scheduled post is next sent at, which is the time of its next
+// occurrence if it recurs, or undefined if it is paused.
+export function getScheduledPostNextRunAt(scheduledPost: SchedulingInfo): number | undefined {
+    if (scheduledPost.paused_at) {
+        return undefined;
+    }
+
+    return scheduledPost.scheduled_at;
+}
+
 export function scheduledPostFromPost(post: Post, schedulingInfo: SchedulingInfo): ScheduledPost {
     return {
         id: '',
         scheduled_at: schedulingInfo.scheduled_at,
+        recurrence_rule: schedulingInfo.recurrence_rule,
         create_at: 0,
         update_at: post.update_at,
         user_id: post.user_id,